@@ -185,6 +185,11 @@ type StoreSpec struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// RaftLogPath, if set, is the directory of a dedicated storage engine that
+	// holds the store's raft log entries and HardStates. The directory may live
+	// on a separate device from Path. If empty, the raft log is stored in the
+	// same engine as the rest of the store's data.
+	RaftLogPath string
 }
 
 // String returns a fully parsable version of the store spec.
@@ -196,6 +201,9 @@ func (ss StoreSpec) String() string {
 	if ss.InMemory {
 		fmt.Fprint(&buffer, "type=mem,")
 	}
+	if len(ss.RaftLogPath) != 0 {
+		fmt.Fprintf(&buffer, "raft-log-path=%s,", ss.RaftLogPath)
+	}
 	if ss.Size.InBytes > 0 {
 		fmt.Fprintf(&buffer, "size=%s,", humanizeutil.IBytes(ss.Size.InBytes))
 	}
@@ -255,9 +263,13 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - raft-log-path=xxx The optional directory in which a dedicated storage
+//   engine holding the raft log should be located. Not allowed for in memory
+//   stores.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
+	const raftLogPathField = "raft-log-path"
 	if len(value) == 0 {
 		return StoreSpec{}, fmt.Errorf("no value specified")
 	}
@@ -296,6 +308,12 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			if err != nil {
 				return StoreSpec{}, err
 			}
+		case raftLogPathField:
+			var err error
+			ss.RaftLogPath, err = GetAbsoluteStorePath(raftLogPathField, value)
+			if err != nil {
+				return StoreSpec{}, err
+			}
 		case "size":
 			var err error
 			var minBytesAllowed int64 = MinimumStoreSize
@@ -375,11 +393,16 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.Path != "" {
 			return StoreSpec{}, fmt.Errorf("path specified for in memory store")
 		}
+		if ss.RaftLogPath != "" {
+			return StoreSpec{}, fmt.Errorf("raft-log-path specified for in memory store")
+		}
 		if ss.Size.Percent == 0 && ss.Size.InBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
+	} else if ss.RaftLogPath == ss.Path {
+		return StoreSpec{}, fmt.Errorf("raft-log-path must differ from the store path")
 	}
 	return ss, nil
 }
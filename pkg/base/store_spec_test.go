@@ -144,6 +144,15 @@ target_file_size=2097152`
 		{"path=/mnt/hda1,type=other", "other is not a valid store type", StoreSpec{}},
 		{"path=/mnt/hda1,type=mem,size=20GiB", "path specified for in memory store", StoreSpec{}},
 
+		// raft-log-path
+		{"path=/mnt/hda1,raft-log-path=/mnt/hdb1", "", StoreSpec{Path: "/mnt/hda1", RaftLogPath: "/mnt/hdb1"}},
+		{"raft-log-path=/mnt/hdb1,/mnt/hda1", "", StoreSpec{Path: "/mnt/hda1", RaftLogPath: "/mnt/hdb1"}},
+		{"path=/mnt/hda1,raft-log-path=", "no value specified for raft-log-path", StoreSpec{}},
+		{"path=/mnt/hda1,raft-log-path=~/raft", "raft-log-path cannot start with '~': ~/raft", StoreSpec{}},
+		{"path=/mnt/hda1,raft-log-path=/mnt/hda1", "raft-log-path must differ from the store path", StoreSpec{}},
+		{"raft-log-path=/mnt/hdb1", "no path specified", StoreSpec{}},
+		{"type=mem,size=20GiB,raft-log-path=/mnt/hdb1", "raft-log-path specified for in memory store", StoreSpec{}},
+
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
@@ -0,0 +1,618 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/covering"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// backupCompactionResumer is the resumer of a job which compacts a chain of
+// backups in a collection, i.e. a full backup and the incremental backups
+// appended to it, into a new full backup in the collection, which becomes the
+// latest one. The job only reads the files of the chain from external storage,
+// not the cluster, so the new backup holds the data of the chain as of the end
+// time of its last backup, along with its revision history if every backup of
+// the chain was taken with revision_history.
+type backupCompactionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &backupCompactionResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *backupCompactionResumer) Resume(
+	ctx context.Context, execCtx interface{}, _ chan<- tree.Datums,
+) error {
+	p := execCtx.(sql.JobExecContext)
+	details := r.job.Details().(jobspb.BackupCompactionDetails)
+	progress := r.job.Progress().Details.(*jobspb.Progress_BackupCompaction).BackupCompaction
+	return compactBackupChain(ctx, p, details, *progress,
+		func(ctx context.Context, progress jobspb.BackupCompactionProgress, fraction float32) error {
+			return r.job.FractionProgressed(ctx,
+				func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+					details.(*jobspb.Progress_BackupCompaction).BackupCompaction.CompletedFiles =
+						progress.CompletedFiles
+					return fraction
+				})
+		})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *backupCompactionResumer) OnFailOrCancel(context.Context, interface{}) error {
+	// The files written by the job are only part of a backup once its manifest
+	// is written, and the chain is left in place until then, so there is
+	// nothing to undo.
+	return nil
+}
+
+// compactBackupChain writes a new full backup holding the data of the chain
+// of backups in details, and points the LATEST file of the collection at it.
+// It does nothing if the chain is no longer the latest one in the collection,
+// or if it has no incremental backups.
+//
+// The spans of the chain are compacted by restore data processors spread
+// across the nodes of the cluster, like those of a RESTORE. The files written
+// so far are periodically passed to checkpointFn, and the files of a previous
+// attempt, in progress, are reused instead of being written again.
+func compactBackupChain(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	details jobspb.BackupCompactionDetails,
+	progress jobspb.BackupCompactionProgress,
+	checkpointFn func(context.Context, jobspb.BackupCompactionProgress, float32) error,
+) error {
+	execCfg := execCtx.ExecCfg()
+	user := execCtx.User()
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	collection, err := mkStore(ctx, details.CollectionURI, user)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+
+	if latest, err := readLatestFile(ctx, collection); err != nil {
+		return err
+	} else if latest != details.Subdir {
+		log.Infof(ctx, "backup %s is no longer the latest in its collection, skipping compaction",
+			details.Subdir)
+		return nil
+	}
+
+	chainURI, _, err := getURIsByLocalityKV([]string{details.CollectionURI}, details.Subdir)
+	if err != nil {
+		return err
+	}
+	chainStore, err := mkStore(ctx, chainURI, user)
+	if err != nil {
+		return err
+	}
+	defer chainStore.Close()
+
+	_, manifests, _, err := resolveBackupManifests(
+		ctx, []cloud.ExternalStorage{chainStore}, mkStore, [][]string{{chainURI}},
+		hlc.Timestamp{}, details.EncryptionOptions, user,
+	)
+	if err != nil {
+		return err
+	}
+	if len(manifests) < 2 {
+		return nil
+	}
+	keepRevisions := true
+	for i := range manifests {
+		if len(manifests[i].PartitionDescriptorFilenames) > 0 {
+			return errors.Errorf("cannot compact backup %s: partitioned backups cannot be compacted",
+				details.Subdir)
+		}
+		if manifests[i].MVCCFilter != MVCCFilter_All {
+			keepRevisions = false
+		}
+	}
+	last := manifests[len(manifests)-1]
+
+	// Without revision history, the new backup holds the data of the spans of
+	// the last backup as of its end time, which every backup of the chain up to
+	// it must cover, like a RESTORE of the chain. With it, the new backup holds
+	// every revision of every span backed up by any backup of the chain, even
+	// those of tables which were dropped or created during it.
+	spans := last.Spans
+	onMissing := errOnMissingRange
+	if keepRevisions {
+		spans = nil
+		for i := range manifests {
+			spans = append(spans, manifests[i].Spans...)
+		}
+		spans, _ = roachpb.MergeSpans(spans)
+		onMissing = func(covering.Range, hlc.Timestamp, hlc.Timestamp) error { return nil }
+	}
+	entries, _, err := makeImportSpans(spans, manifests, nil /* backupLocalityInfo */, keys.MinKey,
+		user, onMissing)
+	if err != nil {
+		return err
+	}
+
+	var fileEncryption *roachpb.FileEncryptionOptions
+	if details.EncryptionOptions != nil {
+		key, err := getEncryptionKey(ctx, details.EncryptionOptions, execCfg.Settings,
+			collection.ExternalIOConf())
+		if err != nil {
+			return err
+		}
+		fileEncryption = &roachpb.FileEncryptionOptions{Key: key}
+	}
+
+	destSuffix := last.EndTime.GoTime().Format(dateBasedIntoFolderName)
+	destURI, _, err := getURIsByLocalityKV([]string{details.CollectionURI}, destSuffix)
+	if err != nil {
+		return err
+	}
+	dest, err := mkStore(ctx, destURI, user)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+	if err := checkForPreviousBackup(ctx, dest, destURI); err != nil {
+		return err
+	}
+	if details.EncryptionOptions != nil {
+		encryptionInfo, err := readEncryptionOptions(ctx, chainStore)
+		if err != nil {
+			return err
+		}
+		if err := writeEncryptionInfoIfNotExists(ctx, encryptionInfo, dest); err != nil {
+			return err
+		}
+	}
+
+	pkIDs := make(map[uint64]bool)
+	addPKID := func(desc *descpb.Descriptor) {
+		if t := descpb.TableFromDescriptor(desc, hlc.Timestamp{}); t != nil {
+			pkIDs[roachpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+	for i := range last.Descriptors {
+		addPKID(&last.Descriptors[i])
+	}
+	if keepRevisions {
+		for i := range manifests {
+			for _, rev := range manifests[i].DescriptorChanges {
+				if rev.Desc != nil {
+					addPKID(rev.Desc)
+				}
+			}
+		}
+	}
+
+	// The files of every entry cover its span, so an entry covered by the
+	// files of a previous attempt has already been compacted.
+	completed := progress.CompletedFiles
+	var compactedSpans []roachpb.Span
+	for _, f := range completed {
+		compactedSpans = append(compactedSpans, f.Span)
+	}
+	compactedSpans, _ = roachpb.MergeSpans(compactedSpans)
+	var todo []execinfrapb.RestoreSpanEntry
+	for _, entry := range entries {
+		i := sort.Search(len(compactedSpans), func(i int) bool {
+			return entry.Span.Key.Compare(compactedSpans[i].EndKey) < 0
+		})
+		if i < len(compactedSpans) && compactedSpans[i].Contains(entry.Span) {
+			continue
+		}
+		entry.ProgressIdx = int64(len(todo))
+		todo = append(todo, entry)
+	}
+
+	if len(todo) > 0 {
+		// The entries are chunked like those of a restore.
+		chunkSize := int(math.Sqrt(float64(len(todo))))
+		var chunks [][]execinfrapb.RestoreSpanEntry
+		for start := 0; start < len(todo); start += chunkSize {
+			end := start + chunkSize
+			if end > len(todo) {
+				end = len(todo)
+			}
+			chunks = append(chunks, todo[start:end])
+		}
+
+		destConf := dest.Conf()
+		spec := execinfrapb.RestoreDataSpec{
+			Encryption:              fileEncryption,
+			PKIDs:                   pkIDs,
+			CompactionDest:          &destConf,
+			CompactionKeepRevisions: keepRevisions,
+		}
+
+		// Every entry is reported once its files are written.
+		finished := len(entries) - len(todo)
+		lastCheckpoint := timeutil.Now()
+		metaFn := func(ctx context.Context, meta *execinfrapb.ProducerMetadata) error {
+			if meta.BulkProcessorProgress == nil {
+				return nil
+			}
+			var progDetails BackupManifest_Progress
+			if err := pbtypes.UnmarshalAny(&meta.BulkProcessorProgress.ProgressDetails, &progDetails); err != nil {
+				return err
+			}
+			for _, f := range progDetails.Files {
+				completed = append(completed, jobspb.BackupCompactionProgress_File{
+					Span:         f.Span,
+					Path:         f.Path,
+					Sha512:       f.Sha512,
+					DataSize:     f.EntryCounts.DataSize,
+					Rows:         f.EntryCounts.Rows,
+					IndexEntries: f.EntryCounts.IndexEntries,
+				})
+			}
+			finished++
+			if timeutil.Since(lastCheckpoint) > BackupCheckpointInterval {
+				if err := checkpointFn(ctx, jobspb.BackupCompactionProgress{CompletedFiles: completed},
+					float32(finished)/float32(len(entries))); err != nil {
+					log.Errorf(ctx, "unable to checkpoint backup compaction progress: %+v", err)
+				}
+				lastCheckpoint = timeutil.Now()
+			}
+			return nil
+		}
+
+		rowResultWriter := sql.NewRowResultWriter(nil)
+		if err := runRestoreFlow(
+			ctx, execCtx, chunks, nil /* rekeys */, spec,
+			sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+		); err != nil {
+			return err
+		}
+		if err := rowResultWriter.Err(); err != nil {
+			return err
+		}
+		if finished != len(entries) {
+			return errors.AssertionFailedf("compacted %d of %d spans", finished, len(entries))
+		}
+	}
+
+	files := make([]BackupManifest_File, len(completed))
+	for i, f := range completed {
+		files[i] = BackupManifest_File{
+			Span:   f.Span,
+			Path:   f.Path,
+			Sha512: f.Sha512,
+			EntryCounts: RowCount{
+				DataSize:     f.DataSize,
+				Rows:         f.Rows,
+				IndexEntries: f.IndexEntries,
+			},
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Span.Key.Compare(files[j].Span.Key) < 0
+	})
+
+	m := last
+	m.StartTime = hlc.Timestamp{}
+	m.IntroducedSpans = nil
+	m.Spans = spans
+	m.Files = files
+	m.EntryCounts = RowCount{}
+	for _, f := range m.Files {
+		m.EntryCounts.add(f.EntryCounts)
+	}
+	m.DescriptorChanges = nil
+	m.RevisionStartTime = hlc.Timestamp{}
+	if keepRevisions {
+		// The revisions of the chain start with those of its full backup, unless
+		// an incremental backup lost some of the ones which followed it to GC.
+		m.RevisionStartTime = manifests[0].RevisionStartTime
+		for i := range manifests {
+			if i > 0 && manifests[i].StartTime.Less(manifests[i].RevisionStartTime) {
+				m.RevisionStartTime.Forward(manifests[i].RevisionStartTime)
+			}
+			m.DescriptorChanges = append(m.DescriptorChanges, manifests[i].DescriptorChanges...)
+		}
+	}
+	m.Dir = dest.Conf()
+	m.ID = uuid.MakeV4()
+	// The new backup replaces the chain, so it belongs to the schedule which
+	// took the full backup of the chain rather than the one which took its
+	// last incremental backup.
+	m.ScheduleID = manifests[0].ScheduleID
+	m.PartitionDescriptorFilenames = nil
+	m.LocalityKVs = nil
+	m.DeprecatedStatistics = nil
+	m.StatisticsFilenames = nil
+
+	tableStatistics, err := getStatisticsFromBackup(ctx, chainStore, details.EncryptionOptions, last)
+	if err != nil {
+		return err
+	}
+	if len(tableStatistics) > 0 {
+		if err := writeTableStatistics(ctx, dest, backupStatisticsFileName, details.EncryptionOptions,
+			&StatsTable{Statistics: tableStatistics}); err != nil {
+			return err
+		}
+		m.StatisticsFilenames = make(map[descpb.ID]string)
+		for _, stat := range tableStatistics {
+			m.StatisticsFilenames[stat.TableID] = backupStatisticsFileName
+		}
+	}
+	if err := writeBackupManifest(
+		ctx, execCfg.Settings, dest, backupManifestName, details.EncryptionOptions, &m,
+	); err != nil {
+		return err
+	}
+
+	// Point the LATEST file of the collection at the new backup, unless a new
+	// full backup was taken in the meantime.
+	if latest, err := readLatestFile(ctx, collection); err != nil {
+		return err
+	} else if latest != details.Subdir {
+		return nil
+	}
+	return collection.WriteFile(ctx, latestFileName, strings.NewReader(destSuffix))
+}
+
+// compactionSink writes the merged data of a restore span entry of a chain of
+// backups to the files of a new backup, starting a new file, at a key
+// boundary, once the current one reaches the target size. The files cover the
+// span of the entry.
+type compactionSink struct {
+	dest       cloud.ExternalStorage
+	encryption *roachpb.FileEncryptionOptions
+	instanceID base.SQLInstanceID
+	targetSize int64
+	pkIDs      map[uint64]bool
+
+	sst     *storage.MemFile
+	w       storage.SSTWriter
+	counter storage.RowCounter
+	start   roachpb.Key
+	lastKey roachpb.Key
+	files   []BackupManifest_File
+}
+
+// addEntry merges the files of the chain covering the span of entry and adds
+// their data to the sink: every revision if keepRevisions is set, otherwise
+// the latest revision of every key which was not deleted.
+func (s *compactionSink) addEntry(
+	ctx context.Context,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	entry execinfrapb.RestoreSpanEntry,
+	keepRevisions bool,
+) error {
+	s.start = entry.Span.Key
+	// The files are ordered by backup, so the multi-iterator prefers the later
+	// ones for the same key and timestamp.
+	var iters []storage.SimpleMVCCIterator
+	for _, file := range entry.Files {
+		fileContents, err := fetchBackupFile(ctx, makeExternalStorage, file)
+		if err != nil {
+			return err
+		}
+		fileContents, err = decodeBackupFile(fileContents, file, s.encryption)
+		if err != nil {
+			return err
+		}
+		iter, err := storage.NewMemSSTIterator(fileContents, false)
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+		iters = append(iters, iter)
+	}
+
+	endKeyMVCC := storage.MVCCKey{Key: entry.Span.EndKey}
+	iter := storage.MakeMultiIterator(iters)
+	defer iter.Close()
+	for iter.SeekGE(storage.MVCCKey{Key: entry.Span.Key}); ; {
+		ok, err := iter.Valid()
+		if err != nil {
+			return err
+		}
+		if !ok || !iter.UnsafeKey().Less(endKeyMVCC) {
+			break
+		}
+		if keepRevisions {
+			if err := s.put(ctx, iter.UnsafeKey(), iter.UnsafeValue()); err != nil {
+				return err
+			}
+			iter.Next()
+			continue
+		}
+		if len(iter.UnsafeValue()) > 0 {
+			if err := s.put(ctx, iter.UnsafeKey(), iter.UnsafeValue()); err != nil {
+				return err
+			}
+		}
+		iter.NextKey()
+	}
+	return nil
+}
+
+func (s *compactionSink) put(ctx context.Context, key storage.MVCCKey, value []byte) error {
+	if !key.Key.Equal(s.lastKey) {
+		if s.sst != nil && s.counter.DataSize >= s.targetSize {
+			if err := s.flush(ctx, key.Key); err != nil {
+				return err
+			}
+		}
+		s.lastKey = append(s.lastKey[:0], key.Key...)
+	}
+	if s.sst == nil {
+		s.sst = &storage.MemFile{}
+		s.w = storage.MakeBackupSSTWriter(s.sst)
+	}
+	if err := s.w.PutMVCC(key, value); err != nil {
+		return err
+	}
+	if err := s.counter.Count(key.Key); err != nil {
+		return errors.Wrap(err, "decoding key")
+	}
+	s.counter.DataSize += int64(len(key.Key) + len(value))
+	return nil
+}
+
+// flush writes the current file, if any, as covering the span from the end of
+// the previous one, or the start of the entry, to end.
+func (s *compactionSink) flush(ctx context.Context, end roachpb.Key) error {
+	if s.sst == nil {
+		return nil
+	}
+	if err := s.w.Finish(); err != nil {
+		return err
+	}
+	data := s.sst.Data()
+	checksum, err := storageccl.SHA512ChecksumData(data)
+	if err != nil {
+		return err
+	}
+	if s.encryption != nil {
+		if data, err = storageccl.EncryptFile(data, s.encryption.Key); err != nil {
+			return err
+		}
+	}
+	name := fmt.Sprintf("%d.sst", builtins.GenerateUniqueInt(s.instanceID))
+	if err := s.dest.WriteFile(ctx, name, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	span := roachpb.Span{Key: s.start, EndKey: append(roachpb.Key(nil), end...)}
+	s.files = append(s.files, BackupManifest_File{
+		Span:        span,
+		Path:        name,
+		Sha512:      checksum,
+		EntryCounts: countRows(s.counter.BulkOpSummary, s.pkIDs),
+	})
+	s.start = span.EndKey
+	s.sst = nil
+	s.counter = storage.RowCounter{}
+	return nil
+}
+
+func (s *compactionSink) close() {
+	if s.sst != nil {
+		s.w.Close()
+	}
+}
+
+// maybeStartBackupCompaction starts a job to compact the chain of backups to
+// which the incremental backup of the job was appended, if the backup was
+// taken by a schedule which compacts its chains and the chain has reached the
+// number of incremental backups after which it does. Failing to start it does
+// not fail the backup: the schedule tries again once that many more
+// incremental backups are appended to the chain.
+func (b *backupResumer) maybeStartBackupCompaction(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails,
+) {
+	if details.StartTime.IsEmpty() || details.CollectionURI == "" || len(details.URIsByLocalityKV) > 0 {
+		return
+	}
+	if err := func() error {
+		exec := p.ExecCfg()
+		_, args, err := loadCreatingScheduleArgs(ctx, scheduledJobEnv(exec), exec, *b.job.ID())
+		if err != nil {
+			return err
+		}
+		if args == nil || args.CompactAfterIncrementals <= 0 {
+			return nil
+		}
+
+		backupURI, err := url.Parse(details.URI)
+		if err != nil {
+			return err
+		}
+		collectionURI, err := url.Parse(details.CollectionURI)
+		if err != nil {
+			return err
+		}
+		// The incremental backup is in a subdirectory, itself two levels deep, of
+		// the full backup of its chain.
+		suffix := strings.TrimPrefix(path.Clean(backupURI.Path), path.Clean(collectionURI.Path))
+		subdir := path.Dir(path.Dir(suffix))
+
+		chainURI, _, err := getURIsByLocalityKV([]string{details.CollectionURI}, subdir)
+		if err != nil {
+			return err
+		}
+		chainStore, err := exec.DistSQLSrv.ExternalStorageFromURI(ctx, chainURI, p.User())
+		if err != nil {
+			return err
+		}
+		defer chainStore.Close()
+		incrementals, err := findPriorBackupNames(ctx, chainStore)
+		if err != nil {
+			return err
+		}
+		if len(incrementals) == 0 || int64(len(incrementals))%args.CompactAfterIncrementals != 0 {
+			return nil
+		}
+
+		record := jobs.Record{
+			Description: fmt.Sprintf("BACKUP COMPACTION of %s in %s",
+				subdir, RedactURIForErrorMessage(details.CollectionURI)),
+			Username: p.User(),
+			Details: jobspb.BackupCompactionDetails{
+				CollectionURI:     details.CollectionURI,
+				Subdir:            subdir,
+				EncryptionOptions: details.EncryptionOptions,
+			},
+			Progress: jobspb.BackupCompactionProgress{},
+		}
+		return exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			job, err := exec.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, txn)
+			if err != nil {
+				return err
+			}
+			log.Infof(ctx, "started job %d to compact backup %s after %d incremental backups",
+				*job.ID(), subdir, len(incrementals))
+			return nil
+		})
+	}(); err != nil {
+		log.Warningf(ctx, "failed to start compaction of the chain of backup job %d: %v",
+			*b.job.ID(), err)
+	}
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeBackupCompaction,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &backupCompactionResumer{
+				job: job,
+			}
+		},
+	)
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupCompaction(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 20
+	ctx, tc, sqlDB, dir, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+	execCfg := tc.Server(0).ExecutorConfig().(sql.ExecutorConfig)
+	execCtx, cleanup := sql.MakeJobExecContext("test", security.RootUserName(), &sql.MemoryMetrics{}, &execCfg)
+	defer cleanup()
+
+	defer func(oldInterval time.Duration) {
+		BackupCheckpointInterval = oldInterval
+	}(BackupCheckpointInterval)
+	BackupCheckpointInterval = 0
+	var checkpoint jobspb.BackupCompactionProgress
+	checkpointFn := func(_ context.Context, progress jobspb.BackupCompactionProgress, _ float32) error {
+		checkpoint = progress
+		return nil
+	}
+
+	for _, test := range []struct {
+		name string
+		opts string
+	}{
+		{name: "latest"},
+		{name: "revision-history", opts: " WITH revision_history"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			collection := LocalFoo + "/" + test.name
+			readLatest := func() string {
+				latest, err := ioutil.ReadFile(filepath.Join(dir, "foo", test.name, latestFileName))
+				require.NoError(t, err)
+				return string(latest)
+			}
+			restoreCount := 0
+			checkRestore := func(subdir, asOf string, expected [][]string) {
+				restoreCount++
+				restoredDB := fmt.Sprintf("restored_%s_%d", test.name[:3], restoreCount)
+				sqlDB.Exec(t, `CREATE DATABASE `+restoredDB)
+				sqlDB.Exec(t, `RESTORE TABLE data.bank FROM $1 IN $2`+asOf+` WITH into_db = $3`,
+					subdir, collection, restoredDB)
+				sqlDB.CheckQueryResults(t, `SELECT * FROM `+restoredDB+`.bank ORDER BY id`, expected)
+			}
+
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`+test.opts, collection)
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 5`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`+test.opts, collection)
+			var beforeDelete string
+			sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeDelete)
+			expectedBeforeDelete := sqlDB.QueryStr(t,
+				`SELECT * FROM data.bank AS OF SYSTEM TIME `+beforeDelete+` ORDER BY id`)
+			sqlDB.Exec(t, `DELETE FROM data.bank WHERE id % 2 = 0`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`+test.opts, collection)
+			sqlDB.Exec(t, `INSERT INTO data.bank VALUES (100, 100, 'new')`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`+test.opts, collection)
+			expected := sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`)
+
+			chain := readLatest()
+			details := jobspb.BackupCompactionDetails{CollectionURI: collection, Subdir: chain}
+			checkpoint = jobspb.BackupCompactionProgress{}
+			require.NoError(t, compactBackupChain(ctx, execCtx, details, checkpoint, checkpointFn))
+			compacted := readLatest()
+			require.NotEqual(t, chain, compacted)
+			require.NotEmpty(t, checkpoint.CompletedFiles)
+
+			// A compaction which is resumed reuses the files written before it was
+			// interrupted instead of writing them again.
+			compactedDir := filepath.Join(dir, "foo", test.name, compacted)
+			ssts, err := filepath.Glob(filepath.Join(compactedDir, "*.sst"))
+			require.NoError(t, err)
+			require.NoError(t, os.Remove(filepath.Join(compactedDir, backupManifestName)))
+			require.NoError(t, ioutil.WriteFile(
+				filepath.Join(dir, "foo", test.name, latestFileName), []byte(chain), 0644))
+			require.NoError(t, compactBackupChain(ctx, execCtx, details, checkpoint, checkpointFn))
+			require.Equal(t, compacted, readLatest())
+			resumedSSTs, err := filepath.Glob(filepath.Join(compactedDir, "*.sst"))
+			require.NoError(t, err)
+			require.Equal(t, ssts, resumedSSTs)
+
+			// The compacted backup is a single full backup, which restores the
+			// same data as the chain.
+			require.Equal(t, [][]string{{"1"}}, sqlDB.QueryStr(t,
+				`SELECT count(*) FROM [SHOW BACKUP $1] WHERE object_name = 'bank'`,
+				collection+compacted))
+			checkRestore(chain, "", expected)
+			checkRestore(compacted, "", expected)
+			if test.opts != "" {
+				checkRestore(compacted, ` AS OF SYSTEM TIME `+beforeDelete, expectedBeforeDelete)
+			}
+
+			// The chain is no longer the latest, so it is not compacted again.
+			require.NoError(t, compactBackupChain(ctx, execCtx, details, checkpoint, checkpointFn))
+			require.Equal(t, compacted, readLatest())
+
+			// Incremental backups are appended to the compacted backup.
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = 0 WHERE id = 1`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`+test.opts, collection)
+			checkRestore(compacted, "", sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+		})
+	}
+}
@@ -534,6 +534,8 @@ func (b *backupResumer) Resume(
 	}
 
 	b.maybeNotifyScheduledJobCompletion(ctx, jobs.StatusSucceeded, p.ExecCfg())
+	b.maybeStartBackupCompaction(ctx, p, details)
+	b.maybeDeleteExpiredBackupChains(ctx, p, details)
 	return nil
 }
 
@@ -581,37 +583,86 @@ func (b *backupResumer) readManifestOnResume(
 	return &desc, nil
 }
 
-func (b *backupResumer) maybeNotifyScheduledJobCompletion(
-	ctx context.Context, jobStatus jobs.Status, exec *sql.ExecutorConfig,
-) {
-	env := scheduledjobs.ProdJobSchedulerEnv
+// scheduledJobEnv returns the environment of the job scheduler, which tests
+// may override.
+func scheduledJobEnv(exec *sql.ExecutorConfig) scheduledjobs.JobSchedulerEnv {
 	if knobs, ok := exec.DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
 		if knobs.JobSchedulerEnv != nil {
-			env = knobs.JobSchedulerEnv
+			return knobs.JobSchedulerEnv
 		}
 	}
+	return scheduledjobs.ProdJobSchedulerEnv
+}
 
-	if err := exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		// Do not rely on b.job containing created_by_id.  Query it directly.
-		datums, err := exec.InternalExecutor.QueryRowEx(
-			ctx,
-			"lookup-schedule-info",
-			txn,
-			sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
-			fmt.Sprintf(
-				"SELECT created_by_id FROM %s WHERE id=$1 AND created_by_type=$2",
-				env.SystemJobsTableName()),
-			*b.job.ID(), jobs.CreatedByScheduledJobs)
+// lookupCreatingSchedule returns the ID of the schedule which created the job,
+// or false if the job was not created by a schedule.
+func lookupCreatingSchedule(
+	ctx context.Context,
+	env scheduledjobs.JobSchedulerEnv,
+	exec *sql.ExecutorConfig,
+	jobID int64,
+	txn *kv.Txn,
+) (int64, bool, error) {
+	// Do not rely on the job containing created_by_id.  Query it directly.
+	datums, err := exec.InternalExecutor.QueryRowEx(
+		ctx,
+		"lookup-schedule-info",
+		txn,
+		sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+		fmt.Sprintf(
+			"SELECT created_by_id FROM %s WHERE id=$1 AND created_by_type=$2",
+			env.SystemJobsTableName()),
+		jobID, jobs.CreatedByScheduledJobs)
 
+	if err != nil {
+		return 0, false, errors.Wrap(err, "schedule info lookup")
+	}
+	if datums == nil {
+		// Not a scheduled backup.
+		return 0, false, nil
+	}
+	return int64(tree.MustBeDInt(datums[0])), true, nil
+}
+
+// loadCreatingScheduleArgs returns the ID and the execution arguments of the
+// backup schedule which created the job, or nil arguments if the job was not
+// created by a schedule.
+func loadCreatingScheduleArgs(
+	ctx context.Context, env scheduledjobs.JobSchedulerEnv, exec *sql.ExecutorConfig, jobID int64,
+) (int64, *ScheduledBackupExecutionArgs, error) {
+	var scheduleID int64
+	var args *ScheduledBackupExecutionArgs
+	if err := exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		args = nil
+		var ok bool
+		var err error
+		scheduleID, ok, err = lookupCreatingSchedule(ctx, env, exec, jobID, txn)
+		if err != nil || !ok {
+			return err
+		}
+		schedule, err := jobs.LoadScheduledJob(ctx, env, scheduleID, exec.InternalExecutor, txn)
 		if err != nil {
-			return errors.Wrap(err, "schedule info lookup")
+			return err
 		}
-		if datums == nil {
-			// Not a scheduled backup.
-			return nil
+		args = &ScheduledBackupExecutionArgs{}
+		return types.UnmarshalAny(schedule.ExecutionArgs().Args, args)
+	}); err != nil {
+		return 0, nil, err
+	}
+	return scheduleID, args, nil
+}
+
+func (b *backupResumer) maybeNotifyScheduledJobCompletion(
+	ctx context.Context, jobStatus jobs.Status, exec *sql.ExecutorConfig,
+) {
+	env := scheduledJobEnv(exec)
+
+	if err := exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		scheduleID, ok, err := lookupCreatingSchedule(ctx, env, exec, *b.job.ID(), txn)
+		if err != nil || !ok {
+			return err
 		}
 
-		scheduleID := int64(tree.MustBeDInt(datums[0]))
 		if err := jobs.NotifyJobTermination(
 			ctx, env, *b.job.ID(), jobStatus, b.job.Details(), scheduleID, exec.InternalExecutor, txn); err != nil {
 			log.Warningf(ctx,
@@ -1059,6 +1059,10 @@ func backupPlanHook(
 			StatisticsFilenames: statsFiles,
 			DescriptorCoverage:  backupStmt.Coverage(),
 		}
+		if backupStmt.CreatedByInfo != nil &&
+			backupStmt.CreatedByInfo.Name == jobs.CreatedByScheduledJobs {
+			backupManifest.ScheduleID = backupStmt.CreatedByInfo.ID
+		}
 
 		// Sanity check: re-run the validation that RESTORE will do, but this time
 		// including this backup, to ensure that the this backup plus any previous
@@ -3945,6 +3945,66 @@ func TestBackupRestoreChecksum(t *testing.T) {
 	sqlDB.ExpectErr(t, "checksum mismatch", `RESTORE data.* FROM $1`, LocalFoo)
 }
 
+func TestRestoreVerifyBackupTableData(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 1000
+	_, _, sqlDB, dir, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	full, inc := LocalFoo+"/full", LocalFoo+"/inc"
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, full)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 INCREMENTAL FROM $2`, inc, full)
+
+	// verify returns the statuses of the files of the backup, by layer.
+	verify := func() map[string][]string {
+		statuses := make(map[string][]string)
+		for _, row := range sqlDB.QueryStr(t,
+			`RESTORE data.bank FROM $1, $2 WITH verify_backup_table_data`, full, inc,
+		) {
+			statuses[row[0]] = append(statuses[row[0]], row[4])
+		}
+		return statuses
+	}
+
+	// The table still exists, which would make a real restore fail.
+	statuses := verify()
+	require.Len(t, statuses, 2)
+	for _, layer := range []string{"0", "1"} {
+		require.NotEmpty(t, statuses[layer])
+		for _, status := range statuses[layer] {
+			require.Equal(t, verifyStatusOK, status)
+		}
+	}
+
+	// Remove a file of the full backup and corrupt one of the incremental
+	// backup.
+	fullFiles, err := filepath.Glob(filepath.Join(dir, "foo", "full", "*.sst"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fullFiles)
+	require.NoError(t, os.Remove(fullFiles[0]))
+	incFiles, err := filepath.Glob(filepath.Join(dir, "foo", "inc", "*.sst"))
+	require.NoError(t, err)
+	require.NotEmpty(t, incFiles)
+	f, err := os.OpenFile(incFiles[0], os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Seek(-8, io.SeekEnd)
+	require.NoError(t, err)
+	_, err = f.Write(make([]byte, 8))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	statuses = verify()
+	require.Contains(t, statuses["0"], verifyStatusMissing)
+	require.Contains(t, statuses["1"], verifyStatusCorrupt)
+
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.bank`, [][]string{{"1000"}})
+	sqlDB.ExpectErr(t, "cannot use \"verify_backup_table_data\" option in detached mode",
+		`RESTORE data.bank FROM $1, $2 WITH verify_backup_table_data, detached`, full, inc)
+}
+
 func TestTimestampMismatch(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -4714,6 +4774,101 @@ func TestRestoreDatabaseVersusTable(t *testing.T) {
 	})
 }
 
+func TestRestoreTableAs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 0
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `
+CREATE DATABASE d;
+USE d;
+CREATE SCHEMA sc;
+CREATE TYPE sc.status AS ENUM ('open', 'closed');
+CREATE SEQUENCE sc.order_ids;
+CREATE TABLE sc.customers (id INT PRIMARY KEY);
+CREATE TABLE sc.orders (
+  id INT PRIMARY KEY DEFAULT nextval('sc.order_ids'),
+  customer INT REFERENCES sc.customers,
+  status sc.status
+);
+CREATE VIEW sc.open_orders AS SELECT id FROM sc.orders WHERE status = 'open';
+CREATE SEQUENCE sc.ticket_ids;
+CREATE TABLE sc.tickets (
+  id STRING PRIMARY KEY DEFAULT nextval('sc.ticket_ids'::STRING)::STRING || ('open'::sc.status)::STRING
+);
+INSERT INTO sc.customers VALUES (1);
+INSERT INTO sc.orders (customer, status) VALUES (1, 'open'), (1, 'closed');
+INSERT INTO sc.tickets DEFAULT VALUES;
+`)
+	sqlDB.Exec(t, `BACKUP DATABASE d TO $1`, LocalFoo)
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.ExpectErr(t, "only single tables can be renamed",
+			`RESTORE TABLE d.sc.* AS foo FROM $1`, LocalFoo)
+		sqlDB.ExpectErr(t, `table "customers" is restored under multiple names`,
+			`RESTORE TABLE d.sc.customers AS c1, d.sc.customers AS c2 FROM $1`, LocalFoo)
+		sqlDB.ExpectErr(t, `multiple restored tables are named "c1"`,
+			`RESTORE TABLE d.sc.customers AS c1, d.sc.order_ids AS c1 FROM $1`, LocalFoo)
+		sqlDB.ExpectErr(t, `relation "customers" already exists`,
+			`RESTORE TABLE d.sc.customers AS customers FROM $1`, LocalFoo)
+	})
+
+	t.Run("next-to-live-tables", func(t *testing.T) {
+		sqlDB.Exec(t, `
+RESTORE TABLE d.sc.order_ids AS order_ids2, d.sc.customers AS customers2,
+  d.sc.orders AS orders2, d.sc.open_orders AS open_orders2 FROM $1`, LocalFoo)
+
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM d.sc.orders2`, [][]string{{"2"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM d.sc.orders`, [][]string{{"2"}})
+
+		// The restored tables reference each other, not the live tables.
+		sqlDB.Exec(t, `INSERT INTO d.sc.customers2 VALUES (2)`)
+		sqlDB.Exec(t, `INSERT INTO d.sc.orders2 (customer, status) VALUES (2, 'open')`)
+		sqlDB.ExpectErr(t, "violates foreign key constraint",
+			`INSERT INTO d.sc.orders (customer, status) VALUES (2, 'open')`)
+		sqlDB.CheckQueryResults(t, `SELECT last_value FROM d.sc.order_ids2`, [][]string{{"3"}})
+		sqlDB.CheckQueryResults(t, `SELECT last_value FROM d.sc.order_ids`, [][]string{{"2"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM d.sc.open_orders2`, [][]string{{"2"}})
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM d.sc.open_orders`, [][]string{{"1"}})
+
+		// The existing schema and type are reused rather than duplicated.
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW SCHEMAS FROM d] WHERE schema_name = 'sc'`, [][]string{{"1"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW ENUMS FROM d] WHERE name = 'status'`, [][]string{{"1"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM d.sc.orders2 WHERE status = 'open'::d.sc.status`, [][]string{{"2"}})
+	})
+
+	t.Run("default-with-type-reference", func(t *testing.T) {
+		// The default expression refers to the enum type by ID, and its sequence
+		// name is cast.
+		sqlDB.Exec(t, `
+RESTORE TABLE d.sc.ticket_ids AS ticket_ids2, d.sc.tickets AS tickets2 FROM $1`, LocalFoo)
+		sqlDB.Exec(t, `INSERT INTO d.sc.tickets2 DEFAULT VALUES`)
+		sqlDB.CheckQueryResults(t, `SELECT id FROM d.sc.tickets2 ORDER BY id`,
+			[][]string{{"1open"}, {"2open"}})
+		sqlDB.CheckQueryResults(t, `SELECT last_value FROM d.sc.ticket_ids2`, [][]string{{"2"}})
+		sqlDB.CheckQueryResults(t, `SELECT last_value FROM d.sc.ticket_ids`, [][]string{{"1"}})
+	})
+
+	t.Run("into-existing-schema", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE d2`)
+		sqlDB.Exec(t, `CREATE SCHEMA d2.sc`)
+		sqlDB.Exec(t, `CREATE TABLE d2.sc.customers3 (id INT PRIMARY KEY)`)
+
+		// The name collision is checked in the schema of the target database.
+		sqlDB.ExpectErr(t, `relation "customers3" already exists`,
+			`RESTORE TABLE d.sc.customers AS customers3 FROM $1 WITH into_db = 'd2'`, LocalFoo)
+
+		sqlDB.Exec(t, `RESTORE TABLE d.sc.customers AS customers4 FROM $1 WITH into_db = 'd2'`, LocalFoo)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM d2.sc.customers4`, [][]string{{"1"}})
+	})
+}
+
 func TestBackupAzureAccountName(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
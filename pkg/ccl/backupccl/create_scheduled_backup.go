@@ -12,6 +12,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,19 +36,23 @@ import (
 )
 
 const (
-	optFirstRun                = "first_run"
-	optOnExecFailure           = "on_execution_failure"
-	optOnPreviousRunning       = "on_previous_running"
-	optIgnoreExistingBackups   = "ignore_existing_backups"
-	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optFirstRun                 = "first_run"
+	optOnExecFailure            = "on_execution_failure"
+	optOnPreviousRunning        = "on_previous_running"
+	optIgnoreExistingBackups    = "ignore_existing_backups"
+	optUpdatesLastBackupMetric  = "updates_cluster_last_backup_time_metric"
+	optCompactAfterIncrementals = "compact_after_incrementals"
+	optRetention                = "retention"
 )
 
 var scheduledBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
-	optFirstRun:                sql.KVStringOptRequireValue,
-	optOnExecFailure:           sql.KVStringOptRequireValue,
-	optOnPreviousRunning:       sql.KVStringOptRequireValue,
-	optIgnoreExistingBackups:   sql.KVStringOptRequireNoValue,
-	optUpdatesLastBackupMetric: sql.KVStringOptRequireNoValue,
+	optFirstRun:                 sql.KVStringOptRequireValue,
+	optOnExecFailure:            sql.KVStringOptRequireValue,
+	optOnPreviousRunning:        sql.KVStringOptRequireValue,
+	optIgnoreExistingBackups:    sql.KVStringOptRequireNoValue,
+	optUpdatesLastBackupMetric:  sql.KVStringOptRequireNoValue,
+	optCompactAfterIncrementals: sql.KVStringOptRequireValue,
+	optRetention:                sql.KVStringOptRequireValue,
 }
 
 // scheduledBackupEval is a representation of tree.ScheduledBackup, prepared
@@ -130,6 +136,38 @@ func scheduleFirstRun(evalCtx *tree.EvalContext, opts map[string]string) (*time.
 	return nil, nil
 }
 
+// scheduleCompactAfterIncrementals returns the number of incremental backups
+// after which the chain of backups of the schedule is compacted into a new full
+// backup, or 0 if it is never compacted.
+func scheduleCompactAfterIncrementals(opts map[string]string) (int64, error) {
+	v, ok := opts[optCompactAfterIncrementals]
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.Newf(
+			"%q is not a valid %s; it must be a positive integer", v, optCompactAfterIncrementals)
+	}
+	return n, nil
+}
+
+// scheduleRetention returns how long the backup chains of the schedule are
+// retained after their most recent backup, or 0 if they are retained forever.
+func scheduleRetention(opts map[string]string) (time.Duration, error) {
+	v, ok := opts[optRetention]
+	if !ok {
+		return 0, nil
+	}
+	if d, err := tree.ParseDInterval(v); err == nil {
+		if secs, ok := d.AsInt64(); ok && secs > 0 && secs <= int64(math.MaxInt64/time.Second) {
+			return time.Duration(secs) * time.Second, nil
+		}
+	}
+	return 0, errors.Newf(
+		"%q is not a valid %s; it must be a positive interval", v, optRetention)
+}
+
 type scheduleRecurrence struct {
 	cron      string
 	frequency time.Duration
@@ -325,6 +363,29 @@ func doCreateBackupSchedules(
 		return err
 	}
 
+	compactAfterIncrementals, err := scheduleCompactAfterIncrementals(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	if compactAfterIncrementals > 0 {
+		if incRecurrence == nil {
+			return errors.Newf("%s requires a schedule which takes incremental backups",
+				optCompactAfterIncrementals)
+		}
+		if len(destinations) > 1 {
+			return errors.Newf("%s is not supported for partitioned backups",
+				optCompactAfterIncrementals)
+		}
+	}
+
+	retention, err := scheduleRetention(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	if retention > 0 && len(destinations) > 1 {
+		return errors.Newf("%s is not supported for partitioned backups", optRetention)
+	}
+
 	ex := p.ExecCfg().InternalExecutor
 
 	unpauseOnSuccessID := jobs.InvalidScheduleID
@@ -334,7 +395,8 @@ func doCreateBackupSchedules(
 		backupNode.AppendToLatest = true
 		inc, err := makeBackupSchedule(
 			env, p.User(), scheduleLabel,
-			incRecurrence, details, unpauseOnSuccessID, updateMetricOnSuccess, backupNode)
+			incRecurrence, details, unpauseOnSuccessID, updateMetricOnSuccess,
+			compactAfterIncrementals, 0 /* retention */, backupNode)
 
 		if err != nil {
 			return err
@@ -357,7 +419,8 @@ func doCreateBackupSchedules(
 	backupNode.AppendToLatest = false
 	full, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel,
-		fullRecurrence, details, unpauseOnSuccessID, updateMetricOnSuccess, backupNode)
+		fullRecurrence, details, unpauseOnSuccessID, updateMetricOnSuccess,
+		0 /* compactAfterIncrementals */, retention, backupNode)
 	if err != nil {
 		return err
 	}
@@ -432,6 +495,8 @@ func makeBackupSchedule(
 	details jobspb.ScheduleDetails,
 	unpauseOnSuccess int64,
 	updateLastMetricOnSuccess bool,
+	compactAfterIncrementals int64,
+	retention time.Duration,
 	backupNode *tree.Backup,
 ) (*jobs.ScheduledJob, error) {
 	sj := jobs.NewScheduledJob(env)
@@ -440,8 +505,10 @@ func makeBackupSchedule(
 
 	// Prepare arguments for scheduled backup execution.
 	args := &ScheduledBackupExecutionArgs{
-		UnpauseOnSuccess:        unpauseOnSuccess,
-		UpdatesLastBackupMetric: updateLastMetricOnSuccess,
+		UnpauseOnSuccess:         unpauseOnSuccess,
+		UpdatesLastBackupMetric:  updateLastMetricOnSuccess,
+		CompactAfterIncrementals: compactAfterIncrementals,
+		Retention:                retention,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = ScheduledBackupExecutionArgs_INCREMENTAL
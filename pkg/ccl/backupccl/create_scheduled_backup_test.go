@@ -14,7 +14,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -29,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -188,6 +191,10 @@ func TestSerializesScheduledBackupExecutionArgs(t *testing.T) {
 		runsNow    bool
 		shownStmt  string
 		paused     bool
+		// compactAfter is the number of incremental backups after which the
+		// schedule compacts its chain of backups.
+		compactAfter int64
+		retention    time.Duration
 	}
 
 	testCases := []struct {
@@ -371,6 +378,76 @@ func TestSerializesScheduledBackupExecutionArgs(t *testing.T) {
 			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' WITH encryption_passphrase=$1 RECURRING '@hourly'`,
 			errMsg: "failed to evaluate backup encryption_passphrase",
 		},
+		{
+			name: "compact-after-incrementals",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			WITH SCHEDULE OPTIONS compact_after_incrementals = '12'`,
+			expectedSchedules: []expectedSchedule{
+				{
+					nameRe:       "BACKUP .*",
+					backupStmt:   "BACKUP INTO LATEST IN 'nodelocal://0/backup' WITH detached",
+					period:       time.Hour,
+					paused:       true,
+					compactAfter: 12,
+				},
+				{
+					nameRe:     "BACKUP .+",
+					backupStmt: "BACKUP INTO 'nodelocal://0/backup' WITH detached",
+					period:     24 * time.Hour,
+					runsNow:    true,
+				},
+			},
+		},
+		{
+			name: "compact-after-incrementals-not-positive",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			WITH SCHEDULE OPTIONS compact_after_incrementals = '0'`,
+			errMsg: `"0" is not a valid compact_after_incrementals; it must be a positive integer`,
+		},
+		{
+			name: "compact-after-incrementals-without-incrementals",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			FULL BACKUP ALWAYS WITH SCHEDULE OPTIONS compact_after_incrementals = '4'`,
+			errMsg: "compact_after_incrementals requires a schedule which takes incremental backups",
+		},
+		{
+			name: "retention",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			WITH SCHEDULE OPTIONS retention = '30 days'`,
+			expectedSchedules: []expectedSchedule{
+				{
+					nameRe:     "BACKUP .*",
+					backupStmt: "BACKUP INTO LATEST IN 'nodelocal://0/backup' WITH detached",
+					period:     time.Hour,
+					paused:     true,
+				},
+				{
+					nameRe:     "BACKUP .+",
+					backupStmt: "BACKUP INTO 'nodelocal://0/backup' WITH detached",
+					period:     24 * time.Hour,
+					runsNow:    true,
+					retention:  30 * 24 * time.Hour,
+				},
+			},
+		},
+		{
+			name: "retention-not-positive",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			WITH SCHEDULE OPTIONS retention = '-1 day'`,
+			errMsg: `"-1 day" is not a valid retention; it must be a positive interval`,
+		},
+		{
+			name: "retention-not-interval",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'nodelocal://0/backup' RECURRING '@hourly'
+			WITH SCHEDULE OPTIONS retention = 'forever'`,
+			errMsg: `"forever" is not a valid retention; it must be a positive interval`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -425,6 +502,11 @@ func TestSerializesScheduledBackupExecutionArgs(t *testing.T) {
 				require.EqualValues(t, expectedSchedule.period, frequency, expectedSchedule)
 
 				require.Equal(t, expectedSchedule.paused, s.IsPaused())
+
+				args := &ScheduledBackupExecutionArgs{}
+				require.NoError(t, pbtypes.UnmarshalAny(s.ExecutionArgs().Args, args))
+				require.Equal(t, expectedSchedule.compactAfter, args.CompactAfterIncrementals)
+				require.Equal(t, expectedSchedule.retention, args.Retention)
 				if expectedSchedule.runsNow {
 					require.EqualValues(t, th.env.Now().Round(time.Microsecond), s.ScheduledRunTime())
 				}
@@ -576,6 +658,102 @@ INSERT INTO t1 values (-1), (10), (-100);
 	}
 }
 
+func TestScheduledBackupRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	const collection = "nodelocal://0/retention"
+	ctx := context.Background()
+	execCfg := th.server.ExecutorConfig().(sql.ExecutorConfig)
+	readLatest := func() string {
+		latest, err := ioutil.ReadFile(path.Join(th.iodir, "retention", latestFileName))
+		require.NoError(t, err)
+		return string(latest)
+	}
+	countFiles := func(subdir string) int {
+		var n int
+		require.NoError(t, filepath.Walk(path.Join(th.iodir, "retention", subdir),
+			func(_ string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					n++
+				}
+				return err
+			}))
+		return n
+	}
+	deletedChains := func() [][]string {
+		return th.sqlDB.QueryStr(t, `
+SELECT info::JSONB->>'ScheduleID', info::JSONB->>'Subdir', info::JSONB->>'NumBackups'
+FROM system.eventlog WHERE "eventType" = 'delete_backup_chain'`)
+	}
+
+	// The backups of the schedule can't be taken as of the time of the test
+	// environment.
+	knobs := th.cfg.TestingKnobs.(*jobs.TestingKnobs)
+	knobs.OverrideAsOfClause = func(clause *tree.AsOfClause) {
+		expr, err := tree.MakeDTimestampTZ(th.cfg.DB.Clock().PhysicalTime(), time.Microsecond)
+		require.NoError(t, err)
+		clause.Expr = expr
+	}
+	defer func() { knobs.OverrideAsOfClause = nil }()
+
+	th.sqlDB.Exec(t, `CREATE DATABASE db; CREATE TABLE db.t(a int); INSERT INTO db.t VALUES (1)`)
+	schedules, err := th.createBackupSchedule(t, `
+CREATE SCHEDULE FOR BACKUP DATABASE db INTO $1 WITH encryption_passphrase = 'secret'
+RECURRING '@hourly' FULL BACKUP ALWAYS`, collection)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	schedule := schedules[0]
+	defer th.sqlDB.Exec(t, "DROP SCHEDULE $1", schedule.ScheduleID())
+	args := &ScheduledBackupExecutionArgs{}
+	require.NoError(t, pbtypes.UnmarshalAny(schedule.ExecutionArgs().Args, args))
+	encryptionParams, err := scheduledBackupEncryptionParams(&execCfg, args)
+	require.NoError(t, err)
+	deleteExpired := func(cutoff time.Time) {
+		require.NoError(t, deleteExpiredBackupChains(ctx, &execCfg, security.RootUserName(),
+			schedule.ScheduleID(), collection, encryptionParams, cutoff))
+	}
+
+	// Nothing was backed up to the collection yet.
+	deleteExpired(timeutil.Now())
+
+	// The full backup of the first chain is taken by the schedule, and those of
+	// the other chains are not.
+	start := timeutil.Now().Add(-time.Second)
+	th.env.SetTime(schedule.NextRun().Add(time.Second))
+	require.NoError(t, th.executeSchedules())
+	th.waitForSuccessfulScheduledJob(t, schedule.ScheduleID())
+	expired := readLatest()
+	th.sqlDB.Exec(t, `BACKUP DATABASE db INTO LATEST IN $1 WITH encryption_passphrase = 'secret'`,
+		collection)
+	th.sqlDB.Exec(t, `BACKUP DATABASE db INTO LATEST IN $1 WITH encryption_passphrase = 'secret'`,
+		collection)
+	th.sqlDB.Exec(t, `BACKUP DATABASE db INTO $1`, collection)
+	other := readLatest()
+	th.sqlDB.Exec(t, `BACKUP DATABASE db INTO $1 WITH encryption_passphrase = 'secret'`, collection)
+	latest := readLatest()
+	require.NotZero(t, countFiles(expired))
+	require.NotZero(t, countFiles(other))
+	require.NotZero(t, countFiles(latest))
+
+	// No chain ended before the cutoff.
+	deleteExpired(start)
+	require.Empty(t, deletedChains())
+
+	// Every chain ended before the cutoff, but only the chain of the schedule is
+	// deleted, and the latest chain is kept.
+	deleteExpired(timeutil.Now().Add(time.Hour))
+	require.Zero(t, countFiles(expired))
+	require.NotZero(t, countFiles(other))
+	require.NotZero(t, countFiles(latest))
+	require.Equal(t, [][]string{{fmt.Sprint(schedule.ScheduleID()), expired, "3"}}, deletedChains())
+	th.sqlDB.Exec(t, `SHOW BACKUP $1 WITH encryption_passphrase = 'secret'`, collection+latest)
+
+	deleteExpired(timeutil.Now().Add(time.Hour))
+	require.Len(t, deletedChains(), 1)
+}
+
 func TestCreateBackupScheduleRequiresAdminRole(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	th, cleanup := newTestHelper(t)
@@ -0,0 +1,203 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The reasons for which a table cannot be restored during a window.
+const (
+	windowReasonMissing           = "table does not exist"
+	windowReasonDropped           = "table dropped"
+	windowReasonOffline           = "table offline"
+	windowReasonAdding            = "table being created"
+	windowReasonNotBackedUp       = "table data not backed up"
+	windowReasonNoRevisionHistory = "backup taken without revision_history"
+	windowReasonRevisionsGCed     = "revision history not retained"
+)
+
+// restorableWindow is a window of time, (start, end], at every time of which a
+// table in a chain of backups either can or cannot be restored AS OF SYSTEM
+// TIME.
+type restorableWindow struct {
+	start, end hlc.Timestamp
+	// reason is why the table cannot be restored during the window, and is empty
+	// if it can be.
+	reason string
+}
+
+func (w restorableWindow) restorable() bool {
+	return w.reason == ""
+}
+
+func (w restorableWindow) contains(ts hlc.Timestamp) bool {
+	return w.start.Less(ts) && ts.LessEq(w.end)
+}
+
+// tableRestorableWindows returns the consecutive windows of time during which
+// the table can or cannot be restored from the chain of backups, in order.
+// Windows before the table was created are omitted.
+//
+// A backup taken with revision_history can be restored as of any time since
+// the later of its start time and its revision start time, but one taken
+// without it only as of its end time. Within these, a table can be restored
+// while its descriptor, as of that time, is public, and while its data was
+// backed up by every backup of the chain up to that time, or was reintroduced
+// in full since: a table that was offline when a backup was taken, e.g. during
+// an IMPORT INTO, is not backed up until it comes back online, so the backups
+// in between cannot restore it even once it is public. Views have no data, so
+// only their descriptors matter.
+func tableRestorableWindows(
+	codec keys.SQLCodec, manifests []BackupManifest, table catalog.TableDescriptor,
+) []restorableWindow {
+	id := table.GetID()
+	prefix := codec.TablePrefix(uint32(id))
+	span := roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+
+	var windows []restorableWindow
+	addWindow := func(start, end hlc.Timestamp, reason string) {
+		if !start.Less(end) {
+			return
+		}
+		if n := len(windows); n > 0 && windows[n-1].reason == reason && windows[n-1].end == start {
+			windows[n-1].end = end
+			return
+		}
+		if len(windows) == 0 && reason == windowReasonMissing {
+			return
+		}
+		windows = append(windows, restorableWindow{start: start, end: end, reason: reason})
+	}
+
+	state := windowReasonMissing
+	var backedUp bool
+	// reason returns why the table cannot be restored during a window, which is
+	// the state of its descriptor, unless it is public, otherwise the reason the
+	// backup lost the revisions of the window, if it did, or whether its data
+	// was not backed up.
+	reason := func(lost string) string {
+		switch {
+		case state != "":
+			return state
+		case lost != "":
+			return lost
+		case !backedUp:
+			return windowReasonNotBackedUp
+		}
+		return ""
+	}
+
+	for i := range manifests {
+		m := &manifests[i]
+		if !table.IsPhysicalTable() {
+			backedUp = true
+		} else if i == 0 {
+			backedUp = spansOverlap(m.Spans, span)
+		} else {
+			backedUp = spansOverlap(m.IntroducedSpans, span) || (backedUp && spansOverlap(m.Spans, span))
+		}
+
+		if m.MVCCFilter != MVCCFilter_All {
+			if i > 0 {
+				addWindow(m.StartTime, m.EndTime.Prev(), reason(windowReasonNoRevisionHistory))
+			}
+			state = windowReasonMissing
+			for j := range m.Descriptors {
+				if descpb.GetDescriptorID(&m.Descriptors[j]) == id {
+					state = tableRestorableState(&m.Descriptors[j])
+					break
+				}
+			}
+			addWindow(m.EndTime.Prev(), m.EndTime, reason(""))
+			continue
+		}
+
+		start := m.StartTime
+		if i == 0 {
+			// The chain starts with the revisions of the full backup.
+			start = m.RevisionStartTime
+		} else if start.Less(m.RevisionStartTime) {
+			addWindow(start, m.RevisionStartTime, reason(windowReasonRevisionsGCed))
+			start = m.RevisionStartTime
+		}
+		cur := start
+		for _, rev := range m.DescriptorChanges {
+			if rev.ID != id {
+				continue
+			}
+			if start.Less(rev.Time) {
+				addWindow(cur, rev.Time.Prev(), reason(""))
+				cur = rev.Time.Prev()
+			}
+			state = tableRestorableState(rev.Desc)
+		}
+		addWindow(cur, m.EndTime, reason(""))
+	}
+	return windows
+}
+
+// tableRestorableState returns why the table described by desc cannot be
+// restored, or an empty string if it can be.
+func tableRestorableState(desc *descpb.Descriptor) string {
+	table := descpb.TableFromDescriptor(desc, hlc.Timestamp{})
+	if table == nil {
+		return windowReasonMissing
+	}
+	switch {
+	case table.Dropped():
+		return windowReasonDropped
+	case table.Offline():
+		if table.OfflineReason != "" {
+			return windowReasonOffline + ": " + table.OfflineReason
+		}
+		return windowReasonOffline
+	case table.Adding():
+		return windowReasonAdding
+	}
+	return ""
+}
+
+func spansOverlap(spans []roachpb.Span, span roachpb.Span) bool {
+	for _, s := range spans {
+		if s.Overlaps(span) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTablesRestorableAt checks that the tables can be restored as of endTime
+// from the chain of backups, so that a RESTORE to a time at which the data of
+// a table is not in the backups fails before its job is created.
+func checkTablesRestorableAt(
+	codec keys.SQLCodec,
+	manifests []BackupManifest,
+	tables []catalog.TableDescriptor,
+	endTime hlc.Timestamp,
+) error {
+	for _, table := range tables {
+		for _, w := range tableRestorableWindows(codec, manifests, table) {
+			if w.contains(endTime) && !w.restorable() {
+				return errors.WithHint(
+					errors.Errorf("table %q cannot be restored as of %s: %s",
+						table.GetName(), timeutil.Unix(0, endTime.WallTime).UTC(), w.reason),
+					"use SHOW BACKUP TIMESTAMPS to find the times to which each table can be restored",
+				)
+			}
+		}
+	}
+	return nil
+}
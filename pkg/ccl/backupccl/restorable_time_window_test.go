@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableRestorableWindows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	codec := keys.SystemSQLCodec
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+	makeTable := func(
+		id descpb.ID, state descpb.DescriptorState, offlineReason, viewQuery string,
+	) catalog.TableDescriptor {
+		return tabledesc.NewImmutable(descpb.TableDescriptor{
+			ID:            id,
+			Name:          "t",
+			ParentID:      50,
+			State:         state,
+			OfflineReason: offlineReason,
+			ViewQuery:     viewQuery,
+		})
+	}
+	const tableID, droppedID, viewID = 52, 53, 54
+	public := makeTable(tableID, descpb.DescriptorState_PUBLIC, "", "")
+	importing := makeTable(tableID, descpb.DescriptorState_OFFLINE, "importing", "")
+	dropped := makeTable(droppedID, descpb.DescriptorState_PUBLIC, "", "")
+	view := makeTable(viewID, descpb.DescriptorState_PUBLIC, "", "SELECT 1")
+	rev := func(wall int64, id descpb.ID, desc catalog.TableDescriptor) BackupManifest_DescriptorRevision {
+		r := BackupManifest_DescriptorRevision{Time: ts(wall), ID: id}
+		if desc != nil {
+			r.Desc = desc.DescriptorProto()
+		}
+		return r
+	}
+	tableSpan := func(id uint32) roachpb.Span {
+		prefix := codec.TablePrefix(id)
+		return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+	}
+	tableSpans := []roachpb.Span{tableSpan(tableID), tableSpan(droppedID)}
+
+	manifests := []BackupManifest{
+		{
+			// A full backup with revision_history, during which the table is
+			// created, and another table is created and dropped.
+			EndTime:    ts(10),
+			MVCCFilter: MVCCFilter_All,
+			Spans:      tableSpans,
+			DescriptorChanges: []BackupManifest_DescriptorRevision{
+				rev(1, viewID, view),
+				rev(2, tableID, public),
+				rev(3, droppedID, dropped),
+				rev(5, droppedID, makeTable(droppedID, descpb.DescriptorState_DROP, "", "")),
+				rev(8, droppedID, nil),
+			},
+		},
+		{
+			// An incremental backup with revision_history, during which an IMPORT
+			// INTO the table starts.
+			StartTime:  ts(10),
+			EndTime:    ts(20),
+			MVCCFilter: MVCCFilter_All,
+			Spans:      tableSpans[:1],
+			DescriptorChanges: []BackupManifest_DescriptorRevision{
+				rev(10, viewID, view),
+				rev(10, tableID, public),
+				rev(15, tableID, importing),
+			},
+		},
+		{
+			// An incremental backup without revision_history, once the IMPORT INTO
+			// is done, which does not back up the table.
+			StartTime:   ts(20),
+			EndTime:     ts(30),
+			MVCCFilter:  MVCCFilter_Latest,
+			Descriptors: []descpb.Descriptor{*view.DescriptorProto(), *public.DescriptorProto()},
+		},
+		{
+			// An incremental backup which reintroduces the table.
+			StartTime:       ts(30),
+			EndTime:         ts(40),
+			MVCCFilter:      MVCCFilter_Latest,
+			Spans:           tableSpans[:1],
+			IntroducedSpans: tableSpans[:1],
+			Descriptors:     []descpb.Descriptor{*view.DescriptorProto(), *public.DescriptorProto()},
+		},
+	}
+
+	require.Equal(t, []restorableWindow{
+		{start: ts(2).Prev(), end: ts(15).Prev()},
+		{start: ts(15).Prev(), end: ts(30).Prev(), reason: windowReasonOffline + ": importing"},
+		{start: ts(30).Prev(), end: ts(30), reason: windowReasonNotBackedUp},
+		{start: ts(30), end: ts(40).Prev(), reason: windowReasonNoRevisionHistory},
+		{start: ts(40).Prev(), end: ts(40)},
+	}, tableRestorableWindows(codec, manifests, public))
+
+	require.Equal(t, []restorableWindow{
+		{start: ts(3).Prev(), end: ts(5).Prev()},
+		{start: ts(5).Prev(), end: ts(8).Prev(), reason: windowReasonDropped},
+		{start: ts(8).Prev(), end: ts(40), reason: windowReasonMissing},
+	}, tableRestorableWindows(codec, manifests, dropped))
+
+	// The data of views is never backed up, so only their descriptors matter.
+	require.Equal(t, []restorableWindow{
+		{start: ts(1).Prev(), end: ts(20)},
+		{start: ts(20), end: ts(30).Prev(), reason: windowReasonNoRevisionHistory},
+		{start: ts(30).Prev(), end: ts(30)},
+		{start: ts(30), end: ts(40).Prev(), reason: windowReasonNoRevisionHistory},
+		{start: ts(40).Prev(), end: ts(40)},
+	}, tableRestorableWindows(codec, manifests, view))
+
+	tables := []catalog.TableDescriptor{public, view}
+	require.NoError(t, checkTablesRestorableAt(codec, manifests, tables, ts(12)))
+	require.NoError(t, checkTablesRestorableAt(codec, manifests, tables, ts(40)))
+	require.True(t, testutils.IsError(
+		checkTablesRestorableAt(codec, manifests, tables, ts(30)),
+		`table "t" cannot be restored as of .*: table data not backed up`,
+	))
+}
@@ -15,9 +15,13 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/bulk"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
@@ -25,6 +29,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -36,6 +42,15 @@ import (
 // Progress is streamed to the coordinator through metadata.
 var restoreDataOutputTypes = []*types.T{}
 
+// restoreVerifyOutputTypes are the types of the rows emitted in verify mode:
+// the progress index of the checked entry, its status, and the error that
+// the status is based on, if any.
+var restoreVerifyOutputTypes = []*types.T{
+	types.Int,    // ProgressIdx of the entry
+	types.String, // status
+	types.String, // detail
+}
+
 type restoreDataProcessor struct {
 	execinfra.ProcessorBase
 
@@ -46,6 +61,10 @@ type restoreDataProcessor struct {
 
 	alloc rowenc.DatumAlloc
 	kr    *storageccl.KeyRewriter
+
+	// verifyTables holds every version of the tables in spec.VerifyTables, by
+	// ID. It is only set in verify mode.
+	verifyTables map[descpb.ID][]catalog.TableDescriptor
 }
 
 var _ execinfra.Processor = &restoreDataProcessor{}
@@ -74,7 +93,17 @@ func newRestoreDataProcessor(
 		return nil, err
 	}
 
-	if err := rd.Init(rd, post, restoreDataOutputTypes, flowCtx, processorID, output, nil, /* memMonitor */
+	outputTypes := restoreDataOutputTypes
+	if spec.Verify {
+		outputTypes = restoreVerifyOutputTypes
+		rd.verifyTables = make(map[descpb.ID][]catalog.TableDescriptor)
+		for i := range spec.VerifyTables {
+			table := tabledesc.NewImmutable(spec.VerifyTables[i])
+			rd.verifyTables[table.GetID()] = append(rd.verifyTables[table.GetID()], table)
+		}
+	}
+
+	if err := rd.Init(rd, post, outputTypes, flowCtx, processorID, output, nil, /* memMonitor */
 		execinfra.ProcStateOpts{
 			InputsToDrain: []execinfra.RowSource{input},
 		}); err != nil {
@@ -130,6 +159,41 @@ func (rd *restoreDataProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.Produce
 		return nil, rd.DrainHelper()
 	}
 
+	if rd.spec.Verify {
+		log.VEventf(rd.Ctx, 1 /* level */, "verifying span %v", entry.Span)
+		status, detail, err := rd.verifyRestoreSpanEntry(entry)
+		if err != nil {
+			rd.MoveToDraining(err)
+			return nil, rd.DrainHelper()
+		}
+		row := rowenc.EncDatumRow{
+			rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(entry.ProgressIdx))),
+			rowenc.DatumToEncDatum(types.String, tree.NewDString(status)),
+			rowenc.DatumToEncDatum(types.String, detail),
+		}
+		if outRow := rd.ProcessRowHelper(row); outRow != nil {
+			return outRow, nil
+		}
+		return nil, rd.DrainHelper()
+	}
+
+	if rd.spec.CompactionDest != nil {
+		log.VEventf(rd.Ctx, 1 /* level */, "compacting span %v", entry.Span)
+		files, err := rd.compactRestoreSpanEntry(entry)
+		if err != nil {
+			rd.MoveToDraining(err)
+			return nil, rd.DrainHelper()
+		}
+		var prog execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
+		details, err := gogotypes.MarshalAny(&BackupManifest_Progress{Files: files})
+		if err != nil {
+			rd.MoveToDraining(err)
+			return nil, rd.DrainHelper()
+		}
+		prog.ProgressDetails = *details
+		return nil, &execinfrapb.ProducerMetadata{BulkProcessorProgress: &prog}
+	}
+
 	newSpanKey, err := rewriteBackupSpanKey(rd.flowCtx.Codec(), rd.kr, entry.Span.Key)
 	if err != nil {
 		rd.MoveToDraining(errors.Wrap(err, "re-writing span key to import"))
@@ -187,47 +251,13 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 	for _, file := range entry.Files {
 		log.VEventf(ctx, 2, "import file %s %s", file.Path, newSpanKey)
 
-		dir, err := rd.flowCtx.Cfg.ExternalStorage(ctx, file.Dir)
+		fileContents, err := fetchBackupFile(ctx, rd.flowCtx.Cfg.ExternalStorage, file)
 		if err != nil {
 			return summary, err
 		}
-		defer func() {
-			if err := dir.Close(); err != nil {
-				log.Warningf(ctx, "close export storage failed %v", err)
-			}
-		}()
-
-		const maxAttempts = 3
-		var fileContents []byte
-		if err := retry.WithMaxAttempts(ctx, base.DefaultRetryOptions(), maxAttempts, func() error {
-			f, err := dir.ReadFile(ctx, file.Path)
-			if err != nil {
-				return err
-			}
-			defer f.Close()
-			fileContents, err = ioutil.ReadAll(f)
-			return err
-		}); err != nil {
-			return summary, errors.Wrapf(err, "fetching %q", file.Path)
-		}
-		dataSize := int64(len(fileContents))
-		log.Eventf(ctx, "fetched file (%s)", humanizeutil.IBytes(dataSize))
-
-		if rd.spec.Encryption != nil {
-			fileContents, err = storageccl.DecryptFile(fileContents, rd.spec.Encryption.Key)
-			if err != nil {
-				return summary, err
-			}
-		}
-
-		if len(file.Sha512) > 0 {
-			checksum, err := storageccl.SHA512ChecksumData(fileContents)
-			if err != nil {
-				return summary, err
-			}
-			if !bytes.Equal(checksum, file.Sha512) {
-				return summary, errors.Errorf("checksum mismatch for %s", file.Path)
-			}
+		fileContents, err = decodeBackupFile(fileContents, file, rd.spec.Encryption)
+		if err != nil {
+			return summary, err
 		}
 
 		iter, err := storage.NewMemSSTIterator(fileContents, false)
@@ -323,3 +353,240 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 
 	return batcher.GetSummary(), nil
 }
+
+// verifyRestoreSpanEntry checks the files of a restore span entry without
+// ingesting them, and returns the status of the entry: verifyStatusMissing
+// if a file doesn't exist, verifyStatusCorrupt if a file can't be decoded or
+// its data doesn't match the tables it belongs to, and verifyStatusOK
+// otherwise. The detail is the error that the status is based on, or NULL.
+func (rd *restoreDataProcessor) verifyRestoreSpanEntry(
+	entry execinfrapb.RestoreSpanEntry,
+) (string, tree.Datum, error) {
+	for _, file := range entry.Files {
+		fileContents, err := fetchBackupFile(rd.Ctx, rd.flowCtx.Cfg.ExternalStorage, file)
+		if err != nil {
+			if !errors.Is(err, cloudimpl.ErrFileDoesNotExist) {
+				return "", nil, err
+			}
+			return verifyStatusMissing, tree.NewDString(err.Error()), nil
+		}
+		sst, err := decodeBackupFile(fileContents, file, rd.spec.Encryption)
+		if err != nil {
+			return verifyStatusCorrupt, tree.NewDString(err.Error()), nil
+		}
+		if err := verifyBackupFileData(rd.flowCtx.Codec(), sst, entry.Span, rd.verifyTables); err != nil {
+			return verifyStatusCorrupt, tree.NewDString(err.Error()), nil
+		}
+	}
+	return verifyStatusOK, tree.DNull, nil
+}
+
+// compactRestoreSpanEntry merges the files of a restore span entry into new
+// files in the compaction destination, which cover the span of the entry, and
+// returns them.
+func (rd *restoreDataProcessor) compactRestoreSpanEntry(
+	entry execinfrapb.RestoreSpanEntry,
+) ([]BackupManifest_File, error) {
+	dest, err := rd.flowCtx.Cfg.ExternalStorage(rd.Ctx, *rd.spec.CompactionDest)
+	if err != nil {
+		return nil, err
+	}
+	defer dest.Close()
+
+	sink := compactionSink{
+		dest:       dest,
+		encryption: rd.spec.Encryption,
+		instanceID: rd.flowCtx.NodeID.SQLInstanceID(),
+		targetSize: storageccl.ExportRequestTargetFileSize.Get(&rd.flowCtx.Cfg.Settings.SV),
+		pkIDs:      rd.spec.PKIDs,
+	}
+	defer sink.close()
+	if err := sink.addEntry(
+		rd.Ctx, rd.flowCtx.Cfg.ExternalStorage, entry, rd.spec.CompactionKeepRevisions,
+	); err != nil {
+		return nil, err
+	}
+	if err := sink.flush(rd.Ctx, entry.Span.EndKey); err != nil {
+		return nil, err
+	}
+	return sink.files, nil
+}
+
+// fetchBackupFile reads the contents of a backup file from external storage,
+// retrying transient errors.
+func fetchBackupFile(
+	ctx context.Context,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	file roachpb.ImportRequest_File,
+) ([]byte, error) {
+	dir, err := makeExternalStorage(ctx, file.Dir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := dir.Close(); err != nil {
+			log.Warningf(ctx, "close export storage failed %v", err)
+		}
+	}()
+
+	const maxAttempts = 3
+	var fileContents []byte
+	if err := retry.WithMaxAttempts(ctx, base.DefaultRetryOptions(), maxAttempts, func() error {
+		f, err := dir.ReadFile(ctx, file.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fileContents, err = ioutil.ReadAll(f)
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "fetching %q", file.Path)
+	}
+	log.Eventf(ctx, "fetched file (%s)", humanizeutil.IBytes(int64(len(fileContents))))
+	return fileContents, nil
+}
+
+// decodeBackupFile decrypts the contents of a backup file, if the backup is
+// encrypted, and checks them against the checksum recorded in the backup
+// manifest, if any. It returns the sstable stored in the file.
+func decodeBackupFile(
+	fileContents []byte, file roachpb.ImportRequest_File, encryption *roachpb.FileEncryptionOptions,
+) ([]byte, error) {
+	if encryption != nil {
+		var err error
+		fileContents, err = storageccl.DecryptFile(fileContents, encryption.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(file.Sha512) > 0 {
+		checksum, err := storageccl.SHA512ChecksumData(fileContents)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(checksum, file.Sha512) {
+			return nil, errors.Errorf("checksum mismatch for %s", file.Path)
+		}
+	}
+	return fileContents, nil
+}
+
+// verifyBackupFileData checks the sstable of a backup file without restoring
+// it: every key must lie within the span of the file and, unless it belongs
+// to a tenant other than the one of the codec, decode against the index of
+// one of the versions of its table in tables, and every value must match its
+// checksum.
+func verifyBackupFileData(
+	codec keys.SQLCodec,
+	sst []byte,
+	span roachpb.Span,
+	tables map[descpb.ID][]catalog.TableDescriptor,
+) error {
+	iter, err := storage.NewMemSSTIterator(sst, false /* verify */)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	type indexInfo struct {
+		table   catalog.TableDescriptor
+		index   *descpb.IndexDescriptor
+		types   []*types.T
+		vals    []rowenc.EncDatum
+		colDirs []descpb.IndexDescriptor_Direction
+	}
+	// indexes holds, for every index, an entry for each version of its table
+	// which has the index.
+	indexes := make(map[tableAndIndex][]*indexInfo)
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.Next() {
+		ok, err := iter.Valid()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if !span.ContainsKey(key.Key) {
+			return errors.Errorf("key %s is outside of file span %s", key, span)
+		}
+		if err := (roachpb.Value{RawBytes: iter.UnsafeValue()}).Verify(key.Key); err != nil {
+			return err
+		}
+
+		if codec.ForSystemTenant() {
+			// The data of tenants is backed up as is, and it cannot be decoded
+			// without their descriptors, which aren't part of the backup.
+			_, tenID, err := keys.DecodeTenantPrefix(key.Key)
+			if err != nil {
+				return errors.Wrapf(err, "decoding key %s", key)
+			}
+			if tenID != roachpb.SystemTenantID {
+				continue
+			}
+		}
+		_, tableID, indexID, err := codec.DecodeIndexPrefix(key.Key)
+		if err != nil {
+			return errors.Wrapf(err, "decoding key %s", key)
+		}
+		ti := tableAndIndex{tableID: descpb.ID(tableID), indexID: descpb.IndexID(indexID)}
+		versions, ok := tables[ti.tableID]
+		if !ok {
+			return errors.Errorf("key %s belongs to table %d, which is not in the backup", key, tableID)
+		}
+		infos, ok := indexes[ti]
+		if !ok {
+			for _, table := range versions {
+				index, err := table.FindIndexByID(ti.indexID)
+				if err != nil {
+					continue
+				}
+				info := &indexInfo{
+					table:   table,
+					index:   index,
+					types:   make([]*types.T, len(index.ColumnIDs)),
+					vals:    make([]rowenc.EncDatum, len(index.ColumnIDs)),
+					colDirs: index.ColumnDirections,
+				}
+				for i, colID := range index.ColumnIDs {
+					col, err := table.FindColumnByID(colID)
+					if err != nil {
+						return errors.Wrapf(err, "index %q of table %q", index.Name, table.GetName())
+					}
+					info.types[i] = col.Type
+				}
+				infos = append(infos, info)
+			}
+			if len(infos) == 0 {
+				return errors.Errorf("key %s belongs to index %d of table %q, which is not in the backup",
+					key, indexID, versions[0].GetName())
+			}
+			indexes[ti] = infos
+		}
+		var decodeErr error
+		for _, info := range infos {
+			_, matches, _, err := rowenc.DecodeIndexKey(
+				codec, info.table, info.index, info.types, info.vals, info.colDirs, key.Key,
+			)
+			if err != nil {
+				decodeErr = errors.Wrapf(err, "decoding key %s against index %q of table %q",
+					key, info.index.Name, info.table.GetName())
+				continue
+			}
+			// Keys of interleaved children are prefixed by the key of their
+			// ancestor in the outermost table, which they don't match.
+			if !matches && len(info.index.InterleavedBy) == 0 {
+				decodeErr = errors.Errorf("key %s does not match index %q of table %q",
+					key, info.index.Name, info.table.GetName())
+				continue
+			}
+			decodeErr = nil
+			break
+		}
+		if decodeErr != nil {
+			return decodeErr
+		}
+	}
+	return nil
+}
@@ -21,7 +21,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
@@ -49,32 +48,125 @@ func distRestore(
 ) error {
 	ctx = logtags.AddTag(ctx, "restore-distsql", nil)
 	defer close(progCh)
-	var noTxn *kv.Txn
 
-	dsp := execCtx.DistSQLPlanner()
-	evalCtx := execCtx.ExtendedEvalContext()
+	fileEncryption, err := restoreFileEncryption(ctx, execCtx.ExecCfg(), encryption)
+	if err != nil {
+		return err
+	}
+
+	restoreDataSpec := execinfrapb.RestoreDataSpec{
+		RestoreTime: restoreTime,
+		Encryption:  fileEncryption,
+		Rekeys:      rekeys,
+		PKIDs:       pkIDs,
+	}
+
+	metaFn := func(_ context.Context, meta *execinfrapb.ProducerMetadata) error {
+		if meta.BulkProcessorProgress != nil {
+			// Send the progress up a level to be written to the manifest.
+			progCh <- meta.BulkProcessorProgress
+		}
+		return nil
+	}
+
+	rowResultWriter := sql.NewRowResultWriter(nil)
+	if err := runRestoreFlow(
+		ctx, execCtx, chunks, rekeys, restoreDataSpec,
+		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+	); err != nil {
+		return err
+	}
+	return rowResultWriter.Err()
+}
+
+// distVerifyBackupTableData runs the flow of distRestore in verify mode: the
+// splitAndScatter processors route every entry to the restore data processor
+// on their own node without splitting or scattering anything, and the restore
+// data processors check the files of the entries instead of ingesting them.
+// Each entry is expected to hold a single file. The status of every entry is
+// passed to fn along with its ProgressIdx.
+func distVerifyBackupTableData(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	chunks [][]execinfrapb.RestoreSpanEntry,
+	encryption *jobspb.BackupEncryptionOptions,
+	tables []descpb.TableDescriptor,
+	fn func(progressIdx int64, status string, detail tree.Datum) error,
+) error {
+	ctx = logtags.AddTag(ctx, "restore-verify-distsql", nil)
+
+	fileEncryption, err := restoreFileEncryption(ctx, execCtx.ExecCfg(), encryption)
+	if err != nil {
+		return err
+	}
 
-	if encryption != nil && encryption.Mode == jobspb.EncryptionMode_KMS {
+	restoreDataSpec := execinfrapb.RestoreDataSpec{
+		Encryption:   fileEncryption,
+		Verify:       true,
+		VerifyTables: tables,
+	}
+
+	rowFn := func(_ context.Context, row tree.Datums) error {
+		return fn(int64(tree.MustBeDInt(row[0])), string(tree.MustBeDString(row[1])), row[2])
+	}
+	metaFn := func(context.Context, *execinfrapb.ProducerMetadata) error { return nil }
+
+	rowResultWriter := sql.NewCallbackResultWriter(rowFn)
+	if err := runRestoreFlow(
+		ctx, execCtx, chunks, nil /* rekeys */, restoreDataSpec,
+		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+	); err != nil {
+		return err
+	}
+	return rowResultWriter.Err()
+}
+
+// restoreFileEncryption returns the options the restore data processors use
+// to decrypt the files of a backup, decrypting the data key with the KMS if
+// the backup is encrypted with one.
+func restoreFileEncryption(
+	ctx context.Context, execCfg *sql.ExecutorConfig, encryption *jobspb.BackupEncryptionOptions,
+) (*roachpb.FileEncryptionOptions, error) {
+	if encryption == nil {
+		return nil, nil
+	}
+	if encryption.Mode == jobspb.EncryptionMode_KMS {
 		kms, err := cloud.KMSFromURI(encryption.KMSInfo.Uri, &backupKMSEnv{
-			settings: execCtx.ExecCfg().Settings,
-			conf:     &execCtx.ExecCfg().ExternalIODirConfig,
+			settings: execCfg.Settings,
+			conf:     &execCfg.ExternalIODirConfig,
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		encryption.Key, err = kms.Decrypt(ctx, encryption.KMSInfo.EncryptedDataKey)
 		if err != nil {
-			return errors.Wrap(err,
+			return nil, errors.Wrap(err,
 				"failed to decrypt data key before starting BackupDataProcessor")
 		}
 	}
 	// Wrap the relevant BackupEncryptionOptions to be used by the Restore
 	// processor.
-	var fileEncryption *roachpb.FileEncryptionOptions
-	if encryption != nil {
-		fileEncryption = &roachpb.FileEncryptionOptions{Key: encryption.Key}
-	}
+	return &roachpb.FileEncryptionOptions{Key: encryption.Key}, nil
+}
+
+// runRestoreFlow plans and runs the 2 stage flow of distRestore, with the
+// given spec for the restore data processors. If the spec is in verify or
+// compaction mode, the splitAndScatter processors route the entries locally,
+// and in verify mode the rows the restore data processors emit are passed to
+// resultWriter. Errors of the flow are set on resultWriter.
+func runRestoreFlow(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	chunks [][]execinfrapb.RestoreSpanEntry,
+	rekeys []roachpb.ImportRequest_TableRekey,
+	restoreDataSpec execinfrapb.RestoreDataSpec,
+	resultWriter *sql.MetadataCallbackWriter,
+) error {
+	var noTxn *kv.Txn
+
+	dsp := execCtx.DistSQLPlanner()
+	evalCtx := execCtx.ExtendedEvalContext()
 
 	planCtx, nodes, err := dsp.SetupAllNodesPlanning(ctx, evalCtx, execCtx.ExecCfg())
 	if err != nil {
@@ -86,20 +178,27 @@ func distRestore(
 		return err
 	}
 
-	restoreDataSpec := execinfrapb.RestoreDataSpec{
-		RestoreTime: restoreTime,
-		Encryption:  fileEncryption,
-		Rekeys:      rekeys,
-		PKIDs:       pkIDs,
-	}
-
 	if len(splitAndScatterSpecs) == 0 {
 		// We should return an error here as there are no nodes that are compatible,
 		// but we should have at least found ourselves.
 		return nil
 	}
 
+	restoreDataResultTypes := restoreDataOutputTypes
+	if restoreDataSpec.Verify {
+		restoreDataResultTypes = restoreVerifyOutputTypes
+	}
+	if restoreDataSpec.Verify || restoreDataSpec.CompactionDest != nil {
+		for _, spec := range splitAndScatterSpecs {
+			spec.RouteLocally = true
+		}
+	}
+
 	p := planCtx.NewPhysicalPlan()
+	p.PlanToStreamColMap = make([]int, len(restoreDataResultTypes))
+	for i := range p.PlanToStreamColMap {
+		p.PlanToStreamColMap[i] = i
+	}
 
 	// Plan SplitAndScatter in a round-robin fashion.
 	splitAndScatterStageID := p.NewStageOnNodes(nodes)
@@ -176,7 +275,7 @@ func distRestore(
 				Post:        execinfrapb.PostProcessSpec{},
 				Output:      []execinfrapb.OutputRouterSpec{{Type: execinfrapb.OutputRouterSpec_PASS_THROUGH}},
 				StageID:     restoreDataStageID,
-				ResultTypes: []*types.T{},
+				ResultTypes: restoreDataResultTypes,
 			},
 		}
 		pIdx := p.AddProcessor(proc)
@@ -199,19 +298,9 @@ func distRestore(
 
 	dsp.FinalizePlan(planCtx, p)
 
-	metaFn := func(_ context.Context, meta *execinfrapb.ProducerMetadata) error {
-		if meta.BulkProcessorProgress != nil {
-			// Send the progress up a level to be written to the manifest.
-			progCh <- meta.BulkProcessorProgress
-		}
-		return nil
-	}
-
-	rowResultWriter := sql.NewRowResultWriter(nil)
-
 	recv := sql.MakeDistSQLReceiver(
 		ctx,
-		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+		resultWriter,
 		tree.Rows,
 		nil,   /* rangeCache */
 		noTxn, /* txn - the flow does not read or write the database */
@@ -223,7 +312,7 @@ func distRestore(
 	// Copy the evalCtx, as dsp.Run() might change it.
 	evalCtxCopy := *evalCtx
 	dsp.Run(planCtx, noTxn, p, recv, &evalCtxCopy, nil /* finishedSetupFn */)()
-	return rowResultWriter.Err()
+	return nil
 }
 
 // makeSplitAndScatterSpecs returns a map from nodeID to the SplitAndScatter
@@ -10,6 +10,9 @@ package backupccl
 
 import (
 	"context"
+	"path"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
@@ -17,12 +20,16 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/errors"
 	pbtypes "github.com/gogo/protobuf/types"
@@ -222,6 +229,303 @@ func (e *scheduledBackupExecutor) backupSucceeded(
 	return nil
 }
 
+// backupChain is a chain of backups in a collection: a full backup, and the
+// incremental backups appended to it.
+type backupChain struct {
+	// subdir is the subdirectory of the collection which holds the chain, in the
+	// form stored in the LATEST file, e.g. "/2021/01/01-000000.00".
+	subdir string
+	// numBackups is the number of backups in the chain, including the full.
+	numBackups int
+	// endTime is the end time of the most recent backup in the chain.
+	endTime time.Time
+}
+
+// fullBackupSubdirGlob matches the subdirectories of a collection which hold
+// full backups, as named by dateBasedIntoFolderName.
+const fullBackupSubdirGlob = "[0-9]*/[0-9]*/[0-9]*-[0-9]*.[0-9][0-9]/"
+
+// incBackupDayDirGlob matches the first level of the subdirectories of a full
+// backup which hold incremental backups, as named by dateBasedIncFolderName.
+const incBackupDayDirGlob = "[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]"
+
+// maybeDeleteExpiredBackupChains enforces the retention policy, if any, of the
+// schedule which took the full backup of the job, once the backup succeeded.
+// It is only done by the schedule taking full backups, since a chain can only
+// expire once a new chain was started. Failing to enforce it does not fail the
+// backup: the expired chains are deleted once the next full backup succeeds.
+func (b *backupResumer) maybeDeleteExpiredBackupChains(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails,
+) {
+	if !details.StartTime.IsEmpty() || details.CollectionURI == "" {
+		return
+	}
+	if err := func() error {
+		exec := p.ExecCfg()
+		env := scheduledJobEnv(exec)
+		scheduleID, args, err := loadCreatingScheduleArgs(ctx, env, exec, *b.job.ID())
+		if err != nil {
+			return err
+		}
+		if args == nil || args.Retention == 0 || args.BackupType != ScheduledBackupExecutionArgs_FULL {
+			return nil
+		}
+		if len(details.URIsByLocalityKV) > 0 {
+			return errors.Newf("%s is not supported for partitioned backups", optRetention)
+		}
+		encryptionParams, err := scheduledBackupEncryptionParams(exec, args)
+		if err != nil {
+			return err
+		}
+		return deleteExpiredBackupChains(ctx, exec, p.User(), scheduleID, details.CollectionURI,
+			encryptionParams, env.Now().Add(-args.Retention))
+	}(); err != nil {
+		log.Warningf(ctx, "failed to delete expired backups of the schedule of backup job %d: %v",
+			*b.job.ID(), err)
+	}
+}
+
+// scheduledBackupEncryptionParams returns the encryption parameters of the
+// backups taken by a schedule, which are the options of its backup statement.
+func scheduledBackupEncryptionParams(
+	exec *sql.ExecutorConfig, args *ScheduledBackupExecutionArgs,
+) (backupEncryptionParams, error) {
+	node, err := parser.ParseOne(args.BackupStatement)
+	if err != nil {
+		return backupEncryptionParams{}, errors.Wrap(err, "parsing backup statement")
+	}
+	backupStmt, ok := node.AST.(*tree.Backup)
+	if !ok {
+		return backupEncryptionParams{}, errors.Newf("unexpect node type %T", node)
+	}
+
+	params := backupEncryptionParams{encryptMode: noEncryption}
+	if pw := backupStmt.Options.EncryptionPassphrase; pw != nil {
+		raw, ok := pw.(*tree.StrVal)
+		if !ok {
+			return backupEncryptionParams{}, errors.Errorf("unexpected %T arg in backup schedule: %v", pw, pw)
+		}
+		params.encryptMode = passphrase
+		params.encryptionPassphrase = []byte(raw.RawString())
+	}
+	for _, uri := range backupStmt.Options.EncryptionKMSURI {
+		raw, ok := uri.(*tree.StrVal)
+		if !ok {
+			return backupEncryptionParams{}, errors.Errorf("unexpected %T arg in backup schedule: %v", uri, uri)
+		}
+		params.encryptMode = kms
+		params.kmsURIs = append(params.kmsURIs, raw.RawString())
+		params.kmsEnv = &backupKMSEnv{settings: exec.Settings, conf: &exec.ExternalIODirConfig}
+	}
+	return params, nil
+}
+
+// deleteExpiredBackupChains deletes the chains of backups in the collection
+// whose full backup was taken by the schedule and whose most recent backup
+// ended before the cutoff, and records each deleted chain in system.eventlog.
+// The full backups are decrypted, to find the schedule which took them, with
+// encryptionParams. Only whole chains are deleted, and never the chain LATEST
+// points to, since incremental backups are still appended to it.
+func deleteExpiredBackupChains(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	scheduleID int64,
+	collectionURI string,
+	encryptionParams backupEncryptionParams,
+	cutoff time.Time,
+) error {
+	collection, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, collectionURI, user)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+
+	latest, err := readLatestFile(ctx, collection)
+	if err != nil {
+		if errors.Is(err, cloudimpl.ErrFileDoesNotExist) {
+			// No backup completed in the collection yet.
+			return nil
+		}
+		return err
+	}
+	fulls, err := collection.ListFiles(ctx, fullBackupSubdirGlob+backupManifestName)
+	if err != nil {
+		return errors.Wrap(err, "listing full backups")
+	}
+	incs, err := collection.ListFiles(ctx,
+		fullBackupSubdirGlob+incBackupSubdirGlob+backupManifestName)
+	if err != nil {
+		return errors.Wrap(err, "listing incremental backups")
+	}
+	redactedURI, err := cloudimpl.SanitizeExternalStorageURI(collectionURI, nil /* extraParams */)
+	if err != nil {
+		return err
+	}
+
+	for _, chain := range makeBackupChains(fulls, incs) {
+		if chain.subdir == latest || !chain.endTime.Before(cutoff) {
+			continue
+		}
+		// Backups which were not taken by the schedule, including those taken
+		// before backups recorded their schedule, are never deleted.
+		chainScheduleID, err := readBackupChainScheduleID(
+			ctx, execCfg, user, collectionURI, chain, encryptionParams)
+		if err != nil {
+			log.Warningf(ctx, "backup schedule %d skipped backup chain %s: %v",
+				scheduleID, chain.subdir, err)
+			continue
+		}
+		if chainScheduleID != scheduleID {
+			continue
+		}
+		numFiles, err := deleteBackupChain(ctx, collection, chain)
+		if err != nil {
+			return errors.Wrapf(err, "deleting backup chain %s", chain.subdir)
+		}
+		log.Infof(ctx, "backup schedule %d deleted %d files of backup chain %s ending at %s",
+			scheduleID, numFiles, chain.subdir, chain.endTime)
+
+		event := &eventpb.DeleteBackupChain{
+			ScheduleID: scheduleID,
+			Collection: redactedURI,
+			Subdir:     chain.subdir,
+			EndTime:    chain.endTime.UnixNano(),
+			NumBackups: int32(chain.numBackups),
+			NumFiles:   int64(numFiles),
+		}
+		if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			event.Timestamp = txn.ReadTimestamp().GoTime()
+			return sql.InsertEventRecord(ctx, execCfg.InternalExecutor, txn,
+				0 /* targetID */, int32(execCfg.NodeID.SQLInstanceID()),
+				false /* skipExternalLog */, event)
+		}); err != nil {
+			return errors.Wrap(err, "recording deleted backup chain")
+		}
+	}
+	return nil
+}
+
+// makeBackupChains groups the manifests of full and incremental backups,
+// relative to the collection, into chains ordered by subdirectory. The end
+// times of the backups are taken from the names of their subdirectories, so a
+// chain holding a backup which is not named that way is left out.
+func makeBackupChains(fulls, incs []string) []backupChain {
+	chains := make(map[string]*backupChain)
+	for _, full := range fulls {
+		subdir := "/" + path.Dir(full)
+		endTime, err := time.Parse(dateBasedIntoFolderName, subdir)
+		if err != nil {
+			continue
+		}
+		chains[subdir] = &backupChain{subdir: subdir, numBackups: 1, endTime: endTime}
+	}
+	for _, inc := range incs {
+		incDir := path.Dir(inc)
+		subdir := "/" + path.Dir(path.Dir(incDir))
+		chain, ok := chains[subdir]
+		if !ok {
+			continue
+		}
+		endTime, err := time.Parse(dateBasedIncFolderName, strings.TrimPrefix("/"+incDir, subdir))
+		if err != nil {
+			delete(chains, subdir)
+			continue
+		}
+		chain.numBackups++
+		if endTime.After(chain.endTime) {
+			chain.endTime = endTime
+		}
+	}
+
+	res := make([]backupChain, 0, len(chains))
+	for _, chain := range chains {
+		res = append(res, *chain)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].subdir < res[j].subdir })
+	return res
+}
+
+// readBackupChainScheduleID returns the ID of the schedule which took the full
+// backup of the chain, or 0 if it was not taken by a schedule.
+func readBackupChainScheduleID(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	collectionURI string,
+	chain backupChain,
+	encryptionParams backupEncryptionParams,
+) (int64, error) {
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	chainURI, _, err := getURIsByLocalityKV([]string{collectionURI}, chain.subdir)
+	if err != nil {
+		return 0, err
+	}
+	encryption, err := getEncryptionFromBase(ctx, user, mkStore, chainURI, encryptionParams)
+	if err != nil {
+		return 0, err
+	}
+	store, err := mkStore(ctx, chainURI, user)
+	if err != nil {
+		return 0, err
+	}
+	defer store.Close()
+	manifest, err := readBackupManifestFromStore(ctx, store, encryption)
+	if err != nil {
+		return 0, err
+	}
+	return manifest.ScheduleID, nil
+}
+
+// deleteBackupChain deletes the files of the chain and returns how many were
+// deleted. The manifest of the full backup is deleted last but for the
+// encryption info of the chain, which is needed to read it, so that the chain
+// is still found, and its deletion retried, if this is interrupted.
+func deleteBackupChain(
+	ctx context.Context, collection cloud.ExternalStorage, chain backupChain,
+) (int, error) {
+	prefix := strings.TrimPrefix(chain.subdir, "/") + "/"
+	incFiles, err := collection.ListFiles(ctx, prefix+incBackupSubdirGlob+"*")
+	if err != nil {
+		return 0, err
+	}
+	fullFiles, err := collection.ListFiles(ctx, prefix+"*")
+	if err != nil {
+		return 0, err
+	}
+
+	fullManifest := prefix + backupManifestName
+	encryptionInfo := prefix + backupEncryptionInfoFile
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range append(incFiles, fullFiles...) {
+		// Storage with real directories lists the subdirectories of incremental
+		// backups, which are left behind empty.
+		if isDir, _ := path.Match(incBackupDayDirGlob, strings.TrimPrefix(f, prefix)); isDir {
+			continue
+		}
+		if f == fullManifest || f == encryptionInfo || seen[f] {
+			continue
+		}
+		seen[f] = true
+		files = append(files, f)
+	}
+	files = append(files, fullManifest)
+	for _, f := range fullFiles {
+		if f == encryptionInfo {
+			files = append(files, encryptionInfo)
+			break
+		}
+	}
+
+	for _, f := range files {
+		if err := collection.Delete(ctx, f); err != nil {
+			return 0, errors.Wrapf(err, "deleting %s", f)
+		}
+	}
+	return len(files), nil
+}
+
 // Metrics implements ScheduledJobExecutor interface
 func (e *scheduledBackupExecutor) Metrics() metric.Struct {
 	return &e.metrics
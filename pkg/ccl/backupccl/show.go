@@ -12,6 +12,7 @@ import (
 	"context"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -110,6 +111,8 @@ func showBackupPlanHook(
 		shower = backupShowerRanges
 	case tree.BackupFileDetails:
 		shower = backupShowerFiles
+	case tree.BackupTimestampDetails:
+		shower = backupShowerTimestamps(ctx, p.ExecCfg().Codec)
 	default:
 		shower = backupShowerDefault(ctx, p, backup.ShouldIncludeSchemas, opts)
 	}
@@ -501,6 +504,81 @@ var backupShowerFiles = backupShower{
 	},
 }
 
+// backupShowerTimestamps lists the windows of time during which each table in
+// the chain of backups can, or cannot, be restored AS OF SYSTEM TIME. The
+// start_time of a window is the first time in it, and its end_time the last.
+func backupShowerTimestamps(ctx context.Context, codec keys.SQLCodec) backupShower {
+	return backupShower{
+		header: colinfo.ResultColumns{
+			{Name: "database_name", Typ: types.String},
+			{Name: "parent_schema_name", Typ: types.String},
+			{Name: "object_name", Typ: types.String},
+			{Name: "start_time", Typ: types.Timestamp},
+			{Name: "end_time", Typ: types.Timestamp},
+			{Name: "restorable", Typ: types.Bool},
+			{Name: "reason", Typ: types.String},
+		},
+
+		fn: func(manifests []BackupManifest) ([]tree.Datums, error) {
+			// Name the tables, and their databases and schemas, after their latest
+			// descriptors in the chain of backups.
+			names := make(map[descpb.ID]string)
+			names[keys.PublicSchemaID] = sessiondata.PublicSchemaName
+			tables := make(map[descpb.ID]catalog.TableDescriptor)
+			addDesc := func(raw *descpb.Descriptor) {
+				if raw == nil {
+					return
+				}
+				desc := catalogkv.UnwrapDescriptorRaw(ctx, raw)
+				names[desc.GetID()] = desc.GetName()
+				if table, ok := desc.(catalog.TableDescriptor); ok {
+					tables[desc.GetID()] = table
+				}
+			}
+			for i := range manifests {
+				for _, rev := range manifests[i].DescriptorChanges {
+					addDesc(rev.Desc)
+				}
+				for j := range manifests[i].Descriptors {
+					addDesc(&manifests[i].Descriptors[j])
+				}
+			}
+			ids := make([]descpb.ID, 0, len(tables))
+			for id := range tables {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+			var rows []tree.Datums
+			for _, id := range ids {
+				table := tables[id]
+				for _, w := range tableRestorableWindows(codec, manifests, table) {
+					// The window excludes its start, so its first time at the
+					// precision of a TIMESTAMP is the nanosecond after it.
+					start, err := tree.MakeDTimestamp(timeutil.Unix(0, w.start.WallTime+1), time.Nanosecond)
+					if err != nil {
+						return nil, err
+					}
+					end, err := tree.MakeDTimestamp(timeutil.Unix(0, w.end.WallTime), time.Nanosecond)
+					if err != nil {
+						return nil, err
+					}
+					rows = append(rows, tree.Datums{
+						nullIfEmpty(names[table.GetParentID()]),
+						nullIfEmpty(names[table.GetParentSchemaID()]),
+						tree.NewDString(table.GetName()),
+						start,
+						end,
+						tree.MakeDBool(tree.DBool(w.restorable())),
+						nullIfEmpty(w.reason),
+					})
+				}
+			}
+			return rows, nil
+		},
+	}
+}
+
 // showBackupPlanHook implements PlanHookFn.
 func showBackupsInCollectionPlanHook(
 	ctx context.Context, backup *tree.ShowBackup, p sql.PlanHookState,
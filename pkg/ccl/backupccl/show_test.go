@@ -410,6 +410,46 @@ func TestShowBackups(t *testing.T) {
 	require.Equal(t, 3, len(b2))
 }
 
+func TestShowBackupTimestamps(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 1
+	_, _, sqlDB, _, cleanupFn := BackupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TABLE data.dropped (a INT)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, LocalFoo)
+	var beforeDrop, afterDrop string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeDrop)
+	sqlDB.Exec(t, `DROP TABLE data.dropped`)
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&afterDrop)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, LocalFoo)
+
+	sqlDB.CheckQueryResults(t, fmt.Sprintf(`
+SELECT database_name, parent_schema_name, object_name, restorable, reason
+FROM [SHOW BACKUP TIMESTAMPS '%s']
+ORDER BY object_name, start_time`, LocalFoo), [][]string{
+		{"data", "public", "bank", "true", "NULL"},
+		{"data", "public", "dropped", "true", "NULL"},
+		{"data", "public", "dropped", "false", "table dropped"},
+	})
+	// A window starts after the end of the previous one, as it excludes the
+	// time at which the previous one ends.
+	sqlDB.CheckQueryResults(t, fmt.Sprintf(`
+SELECT count(*) FROM [SHOW BACKUP TIMESTAMPS '%s'] AS w1, [SHOW BACKUP TIMESTAMPS '%s'] AS w2
+WHERE w1.object_name = w2.object_name AND w1.start_time < w2.start_time
+  AND w2.start_time <= w1.end_time`, LocalFoo, LocalFoo), [][]string{{"0"}})
+
+	sqlDB.Exec(t, `CREATE DATABASE restoredb`)
+	sqlDB.Exec(t, fmt.Sprintf(
+		`RESTORE data.dropped FROM $1 AS OF SYSTEM TIME %s WITH into_db = 'restoredb'`, beforeDrop,
+	), LocalFoo)
+	sqlDB.ExpectErr(t, `table "data.*dropped" does not exist`, fmt.Sprintf(
+		`RESTORE data.dropped FROM $1 AS OF SYSTEM TIME %s WITH into_db = 'restoredb'`, afterDrop,
+	), LocalFoo)
+}
+
 func TestShowBackupTenants(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
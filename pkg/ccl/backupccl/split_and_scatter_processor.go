@@ -226,6 +226,22 @@ func runSplitAndScatter(
 	scatterer splitAndScatterer,
 	doneScatterCh chan entryNode,
 ) error {
+	if spec.RouteLocally {
+		// The entries are only read, so they're all routed to the restore data
+		// processor on this node.
+		node, _ := flowCtx.NodeID.OptionalNodeID()
+		for _, importSpanChunk := range spec.Chunks {
+			for _, importSpan := range importSpanChunk.Entries {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case doneScatterCh <- entryNode{entry: importSpan, node: node}:
+				}
+			}
+		}
+		return nil
+	}
+
 	g := ctxgroup.WithContext(ctx)
 	db := flowCtx.Cfg.DB
 	kr, err := storageccl.MakeKeyRewriterFromRekeys(spec.Rekeys)
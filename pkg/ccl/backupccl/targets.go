@@ -39,6 +39,11 @@ type descriptorsMatched struct {
 
 	// Explicitly requested DBs (e.g. DATABASE a).
 	requestedDBs []catalog.DatabaseDescriptor
+
+	// Explicitly requested tables (e.g. TABLE a), in the order of the table
+	// patterns of the targets, with nil for the patterns which match all the
+	// tables of a database (e.g. a.*).
+	requestedTables []catalog.TableDescriptor
 }
 
 func (d descriptorsMatched) checkExpansions(coveredDBs []descpb.ID) error {
@@ -384,6 +389,7 @@ func descriptorsMatchingTargets(
 				alreadyRequestedTables[tableDesc.GetID()] = struct{}{}
 				ret.descs = append(ret.descs, tableDesc)
 			}
+			ret.requestedTables = append(ret.requestedTables, tableDesc)
 			// Since the table was directly requested, so is the schema. If the table
 			// is PUBLIC, we expect the schema to also be PUBLIC.
 			if err := maybeAddSchemaDesc(tableDesc.GetParentSchemaID(), true /* requirePublic */); err != nil {
@@ -420,6 +426,7 @@ func descriptorsMatchingTargets(
 				ret.expandedDB = append(ret.expandedDB, desc.GetID())
 				alreadyExpandedDBs[desc.GetID()] = struct{}{}
 			}
+			ret.requestedTables = append(ret.requestedTables, nil)
 
 		default:
 			return ret, errors.Errorf("unknown pattern %T: %+v", pattern, pattern)
@@ -863,6 +870,7 @@ func selectTargets(
 	p sql.PlanHookState,
 	backupManifests []BackupManifest,
 	targets tree.TargetList,
+	asNames tree.NameList,
 	descriptorCoverage tree.DescriptorCoverage,
 	asOf hlc.Timestamp,
 ) ([]catalog.Descriptor, []catalog.DatabaseDescriptor, []descpb.TenantInfo, error) {
@@ -899,5 +907,11 @@ func selectTargets(
 		}
 	}
 
+	if len(asNames) > 0 {
+		if err := renameRestoredTables(matched, targets, asNames); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
 	return matched.descs, matched.requestedDBs, nil, nil
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
@@ -229,8 +230,8 @@ func changefeedPlanHook(
 		//   and `format` if the user didn't specify them.
 		// - Then `getEncoder` is run to return any configuration errors.
 		// - Then the changefeed is opted in to `OptKeyInValue` for any cloud
-		//   storage sink. Kafka etc have a key and value field in each message but
-		//   cloud storage sinks don't have anywhere to put the key. So if the key
+		//   storage or webhook sink. Kafka etc have a key and value field in each
+		//   message but these sinks don't have anywhere to put the key. So if the key
 		//   is not in the value, then for DELETEs there is no way to recover which
 		//   key was deleted. We could make the user explicitly pass this option for
 		//   every cloud storage sink and error if they don't, but that seems
@@ -253,11 +254,19 @@ func changefeedPlanHook(
 		if details, err = validateDetails(details); err != nil {
 			return err
 		}
+		if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) ==
+			changefeedbase.OptFormatParquet &&
+			!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ParquetFormat) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s=%s requires all nodes to be upgraded to %s",
+				changefeedbase.OptFormat, changefeedbase.OptFormatParquet,
+				clusterversion.ByKey(clusterversion.ParquetFormat))
+		}
 
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
-		if isCloudStorageSink(parsedSink) {
+		if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
 
@@ -411,7 +420,9 @@ func changefeedPlanHook(
 func changefeedJobDescription(
 	p sql.PlanHookState, changefeed *tree.CreateChangefeed, sinkURI string, opts map[string]string,
 ) (string, error) {
-	cleanedSinkURI, err := cloudimpl.SanitizeExternalStorageURI(sinkURI, []string{changefeedbase.SinkParamSASLPassword})
+	cleanedSinkURI, err := cloudimpl.SanitizeExternalStorageURI(sinkURI, []string{
+		changefeedbase.SinkParamSASLPassword, changefeedbase.SinkParamClientKey,
+	})
 	if err != nil {
 		return "", err
 	}
@@ -421,6 +432,9 @@ func changefeedJobDescription(
 	}
 	for k, v := range opts {
 		opt := tree.KVOption{Key: tree.Name(k)}
+		if k == changefeedbase.OptWebhookAuthHeader {
+			v = `redacted`
+		}
 		if len(v) > 0 {
 			opt.Value = tree.NewDString(v)
 		}
@@ -503,7 +517,7 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.OptFormatParquet:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
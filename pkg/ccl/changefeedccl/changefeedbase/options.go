@@ -38,6 +38,10 @@ const (
 	OptSchemaChangeEvents       = `schema_change_events`
 	OptSchemaChangePolicy       = `schema_change_policy`
 	OptProtectDataFromGCOnPause = `protect_data_from_gc_on_pause`
+	OptWebhookAuthHeader        = `webhook_auth_header`
+	OptWebhookClientTimeout     = `webhook_client_timeout`
+	OptWebhookSinkConfig        = `webhook_sink_config`
+	OptParquetRowGroupSize      = `parquet_row_group_size`
 
 	// OptSchemaChangeEventClassColumnChange corresponds to all schema change
 	// events which add or remove any column.
@@ -73,8 +77,9 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `experimental_avro`
+	OptFormatJSON    FormatType = `json`
+	OptFormatAvro    FormatType = `experimental_avro`
+	OptFormatParquet FormatType = `parquet`
 
 	SinkParamCACert           = `ca_cert`
 	SinkParamClientCert       = `client_cert`
@@ -91,6 +96,7 @@ const (
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
 	SinkParamSASLPassword     = `sasl_password`
+	SinkSchemeWebhookHTTPS    = `webhook-https`
 )
 
 // ChangefeedOptionExpectValues is used to parse changefeed options using
@@ -110,4 +116,8 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptInitialScan:              sql.KVStringOptRequireNoValue,
 	OptNoInitialScan:            sql.KVStringOptRequireNoValue,
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
+	OptWebhookAuthHeader:        sql.KVStringOptRequireValue,
+	OptWebhookClientTimeout:     sql.KVStringOptRequireValue,
+	OptWebhookSinkConfig:        sql.KVStringOptRequireValue,
+	OptParquetRowGroupSize:      sql.KVStringOptRequireValue,
 }
@@ -0,0 +1,197 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// parquetEventTypeColumn is the name of the column holding the type of the
+	// change, either parquetEventTypeUpsert or parquetEventTypeDelete.
+	parquetEventTypeColumn = `__crdb__event_type`
+	// parquetUpdatedColumn is the name of the column holding the updated
+	// timestamp when the updated option is set.
+	parquetUpdatedColumn = `__crdb__updated`
+
+	parquetEventTypeUpsert = `upsert`
+	parquetEventTypeDelete = `delete`
+)
+
+// parquetEncoder encodes changefeed entries for the parquet format. Parquet is
+// a columnar file format, so rows can't be serialized on their own: values are
+// instead the public columns of the row in value encoding, preceded by the
+// type of the change, and the cloud storage sink decodes them and appends them
+// to the parquet file of their table. For deletes, only the primary key
+// columns are set. Keys and resolved timestamps are encoded as JSON.
+type parquetEncoder struct {
+	*jsonEncoder
+
+	valueAlloc rowenc.DatumAlloc
+	buf        []byte
+	scratch    []byte
+}
+
+var _ Encoder = &parquetEncoder{}
+
+func makeParquetEncoder(opts map[string]string) (*parquetEncoder, error) {
+	if _, ok := opts[changefeedbase.OptDiff]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+	if v, ok := opts[changefeedbase.OptParquetRowGroupSize]; ok {
+		if _, err := parseParquetRowGroupSize(v); err != nil {
+			return nil, err
+		}
+	}
+	e, err := makeJSONEncoder(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetEncoder{jsonEncoder: e}, nil
+}
+
+func parseParquetRowGroupSize(v string) (int64, error) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.Errorf(`invalid %s: %q, expected a positive integer`,
+			changefeedbase.OptParquetRowGroupSize, v)
+	}
+	return n, nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *parquetEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	eventType := parquetEventTypeUpsert
+	if row.deleted {
+		eventType = parquetEventTypeDelete
+	}
+	var err error
+	e.buf, err = rowenc.EncodeTableValue(
+		e.buf[:0], descpb.ColumnID(encoding.NoColumnID), tree.NewDString(eventType), e.scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKeyCols catalog.TableColSet
+	if row.deleted {
+		for _, colID := range row.tableDesc.GetPrimaryIndex().ColumnIDs {
+			primaryKeyCols.Add(colID)
+		}
+	}
+	columns := row.tableDesc.GetPublicColumns()
+	for i := range columns {
+		col := &columns[i]
+		datum := tree.DNull
+		if !row.deleted || primaryKeyCols.Contains(col.ID) {
+			if err := row.datums[i].EnsureDecoded(col.Type, &e.valueAlloc); err != nil {
+				return nil, err
+			}
+			datum = row.datums[i].Datum
+		}
+		e.buf, err = rowenc.EncodeTableValue(
+			e.buf, descpb.ColumnID(encoding.NoColumnID), datum, e.scratch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.buf, nil
+}
+
+// parquetFile accumulates the rows of a table version which the cloud storage
+// sink writes to a single parquet file.
+type parquetFile struct {
+	writer      *parquet.Writer
+	columnTypes []*types.T
+	withUpdated bool
+	datums      tree.Datums
+	alloc       rowenc.DatumAlloc
+}
+
+// newParquetFile returns a parquetFile that writes the rows of table to w.
+// The columns of the file are the public columns of table, followed by the
+// event type and, if withUpdated is true, the updated timestamp.
+func newParquetFile(
+	table catalog.TableDescriptor, w io.Writer, withUpdated bool, opts ...parquet.Option,
+) (*parquetFile, error) {
+	columns := table.GetPublicColumns()
+	names := make([]string, 0, len(columns)+2)
+	typs := make([]*types.T, 0, len(columns)+2)
+	for i := range columns {
+		if strings.HasPrefix(columns[i].Name, `__crdb__`) {
+			return nil, errors.Errorf(`column %s of table %s conflicts with the parquet metadata columns`,
+				columns[i].Name, table.GetName())
+		}
+		names = append(names, columns[i].Name)
+		typs = append(typs, columns[i].Type)
+	}
+	columnTypes := typs
+	names = append(names, parquetEventTypeColumn)
+	typs = append(typs, types.String)
+	if withUpdated {
+		names = append(names, parquetUpdatedColumn)
+		typs = append(typs, types.String)
+	}
+
+	sch, err := parquet.NewSchema(names, typs)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := parquet.NewWriter(sch, w, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetFile{
+		writer:      writer,
+		columnTypes: columnTypes,
+		withUpdated: withUpdated,
+		datums:      make(tree.Datums, len(typs)),
+	}, nil
+}
+
+// addRow decodes a value produced by parquetEncoder and adds it to the file.
+func (f *parquetFile) addRow(value []byte, updated hlc.Timestamp) error {
+	eventType, value, err := rowenc.DecodeTableValue(&f.alloc, types.String, value)
+	if err != nil {
+		return err
+	}
+	for i, typ := range f.columnTypes {
+		if f.datums[i], value, err = rowenc.DecodeTableValue(&f.alloc, typ, value); err != nil {
+			return err
+		}
+	}
+	if len(value) != 0 {
+		return errors.AssertionFailedf(`%d unexpected trailing bytes in parquet value`, len(value))
+	}
+	n := len(f.columnTypes)
+	f.datums[n] = eventType
+	if f.withUpdated {
+		f.datums[n+1] = tree.NewDString(updated.AsOfSystemTime())
+	}
+	return f.writer.AddRow(f.datums)
+}
+
+// close writes the footer of the file.
+func (f *parquetFile) close() error {
+	return f.writer.Close()
+}
@@ -82,6 +82,14 @@ func getSink(
 	}
 	q := u.Query()
 
+	// Parquet files can only be assembled by the cloud storage sink, see
+	// parquetEncoder.
+	if changefeedbase.FormatType(opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatParquet &&
+		!isCloudStorageSink(u) {
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+
 	// Use a function here to delay creation of the sink until after we've done
 	// all the parameter verification.
 	var makeSink func() (Sink, error)
@@ -200,6 +208,42 @@ func getSink(
 				opts, timestampOracle, makeExternalStorageFromURI, user,
 			)
 		}
+	case isWebhookSink(u):
+		sinkOpts, err := makeWebhookSinkOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		if tlsVerifyBool := q.Get(changefeedbase.SinkParamSkipTLSVerify); tlsVerifyBool != `` {
+			if sinkOpts.tlsSkipVerify, err = strconv.ParseBool(tlsVerifyBool); err != nil {
+				return nil, errors.Errorf(`param %s must be a bool: %s`, changefeedbase.SinkParamSkipTLSVerify, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamSkipTLSVerify)
+		if caCertHex := q.Get(changefeedbase.SinkParamCACert); caCertHex != `` {
+			if sinkOpts.caCert, err = base64.StdEncoding.DecodeString(caCertHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamCACert, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamCACert)
+		if clientCertHex := q.Get(changefeedbase.SinkParamClientCert); clientCertHex != `` {
+			if sinkOpts.clientCert, err = base64.StdEncoding.DecodeString(clientCertHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamClientCert, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamClientCert)
+		if clientKeyHex := q.Get(changefeedbase.SinkParamClientKey); clientKeyHex != `` {
+			if sinkOpts.clientKey, err = base64.StdEncoding.DecodeString(clientKeyHex); err != nil {
+				return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, changefeedbase.SinkParamClientKey, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamClientKey)
+
+		// The remaining query parameters belong to the endpoint.
+		u.RawQuery = q.Encode()
+		q = url.Values{}
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(u, opts, sinkOpts)
+		}
 	case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
 	"github.com/cockroachdb/errors"
 	"github.com/google/btree"
 )
@@ -57,6 +58,9 @@ type cloudStorageSinkFile struct {
 	codec   io.WriteCloser
 	rawSize int
 	buf     bytes.Buffer
+	// parquet is set for files in the parquet format, which are written
+	// through it rather than a row at a time.
+	parquet *parquetFile
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -277,6 +281,13 @@ type cloudStorageSink struct {
 
 	compression string
 
+	// parquet is true if files are written in the parquet format, in which case
+	// compression applies to the pages of the files rather than the files as a
+	// whole.
+	parquet            bool
+	parquetOpts        []parquet.Option
+	parquetWithUpdated bool
+
 	es cloud.ExternalStorage
 
 	// These are fields to track information needed to output files based on the naming
@@ -295,6 +306,7 @@ type cloudStorageSink struct {
 }
 
 const sinkCompressionGzip = "gzip"
+const sinkCompressionSnappy = "snappy"
 
 var cloudStorageSinkIDAtomic int64
 
@@ -339,6 +351,17 @@ func makeCloudStorageSink(
 			_, err := w.Write([]byte{'\n'})
 			return err
 		}
+	case changefeedbase.OptFormatParquet:
+		s.ext = `.parquet`
+		s.parquet = true
+		_, s.parquetWithUpdated = opts[changefeedbase.OptUpdatedTimestamps]
+		if v, ok := opts[changefeedbase.OptParquetRowGroupSize]; ok {
+			rowGroupSize, err := parseParquetRowGroupSize(v)
+			if err != nil {
+				return nil, err
+			}
+			s.parquetOpts = append(s.parquetOpts, parquet.WithMaxRowGroupLength(rowGroupSize))
+		}
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
@@ -356,10 +379,15 @@ func makeCloudStorageSink(
 	}
 
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
-		if strings.EqualFold(codec, "gzip") {
+		switch {
+		case s.parquet && strings.EqualFold(codec, sinkCompressionGzip):
+			s.parquetOpts = append(s.parquetOpts, parquet.WithCompressionCodec(parquet.CompressionGzip))
+		case s.parquet && strings.EqualFold(codec, sinkCompressionSnappy):
+			s.parquetOpts = append(s.parquetOpts, parquet.WithCompressionCodec(parquet.CompressionSnappy))
+		case strings.EqualFold(codec, sinkCompressionGzip):
 			s.compression = sinkCompressionGzip
 			s.ext = s.ext + ".gz"
-		} else {
+		default:
 			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
 		}
 	}
@@ -401,14 +429,32 @@ func (s *cloudStorageSink) EmitRow(
 	file := s.getOrCreateFile(table.GetName(), table.GetVersion())
 
 	// TODO(dan): Memory monitoring for this
-	if _, err := file.Write(value); err != nil {
-		return err
-	}
-	if err := s.recordDelimFn(file); err != nil {
-		return err
+	var fileSize int64
+	if s.parquet {
+		if file.parquet == nil {
+			var err error
+			if file.parquet, err = newParquetFile(table, &file.buf, s.parquetWithUpdated, s.parquetOpts...); err != nil {
+				return err
+			}
+		}
+		if err := file.parquet.addRow(value, updated); err != nil {
+			return err
+		}
+		// Rows are buffered by the parquet writer until a row group is full, so
+		// estimate the size of the file from the encoded rows instead.
+		file.rawSize += len(value)
+		fileSize = int64(file.rawSize)
+	} else {
+		if _, err := file.Write(value); err != nil {
+			return err
+		}
+		if err := s.recordDelimFn(file); err != nil {
+			return err
+		}
+		fileSize = int64(file.buf.Len())
 	}
 
-	if int64(file.buf.Len()) > s.targetMaxFileSize {
+	if fileSize > s.targetMaxFileSize {
 		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
 			return err
 		}
@@ -507,6 +553,13 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return nil
 	}
 
+	// Parquet files have a footer, which is written when the file is closed.
+	if file.parquet != nil {
+		if err := file.parquet.close(); err != nil {
+			return err
+		}
+	}
+
 	// If the file is written via compression codec, close the codec to ensure it
 	// has flushed to the underlying buffer.
 	if file.codec != nil {
@@ -29,6 +29,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -536,4 +539,76 @@ func TestCloudStorageSink(t *testing.T) {
 			"w1\n",
 		}, slurpDir(t, dir))
 	})
+
+	t.Run(`parquet`, func(t *testing.T) {
+		t1 := tabledesc.NewImmutable(descpb.TableDescriptor{
+			Name: `t1`,
+			Columns: []descpb.ColumnDescriptor{
+				{ID: 1, Name: `a`, Type: types.Int},
+				{ID: 2, Name: `b`, Type: types.String, Nullable: true},
+			},
+			PrimaryIndex: descpb.IndexDescriptor{ColumnIDs: []descpb.ColumnID{1}},
+		})
+		row := func(a int, b string) rowenc.EncDatumRow {
+			return rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(a))),
+				rowenc.DatumToEncDatum(types.String, tree.NewDString(b)),
+			}
+		}
+
+		for _, compression := range []string{"", "gzip", "snappy"} {
+			t.Run("compress="+compression, func(t *testing.T) {
+				parquetOpts := map[string]string{
+					changefeedbase.OptFormat:              string(changefeedbase.OptFormatParquet),
+					changefeedbase.OptEnvelope:            string(changefeedbase.OptEnvelopeWrapped),
+					changefeedbase.OptKeyInValue:          ``,
+					changefeedbase.OptUpdatedTimestamps:   ``,
+					changefeedbase.OptCompression:         compression,
+					changefeedbase.OptParquetRowGroupSize: `1`,
+				}
+				pe, err := makeParquetEncoder(parquetOpts)
+				require.NoError(t, err)
+
+				testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+				sf := span.MakeFrontier(testSpan)
+				timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+				dir := `parquet` + compression
+				s, err := makeCloudStorageSink(
+					ctx, `nodelocal://0/`+dir, 1, unlimitedFileSize,
+					settings, parquetOpts, timestampOracle, externalStorageFromURI, user,
+				)
+				require.NoError(t, err)
+
+				for _, r := range []encodeRow{
+					{datums: row(1, `x`), updated: ts(1), tableDesc: t1},
+					{datums: row(2, `y`), updated: ts(2), tableDesc: t1},
+					{datums: row(1, `x`), updated: ts(3), tableDesc: t1, deleted: true},
+				} {
+					value, err := pe.EncodeValue(ctx, r)
+					require.NoError(t, err)
+					require.NoError(t, s.EmitRow(ctx, t1, noKey, value, r.updated))
+				}
+				require.NoError(t, s.Flush(ctx))
+
+				files := slurpDir(t, dir)
+				require.Len(t, files, 1)
+				file := files[0]
+				require.True(t, strings.HasPrefix(file, "PAR1"))
+				require.True(t, strings.HasSuffix(file, "PAR1"))
+				// The updated timestamps and the event types end up as strings
+				// in the file.
+				if compression == "" {
+					require.Contains(t, file, parquetEventTypeDelete)
+					require.Contains(t, file, ts(3).AsOfSystemTime())
+				}
+			})
+		}
+
+		_, err := makeParquetEncoder(map[string]string{
+			changefeedbase.OptFormat:   string(changefeedbase.OptFormatParquet),
+			changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptDiff:     ``,
+		})
+		require.EqualError(t, err, `diff is not supported with format=parquet`)
+	})
 }
@@ -0,0 +1,502 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	gojson "encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	applicationTypeJSON = `application/json`
+	authorizationHeader = `Authorization`
+
+	defaultWebhookClientTimeout = 3 * time.Second
+	defaultWebhookRetryMax      = 3
+	defaultWebhookRetryBackoff  = 500 * time.Millisecond
+)
+
+func isWebhookSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS
+}
+
+// webhookSinkConfig is the user-facing configuration of a webhook sink. It is
+// specified as JSON in the `webhook_sink_config` changefeed option. Durations
+// are strings parsed with time.ParseDuration.
+type webhookSinkConfig struct {
+	Flush struct {
+		// Messages is the number of rows batched into a single request. A
+		// value of 0 means every row is sent in its own request.
+		Messages int `json:",omitempty"`
+		// Frequency is the maximum amount of time a row may be buffered
+		// before the batch holding it is sent, even if it is not full.
+		Frequency string `json:",omitempty"`
+	} `json:",omitempty"`
+	Retry struct {
+		// Max is the maximum number of retries of a request that failed. A
+		// value of 0 disables retries; it is a pointer so that it can be told
+		// apart from an unset value, which keeps the default.
+		Max *int `json:",omitempty"`
+		// Backoff is the initial backoff between retries.
+		Backoff string `json:",omitempty"`
+	} `json:",omitempty"`
+	// Headers are added to every request sent to the sink.
+	Headers map[string]string `json:",omitempty"`
+}
+
+// webhookSinkOptions is the parsed form of webhookSinkConfig, along with the
+// other options and sink parameters which affect the webhook sink.
+type webhookSinkOptions struct {
+	batchSize     int
+	flushInterval time.Duration
+	retryOpts     retry.Options
+	clientTimeout time.Duration
+	headers       http.Header
+
+	caCert        []byte
+	clientCert    []byte
+	clientKey     []byte
+	tlsSkipVerify bool
+}
+
+func makeWebhookSinkOptions(opts map[string]string) (webhookSinkOptions, error) {
+	o := webhookSinkOptions{
+		batchSize:     1,
+		clientTimeout: defaultWebhookClientTimeout,
+		headers:       make(http.Header),
+		retryOpts: retry.Options{
+			InitialBackoff: defaultWebhookRetryBackoff,
+			MaxRetries:     defaultWebhookRetryMax,
+		},
+	}
+
+	if timeout, ok := opts[changefeedbase.OptWebhookClientTimeout]; ok && timeout != `` {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return o, errors.Wrapf(err, `parsing %s`, changefeedbase.OptWebhookClientTimeout)
+		}
+		if d <= 0 {
+			return o, errors.Errorf(`%s must be a positive duration: %s`,
+				changefeedbase.OptWebhookClientTimeout, timeout)
+		}
+		o.clientTimeout = d
+	}
+
+	if auth := opts[changefeedbase.OptWebhookAuthHeader]; auth != `` {
+		o.headers.Set(authorizationHeader, auth)
+	}
+
+	configJSON, ok := opts[changefeedbase.OptWebhookSinkConfig]
+	if !ok || configJSON == `` {
+		return o, nil
+	}
+	var cfg webhookSinkConfig
+	if err := gojson.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		return o, errors.Wrapf(err, `parsing %s`, changefeedbase.OptWebhookSinkConfig)
+	}
+	if cfg.Flush.Messages < 0 {
+		return o, errors.Errorf(`%s: Flush.Messages must be non-negative: %d`,
+			changefeedbase.OptWebhookSinkConfig, cfg.Flush.Messages)
+	}
+	if cfg.Flush.Messages > 0 {
+		o.batchSize = cfg.Flush.Messages
+	}
+	if cfg.Flush.Frequency != `` {
+		d, err := time.ParseDuration(cfg.Flush.Frequency)
+		if err != nil {
+			return o, errors.Wrapf(err, `%s: parsing Flush.Frequency`, changefeedbase.OptWebhookSinkConfig)
+		}
+		if d < 0 {
+			return o, errors.Errorf(`%s: negative durations are not accepted: Flush.Frequency='%s'`,
+				changefeedbase.OptWebhookSinkConfig, cfg.Flush.Frequency)
+		}
+		o.flushInterval = d
+	}
+	if cfg.Retry.Max != nil {
+		if *cfg.Retry.Max < 0 {
+			return o, errors.Errorf(`%s: Retry.Max must be non-negative: %d`,
+				changefeedbase.OptWebhookSinkConfig, *cfg.Retry.Max)
+		}
+		o.retryOpts.MaxRetries = *cfg.Retry.Max
+	}
+	if cfg.Retry.Backoff != `` {
+		d, err := time.ParseDuration(cfg.Retry.Backoff)
+		if err != nil {
+			return o, errors.Wrapf(err, `%s: parsing Retry.Backoff`, changefeedbase.OptWebhookSinkConfig)
+		}
+		if d <= 0 {
+			return o, errors.Errorf(`%s: Retry.Backoff must be a positive duration: %s`,
+				changefeedbase.OptWebhookSinkConfig, cfg.Retry.Backoff)
+		}
+		o.retryOpts.InitialBackoff = d
+	}
+	for k, v := range cfg.Headers {
+		// The Authorization header has a dedicated option so that it can be
+		// redacted from the job description.
+		if http.CanonicalHeaderKey(k) == authorizationHeader {
+			return o, errors.Errorf(`%s: use the %s option to set the %s header`,
+				changefeedbase.OptWebhookSinkConfig, changefeedbase.OptWebhookAuthHeader, authorizationHeader)
+		}
+		o.headers.Set(k, v)
+	}
+	return o, nil
+}
+
+// webhookSinkPayload is the body of every request which carries rows. It
+// mirrors the envelope used by other HTTP-based change data capture tools.
+type webhookSinkPayload struct {
+	Payload []gojson.RawMessage `json:"payload"`
+	Length  int                 `json:"length"`
+}
+
+// webhookMessage is a unit of work handed from the changefeed goroutine to the
+// worker goroutine of the webhook sink. Exactly one of the fields is set,
+// except for flush messages, which have none set.
+type webhookMessage struct {
+	row      []byte
+	resolved []byte
+}
+
+// webhookSink emits to an HTTPS endpoint using POST requests with JSON
+// bodies. Rows are batched and sent asynchronously by a single worker
+// goroutine, which guarantees that requests are sent, and acknowledged, in the
+// order in which they were emitted. In particular, a resolved timestamp is
+// only sent after every row emitted before it has been acknowledged.
+//
+// Like kafkaSink, it is not concurrency-safe; all calls to Emit and Flush
+// should be from the same goroutine.
+type webhookSink struct {
+	url    string
+	opts   webhookSinkOptions
+	client *httputil.Client
+
+	msgCh        chan webhookMessage
+	stopWorkerCh chan struct{}
+	worker       sync.WaitGroup
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+func makeWebhookSink(u *url.URL, opts map[string]string, sinkOpts webhookSinkOptions) (Sink, error) {
+	if u.Scheme != changefeedbase.SinkSchemeWebhookHTTPS {
+		return nil, errors.Errorf(`this sink requires the %s scheme`, changefeedbase.SinkSchemeWebhookHTTPS)
+	}
+	if u.Host == `` {
+		return nil, errors.Errorf(`webhook sink requires a host`)
+	}
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case ``, changefeedbase.OptFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case ``, changefeedbase.OptEnvelopeWrapped:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+	}
+
+	// Swap the changefeed prefix for the plain https one.
+	sinkURL := *u
+	sinkURL.Scheme = `https`
+	client, err := makeWebhookClient(sinkOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &webhookSink{
+		url:    sinkURL.String(),
+		opts:   sinkOpts,
+		client: client,
+	}
+	sink.start()
+	return sink, nil
+}
+
+func makeWebhookClient(opts webhookSinkOptions) (*httputil.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.tlsSkipVerify,
+	}
+	if opts.caCert != nil {
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil || caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(opts.caCert) {
+			return nil, errors.Errorf(`failed to parse certificates in %s`, changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if opts.clientCert != nil {
+		if opts.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`,
+				changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(opts.clientCert, opts.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if opts.clientKey != nil {
+		return nil, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+
+	return &httputil.Client{Client: &http.Client{
+		Timeout: opts.clientTimeout,
+		Transport: &http.Transport{
+			DialContext:     (&net.Dialer{Timeout: opts.clientTimeout}).DialContext,
+			TLSClientConfig: tlsConfig,
+		},
+	}}, nil
+}
+
+func (s *webhookSink) start() {
+	s.msgCh = make(chan webhookMessage)
+	s.stopWorkerCh = make(chan struct{})
+	s.worker.Add(1)
+	go s.workerLoop()
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	close(s.stopWorkerCh)
+	s.worker.Wait()
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, _ catalog.TableDescriptor, _, value []byte, _ hlc.Timestamp,
+) error {
+	return s.enqueue(ctx, webhookMessage{row: value})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	// The webhook sink has no notion of topics, so the resolved timestamp is
+	// sent once rather than once per topic.
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, noTopic, resolved)
+	if err != nil {
+		return err
+	}
+	// The encoder reuses its buffer, so the payload has to be copied before it
+	// is handed off to the worker.
+	return s.enqueue(ctx, webhookMessage{resolved: append([]byte(nil), payload...)})
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	// Force out any partially filled batch.
+	if err := s.enqueue(ctx, webhookMessage{}); err != nil {
+		return err
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+func (s *webhookSink) enqueue(ctx context.Context, msg webhookMessage) error {
+	s.mu.Lock()
+	s.mu.inflight++
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.finishInflight(1, nil)
+		return ctx.Err()
+	case <-s.stopWorkerCh:
+		s.finishInflight(1, nil)
+		return errors.New(`cannot emit to a closed webhook sink`)
+	case s.msgCh <- msg:
+	}
+	return nil
+}
+
+// finishInflight marks n messages as no longer inflight, records err (if it is
+// the first error since the last flush) and wakes up a pending Flush if
+// nothing remains inflight.
+func (s *webhookSink) finishInflight(n int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil && s.mu.flushErr == nil {
+		s.mu.flushErr = err
+	}
+	s.mu.inflight -= n
+	if s.mu.inflight == 0 && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
+	}
+}
+
+func (s *webhookSink) workerLoop() {
+	defer s.worker.Done()
+
+	// Cancel any request in flight when the sink is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stopWorkerCh
+		cancel()
+	}()
+
+	var batch []gojson.RawMessage
+	sendBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := s.sendBatch(ctx, batch)
+		s.finishInflight(int64(len(batch)), err)
+		batch = batch[:0]
+	}
+
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-timer.C:
+			timer.Read = true
+			sendBatch()
+		case msg := <-s.msgCh:
+			switch {
+			case msg.row != nil:
+				if len(batch) == 0 && s.opts.flushInterval > 0 {
+					timer.Reset(s.opts.flushInterval)
+				}
+				batch = append(batch, msg.row)
+				if len(batch) >= s.opts.batchSize {
+					sendBatch()
+				}
+			case msg.resolved != nil:
+				// Every row emitted before a resolved timestamp must be
+				// delivered before it.
+				sendBatch()
+				err := s.sendWithRetries(ctx, msg.resolved)
+				s.finishInflight(1, err)
+			default:
+				sendBatch()
+				s.finishInflight(1, nil)
+			}
+		}
+	}
+}
+
+func (s *webhookSink) sendBatch(ctx context.Context, batch []gojson.RawMessage) error {
+	body, err := gojson.Marshal(webhookSinkPayload{Payload: batch, Length: len(batch)})
+	if err != nil {
+		return err
+	}
+	return s.sendWithRetries(ctx, body)
+}
+
+func (s *webhookSink) sendWithRetries(ctx context.Context, body []byte) error {
+	send := func() error {
+		err := s.send(ctx, body)
+		if err != nil && log.V(1) {
+			log.Infof(ctx, "webhook sink request failed: %v", err)
+		}
+		return err
+	}
+	// A single attempt leaves retry.Options.MaxRetries at 0, which retries
+	// forever rather than not at all.
+	if s.opts.retryOpts.MaxRetries == 0 {
+		return send()
+	}
+	return retry.WithMaxAttempts(ctx, s.opts.retryOpts, s.opts.retryOpts.MaxRetries+1, send)
+}
+
+func (s *webhookSink) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.opts.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set(`Content-Type`, applicationTypeJSON)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		resBody, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<10))
+		if err != nil {
+			return errors.Wrapf(err, `failed to read body for HTTP response with status: %d`, res.StatusCode)
+		}
+		return errors.Errorf(`%s: %s`, res.Status, strings.TrimSpace(string(resBody)))
+	}
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	return nil
+}
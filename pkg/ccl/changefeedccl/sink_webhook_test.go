@@ -0,0 +1,238 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookTestServer records the bodies of the requests it receives. It fails
+// the first failures requests it receives with a 500.
+type webhookTestServer struct {
+	*httptest.Server
+
+	mu struct {
+		syncutil.Mutex
+		failures int
+		bodies   []string
+		headers  []http.Header
+	}
+}
+
+func makeWebhookTestServer(t *testing.T, failures int) *webhookTestServer {
+	s := &webhookTestServer{}
+	s.mu.failures = failures
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.failures > 0 {
+			s.mu.failures--
+			http.Error(w, `try again`, http.StatusInternalServerError)
+			return
+		}
+		s.mu.bodies = append(s.mu.bodies, string(body))
+		s.mu.headers = append(s.mu.headers, r.Header.Clone())
+	}))
+	return s
+}
+
+func (s *webhookTestServer) sinkURL(t *testing.T) *url.URL {
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	u.Scheme = changefeedbase.SinkSchemeWebhookHTTPS
+	return u
+}
+
+func (s *webhookTestServer) bodies() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mu.bodies...)
+}
+
+func webhookPayload(t *testing.T, rows ...string) string {
+	p := webhookSinkPayload{Length: len(rows)}
+	for _, r := range rows {
+		p.Payload = append(p.Payload, gojson.RawMessage(r))
+	}
+	b, err := gojson.Marshal(p)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	table := tabledesc.NewImmutable(descpb.TableDescriptor{Name: `t`})
+
+	makeSink := func(t *testing.T, srv *webhookTestServer, opts map[string]string) Sink {
+		sinkOpts, err := makeWebhookSinkOptions(opts)
+		require.NoError(t, err)
+		sinkOpts.tlsSkipVerify = true
+		sink, err := makeWebhookSink(srv.sinkURL(t), opts, sinkOpts)
+		require.NoError(t, err)
+		return sink
+	}
+
+	t.Run("batching", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 0 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookSinkConfig: `{"Flush": {"Messages": 2}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// No inflight
+		require.NoError(t, sink.Flush(ctx))
+
+		for _, row := range []string{`{"a":1}`, `{"a":2}`, `{"a":3}`} {
+			require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(row), zeroTS))
+		}
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{
+			webhookPayload(t, `{"a":1}`, `{"a":2}`),
+			webhookPayload(t, `{"a":3}`),
+		}, srv.bodies())
+	})
+
+	t.Run("resolved ordering", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 0 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookSinkConfig: `{"Flush": {"Messages": 10, "Frequency": "1h"}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// The partially filled batch has to be sent before the resolved
+		// timestamp even though neither the size nor the frequency of the
+		// batch has been reached.
+		var e testEncoder
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":1}`), zeroTS))
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, e, hlc.Timestamp{WallTime: 1}))
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":2}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{
+			webhookPayload(t, `{"a":1}`),
+			`0.000000001,0`,
+			webhookPayload(t, `{"a":2}`),
+		}, srv.bodies())
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 2 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookSinkConfig: `{"Retry": {"Max": 2, "Backoff": "1ms"}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":1}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{webhookPayload(t, `{"a":1}`)}, srv.bodies())
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 3 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookSinkConfig: `{"Retry": {"Max": 2, "Backoff": "1ms"}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":1}`), zeroTS))
+		require.Regexp(t, `500 Internal Server Error: try again`, sink.Flush(ctx))
+		require.Empty(t, srv.bodies())
+
+		// Check simple success again after error
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":2}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{webhookPayload(t, `{"a":2}`)}, srv.bodies())
+	})
+
+	t.Run("retries disabled", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 1 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookSinkConfig: `{"Retry": {"Max": 0, "Backoff": "1ms"}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":1}`), zeroTS))
+		require.Regexp(t, `500 Internal Server Error: try again`, sink.Flush(ctx))
+		require.Empty(t, srv.bodies())
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		srv := makeWebhookTestServer(t, 0 /* failures */)
+		defer srv.Close()
+		sink := makeSink(t, srv, map[string]string{
+			changefeedbase.OptWebhookAuthHeader: `Bearer s3cr3t`,
+			changefeedbase.OptWebhookSinkConfig: `{"Headers": {"X-Custom": "foo"}}`,
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, nil, []byte(`{"a":1}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Len(t, srv.mu.headers, 1)
+		require.Equal(t, `Bearer s3cr3t`, srv.mu.headers[0].Get(`Authorization`))
+		require.Equal(t, `foo`, srv.mu.headers[0].Get(`X-Custom`))
+		require.Equal(t, `application/json`, srv.mu.headers[0].Get(`Content-Type`))
+	})
+}
+
+func TestWebhookSinkOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		config string
+		err    string
+	}{
+		{config: `{"Flush": {"Messages": 5, "Frequency": "1s"}}`},
+		{config: `{"Retry": {"Max": 5, "Backoff": "1s"}}`},
+		{config: `{"Retry": {"Max": 0}}`},
+		{config: `{"Retry": {"Max": -1}}`, err: `Retry.Max must be non-negative`},
+		{config: `{"Flush": {"Messages": -1}}`, err: `Flush.Messages must be non-negative`},
+		{config: `{"Flush": {"Frequency": "bad"}}`, err: `parsing Flush.Frequency`},
+		{config: `{"Retry": {"Backoff": "0s"}}`, err: `Retry.Backoff must be a positive duration`},
+		{config: `{"Headers": {"authorization": "x"}}`, err: `use the webhook_auth_header option`},
+		{config: `not json`, err: `parsing webhook_sink_config`},
+	} {
+		t.Run(tc.config, func(t *testing.T) {
+			_, err := makeWebhookSinkOptions(map[string]string{
+				changefeedbase.OptWebhookSinkConfig: tc.config,
+			})
+			if tc.err == `` {
+				require.NoError(t, err)
+			} else {
+				require.Regexp(t, tc.err, err)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
 	"github.com/cockroachdb/cockroach/pkg/cli"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -44,6 +45,7 @@ const (
 
 var encryptionStatusOpts struct {
 	activeStoreIDOnly bool
+	keyUsage          bool
 }
 
 func init() {
@@ -56,7 +58,10 @@ Encryption keys must be specified in the '--enterprise-encryption' flag.
 
 Displays all store and data keys as well as files encrypted with each.
 Specifying --active-store-key-id-only prints the key ID of the active store key
-and exits.
+and exits. Specifying --key-usage prints the number and size of the files
+encrypted with each key that still protects files, and exits; keys other than
+the active ones are no longer listed once all files encrypted with them have
+been rewritten.
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: cli.MaybeDecorateGRPCError(runEncryptionStatus),
@@ -88,6 +93,8 @@ AES128_CTR:be235...   # AES-128 encryption with store key ID
 	// And other flags.
 	f.BoolVar(&encryptionStatusOpts.activeStoreIDOnly, "active-store-key-id-only", false,
 		"print active store key ID and exit")
+	f.BoolVar(&encryptionStatusOpts.keyUsage, "key-usage", false,
+		"print the number and size of the files encrypted with each key and exit")
 
 	// Add encryption flag to all OSS debug commands that want it.
 	for _, cmd := range cli.DebugCmdsForRocksDB {
@@ -182,6 +189,29 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if encryptionStatusOpts.keyUsage {
+		envStats, err := db.GetEnvStats()
+		if err != nil {
+			return err
+		}
+		for _, u := range envStats.DataKeyUsage {
+			var kind string
+			switch {
+			case u.KeyID == keyRegistry.ActiveDataKeyId:
+				kind = "active data key"
+			case u.KeyID == keyRegistry.ActiveStoreKeyId:
+				kind = "active store key"
+			case keyRegistry.DataKeys[u.KeyID] != nil:
+				kind = "retired data key"
+			default:
+				kind = "retired store key"
+			}
+			fmt.Printf("%s (%s): %d files, %s\n", u.KeyID, kind, u.Files,
+				humanizeutil.IBytes(int64(u.Bytes)))
+		}
+		return nil
+	}
+
 	// Build a map of 'key ID' -> list of files
 	fileKeyMap := make(map[string][]string)
 
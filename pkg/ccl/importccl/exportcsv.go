@@ -131,6 +131,9 @@ func newCSVWriterProcessor(
 	input execinfra.RowSource,
 	output execinfra.RowReceiver,
 ) (execinfra.Processor, error) {
+	if spec.Format == execinfrapb.ExportFormat_Parquet {
+		return newParquetWriterProcessor(flowCtx, processorID, spec, input, output)
+	}
 	c := &csvWriter{
 		flowCtx:     flowCtx,
 		processorID: processorID,
@@ -0,0 +1,261 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
+
+// parquetExporter writes rows to an in-memory parquet file. Unlike CSV, the
+// compression codec applies to the pages of the file rather than to the file
+// as a whole, so the file name is not suffixed.
+type parquetExporter struct {
+	buf    *bytes.Buffer
+	schema *parquet.SchemaDefinition
+	opts   []parquet.Option
+	writer *parquet.Writer
+}
+
+func newParquetExporter(
+	sp execinfrapb.CSVWriterSpec, typs []*types.T,
+) (*parquetExporter, error) {
+	if len(sp.ColumnNames) != len(typs) {
+		return nil, errors.AssertionFailedf(
+			"expected %d column names, got %d", len(typs), len(sp.ColumnNames))
+	}
+	schema, err := parquet.NewSchema(sp.ColumnNames, typs)
+	if err != nil {
+		return nil, err
+	}
+	var opts []parquet.Option
+	switch sp.CompressionCodec {
+	case execinfrapb.FileCompression_Gzip:
+		opts = append(opts, parquet.WithCompressionCodec(parquet.CompressionGzip))
+	case execinfrapb.FileCompression_Snappy:
+		opts = append(opts, parquet.WithCompressionCodec(parquet.CompressionSnappy))
+	}
+	if sp.RowGroupSize > 0 {
+		opts = append(opts, parquet.WithMaxRowGroupLength(sp.RowGroupSize))
+	}
+	return &parquetExporter{
+		buf:    bytes.NewBuffer([]byte{}),
+		schema: schema,
+		opts:   opts,
+	}, nil
+}
+
+// Reset starts a new file.
+func (e *parquetExporter) Reset() error {
+	e.buf.Reset()
+	w, err := parquet.NewWriter(e.schema, e.buf, e.opts...)
+	if err != nil {
+		return err
+	}
+	e.writer = w
+	return nil
+}
+
+// Write appends a row to the file.
+func (e *parquetExporter) Write(row tree.Datums) error {
+	return e.writer.AddRow(row)
+}
+
+// Close writes the footer of the file.
+func (e *parquetExporter) Close() error {
+	return e.writer.Close()
+}
+
+// Bytes returns the contents of the file.
+func (e *parquetExporter) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Len returns the length of the file.
+func (e *parquetExporter) Len() int {
+	return e.buf.Len()
+}
+
+func (e *parquetExporter) FileName(spec execinfrapb.CSVWriterSpec, part string) string {
+	pattern := exportParquetFilePatternDefault
+	if spec.NamePattern != "" {
+		pattern = spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+func newParquetWriterProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.CSVWriterSpec,
+	input execinfra.RowSource,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	c := &parquetWriter{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		output:      output,
+	}
+	semaCtx := tree.MakeSemaContext()
+	if err := c.out.Init(&execinfrapb.PostProcessSpec{}, c.OutputTypes(), &semaCtx, flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type parquetWriter struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.CSVWriterSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+}
+
+var _ execinfra.Processor = &parquetWriter{}
+
+func (sp *parquetWriter) OutputTypes() []*types.T {
+	res := make([]*types.T, len(colinfo.ExportColumns))
+	for i := range res {
+		res[i] = colinfo.ExportColumns[i].Typ
+	}
+	return res
+}
+
+func (sp *parquetWriter) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "parquetWriter")
+	defer span.Finish()
+
+	err := func() error {
+		typs := sp.input.OutputTypes()
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
+
+		alloc := &rowenc.DatumAlloc{}
+
+		writer, err := newParquetExporter(sp.spec, typs)
+		if err != nil {
+			return err
+		}
+
+		datums := make(tree.Datums, len(typs))
+
+		chunk := 0
+		done := false
+		for {
+			var rows int64
+			if err := writer.Reset(); err != nil {
+				return err
+			}
+			for {
+				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
+					break
+				}
+				row, err := input.NextRow()
+				if err != nil {
+					return err
+				}
+				if row == nil {
+					done = true
+					break
+				}
+				rows++
+
+				for i, ed := range row {
+					if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
+						return err
+					}
+					datums[i] = ed.Datum
+				}
+				if err := writer.Write(datums); err != nil {
+					return err
+				}
+			}
+			if rows < 1 {
+				break
+			}
+			// Close writer to ensure the buffered row group and the footer are
+			// written.
+			if err := writer.Close(); err != nil {
+				return errors.Wrapf(err, "failed to close exporting writer")
+			}
+
+			conf, err := cloudimpl.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User())
+			if err != nil {
+				return err
+			}
+			es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+
+			nodeID, err := sp.flowCtx.EvalCtx.NodeID.OptionalNodeIDErr(47970)
+			if err != nil {
+				return err
+			}
+
+			part := fmt.Sprintf("n%d.%d", nodeID, chunk)
+			chunk++
+			filename := writer.FileName(sp.spec, part)
+			size := writer.Len()
+
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(writer.Bytes())); err != nil {
+				return err
+			}
+			res := rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(
+					types.String,
+					tree.NewDString(filename),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(rows)),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(size)),
+				),
+			}
+
+			cs, err := sp.out.EmitRow(ctx, res)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return errors.New("unexpected closure of consumer")
+			}
+			if done {
+				break
+			}
+		}
+
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// requireParquetFile checks that contents is framed as a parquet file, with
+// the magic bytes on both ends and a footer that fits in the file.
+func requireParquetFile(t *testing.T, contents []byte) {
+	t.Helper()
+	require.True(t, len(contents) > 12, "file too short: %d bytes", len(contents))
+	require.Equal(t, "PAR1", string(contents[:4]))
+	require.Equal(t, "PAR1", string(contents[len(contents)-4:]))
+	footerLen := binary.LittleEndian.Uint32(contents[len(contents)-8:])
+	require.True(t, int(footerLen) < len(contents)-12, "footer length %d", footerLen)
+}
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TYPE greeting AS ENUM ('hello', 'hi')`)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+		i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, a INT[], j JSONB, g greeting, s STRING
+	)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 1.25, '2021-01-01 00:00:00+00', ARRAY[1, NULL, 3], '{"a": 1}', 'hello', 'x'),
+		(2, NULL, NULL, NULL, NULL, NULL, NULL),
+		(3, -3.50, '2021-01-02 00:00:00+00', ARRAY[]:::INT[], '[]', 'hi', 'z')`)
+
+	for _, tc := range []struct {
+		name string
+		with string
+	}{
+		{name: "plain"},
+		{name: "snappy", with: `WITH compression = snappy`},
+		{name: "gzip", with: `WITH compression = gzip, row_group_size = 1`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var filename string
+			var rows, size int
+			sqlDB.QueryRow(t, `EXPORT INTO PARQUET 'nodelocal://0/`+tc.name+`' `+tc.with+
+				` FROM SELECT * FROM foo`).Scan(&filename, &rows, &size)
+			require.Equal(t, 3, rows)
+			require.Regexp(t, `\.parquet$`, filename)
+
+			contents := readFileByGlob(t, filepath.Join(dir, tc.name, "export*-n1.0.parquet"))
+			require.Equal(t, size, len(contents))
+			requireParquetFile(t, contents)
+		})
+	}
+
+	sqlDB.ExpectErr(t, `delimiter option is not supported for PARQUET export`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH delimiter = '|' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `row_group_size option is not supported for CSV export`,
+		`EXPORT INTO CSV 'nodelocal://0/bad' WITH row_group_size = '10' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `unsupported compression codec snappy`,
+		`EXPORT INTO CSV 'nodelocal://0/bad' WITH compression = snappy FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `invalid parquet row group size`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH row_group_size = '0' FROM SELECT * FROM foo`)
+}
+
+// TestExportParquetRequiresClusterVersion checks that EXPORT INTO PARQUET is
+// rejected until every node is able to write Parquet files.
+func TestExportParquetRequiresClusterVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	ctx := context.Background()
+	oldVersion := clusterversion.ByKey(clusterversion.ParquetFormat - 1)
+	newVersion := clusterversion.ByKey(clusterversion.ParquetFormat)
+	st := cluster.MakeTestingClusterSettingsWithVersions(
+		newVersion, oldVersion, false /* initializeVersion */)
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Settings:      st,
+		ExternalIODir: dir,
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				BinaryVersionOverride:          oldVersion,
+				DisableAutomaticVersionUpgrade: 1,
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE foo (i INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+	sqlDB.ExpectErr(t, `exporting to PARQUET requires all nodes to be upgraded`,
+		`EXPORT INTO PARQUET 'nodelocal://0/before' FROM SELECT * FROM foo`)
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING version = $1`, newVersion.String())
+	var filename string
+	var rows, size int
+	sqlDB.QueryRow(t, `EXPORT INTO PARQUET 'nodelocal://0/after' FROM SELECT * FROM foo`).Scan(
+		&filename, &rows, &size)
+	require.Equal(t, 1, rows)
+}
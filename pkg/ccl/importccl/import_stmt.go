@@ -1670,6 +1670,15 @@ func (r *importResumer) dropTables(
 	}
 
 	for i := range empty {
+		// Prefer deleting the imported data with a range tombstone, which keeps
+		// the history of the table intact for backups and changefeeds.
+		if ok, err := gcjob.DeleteTableDataUsingRangeTombstone(
+			ctx, execCfg.DB, execCfg.Settings, execCfg.Codec, empty[i],
+		); err != nil {
+			return errors.Wrapf(err, "deleting data for table %d", empty[i].ID)
+		} else if ok {
+			continue
+		}
 		if err := gcjob.ClearTableData(ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, empty[i]); err != nil {
 			return errors.Wrapf(err, "clearing data for table %d", empty[i].ID)
 		}
@@ -89,12 +89,17 @@ func txnCanPerformFollowerRead(txn *roachpb.Transaction) bool {
 	return txn != nil && !txn.IsLocking()
 }
 
-// canUseFollowerRead determines if a query can be sent to a follower.
-func canUseFollowerRead(clusterID uuid.UUID, st *cluster.Settings, ts hlc.Timestamp) bool {
+// canUseFollowerRead determines if a query can be sent to a follower. The
+// globalReadsLead is the lead of the closed timestamps of the range the query
+// is addressed to over present time, or zero if the range is not in
+// non-blocking mode.
+func canUseFollowerRead(
+	clusterID uuid.UUID, st *cluster.Settings, globalReadsLead time.Duration, ts hlc.Timestamp,
+) bool {
 	if !kvserver.FollowerReadsEnabled.Get(&st.SV) {
 		return false
 	}
-	threshold := (-1 * getFollowerReadDuration(st)) - 1*base.DefaultMaxClockOffset
+	threshold := (-1 * getFollowerReadDuration(st)) - 1*base.DefaultMaxClockOffset - globalReadsLead
 	if timeutil.Since(ts.GoTime()) < threshold {
 		return false
 	}
@@ -103,10 +108,16 @@ func canUseFollowerRead(clusterID uuid.UUID, st *cluster.Settings, ts hlc.Timest
 
 // canSendToFollower implements the logic for checking whether a batch request
 // may be sent to a follower.
-func canSendToFollower(clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest) bool {
+func canSendToFollower(
+	clusterID uuid.UUID,
+	st *cluster.Settings,
+	globalReadsLead time.Duration,
+	ba roachpb.BatchRequest,
+) bool {
 	return batchCanBeEvaluatedOnFollower(ba) &&
 		txnCanPerformFollowerRead(ba.Txn) &&
-		canUseFollowerRead(clusterID, st, forward(ba.Txn.ReadTimestamp, ba.Txn.MaxTimestamp))
+		canUseFollowerRead(clusterID, st, globalReadsLead,
+			forward(ba.Txn.ReadTimestamp, ba.Txn.MaxTimestamp))
 }
 
 func forward(ts hlc.Timestamp, to hlc.Timestamp) hlc.Timestamp {
@@ -132,7 +143,7 @@ func newOracleFactory(cfg replicaoracle.Config) replicaoracle.OracleFactory {
 }
 
 func (f oracleFactory) Oracle(txn *kv.Txn) replicaoracle.Oracle {
-	if txn != nil && canUseFollowerRead(f.clusterID.Get(), f.st, txn.ReadTimestamp()) {
+	if txn != nil && canUseFollowerRead(f.clusterID.Get(), f.st, 0 /* globalReadsLead */, txn.ReadTimestamp()) {
 		return f.closest.Oracle(txn)
 	}
 	return f.binPacking.Oracle(txn)
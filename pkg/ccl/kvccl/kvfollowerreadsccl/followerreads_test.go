@@ -82,22 +82,22 @@ func TestCanSendToFollower(t *testing.T) {
 	}}
 	rw := roachpb.BatchRequest{Header: oldHeader}
 	rw.Add(&roachpb.PutRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, rw) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, rw) {
 		t.Fatalf("should not be able to send a rw request to a follower")
 	}
 	roNonTxn := roachpb.BatchRequest{Header: oldHeader}
 	roNonTxn.Add(&roachpb.QueryTxnRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNonTxn) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roNonTxn) {
 		t.Fatalf("should not be able to send a non-transactional ro request to a follower")
 	}
 	roNoTxn := roachpb.BatchRequest{}
 	roNoTxn.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNoTxn) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roNoTxn) {
 		t.Fatalf("should not be able to send a batch with no txn to a follower")
 	}
 	roOld := roachpb.BatchRequest{Header: oldHeader}
 	roOld.Add(&roachpb.GetRequest{})
-	if !canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if !canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roOld) {
 		t.Fatalf("should be able to send an old ro batch to a follower")
 	}
 	roRWTxnOld := roachpb.BatchRequest{Header: roachpb.Header{
@@ -107,11 +107,11 @@ func TestCanSendToFollower(t *testing.T) {
 		},
 	}}
 	roRWTxnOld.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roRWTxnOld) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roRWTxnOld) {
 		t.Fatalf("should not be able to send a ro request from a rw txn to a follower")
 	}
 	kvserver.FollowerReadsEnabled.Override(&st.SV, false)
-	if canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roOld) {
 		t.Fatalf("should not be able to send an old ro batch to a follower when follower reads are disabled")
 	}
 	kvserver.FollowerReadsEnabled.Override(&st.SV, true)
@@ -120,7 +120,7 @@ func TestCanSendToFollower(t *testing.T) {
 			ReadTimestamp: hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
 		},
 	}}
-	if canSendToFollower(uuid.MakeV4(), st, roNew) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roNew) {
 		t.Fatalf("should not be able to send a new ro batch to a follower")
 	}
 	roOldWithNewMax := roachpb.BatchRequest{Header: roachpb.Header{
@@ -129,11 +129,21 @@ func TestCanSendToFollower(t *testing.T) {
 		},
 	}}
 	roOldWithNewMax.Add(&roachpb.GetRequest{})
-	if canSendToFollower(uuid.MakeV4(), st, roNew) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roNew) {
 		t.Fatalf("should not be able to send a ro batch with new MaxTimestamp to a follower")
 	}
+	// Ranges in non-blocking mode can serve present-time reads on followers.
+	roNewGet := roachpb.BatchRequest{Header: roachpb.Header{
+		Txn: &roachpb.Transaction{
+			ReadTimestamp: hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
+		},
+	}}
+	roNewGet.Add(&roachpb.GetRequest{})
+	if !canSendToFollower(uuid.MakeV4(), st, 5*time.Second, roNewGet) {
+		t.Fatalf("should be able to send a new ro batch to a follower of a global_reads range")
+	}
 	disableEnterprise()
-	if canSendToFollower(uuid.MakeV4(), st, roOld) {
+	if canSendToFollower(uuid.MakeV4(), st, 0 /* globalReadsLead */, roOld) {
 		t.Fatalf("should not be able to send an old ro batch to a follower without enterprise enabled")
 	}
 }
@@ -0,0 +1,407 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// The "ldap" authentication method validates the password provided by the
+// client against an LDAP directory, using the "search+bind" mode of
+// PostgreSQL: the server binds to the directory (either anonymously or with
+// ldapbinddn and ldapbindpasswd), searches for the entry of the user under
+// ldapbasedn, then binds again as that entry with the password provided by
+// the client.
+//
+// The following HBA options are supported:
+//
+//   ldapurl              URL of the LDAP server, e.g. ldaps://ldap.example.com:636.
+//                        Required.
+//   ldapbasedn           DN of the root of the search. Required.
+//   ldapbinddn           DN to bind as to perform the search. If unset, the
+//                        search is performed anonymously.
+//   ldapbindpasswd       Password to bind as ldapbinddn.
+//   ldapsearchattribute  Attribute matched against the user name during the
+//                        search. Defaults to uid.
+//   ldapsearchfilter     Filter used for the search instead of
+//                        ldapsearchattribute, where $username is replaced by
+//                        the user name, e.g. (&(objectClass=person)(uid=$username)).
+//   ldaptls              If set to 1, use StartTLS to secure connections
+//                        made with the ldap scheme.
+//   ldapgrouprolesync    If set to 1, upon successful authentication the user
+//                        is granted membership of the existing roles named
+//                        after the groups the user belongs to.
+//   ldapgroupattribute   Attribute of the user's entry listing the DNs of the
+//                        user's groups. Defaults to memberOf.
+//
+// Option values containing commas or spaces must be quoted as a whole, e.g.
+// "ldapbasedn=dc=example,dc=com".
+//
+// The user must exist in the cluster; only the password is checked against
+// the directory.
+
+const (
+	ldapURLOption             = "ldapurl"
+	ldapBaseDNOption          = "ldapbasedn"
+	ldapBindDNOption          = "ldapbinddn"
+	ldapBindPasswdOption      = "ldapbindpasswd"
+	ldapSearchAttributeOption = "ldapsearchattribute"
+	ldapSearchFilterOption    = "ldapsearchfilter"
+	ldapTLSOption             = "ldaptls"
+	ldapGroupRoleSyncOption   = "ldapgrouprolesync"
+	ldapGroupAttributeOption  = "ldapgroupattribute"
+
+	defaultLDAPSearchAttribute = "uid"
+	defaultLDAPGroupAttribute  = "memberOf"
+
+	// ldapUsernamePlaceholder is replaced by the user name in
+	// ldapsearchfilter.
+	ldapUsernamePlaceholder = "$username"
+
+	// ldapTimeout bounds the duration of each operation performed with the
+	// LDAP server.
+	ldapTimeout = 10 * time.Second
+)
+
+const authCleartextPassword int32 = 3
+
+// LDAPCustomCASettingName is the name of the setting holding the custom
+// root CA used to verify LDAP servers.
+const LDAPCustomCASettingName = "server.ldap_authentication.custom_ca"
+
+var ldapCustomCA = settings.RegisterStringSetting(
+	LDAPCustomCASettingName,
+	"custom root CA (appended to system's default CAs) for verifying certificates "+
+		"when connecting to LDAP servers",
+	"",
+).WithPublic()
+
+// ldapConfig is the configuration of the ldap method extracted from the
+// options of an HBA entry.
+type ldapConfig struct {
+	url             *url.URL
+	baseDN          string
+	bindDN          string
+	bindPasswd      string
+	searchAttribute string
+	searchFilter    string
+	startTLS        bool
+	groupRoleSync   bool
+	groupAttribute  string
+}
+
+func parseLDAPConfig(entry hba.Entry) (ldapConfig, error) {
+	conf := ldapConfig{
+		searchAttribute: defaultLDAPSearchAttribute,
+		groupAttribute:  defaultLDAPGroupAttribute,
+	}
+	seen := make(map[string]bool)
+	for _, op := range entry.Options {
+		name, val := op[0], op[1]
+		if seen[name] {
+			return conf, errors.Errorf("option %s specified more than once", name)
+		}
+		seen[name] = true
+		switch name {
+		case ldapURLOption:
+			u, err := url.Parse(val)
+			if err != nil {
+				return conf, errors.Wrapf(err, "invalid %s", name)
+			}
+			if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+				return conf, errors.Errorf("%s must use the ldap or ldaps scheme: %s", name, val)
+			}
+			if u.Hostname() == "" {
+				return conf, errors.Errorf("%s must specify a host: %s", name, val)
+			}
+			conf.url = u
+		case ldapBaseDNOption:
+			conf.baseDN = val
+		case ldapBindDNOption:
+			conf.bindDN = val
+		case ldapBindPasswdOption:
+			conf.bindPasswd = val
+		case ldapSearchAttributeOption:
+			conf.searchAttribute = val
+		case ldapSearchFilterOption:
+			if !strings.Contains(val, ldapUsernamePlaceholder) {
+				return conf, errors.Errorf("%s must contain %s", name, ldapUsernamePlaceholder)
+			}
+			if _, err := compileLDAPFilter(
+				strings.Replace(val, ldapUsernamePlaceholder, "x", -1)); err != nil {
+				return conf, err
+			}
+			conf.searchFilter = val
+		case ldapTLSOption, ldapGroupRoleSyncOption:
+			if val != "0" && val != "1" {
+				return conf, errors.Errorf("%s must be set to 0 or 1: %s", name, val)
+			}
+			if name == ldapTLSOption {
+				conf.startTLS = val == "1"
+			} else {
+				conf.groupRoleSync = val == "1"
+			}
+		case ldapGroupAttributeOption:
+			conf.groupAttribute = val
+		default:
+			return conf, errors.Errorf("unsupported option %s", name)
+		}
+	}
+	if conf.url == nil {
+		return conf, errors.Errorf("missing %q option in LDAP entry", ldapURLOption)
+	}
+	if conf.baseDN == "" {
+		return conf, errors.Errorf("missing %q option in LDAP entry", ldapBaseDNOption)
+	}
+	if (conf.bindDN == "") != (conf.bindPasswd == "") {
+		return conf, errors.Errorf("options %s and %s must be specified together",
+			ldapBindDNOption, ldapBindPasswdOption)
+	}
+	if seen[ldapSearchAttributeOption] && seen[ldapSearchFilterOption] {
+		return conf, errors.Errorf("options %s and %s cannot be used together",
+			ldapSearchAttributeOption, ldapSearchFilterOption)
+	}
+	if conf.startTLS && conf.url.Scheme == "ldaps" {
+		return conf, errors.Errorf("option %s cannot be used with the ldaps scheme", ldapTLSOption)
+	}
+	return conf, nil
+}
+
+// filter returns the search filter matching the entry of user.
+func (conf *ldapConfig) filter(user string) string {
+	user = escapeLDAPFilterValue(user)
+	if conf.searchFilter != "" {
+		return strings.Replace(conf.searchFilter, ldapUsernamePlaceholder, user, -1)
+	}
+	return fmt.Sprintf("(%s=%s)", conf.searchAttribute, user)
+}
+
+func checkEntry(entry hba.Entry) error {
+	_, err := parseLDAPConfig(entry)
+	return err
+}
+
+// makeTLSConfig returns the TLS configuration used to connect to LDAP
+// servers, or nil to use the defaults.
+func makeTLSConfig(sv *settings.Values) (*tls.Config, error) {
+	pem := ldapCustomCA.Get(sv)
+	if pem == "" {
+		return nil, nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load system root CA pool")
+	}
+	if !roots.AppendCertsFromPEM([]byte(pem)) {
+		return nil, errors.Errorf("failed to parse root CA certificate from %q", pem)
+	}
+	return &tls.Config{RootCAs: roots}, nil
+}
+
+// authLDAP performs LDAP authentication.
+func authLDAP(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	_ tls.ConnectionState,
+	_ pgwire.PasswordRetrievalFn,
+	_ pgwire.PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	entry *hba.Entry,
+) (security.UserAuthHook, error) {
+	conf, err := parseLDAPConfig(*entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SendAuthRequest(authCleartextPassword, nil /* data */); err != nil {
+		return nil, err
+	}
+	pwdData, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(pwdData, 0) != len(pwdData)-1 {
+		return nil, errors.New("expected 0-terminated byte array")
+	}
+	password := string(pwdData[:len(pwdData)-1])
+
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		if requestedUser.Undefined() {
+			return nil, errors.New("user is missing")
+		}
+		if !clientConnection {
+			return nil, errors.New("LDAP authentication is only available for client connections")
+		}
+		// An empty password would result in an unauthenticated bind, which
+		// most servers accept. Reject it upfront.
+		if password == "" {
+			return nil, errors.Errorf(security.ErrPasswordUserAuthFailed, requestedUser)
+		}
+
+		groups, err := ldapAuthenticate(ctx, c, execCfg, &conf, requestedUser.Normalized(), password)
+		if err != nil {
+			c.Logf(ctx, "LDAP authentication failed: %v", err)
+			return nil, errors.Errorf(security.ErrPasswordUserAuthFailed, requestedUser)
+		}
+
+		// Do the license check after the directory has been contacted, so
+		// that administrators are able to test whether their configuration
+		// is correct.
+		if err := utilccl.CheckEnterpriseEnabled(
+			execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "LDAP authentication",
+		); err != nil {
+			return nil, err
+		}
+
+		if conf.groupRoleSync {
+			if err := syncGroupRoles(ctx, c, execCfg, requestedUser, groups); err != nil {
+				// Failing to sync roles does not prevent the user from logging
+				// in with the roles they already have.
+				log.Warningf(ctx, "unable to sync LDAP groups of user %s: %v", requestedUser, err)
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// ldapAuthenticate checks the password of user against the directory and
+// returns the DNs of the groups the user belongs to, if group role sync is
+// enabled.
+func ldapAuthenticate(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	execCfg *sql.ExecutorConfig,
+	conf *ldapConfig,
+	user, password string,
+) (groups []string, _ error) {
+	tlsConf, err := makeTLSConfig(&execCfg.Settings.SV)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialLDAP(ctx, conf.url, conf.startTLS, tlsConf, ldapTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if conf.bindDN != "" {
+		if err := conn.Bind(conf.bindDN, conf.bindPasswd); err != nil {
+			return nil, errors.Wrapf(err, "binding as %s", conf.bindDN)
+		}
+	}
+	var attrs []string
+	if conf.groupRoleSync {
+		attrs = []string{conf.groupAttribute}
+	} else {
+		// Request no attributes, see RFC 4511, section 4.5.1.8.
+		attrs = []string{"1.1"}
+	}
+	entries, err := conn.Search(conf.baseDN, conf.filter(user), attrs)
+	if err != nil {
+		return nil, err
+	}
+	switch len(entries) {
+	case 0:
+		return nil, errors.Errorf("user %s not found in LDAP directory", user)
+	case 1:
+	default:
+		return nil, errors.Errorf("user %s matches %d entries in LDAP directory", user, len(entries))
+	}
+	c.Logf(ctx, "found LDAP entry %s for user %s", entries[0].dn, user)
+
+	if err := conn.Bind(entries[0].dn, password); err != nil {
+		return nil, err
+	}
+	return entries[0].attributes[strings.ToLower(conf.groupAttribute)], nil
+}
+
+// syncGroupRoles grants the user membership of the existing roles named
+// after the common name of the user's groups. Memberships are never revoked,
+// since they may have been granted outside of LDAP.
+func syncGroupRoles(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	execCfg *sql.ExecutorConfig,
+	user security.SQLUsername,
+	groups []string,
+) error {
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+	for _, group := range groups {
+		cn := groupCommonName(group)
+		if cn == "" {
+			continue
+		}
+		role, err := security.MakeSQLUsernameFromUserInput(cn, security.UsernameValidation)
+		if err != nil || role.IsAdminRole() || role.IsRootUser() || role.IsPublicRole() || role == user {
+			// Groups which cannot correspond to roles are ignored, and we never
+			// grant admin privileges based on directory contents.
+			continue
+		}
+		row, err := ie.QueryRowEx(ctx, "ldap-sync-roles", nil /* txn */, override,
+			`SELECT 1 FROM system.users WHERE username = $1 AND "isRole"
+			 AND NOT EXISTS (SELECT 1 FROM system.role_members WHERE role = $1 AND member = $2)`,
+			role.Normalized(), user.Normalized())
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			// Either the role does not exist, or the user is already a member.
+			continue
+		}
+		if _, err := ie.ExecEx(ctx, "ldap-sync-roles", nil /* txn */, override,
+			fmt.Sprintf("GRANT %s TO %s", role.SQLIdentifier(), user.SQLIdentifier()),
+		); err != nil {
+			return err
+		}
+		c.Logf(ctx, "granted role %s to user %s based on LDAP group %s", role, user, group)
+	}
+	return nil
+}
+
+// groupCommonName returns the value of the first RDN of dn if it is a common
+// name, e.g. "developers" for "cn=developers,ou=groups,dc=example,dc=com".
+func groupCommonName(dn string) string {
+	rdn := dn
+	for i := 0; i < len(dn); i++ {
+		if dn[i] == '\\' {
+			i++
+			continue
+		}
+		if dn[i] == ',' || dn[i] == '+' {
+			rdn = dn[:i]
+			break
+		}
+	}
+	eq := strings.IndexByte(rdn, '=')
+	if eq < 0 || !strings.EqualFold(strings.TrimSpace(rdn[:eq]), "cn") {
+		return ""
+	}
+	return strings.TrimSpace(rdn[eq+1:])
+}
+
+func init() {
+	pgwire.RegisterAuthMethod("ldap", authLDAP, hba.ConnAny, checkEntry)
+}
@@ -0,0 +1,422 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	defer utilccl.TestingEnableEnterprise()()
+	security.SetAssetLoader(securitytest.EmbeddedAssets)
+	randutil.SeedForTests()
+	serverutils.InitTestServerFactory(server.TestServerFactory)
+	serverutils.InitTestClusterFactory(testcluster.TestClusterFactory)
+	os.Exit(m.Run())
+}
+
+// ldapStandIn is an in-process LDAP server serving a fixed directory. It
+// supports simple binds and searches with equality, presence, and, or and
+// not filters.
+type ldapStandIn struct {
+	ln      net.Listener
+	entries map[string]ldapStandInEntry
+
+	mu struct {
+		syncutil.Mutex
+		binds []string
+	}
+}
+
+type ldapStandInEntry struct {
+	password   string
+	attributes map[string][]string
+}
+
+func startLDAPStandIn(t *testing.T, entries map[string]ldapStandInEntry) *ldapStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &ldapStandIn{ln: ln, entries: entries}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *ldapStandIn) url() string {
+	return "ldap://" + s.ln.Addr().String()
+}
+
+func (s *ldapStandIn) close() {
+	_ = s.ln.Close()
+}
+
+func (s *ldapStandIn) binds() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mu.binds...)
+}
+
+func ldapResult(tag byte, code int64, msg string) *berPacket {
+	return berSequence(tag,
+		berInt(berTagEnumerated, code),
+		berOctetString(berTagOctetString, ""),
+		berOctetString(berTagOctetString, msg))
+}
+
+func (s *ldapStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := readBERPacket(r)
+		if err != nil || len(msg.children) < 2 {
+			return
+		}
+		id := msg.children[0]
+		op := msg.children[1]
+		var responses []*berPacket
+		switch op.tag {
+		case ldapBindRequest:
+			dn, password := op.children[1].str(), op.children[2].str()
+			s.mu.Lock()
+			s.mu.binds = append(s.mu.binds, dn)
+			s.mu.Unlock()
+			if e, ok := s.entries[dn]; dn == "" || (ok && e.password == password) {
+				responses = append(responses, ldapResult(ldapBindResponse, 0, ""))
+			} else {
+				responses = append(responses, ldapResult(ldapBindResponse, 49, "invalid credentials"))
+			}
+		case ldapSearchRequest:
+			baseDN, filter := op.children[0].str(), op.children[6]
+			var requested []string
+			for _, a := range op.children[7].children {
+				requested = append(requested, strings.ToLower(a.str()))
+			}
+			for dn, e := range s.entries {
+				if !strings.HasSuffix(dn, baseDN) || !matchLDAPFilter(e, filter) {
+					continue
+				}
+				attrs := berSequence(berTagSequence)
+				for _, name := range requested {
+					vals := berSequence(berTagSet)
+					for _, v := range e.attributes[name] {
+						vals.children = append(vals.children, berOctetString(berTagOctetString, v))
+					}
+					if len(vals.children) > 0 {
+						attrs.children = append(attrs.children,
+							berSequence(berTagSequence, berOctetString(berTagOctetString, name), vals))
+					}
+				}
+				responses = append(responses, berSequence(ldapSearchResultEntry,
+					berOctetString(berTagOctetString, dn), attrs))
+			}
+			responses = append(responses, ldapResult(ldapSearchResultDone, 0, ""))
+		case ldapExtendedRequest:
+			responses = append(responses, ldapResult(ldapExtendedResponse, 2, "unsupported operation"))
+		default:
+			return
+		}
+		for _, res := range responses {
+			if _, err := conn.Write(berSequence(berTagSequence, id, res).appendTo(nil)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func matchLDAPFilter(e ldapStandInEntry, f *berPacket) bool {
+	switch f.tag {
+	case ldapFilterAnd, ldapFilterOr:
+		for _, c := range f.children {
+			if matchLDAPFilter(e, c) == (f.tag == ldapFilterOr) {
+				return f.tag == ldapFilterOr
+			}
+		}
+		return f.tag == ldapFilterAnd
+	case ldapFilterNot:
+		return !matchLDAPFilter(e, f.children[0])
+	case ldapFilterPresent:
+		return len(e.attributes[strings.ToLower(f.str())]) > 0
+	case ldapFilterEqualityMatch:
+		for _, v := range e.attributes[strings.ToLower(f.children[0].str())] {
+			if strings.EqualFold(v, f.children[1].str()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestLDAPFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		filter string
+		err    string
+	}{
+		{filter: "(uid=alice)"},
+		{filter: "(&(objectClass=person)(|(uid=alice)(mail=alice@example.com)))"},
+		{filter: "(!(uid=bob))"},
+		{filter: "(uid=*)"},
+		{filter: "(cn=al*ce*)"},
+		{filter: "(uidNumber>=1000)"},
+		{filter: `(cn=a\2a\28b\29)`},
+		{filter: "uid=alice", err: "expected ("},
+		{filter: "(uid=alice", err: "expected )"},
+		{filter: "(uid=alice))", err: "unexpected trailing characters"},
+		{filter: "(&)", err: "empty filter list"},
+		{filter: "(=alice)", err: "invalid filter item"},
+		{filter: "(cn:dn:=alice)", err: "extensible match filters are not supported"},
+		{filter: `(cn=a\2)`, err: "invalid escape"},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			p, err := compileLDAPFilter(tc.filter)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			// The encoding must round trip.
+			decoded, rest, err := decodeBERPacket(p.appendTo(nil))
+			require.NoError(t, err)
+			require.Empty(t, rest)
+			require.Equal(t, p.appendTo(nil), decoded.appendTo(nil))
+		})
+	}
+
+	p, err := compileLDAPFilter(`(cn=a\2a\28b\29)`)
+	require.NoError(t, err)
+	require.Equal(t, "a*(b)", p.children[1].str())
+	require.Equal(t, `a\2a\28b\29\5c`, escapeLDAPFilterValue(`a*(b)\`))
+}
+
+func TestBERInt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, i := range []int64{0, 1, 127, 128, 255, 256, 1 << 40, -1, -128, -129, -(1 << 40)} {
+		p := berInt(berTagInteger, i)
+		decoded, _, err := decodeBERPacket(p.appendTo(nil))
+		require.NoError(t, err)
+		v, err := decoded.int()
+		require.NoError(t, err)
+		require.Equal(t, i, v)
+	}
+	// Long form lengths.
+	long := berOctetString(berTagOctetString, strings.Repeat("x", 1000))
+	decoded, _, err := decodeBERPacket(long.appendTo(nil))
+	require.NoError(t, err)
+	require.Equal(t, long.value, decoded.value)
+}
+
+func TestParseLDAPConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		options string
+		filter  string
+		err     string
+	}{
+		{
+			options: `ldapurl=ldap://ldap.example.com "ldapbasedn=dc=example,dc=com"`,
+			filter:  `(uid=a\2a)`,
+		},
+		{
+			options: `ldapurl=ldaps://ldap.example.com "ldapbasedn=dc=example,dc=com" ldapsearchattribute=cn`,
+			filter:  `(cn=a\2a)`,
+		},
+		{
+			options: `ldapurl=ldap://ldap.example.com ldapbasedn=dc=com ldaptls=1 ` +
+				`"ldapsearchfilter=(&(objectClass=person)(uid=$username))"`,
+			filter: `(&(objectClass=person)(uid=a\2a))`,
+		},
+		{options: `ldapbasedn=dc=com`, err: `missing "ldapurl" option`},
+		{options: `ldapurl=ldap://ldap.example.com`, err: `missing "ldapbasedn" option`},
+		{options: `ldapurl=http://ldap.example.com ldapbasedn=dc=com`, err: "must use the ldap or ldaps scheme"},
+		{options: `ldapurl=ldap:// ldapbasedn=dc=com`, err: "must specify a host"},
+		{options: `ldapurl=ldap://h ldapbasedn=dc=com ldapbinddn=cn=svc`, err: "must be specified together"},
+		{options: `ldapurl=ldap://h ldapbasedn=dc=com ldaptls=yes`, err: "must be set to 0 or 1"},
+		{options: `ldapurl=ldaps://h ldapbasedn=dc=com ldaptls=1`, err: "cannot be used with the ldaps scheme"},
+		{options: `ldapurl=ldap://h ldapbasedn=dc=com ldapsearchfilter=(uid=x)`, err: "must contain $username"},
+		{
+			options: `ldapurl=ldap://h ldapbasedn=dc=com ldapsearchattribute=cn ldapsearchfilter=(uid=$username)`,
+			err:     "cannot be used together",
+		},
+		{options: `ldapurl=ldap://h ldapbasedn=dc=com ldapbasedn=dc=org`, err: "specified more than once"},
+		{options: `ldapurl=ldap://h ldapbasedn=dc=com ldapport=389`, err: "unsupported option ldapport"},
+	} {
+		t.Run(tc.options, func(t *testing.T) {
+			conf, err := hba.ParseAndNormalize("host all all all ldap " + tc.options)
+			require.NoError(t, err)
+			entry := conf.Entries[0]
+			require.Equal(t, tc.err == "", checkEntry(entry) == nil)
+			ldapConf, err := parseLDAPConfig(entry)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.filter, ldapConf.filter("a*"))
+		})
+	}
+}
+
+func TestGroupCommonName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for dn, expected := range map[string]string{
+		"cn=developers,ou=groups,dc=example,dc=com": "developers",
+		"CN = ops ,dc=com":                          "ops",
+		`cn=a\,b,dc=com`:                            `a\,b`,
+		"ou=groups,dc=example,dc=com":               "",
+		"developers":                                "",
+	} {
+		require.Equal(t, expected, groupCommonName(dn), dn)
+	}
+}
+
+func TestLDAPClient(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const aliceDN = "uid=alice,ou=people,dc=example,dc=com"
+	standIn := startLDAPStandIn(t, map[string]ldapStandInEntry{
+		aliceDN: {
+			password:   "secret",
+			attributes: map[string][]string{"uid": {"alice"}, "memberof": {"cn=a,dc=com", "cn=b,dc=com"}},
+		},
+	})
+	defer standIn.close()
+
+	ctx := context.Background()
+	u, err := url.Parse(standIn.url())
+	require.NoError(t, err)
+	conn, err := dialLDAP(ctx, u, false /* startTLS */, nil /* tlsConf */, ldapTimeout)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+
+	require.NoError(t, conn.Bind("", ""))
+	entries, err := conn.Search("dc=example,dc=com", "(&(uid=alice)(memberOf=*))", []string{"memberOf"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, aliceDN, entries[0].dn)
+	require.Equal(t, []string{"cn=a,dc=com", "cn=b,dc=com"}, entries[0].attributes["memberof"])
+
+	entries, err = conn.Search("dc=example,dc=com", "(uid=bob)", []string{"1.1"})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoError(t, conn.Bind(aliceDN, "secret"))
+	err = conn.Bind(aliceDN, "wrong")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "result code 49")
+
+	// The stand-in does not support StartTLS.
+	_, err = dialLDAP(ctx, u, true /* startTLS */, nil /* tlsConf */, ldapTimeout)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "StartTLS failed")
+}
+
+func TestLDAPAuthentication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const (
+		serviceDN = "cn=svc,dc=example,dc=com"
+		aliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	)
+	standIn := startLDAPStandIn(t, map[string]ldapStandInEntry{
+		serviceDN: {password: "svcpw"},
+		aliceDN: {
+			password: "secret",
+			attributes: map[string][]string{
+				"uid": {"alice"},
+				"memberof": {
+					"cn=developers,ou=groups,dc=example,dc=com",
+					"cn=admin,ou=groups,dc=example,dc=com",
+					"cn=unknown,ou=groups,dc=example,dc=com",
+				},
+			},
+		},
+	})
+	defer standIn.close()
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE USER alice`)
+	sqlDB.Exec(t, `CREATE ROLE developers`)
+	sqlDB.Exec(t, `CREATE ROLE testers`)
+
+	sqlDB.ExpectErr(t, `missing "ldapurl" option`,
+		`SET CLUSTER SETTING server.host_based_authentication.configuration = 'host all all all ldap ldapbasedn=dc=com'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = $1`,
+		fmt.Sprintf(`host all alice all ldap ldapurl=%s "ldapbasedn=dc=example,dc=com" `+
+			`"ldapbinddn=%s" ldapbindpasswd=svcpw ldapgrouprolesync=1`, standIn.url(), serviceDN))
+
+	connect := func(password string) error {
+		url := fmt.Sprintf("postgres://alice:%s@%s/defaultdb?sslmode=require",
+			password, s.ServingSQLAddr())
+		conn, err := pgx.Connect(ctx, url)
+		if err != nil {
+			return err
+		}
+		return conn.Close(ctx)
+	}
+
+	// Wait for the HBA configuration to be in effect.
+	testutils.SucceedsSoon(t, func() error { return connect("secret") })
+	require.Contains(t, standIn.binds(), serviceDN)
+	require.Contains(t, standIn.binds(), aliceDN)
+
+	require.Regexp(t, `password authentication failed for user alice`, connect("wrong"))
+	require.Regexp(t, `password authentication failed for user alice`, connect(""))
+
+	// The user was granted the roles matching their groups, except admin.
+	sqlDB.CheckQueryResults(t,
+		`SELECT role FROM system.role_members WHERE member = 'alice' ORDER BY role`,
+		[][]string{{"developers"}})
+	// Logging in again is a no-op.
+	require.NoError(t, connect("secret"))
+	sqlDB.CheckQueryResults(t,
+		`SELECT role FROM system.role_members WHERE member = 'alice' ORDER BY role`,
+		[][]string{{"developers"}})
+}
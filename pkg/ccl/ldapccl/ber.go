@@ -0,0 +1,220 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements the subset of the ASN.1 Basic Encoding Rules needed
+// to speak LDAPv3 (RFC 4511): single byte tags and definite lengths.
+
+// BER class and form bits of the identifier octet.
+const (
+	berClassApplication byte = 0x40
+	berClassContext     byte = 0x80
+	berConstructed      byte = 0x20
+)
+
+// Universal tags.
+const (
+	berTagBoolean     byte = 0x01
+	berTagInteger     byte = 0x02
+	berTagOctetString byte = 0x04
+	berTagEnumerated  byte = 0x0a
+	berTagSequence    byte = 0x10 | berConstructed
+	berTagSet         byte = 0x11 | berConstructed
+)
+
+// maxBERMessageSize bounds the size of the messages accepted from the peer.
+const maxBERMessageSize = 16 << 20
+
+// berPacket is a decoded BER element. Primitive elements carry their
+// contents in value, constructed elements in children.
+type berPacket struct {
+	tag      byte
+	value    []byte
+	children []*berPacket
+}
+
+func (p *berPacket) constructed() bool {
+	return p.tag&berConstructed != 0
+}
+
+func berSequence(tag byte, children ...*berPacket) *berPacket {
+	return &berPacket{tag: tag, children: children}
+}
+
+func berOctetString(tag byte, s string) *berPacket {
+	return &berPacket{tag: tag, value: []byte(s)}
+}
+
+func berBool(tag byte, b bool) *berPacket {
+	v := byte(0)
+	if b {
+		v = 0xff
+	}
+	return &berPacket{tag: tag, value: []byte{v}}
+}
+
+func berInt(tag byte, i int64) *berPacket {
+	// Two's complement, big endian, minimal length.
+	var buf [8]byte
+	n := 8
+	for {
+		n--
+		buf[n] = byte(i)
+		i >>= 8
+		if (i == 0 && buf[n]&0x80 == 0) || (i == -1 && buf[n]&0x80 != 0) {
+			break
+		}
+	}
+	return &berPacket{tag: tag, value: append([]byte(nil), buf[n:]...)}
+}
+
+// str returns the contents of a primitive element as a string.
+func (p *berPacket) str() string {
+	return string(p.value)
+}
+
+// int returns the contents of an INTEGER or ENUMERATED element.
+func (p *berPacket) int() (int64, error) {
+	if len(p.value) == 0 || len(p.value) > 8 {
+		return 0, errors.Newf("invalid BER integer of length %d", len(p.value))
+	}
+	i := int64(int8(p.value[0]))
+	for _, b := range p.value[1:] {
+		i = i<<8 | int64(b)
+	}
+	return i, nil
+}
+
+// child returns the i-th child of a constructed element.
+func (p *berPacket) child(i int) (*berPacket, error) {
+	if i >= len(p.children) {
+		return nil, errors.Newf("BER element with tag 0x%02x has %d children, expected at least %d",
+			p.tag, len(p.children), i+1)
+	}
+	return p.children[i], nil
+}
+
+func (p *berPacket) contents() []byte {
+	if !p.constructed() {
+		return p.value
+	}
+	var buf []byte
+	for _, c := range p.children {
+		buf = c.appendTo(buf)
+	}
+	return buf
+}
+
+// appendTo appends the encoding of the element to buf.
+func (p *berPacket) appendTo(buf []byte) []byte {
+	contents := p.contents()
+	buf = append(buf, p.tag)
+	l := len(contents)
+	switch {
+	case l < 0x80:
+		buf = append(buf, byte(l))
+	default:
+		var lenBuf [4]byte
+		n := 4
+		for l > 0 {
+			n--
+			lenBuf[n] = byte(l)
+			l >>= 8
+		}
+		buf = append(buf, 0x80|byte(4-n))
+		buf = append(buf, lenBuf[n:]...)
+	}
+	return append(buf, contents...)
+}
+
+// readBERPacket reads and decodes a single element from r.
+func readBERPacket(r *bufio.Reader) (*berPacket, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(l)
+	if l&0x80 != 0 {
+		n := int(l & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errors.Newf("unsupported BER length encoding 0x%02x", l)
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxBERMessageSize {
+		return nil, errors.Newf("BER element too large: %d bytes", length)
+	}
+	contents := make([]byte, length)
+	if _, err := io.ReadFull(r, contents); err != nil {
+		return nil, err
+	}
+	return decodeBERContents(tag, contents)
+}
+
+// decodeBERPacket decodes a single element which must span all of buf.
+func decodeBERPacket(buf []byte) (*berPacket, []byte, error) {
+	if len(buf) < 2 {
+		return nil, nil, errors.New("truncated BER element")
+	}
+	tag, l := buf[0], buf[1]
+	buf = buf[2:]
+	length := int(l)
+	if l&0x80 != 0 {
+		n := int(l & 0x7f)
+		if n == 0 || n > 4 || len(buf) < n {
+			return nil, nil, errors.Newf("invalid BER length encoding 0x%02x", l)
+		}
+		length = 0
+		for _, b := range buf[:n] {
+			length = length<<8 | int(b)
+		}
+		buf = buf[n:]
+	}
+	if length > len(buf) {
+		return nil, nil, errors.New("truncated BER element")
+	}
+	p, err := decodeBERContents(tag, buf[:length])
+	return p, buf[length:], err
+}
+
+func decodeBERContents(tag byte, contents []byte) (*berPacket, error) {
+	p := &berPacket{tag: tag}
+	if !p.constructed() {
+		p.value = contents
+		return p, nil
+	}
+	for len(contents) > 0 {
+		var c *berPacket
+		var err error
+		c, contents, err = decodeBERPacket(contents)
+		if err != nil {
+			return nil, err
+		}
+		p.children = append(p.children, c)
+	}
+	return p, nil
+}
@@ -0,0 +1,481 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains a minimal LDAPv3 client (RFC 4511), sufficient to
+// perform simple binds and searches.
+
+// LDAP protocol operations.
+const (
+	ldapBindRequest       = berClassApplication | berConstructed | 0
+	ldapBindResponse      = berClassApplication | berConstructed | 1
+	ldapUnbindRequest     = berClassApplication | 2
+	ldapSearchRequest     = berClassApplication | berConstructed | 3
+	ldapSearchResultEntry = berClassApplication | berConstructed | 4
+	ldapSearchResultDone  = berClassApplication | berConstructed | 5
+	ldapSearchResultRef   = berClassApplication | berConstructed | 19
+	ldapExtendedRequest   = berClassApplication | berConstructed | 23
+	ldapExtendedResponse  = berClassApplication | berConstructed | 24
+)
+
+// ldapResultSuccess is the resultCode of successful operations.
+const ldapResultSuccess = 0
+
+// ldapStartTLSOID is the name of the StartTLS extended operation (RFC 4511,
+// section 4.14).
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// ldapScopeWholeSubtree is the search scope covering the base object and all
+// its subordinates.
+const ldapScopeWholeSubtree = 2
+
+const (
+	defaultLDAPPort  = "389"
+	defaultLDAPSPort = "636"
+)
+
+// ldapError is returned when the server responds to an operation with a
+// resultCode other than success.
+type ldapError struct {
+	op         string
+	resultCode int64
+	message    string
+}
+
+func (e *ldapError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("LDAP %s failed with result code %d", e.op, e.resultCode)
+	}
+	return fmt.Sprintf("LDAP %s failed with result code %d: %s", e.op, e.resultCode, e.message)
+}
+
+// ldapEntry is an entry returned by a search.
+type ldapEntry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// ldapConn is a connection to an LDAP server.
+type ldapConn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	msgID   int64
+}
+
+// dialLDAP connects to the server designated by u, which must use either
+// the ldap or the ldaps scheme. If startTLS is set, the connection is
+// upgraded to TLS using the StartTLS extended operation. tlsConf may be nil,
+// in which case the system roots are used to verify the server.
+func dialLDAP(
+	ctx context.Context, u *url.URL, startTLS bool, tlsConf *tls.Config, timeout time.Duration,
+) (*ldapConn, error) {
+	host := u.Hostname()
+	port := u.Port()
+	if tlsConf == nil {
+		tlsConf = &tls.Config{}
+	}
+	if tlsConf.ServerName == "" {
+		tlsConf = tlsConf.Clone()
+		tlsConf.ServerName = host
+	}
+	d := net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = defaultLDAPPort
+		}
+		conn, err = d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	case "ldaps":
+		if port == "" {
+			port = defaultLDAPSPort
+		}
+		conn, err = (&tls.Dialer{NetDialer: &d, Config: tlsConf}).DialContext(
+			ctx, "tcp", net.JoinHostPort(host, port))
+	default:
+		return nil, errors.Newf("unsupported LDAP URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to LDAP server")
+	}
+	c := &ldapConn{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+	if startTLS {
+		if u.Scheme == "ldaps" {
+			_ = conn.Close()
+			return nil, errors.New("StartTLS cannot be used with the ldaps scheme")
+		}
+		if err := c.startTLS(tlsConf); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Close sends an unbind request and closes the connection.
+func (c *ldapConn) Close() error {
+	_ = c.send(&berPacket{tag: ldapUnbindRequest})
+	return c.conn.Close()
+}
+
+// send writes an LDAPMessage carrying the given protocol operation, using
+// a new message ID.
+func (c *ldapConn) send(op *berPacket) error {
+	c.msgID++
+	msg := berSequence(berTagSequence, berInt(berTagInteger, c.msgID), op)
+	if c.timeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return err
+		}
+	}
+	_, err := c.conn.Write(msg.appendTo(nil))
+	return errors.Wrap(err, "writing to LDAP server")
+}
+
+// receive reads an LDAPMessage and returns its protocol operation.
+func (c *ldapConn) receive() (*berPacket, error) {
+	msg, err := readBERPacket(c.r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading from LDAP server")
+	}
+	if msg.tag != berTagSequence {
+		return nil, errors.Newf("unexpected LDAP message with tag 0x%02x", msg.tag)
+	}
+	idPacket, err := msg.child(0)
+	if err != nil {
+		return nil, err
+	}
+	id, err := idPacket.int()
+	if err != nil {
+		return nil, err
+	}
+	if id != c.msgID {
+		// Message ID 0 is used for unsolicited notifications, such as the
+		// server disconnecting us.
+		return nil, errors.Newf("unexpected LDAP message ID %d, expected %d", id, c.msgID)
+	}
+	return msg.child(1)
+}
+
+// checkResult checks the LDAPResult of an operation.
+func checkResult(op string, res *berPacket) error {
+	if len(res.children) < 3 {
+		return errors.Newf("malformed LDAP %s response", op)
+	}
+	code, err := res.children[0].int()
+	if err != nil {
+		return err
+	}
+	if code != ldapResultSuccess {
+		return &ldapError{op: op, resultCode: code, message: res.children[2].str()}
+	}
+	return nil
+}
+
+func (c *ldapConn) startTLS(tlsConf *tls.Config) error {
+	req := berSequence(ldapExtendedRequest, berOctetString(berClassContext|0, ldapStartTLSOID))
+	if err := c.send(req); err != nil {
+		return err
+	}
+	res, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if res.tag != ldapExtendedResponse {
+		return errors.Newf("unexpected LDAP response with tag 0x%02x to StartTLS", res.tag)
+	}
+	if err := checkResult("StartTLS", res); err != nil {
+		return err
+	}
+	tlsConn := tls.Client(c.conn, tlsConf)
+	if err := tlsConn.Handshake(); err != nil {
+		return errors.Wrap(err, "LDAP StartTLS handshake")
+	}
+	c.conn = tlsConn
+	c.r = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Bind performs a simple bind. An empty dn and password perform an
+// anonymous bind.
+func (c *ldapConn) Bind(dn, password string) error {
+	req := berSequence(ldapBindRequest,
+		berInt(berTagInteger, 3),
+		berOctetString(berTagOctetString, dn),
+		berOctetString(berClassContext|0, password),
+	)
+	if err := c.send(req); err != nil {
+		return err
+	}
+	res, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if res.tag != ldapBindResponse {
+		return errors.Newf("unexpected LDAP response with tag 0x%02x to bind", res.tag)
+	}
+	return checkResult("bind", res)
+}
+
+// Search returns the entries under baseDN matching filter, with the
+// requested attributes.
+func (c *ldapConn) Search(baseDN, filter string, attributes []string) ([]ldapEntry, error) {
+	f, err := compileLDAPFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := berSequence(berTagSequence)
+	for _, a := range attributes {
+		attrs.children = append(attrs.children, berOctetString(berTagOctetString, a))
+	}
+	req := berSequence(ldapSearchRequest,
+		berOctetString(berTagOctetString, baseDN),
+		berInt(berTagEnumerated, ldapScopeWholeSubtree),
+		berInt(berTagEnumerated, 0 /* neverDerefAliases */),
+		berInt(berTagInteger, 0 /* sizeLimit */),
+		berInt(berTagInteger, int64(c.timeout/time.Second) /* timeLimit */),
+		berBool(berTagBoolean, false /* typesOnly */),
+		f,
+		attrs,
+	)
+	if err := c.send(req); err != nil {
+		return nil, err
+	}
+	var entries []ldapEntry
+	for {
+		res, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+		switch res.tag {
+		case ldapSearchResultEntry:
+			e, err := decodeLDAPEntry(res)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		case ldapSearchResultRef:
+			// Referrals to other servers are not followed.
+		case ldapSearchResultDone:
+			if err := checkResult("search", res); err != nil {
+				return nil, err
+			}
+			return entries, nil
+		default:
+			return nil, errors.Newf("unexpected LDAP response with tag 0x%02x to search", res.tag)
+		}
+	}
+}
+
+func decodeLDAPEntry(p *berPacket) (ldapEntry, error) {
+	e := ldapEntry{attributes: map[string][]string{}}
+	if len(p.children) != 2 {
+		return e, errors.New("malformed LDAP search result entry")
+	}
+	e.dn = p.children[0].str()
+	for _, attr := range p.children[1].children {
+		if len(attr.children) != 2 {
+			return e, errors.New("malformed LDAP search result attribute")
+		}
+		name := strings.ToLower(attr.children[0].str())
+		for _, v := range attr.children[1].children {
+			e.attributes[name] = append(e.attributes[name], v.str())
+		}
+	}
+	return e, nil
+}
+
+// Filter choices, see RFC 4511, section 4.5.1.
+const (
+	ldapFilterAnd            = berClassContext | berConstructed | 0
+	ldapFilterOr             = berClassContext | berConstructed | 1
+	ldapFilterNot            = berClassContext | berConstructed | 2
+	ldapFilterEqualityMatch  = berClassContext | berConstructed | 3
+	ldapFilterSubstrings     = berClassContext | berConstructed | 4
+	ldapFilterGreaterOrEqual = berClassContext | berConstructed | 5
+	ldapFilterLessOrEqual    = berClassContext | berConstructed | 6
+	ldapFilterPresent        = berClassContext | 7
+	ldapFilterApproxMatch    = berClassContext | berConstructed | 8
+)
+
+// Substring filter components.
+const (
+	ldapSubstringInitial = berClassContext | 0
+	ldapSubstringAny     = berClassContext | 1
+	ldapSubstringFinal   = berClassContext | 2
+)
+
+// compileLDAPFilter converts the string representation of a search filter
+// (RFC 4515) to its BER encoding. Extensible matches are not supported.
+func compileLDAPFilter(filter string) (*berPacket, error) {
+	p, rest, err := parseLDAPFilter(filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid LDAP filter %q", filter)
+	}
+	if rest != "" {
+		return nil, errors.Newf("invalid LDAP filter %q: unexpected trailing characters", filter)
+	}
+	return p, nil
+}
+
+func parseLDAPFilter(s string) (*berPacket, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", errors.New("expected (")
+	}
+	s = s[1:]
+	var p *berPacket
+	var err error
+	switch {
+	case strings.HasPrefix(s, "&"), strings.HasPrefix(s, "|"):
+		tag := byte(ldapFilterAnd)
+		if s[0] == '|' {
+			tag = ldapFilterOr
+		}
+		p = berSequence(tag)
+		s = s[1:]
+		for strings.HasPrefix(s, "(") {
+			var c *berPacket
+			if c, s, err = parseLDAPFilter(s); err != nil {
+				return nil, "", err
+			}
+			p.children = append(p.children, c)
+		}
+		if len(p.children) == 0 {
+			return nil, "", errors.New("empty filter list")
+		}
+	case strings.HasPrefix(s, "!"):
+		var c *berPacket
+		if c, s, err = parseLDAPFilter(s[1:]); err != nil {
+			return nil, "", err
+		}
+		p = berSequence(ldapFilterNot, c)
+	default:
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return nil, "", errors.New("expected )")
+		}
+		if p, err = parseLDAPFilterItem(s[:end]); err != nil {
+			return nil, "", err
+		}
+		s = s[end:]
+	}
+	if !strings.HasPrefix(s, ")") {
+		return nil, "", errors.New("expected )")
+	}
+	return p, s[1:], nil
+}
+
+func parseLDAPFilterItem(item string) (*berPacket, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, errors.Newf("invalid filter item %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+	tag := byte(ldapFilterEqualityMatch)
+	switch attr[len(attr)-1] {
+	case '>':
+		tag = ldapFilterGreaterOrEqual
+	case '<':
+		tag = ldapFilterLessOrEqual
+	case '~':
+		tag = ldapFilterApproxMatch
+	case ':':
+		return nil, errors.New("extensible match filters are not supported")
+	}
+	if tag != ldapFilterEqualityMatch {
+		attr = attr[:len(attr)-1]
+	}
+	if attr == "" {
+		return nil, errors.Newf("invalid filter item %q", item)
+	}
+	if tag == ldapFilterEqualityMatch && strings.Contains(value, "*") {
+		if value == "*" {
+			return berOctetString(ldapFilterPresent, attr), nil
+		}
+		parts := strings.Split(value, "*")
+		subs := berSequence(berTagSequence)
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeLDAPFilterValue(part)
+			if err != nil {
+				return nil, err
+			}
+			subTag := byte(ldapSubstringAny)
+			if i == 0 {
+				subTag = ldapSubstringInitial
+			} else if i == len(parts)-1 {
+				subTag = ldapSubstringFinal
+			}
+			subs.children = append(subs.children, berOctetString(subTag, v))
+		}
+		return berSequence(ldapFilterSubstrings, berOctetString(berTagOctetString, attr), subs), nil
+	}
+	v, err := unescapeLDAPFilterValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return berSequence(tag, berOctetString(berTagOctetString, attr), berOctetString(berTagOctetString, v)), nil
+}
+
+// unescapeLDAPFilterValue decodes the \XX escapes of a filter value.
+func unescapeLDAPFilterValue(v string) (string, error) {
+	if !strings.Contains(v, `\`) {
+		return v, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' {
+			sb.WriteByte(v[i])
+			continue
+		}
+		if i+3 > len(v) {
+			return "", errors.Newf("invalid escape in filter value %q", v)
+		}
+		b, err := hex.DecodeString(v[i+1 : i+3])
+		if err != nil {
+			return "", errors.Newf("invalid escape in filter value %q", v)
+		}
+		sb.Write(b)
+		i += 2
+	}
+	return sb.String(), nil
+}
+
+// escapeLDAPFilterValue escapes the characters of v which have a special
+// meaning in search filters, see RFC 4515, section 3.
+func escapeLDAPFilterValue(v string) string {
+	var sb strings.Builder
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&sb, `\%02x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
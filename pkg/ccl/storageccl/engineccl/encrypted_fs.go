@@ -109,6 +109,11 @@ type encryptedFS struct {
 	vfs.FS
 	fileRegistry  *storage.PebbleFileRegistry
 	streamCreator *FileCipherStreamCreator
+
+	// reencryptMu is held exclusively while a re-encrypted file replaces the
+	// original one, and shared by operations on existing files. See
+	// reencrypt.go.
+	reencryptMu syncutil.RWMutex
 }
 
 // Create implements vfs.FS.Create.
@@ -137,6 +142,8 @@ func (fs *encryptedFS) Create(name string) (vfs.File, error) {
 
 // Link implements vfs.FS.Link.
 func (fs *encryptedFS) Link(oldname, newname string) error {
+	fs.reencryptMu.RLock()
+	defer fs.reencryptMu.RUnlock()
 	if err := fs.FS.Link(oldname, newname); err != nil {
 		return err
 	}
@@ -145,6 +152,8 @@ func (fs *encryptedFS) Link(oldname, newname string) error {
 
 // Open implements vfs.FS.Open.
 func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	fs.reencryptMu.RLock()
+	defer fs.reencryptMu.RUnlock()
 	f, err := fs.FS.Open(name, opts...)
 	if err != nil {
 		return f, err
@@ -173,6 +182,13 @@ func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, erro
 
 // Remove implements vfs.FS.Remove.
 func (fs *encryptedFS) Remove(name string) error {
+	fs.reencryptMu.RLock()
+	defer fs.reencryptMu.RUnlock()
+	return fs.removeLocked(name)
+}
+
+// removeLocked is like Remove, but expects reencryptMu to be held.
+func (fs *encryptedFS) removeLocked(name string) error {
 	if err := fs.FS.Remove(name); err != nil {
 		return err
 	}
@@ -181,6 +197,8 @@ func (fs *encryptedFS) Remove(name string) error {
 
 // Rename implements vfs.FS.Rename.
 func (fs *encryptedFS) Rename(oldname, newname string) error {
+	fs.reencryptMu.RLock()
+	defer fs.reencryptMu.RUnlock()
 	if err := fs.FS.Rename(oldname, newname); err != nil {
 		return err
 	}
@@ -205,6 +223,7 @@ func (fs *encryptedFS) ReuseForWrite(oldname, newname string) (vfs.File, error)
 type encryptionStatsHandler struct {
 	storeKM *StoreKeyManager
 	dataKM  *DataKeyManager
+	dataFS  *encryptedFS
 }
 
 func (e *encryptionStatsHandler) GetEncryptionStatus() ([]byte, error) {
@@ -314,6 +333,11 @@ func newEncryptedEnv(
 		if err := dataKeyManager.SetActiveStoreKeyInfo(context.TODO(), key.Info); err != nil {
 			return nil, nil, err
 		}
+		if err := dataFS.recoverReencryption(dbDir); err != nil {
+			return nil, nil, err
+		}
 	}
-	return dataFS, &encryptionStatsHandler{storeKM: storeKeyManager, dataKM: dataKeyManager}, nil
+	return dataFS, &encryptionStatsHandler{
+		storeKM: storeKeyManager, dataKM: dataKeyManager, dataFS: dataFS,
+	}, nil
 }
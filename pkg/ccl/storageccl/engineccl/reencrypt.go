@@ -0,0 +1,224 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/cockroachdb/pebble/vfs"
+	"golang.org/x/time/rate"
+)
+
+// Re-encryption rewrites a file encrypted with a retired data key under the
+// active data key, so that the retired key no longer protects any data.
+//
+// The file is copied into a temporary file, named after the original with
+// reencryptSuffix appended, which is created under the active key. The
+// registry entry of the temporary file is written before the file itself is
+// created. Once the copy is synced, the registry entry of the temporary file
+// replaces that of the original file in a single registry update, which is
+// the commit point of the re-encryption, and the temporary file is renamed
+// over the original. Hence, after a crash, a temporary file that still has a
+// registry entry belongs to an uncommitted re-encryption and is removed,
+// while one without an entry belongs to a committed re-encryption and is
+// renamed over the original.
+
+// reencryptSuffix is appended to the name of a file to obtain the name of the
+// temporary file it is re-encrypted into.
+const reencryptSuffix = ".reencrypt"
+
+// reencryptChunkSize is the size of the chunks in which files are copied when
+// they are re-encrypted.
+const reencryptChunkSize = 128 << 10
+
+// reencrypt rewrites the named file under the active key of the FS, waiting on
+// the limiter, if any, for the bytes it writes. It returns the number of bytes
+// rewritten, which is zero if the file was removed in the meantime.
+func (fs *encryptedFS) reencrypt(
+	ctx context.Context, name string, limiter *rate.Limiter,
+) (int64, error) {
+	src, err := fs.Open(name)
+	if err != nil {
+		if oserror.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer src.Close()
+
+	tmpName := name + reencryptSuffix
+	dst, err := fs.createReencryptTemp(ctx, tmpName)
+	if err != nil {
+		return 0, err
+	}
+	n, err := copyWithLimiter(ctx, dst, src, limiter)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, errors.CombineErrors(err, fs.Remove(tmpName))
+	}
+
+	// Operations on existing files are blocked while the temporary file
+	// replaces the original, so that they never observe a registry entry that
+	// doesn't match the file contents.
+	fs.reencryptMu.Lock()
+	defer fs.reencryptMu.Unlock()
+	if _, err := fs.FS.Stat(name); err != nil {
+		removeErr := fs.removeLocked(tmpName)
+		if oserror.IsNotExist(err) {
+			// The file was removed while it was being copied.
+			return 0, removeErr
+		}
+		return 0, errors.CombineErrors(err, removeErr)
+	}
+	oldEntry := fs.fileRegistry.GetFileEntry(name)
+	newEntry := fs.fileRegistry.GetFileEntry(tmpName)
+	if err := fs.fileRegistry.MaybeRenameEntry(tmpName, name); err != nil {
+		return 0, errors.CombineErrors(err, fs.removeLocked(tmpName))
+	}
+	if err := fs.FS.Rename(tmpName, name); err != nil {
+		// Roll back the registry, so that the original file can be read again.
+		if rollbackErr := fs.fileRegistry.SetFileEntry(tmpName, newEntry); rollbackErr != nil {
+			return 0, errors.CombineErrors(err, rollbackErr)
+		}
+		var rollbackErr error
+		if oldEntry != nil {
+			rollbackErr = fs.fileRegistry.SetFileEntry(name, oldEntry)
+		} else {
+			rollbackErr = fs.fileRegistry.MaybeDeleteEntry(name)
+		}
+		if rollbackErr == nil {
+			rollbackErr = fs.removeLocked(tmpName)
+		}
+		return 0, errors.CombineErrors(err, rollbackErr)
+	}
+	return n, syncDir(fs.FS, fs.FS.PathDir(name))
+}
+
+// createReencryptTemp creates the temporary file that a file is re-encrypted
+// into. Unlike Create, it writes the registry entry of the file before
+// creating it; see the comment at the top of this file.
+func (fs *encryptedFS) createReencryptTemp(ctx context.Context, name string) (vfs.File, error) {
+	settings, stream, err := fs.streamCreator.CreateNew(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fproto := &enginepb.FileEntry{}
+	fproto.EnvType = fs.streamCreator.envType
+	if fproto.EncryptionSettings, err = protoutil.Marshal(settings); err != nil {
+		return nil, err
+	}
+	if err := fs.fileRegistry.SetFileEntry(name, fproto); err != nil {
+		return nil, err
+	}
+	f, err := fs.FS.Create(name)
+	if err != nil {
+		return nil, errors.CombineErrors(err, fs.fileRegistry.MaybeDeleteEntry(name))
+	}
+	return &encryptedFile{File: f, stream: stream}, nil
+}
+
+// recoverReencryption completes or rolls back the re-encryptions of files in
+// dir that were interrupted by a crash. It must be called before the FS is
+// used.
+func (fs *encryptedFS) recoverReencryption(dir string) error {
+	names, err := fs.FS.List(dir)
+	if err != nil {
+		if oserror.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var recovered bool
+	for _, tmpName := range names {
+		if !strings.HasSuffix(tmpName, reencryptSuffix) {
+			continue
+		}
+		tmpName = fs.FS.PathJoin(dir, tmpName)
+		if fs.fileRegistry.GetFileEntry(tmpName) != nil {
+			// The re-encryption wasn't committed, and the original file is
+			// intact.
+			if err := fs.Remove(tmpName); err != nil {
+				return err
+			}
+			continue
+		}
+		// The registry entry of the original file describes the temporary file.
+		if err := fs.FS.Rename(tmpName, strings.TrimSuffix(tmpName, reencryptSuffix)); err != nil {
+			return err
+		}
+		recovered = true
+	}
+	if !recovered {
+		return nil
+	}
+	return syncDir(fs.FS, dir)
+}
+
+// copyWithLimiter copies src to dst, waiting on the limiter, if any, for every
+// chunk it writes. It returns the number of bytes copied.
+func copyWithLimiter(
+	ctx context.Context, dst io.Writer, src io.Reader, limiter *rate.Limiter,
+) (int64, error) {
+	chunkSize := reencryptChunkSize
+	if limiter != nil && limiter.Burst() > 0 && limiter.Burst() < chunkSize {
+		chunkSize = limiter.Burst()
+	}
+	buf := make([]byte, chunkSize)
+	var written int64
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if limiter != nil {
+				if err := limiter.WaitN(ctx, n); err != nil {
+					return written, err
+				}
+			}
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return written, err
+			}
+			written += int64(n)
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// syncDir syncs the directory, which makes renames of its files durable.
+func syncDir(fs vfs.FS, dir string) error {
+	d, err := fs.OpenDir(dir)
+	if err != nil {
+		return err
+	}
+	return errors.CombineErrors(d.Sync(), d.Close())
+}
+
+// ReencryptFile implements the storage.DataFileReencrypter interface.
+func (e *encryptionStatsHandler) ReencryptFile(
+	ctx context.Context, filename string, limiter *rate.Limiter,
+) (int64, error) {
+	return e.dataFS.reencrypt(ctx, filename, limiter)
+}
+
+var _ storage.DataFileReencrypter = &encryptionStatsHandler{}
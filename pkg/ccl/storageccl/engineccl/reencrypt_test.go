@@ -0,0 +1,147 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package engineccl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// TestPebbleReencryption writes an sstable, rotates the store key, which
+// retires the active data key, and verifies that re-encryption rewrites the
+// sstable under the new data key, and that re-encryptions interrupted by a
+// crash are recovered from.
+func TestPebbleReencryption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	memFS := vfs.NewMem()
+	writeToFile(t, memFS, "16.key", []byte(keyFile128))
+	writeToFile(t, memFS, "32.key", []byte(keyFile256))
+
+	open := func(currentKey, oldKey string) *storage.Pebble {
+		var encOptions baseccl.EncryptionOptions
+		encOptions.KeySource = baseccl.EncryptionKeySource_KeyFiles
+		encOptions.KeyFiles = &baseccl.EncryptionKeyFiles{
+			CurrentKey: currentKey,
+			OldKey:     oldKey,
+		}
+		encOptions.DataKeyRotationPeriod = 1000 // arbitrary seconds
+		encOptionsBytes, err := protoutil.Marshal(&encOptions)
+		require.NoError(t, err)
+
+		opts := storage.DefaultPebbleOptions()
+		opts.Cache = pebble.NewCache(1 << 20)
+		defer opts.Cache.Unref()
+		opts.FS = memFS
+		db, err := storage.NewPebble(ctx, storage.PebbleConfig{
+			StorageConfig: base.StorageConfig{
+				Attrs:           roachpb.Attributes{},
+				MaxSize:         512 << 20,
+				UseFileRegistry: true,
+				ExtraOptions:    encOptionsBytes,
+			},
+			Opts: opts,
+		})
+		require.NoError(t, err)
+		return db
+	}
+	requireValue := func(db *storage.Pebble) {
+		val, err := db.MVCCGet(storage.MVCCKey{Key: roachpb.Key("a")})
+		require.NoError(t, err)
+		require.Equal(t, "a", string(val))
+	}
+	// retiredKeyBytes returns the size of the sstables encrypted with a key
+	// other than the active data key.
+	retiredKeyBytes := func(db *storage.Pebble) uint64 {
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		var bytes uint64
+		for _, u := range stats.DataKeyUsage {
+			bytes += u.Bytes
+		}
+		return bytes - stats.ActiveKeyBytes
+	}
+	listSSTables := func() []string {
+		names, err := memFS.List("")
+		require.NoError(t, err)
+		var ssts []string
+		for _, name := range names {
+			if strings.HasSuffix(name, ".sst") {
+				ssts = append(ssts, name)
+			}
+		}
+		return ssts
+	}
+
+	db := open("16.key", "plain")
+	batch := db.NewWriteOnlyBatch()
+	require.NoError(t, batch.PutUnversioned(roachpb.Key("a"), []byte("a")))
+	require.NoError(t, batch.Commit(true))
+	require.NoError(t, db.Flush())
+	require.Zero(t, retiredKeyBytes(db))
+	db.Close()
+
+	// Rotating the store key rotates the data key, which retires the key the
+	// sstable is encrypted with.
+	db = open("32.key", "16.key")
+	require.NotZero(t, retiredKeyBytes(db))
+	files, bytes, err := db.ReencryptDataFiles(ctx, rate.NewLimiter(rate.Inf, 1<<10))
+	require.NoError(t, err)
+	require.Equal(t, 1, files)
+	require.NotZero(t, bytes)
+	require.Zero(t, retiredKeyBytes(db))
+	requireValue(db)
+
+	// Nothing is left to re-encrypt.
+	files, _, err = db.ReencryptDataFiles(ctx, nil /* limiter */)
+	require.NoError(t, err)
+	require.Zero(t, files)
+	db.Close()
+
+	ssts := listSSTables()
+	require.Len(t, ssts, 1)
+	sst := ssts[0]
+	tmp := sst + reencryptSuffix
+	fr := &storage.PebbleFileRegistry{FS: memFS}
+	require.NoError(t, fr.Load())
+
+	// A temporary file with a registry entry belongs to an uncommitted
+	// re-encryption, and is removed.
+	writeToFile(t, memFS, tmp, []byte("garbage"))
+	require.NoError(t, fr.SetFileEntry(tmp, &enginepb.FileEntry{EnvType: enginepb.EnvType_Data}))
+	db = open("32.key", "16.key")
+	_, err = memFS.Stat(tmp)
+	require.Error(t, err)
+	requireValue(db)
+	db.Close()
+
+	// A temporary file without a registry entry belongs to a committed
+	// re-encryption, and replaces the original file.
+	require.NoError(t, memFS.Rename(sst, tmp))
+	db = open("32.key", "16.key")
+	require.Equal(t, []string{sst}, listSSTables())
+	requireValue(db)
+	db.Close()
+}
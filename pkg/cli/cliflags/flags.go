@@ -802,6 +802,16 @@ for example:
   --store=path=/mnt/ssd01,size=0.2             -> 20% of available space
   --store=path=/mnt/ssd01,size=.2              -> 20% of available space
 
+</PRE>
+The "raft-log-path" field places the store's raft log in a separate storage
+engine at the given directory, which may reside on a different device than
+the rest of the store's data. Existing stores move their raft log into the
+new engine when they next start. Once a store has been started with this
+field, it must continue to be specified with the same path, for example:
+<PRE>
+
+  --store=path=/mnt/hda1,raft-log-path=/mnt/ssd01/raft
+
 </PRE>
 For an in-memory store, the "type" and "size" fields are required, and the
 "path" field is forbidden. The "type" field must be set to "mem", and the
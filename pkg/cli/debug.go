@@ -35,8 +35,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
-	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
@@ -1045,58 +1045,12 @@ func removeDeadReplicas(
 		// will individual values stored in the meta ranges diverge, but
 		// there will be keys not represented by any ranges or vice
 		// versa).
-		key := keys.RangeDescriptorKey(desc.StartKey)
-		sl := stateloader.Make(desc.RangeID)
-		ms, err := sl.LoadMVCCStats(ctx, batch)
-		if err != nil {
-			return nil, errors.Wrap(err, "loading MVCCStats")
-		}
-		err = storage.MVCCPutProto(ctx, batch, &ms, key, clock.Now(), nil /* txn */, &desc)
-		if wiErr := (*roachpb.WriteIntentError)(nil); errors.As(err, &wiErr) {
-			if len(wiErr.Intents) != 1 {
-				return nil, errors.Errorf("expected 1 intent, found %d: %s", len(wiErr.Intents), wiErr)
-			}
-			intent := wiErr.Intents[0]
-			// We rely on the property that transactions involving the range
-			// descriptor always start on the range-local descriptor's key.
-			// This guarantees that when the transaction commits, the intent
-			// will be resolved synchronously. If we see an intent on this
-			// key, we know that the transaction did not commit and we can
-			// abort it.
-			//
-			// TODO(nvanbenschoten): This need updating for parallel
-			// commits. If the transaction record is in the STAGING state,
-			// we can't just delete it. Simplest solution to this is to
-			// avoid parallel commits for membership change transactions; if
-			// we can't do that I don't think we'll be able to recover them
-			// with an offline tool.
-			fmt.Printf("Conflicting intent found on %s. Aborting txn %s to resolve.\n", key, intent.Txn.ID)
-
-			// A crude form of the intent resolution process: abort the
-			// transaction by deleting its record.
-			txnKey := keys.TransactionKey(intent.Txn.Key, intent.Txn.ID)
-			if err := storage.MVCCDelete(ctx, batch, &ms, txnKey, hlc.Timestamp{}, nil); err != nil {
-				return nil, err
-			}
-			update := roachpb.LockUpdate{
-				Span:   roachpb.Span{Key: intent.Key},
-				Txn:    intent.Txn,
-				Status: roachpb.ABORTED,
-			}
-			if _, err := storage.MVCCResolveWriteIntent(ctx, batch, &ms, update); err != nil {
-				return nil, err
-			}
-			// With the intent resolved, we can try again.
-			if err := storage.MVCCPutProto(ctx, batch, &ms, key, clock.Now(),
-				nil /* txn */, &desc); err != nil {
-				return nil, err
-			}
-		} else if err != nil {
+		//
+		// Intents left on the descriptor by a transaction that can no longer
+		// commit are aborted, see loqrecovery.WriteRangeDescriptor.
+		if err := loqrecovery.WriteRangeDescriptor(ctx, batch, clock, &desc); err != nil {
 			batch.Close()
-			return nil, err
-		}
-		if err := sl.SetMVCCStats(ctx, batch, &ms); err != nil {
-			return nil, errors.Wrap(err, "updating MVCCStats")
+			return nil, errors.Wrapf(err, "r%d", desc.RangeID)
 		}
 	}
 
@@ -1180,6 +1134,7 @@ var debugCmds = append(DebugCmdsForRocksDB,
 	debugZipCmd,
 	debugMergeLogsCommand,
 	debugResetQuorumCmd,
+	debugRecoverCmd,
 )
 
 // DebugCmd is the root of all debug commands. Exported to allow modification by CCL code.
@@ -1246,6 +1201,24 @@ func init() {
 	f.IntSliceVar(&removeDeadReplicasOpts.deadStoreIDs, "dead-store-ids", nil,
 		"list of dead store IDs")
 
+	debugRecoverCmd.AddCommand(debugRecoverCmds...)
+
+	f = debugRecoverCollectInfoCmd.Flags()
+	f.StringSliceVar(&debugRecoverCollectInfoOpts.storeDirs, "store", nil,
+		"path to a store directory of the node to collect replica info from (may be repeated)")
+
+	f = debugRecoverMakePlanCmd.Flags()
+	f.StringVarP(&debugRecoverMakePlanOpts.outputFileName, "plan", "o", "",
+		"filename to write the plan to, stdout if not specified")
+	f.IntSliceVar(&debugRecoverMakePlanOpts.deadStoreIDs, "dead-store-ids", nil,
+		"list of dead store IDs, used to check that replica info was collected from all surviving stores")
+	f.BoolVar(&debugRecoverMakePlanOpts.force, "force", false,
+		"write the plan even if the key space covered by the surviving replicas is inconsistent")
+
+	f = debugRecoverApplyPlanCmd.Flags()
+	f.StringSliceVar(&debugRecoverApplyPlanOpts.storeDirs, "store", nil,
+		"path to a store directory of the node to apply the plan to (may be repeated)")
+
 	f = debugMergeLogsCommand.Flags()
 	f.Var(flagutil.Time(&debugMergeLogsOpts.from), "from",
 		"time before which messages should be filtered")
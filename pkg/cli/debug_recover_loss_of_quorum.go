@@ -0,0 +1,287 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+)
+
+var debugRecoverCmd = &cobra.Command{
+	Use:   "recover [command]",
+	Short: "commands to recover unavailable ranges in case of quorum loss",
+	Long: `
+Set of commands to recover unavailable ranges after the permanent loss of a
+majority of their replicas. Recovery is performed offline, with all nodes of
+the cluster stopped, in three steps:
+
+1. Replica information is collected from the stores of every surviving node
+   with 'collect-info'.
+2. The collected information is combined into a recovery plan with
+   'make-plan'. For every range that lost quorum, the plan designates the most
+   up-to-date surviving replica as the sole voter of the range.
+3. The plan is reviewed, and applied to the stores of every surviving node with
+   'apply-plan'.
+
+Once the nodes are restarted, the recovered ranges are up-replicated to restore
+their redundancy.
+
+These commands are UNSAFE and should only be used with the supervision of
+Cockroach Labs support. Writes which were committed, but not applied by the
+chosen replicas are lost, and the recovered data is not guaranteed to be
+consistent. The dead nodes must never rejoin the cluster once a plan has been
+applied.
+`,
+	RunE: usageAndErr,
+}
+
+var debugRecoverCmds = []*cobra.Command{
+	debugRecoverCollectInfoCmd,
+	debugRecoverMakePlanCmd,
+	debugRecoverApplyPlanCmd,
+}
+
+var debugRecoverCollectInfoCmd = &cobra.Command{
+	Use:   "collect-info --store=<store-dir> [destination-file]",
+	Short: "collect replica information from the stores of a node",
+	Long: `
+Collect information about the replicas on the given stores, which must all
+belong to the node this command is run on. The node must be stopped.
+
+The information is written as JSON to the destination file, or to stdout if no
+file is given.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDebugRecoverCollectInfo,
+}
+
+var debugRecoverCollectInfoOpts struct {
+	storeDirs []string
+}
+
+func runDebugRecoverCollectInfo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	var stores []storage.Engine
+	for _, dir := range debugRecoverCollectInfoOpts.storeDirs {
+		db, err := OpenExistingStore(dir, stopper, true /* readOnly */)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open store at %s", dir)
+		}
+		stores = append(stores, db)
+	}
+
+	info, err := loqrecovery.CollectReplicaInfo(ctx, stores)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(args) > 0 {
+		f, err := os.Create(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to create replica info file")
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := loqrecovery.WriteJSON(out, info); err != nil {
+		return errors.Wrap(err, "failed to write replica info")
+	}
+	fmt.Fprintf(os.Stderr, "Collected info about %d replicas.\n", len(info.Replicas))
+	return nil
+}
+
+var debugRecoverMakePlanCmd = &cobra.Command{
+	Use:   "make-plan [replica-files]",
+	Short: "generate a plan to recover ranges that lost quorum",
+	Long: `
+Generate a plan to recover the ranges that lost quorum, from the replica
+information collected from all surviving nodes with 'collect-info'.
+
+Stores which aren't part of the collected information are considered dead. If
+--dead-store-ids is given, it must list every dead store with replicas of the
+ranges that lost quorum; this guards against information missing for some of
+the surviving stores.
+
+The plan is written as JSON to the file given by --plan, or to stdout. Problems
+found in the key space covered by the surviving replicas, which indicate that
+the cluster may not be able to recover, are reported; plans with problems are
+only written with --force.
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDebugRecoverMakePlan,
+}
+
+var debugRecoverMakePlanOpts struct {
+	outputFileName string
+	deadStoreIDs   []int
+	force          bool
+}
+
+func runDebugRecoverMakePlan(cmd *cobra.Command, args []string) error {
+	var nodes []loqrecovery.NodeReplicaInfo
+	for _, filename := range args {
+		var info loqrecovery.NodeReplicaInfo
+		if err := readJSONFile(filename, &info); err != nil {
+			return errors.Wrapf(err, "failed to read replica info file %s", filename)
+		}
+		nodes = append(nodes, info)
+	}
+	var deadStoreIDs []roachpb.StoreID
+	for _, id := range debugRecoverMakePlanOpts.deadStoreIDs {
+		deadStoreIDs = append(deadStoreIDs, roachpb.StoreID(id))
+	}
+
+	plan, problems, err := loqrecovery.PlanReplicas(nodes, deadStoreIDs)
+	if err != nil {
+		return err
+	}
+
+	stderr := os.Stderr
+	for _, update := range plan.Updates {
+		fmt.Fprintf(stderr, "Recovering r%d at %s: replica %d on s%d becomes sole voter %s\n",
+			update.RangeID, update.StartKey, update.OldReplicaID, update.NewReplica.StoreID,
+			update.NewReplica)
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(stderr, "Found %d problems:\n", len(problems))
+		for _, problem := range problems {
+			fmt.Fprintf(stderr, "  %s\n", problem)
+		}
+		if !debugRecoverMakePlanOpts.force {
+			return errors.New("the key space covered by the surviving replicas is inconsistent; " +
+				"use --force to generate a plan regardless")
+		}
+	}
+	if len(plan.Updates) == 0 {
+		fmt.Fprintf(stderr, "No ranges lost quorum, nothing to do.\n")
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	if debugRecoverMakePlanOpts.outputFileName != "" {
+		f, err := os.Create(debugRecoverMakePlanOpts.outputFileName)
+		if err != nil {
+			return errors.Wrap(err, "failed to create plan file")
+		}
+		defer f.Close()
+		out = f
+	}
+	return errors.Wrap(loqrecovery.WriteJSON(out, plan), "failed to write plan")
+}
+
+var debugRecoverApplyPlanCmd = &cobra.Command{
+	Use:   "apply-plan --store=<store-dir> <plan-file>",
+	Short: "apply a recovery plan to the stores of a node",
+	Long: `
+Apply the recovery plan generated by 'make-plan' to the given stores of the
+node this command is run on. The node must be stopped.
+
+The changes are shown and have to be confirmed before they are written. The
+command must be run on every surviving node; nodes which don't hold any
+replicas updated by the plan are left untouched.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDebugRecoverApplyPlan,
+}
+
+var debugRecoverApplyPlanOpts struct {
+	storeDirs []string
+}
+
+func runDebugRecoverApplyPlan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	if len(debugRecoverApplyPlanOpts.storeDirs) == 0 {
+		return errors.New("no stores were provided to apply the plan to")
+	}
+	var plan loqrecovery.ReplicaUpdatePlan
+	if err := readJSONFile(args[0], &plan); err != nil {
+		return errors.Wrapf(err, "failed to read plan file %s", args[0])
+	}
+
+	clock := hlc.NewClock(hlc.UnixNano, 0)
+	var batches []storage.Batch
+	defer func() {
+		for _, b := range batches {
+			b.Close()
+		}
+	}()
+	var numChanges int
+	for _, dir := range debugRecoverApplyPlanOpts.storeDirs {
+		db, err := OpenExistingStore(dir, stopper, false /* readOnly */)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open store at %s", dir)
+		}
+		ident, err := kvserver.ReadStoreIdent(ctx, db)
+		if err != nil {
+			return err
+		}
+		batch := db.NewBatch()
+		batches = append(batches, batch)
+		changes, err := loqrecovery.PrepareUpdateReplicas(ctx, plan, clock, ident.StoreID, batch)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			fmt.Printf("s%d: %s\n", ident.StoreID, change)
+		}
+		numChanges += len(changes)
+	}
+	if numChanges == 0 {
+		fmt.Printf("Nothing to do\n")
+		return nil
+	}
+
+	fmt.Printf("Proceed with the above rewrites? [y/N] ")
+	reader := bufio.NewReader(os.Stdin)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n")
+	if line[0] != 'y' && line[0] != 'Y' {
+		fmt.Printf("Aborting\n")
+		return nil
+	}
+	fmt.Printf("Committing\n")
+	for _, b := range batches {
+		if err := b.Commit(true /* sync */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readJSONFile(filename string, v interface{}) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return loqrecovery.ReadJSON(f, v)
+}
@@ -204,6 +204,35 @@ const (
 	UniqueWithoutIndexConstraints
 	// VirtualComputedColumns is when virtual computed columns are supported.
 	VirtualComputedColumns
+	// UserDefinedFunctions is when user-defined SQL functions are supported.
+	UserDefinedFunctions
+	// DeferrableConstraints is when DEFERRABLE foreign key and unique constraints are supported.
+	DeferrableConstraints
+	// ExclusionConstraints is when EXCLUDE constraints are supported.
+	ExclusionConstraints
+	// RowLevelTriggers is when row-level triggers are supported.
+	RowLevelTriggers
+	// NotificationsTable adds the system.notifications table used by LISTEN and NOTIFY.
+	NotificationsTable
+	// MVCCRangeTombstones is when DeleteRange can write MVCC range tombstones.
+	MVCCRangeTombstones
+	// SharedLocksAndSkipLocked is when Scan and ReverseScan requests can acquire
+	// Shared locks and, along with Get requests, use the SkipLocked wait
+	// policy.
+	SharedLocksAndSkipLocked
+	// CPUBasedRebalancing is when stores report CPU time in their capacity,
+	// allowing load-based rebalancing and splitting on CPU.
+	CPUBasedRebalancing
+	// NonBlockingTransactions is when ranges can be configured to serve
+	// consistent reads from all replicas through the global_reads zone config
+	// attribute, by having writes wait out the closed timestamp lead on commit.
+	NonBlockingTransactions
+	// SCRAMAuthentication is when passwords can be stored as SCRAM-SHA-256
+	// verifiers, which nodes at older versions cannot verify.
+	SCRAMAuthentication
+	// ParquetFormat is when EXPORT and cloud storage changefeeds can write
+	// Parquet files, which nodes at older versions would write as CSV or reject.
+	ParquetFormat
 
 	// Step (1): Add new versions here.
 )
@@ -334,6 +363,50 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VirtualComputedColumns,
 		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 8},
 	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 10},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 12},
+	},
+	{
+		Key:     ExclusionConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 14},
+	},
+	{
+		Key:     RowLevelTriggers,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 16},
+	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 18},
+	},
+	{
+		Key:     MVCCRangeTombstones,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 20},
+	},
+	{
+		Key:     SharedLocksAndSkipLocked,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 22},
+	},
+	{
+		Key:     CPUBasedRebalancing,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 24},
+	},
+	{
+		Key:     NonBlockingTransactions,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 26},
+	},
+	{
+		Key:     SCRAMAuthentication,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 28},
+	},
+	{
+		Key:     ParquetFormat,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 30},
+	},
 
 	// Step (2): Add new versions here.
 })
@@ -51,15 +51,6 @@ func registerTPCDSVec(r *testRegistry) {
 		75: true,
 		81: true,
 		95: true,
-
-		// These queries contain unsupported function 'rollup' (#46280).
-		5:  true,
-		14: true,
-		18: true,
-		22: true,
-		67: true,
-		77: true,
-		80: true,
 	}
 
 	queriesToSkip20_1 := map[int]bool{
@@ -395,6 +395,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.InheritedLeasePreferences = false
 		}
 	}
+	if z.GlobalReads == nil {
+		if parent.GlobalReads != nil {
+			z.GlobalReads = proto.Bool(*parent.GlobalReads)
+		}
+	}
 }
 
 // CopyFromZone copies over the specified fields from the other zone.
@@ -433,6 +438,12 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
 		}
+		if fieldName == "global_reads" {
+			z.GlobalReads = nil
+			if other.GlobalReads != nil {
+				z.GlobalReads = proto.Bool(*other.GlobalReads)
+			}
+		}
 	}
 }
 
@@ -400,6 +400,7 @@ func TestZoneConfigMarshalYAML(t *testing.T) {
 	testCases := []struct {
 		constraints      []ConstraintsConjunction
 		leasePreferences []LeasePreference
+		globalReads      *bool
 		expected         string
 	}{
 		{
@@ -629,6 +630,18 @@ gc:
 num_replicas: 1
 constraints: [+duck=foo]
 lease_preferences: [[+duck=bar1, +duck=bar2], [-duck=foo]]
+`,
+		},
+		{
+			globalReads: proto.Bool(true),
+			expected: `range_min_bytes: 1
+range_max_bytes: 1
+gc:
+  ttlseconds: 1
+num_replicas: 1
+constraints: []
+lease_preferences: []
+global_reads: true
 `,
 		},
 	}
@@ -637,6 +650,7 @@ lease_preferences: [[+duck=bar1, +duck=bar2], [-duck=foo]]
 		t.Run("", func(t *testing.T) {
 			original.Constraints = tc.constraints
 			original.LeasePreferences = tc.leasePreferences
+			original.GlobalReads = tc.globalReads
 			body, err := yaml.Marshal(original)
 			if err != nil {
 				t.Fatal(err)
@@ -206,6 +206,7 @@ type marshalableZoneConfig struct {
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
 	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	GlobalReads                  *bool             `json:"global_reads,omitempty" yaml:"global_reads,omitempty"`
 	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
 	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
@@ -229,6 +230,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if !c.InheritedLeasePreferences {
 		m.LeasePreferences = c.LeasePreferences
 	}
+	if c.GlobalReads != nil {
+		m.GlobalReads = proto.Bool(*c.GlobalReads)
+	}
 	// We intentionally do not round-trip ExperimentalLeasePreferences. We never
 	// want to return yaml containing it.
 	m.Subzones = c.Subzones
@@ -271,6 +275,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.LeasePreferences != nil || m.ExperimentalLeasePreferences != nil {
 		c.InheritedLeasePreferences = false
 	}
+	if m.GlobalReads != nil {
+		c.GlobalReads = proto.Bool(*m.GlobalReads)
+	}
 	c.Subzones = m.Subzones
 	c.SubzoneSpans = m.SubzoneSpans
 	return c
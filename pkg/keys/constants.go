@@ -52,9 +52,9 @@ var (
 	// key suffixes.
 	localSuffixLength = 4
 
-	// There are five types of local key data enumerated below: replicated
-	// range-ID, unreplicated range-ID, range local, range lock, and
-	// store-local keys.
+	// There are six types of local key data enumerated below: replicated
+	// range-ID, unreplicated range-ID, range local, range lock, MVCC range
+	// tombstone, and store-local keys.
 
 	// 1. Replicated Range-ID keys
 	//
@@ -134,6 +134,9 @@ var (
 	// key info, such as the txn ID in the case of a transaction record.
 	LocalRangePrefix = roachpb.Key(makeKey(localPrefix, roachpb.RKey("k")))
 	LocalRangeMax    = LocalRangePrefix.PrefixEnd()
+	// LocalRangeProbeSuffix is the suffix for keys written by the replica
+	// circuit breaker to probe whether a range can replicate writes.
+	LocalRangeProbeSuffix = roachpb.RKey("prbe")
 	// LocalQueueLastProcessedSuffix is the suffix for replica queue state keys.
 	LocalQueueLastProcessedSuffix = roachpb.RKey("qlpt")
 	// LocalRangeDescriptorSuffix is the suffix for keys storing
@@ -169,7 +172,18 @@ var (
 	LockTableSingleKeyEnd = roachpb.Key(
 		makeKey(LocalRangeLockTablePrefix, roachpb.Key(LockTableSingleKeyInfix).PrefixEnd()))
 
-	// 5. Store local keys
+	// 5. MVCC range tombstone keys
+	//
+	// LocalMVCCRangeTombstonePrefix specifies the key prefix for MVCC range
+	// tombstones. It is immediately followed by the start key of the span
+	// deleted by the tombstone. The timestamp of the tombstone is in the
+	// versioned part of the key, and its end key is stored in the value.
+	LocalMVCCRangeTombstonePrefix = roachpb.Key(makeKey(localPrefix, roachpb.RKey("m")))
+	// LocalMVCCRangeTombstoneMax is the exclusive end key of the key range
+	// containing MVCC range tombstones.
+	LocalMVCCRangeTombstoneMax = LocalMVCCRangeTombstonePrefix.PrefixEnd()
+
+	// 6. Store local keys
 	//
 	// localStorePrefix is the prefix identifying per-store data.
 	localStorePrefix = makeKey(localPrefix, roachpb.Key("s"))
@@ -198,6 +212,10 @@ var (
 	// is to allow a restarting node to discover approximately how long it has
 	// been down without needing to retrieve liveness records from the cluster.
 	localStoreLastUpSuffix = []byte("uptm")
+	// localStoreRaftEngineSuffix marks a store whose raft log has been moved
+	// to a separate raft engine. It is written to both the state machine
+	// engine and the raft engine once the migration has completed.
+	localStoreRaftEngineSuffix = []byte("rfte")
 	// localRemovedLeakedRaftEntriesSuffix is DEPRECATED and remains to prevent
 	// reuse.
 	localRemovedLeakedRaftEntriesSuffix = []byte("dlre")
@@ -397,6 +415,7 @@ const (
 	ScheduledJobsTableID                = 37
 	TenantsRangesID                     = 38 // pseudo
 	SqllivenessID                       = 39
+	NotificationsTableID                = 40
 
 	// CommentType is type for system.comments
 	DatabaseCommentType = 0
@@ -156,9 +156,9 @@ package keys
 var _ = [...]interface{}{
 	MinKey,
 
-	// There are five types of local key data enumerated below: replicated
-	// range-ID, unreplicated range-ID, range local, range lock, and
-	// store-local keys.
+	// There are six types of local key data enumerated below: replicated
+	// range-ID, unreplicated range-ID, range local, range lock, MVCC range
+	// tombstone, and store-local keys.
 	// Local keys are constructed using a prefix, an optional infix, and a
 	// suffix. The prefix and infix are used to disambiguate between the four
 	// types of local keys listed above, and determines inter-group ordering.
@@ -171,10 +171,12 @@ var _ = [...]interface{}{
 	// 	  - Range local keys all share `LocalRangePrefix`.
 	// 	  - Range lock (which are also local keys) all share
 	//	  `LocalRangeLockTablePrefix`.
+	//	  - MVCC range tombstones all share `LocalMVCCRangeTombstonePrefix`.
 	//	  - Store keys all share `localStorePrefix`.
 	//
 	// `LocalRangeIDPrefix`, `localRangePrefix`, `LocalRangeLockTablePrefix`,
-	// and `localStorePrefix` all in turn share `localPrefix`. `localPrefix` was
+	// `LocalMVCCRangeTombstonePrefix` and `localStorePrefix` all in turn share
+	// `localPrefix`. `localPrefix` was
 	// chosen arbitrarily. Local keys would work just as well with a different
 	// prefix, like 0xff, or even with a suffix.
 
@@ -205,6 +207,7 @@ var _ = [...]interface{}{
 	//   as a whole. They are replicated and addressable. Typical examples are
 	//   the range descriptor and transaction records. They all share
 	//   `LocalRangePrefix`.
+	RangeProbeKey,         // "prbe"
 	QueueLastProcessedKey, // "qlpt"
 	RangeDescriptorKey,    // "rdsc"
 	TransactionKey,        // "txn-"
@@ -219,7 +222,15 @@ var _ = [...]interface{}{
 	//   separate from (future) range locks.
 	LockTableSingleKey,
 
-	//   5. Store local keys: These contain metadata about an individual store.
+	//   5. MVCC range tombstone keys. An MVCC range tombstone deletes all the
+	//   versions of the global keys in a span at or below its timestamp. The
+	//   tombstones are stored under LocalMVCCRangeTombstonePrefix followed by
+	//   the start key of the span, and are fragmented at range boundaries so
+	//   that each one belongs to the range containing its start key. They are
+	//   replicated but unaddressable.
+	MVCCRangeTombstoneKey,
+
+	//   6. Store local keys: These contain metadata about an individual store.
 	//   They are unreplicated and unaddressable. The typical example is the
 	//   store 'ident' record. They all share `localStorePrefix`.
 	StoreClusterVersionKey, // "cver"
@@ -227,6 +238,7 @@ var _ = [...]interface{}{
 	StoreHLCUpperBoundKey,  // "hlcu"
 	StoreIdentKey,          // "iden"
 	StoreNodeTombstoneKey,  // "ntmb"
+	StoreRaftEngineKey,     // "rfte"
 	StoreLastUpKey,         // "uptm"
 	StoreCachedSettingsKey, // "stng"
 
@@ -70,6 +70,12 @@ func StoreLastUpKey() roachpb.Key {
 	return MakeStoreKey(localStoreLastUpSuffix, nil)
 }
 
+// StoreRaftEngineKey returns the store-local key marking that the store's
+// raft log lives in a separate raft engine.
+func StoreRaftEngineKey() roachpb.Key {
+	return MakeStoreKey(localStoreRaftEngineSuffix, nil)
+}
+
 // StoreHLCUpperBoundKey returns the store-local key for storing an upper bound
 // to the wall time used by HLC.
 func StoreHLCUpperBoundKey() roachpb.Key {
@@ -401,6 +407,12 @@ func TransactionKey(key roachpb.Key, txnID uuid.UUID) roachpb.Key {
 	return MakeRangeKey(rk, LocalTransactionSuffix, roachpb.RKey(txnID.GetBytes()))
 }
 
+// RangeProbeKey returns a range-local key which is written to by the replica
+// circuit breaker to probe whether the range can replicate writes.
+func RangeProbeKey(key roachpb.RKey) roachpb.Key {
+	return MakeRangeKey(key, LocalRangeProbeSuffix, nil)
+}
+
 // QueueLastProcessedKey returns a range-local key for last processed
 // timestamps for the named queue. These keys represent per-range last
 // processed times.
@@ -463,6 +475,39 @@ func DecodeLockTableSingleKey(key roachpb.Key) (lockedKey roachpb.Key, err error
 	return lockedKey, err
 }
 
+// MVCCRangeTombstoneKey returns the key under which the versions of an MVCC
+// range tombstone fragment starting at the given global key are stored. The
+// end key of the fragment is stored in the value. For a span [start, end)
+// the tombstones starting within it are found in
+// [MVCCRangeTombstoneKey(start), MVCCRangeTombstoneKey(end)).
+func MVCCRangeTombstoneKey(key roachpb.Key) roachpb.Key {
+	// The +3 accounts for the bytesMarker and terminator, as in
+	// LockTableSingleKey.
+	buf := make(roachpb.Key, 0, len(LocalMVCCRangeTombstonePrefix)+len(key)+3)
+	buf = append(buf, LocalMVCCRangeTombstonePrefix...)
+	buf = encoding.EncodeBytesAscending(buf, key)
+	return buf
+}
+
+// DecodeMVCCRangeTombstoneKey decodes an MVCC range tombstone key to return
+// the start key of the span deleted by the tombstone.
+func DecodeMVCCRangeTombstoneKey(key roachpb.Key) (startKey roachpb.Key, err error) {
+	if !bytes.HasPrefix(key, LocalMVCCRangeTombstonePrefix) {
+		return nil, errors.Errorf("key %q does not have %q prefix",
+			key, LocalMVCCRangeTombstonePrefix)
+	}
+	b := key[len(LocalMVCCRangeTombstonePrefix):]
+	b, startKey, err = encoding.DecodeBytesAscending(b, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, errors.Errorf("key %q has left-over bytes %d after decoding",
+			key, len(b))
+	}
+	return startKey, nil
+}
+
 // IsLocal performs a cheap check that returns true iff a range-local key is
 // passed, that is, a key for which `Addr` would return a non-identical RKey
 // (or a decoding error).
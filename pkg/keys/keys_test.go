@@ -36,6 +36,7 @@ func TestStoreKeyEncodeDecode(t *testing.T) {
 		{key: StoreClusterVersionKey(), expSuffix: localStoreClusterVersionSuffix, expDetail: nil},
 		{key: StoreLastUpKey(), expSuffix: localStoreLastUpSuffix, expDetail: nil},
 		{key: StoreHLCUpperBoundKey(), expSuffix: localStoreHLCUpperBoundSuffix, expDetail: nil},
+		{key: StoreRaftEngineKey(), expSuffix: localStoreRaftEngineSuffix, expDetail: nil},
 	}
 	for _, test := range testCases {
 		t.Run("", func(t *testing.T) {
@@ -734,3 +735,27 @@ func TestLockTableKeyEncodeDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestMVCCRangeTombstoneKeyEncodeDecode(t *testing.T) {
+	testCases := []struct {
+		key roachpb.Key
+	}{
+		{key: roachpb.Key("foo")},
+		{key: roachpb.Key("a\x00b")},
+		{key: roachpb.Key("")},
+	}
+	for _, test := range testCases {
+		t.Run("", func(t *testing.T) {
+			rtKey := MVCCRangeTombstoneKey(test.key)
+			require.True(t, bytes.HasPrefix(rtKey, LocalMVCCRangeTombstonePrefix))
+			k, err := DecodeMVCCRangeTombstoneKey(rtKey)
+			require.NoError(t, err)
+			require.Equal(t, test.key, k)
+		})
+	}
+	// The encoding preserves the ordering of the start keys.
+	require.True(t, MVCCRangeTombstoneKey(roachpb.Key("a")).Compare(
+		MVCCRangeTombstoneKey(roachpb.Key("a\x00"))) < 0)
+	require.True(t, MVCCRangeTombstoneKey(roachpb.Key("a\xff")).Compare(
+		MVCCRangeTombstoneKey(roachpb.Key("b"))) < 0)
+}
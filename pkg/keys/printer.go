@@ -79,6 +79,8 @@ var (
 				PSFunc: parseUnsupported},
 			{Name: "/Lock", prefix: LocalRangeLockTablePrefix, ppFunc: localRangeLockTablePrint,
 				PSFunc: parseUnsupported},
+			{Name: "/RangeTombstone", prefix: LocalMVCCRangeTombstonePrefix,
+				ppFunc: localMVCCRangeTombstonePrint, PSFunc: parseUnsupported},
 		}},
 		{Name: "/Meta1", start: Meta1Prefix, end: Meta1KeyMax, Entries: []DictEntry{
 			{Name: "", prefix: Meta1Prefix, ppFunc: print,
@@ -181,6 +183,7 @@ var (
 		{name: "RangeDescriptor", suffix: LocalRangeDescriptorSuffix, atEnd: true},
 		{name: "Transaction", suffix: LocalTransactionSuffix, atEnd: false},
 		{name: "QueueLastProcessed", suffix: LocalQueueLastProcessedSuffix, atEnd: false},
+		{name: "RangeProbe", suffix: LocalRangeProbeSuffix, atEnd: true},
 	}
 )
 
@@ -194,6 +197,7 @@ var constSubKeyDict = []struct {
 	{"/nodeTombstone", localStoreNodeTombstoneSuffix},
 	{"/suggestedCompaction", localStoreSuggestedCompactionSuffix},
 	{"/cachedSettings", localStoreCachedSettingsSuffix},
+	{"/raftEngine", localStoreRaftEngineSuffix},
 }
 
 func nodeTombstoneKeyPrint(key roachpb.Key) string {
@@ -546,6 +550,14 @@ func localRangeLockTablePrint(valDirs []encoding.Direction, key roachpb.Key) str
 	return buf.String()
 }
 
+func localMVCCRangeTombstonePrint(valDirs []encoding.Direction, key roachpb.Key) string {
+	b, startKey, err := encoding.DecodeBytesAscending(key, nil)
+	if err != nil || len(b) != 0 {
+		return fmt.Sprintf("/\"%x\"", key)
+	}
+	return lockTablePrintLockedKey(valDirs, startKey, true)
+}
+
 // ErrUglifyUnsupported is returned when UglyPrint doesn't know how to process a
 // key.
 type ErrUglifyUnsupported struct {
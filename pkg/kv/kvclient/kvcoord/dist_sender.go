@@ -127,9 +127,11 @@ var (
 // CanSendToFollower is used by the DistSender to determine if it needs to look
 // up the current lease holder for a request. It is used by the
 // followerreadsccl code to inject logic to check if follower reads are enabled.
+// The globalReadsLead is the lead of the addressed range's closed timestamps
+// over present time if the range is in non-blocking mode, and zero otherwise.
 // By default, without CCL code, this function returns false.
 var CanSendToFollower = func(
-	clusterID uuid.UUID, st *cluster.Settings, ba roachpb.BatchRequest,
+	clusterID uuid.UUID, st *cluster.Settings, globalReadsLead time.Duration, ba roachpb.BatchRequest,
 ) bool {
 	return false
 }
@@ -1792,7 +1794,8 @@ func (ds *DistSender) sendToReplicas(
 
 	// Try the leaseholder first, if the request wants it.
 	{
-		canFollowerRead := (ds.clusterID != nil) && CanSendToFollower(ds.clusterID.Get(), ds.st, ba)
+		canFollowerRead := (ds.clusterID != nil) &&
+			CanSendToFollower(ds.clusterID.Get(), ds.st, desc.GetGlobalReads().Lead, ba)
 		sendToLeaseholder := (leaseholder != nil) && !canFollowerRead && ba.RequiresLeaseHolder()
 		if sendToLeaseholder {
 			idx := replicas.Find(leaseholder.ReplicaID)
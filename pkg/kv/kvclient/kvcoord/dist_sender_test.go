@@ -3431,7 +3431,9 @@ func TestCanSendToFollower(t *testing.T) {
 	old := CanSendToFollower
 	defer func() { CanSendToFollower = old }()
 	canSend := true
-	CanSendToFollower = func(_ uuid.UUID, _ *cluster.Settings, ba roachpb.BatchRequest) bool {
+	CanSendToFollower = func(
+		_ uuid.UUID, _ *cluster.Settings, _ time.Duration, ba roachpb.BatchRequest,
+	) bool {
 		return !ba.IsLocking() && canSend
 	}
 
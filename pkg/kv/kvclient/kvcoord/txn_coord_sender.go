@@ -438,6 +438,9 @@ func (tc *TxnCoordSender) commitReadOnlyTxnLocked(
 	// Also so that the correct metric gets incremented.
 	tc.mu.txn.Status = roachpb.COMMITTED
 	tc.cleanupTxnLocked(ctx)
+	if err := tc.maybeCommitWaitLocked(ctx); err != nil {
+		return roachpb.NewError(err)
+	}
 	return nil
 }
 
@@ -509,6 +512,9 @@ func (tc *TxnCoordSender) Send(
 				tc.mu.txnState = txnFinalized
 				tc.cleanupTxnLocked(ctx)
 				tc.maybeSleepForLinearizable(ctx, br, startNs)
+				if err := tc.maybeCommitWaitLocked(ctx); err != nil {
+					pErr = roachpb.NewError(err)
+				}
 			}
 		} else {
 			// Rollbacks always move us to txnFinalized.
@@ -528,6 +534,33 @@ func (tc *TxnCoordSender) Send(
 	return br, nil
 }
 
+// maybeCommitWaitLocked waits for the commit timestamp of the transaction,
+// which has just committed, to pass if it is a synthetic timestamp. Commit
+// timestamps are synthetic when the transaction wrote to a range in
+// non-blocking mode, which places writes ahead of present time, or read a
+// value written to such a range and moved its timestamp forward because of it.
+//
+// Returning to the client before the local clock has reached the commit
+// timestamp would allow causally dependent transactions to be ordered before
+// this one. Waiting until then ensures that any transaction that starts after
+// this one returns observes its writes, either directly or through its
+// uncertainty interval.
+func (tc *TxnCoordSender) maybeCommitWaitLocked(ctx context.Context) error {
+	commitTS := tc.mu.txn.WriteTimestamp
+	if !commitTS.IsFlagSet(hlc.TimestampFlag_SYNTHETIC) {
+		return nil
+	}
+	before := tc.clock.PhysicalTime()
+	// NB: like maybeSleepForLinearizable, this sleeps with the lock held.
+	if err := tc.clock.SleepUntil(ctx, commitTS); err != nil {
+		return errors.Wrapf(err, "waiting for commit timestamp %s", commitTS)
+	}
+	log.VEventf(ctx, 2, "%v: completed commit-wait in %s", tc.mu.txn.Short(),
+		tc.clock.PhysicalTime().Sub(before))
+	tc.metrics.CommitWaits.Inc(1)
+	return nil
+}
+
 // maybeSleepForLinearizable sleeps if the linearizable flag is set. We want to
 // make sure that all the clocks in the system are past the commit timestamp of
 // the transaction. This is guaranteed if either:
@@ -586,13 +586,12 @@ func (sr *txnSpanRefresher) appendRefreshSpans(
 			errors.Safe(readTimestamp), errors.Safe(sr.refreshedTimestamp), ba)
 	}
 
-	ba.RefreshSpanIterate(br, func(span roachpb.Span) {
+	return ba.RefreshSpanIterate(br, func(span roachpb.Span) {
 		if log.ExpensiveLogEnabled(ctx, 3) {
 			log.VEventf(ctx, 3, "recording span to refresh: %s", span.String())
 		}
 		sr.refreshFootprint.insert(span)
 	})
-	return nil
 }
 
 // canForwardReadTimestampWithoutRefresh returns whether the transaction can
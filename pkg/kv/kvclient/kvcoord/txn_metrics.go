@@ -23,6 +23,7 @@ type TxnMetrics struct {
 	Commits         *metric.Counter
 	Commits1PC      *metric.Counter // Commits which finished in a single phase
 	ParallelCommits *metric.Counter // Commits which entered the STAGING state
+	CommitWaits     *metric.Counter // Commits which waited for their timestamp to pass
 
 	RefreshSuccess                *metric.Counter
 	RefreshFail                   *metric.Counter
@@ -71,6 +72,14 @@ var (
 		Measurement: "KV Transactions",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaCommitWaitRates = metric.Metadata{
+		Name: "txn.commit_waits",
+		Help: "Number of KV transactions that had to commit-wait on commit " +
+			"in order to ensure linearizability. This generally happens to " +
+			"transactions writing to global ranges.",
+		Measurement: "KV Transactions",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRefreshSuccess = metric.Metadata{
 		Name: "txn.refresh.success",
 		Help: "Number of successful transaction refreshes. A refresh may be " +
@@ -206,6 +215,7 @@ func MakeTxnMetrics(histogramWindow time.Duration) TxnMetrics {
 		Aborts:                        metric.NewCounter(metaAbortsRates),
 		Commits:                       metric.NewCounter(metaCommitsRates),
 		Commits1PC:                    metric.NewCounter(metaCommits1PCRates),
+		CommitWaits:                   metric.NewCounter(metaCommitWaitRates),
 		ParallelCommits:               metric.NewCounter(metaParallelCommitsRates),
 		RefreshSuccess:                metric.NewCounter(metaRefreshSuccess),
 		RefreshFail:                   metric.NewCounter(metaRefreshFail),
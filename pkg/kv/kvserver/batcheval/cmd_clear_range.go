@@ -45,6 +45,8 @@ func declareKeysClearRange(
 	// We look up the range descriptor key to check whether the span
 	// is equal to the entire range for fast stats updating.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
+	// Any range tombstones in the span are cleared as well.
+	declareMVCCRangeTombstoneKeys(desc, latchSpans, spanset.SpanReadWrite)
 }
 
 // ClearRange wipes all MVCC versions of keys covered by the specified
@@ -81,6 +83,12 @@ func ClearRange(
 	}
 	cArgs.Stats.Subtract(statsDelta)
 
+	// Remove the range tombstones covering the span, which would otherwise
+	// outlive the keys they shadowed.
+	if err := storage.ClearMVCCRangeTombstones(readWriter, cArgs.Stats, from, to); err != nil {
+		return result.Result{}, err
+	}
+
 	// If the total size of data to be cleared is less than
 	// clearRangeBytesThreshold, clear the individual values manually,
 	// instead of using a range tombstone (inefficient for small ranges).
@@ -141,7 +149,7 @@ func computeStatsDelta(
 	// compute stats across the key span to be cleared.
 	if !fast || util.RaceEnabled {
 		iter := readWriter.NewMVCCIterator(storage.MVCCKeyAndIntentsIterKind, storage.IterOptions{UpperBound: to})
-		computed, err := iter.ComputeStats(from, to, delta.LastUpdateNanos)
+		computed, err := storage.ComputeStatsWithRangeTombstones(readWriter, iter, from, to, delta.LastUpdateNanos)
 		iter.Close()
 		if err != nil {
 			return enginepb.MVCCStats{}, err
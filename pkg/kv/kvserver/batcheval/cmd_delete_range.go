@@ -13,11 +13,15 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -36,6 +40,12 @@ func declareKeysDeleteRange(
 	} else {
 		DefaultDeclareIsolatedKeys(desc, header, req, latchSpans, lockSpans)
 	}
+	if args.UseRangeTombstone {
+		declareMVCCRangeTombstoneKeys(desc, latchSpans, spanset.SpanReadWrite)
+		// We look up the range descriptor key to check whether the span
+		// is equal to the entire range for fast stats updating.
+		latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
+	}
 }
 
 // DeleteRange deletes the range of key/value pairs specified by
@@ -47,6 +57,10 @@ func DeleteRange(
 	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		return result.Result{}, deleteRangeUsingTombstone(ctx, readWriter, cArgs)
+	}
+
 	var timestamp hlc.Timestamp
 	if !args.Inline {
 		timestamp = h.Timestamp
@@ -74,3 +88,32 @@ func DeleteRange(
 	// error is not consumed by the caller because the result will be discarded.
 	return result.FromAcquiredLocks(h.Txn, deleted...), err
 }
+
+// deleteRangeUsingTombstone deletes the span of a DeleteRange request by
+// writing an MVCC range tombstone.
+func deleteRangeUsingTombstone(
+	ctx context.Context, readWriter storage.ReadWriter, cArgs CommandArgs,
+) error {
+	args := cArgs.Args.(*roachpb.DeleteRangeRequest)
+	h := cArgs.Header
+	if h.Txn != nil {
+		return errors.New("cannot delete a range using a range tombstone within a transaction")
+	}
+	if args.Inline || args.ReturnKeys {
+		return errors.New("cannot delete a range using a range tombstone with inline or return_keys")
+	}
+	if !cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) {
+		return errors.New("range tombstones are not supported until the cluster version is finalized")
+	}
+
+	// If the span is the entire range, the stats of the keys being deleted are
+	// those of the range, and we can avoid scanning the span.
+	var msCovered *enginepb.MVCCStats
+	if desc := cArgs.EvalCtx.Desc(); desc.StartKey.Equal(args.Key) && desc.EndKey.Equal(args.EndKey) {
+		ms := cArgs.EvalCtx.GetMVCCStats()
+		msCovered = &ms
+	}
+	return storage.MVCCDeleteRangeUsingTombstone(
+		ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, h.Timestamp, msCovered,
+	)
+}
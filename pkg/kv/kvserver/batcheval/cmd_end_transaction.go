@@ -120,6 +120,11 @@ func declareKeysEndTxn(
 					Key:    keys.MakeRangeKeyPrefix(st.LeftDesc.StartKey),
 					EndKey: keys.MakeRangeKeyPrefix(st.RightDesc.EndKey).PrefixEnd(),
 				})
+				// Range tombstones straddling the split key are split in two.
+				latchSpans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{
+					Key:    keys.MVCCRangeTombstoneKey(st.LeftDesc.StartKey.AsRawKey()),
+					EndKey: keys.MVCCRangeTombstoneKey(st.RightDesc.EndKey.AsRawKey()),
+				})
 
 				leftRangeIDPrefix := keys.MakeRangeIDReplicatedPrefix(header.RangeID)
 				latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
@@ -162,6 +167,21 @@ func declareKeysEndTxn(
 					EndKey: keys.MakeRangeIDReplicatedPrefix(mt.RightDesc.RangeID).PrefixEnd(),
 				})
 			}
+			if et.InternalCommitTrigger.GlobalReadsTrigger != nil {
+				// Changes to the non-blocking mode of a range declare non-MVCC
+				// read access across the entire range to block all concurrent
+				// writes. Every write that is proposed after the trigger must be
+				// evaluated under the new mode, and every write that was
+				// evaluated under the old mode must be proposed before it.
+				latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
+					Key:    desc.StartKey.AsRawKey(),
+					EndKey: desc.EndKey.AsRawKey(),
+				})
+				latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
+					Key:    keys.MakeRangeKeyPrefix(desc.StartKey),
+					EndKey: keys.MakeRangeKeyPrefix(desc.EndKey).PrefixEnd(),
+				})
+			}
 		}
 	}
 }
@@ -645,6 +665,29 @@ func RunCommitTrigger(
 		}
 		return res, nil
 	}
+	if grt := ct.GetGlobalReadsTrigger(); grt != nil {
+		// Followers may have served reads up to the closed timestamp plus the
+		// old lead. If the lead is being lowered, writes need to stay above
+		// anything that may have been read this way, which the floor ensures.
+		oldLead := rec.Desc().GetGlobalReads().Lead
+		if grt.GlobalReads.Lead < oldLead {
+			if minFloor := rec.Clock().Now().Add(oldLead.Nanoseconds(), 0); grt.GlobalReads.Floor.Less(minFloor) {
+				return result.Result{}, errors.Errorf(
+					"global reads floor %s is below minimum floor %s", grt.GlobalReads.Floor, minFloor)
+			}
+		}
+		newDesc := *rec.Desc()
+		if grt.GlobalReads != (roachpb.GlobalReadsState{}) {
+			newDesc.GlobalReads = &grt.GlobalReads
+		} else {
+			newDesc.GlobalReads = nil
+		}
+		var res result.Result
+		res.Replicated.State = &kvserverpb.ReplicaState{
+			Desc: &newDesc,
+		}
+		return res, nil
+	}
 
 	log.Fatalf(ctx, "unknown commit trigger: %+v", ct)
 	return result.Result{}, nil
@@ -821,6 +864,15 @@ func splitTrigger(
 			split.RightDesc.StartKey, split.RightDesc.EndKey, desc)
 	}
 
+	// Split any range tombstone straddling the split key, so that each side
+	// only holds range tombstones within its own bounds. This must happen
+	// before computing the LHS stats below.
+	if err := storage.SplitMVCCRangeTombstones(
+		ctx, batch, &bothDeltaMS, split.RightDesc.StartKey.AsRawKey(),
+	); err != nil {
+		return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to split range tombstones")
+	}
+
 	// Compute the absolute stats for the (post-split) LHS. No more
 	// modifications to it are allowed after this line.
 
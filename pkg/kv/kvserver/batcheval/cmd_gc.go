@@ -46,6 +46,11 @@ func declareKeysGC(
 	// but can avoid declaring these keys below.
 	if !gcr.Threshold.IsEmpty() {
 		latchSpans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
+		// Requests setting the threshold also remove the range tombstones below
+		// it, which requires reading the versions they shadow. Those are below
+		// the threshold, so the read latch doesn't block any writers.
+		declareMVCCRangeTombstoneKeys(desc, latchSpans, spanset.SpanReadWrite)
+		latchSpans.AddMVCC(spanset.SpanReadOnly, desc.RSpan().AsRawSpanWithNoLocals(), gcr.Threshold)
 	}
 	// Needed for Range bounds checks in calls to EvalContext.ContainsKey.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
@@ -54,7 +59,8 @@ func declareKeysGC(
 // GC iterates through the list of keys to garbage collect
 // specified in the arguments. MVCCGarbageCollect is invoked on each
 // listed key along with the expiration timestamp. The GC metadata
-// specified in the args is persisted after GC.
+// specified in the args is persisted after GC, and range tombstones
+// below the GC threshold are removed if they no longer shadow anything.
 func GC(
 	ctx context.Context, readWriter storage.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
 ) (result.Result, error) {
@@ -104,6 +110,15 @@ func GC(
 				GCThreshold: &newThreshold,
 			}
 		}
+
+		// Remove the range tombstones that no longer shadow any versions.
+		span := cArgs.EvalCtx.Desc().RSpan().AsRawSpanWithNoLocals()
+		pointReader := spanset.GetDBEngine(readWriter, span)
+		if err := storage.MVCCGarbageCollectRangeTombstones(
+			ctx, readWriter, pointReader, cArgs.Stats, span.Key, span.EndKey, newThreshold,
+		); err != nil {
+			return result.Result{}, err
+		}
 	}
 
 	return res, nil
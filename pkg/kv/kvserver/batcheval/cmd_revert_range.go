@@ -13,6 +13,7 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -38,9 +39,12 @@ func declareKeysRevertRange(
 	// is equal to the entire range for fast stats updating.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(desc.RangeID)})
+	// Range tombstones written after the target time are removed as well.
+	declareMVCCRangeTombstoneKeys(desc, latchSpans, spanset.SpanReadWrite)
 }
 
-// isEmptyKeyTimeRange checks if the span has no writes in (since,until].
+// isEmptyKeyTimeRange checks if the span has no writes in (since,until],
+// including range tombstones.
 func isEmptyKeyTimeRange(
 	readWriter storage.ReadWriter, from, to roachpb.Key, since, until hlc.Timestamp,
 ) (bool, error) {
@@ -54,13 +58,28 @@ func isEmptyKeyTimeRange(
 	})
 	defer iter.Close()
 	iter.SeekGE(storage.MVCCKey{Key: from})
-	ok, err := iter.Valid()
-	return !ok, err
+	if ok, err := iter.Valid(); ok || err != nil {
+		return false, err
+	}
+	// The span may still have been deleted by a range tombstone in the time
+	// range.
+	tombstones, err := storage.ScanMVCCRangeTombstones(readWriter, from, to)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tombstones {
+		if since.Less(t.Timestamp) && t.Timestamp.LessEq(until) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // RevertRange wipes all MVCC versions more recent than TargetTime (up to the
 // command timestamp) of the keys covered by the specified span, adjusting the
-// MVCC stats accordingly.
+// MVCC stats accordingly. If the span is an entire range none of whose keys
+// were live at TargetTime, they are deleted by an MVCC range tombstone
+// instead, which retains their history.
 //
 // Note: this should only be used when there is no user traffic writing to the
 // target span at or above the target time.
@@ -85,6 +104,13 @@ func RevertRange(
 		return result.Result{}, nil
 	}
 
+	if ok, err := revertRangeUsingTombstone(ctx, readWriter, cArgs); err != nil {
+		return result.Result{}, err
+	} else if ok {
+		log.VEventf(ctx, 2, "deleted keys written after %v using a range tombstone", args.TargetTime)
+		return result.Result{}, nil
+	}
+
 	log.VEventf(ctx, 2, "clearing keys with timestamp (%v, %v]", args.TargetTime, cArgs.Header.Timestamp)
 
 	resume, err := storage.MVCCClearTimeRange(ctx, readWriter, cArgs.Stats, args.Key, args.EndKey,
@@ -114,3 +140,48 @@ func RevertRange(
 
 	return pd, nil
 }
+
+// revertRangeUsingTombstone reverts the span of a RevertRange request by
+// writing an MVCC range tombstone at the request timestamp, if the span is the
+// entire range and held no live keys at the target time. This is the case for
+// the ranges of an empty table which an IMPORT INTO filled with new keys.
+// Unlike clearing the keys written since the target time, this doesn't
+// depend on the number of keys and retains their history. It returns false if
+// the span can't be reverted this way.
+func revertRangeUsingTombstone(
+	ctx context.Context, readWriter storage.ReadWriter, cArgs CommandArgs,
+) (bool, error) {
+	args := cArgs.Args.(*roachpb.RevertRangeRequest)
+	h := cArgs.Header
+	if !cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) {
+		return false, nil
+	}
+	// Restricting this to entire ranges means that the stats of the deleted
+	// keys are those of the range, and that a request resumed after clearing
+	// part of the span is never turned into a range tombstone.
+	desc := cArgs.EvalCtx.Desc()
+	if !desc.StartKey.Equal(args.Key) || !desc.EndKey.Equal(args.EndKey) {
+		return false, nil
+	}
+
+	// The scan is inconsistent so that intents at or below the target time are
+	// returned rather than failing the request; the keys are cleared instead.
+	// Intents above the target time make writing the range tombstone fail, as
+	// they make clearing the keys fail.
+	res, err := storage.MVCCScan(ctx, readWriter, args.Key, args.EndKey, args.TargetTime,
+		storage.MVCCScanOptions{Inconsistent: true, MaxKeys: 1})
+	if err != nil {
+		return false, err
+	}
+	if res.NumKeys > 0 || len(res.Intents) > 0 {
+		return false, nil
+	}
+
+	msCovered := cArgs.EvalCtx.GetMVCCStats()
+	if err := storage.MVCCDeleteRangeUsingTombstone(
+		ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, h.Timestamp, &msCovered,
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -79,6 +80,7 @@ func TestCmdRevertRange(t *testing.T) {
 	const keyCount = 10
 
 	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
 
 	// Run this test on both RocksDB and Pebble. Regression test for:
 	// https://github.com/cockroachdb/cockroach/pull/42386
@@ -135,7 +137,9 @@ func TestCmdRevertRange(t *testing.T) {
 				EndKey:   roachpb.RKey(endKey),
 			}
 			cArgs := CommandArgs{Header: roachpb.Header{RangeID: desc.RangeID, Timestamp: tsC, MaxSpanRequestKeys: 2}}
-			evalCtx := &MockEvalCtx{Desc: &desc, Clock: hlc.NewClock(hlc.UnixNano, time.Nanosecond), Stats: stats}
+			evalCtx := &MockEvalCtx{
+				ClusterSettings: st, Desc: &desc, Clock: hlc.NewClock(hlc.UnixNano, time.Nanosecond), Stats: stats,
+			}
 			cArgs.EvalCtx = evalCtx.EvalContext()
 			afterStats := getStats(t, eng)
 			for _, tc := range []struct {
@@ -209,7 +213,9 @@ func TestCmdRevertRange(t *testing.T) {
 
 			cArgs.Header.Timestamp = tsD
 			// Re-set EvalCtx to pick up revised stats.
-			cArgs.EvalCtx = (&MockEvalCtx{Desc: &desc, Clock: hlc.NewClock(hlc.UnixNano, time.Nanosecond), Stats: stats}).EvalContext()
+			cArgs.EvalCtx = (&MockEvalCtx{
+				ClusterSettings: st, Desc: &desc, Clock: hlc.NewClock(hlc.UnixNano, time.Nanosecond), Stats: stats,
+			}).EvalContext()
 			for _, tc := range []struct {
 				name        string
 				ts          hlc.Timestamp
@@ -262,3 +268,96 @@ func TestCmdRevertRange(t *testing.T) {
 		})
 	}
 }
+
+// TestCmdRevertRangeUsingTombstone verifies that RevertRange deletes the keys
+// of a range using a range tombstone if none of them were live at the target
+// time.
+func TestCmdRevertRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := createTestPebbleEngine(ctx)
+	defer eng.Close()
+
+	startKey := roachpb.Key("0000")
+	endKey := roachpb.Key("9999")
+	targetTime := hlc.Timestamp{WallTime: 100}
+	writeTime := hlc.Timestamp{WallTime: 200}
+	revertTime := hlc.Timestamp{WallTime: 300}
+
+	var stats enginepb.MVCCStats
+	for i := 0; i < 10; i++ {
+		key := roachpb.Key(fmt.Sprintf("%04d", i))
+		var value roachpb.Value
+		value.SetString(fmt.Sprintf("%d", i))
+		if err := storage.MVCCPut(ctx, eng, &stats, key, writeTime, value, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	desc := roachpb.RangeDescriptor{RangeID: 99,
+		StartKey: roachpb.RKey(startKey),
+		EndKey:   roachpb.RKey(endKey),
+	}
+	evalCtx := &MockEvalCtx{
+		ClusterSettings: cluster.MakeTestingClusterSettings(),
+		Desc:            &desc,
+		Clock:           hlc.NewClock(hlc.UnixNano, time.Nanosecond),
+		Stats:           stats,
+	}
+	cArgs := CommandArgs{
+		EvalCtx: evalCtx.EvalContext(),
+		Header:  roachpb.Header{RangeID: desc.RangeID, Timestamp: revertTime, MaxSpanRequestKeys: 2},
+		Args: &roachpb.RevertRangeRequest{
+			RequestHeader: roachpb.RequestHeader{Key: startKey, EndKey: endKey}, TargetTime: targetTime,
+		},
+		Stats: &enginepb.MVCCStats{},
+	}
+	var reply roachpb.RevertRangeResponse
+	if _, err := RevertRange(ctx, eng, cArgs, &reply); err != nil {
+		t.Fatal(err)
+	}
+	// The range tombstone deletes all of the keys at once.
+	if reply.ResumeSpan != nil {
+		t.Fatalf("unexpected resume span %s", reply.ResumeSpan)
+	}
+	tombstones, err := storage.ScanMVCCRangeTombstones(eng, startKey, endKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 1 || tombstones[0].Timestamp != revertTime {
+		t.Fatalf("expected a range tombstone at %s, got %v", revertTime, tombstones)
+	}
+
+	// The keys are deleted, but their history is retained.
+	for _, tc := range []struct {
+		ts      hlc.Timestamp
+		numKeys int64
+	}{
+		{targetTime, 0},
+		{writeTime, 10},
+		{revertTime, 0},
+	} {
+		res, err := storage.MVCCScan(ctx, eng, startKey, endKey, tc.ts, storage.MVCCScanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.NumKeys != tc.numKeys {
+			t.Errorf("expected %d keys at %s, got %d", tc.numKeys, tc.ts, res.NumKeys)
+		}
+	}
+
+	evalStats := stats
+	evalStats.Add(*cArgs.Stats)
+	iter := eng.NewMVCCIterator(storage.MVCCKeyAndIntentsIterKind, storage.IterOptions{UpperBound: roachpb.KeyMax})
+	defer iter.Close()
+	realStats, err := storage.ComputeStatsWithRangeTombstones(eng, iter, roachpb.KeyMin, roachpb.KeyMax, revertTime.WallTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evalStats.AgeTo(revertTime.WallTime)
+	if !evalStats.Equal(realStats) {
+		t.Fatalf("stats mismatch:\nevaled:\t%+v\nactual:\t%+v", evalStats, realStats)
+	}
+}
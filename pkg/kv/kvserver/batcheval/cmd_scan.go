@@ -41,12 +41,16 @@ func Scan(
 
 	opts := storage.MVCCScanOptions{
 		Inconsistent:     h.ReadConsistency != roachpb.CONSISTENT,
+		SkipLocked:       h.WaitPolicy == lock.WaitPolicy_SkipLocked,
 		Txn:              h.Txn,
 		MaxKeys:          h.MaxSpanRequestKeys,
 		TargetBytes:      h.TargetBytes,
 		FailOnMoreRecent: args.KeyLocking != lock.None,
 		Reverse:          false,
 	}
+	if opts.SkipLocked {
+		opts.LockTable = cArgs.Concurrency
+	}
 
 	switch args.ScanFormat {
 	case roachpb.BATCH_RESPONSE:
@@ -87,7 +91,7 @@ func Scan(
 	}
 
 	if args.KeyLocking != lock.None && h.Txn != nil {
-		err = acquireUnreplicatedLocksOnKeys(&res, h.Txn, args.ScanFormat, &scanRes)
+		err = acquireUnreplicatedLocksOnKeys(&res, h.Txn, args.KeyLocking, args.ScanFormat, &scanRes)
 		if err != nil {
 			return result.Result{}, err
 		}
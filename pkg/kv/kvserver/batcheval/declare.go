@@ -14,6 +14,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -104,6 +105,20 @@ func DeclareKeysForBatch(
 	}
 }
 
+// declareMVCCRangeTombstoneKeys declares the keys of all MVCC range tombstones
+// of the range. Range tombstones are stored outside of the keyspace they
+// cover, and writing one may refragment any range tombstone in the range, so
+// commands that write range tombstones declare the entire range tombstone
+// keyspace of the range.
+func declareMVCCRangeTombstoneKeys(
+	desc *roachpb.RangeDescriptor, latchSpans *spanset.SpanSet, access spanset.SpanAccess,
+) {
+	latchSpans.AddNonMVCC(access, roachpb.Span{
+		Key:    keys.MVCCRangeTombstoneKey(desc.StartKey.AsRawKey()),
+		EndKey: keys.MVCCRangeTombstoneKey(desc.EndKey.AsRawKey()),
+	})
+}
+
 // CommandArgs contains all the arguments to a command.
 // TODO(bdarnell): consider merging with kvserverbase.FilterArgs (which
 // would probably require removing the EvalCtx field due to import order
@@ -114,4 +129,8 @@ type CommandArgs struct {
 	Args    roachpb.Request
 	// *Stats should be mutated to reflect any writes made by the command.
 	Stats *enginepb.MVCCStats
+	// Concurrency is the concurrency guard held by the request, if any. It is
+	// used by requests with the SkipLocked wait policy to determine which keys
+	// are locked by conflicting transactions and should be skipped.
+	Concurrency *concurrency.Guard
 }
@@ -42,6 +42,9 @@ type Limiters struct {
 	// is a temporary state at the beginning of a rangefeed which is expensive
 	// because it uses an engine iterator.
 	ConcurrentRangefeedIters limit.ConcurrentRequestLimiter
+	// RangefeedCatchupScanRate limits the rate (in bytes per second) at which
+	// rangefeed catch-up scans read from the engine across the store.
+	RangefeedCatchupScanRate *rate.Limiter
 }
 
 // EvalContext is the interface through which command evaluation accesses the
@@ -90,7 +93,7 @@ type EvalContext interface {
 	//
 	// NOTE: This should not be used when the load based splitting cluster
 	// setting is disabled.
-	GetSplitQPS() float64
+	GetSplitQPS(context.Context) float64
 
 	GetGCThreshold() hlc.Timestamp
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
@@ -193,7 +196,7 @@ func (m *mockEvalCtxImpl) ContainsKey(key roachpb.Key) bool {
 func (m *mockEvalCtxImpl) GetMVCCStats() enginepb.MVCCStats {
 	return m.Stats
 }
-func (m *mockEvalCtxImpl) GetSplitQPS() float64 {
+func (m *mockEvalCtxImpl) GetSplitQPS(context.Context) float64 {
 	return m.QPS
 }
 func (m *mockEvalCtxImpl) CanCreateTxnRecord(
@@ -0,0 +1,75 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicaCircuitBreakerLeaseholderAndQuorumLoss verifies that the circuit
+// breaker of the surviving replica of a range which lost both its leaseholder
+// and quorum trips. The surviving replica attempts to acquire the lease, which
+// can't succeed.
+func TestReplicaCircuitBreakerLeaseholderAndQuorumLoss(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	_, err := tc.ServerConn(0).Exec(
+		`SET CLUSTER SETTING kv.replica_circuit_breaker.slow_replication_threshold = '100ms'`)
+	require.NoError(t, err)
+
+	// The system ranges, including the node liveness range, only have a replica
+	// on n1. The scratch range is replicated to n2 and n3, and its lease is
+	// transferred to n2.
+	key := tc.ScratchRange(t)
+	desc := tc.AddVotersOrFatal(t, key, tc.Targets(1, 2)...)
+	tc.TransferRangeLeaseOrFatal(t, desc, tc.Target(1))
+
+	tc.StopServer(1)
+	tc.StopServer(2)
+
+	store := tc.GetFirstStoreFromServer(t, 0)
+	put := func() error {
+		var ba roachpb.BatchRequest
+		ba.RangeID = desc.RangeID
+		ba.Add(putArgs(key, []byte("v")))
+		_, pErr := store.Send(ctx, ba)
+		return pErr.GoError()
+	}
+
+	// Requests are redirected to n2 until its lease expires. Afterwards, n1
+	// attempts to acquire the lease, which trips its breaker.
+	testutils.SucceedsSoon(t, func() error {
+		if err := put(); !kvserver.IsReplicaUnavailableError(err) {
+			return errors.Errorf("expected replica unavailable error, got %v", err)
+		}
+		return nil
+	})
+	// Subsequent requests fail fast.
+	require.True(t, kvserver.IsReplicaUnavailableError(put()))
+}
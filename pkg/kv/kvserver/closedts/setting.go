@@ -37,3 +37,33 @@ var CloseFraction = settings.RegisterFloatSetting(
 		}
 		return nil
 	})
+
+// LeadForGlobalReadsOverride overrides the lead time that ranges with global
+// reads (non-blocking ranges) use to publish closed timestamps ahead of
+// present time.
+var LeadForGlobalReadsOverride = settings.RegisterDurationSetting(
+	"kv.closed_timestamp.lead_for_global_reads_override",
+	"if nonzero, overrides the lead time that global_reads ranges use to publish closed timestamps",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// maxNetworkLatency is a conservative estimate of the time it takes for a
+// closed timestamp update to make its way from a leaseholder to its followers.
+const maxNetworkLatency = 150 * time.Millisecond
+
+// LeadForGlobalReads returns the duration by which writes to non-blocking
+// ranges are placed ahead of present time. Followers serve reads up to the
+// range's closed timestamp plus this lead, so it has to cover the lag of the
+// closed timestamp behind present time as seen by a follower (the target
+// duration, the interval at which timestamps are closed, and the time it
+// takes for the closed timestamp to reach the follower) as well as the
+// uncertainty interval of a present-time read.
+func LeadForGlobalReads(sv *settings.Values, maxOffset time.Duration) time.Duration {
+	if override := LeadForGlobalReadsOverride.Get(sv); override != 0 {
+		return override
+	}
+	target := TargetDuration.Get(sv)
+	closeInterval := time.Duration(float64(target) * CloseFraction.Get(sv))
+	return target + closeInterval + maxNetworkLatency + maxOffset
+}
@@ -458,6 +458,11 @@ type lockTable interface {
 	// lockTableGuard and the subsequent calls reuse the previously returned
 	// one. The latches needed by the request must be held when calling this
 	// function.
+	//
+	// Requests with the SkipLocked wait policy do not enqueue in any lock
+	// wait-queues and never need to wait. Instead, they capture a snapshot of
+	// the lockTable, which they later consult during evaluation through
+	// lockTableGuard.IsKeyLockedByConflictingTxn.
 	ScanAndEnqueue(Request, lockTableGuard) lockTableGuard
 
 	// Dequeue removes the request from its lock wait-queues. It should be
@@ -503,11 +508,13 @@ type lockTable interface {
 	// the lockTable initially. It must only be called in the evaluation phase
 	// before calling Dequeue, which means all the latches needed by the request
 	// are held. The key must be in the request's SpanSet with the appropriate
-	// SpanAccess: currently the strength is always Exclusive, so the span
-	// containing this key must be SpanReadWrite. This contract ensures that the
-	// lock is not held in a conflicting manner by a different transaction.
-	// Acquiring a lock that is already held by this transaction upgrades the
-	// lock's timestamp and strength, if necessary.
+	// SpanAccess: the strength is either Exclusive or Shared, and in both cases
+	// the span containing this key must be SpanReadWrite. This contract ensures
+	// that the lock is not held in a conflicting manner by a different
+	// transaction. Acquiring a lock that is already held by this transaction
+	// upgrades the lock's timestamp and strength, if necessary.
+	//
+	// Shared locks can only be acquired with the Unreplicated durability.
 	//
 	// For replicated locks, this must be called after the corresponding write
 	// intent has been applied to the replicated state machine.
@@ -588,6 +595,15 @@ type lockTableGuard interface {
 
 	// CurState returns the latest waiting state.
 	CurState() waitingState
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// or reserved by a conflicting transaction in the lockTable snapshot that
+	// the guard captured during its last call to ScanAndEnqueue, along with the
+	// holder of the lock if it is held. Whether a lock conflicts depends on the
+	// access the request declared for the key and on the strength with which
+	// the request acquires locks. It is used by requests with the SkipLocked
+	// wait policy to determine which keys to skip during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key) (bool, *enginepb.TxnMeta)
 }
 
 // lockTableWaiter is concerned with waiting in lock wait-queues for locks held
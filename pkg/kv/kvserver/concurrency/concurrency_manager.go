@@ -306,7 +306,13 @@ func (m *managerImpl) HandleTransactionPushError(
 
 // OnLockAcquired implements the LockManager interface.
 func (m *managerImpl) OnLockAcquired(ctx context.Context, acq *roachpb.LockAcquisition) {
-	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, lock.Exclusive, acq.Durability); err != nil {
+	// Lock acquisitions that predate Shared locks do not specify a strength,
+	// in which case the lock was acquired with Exclusive strength.
+	str := acq.Strength
+	if str == lock.None {
+		str = lock.Exclusive
+	}
+	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, str, acq.Durability); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 }
@@ -435,6 +441,33 @@ func (r *Request) writeConflictTimestamp() hlc.Timestamp {
 	return ts
 }
 
+// lockStrength returns the strength with which the request acquires locks on
+// the keys that it declares with SpanReadWrite access. Requests composed
+// entirely of non-locking reads and of Scan and ReverseScan requests that
+// acquire Shared locks acquire Shared locks. All other requests are treated as
+// acquiring Exclusive locks.
+func (r *Request) lockStrength() lock.Strength {
+	str := lock.Exclusive
+	for _, ru := range r.Requests {
+		req := ru.GetInner()
+		var keyLocking lock.Strength
+		switch t := req.(type) {
+		case *roachpb.ScanRequest:
+			keyLocking = t.KeyLocking
+		case *roachpb.ReverseScanRequest:
+			keyLocking = t.KeyLocking
+		}
+		if keyLocking == lock.Shared {
+			str = lock.Shared
+			continue
+		}
+		if !roachpb.IsReadOnly(req) || roachpb.IsLocking(req) {
+			return lock.Exclusive
+		}
+	}
+	return str
+}
+
 func (r *Request) isSingle(m roachpb.Method) bool {
 	if len(r.Requests) != 1 {
 		return false
@@ -482,6 +515,18 @@ func (g *Guard) AssertNoLatches() {
 	}
 }
 
+// IsKeyLockedByConflictingTxn returns whether the specified key is locked by a
+// conflicting transaction in the lockTable snapshot captured by the guard,
+// along with the holder of the lock if it is held. It implements the
+// storage.LockTableView interface, which is used by scans with the SkipLocked
+// wait policy to determine which keys to skip.
+func (g *Guard) IsKeyLockedByConflictingTxn(key roachpb.Key) (bool, *enginepb.TxnMeta) {
+	if g == nil || g.ltg == nil {
+		return false, nil
+	}
+	return g.ltg.IsKeyLockedByConflictingTxn(key)
+}
+
 func (g *Guard) moveLatchGuard() latchGuard {
 	lg := g.lg
 	g.lg = nil
@@ -262,6 +262,9 @@ type lockTableGuardImpl struct {
 	spans   *spanset.SpanSet
 	readTS  hlc.Timestamp
 	writeTS hlc.Timestamp
+	// The strength with which the request acquires locks on the keys in its
+	// SpanReadWrite spans. See Request.lockStrength.
+	strength lock.Strength
 
 	// Snapshots of the trees for which this request has some spans. Note that
 	// the lockStates in these snapshots may have been removed from
@@ -400,6 +403,24 @@ func (g *lockTableGuardImpl) CurState() waitingState {
 	return g.mu.state
 }
 
+// IsKeyLockedByConflictingTxn implements the lockTableGuard interface.
+func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
+	key roachpb.Key,
+) (bool, *enginepb.TxnMeta) {
+	sa, ss, err := findAccessInSpans(key, g.spans)
+	if err != nil {
+		// The key is not in the request's lock spans, so the request does not
+		// need isolation from locks on it.
+		return false, nil
+	}
+	iter := g.tableSnapshot[ss].MakeIter()
+	iter.FirstOverlap(&lockState{key: key})
+	if !iter.Valid() {
+		return false, nil
+	}
+	return iter.Cur().isLockedByConflictingTxn(g, sa)
+}
+
 func (g *lockTableGuardImpl) notify() {
 	select {
 	case g.mu.signal <- struct{}{}:
@@ -541,17 +562,29 @@ type lockState struct {
 	// - if holder.locked and multiple holderInfos have txn != nil: all the
 	//   txns must have the same txn.ID.
 	// - !holder.locked => waitingReaders.Len() == 0. That is, readers wait
-	//   only if the lock is held. They do not wait for a reservation.
+	//   only if the lock is held with Exclusive strength. They do not wait for
+	//   a reservation or for Shared lock holders.
 	// - If reservation != nil, that request is not in queuedWriters.
+	// - both len(holder.shared) > 0 and waitQ.reservation != nil cannot be
+	//   true.
+	// - if holder.locked and len(holder.shared) > 0: all the Shared lock
+	//   holders must have the same txn.ID as the Exclusive lock holder.
 
 	// Information about whether the lock is held and the holder. We track
 	// information for each durability level separately since a transaction can
 	// go through multiple epochs and TxnSeq and may acquire the same lock in
 	// replicated and unreplicated mode at different stages.
 	holder struct {
+		// locked is true iff the lock is held with Exclusive strength, in which
+		// case holder contains the information about the lock holder.
 		locked bool
-		// LockStrength is always Exclusive
 		holder [lock.MaxDurability + 1]lockHolderInfo
+		// shared contains the transactions holding the lock with Shared
+		// strength, in the order in which they acquired it. Shared locks are
+		// compatible with each other, so multiple transactions can hold them
+		// concurrently. They are only ever acquired with the Unreplicated
+		// durability, so there is a single lockHolderInfo per transaction.
+		shared []lockHolderInfo
 	}
 
 	// Information about the requests waiting on the lock.
@@ -689,6 +722,15 @@ type lockWaitQueue struct {
 	//   since those are also shared lockers. In that case it will depend on the
 	//   first waiter since that waiter must be desiring a lock that is
 	//   incompatible with a shared lock.
+	//
+	// Shared locks are currently supported in a simplified form of the above,
+	// without Upgrade locks or joint reservations. Requests that acquire Shared
+	// locks queue in queuedWriters behind an Exclusive lock holder or a
+	// reservation, like any other writer, but do not wait for Shared lock
+	// holders. Requests that acquire Exclusive locks, and non-transactional
+	// writers, queue in queuedWriters behind the Shared lock holders of other
+	// transactions and push the first of them. Once all Shared locks are
+	// released, the first queued writer acquires the reservation as usual.
 
 	reservation *lockTableGuardImpl
 
@@ -781,12 +823,23 @@ func (l *lockState) Format(buf *strings.Builder) {
 		}
 		fmt.Fprintln(b, "")
 	}
+	writeSharedHolderInfo := func(b *strings.Builder, h *lockHolderInfo) {
+		fmt.Fprintf(b, "  shared holder: txn: %v, ts: %v, info: unrepl epoch: %d, seqs: [%d",
+			h.txn.ID, h.ts, h.txn.Epoch, h.seqs[0])
+		for j := 1; j < len(h.seqs); j++ {
+			fmt.Fprintf(b, ", %d", h.seqs[j])
+		}
+		fmt.Fprintln(b, "]")
+	}
 	txn, ts := l.getLockHolder()
-	if txn == nil {
+	if txn != nil {
+		writeHolderInfo(buf, txn, ts)
+	} else if l.reservation != nil {
 		fmt.Fprintf(buf, "  res: req: %d, ", l.reservation.seqNum)
 		writeResInfo(buf, l.reservation.txn, l.reservation.writeTS)
-	} else {
-		writeHolderInfo(buf, txn, ts)
+	}
+	for i := range l.holder.shared {
+		writeSharedHolderInfo(buf, &l.holder.shared[i])
 	}
 	// TODO(sumeer): Add an optional `description string` field to Request and
 	// lockTableGuardImpl that tests can set to avoid relying on the seqNum to
@@ -845,15 +898,23 @@ func (l *lockState) tryBreakReservation(seqNum uint64) bool {
 func (l *lockState) informActiveWaiters() {
 	waitForState := waitingState{kind: waitFor, key: l.key}
 	findDistinguished := l.distinguishedWaiter == nil
+	sharedOnly := false
 	if lockHolderTxn, _ := l.getLockHolder(); lockHolderTxn != nil {
 		waitForState.txn = lockHolderTxn
 		waitForState.held = true
-	} else {
+	} else if l.reservation != nil {
 		waitForState.txn = l.reservation.txn
 		if !findDistinguished && l.distinguishedWaiter.isSameTxnAsReservation(waitForState) {
 			findDistinguished = true
 			l.distinguishedWaiter = nil
 		}
+	} else {
+		// The lock is only held with Shared strength. Each waiter waits for the
+		// first Shared lock holder from a different transaction. Note that
+		// there cannot be waitingReaders, since they do not wait for Shared
+		// locks.
+		sharedOnly = true
+		waitForState.held = true
 	}
 
 	for e := l.waitingReaders.Front(); e != nil; e = e.Next() {
@@ -887,6 +948,9 @@ func (l *lockState) informActiveWaiters() {
 		} else {
 			state = waitForState
 			state.guardAccess = spanset.SpanReadWrite
+			if sharedOnly {
+				state.txn = l.conflictingSharedHolder(g)
+			}
 			if findDistinguished {
 				l.distinguishedWaiter = g
 				findDistinguished = false
@@ -958,7 +1022,7 @@ func (l *lockState) tryMakeNewDistinguished() {
 // reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) isEmptyLock() bool {
-	if !l.holder.locked && l.reservation == nil {
+	if !l.holder.locked && l.reservation == nil && len(l.holder.shared) == 0 {
 		for i := range l.holder.holder {
 			if !l.holder.holder[i].isEmpty() {
 				panic("lockState with !locked but non-zero lockHolderInfo")
@@ -1013,7 +1077,8 @@ func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 	return l.holder.holder[index].txn, l.holder.holder[index].ts
 }
 
-// Removes the current lock holder from the lock.
+// Removes the current lock holder from the lock. Shared lock holders are not
+// removed.
 // REQUIRES: l.mu is locked.
 func (l *lockState) clearLockHolder() {
 	l.holder.locked = false
@@ -1022,6 +1087,69 @@ func (l *lockState) clearLockHolder() {
 	}
 }
 
+// Returns the index of the Shared lock holder from the transaction with the
+// given id, or -1 if the transaction does not hold a Shared lock.
+// REQUIRES: l.mu is locked.
+func (l *lockState) sharedHolderIndex(id uuid.UUID) int {
+	for i := range l.holder.shared {
+		if l.holder.shared[i].txn.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the first Shared lock holder that is from a different transaction
+// than request g, or nil if there is no such holder.
+// REQUIRES: l.mu is locked.
+func (l *lockState) conflictingSharedHolder(g *lockTableGuardImpl) *enginepb.TxnMeta {
+	for i := range l.holder.shared {
+		if txn := l.holder.shared[i].txn; !g.isSameTxn(txn) {
+			return txn
+		}
+	}
+	return nil
+}
+
+// Returns whether the lock is held or reserved in a manner that conflicts with
+// request g accessing the key with access sa, along with the conflicting lock
+// holder if the lock is held. Unlike tryActiveWait, this does not enqueue g in
+// the lock's wait-queues.
+// Acquires l.mu.
+func (l *lockState) isLockedByConflictingTxn(
+	g *lockTableGuardImpl, sa spanset.SpanAccess,
+) (bool, *enginepb.TxnMeta) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lockHolderTxn, lockHolderTS := l.getLockHolder()
+	if lockHolderTxn != nil {
+		if g.isSameTxn(lockHolderTxn) {
+			// Already locked by this txn.
+			return false, nil
+		}
+		if sa == spanset.SpanReadOnly && g.readTS.Less(lockHolderTS) {
+			// Reads do not conflict with locks above their timestamp.
+			return false, nil
+		}
+		return true, lockHolderTxn
+	}
+	if sa == spanset.SpanReadOnly {
+		// Reads only care about an Exclusive locker.
+		return false, nil
+	}
+	if l.reservation != nil {
+		// The reservation holder may acquire the lock as soon as it evaluates,
+		// so a reservation from a different transaction is treated as a lock.
+		return l.reservation != g && !g.isSameTxn(l.reservation.txn), nil
+	}
+	if g.strength == lock.Exclusive {
+		if sharedHolderTxn := l.conflictingSharedHolder(g); sharedHolderTxn != nil {
+			return true, sharedHolderTxn
+		}
+	}
+	return false, nil
+}
+
 // Decides whether the request g with access sa should actively wait at this
 // lock and if yes, adjusts the data-structures appropriately. The notify
 // parameter is true iff the request's new state channel should be notified --
@@ -1049,7 +1177,8 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 
 	if sa == spanset.SpanReadOnly {
 		if lockHolderTxn == nil {
-			// Reads only care about locker, not a reservation.
+			// Reads only care about an Exclusive locker, not a reservation or
+			// Shared lockers.
 			return false
 		}
 		// Locked by some other txn.
@@ -1081,6 +1210,19 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 	if lockHolderTxn != nil {
 		waitForState.txn = lockHolderTxn
 		waitForState.held = true
+	} else if l.reservation == nil {
+		// The lock is only held with Shared strength. Requests that acquire
+		// Shared locks are compatible with it, as are requests from the
+		// transaction holding all of the Shared locks.
+		if g.strength != lock.Exclusive {
+			return false
+		}
+		sharedHolderTxn := l.conflictingSharedHolder(g)
+		if sharedHolderTxn == nil {
+			return false
+		}
+		waitForState.txn = sharedHolderTxn
+		waitForState.held = true
 	} else {
 		if l.reservation == g {
 			// Already reserved by this request.
@@ -1185,10 +1327,13 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 // that is acquiring the lock.
 // Acquires l.mu.
 func (l *lockState) acquireLock(
-	_ lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+	strength lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if strength == lock.Shared {
+		return l.acquireSharedLock(durability, txn, ts)
+	}
 	if l.holder.locked {
 		// Already held.
 		beforeTxn, beforeTs := l.getLockHolder()
@@ -1266,34 +1411,25 @@ func (l *lockState) acquireLock(
 		}
 		return nil
 	}
-	// Not already held, so may be reserved by this request. There is also the
-	// possibility that some other request has broken this reservation because
-	// of a concurrent release but that is harmless since this request is
-	// holding latches and has proceeded to evaluation.
-	if l.reservation != nil {
-		if l.reservation.txn.ID != txn.ID {
-			// Reservation is broken.
-			qg := &queuedGuard{
-				guard:  l.reservation,
-				active: false,
-			}
-			l.queuedWriters.PushFront(qg)
-		} else {
-			// Else, reservation is not broken, or broken by a different request
-			// from the same transaction. In the latter case, both requests are not
-			// actively waiting at this lock. We don't know which is in the queue
-			// and which is holding the reservation but it does not matter. Both
-			// will have their requestGuardImpl.mu.locks updated and neither will be
-			// in the queue at the end of this method.
-			l.reservation.mu.Lock()
-			delete(l.reservation.mu.locks, l)
-			l.reservation.mu.Unlock()
-		}
-		if l.waitingReaders.Len() > 0 {
-			panic("lockTable bug")
+	// Not already held with Exclusive strength, but may be held with Shared
+	// strength by this transaction. It cannot be held with Shared strength by
+	// other transactions, since this request would have waited for them.
+	for i := range l.holder.shared {
+		if l.holder.shared[i].txn.ID != txn.ID {
+			return errors.AssertionFailedf(
+				"lock cannot be acquired with Exclusive strength while held with Shared strength by different transaction")
 		}
+	}
+	// May be reserved by this request. There is also the possibility that some
+	// other request has broken this reservation because of a concurrent release
+	// but that is harmless since this request is holding latches and has
+	// proceeded to evaluation.
+	if l.reservation != nil {
+		l.breakReservationOnAcquire(txn)
 	} else {
-		if l.queuedWriters.Len() > 0 || l.waitingReaders.Len() > 0 {
+		// If the lock is held with Shared strength by this transaction, there
+		// may be queued writers waiting for it to be released.
+		if (l.queuedWriters.Len() > 0 && len(l.holder.shared) == 0) || l.waitingReaders.Len() > 0 {
 			panic("lockTable bug")
 		}
 	}
@@ -1311,6 +1447,88 @@ func (l *lockState) acquireLock(
 	return nil
 }
 
+// Called when the lock, which is reserved, is acquired by txn. If the
+// reservation belongs to a different transaction then it is broken and the
+// reservation holder reenters the queue as an inactive waiter.
+// REQUIRES: l.mu is locked.
+func (l *lockState) breakReservationOnAcquire(txn *enginepb.TxnMeta) {
+	if l.reservation.txn.ID != txn.ID {
+		// Reservation is broken.
+		qg := &queuedGuard{
+			guard:  l.reservation,
+			active: false,
+		}
+		l.queuedWriters.PushFront(qg)
+	} else {
+		// Else, reservation is not broken, or broken by a different request
+		// from the same transaction. In the latter case, both requests are not
+		// actively waiting at this lock. We don't know which is in the queue
+		// and which is holding the reservation but it does not matter. Both
+		// will have their requestGuardImpl.mu.locks updated and neither will be
+		// in the queue at the end of this method.
+		l.reservation.mu.Lock()
+		delete(l.reservation.mu.locks, l)
+		l.reservation.mu.Unlock()
+	}
+	if l.waitingReaders.Len() > 0 {
+		panic("lockTable bug")
+	}
+	l.reservation = nil
+}
+
+// Acquires this lock with Shared strength. Shared locks are compatible with
+// each other, so the lock may already be held with Shared strength by other
+// transactions. It may also be held with Exclusive strength by the same
+// transaction, in which case the Shared lock is tracked so that it outlives
+// the Exclusive lock if the latter is rolled back.
+// REQUIRES: l.mu is locked.
+func (l *lockState) acquireSharedLock(
+	durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+) error {
+	if durability != lock.Unreplicated {
+		return errors.AssertionFailedf("Shared locks must be acquired with Unreplicated durability")
+	}
+	if l.holder.locked && !l.isLockedBy(txn.ID) {
+		return errors.AssertionFailedf(
+			"lock cannot be acquired with Shared strength while held with Exclusive strength by different transaction")
+	}
+	if i := l.sharedHolderIndex(txn.ID); i < 0 {
+		l.holder.shared = append(l.holder.shared, lockHolderInfo{
+			txn:  txn,
+			ts:   ts,
+			seqs: append([]enginepb.TxnSeq(nil), txn.Sequence),
+		})
+	} else {
+		h := &l.holder.shared[i]
+		if h.txn.Epoch < txn.Epoch {
+			// Clear the sequences for the older epoch.
+			h.seqs = h.seqs[:0]
+		}
+		// Insert the sequence number into the sorted sequence history, if it
+		// is not already being tracked.
+		j := sort.Search(len(h.seqs), func(j int) bool { return h.seqs[j] >= txn.Sequence })
+		if j == len(h.seqs) || h.seqs[j] != txn.Sequence {
+			h.seqs = append(h.seqs, 0)
+			copy(h.seqs[j+1:], h.seqs[j:])
+			h.seqs[j] = txn.Sequence
+		}
+		h.txn = txn
+		// See the comment in acquireLock about forwarding the timestamp instead
+		// of assigning to it blindly.
+		h.ts.Forward(ts)
+	}
+	if l.holder.locked {
+		// Still held with Exclusive strength, so there is no change for the
+		// waiters.
+		return nil
+	}
+	if l.reservation != nil {
+		l.breakReservationOnAcquire(txn)
+	}
+	l.lockIsSharedOnly()
+	return nil
+}
+
 // A replicated lock held by txn with timestamp ts was discovered by guard g
 // where g is trying to access this key with access sa.
 // Acquires l.mu.
@@ -1327,6 +1545,16 @@ func (l *lockState) discoveredLock(
 	} else {
 		l.holder.locked = true
 	}
+	// Shared locks held by other transactions are incompatible with the
+	// discovered lock. Since Shared locks are unreplicated and best-effort, we
+	// drop them instead of failing.
+	shared := l.holder.shared[:0]
+	for _, h := range l.holder.shared {
+		if h.txn.ID == txn.ID {
+			shared = append(shared, h)
+		}
+	}
+	l.holder.shared = shared
 	holder := &l.holder.holder[lock.Replicated]
 	if holder.txn == nil {
 		holder.txn = txn
@@ -1411,8 +1639,9 @@ func (l *lockState) tryClearLock(force bool) bool {
 		return false
 	}
 
-	// Remove unreplicated holder.
+	// Remove unreplicated holder, including Shared lock holders.
 	l.holder.holder[lock.Unreplicated] = lockHolderInfo{}
+	l.holder.shared = nil
 	var waitState waitingState
 	if replicatedHeld && !force {
 		lockHolderTxn, _ := l.getLockHolder()
@@ -1496,13 +1725,19 @@ func removeIgnored(
 func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	sharedReleased := l.tryUpdateSharedLock(up)
 	if !l.isLockedBy(up.Txn.ID) {
+		if !sharedReleased || l.holder.locked {
+			return false, nil
+		}
+		if len(l.holder.shared) == 0 {
+			return l.lockIsFree(), nil
+		}
+		l.lockIsSharedOnly()
 		return false, nil
 	}
 	if up.Status.IsFinalized() {
-		l.clearLockHolder()
-		gc = l.lockIsFree()
-		return gc, nil
+		return l.exclusiveLockReleased(), nil
 	}
 
 	txn := &up.Txn
@@ -1558,9 +1793,7 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 	}
 
 	if !isLocked {
-		l.clearLockHolder()
-		gc = l.lockIsFree()
-		return gc, nil
+		return l.exclusiveLockReleased(), nil
 	}
 
 	if advancedTs {
@@ -1572,6 +1805,101 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 	return false, nil
 }
 
+// Tries to update the Shared lock held by the transaction in the LockUpdate,
+// if any, following the same rules as for Unreplicated locks in
+// tryUpdateLock. Returns true iff the Shared lock was released.
+// REQUIRES: l.mu is locked.
+func (l *lockState) tryUpdateSharedLock(up *roachpb.LockUpdate) (released bool) {
+	i := l.sharedHolderIndex(up.Txn.ID)
+	if i < 0 {
+		return false
+	}
+	h := &l.holder.shared[i]
+	released = up.Status.IsFinalized() || up.Txn.Epoch > h.txn.Epoch
+	if !released && up.Txn.Epoch == h.txn.Epoch {
+		h.seqs = removeIgnored(h.seqs, up.IgnoredSeqNums)
+		released = len(h.seqs) == 0
+	}
+	if released {
+		l.holder.shared = append(l.holder.shared[:i], l.holder.shared[i+1:]...)
+		return true
+	}
+	if ts := up.Txn.WriteTimestamp; h.ts.Less(ts) {
+		// Readers do not wait on Shared locks, so there is no need to inform
+		// any waiters about the timestamp increase.
+		h.ts = ts
+		if up.Txn.Epoch == h.txn.Epoch {
+			h.txn = &up.Txn
+		}
+	}
+	return false
+}
+
+// The lock holder has released the lock held with Exclusive strength. Returns
+// whether the lockState can be garbage collected.
+// REQUIRES: l.mu is locked.
+func (l *lockState) exclusiveLockReleased() (gc bool) {
+	l.clearLockHolder()
+	if len(l.holder.shared) > 0 {
+		// The lock holder's transaction continues to hold the lock with Shared
+		// strength.
+		l.lockIsSharedOnly()
+		return false
+	}
+	return l.lockIsFree()
+}
+
+// The lock has transitioned to being held only with Shared strength. Waiters
+// that are compatible with the Shared lock holders are done waiting, and the
+// remaining active waiters need to be told who they are waiting for.
+// REQUIRES: l.mu is locked.
+func (l *lockState) lockIsSharedOnly() {
+	if l.holder.locked {
+		panic("called lockIsSharedOnly on lock with Exclusive holder")
+	}
+	if l.reservation != nil {
+		panic("called lockIsSharedOnly on lock with reservation")
+	}
+
+	// All waiting readers don't need to wait here anymore.
+	for e := l.waitingReaders.Front(); e != nil; {
+		g := e.Value.(*lockTableGuardImpl)
+		curr := e
+		e = e.Next()
+		l.waitingReaders.Remove(curr)
+		if g == l.distinguishedWaiter {
+			l.distinguishedWaiter = nil
+		}
+		g.doneWaitingAtLock(false, l)
+	}
+
+	// Neither do waiting writers that acquire Shared locks or that are from
+	// the transaction holding all of the Shared locks.
+	for e := l.queuedWriters.Front(); e != nil; {
+		qg := e.Value.(*queuedGuard)
+		curr := e
+		e = e.Next()
+		g := qg.guard
+		if g.strength == lock.Exclusive && l.conflictingSharedHolder(g) != nil {
+			continue
+		}
+		l.queuedWriters.Remove(curr)
+		if qg.active {
+			if g == l.distinguishedWaiter {
+				l.distinguishedWaiter = nil
+			}
+			g.doneWaitingAtLock(false, l)
+		} else {
+			g.mu.Lock()
+			delete(g.mu.locks, l)
+			g.mu.Unlock()
+		}
+	}
+
+	// Tell the active waiters who they are waiting for.
+	l.informActiveWaiters()
+}
+
 // The lock holder timestamp has increased. Some of the waiters may no longer
 // need to wait.
 // REQUIRES: l.mu is locked.
@@ -1683,6 +2011,12 @@ func (l *lockState) tryFreeLockOnReplicatedAcquire() bool {
 		return false
 	}
 
+	// Bail if the lock is also held with Shared strength, which is not stored
+	// as an MVCC intent.
+	if len(l.holder.shared) > 0 {
+		return false
+	}
+
 	// Bail if the lock has waiting writers. It is not uncontended.
 	if l.queuedWriters.Len() != 0 {
 		return false
@@ -1702,7 +2036,7 @@ func (l *lockState) tryFreeLockOnReplicatedAcquire() bool {
 // waiters, but there cannot be a reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) lockIsFree() (gc bool) {
-	if l.holder.locked {
+	if l.holder.locked || len(l.holder.shared) > 0 {
 		panic("called lockIsFree on lock with holder")
 	}
 	if l.reservation != nil {
@@ -1783,6 +2117,7 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 		g.spans = req.LockSpans
 		g.readTS = req.readConflictTimestamp()
 		g.writeTS = req.writeConflictTimestamp()
+		g.strength = req.lockStrength()
 		g.sa = spanset.NumSpanAccess - 1
 		g.index = -1
 	} else {
@@ -1811,6 +2146,13 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 			}
 		}
 	}
+	if req.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		// Requests that skip locked keys do not wait in any lock wait-queues.
+		// Instead, they consult the snapshot of the lockTable captured above
+		// during evaluation, through IsKeyLockedByConflictingTxn, to determine
+		// which keys to skip.
+		return g
+	}
 	g.findNextLockAfter(true /* notify */)
 	return g
 }
@@ -1901,8 +2243,8 @@ func (t *lockTableImpl) AcquireLock(
 		// If not enabled, don't track any locks.
 		return nil
 	}
-	if strength != lock.Exclusive {
-		return errors.AssertionFailedf("lock strength not Exclusive")
+	if strength != lock.Exclusive && strength != lock.Shared {
+		return errors.AssertionFailedf("lock strength not Exclusive or Shared")
 	}
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
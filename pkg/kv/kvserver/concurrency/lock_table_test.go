@@ -52,10 +52,12 @@ new-txn txn=<name> ts=<int>[,<int>] epoch=<int> [seq=<int>]
 
  Creates a TxnMeta.
 
-new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+...
+new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [strength=shared] [skip-locked]
 ----
 
- Creates a Request.
+ Creates a Request. If strength=shared is specified, the request acquires
+ Shared locks on the keys in its write spans instead of Exclusive locks. If
+ skip-locked is specified, the request uses the SkipLocked wait policy.
 
 scan r=<name>
 ----
@@ -64,11 +66,18 @@ scan r=<name>
  Calls lockTable.ScanAndEnqueue. If the request has an existing guard, uses it.
  If a guard is returned, stores it for later use.
 
-acquire r=<name> k=<key> durability=r|u
+acquire r=<name> k=<key> durability=r|u [strength=shared|exclusive]
 ----
 <error string>
 
  Acquires lock for the request, using the existing guard for that request.
+ The lock is acquired with Exclusive strength, unless specified otherwise.
+
+is-key-locked-by-conflicting-txn r=<name> k=<key>
+----
+locked: <bool>[, holder: <txn>]
+
+ Calls lockTableGuard.IsKeyLockedByConflictingTxn for the named request.
 
 release txn=<name> span=<start>[,<end>]
 ----
@@ -204,6 +213,19 @@ func TestLockTableBasic(t *testing.T) {
 					LatchSpans: spans,
 					LockSpans:  spans,
 				}
+				if d.HasArg("strength") {
+					var s string
+					d.ScanArgs(t, "strength", &s)
+					if s != "shared" {
+						d.Fatalf(t, "unsupported request strength: %s", s)
+					}
+					var ru roachpb.RequestUnion
+					ru.MustSetInner(&roachpb.ScanRequest{KeyLocking: lock.Shared})
+					req.Requests = []roachpb.RequestUnion{ru}
+				}
+				if d.HasArg("skip-locked") {
+					req.WaitPolicy = lock.WaitPolicy_SkipLocked
+				}
 				if txnMeta != nil {
 					// Update the transaction's timestamp, if necessary. The transaction
 					// may have needed to move its timestamp for any number of reasons.
@@ -246,11 +268,44 @@ func TestLockTableBasic(t *testing.T) {
 				if s[0] == 'r' {
 					durability = lock.Replicated
 				}
-				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), lock.Exclusive, durability); err != nil {
+				strength := lock.Exclusive
+				if d.HasArg("strength") {
+					d.ScanArgs(t, "strength", &s)
+					switch s {
+					case "shared":
+						strength = lock.Shared
+					case "exclusive":
+					default:
+						d.Fatalf(t, "incorrect strength: %s", s)
+					}
+				}
+				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), strength, durability); err != nil {
 					return err.Error()
 				}
 				return lt.(*lockTableImpl).String()
 
+			case "is-key-locked-by-conflicting-txn":
+				var reqName string
+				d.ScanArgs(t, "r", &reqName)
+				g := guardsByReqName[reqName]
+				if g == nil {
+					d.Fatalf(t, "unknown guard: %s", reqName)
+				}
+				var key string
+				d.ScanArgs(t, "k", &key)
+				locked, txn := g.IsKeyLockedByConflictingTxn(roachpb.Key(key))
+				if !locked || txn == nil {
+					return fmt.Sprintf("locked: %t", locked)
+				}
+				var txnS string
+				for k, v := range txnsByName {
+					if v.ID.Equal(txn.ID) {
+						txnS = k
+						break
+					}
+				}
+				return fmt.Sprintf("locked: true, holder: %s", txnS)
+
 			case "release":
 				var txnName string
 				d.ScanArgs(t, "txn", &txnName)
@@ -72,6 +72,9 @@ func (g *mockLockTableGuard) CurState() waitingState {
 	}
 	return s
 }
+func (g *mockLockTableGuard) IsKeyLockedByConflictingTxn(roachpb.Key) (bool, *enginepb.TxnMeta) {
+	panic("unimplemented")
+}
 func (g *mockLockTableGuard) notify() { g.signal <- struct{}{} }
 
 // mockLockTableGuard implements the LockManager interface.
@@ -16,6 +16,7 @@
 package gc
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"time"
@@ -179,14 +180,36 @@ func Run(
 		Threshold: newThreshold,
 	}
 
+	// Point key versions shadowed by range tombstones are considered deleted at
+	// the timestamp of the range tombstone.
+	tombstones, err := storage.ScanMVCCRangeTombstones(
+		snap, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(),
+	)
+	if err != nil {
+		return Info{}, err
+	}
+
 	// Maps from txn ID to txn and intent key slice.
 	txnMap := map[uuid.UUID]*roachpb.Transaction{}
 	intentKeyMap := map[uuid.UUID][]roachpb.Key{}
-	err := processReplicatedKeyRange(ctx, desc, snap, now, newThreshold, gcer, txnMap, intentKeyMap, &info)
+	err = processReplicatedKeyRange(ctx, desc, snap, now, newThreshold, tombstones, gcer, txnMap, intentKeyMap, &info)
 	if err != nil {
 		return Info{}, err
 	}
 
+	// Range tombstones below the threshold are removed by GC requests that set
+	// the threshold, once they no longer shadow any versions. Now that the
+	// versions they shadowed have been removed above, bump the threshold again
+	// to remove them.
+	if len(tombstones) > 0 {
+		if err := gcer.SetGCThreshold(ctx, Threshold{
+			Key: newThreshold,
+			Txn: txnExp,
+		}); err != nil {
+			return Info{}, errors.Wrap(err, "failed to GC range tombstones")
+		}
+	}
+
 	// From now on, all keys processed are range-local and inline (zero timestamp).
 
 	// Process local range key entries (txn records, queue last processed times).
@@ -233,6 +256,7 @@ func processReplicatedKeyRange(
 	snap storage.Reader,
 	now hlc.Timestamp,
 	threshold hlc.Timestamp,
+	tombstones storage.MVCCRangeTombstones,
 	gcer GCer,
 	txnMap map[uuid.UUID]*roachpb.Transaction,
 	intentKeyMap map[uuid.UUID][]roachpb.Key,
@@ -303,12 +327,17 @@ func processReplicatedKeyRange(
 		if s.curIsNotValue() { // Step over metadata or other system keys
 			continue
 		}
+		if bytes.HasPrefix(s.cur.Key.Key, keys.LocalMVCCRangeTombstonePrefix) {
+			// Range tombstones are garbage collected separately, see Run.
+			continue
+		}
 		if s.curIsIntent() {
 			handleIntent(s.next)
 			continue
 		}
 		isNewest := s.curIsNewest()
-		if isGarbage(threshold, s.cur, s.next, isNewest) {
+		shadowedAt := tombstones.ShadowedAt(s.cur.Key.Key, s.cur.Key.Timestamp)
+		if isGarbage(threshold, s.cur, s.next, isNewest, shadowedAt) {
 			keyBytes := int64(s.cur.Key.EncodedSize())
 			batchGCKeysBytes += keyBytes
 			haveGarbageForThisKey = true
@@ -373,11 +402,20 @@ func processReplicatedKeyRange(
 // guaranteed as described above. However if this were the only rule, then if
 // the most recent write was a delete, it would never be removed. Thus, when a
 // deleted value is the most recent before expiration, it can be deleted.
-func isGarbage(threshold hlc.Timestamp, cur, next *storage.MVCCKeyValue, isNewest bool) bool {
+//
+// If cur is shadowed by a range tombstone, shadowedAt is the timestamp of the
+// range tombstone, and cur is treated as if it were followed by a delete at
+// that timestamp.
+func isGarbage(
+	threshold hlc.Timestamp, cur, next *storage.MVCCKeyValue, isNewest bool, shadowedAt hlc.Timestamp,
+) bool {
 	// If the value is not at or below the threshold then it's not garbage.
 	if belowThreshold := cur.Key.Timestamp.LessEq(threshold); !belowThreshold {
 		return false
 	}
+	if !shadowedAt.IsEmpty() && shadowedAt.LessEq(threshold) {
+		return true
+	}
 	isDelete := len(cur.Value) == 0
 	if isNewest && !isDelete {
 		return false
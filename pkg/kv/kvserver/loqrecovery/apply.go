@@ -0,0 +1,124 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// PrepareUpdateReplicas writes the updates of the plan that concern the
+// given store into the batch, which the caller commits once the changes have
+// been confirmed. It returns a description of every staged change.
+//
+// An update is only applied if the range descriptor on the store still
+// matches the one the plan was made from; otherwise, the store changed
+// since the replica info was collected and a new plan has to be made.
+func PrepareUpdateReplicas(
+	ctx context.Context,
+	plan ReplicaUpdatePlan,
+	clock *hlc.Clock,
+	storeID roachpb.StoreID,
+	batch storage.Batch,
+) ([]string, error) {
+	var changes []string
+	for _, update := range plan.Updates {
+		if update.NewReplica.StoreID != storeID {
+			continue
+		}
+		key := keys.RangeDescriptorKey(update.StartKey)
+		value, _, err := storage.MVCCGet(ctx, batch, key, clock.Now(),
+			storage.MVCCGetOptions{Inconsistent: true})
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, errors.Errorf("r%d: range descriptor not found on store s%d",
+				update.RangeID, storeID)
+		}
+		var desc roachpb.RangeDescriptor
+		if err := value.GetProto(&desc); err != nil {
+			return nil, err
+		}
+		oldReplica, ok := desc.GetReplicaDescriptor(storeID)
+		if desc.RangeID != update.RangeID || !ok ||
+			oldReplica.ReplicaID != update.OldReplicaID ||
+			desc.NextReplicaID != update.NewReplica.ReplicaID {
+			return nil, errors.Errorf(
+				"r%d: range descriptor %s on store s%d changed since replica info was collected",
+				update.RangeID, &desc, storeID)
+		}
+
+		newDesc := desc
+		newDesc.SetReplicas(roachpb.MakeReplicaDescriptors(
+			[]roachpb.ReplicaDescriptor{update.NewReplica}))
+		newDesc.NextReplicaID = update.NextReplicaID
+		if err := WriteRangeDescriptor(ctx, batch, clock, &newDesc); err != nil {
+			return nil, errors.Wrapf(err, "r%d", update.RangeID)
+		}
+		changes = append(changes, fmt.Sprintf("replica %s -> %s", &desc, &newDesc))
+	}
+	return changes, nil
+}
+
+// WriteRangeDescriptor writes the range-local copy of the descriptor. The
+// meta copies are left untouched: they are overwritten by the next change
+// to the descriptor, which the up-replication of the range is bound to
+// make. We rely on the fact that all range descriptor updates start with a
+// CPut on the range-local copy followed by a blind Put to the meta copy.
+func WriteRangeDescriptor(
+	ctx context.Context, batch storage.Batch, clock *hlc.Clock, desc *roachpb.RangeDescriptor,
+) error {
+	key := keys.RangeDescriptorKey(desc.StartKey)
+	sl := stateloader.Make(desc.RangeID)
+	ms, err := sl.LoadMVCCStats(ctx, batch)
+	if err != nil {
+		return errors.Wrap(err, "loading MVCCStats")
+	}
+	err = storage.MVCCPutProto(ctx, batch, &ms, key, clock.Now(), nil /* txn */, desc)
+	if wiErr := (*roachpb.WriteIntentError)(nil); errors.As(err, &wiErr) {
+		if len(wiErr.Intents) != 1 {
+			return errors.Errorf("expected 1 intent, found %d: %s", len(wiErr.Intents), wiErr)
+		}
+		intent := wiErr.Intents[0]
+		// Transactions involving the range descriptor always start on the
+		// range-local descriptor's key, so their records live on this range.
+		// Since the range lost quorum, the transaction can't commit anymore:
+		// abort it by deleting its record and resolving the intent. Note that
+		// a transaction in the STAGING state may have been implicitly
+		// committed, in which case its change to the descriptor is lost.
+		txnKey := keys.TransactionKey(intent.Txn.Key, intent.Txn.ID)
+		if err := storage.MVCCDelete(ctx, batch, &ms, txnKey, hlc.Timestamp{}, nil); err != nil {
+			return err
+		}
+		update := roachpb.LockUpdate{
+			Span:   roachpb.Span{Key: intent.Key},
+			Txn:    intent.Txn,
+			Status: roachpb.ABORTED,
+		}
+		if _, err := storage.MVCCResolveWriteIntent(ctx, batch, &ms, update); err != nil {
+			return err
+		}
+		// With the intent resolved, we can try again.
+		err = storage.MVCCPutProto(ctx, batch, &ms, key, clock.Now(), nil /* txn */, desc)
+	}
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(sl.SetMVCCStats(ctx, batch, &ms), "updating MVCCStats")
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripJSON writes v with WriteJSON and reads it back into out, which must
+// serialize identically. Note that empty keys, such as the start key of the
+// first range, are read back as nil keys.
+func roundTripJSON(t *testing.T, v, out interface{}) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, v))
+	written := buf.String()
+	require.NoError(t, ReadJSON(&buf, out))
+	require.NoError(t, WriteJSON(&buf, out))
+	require.Equal(t, written, buf.String())
+}
+
+// TestCollectPlanApply runs the recovery of a single range that lost quorum
+// through all of its steps, including the serialization of the collected
+// replica info and of the plan.
+func TestCollectPlanApply(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, 0)
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	ident := roachpb.StoreIdent{NodeID: 1, StoreID: 1}
+	require.NoError(t, storage.MVCCPutProto(ctx, eng, nil /* ms */, keys.StoreIdentKey(),
+		hlc.Timestamp{}, nil /* txn */, &ident))
+	desc := makeDesc(1, roachpb.RKeyMin, roachpb.RKeyMax, 1, 2, 3)
+	require.NoError(t, storage.MVCCPutProto(ctx, eng, nil, /* ms */
+		keys.RangeDescriptorKey(desc.StartKey), clock.Now(), nil /* txn */, &desc))
+	require.NoError(t, stateloader.Make(desc.RangeID).SetRangeAppliedState(
+		ctx, eng, 10 /* appliedIndex */, 5 /* leaseAppliedIndex */, &enginepb.MVCCStats{}))
+
+	info, err := CollectReplicaInfo(ctx, []storage.Engine{eng})
+	require.NoError(t, err)
+	require.Equal(t, []ReplicaInfo{
+		{NodeID: 1, StoreID: 1, Desc: desc, RaftAppliedIndex: 10},
+	}, info.Replicas)
+
+	var readInfo NodeReplicaInfo
+	roundTripJSON(t, info, &readInfo)
+
+	plan, problems, err := PlanReplicas([]NodeReplicaInfo{readInfo}, []roachpb.StoreID{2, 3})
+	require.NoError(t, err)
+	require.Empty(t, problems)
+	var readPlan ReplicaUpdatePlan
+	roundTripJSON(t, plan, &readPlan)
+
+	// Updates for other stores are ignored.
+	batch := eng.NewBatch()
+	changes, err := PrepareUpdateReplicas(ctx, readPlan, clock, 2, batch)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	batch.Close()
+
+	batch = eng.NewBatch()
+	changes, err = PrepareUpdateReplicas(ctx, readPlan, clock, 1, batch)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.NoError(t, batch.Commit(true /* sync */))
+	batch.Close()
+
+	var newDesc roachpb.RangeDescriptor
+	ok, err := storage.MVCCGetProto(ctx, eng, keys.RangeDescriptorKey(desc.StartKey),
+		clock.Now(), &newDesc, storage.MVCCGetOptions{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1, ReplicaID: 4}},
+		newDesc.Replicas().All())
+	require.Equal(t, roachpb.ReplicaID(5), newDesc.NextReplicaID)
+
+	// The plan can't be applied twice.
+	batch = eng.NewBatch()
+	defer batch.Close()
+	_, err = PrepareUpdateReplicas(ctx, readPlan, clock, 1, batch)
+	require.Regexp(t, "changed since replica info was collected", err)
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+)
+
+// CollectReplicaInfo captures the information about all replicas found on
+// the provided stores, which must belong to the same node.
+func CollectReplicaInfo(ctx context.Context, stores []storage.Engine) (NodeReplicaInfo, error) {
+	if len(stores) == 0 {
+		return NodeReplicaInfo{}, errors.New("no stores were provided for info collection")
+	}
+
+	var info NodeReplicaInfo
+	var nodeID roachpb.NodeID
+	for _, eng := range stores {
+		ident, err := kvserver.ReadStoreIdent(ctx, eng)
+		if err != nil {
+			return NodeReplicaInfo{}, err
+		}
+		if nodeID == 0 {
+			nodeID = ident.NodeID
+		} else if nodeID != ident.NodeID {
+			return NodeReplicaInfo{}, errors.Errorf(
+				"store s%d belongs to n%d, but other stores belong to n%d",
+				ident.StoreID, ident.NodeID, nodeID)
+		}
+		if err := kvserver.IterateRangeDescriptors(ctx, eng, func(desc roachpb.RangeDescriptor) error {
+			// Replicas that were removed from their range but not garbage
+			// collected yet don't take part in recovery.
+			if _, ok := desc.GetReplicaDescriptor(ident.StoreID); !ok {
+				return nil
+			}
+			appliedIndex, _, err := stateloader.Make(desc.RangeID).LoadAppliedIndex(ctx, eng)
+			if err != nil {
+				return errors.Wrapf(err, "loading applied index of r%d", desc.RangeID)
+			}
+			info.Replicas = append(info.Replicas, ReplicaInfo{
+				NodeID:           ident.NodeID,
+				StoreID:          ident.StoreID,
+				Desc:             desc,
+				RaftAppliedIndex: appliedIndex,
+			})
+			return nil
+		}); err != nil {
+			return NodeReplicaInfo{}, err
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// PlanReplicas computes the updates needed to restore quorum to the ranges
+// that lost it, given the replica information collected from all surviving
+// nodes. The stores present in the collected information are considered
+// alive, all others dead. If deadStores is not empty, it must list every
+// store which is a member of a range that lost quorum and isn't present in
+// the collected information; this protects against information missing
+// from some of the surviving nodes.
+//
+// For every range that can't make progress with its live replicas, the
+// surviving replica with the highest applied index is chosen as the sole
+// voter of the range. Any writes which were committed but not applied on
+// that replica are lost.
+//
+// The returned problems describe inconsistencies found in the key space
+// covered by the chosen replicas, such as overlapping ranges (which
+// indicate that some survivors missed a split or a merge) and key spans
+// without any surviving replica. Applying a plan with problems may result in
+// a cluster that cannot recover on its own.
+func PlanReplicas(
+	nodes []NodeReplicaInfo, deadStores []roachpb.StoreID,
+) (plan ReplicaUpdatePlan, problems []string, _ error) {
+	liveStores := make(map[roachpb.StoreID]struct{})
+	replicasByRange := make(map[roachpb.RangeID][]ReplicaInfo)
+	for _, node := range nodes {
+		for _, replica := range node.Replicas {
+			liveStores[replica.StoreID] = struct{}{}
+			replicasByRange[replica.Desc.RangeID] = append(replicasByRange[replica.Desc.RangeID], replica)
+		}
+	}
+	dead := make(map[roachpb.StoreID]struct{})
+	for _, storeID := range deadStores {
+		if _, ok := liveStores[storeID]; ok {
+			return ReplicaUpdatePlan{}, nil, errors.Errorf(
+				"store s%d is marked as dead, but replica info was collected from it", storeID)
+		}
+		dead[storeID] = struct{}{}
+	}
+
+	var chosen []ReplicaInfo
+	for _, replicas := range replicasByRange {
+		// Prefer the replica which applied the most of the raft log. Break ties
+		// by store ID to make the plan deterministic.
+		sort.Slice(replicas, func(i, j int) bool {
+			if replicas[i].RaftAppliedIndex != replicas[j].RaftAppliedIndex {
+				return replicas[i].RaftAppliedIndex > replicas[j].RaftAppliedIndex
+			}
+			return replicas[i].StoreID > replicas[j].StoreID
+		})
+		chosen = append(chosen, replicas[0])
+	}
+	sort.Slice(chosen, func(i, j int) bool {
+		return chosen[i].Desc.StartKey.Less(chosen[j].Desc.StartKey)
+	})
+
+	for _, replica := range chosen {
+		desc := replica.Desc
+		isLive := func(rep roachpb.ReplicaDescriptor) bool {
+			_, ok := liveStores[rep.StoreID]
+			return ok
+		}
+		if desc.Replicas().CanMakeProgress(isLive) {
+			continue
+		}
+		if len(dead) > 0 {
+			for _, rep := range desc.Replicas().All() {
+				_, isDead := dead[rep.StoreID]
+				if !isLive(rep) && !isDead {
+					return ReplicaUpdatePlan{}, nil, errors.Errorf(
+						"r%d has a replica on store s%d which is neither marked as dead nor present "+
+							"in the collected replica info", desc.RangeID, rep.StoreID)
+				}
+			}
+		}
+		oldReplica, _ := desc.GetReplicaDescriptor(replica.StoreID)
+		plan.Updates = append(plan.Updates, ReplicaUpdate{
+			RangeID:      desc.RangeID,
+			StartKey:     desc.StartKey,
+			OldReplicaID: oldReplica.ReplicaID,
+			NewReplica: roachpb.ReplicaDescriptor{
+				NodeID:    replica.NodeID,
+				StoreID:   replica.StoreID,
+				ReplicaID: desc.NextReplicaID,
+			},
+			NextReplicaID: desc.NextReplicaID + 1,
+		})
+	}
+
+	// Check that the chosen replicas cover the key space without overlaps.
+	prevEndKey := roachpb.RKeyMin
+	var prevDesc roachpb.RangeDescriptor
+	for _, replica := range chosen {
+		desc := replica.Desc
+		switch {
+		case desc.StartKey.Less(prevEndKey):
+			problems = append(problems, fmt.Sprintf(
+				"range %s overlaps with range %s", &desc, &prevDesc))
+		case prevEndKey.Less(desc.StartKey):
+			problems = append(problems, fmt.Sprintf(
+				"key span [%s, %s) has no surviving replicas", prevEndKey, desc.StartKey))
+		}
+		if prevEndKey.Less(desc.EndKey) {
+			prevEndKey, prevDesc = desc.EndKey, desc
+		}
+	}
+	if !prevEndKey.Equal(roachpb.RKeyMax) {
+		problems = append(problems, fmt.Sprintf(
+			"key span [%s, %s) has no surviving replicas", prevEndKey, roachpb.RKeyMax))
+	}
+	return plan, problems, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// makeDesc returns a descriptor for the range with replicas on the given
+// stores, with node IDs matching store IDs.
+func makeDesc(
+	rangeID roachpb.RangeID, start, end roachpb.RKey, storeIDs ...roachpb.StoreID,
+) roachpb.RangeDescriptor {
+	var replicas []roachpb.ReplicaDescriptor
+	for i, storeID := range storeIDs {
+		replicas = append(replicas, roachpb.ReplicaDescriptor{
+			NodeID:    roachpb.NodeID(storeID),
+			StoreID:   storeID,
+			ReplicaID: roachpb.ReplicaID(i + 1),
+		})
+	}
+	return roachpb.RangeDescriptor{
+		RangeID:          rangeID,
+		StartKey:         start,
+		EndKey:           end,
+		InternalReplicas: replicas,
+		NextReplicaID:    roachpb.ReplicaID(len(storeIDs) + 1),
+	}
+}
+
+func makeInfo(
+	storeID roachpb.StoreID, desc roachpb.RangeDescriptor, appliedIndex uint64,
+) ReplicaInfo {
+	return ReplicaInfo{
+		NodeID:           roachpb.NodeID(storeID),
+		StoreID:          storeID,
+		Desc:             desc,
+		RaftAppliedIndex: appliedIndex,
+	}
+}
+
+func TestPlanReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r1 := makeDesc(1, roachpb.RKeyMin, roachpb.RKey("b"), 1, 2, 3)
+	r2 := makeDesc(2, roachpb.RKey("b"), roachpb.RKey("d"), 1, 4, 5)
+	r3 := makeDesc(3, roachpb.RKey("d"), roachpb.RKeyMax, 4, 5, 6)
+
+	t.Run("lost quorum", func(t *testing.T) {
+		// s2, s3 and s6 are lost: r1 lost quorum, while r2 and r3 kept a
+		// majority of their replicas.
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeInfo(1, r1, 10), makeInfo(1, r2, 20)}},
+			{Replicas: []ReplicaInfo{makeInfo(4, r2, 20), makeInfo(4, r3, 30)}},
+			{Replicas: []ReplicaInfo{makeInfo(5, r2, 20), makeInfo(5, r3, 30)}},
+		}
+		plan, problems, err := PlanReplicas(nodes, nil /* deadStores */)
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		require.Equal(t, []ReplicaUpdate{{
+			RangeID:       1,
+			StartKey:      roachpb.RKeyMin,
+			OldReplicaID:  1,
+			NewReplica:    roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 4},
+			NextReplicaID: 5,
+		}}, plan.Updates)
+
+		// The dead stores have to be complete if specified.
+		_, _, err = PlanReplicas(nodes, []roachpb.StoreID{2})
+		require.Regexp(t, "r1 has a replica on store s3 which is neither marked as dead", err)
+		_, _, err = PlanReplicas(nodes, []roachpb.StoreID{1, 2, 3})
+		require.Regexp(t, "store s1 is marked as dead", err)
+		_, _, err = PlanReplicas(nodes, []roachpb.StoreID{2, 3})
+		require.NoError(t, err)
+	})
+
+	t.Run("most up-to-date replica", func(t *testing.T) {
+		// Only s4 and s6 survive, so all ranges lost quorum. The replica on s6
+		// is ahead of the one on s4 for r3.
+		r3 := makeDesc(3, roachpb.RKey("d"), roachpb.RKeyMax, 4, 5, 7, 8, 6)
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{
+				makeInfo(4, makeDesc(1, roachpb.RKeyMin, roachpb.RKey("b"), 4, 2, 3), 10),
+				makeInfo(4, r2, 20),
+				makeInfo(4, r3, 30),
+			}},
+			{Replicas: []ReplicaInfo{makeInfo(6, r3, 31)}},
+		}
+		plan, problems, err := PlanReplicas(nodes, nil /* deadStores */)
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		require.Len(t, plan.Updates, 3)
+		require.Equal(t, roachpb.StoreID(4), plan.Updates[0].NewReplica.StoreID)
+		require.Equal(t, roachpb.StoreID(4), plan.Updates[1].NewReplica.StoreID)
+		require.Equal(t, roachpb.StoreID(6), plan.Updates[2].NewReplica.StoreID)
+		require.Equal(t, roachpb.ReplicaID(5), plan.Updates[2].OldReplicaID)
+		require.Equal(t, roachpb.ReplicaID(6), plan.Updates[2].NewReplica.ReplicaID)
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		// The only replica of r1 missed the split which created r4, and nothing
+		// survived for the rest of the key space.
+		stale := makeDesc(1, roachpb.RKeyMin, roachpb.RKey("c"), 1, 2, 3)
+		nodes := []NodeReplicaInfo{
+			{Replicas: []ReplicaInfo{makeInfo(1, stale, 10)}},
+			{Replicas: []ReplicaInfo{makeInfo(7, makeDesc(4, roachpb.RKey("a"), roachpb.RKey("b"), 7, 8, 9), 10)}},
+		}
+		_, problems, err := PlanReplicas(nodes, nil /* deadStores */)
+		require.NoError(t, err)
+		require.Len(t, problems, 2)
+		require.Regexp(t, `range r4:.* overlaps with range r1:`, problems[0])
+		require.Regexp(t, `key span \[.*c.*, /Max\) has no surviving replicas`, problems[1])
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package loqrecovery implements the offline recovery of ranges that lost
+// quorum after the permanent loss of a majority of their replicas.
+//
+// Recovery is performed with all nodes of the cluster shut down. First,
+// information about all replicas is collected from the stores of the
+// surviving nodes (CollectReplicaInfo). Then, for every range that lost
+// quorum, the most up-to-date surviving replica is chosen and an update is
+// planned which turns it into the sole voter of the range (PlanReplicas). The
+// plan can be reviewed by the operator before it is applied to the stores of
+// the surviving nodes that hold the chosen replicas (PrepareUpdateReplicas).
+//
+// Once the nodes are restarted, the chosen replicas can make progress on their
+// own and are up-replicated to restore the redundancy of their ranges.
+package loqrecovery
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// ReplicaInfo describes a replica found on one of the surviving stores.
+type ReplicaInfo struct {
+	NodeID  roachpb.NodeID  `json:"node_id"`
+	StoreID roachpb.StoreID `json:"store_id"`
+	// Desc is the range descriptor as seen by the replica.
+	Desc roachpb.RangeDescriptor `json:"desc"`
+	// RaftAppliedIndex is the index of the last raft log entry applied by the
+	// replica. It is used to choose the most up-to-date surviving replica.
+	RaftAppliedIndex uint64 `json:"raft_applied_index"`
+}
+
+// NodeReplicaInfo is the information collected from the stores of a single
+// node.
+type NodeReplicaInfo struct {
+	Replicas []ReplicaInfo `json:"replicas"`
+}
+
+// ReplicaUpdate describes the change of a surviving replica into the sole
+// voter of its range.
+type ReplicaUpdate struct {
+	RangeID  roachpb.RangeID `json:"range_id"`
+	StartKey roachpb.RKey    `json:"start_key"`
+	// OldReplicaID is the ID of the replica in the descriptor the plan was
+	// made from. It is used to detect changes to the store since the
+	// information was collected.
+	OldReplicaID roachpb.ReplicaID `json:"old_replica_id"`
+	// NewReplica is the only replica of the range after the update. It uses a
+	// fresh replica ID, so that any other surviving replica of the range does
+	// not recognize it as a member of the old incarnation of the range.
+	NewReplica    roachpb.ReplicaDescriptor `json:"new_replica"`
+	NextReplicaID roachpb.ReplicaID         `json:"next_replica_id"`
+}
+
+// ReplicaUpdatePlan is the set of updates needed to restore quorum to all
+// ranges that lost it.
+type ReplicaUpdatePlan struct {
+	Updates []ReplicaUpdate `json:"updates"`
+}
+
+// WriteJSON writes the value to w in a human-readable JSON format, suitable
+// for review by an operator.
+func WriteJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ReadJSON reads a value written by WriteJSON from r.
+func ReadJSON(r io.Reader, v interface{}) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
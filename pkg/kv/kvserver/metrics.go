@@ -972,6 +972,20 @@ var (
 		Unit:        metric.Unit_COUNT,
 	}
 
+	// Replica circuit breaker metrics.
+	metaReplicaCircuitBreakerCurTripped = metric.Metadata{
+		Name:        "kv.replica_circuit_breaker.num_tripped_replicas",
+		Help:        "Number of Replicas for which the per-Replica circuit breaker is currently tripped",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaReplicaCircuitBreakerCumTripped = metric.Metadata{
+		Name:        "kv.replica_circuit_breaker.num_tripped_events",
+		Help:        "Number of times the per-Replica circuit breakers tripped since process start",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
+
 	// Backpressure metrics.
 	metaBackpressuredOnSplitRequests = metric.Metadata{
 		Name:        "requests.backpressure.split",
@@ -1020,6 +1034,24 @@ var (
 		Measurement: "Encryption At Rest",
 		Unit:        metric.Unit_CONST,
 	}
+	metaEncryptionRetiredKeyBytes = metric.Metadata{
+		Name:        "rocksdb.encryption.retired-key-bytes",
+		Help:        "Size of the sstables encrypted with a data key other than the active one",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaEncryptionReencryptedFiles = metric.Metadata{
+		Name:        "rocksdb.encryption.reencrypted-files",
+		Help:        "Number of sstables rewritten under the active data key by re-encryption",
+		Measurement: "SSTables",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaEncryptionReencryptedBytes = metric.Metadata{
+		Name:        "rocksdb.encryption.reencrypted-bytes",
+		Help:        "Number of bytes of sstables rewritten under the active data key by re-encryption",
+		Measurement: "Storage",
+		Unit:        metric.Unit_BYTES,
+	}
 
 	// Closed timestamp metrics.
 	metaClosedTimestampMaxBehindNanos = metric.Metadata{
@@ -1209,6 +1241,10 @@ type StoreMetrics struct {
 	SlowLeaseRequests *metric.Gauge
 	SlowRaftRequests  *metric.Gauge
 
+	// Replica circuit breaker stats.
+	ReplicaCircuitBreakerCurTripped *metric.Gauge
+	ReplicaCircuitBreakerCumTripped *metric.Counter
+
 	// Backpressure counts.
 	BackpressuredOnSplitRequests *metric.Gauge
 
@@ -1223,6 +1259,10 @@ type StoreMetrics struct {
 	// Encryption-at-rest stats.
 	// EncryptionAlgorithm is an enum representing the cipher in use, so we use a gauge.
 	EncryptionAlgorithm *metric.Gauge
+	// Re-encryption of sstables under retired data keys.
+	EncryptionRetiredKeyBytes  *metric.Gauge
+	EncryptionReencryptedFiles *metric.Counter
+	EncryptionReencryptedBytes *metric.Counter
 
 	// RangeFeed counts.
 	RangeFeedMetrics *rangefeed.Metrics
@@ -1589,6 +1629,10 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		SlowLeaseRequests: metric.NewGauge(metaSlowLeaseRequests),
 		SlowRaftRequests:  metric.NewGauge(metaSlowRaftRequests),
 
+		// Replica circuit breaker counters.
+		ReplicaCircuitBreakerCurTripped: metric.NewGauge(metaReplicaCircuitBreakerCurTripped),
+		ReplicaCircuitBreakerCumTripped: metric.NewCounter(metaReplicaCircuitBreakerCumTripped),
+
 		// Backpressure counters.
 		BackpressuredOnSplitRequests: metric.NewGauge(metaBackpressuredOnSplitRequests),
 
@@ -1600,7 +1644,10 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		AddSSTableProposalEngineDelay: metric.NewCounter(metaAddSSTableEvalEngineDelay),
 
 		// Encryption-at-rest.
-		EncryptionAlgorithm: metric.NewGauge(metaEncryptionAlgorithm),
+		EncryptionAlgorithm:        metric.NewGauge(metaEncryptionAlgorithm),
+		EncryptionRetiredKeyBytes:  metric.NewGauge(metaEncryptionRetiredKeyBytes),
+		EncryptionReencryptedFiles: metric.NewCounter(metaEncryptionReencryptedFiles),
+		EncryptionReencryptedBytes: metric.NewCounter(metaEncryptionReencryptedBytes),
 
 		// RangeFeed counters.
 		RangeFeedMetrics: rangefeed.NewMetrics(),
@@ -1676,6 +1723,11 @@ func (sm *StoreMetrics) updateEngineMetrics(m storage.Metrics) {
 
 func (sm *StoreMetrics) updateEnvStats(stats storage.EnvStats) {
 	sm.EncryptionAlgorithm.Update(int64(stats.EncryptionType))
+	var totalKeyBytes uint64
+	for _, u := range stats.DataKeyUsage {
+		totalKeyBytes += u.Bytes
+	}
+	sm.EncryptionRetiredKeyBytes.Update(int64(totalKeyBytes - stats.ActiveKeyBytes))
 }
 
 func (sm *StoreMetrics) handleMetricsResult(ctx context.Context, metric result.Metrics) {
@@ -25,11 +25,35 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRangeFeedCatchupScanBytes = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scan_bytes",
+		Help:        "Bytes read by RangeFeed catchup scans",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRangeFeedCatchupScansWaiting = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scans_waiting",
+		Help:        "Number of RangeFeed catchup scans waiting to begin",
+		Measurement: "Scans",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRangeFeedCatchupScansInFlight = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scans_in_flight",
+		Help:        "Number of RangeFeed catchup scans in progress",
+		Measurement: "Scans",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Metrics are for production monitoring of RangeFeeds.
 type Metrics struct {
 	RangeFeedCatchupScanNanos *metric.Counter
+	RangeFeedCatchupScanBytes *metric.Counter
+	// RangeFeedCatchupScansWaiting and RangeFeedCatchupScansInFlight track the
+	// catchup scans waiting for and holding a slot in the store's concurrency
+	// limit, respectively.
+	RangeFeedCatchupScansWaiting  *metric.Gauge
+	RangeFeedCatchupScansInFlight *metric.Gauge
 
 	RangeFeedSlowClosedTimestampLogN  log.EveryN
 	RangeFeedSlowClosedTimestampNudge singleflight.Group
@@ -47,6 +71,9 @@ func (*Metrics) MetricStruct() {}
 func NewMetrics() *Metrics {
 	return &Metrics{
 		RangeFeedCatchupScanNanos:            metric.NewCounter(metaRangeFeedCatchupScanNanos),
+		RangeFeedCatchupScanBytes:            metric.NewCounter(metaRangeFeedCatchupScanBytes),
+		RangeFeedCatchupScansWaiting:         metric.NewGauge(metaRangeFeedCatchupScansWaiting),
+		RangeFeedCatchupScansInFlight:        metric.NewGauge(metaRangeFeedCatchupScansInFlight),
 		RangeFeedSlowClosedTimestampLogN:     log.Every(5 * time.Second),
 		RangeFeedSlowClosedTimestampNudgeSem: make(chan struct{}, 1024),
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -74,6 +75,11 @@ type Config struct {
 	// all streams to make sure they have not been canceled.
 	CheckStreamsInterval time.Duration
 
+	// CatchupScanRate, if set, limits the rate (in bytes per second) at which
+	// catch-up scans read from the engine. It is typically shared by all
+	// Processors on a store.
+	CatchupScanRate *rate.Limiter
+
 	// Metrics is for production monitoring of RangeFeeds.
 	Metrics *Metrics
 }
@@ -402,7 +408,7 @@ func (p *Processor) Register(
 
 	r := newRegistration(
 		span.AsRawSpanWithNoLocals(), startTS, catchupIterConstructor, withDiff,
-		p.Config.EventChanCap, p.Metrics, stream, errC,
+		p.Config.EventChanCap, p.CatchupScanRate, p.Metrics, stream, errC,
 	)
 	select {
 	case p.regC <- r:
@@ -582,6 +588,10 @@ func (p *Processor) consumeLogicalOps(ctx context.Context, ops []enginepb.MVCCLo
 		case *enginepb.MVCCAbortTxnOp:
 			// No updates to publish.
 
+		case *enginepb.MVCCDeleteRangeOp:
+			// No updates to publish. Range tombstones disconnect the rangefeed
+			// before they reach the processor, see Replica.RangeFeed.
+
 		default:
 			panic(errors.AssertionFailedf("unknown logical op %T", t))
 		}
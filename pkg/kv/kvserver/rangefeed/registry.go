@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
 )
 
 // Stream is a object capable of transmitting RangeFeedEvents.
@@ -57,6 +58,7 @@ type registration struct {
 	catchupTimestamp       hlc.Timestamp
 	catchupIterConstructor func() storage.SimpleMVCCIterator
 	withDiff               bool
+	catchupRate            *rate.Limiter
 	metrics                *Metrics
 
 	// Output.
@@ -89,6 +91,7 @@ func newRegistration(
 	catchupIterConstructor func() storage.SimpleMVCCIterator,
 	withDiff bool,
 	bufferSz int,
+	catchupRate *rate.Limiter,
 	metrics *Metrics,
 	stream Stream,
 	errC chan<- *roachpb.Error,
@@ -98,6 +101,7 @@ func newRegistration(
 		catchupTimestamp:       startTS,
 		catchupIterConstructor: catchupIterConstructor,
 		withDiff:               withDiff,
+		catchupRate:            catchupRate,
 		metrics:                metrics,
 		stream:                 stream,
 		errC:                   errC,
@@ -232,7 +236,7 @@ func (r *registration) disconnect(pErr *roachpb.Error) {
 // have been emitted.
 func (r *registration) outputLoop(ctx context.Context) error {
 	// If the registration has a catch-up scan, run it.
-	if err := r.maybeRunCatchupScan(); err != nil {
+	if err := r.maybeRunCatchupScan(ctx); err != nil {
 		err = errors.Wrap(err, "catch-up scan failed")
 		log.Errorf(ctx, "%v", err)
 		return err
@@ -272,6 +276,10 @@ func (r *registration) runOutputLoop(ctx context.Context) {
 	r.disconnect(roachpb.NewError(err))
 }
 
+// catchupScanRateChunk is the number of bytes a catch-up scan reads between
+// waits on its rate limiter.
+const catchupScanRateChunk = 64 << 10 // 64 KiB
+
 // maybeRunCatchupScan starts a catchup scan which will output entries for all
 // recorded changes in the replica that are newer than the catchupTimestamp.
 // This uses the iterator provided when the registration was originally created;
@@ -279,17 +287,34 @@ func (r *registration) runOutputLoop(ctx context.Context) {
 //
 // If the registration does not have a catchUpIteratorConstructor, this method
 // is a no-op.
-func (r *registration) maybeRunCatchupScan() error {
+//
+// If the registration has a catch-up scan rate limiter, the scan waits on it
+// for every catchupScanRateChunk bytes read.
+func (r *registration) maybeRunCatchupScan(ctx context.Context) error {
 	if r.catchupIterConstructor == nil {
 		return nil
 	}
 	catchupIter := r.catchupIterConstructor()
 	r.catchupIterConstructor = nil
 	start := timeutil.Now()
+	var readBytes, unpacedBytes int64
 	defer func() {
 		catchupIter.Close()
 		r.metrics.RangeFeedCatchupScanNanos.Inc(timeutil.Since(start).Nanoseconds())
+		r.metrics.RangeFeedCatchupScanBytes.Inc(readBytes)
 	}()
+	pace := func(n int) error {
+		readBytes += int64(n)
+		unpacedBytes += int64(n)
+		if r.catchupRate == nil || unpacedBytes < catchupScanRateChunk {
+			return nil
+		}
+		n, unpacedBytes = int(unpacedBytes), 0
+		if burst := r.catchupRate.Burst(); n > burst {
+			n = burst
+		}
+		return r.catchupRate.WaitN(ctx, n)
+	}
 
 	var a bufalloc.ByteAllocator
 	startKey := storage.MakeMVCCMetadataKey(r.span.Key)
@@ -335,6 +360,9 @@ func (r *registration) maybeRunCatchupScan() error {
 
 		unsafeKey := catchupIter.UnsafeKey()
 		unsafeVal := catchupIter.UnsafeValue()
+		if err := pace(len(unsafeKey.Key) + len(unsafeVal)); err != nil {
+			return err
+		}
 		if !unsafeKey.IsValue() {
 			// Found a metadata key.
 			if err := protoutil.Unmarshal(unsafeVal, &meta); err != nil {
@@ -107,6 +107,7 @@ func newTestRegistration(
 			makeIteratorConstructor(catchup),
 			withDiff,
 			5,
+			nil, /* catchupRate */
 			NewMetrics(),
 			s,
 			errC,
@@ -253,9 +254,11 @@ func TestRegistrationCatchUpScan(t *testing.T) {
 	}, hlc.Timestamp{WallTime: 4}, iter, true /* withDiff */)
 
 	require.Zero(t, r.metrics.RangeFeedCatchupScanNanos.Count())
-	require.NoError(t, r.maybeRunCatchupScan())
+	require.Zero(t, r.metrics.RangeFeedCatchupScanBytes.Count())
+	require.NoError(t, r.maybeRunCatchupScan(context.Background()))
 	require.True(t, iter.closed)
 	require.NotZero(t, r.metrics.RangeFeedCatchupScanNanos.Count())
+	require.NotZero(t, r.metrics.RangeFeedCatchupScanBytes.Count())
 
 	// Compare the events sent on the registration's Stream to the expected events.
 	expEvents := []*roachpb.RangeFeedEvent{
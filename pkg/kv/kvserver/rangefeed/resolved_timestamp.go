@@ -205,6 +205,12 @@ func (rts *resolvedTimestamp) consumeLogicalOp(op enginepb.MVCCLogicalOp) bool {
 		}
 		return rts.intentQ.Del(t.TxnID)
 
+	case *enginepb.MVCCDeleteRangeOp:
+		// Range tombstones are written non-transactionally, so like committed
+		// values they don't affect the resolved timestamp.
+		rts.assertOpAboveRTS(op, t.Timestamp)
+		return false
+
 	default:
 		panic(errors.AssertionFailedf("unknown logical op %T", t))
 	}
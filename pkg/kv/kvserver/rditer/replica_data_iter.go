@@ -68,8 +68,9 @@ func MakeAllKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 //
 // 1. Replicated range-id local key range
 // 2. Range-local key range
-// 3. Lock-table key ranges (optional)
-// 4. User key range
+// 3. Range tombstone key range
+// 4. Lock-table key ranges (optional)
+// 5. User key range
 func MakeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 	return makeRangeKeyRanges(d, true /* replicatedOnly */)
 }
@@ -77,19 +78,22 @@ func MakeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 func makeRangeKeyRanges(d *roachpb.RangeDescriptor, replicatedOnly bool) []KeyRange {
 	rangeIDLocal := MakeRangeIDLocalKeyRange(d.RangeID, replicatedOnly)
 	rangeLocal := makeRangeLocalKeyRange(d)
+	rangeTombstones := makeRangeTombstoneKeyRange(d)
 	user := MakeUserKeyRange(d)
 	if storage.DisallowSeparatedIntents {
 		return []KeyRange{
 			rangeIDLocal,
 			rangeLocal,
+			rangeTombstones,
 			user,
 		}
 	}
 	rangeLockTable := makeRangeLockTableKeyRanges(d)
-	ranges := make([]KeyRange, 3+len(rangeLockTable))
+	ranges := make([]KeyRange, 4+len(rangeLockTable))
 	ranges[0] = rangeIDLocal
 	ranges[1] = rangeLocal
-	i := 2
+	ranges[2] = rangeTombstones
+	i := 3
 	for j := range rangeLockTable {
 		ranges[i] = rangeLockTable[j]
 		i++
@@ -103,11 +107,13 @@ func makeRangeKeyRanges(d *roachpb.RangeDescriptor, replicatedOnly bool) []KeyRa
 // returned in the following sorted order:
 // 1. Replicated range-id local key range
 // 2. Range-local key range
-// 3. User key range
+// 3. Range tombstone key range
+// 4. User key range
 func MakeReplicatedKeyRangesExceptLockTable(d *roachpb.RangeDescriptor) []KeyRange {
 	return []KeyRange{
 		MakeRangeIDLocalKeyRange(d.RangeID, true /* replicatedOnly */),
 		makeRangeLocalKeyRange(d),
+		makeRangeTombstoneKeyRange(d),
 		MakeUserKeyRange(d),
 	}
 }
@@ -116,21 +122,25 @@ func MakeReplicatedKeyRangesExceptLockTable(d *roachpb.RangeDescriptor) []KeyRan
 // replicated for the given Range, except for the replicated range-id local key range.
 // These are returned in the following sorted order:
 // 1. Range-local key range
-// 2. Lock-table key ranges (optional)
-// 3. User key range
+// 2. Range tombstone key range
+// 3. Lock-table key ranges (optional)
+// 4. User key range
 func MakeReplicatedKeyRangesExceptRangeID(d *roachpb.RangeDescriptor) []KeyRange {
 	rangeLocal := makeRangeLocalKeyRange(d)
+	rangeTombstones := makeRangeTombstoneKeyRange(d)
 	user := MakeUserKeyRange(d)
 	if storage.DisallowSeparatedIntents {
 		return []KeyRange{
 			rangeLocal,
+			rangeTombstones,
 			user,
 		}
 	}
 	rangeLockTable := makeRangeLockTableKeyRanges(d)
-	ranges := make([]KeyRange, 2+len(rangeLockTable))
+	ranges := make([]KeyRange, 3+len(rangeLockTable))
 	ranges[0] = rangeLocal
-	i := 1
+	ranges[1] = rangeTombstones
+	i := 2
 	for j := range rangeLockTable {
 		ranges[i] = rangeLockTable[j]
 		i++
@@ -167,6 +177,22 @@ func makeRangeLocalKeyRange(d *roachpb.RangeDescriptor) KeyRange {
 	}
 }
 
+// makeRangeTombstoneKeyRange returns the key range holding the MVCC range
+// tombstones of the range, which are stored in the local keyspace but cover
+// the range's user keys.
+func makeRangeTombstoneKeyRange(d *roachpb.RangeDescriptor) KeyRange {
+	// The first range in the global keyspace can start earlier than LocalMax,
+	// but range tombstones only cover keys from LocalMax onwards.
+	globalStartKey := d.StartKey.AsRawKey()
+	if d.StartKey.Equal(roachpb.RKeyMin) {
+		globalStartKey = keys.LocalMax
+	}
+	return KeyRange{
+		Start: storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(globalStartKey)),
+		End:   storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(d.EndKey.AsRawKey())),
+	}
+}
+
 // makeRangeLockTableKeyRanges returns the 2 lock table key ranges.
 func makeRangeLockTableKeyRanges(d *roachpb.RangeDescriptor) [2]KeyRange {
 	// Handle doubly-local lock table keys since range descriptor key
@@ -27,7 +27,9 @@ func ComputeStatsForRange(
 
 	ms := enginepb.MVCCStats{}
 	for _, keyRange := range MakeReplicatedKeyRangesExceptLockTable(d) {
-		msDelta, err := iter.ComputeStats(keyRange.Start.Key, keyRange.End.Key, nowNanos)
+		msDelta, err := storage.ComputeStatsWithRangeTombstones(
+			reader, iter, keyRange.Start.Key, keyRange.End.Key, nowNanos,
+		)
 		if err != nil {
 			return enginepb.MVCCStats{}, err
 		}
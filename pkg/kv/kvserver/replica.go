@@ -205,6 +205,10 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// cpuStats tracks the nanoseconds spent evaluating requests and applying
+	// raft commands in order to aid in CPU-based rebalancing and splitting
+	// decisions.
+	cpuStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -255,6 +259,10 @@ type Replica struct {
 	// metrics about it.
 	tenantLimiter tenantrate.Limiter
 
+	// breaker fails requests fast while the replica is unable to replicate
+	// writes. See replicaCircuitBreaker.
+	breaker *replicaCircuitBreaker
+
 	mu struct {
 		// Protects all fields in the mu struct.
 		syncutil.RWMutex
@@ -936,8 +944,8 @@ func (r *Replica) GetMVCCStats() enginepb.MVCCStats {
 // works when the load based splitting cluster setting is enabled.
 //
 // Use QueriesPerSecond() for current QPS stats for all other purposes.
-func (r *Replica) GetSplitQPS() float64 {
-	return r.loadBasedSplitter.LastQPS(timeutil.Now())
+func (r *Replica) GetSplitQPS(ctx context.Context) float64 {
+	return r.loadBasedSplitter.LastQPS(ctx, timeutil.Now())
 }
 
 // ContainsKey returns whether this range contains the specified key.
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/grunning"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -350,6 +351,7 @@ func (sm *replicaStateMachine) NewBatch(ephemeral bool) apply.Batch {
 	*b.state.Stats = *r.mu.state.Stats
 	r.mu.RUnlock()
 	b.start = timeutil.Now()
+	b.cpu.Start()
 	return b
 }
 
@@ -366,6 +368,11 @@ type replicaAppBatch struct {
 
 	// batch accumulates writes implied by the raft entries in this batch.
 	batch storage.Batch
+	// raftBatch, if non-nil, accumulates the writes to the store's separate
+	// raft engine implied by the raft entries in this batch. It is committed
+	// only after batch has been durably committed, so that the raft engine
+	// never runs ahead of the state machine. See Store.RaftEngine.
+	raftBatch storage.Batch
 	// state is this batch's view of the replica's state. It is copied from
 	// under the Replica.mu when the batch is initialized and is updated in
 	// stageTrivialReplicatedEvalResult.
@@ -391,6 +398,8 @@ type replicaAppBatch struct {
 	emptyEntries int
 	mutations    int
 	start        time.Time
+	// cpu measures the CPU time spent staging and committing the batch.
+	cpu grunning.Timer
 }
 
 // Stage implements the apply.Batch interface. The method handles the first
@@ -620,7 +629,7 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		//
 		// Alternatively if we discover that the RHS has already been removed
 		// from this store, clean up its data.
-		splitPreApply(ctx, b.batch, res.Split.SplitTrigger, b.r)
+		splitPreApply(ctx, b.batch, b.raftReadWriter(), res.Split.SplitTrigger, b.r)
 
 		// The rangefeed processor will no longer be provided logical ops for
 		// its entire range, so it needs to be shut down and all registrations
@@ -673,6 +682,11 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to destroy replica before merge")
 		}
+		if b.r.store.separateRaftEngine() {
+			if err := clearRaftEngineData(rhsRepl.RangeID, b.raftReadWriter()); err != nil {
+				return wrapWithNonDeterministicFailure(err, "unable to destroy replica before merge")
+			}
+		}
 
 		// Shut down rangefeed processors on either side of the merge.
 		//
@@ -700,7 +714,8 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 
 	if res.State != nil && res.State.TruncatedState != nil {
 		if apply, err := handleTruncatedStateBelowRaft(
-			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader, b.batch,
+			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader,
+			b.batch, b.raftReadWriter(),
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to handle truncated state")
 		} else if !apply {
@@ -761,6 +776,11 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to destroy replica before removal")
 		}
+		if b.r.store.separateRaftEngine() {
+			if err := clearRaftEngineData(b.r.RangeID, b.raftReadWriter()); err != nil {
+				return wrapWithNonDeterministicFailure(err, "unable to destroy replica before removal")
+			}
+		}
 	}
 
 	// Provide the command's corresponding logical operations to the Replica's
@@ -839,12 +859,24 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// then we sync this batch as it is not safe to call postDestroyRaftMuLocked
 	// before ensuring that the replica's data has been synchronously removed.
 	// See handleChangeReplicasResult().
-	sync := b.changeRemovesReplica
+	//
+	// If the batch is accompanied by writes to a separate raft engine, it is
+	// synced as well. These writes (log truncations and HardStates synthesized
+	// for split RHS replicas) are only safe to persist once the state machine
+	// writes they follow from are durable.
+	sync := b.changeRemovesReplica || b.raftBatch != nil
 	if err := b.batch.Commit(sync); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to commit Raft entry batch")
 	}
 	b.batch.Close()
 	b.batch = nil
+	if b.raftBatch != nil {
+		if err := b.raftBatch.Commit(false /* sync */); err != nil {
+			return wrapWithNonDeterministicFailure(err, "unable to commit raft engine batch")
+		}
+		b.raftBatch.Close()
+		b.raftBatch = nil
+	}
 
 	// Update the replica's applied indexes and mvcc stats.
 	r.mu.Lock()
@@ -890,6 +922,20 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	return nil
 }
 
+// raftReadWriter returns the ReadWriter through which the application batch
+// stages writes to the raft log and HardState. This is the batch itself unless
+// the store keeps its raft log in a separate engine, in which case a batch on
+// that engine is created lazily.
+func (b *replicaAppBatch) raftReadWriter() storage.ReadWriter {
+	if !b.r.store.separateRaftEngine() {
+		return b.batch
+	}
+	if b.raftBatch == nil {
+		b.raftBatch = b.r.store.RaftEngine().NewBatch()
+	}
+	return b.raftBatch
+}
+
 // addAppliedStateKeyToBatch adds the applied state key to the application
 // batch's RocksDB batch. This records the highest raft and lease index that
 // have been applied as of this batch. It also records the Range's mvcc stats.
@@ -944,6 +990,9 @@ func (b *replicaAppBatch) recordStatsOnCommit() {
 
 	elapsed := timeutil.Since(b.start)
 	b.r.store.metrics.RaftCommandCommitLatency.RecordValue(elapsed.Nanoseconds())
+	// Attribute the CPU time spent staging and committing the batch to the
+	// replica. This is paid by every replica, not just the leaseholder.
+	b.r.recordCPU(b.cpu.Stop())
 }
 
 // Close implements the apply.Batch interface.
@@ -951,6 +1000,11 @@ func (b *replicaAppBatch) Close() {
 	if b.batch != nil {
 		b.batch.Close()
 	}
+	if b.raftBatch != nil {
+		b.raftBatch.Close()
+	}
+	// Unlock the goroutine from its thread if the batch was not committed.
+	b.cpu.Stop()
 	*b = replicaAppBatch{}
 }
 
@@ -0,0 +1,279 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// replicaCircuitBreakerSlowReplicationThreshold is the duration after which a
+// proposal that hasn't been applied trips the circuit breaker of the replica
+// that proposed it.
+var replicaCircuitBreakerSlowReplicationThreshold = settings.RegisterDurationSetting(
+	"kv.replica_circuit_breaker.slow_replication_threshold",
+	"duration after which slow proposals trip the per-Replica circuit breaker (zero duration disables breakers)",
+	15*time.Second,
+	settings.NonNegativeDuration,
+).WithPublic()
+
+// errReplicaUnavailable marks the errors returned by replicas whose circuit
+// breaker is tripped.
+var errReplicaUnavailable = errors.New("replica unavailable")
+
+// IsReplicaUnavailableError returns true if the error was returned by a
+// replica whose circuit breaker is tripped.
+func IsReplicaUnavailableError(err error) bool {
+	return errors.Is(err, errReplicaUnavailable)
+}
+
+// replicaCircuitBreaker fails requests to a replica fast while the replica is
+// unable to replicate writes, for example because its range lost quorum.
+// Without it, such requests would hang until their context is canceled, tying
+// up goroutines and client connections across the cluster.
+//
+// The breaker trips when a proposal is not applied within the slow replication
+// threshold. Once tripped, requests to the replica fail with an error naming
+// the range and its replicas, and proposals which are waiting to be applied
+// return an ambiguous result. Meanwhile, a probe periodically attempts to
+// replicate a write through the range, bypassing the breaker, and resets the
+// breaker once it succeeds.
+type replicaCircuitBreaker struct {
+	ambCtx     log.AmbientContext
+	stopper    *stop.Stopper
+	st         *cluster.Settings
+	cumTripped *metric.Counter
+	// probe attempts to replicate a write through the range. It returns nil
+	// once requests to the replica can succeed again.
+	probe          func(context.Context) error
+	probeRetryOpts retry.Options
+
+	// state holds the current *breakerState. It is read without locking, as
+	// every request to the replica consults it, and replaced under mu when the
+	// breaker trips or resets.
+	state atomic.Value
+
+	mu struct {
+		syncutil.Mutex
+		// probing is set while the probe is running.
+		probing bool
+	}
+}
+
+// breakerState is the state of a replicaCircuitBreaker. It is immutable.
+type breakerState struct {
+	// err is non-nil while the breaker is tripped.
+	err error
+	// signal is closed when the breaker trips, and replaced when it resets.
+	signal chan struct{}
+}
+
+func newReplicaCircuitBreaker(
+	ambCtx log.AmbientContext,
+	stopper *stop.Stopper,
+	st *cluster.Settings,
+	cumTripped *metric.Counter,
+	probe func(context.Context) error,
+) *replicaCircuitBreaker {
+	br := &replicaCircuitBreaker{
+		ambCtx:     ambCtx,
+		stopper:    stopper,
+		st:         st,
+		cumTripped: cumTripped,
+		probe:      probe,
+		probeRetryOpts: retry.Options{
+			InitialBackoff: time.Second,
+			MaxBackoff:     10 * time.Second,
+			Multiplier:     2,
+		},
+	}
+	br.state.Store(&breakerState{signal: make(chan struct{})})
+	return br
+}
+
+// slowReplicationThreshold returns the duration after which a proposal that
+// hasn't been applied trips the breaker, or zero if breakers are disabled.
+func (br *replicaCircuitBreaker) slowReplicationThreshold() time.Duration {
+	return replicaCircuitBreakerSlowReplicationThreshold.Get(&br.st.SV)
+}
+
+// Err returns the error requests to the replica fail with while the breaker is
+// tripped, or nil if it isn't tripped.
+func (br *replicaCircuitBreaker) Err() error {
+	return br.loadState().err
+}
+
+// Signal returns a channel which is closed when the breaker trips. Err has to
+// be consulted once the channel is closed, as the breaker may have been reset
+// since.
+func (br *replicaCircuitBreaker) Signal() <-chan struct{} {
+	return br.loadState().signal
+}
+
+func (br *replicaCircuitBreaker) loadState() *breakerState {
+	return br.state.Load().(*breakerState)
+}
+
+// trip trips the breaker with the provided error, unless it is tripped
+// already, and makes sure that the probe is running.
+func (br *replicaCircuitBreaker) trip(ctx context.Context, err error) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	if state := br.loadState(); state.err == nil {
+		// The error is published before the signal is closed, so that it is
+		// observed by anyone woken up by the signal.
+		br.state.Store(&breakerState{err: err, signal: state.signal})
+		close(state.signal)
+		br.cumTripped.Inc(1)
+		log.Warningf(ctx, "circuit breaker tripped: %v", err)
+	}
+	if br.mu.probing {
+		return
+	}
+	ctx = br.ambCtx.AnnotateCtx(context.Background())
+	if err := br.stopper.RunAsyncTask(ctx, "replica-circuit-breaker-probe", br.runProbe); err != nil {
+		// The stopper is quiescing, so there's no point in resetting the breaker.
+		return
+	}
+	br.mu.probing = true
+}
+
+// runProbe runs the probe until it succeeds, and resets the breaker.
+func (br *replicaCircuitBreaker) runProbe(ctx context.Context) {
+	ctx, cancel := br.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+	for r := retry.StartWithCtx(ctx, br.probeRetryOpts); r.Next(); {
+		threshold := br.slowReplicationThreshold()
+		if threshold == 0 {
+			// Breakers were disabled.
+			break
+		}
+		err := contextutil.RunWithTimeout(ctx, "replica circuit breaker probe", threshold, br.probe)
+		if err == nil {
+			break
+		}
+		log.VEventf(ctx, 1, "circuit breaker probe failed: %v", err)
+	}
+
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.mu.probing = false
+	if ctx.Err() != nil {
+		// The stopper is quiescing.
+		return
+	}
+	if br.loadState().err != nil {
+		br.state.Store(&breakerState{signal: make(chan struct{})})
+		log.Infof(ctx, "circuit breaker reset")
+	}
+}
+
+// replicaUnavailableError returns the error that requests to a replica whose
+// circuit breaker tripped fail with.
+func replicaUnavailableError(
+	desc *roachpb.RangeDescriptor,
+	replDesc roachpb.ReplicaDescriptor,
+	lm liveness.IsLiveMap,
+	dur time.Duration,
+) error {
+	var liveReplicas, otherReplicas []roachpb.ReplicaDescriptor
+	for _, rDesc := range desc.Replicas().All() {
+		if lm[rDesc.NodeID].IsLive {
+			liveReplicas = append(liveReplicas, rDesc)
+		} else {
+			otherReplicas = append(otherReplicas, rDesc)
+		}
+	}
+	return errors.Mark(errors.Errorf(
+		"replica %s of r%d unavailable: proposals have not been applied for %.2fs "+
+			"(live replicas: %s, non-live replicas: %s)",
+		replDesc, desc.RangeID, dur.Seconds(),
+		roachpb.MakeReplicaDescriptors(liveReplicas),
+		roachpb.MakeReplicaDescriptors(otherReplicas),
+	), errReplicaUnavailable)
+}
+
+// isCircuitBreakerProbe returns true if the batch is the write sent by the
+// circuit breaker probe, which bypasses the breaker.
+func isCircuitBreakerProbe(ba *roachpb.BatchRequest) bool {
+	if !ba.IsSingleRequest() {
+		return false
+	}
+	put, ok := ba.Requests[0].GetInner().(*roachpb.PutRequest)
+	if !ok || !put.Inline {
+		return false
+	}
+	rk, err := keys.Addr(put.Key)
+	if err != nil {
+		return false
+	}
+	return put.Key.Equal(keys.RangeProbeKey(rk))
+}
+
+// tripCircuitBreaker trips the replica's circuit breaker after a proposal
+// wasn't applied for the provided duration.
+func (r *Replica) tripCircuitBreaker(ctx context.Context, dur time.Duration) {
+	replDesc, err := r.GetReplicaDescriptor()
+	if err != nil {
+		// The replica was removed from the range.
+		return
+	}
+	var lm liveness.IsLiveMap
+	if r.store.cfg.NodeLiveness != nil {
+		lm = r.store.cfg.NodeLiveness.GetIsLiveMap()
+	}
+	r.breaker.trip(ctx, replicaUnavailableError(r.Desc(), replDesc, lm, dur))
+}
+
+// sendCircuitBreakerProbe replicates a write to the range's probe key,
+// bypassing the circuit breaker. It returns nil once requests to the replica
+// can succeed again.
+func (r *Replica) sendCircuitBreakerProbe(ctx context.Context) error {
+	r.mu.RLock()
+	removed := r.mu.destroyStatus.Removed()
+	r.mu.RUnlock()
+	if removed {
+		// Requests to the replica fail with a RangeNotFoundError.
+		return nil
+	}
+
+	var ba roachpb.BatchRequest
+	ba.RangeID = r.RangeID
+	ba.Add(&roachpb.PutRequest{
+		RequestHeader: roachpb.RequestHeader{Key: keys.RangeProbeKey(r.Desc().StartKey)},
+		Value:         roachpb.MakeValueFromBytes(nil),
+		Inline:        true,
+	})
+	_, pErr := r.store.Send(ctx, ba)
+	if pErr == nil {
+		return nil
+	}
+	if _, ok := pErr.GetDetail().(*roachpb.NotLeaseHolderError); ok {
+		// The range is served by another replica, which requests are redirected
+		// to.
+		return nil
+	}
+	return pErr.GoError()
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaCircuitBreaker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	st := cluster.MakeTestingClusterSettings()
+	cumTripped := metric.NewCounter(metaReplicaCircuitBreakerCumTripped)
+
+	var probe struct {
+		syncutil.Mutex
+		err error
+	}
+	setProbeErr := func(err error) {
+		probe.Lock()
+		defer probe.Unlock()
+		probe.err = err
+	}
+	setProbeErr(errors.New("probe failed"))
+	br := newReplicaCircuitBreaker(
+		log.AmbientContext{Tracer: st.Tracer}, stopper, st, cumTripped,
+		func(context.Context) error {
+			probe.Lock()
+			defer probe.Unlock()
+			return probe.err
+		},
+	)
+	br.probeRetryOpts = retry.Options{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	requireReset := func() {
+		testutils.SucceedsSoon(t, func() error {
+			return br.Err()
+		})
+		select {
+		case <-br.Signal():
+			t.Fatal("signal of reset breaker is closed")
+		default:
+		}
+	}
+
+	require.NoError(t, br.Err())
+	signal := br.Signal()
+	desc := roachpb.RangeDescriptor{
+		RangeID: 1,
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			{NodeID: 1, StoreID: 1, ReplicaID: 1},
+			{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		},
+	}
+	br.trip(ctx, replicaUnavailableError(&desc, desc.InternalReplicas[0], nil /* lm */, time.Minute))
+	<-signal
+	require.True(t, IsReplicaUnavailableError(br.Err()))
+	require.Regexp(t, `replica \(n1,s1\):1 of r1 unavailable: proposals have not been applied for 60.00s`, br.Err())
+	require.Equal(t, int64(1), cumTripped.Count())
+
+	// Tripping a tripped breaker keeps the original error.
+	br.trip(ctx, errors.New("boom"))
+	require.True(t, IsReplicaUnavailableError(br.Err()))
+	require.Equal(t, int64(1), cumTripped.Count())
+
+	// The breaker resets once the probe succeeds.
+	setProbeErr(nil)
+	requireReset()
+
+	// Disabling the breakers resets tripped ones even if the probe fails.
+	setProbeErr(errors.New("probe failed"))
+	br.trip(ctx, errors.New("boom"))
+	require.Error(t, br.Err())
+	require.Equal(t, int64(2), cumTripped.Count())
+	replicaCircuitBreakerSlowReplicationThreshold.Override(&st.SV, 0)
+	requireReset()
+}
+
+func TestIsCircuitBreakerProbe(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	probeKey := keys.RangeProbeKey(roachpb.RKey("a"))
+	for _, tc := range []struct {
+		name string
+		reqs []roachpb.Request
+		exp  bool
+	}{
+		{
+			name: "probe",
+			reqs: []roachpb.Request{&roachpb.PutRequest{
+				RequestHeader: roachpb.RequestHeader{Key: probeKey}, Inline: true,
+			}},
+			exp: true,
+		},
+		{
+			name: "not inline",
+			reqs: []roachpb.Request{&roachpb.PutRequest{
+				RequestHeader: roachpb.RequestHeader{Key: probeKey},
+			}},
+			exp: false,
+		},
+		{
+			name: "other key",
+			reqs: []roachpb.Request{&roachpb.PutRequest{
+				RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("a")}, Inline: true,
+			}},
+			exp: false,
+		},
+		{
+			name: "other request",
+			reqs: []roachpb.Request{&roachpb.GetRequest{
+				RequestHeader: roachpb.RequestHeader{Key: probeKey},
+			}},
+			exp: false,
+		},
+		{
+			name: "multiple requests",
+			reqs: []roachpb.Request{
+				&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: probeKey}, Inline: true},
+				&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: probeKey}, Inline: true},
+			},
+			exp: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ba roachpb.BatchRequest
+			ba.Add(tc.reqs...)
+			require.Equal(t, tc.exp, isCircuitBreakerProbe(&ba))
+		})
+	}
+}
@@ -140,6 +140,14 @@ func prepareSplitDescs(
 	// of why generations are useful.
 	rightDesc.Generation = leftDesc.Generation
 
+	// The right hand side inherits the non-blocking mode of the left hand side,
+	// including its floor, since it also inherits the writes that were placed
+	// in the future under that mode.
+	if leftDesc.GlobalReads != nil {
+		globalReads := *leftDesc.GlobalReads
+		rightDesc.GlobalReads = &globalReads
+	}
+
 	setStickyBit(rightDesc, expiration)
 	return leftDesc, rightDesc
 }
@@ -635,8 +635,8 @@ func (r *Replica) sha512(
 		// we will probably not have any interleaved intents so we could stop
 		// using MVCCKeyAndIntentsIterKind and consider all locks here.
 		for _, span := range rditer.MakeReplicatedKeyRangesExceptLockTable(&desc) {
-			spanMS, err := storage.ComputeStatsForRange(
-				iter, span.Start.Key, span.End.Key, 0 /* nowNanos */, visitor,
+			spanMS, err := storage.ComputeStatsWithRangeTombstones(
+				snap, iter, span.Start.Key, span.End.Key, 0 /* nowNanos */, visitor,
 			)
 			if err != nil {
 				return nil, err
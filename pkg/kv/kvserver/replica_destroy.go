@@ -161,6 +161,20 @@ func (r *Replica) destroyRaftMuLocked(ctx context.Context, nextReplicaID roachpb
 	if err := batch.Commit(true); err != nil {
 		return err
 	}
+	// If the store keeps its raft log in a separate engine, remove the raft
+	// state from it only now that the removal of the replica's data is durable.
+	// A crash before this batch is durable leaves the raft state behind, which
+	// is cleaned up when the store next starts.
+	if r.store.separateRaftEngine() {
+		raftBatch := r.store.RaftEngine().NewWriteOnlyBatch()
+		defer raftBatch.Close()
+		if err := clearRaftEngineData(r.RangeID, raftBatch); err != nil {
+			return err
+		}
+		if err := raftBatch.Commit(false /* sync */); err != nil {
+			return err
+		}
+	}
 	commitTime := timeutil.Now()
 
 	if err := r.postDestroyRaftMuLocked(ctx, ms); err != nil {
@@ -149,8 +149,8 @@ func (rec SpanSetReplicaEvalContext) GetMVCCStats() enginepb.MVCCStats {
 }
 
 // GetSplitQPS returns the Replica's queries/s rate for splitting purposes.
-func (rec SpanSetReplicaEvalContext) GetSplitQPS() float64 {
-	return rec.i.GetSplitQPS()
+func (rec SpanSetReplicaEvalContext) GetSplitQPS(ctx context.Context) float64 {
+	return rec.i.GetSplitQPS(ctx)
 }
 
 // CanCreateTxnRecord determines whether a transaction record can be created
@@ -114,6 +114,10 @@ func (r *Replica) canServeFollowerRead(
 // start time of the current lease because leasePostApply bumps the timestamp
 // cache forward to at least the new lease start time. Using this combination
 // allows the closed timestamp mechanism to be robust to lease transfers.
+// Ranges in non-blocking mode place all of their writes ahead of the known
+// closed timestamp by the range's lead (see roachpb.GlobalReadsState), so the
+// known closed timestamp is advanced by that lead for them. The maximum closed
+// timestamp of such ranges generally leads present time.
 // If the ok return value is false, the Replica is a member of a range which
 // uses an expiration-based lease. Expiration-based leases do not support the
 // closed timestamp subsystem. A zero-value timestamp will be returned if ok
@@ -122,6 +126,7 @@ func (r *Replica) maxClosed(ctx context.Context) (_ hlc.Timestamp, ok bool) {
 	r.mu.RLock()
 	lai := r.mu.state.LeaseAppliedIndex
 	lease := *r.mu.state.Lease
+	globalReads := r.mu.state.Desc.GetGlobalReads()
 	initialMaxClosed := r.mu.initialMaxClosed
 	r.mu.RUnlock()
 	if lease.Expiration != nil {
@@ -129,6 +134,12 @@ func (r *Replica) maxClosed(ctx context.Context) (_ hlc.Timestamp, ok bool) {
 	}
 	maxClosed := r.store.cfg.ClosedTimestamp.Provider.MaxClosed(
 		lease.Replica.NodeID, r.RangeID, ctpb.Epoch(lease.Epoch), ctpb.LAI(lai))
+	if !maxClosed.IsEmpty() && globalReads.Lead != 0 {
+		// The lead is taken from the descriptor at the same lease applied
+		// index, so it applies to all of the writes that this replica hasn't
+		// applied yet.
+		maxClosed = maxClosed.Add(globalReads.Lead.Nanoseconds(), 0)
+	}
 	maxClosed.Forward(lease.Start)
 	maxClosed.Forward(initialMaxClosed)
 	return maxClosed, true
@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// globalReadsFloorSlack is added to the floor chosen when the closed
+// timestamp lead of a range is lowered, to account for the time between the
+// choice of the floor and the evaluation of the commit trigger, which
+// validates it against the leaseholder's clock.
+const globalReadsFloorSlack = time.Second
+
+// globalReadsMinWriteTimestamp returns the minimum timestamp at which a write
+// to a range with the provided global reads state can be performed, given the
+// minimum timestamp required by the closed timestamp tracker. Writes to ranges
+// in non-blocking mode are placed ahead of present time by the range's lead,
+// and their timestamps are marked as synthetic so that the writers wait for
+// them to become current before acknowledging a commit.
+func globalReadsMinWriteTimestamp(gr roachpb.GlobalReadsState, minTS hlc.Timestamp) hlc.Timestamp {
+	if gr.Lead != 0 {
+		minTS = minTS.Add(gr.Lead.Nanoseconds(), 0).SetFlag(hlc.TimestampFlag_SYNTHETIC)
+	}
+	minTS.Forward(gr.Floor)
+	return minTS
+}
+
+// canUseObservedTimestamps returns whether observed timestamps can be used to
+// limit the uncertainty interval of the transaction on a range with the
+// provided global reads state. Values written to non-blocking ranges may have
+// timestamps above the clock reading of the leaseholder, which means that an
+// observed timestamp taken from the leaseholder's clock does not bound the
+// timestamps of the values that may have been written before the transaction
+// started. The same holds for a range that recently left non-blocking mode or
+// lowered its lead, for values written below its floor.
+func canUseObservedTimestamps(
+	gr roachpb.GlobalReadsState, txn *roachpb.Transaction, maxOffset time.Duration,
+) bool {
+	if gr.Lead != 0 {
+		return false
+	}
+	if txn != nil && !gr.Floor.IsEmpty() && txn.MinTimestamp.LessEq(gr.Floor.Add(maxOffset.Nanoseconds(), 0)) {
+		return false
+	}
+	return true
+}
+
+// maybeUpdateGlobalReads reconciles the global reads state of the range with
+// the provided zone config, if the replica holds the lease. The state is
+// changed through a transaction on the range descriptor whose commit trigger
+// installs the new state on all replicas.
+func (r *Replica) maybeUpdateGlobalReads(ctx context.Context, zone *zonepb.ZoneConfig) {
+	st := r.ClusterSettings()
+	if !st.Version.IsActive(ctx, clusterversion.NonBlockingTransactions) {
+		return
+	}
+	var lead time.Duration
+	if zone.GlobalReads != nil && *zone.GlobalReads {
+		lead = closedts.LeadForGlobalReads(&st.SV, r.Clock().MaxOffset())
+	}
+	desc := r.Desc()
+	old := desc.GetGlobalReads()
+	if old.Lead == lead {
+		return
+	}
+	if !r.OwnsValidLease(ctx, r.Clock().Now()) {
+		return
+	}
+
+	gr := roachpb.GlobalReadsState{Lead: lead, Floor: old.Floor}
+	if lead < old.Lead {
+		// Followers may have served reads at timestamps up to the old lead ahead
+		// of present time, so writes must not be placed below that anymore.
+		gr.Floor = r.Clock().Now().Add((old.Lead + globalReadsFloorSlack).Nanoseconds(), 0).
+			SetFlag(hlc.TimestampFlag_SYNTHETIC)
+	}
+	if err := r.store.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return globalReadsTxnUpdateAttempt(ctx, txn, desc, gr)
+	}); err != nil {
+		log.Warningf(ctx, "failed to update global reads state of %s: %v", desc, err)
+		return
+	}
+	log.Infof(ctx, "updated global reads state to lead=%s floor=%s", gr.Lead, gr.Floor)
+}
+
+// globalReadsTxnUpdateAttempt updates the global reads state of the provided
+// range descriptor through a transaction with a GlobalReadsTrigger.
+func globalReadsTxnUpdateAttempt(
+	ctx context.Context, txn *kv.Txn, desc *roachpb.RangeDescriptor, gr roachpb.GlobalReadsState,
+) error {
+	_, dbDescValue, err := conditionalGetDescValueFromDB(ctx, txn, desc.StartKey, checkDescsEqual(desc))
+	if err != nil {
+		return err
+	}
+	newDesc := *desc
+	newDesc.GlobalReads = nil
+	if gr != (roachpb.GlobalReadsState{}) {
+		newDesc.GlobalReads = &gr
+	}
+
+	b := txn.NewBatch()
+	descKey := keys.RangeDescriptorKey(desc.StartKey)
+	if err := updateRangeDescriptor(ctx, b, descKey, dbDescValue, &newDesc); err != nil {
+		return err
+	}
+	if err := updateRangeAddressing(b, &newDesc); err != nil {
+		return err
+	}
+	// End the transaction manually, instead of letting RunTransaction loop
+	// do it, in order to provide a global reads trigger.
+	b.AddRawRequest(&roachpb.EndTxnRequest{
+		Commit: true,
+		InternalCommitTrigger: &roachpb.InternalCommitTrigger{
+			GlobalReadsTrigger: &roachpb.GlobalReadsTrigger{
+				GlobalReads: gr,
+			},
+		},
+	})
+	return txn.Run(ctx, b)
+}
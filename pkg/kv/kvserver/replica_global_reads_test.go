@@ -0,0 +1,63 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalReadsMinWriteTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts := func(nanos int64) hlc.Timestamp { return hlc.Timestamp{WallTime: nanos} }
+	syn := func(nanos int64) hlc.Timestamp { return ts(nanos).SetFlag(hlc.TimestampFlag_SYNTHETIC) }
+	testCases := []struct {
+		gr    roachpb.GlobalReadsState
+		minTS hlc.Timestamp
+		exp   hlc.Timestamp
+	}{
+		{gr: roachpb.GlobalReadsState{}, minTS: ts(10), exp: ts(10)},
+		{gr: roachpb.GlobalReadsState{Lead: 5}, minTS: ts(10), exp: syn(15)},
+		{gr: roachpb.GlobalReadsState{Floor: syn(20)}, minTS: ts(10), exp: syn(20)},
+		{gr: roachpb.GlobalReadsState{Floor: syn(20)}, minTS: ts(30), exp: ts(30)},
+		{gr: roachpb.GlobalReadsState{Lead: 5, Floor: syn(20)}, minTS: ts(10), exp: syn(20)},
+		{gr: roachpb.GlobalReadsState{Lead: 15, Floor: syn(20)}, minTS: ts(10), exp: syn(25)},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.exp, globalReadsMinWriteTimestamp(tc.gr, tc.minTS), "%+v", tc)
+	}
+}
+
+func TestCanUseObservedTimestamps(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const maxOffset = 10 * time.Nanosecond
+	txn := func(nanos int64) *roachpb.Transaction {
+		return &roachpb.Transaction{TxnMeta: enginepb.TxnMeta{MinTimestamp: hlc.Timestamp{WallTime: nanos}}}
+	}
+	floor := hlc.Timestamp{WallTime: 100}.SetFlag(hlc.TimestampFlag_SYNTHETIC)
+	require.True(t, canUseObservedTimestamps(roachpb.GlobalReadsState{}, txn(50), maxOffset))
+	require.True(t, canUseObservedTimestamps(roachpb.GlobalReadsState{}, nil, maxOffset))
+	require.False(t, canUseObservedTimestamps(roachpb.GlobalReadsState{Lead: 5}, txn(50), maxOffset))
+	require.False(t, canUseObservedTimestamps(roachpb.GlobalReadsState{Floor: floor}, txn(50), maxOffset))
+	require.False(t, canUseObservedTimestamps(roachpb.GlobalReadsState{Floor: floor}, txn(110), maxOffset))
+	require.True(t, canUseObservedTimestamps(roachpb.GlobalReadsState{Floor: floor}, txn(111), maxOffset))
+}
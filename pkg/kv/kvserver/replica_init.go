@@ -90,8 +90,8 @@ func newUnloadedReplica(
 	r.mu.quiescent = true
 	r.mu.zone = store.cfg.DefaultZoneConfig
 	r.mu.replicaID = replicaID
-	split.Init(&r.loadBasedSplitter, rand.Intn, func() float64 {
-		return float64(SplitByLoadQPSThreshold.Get(&store.cfg.Settings.SV))
+	split.Init(&r.loadBasedSplitter, rand.Intn, func(ctx context.Context) float64 {
+		return r.SplitByLoadThreshold(ctx)
 	})
 	r.mu.proposals = map[kvserverbase.CmdIDKey]*ProposalData{}
 	r.mu.checksums = map[uuid.UUID]ReplicaChecksum{}
@@ -107,6 +107,7 @@ func newUnloadedReplica(
 	// Pass nil for the localityOracle because we intentionally don't track the
 	// origin locality of write load.
 	r.writeStats = newReplicaStats(store.Clock(), nil)
+	r.cpuStats = newReplicaStats(store.Clock(), nil)
 
 	// Init rangeStr with the range ID.
 	r.rangeStr.store(replicaID, &roachpb.RangeDescriptor{RangeID: desc.RangeID})
@@ -119,6 +120,10 @@ func newUnloadedReplica(
 
 	r.splitQueueThrottle = util.Every(splitQueueThrottleDuration)
 	r.mergeQueueThrottle = util.Every(mergeQueueThrottleDuration)
+	r.breaker = newReplicaCircuitBreaker(
+		r.AmbientContext, store.Stopper(), store.ClusterSettings(),
+		store.metrics.ReplicaCircuitBreakerCumTripped, r.sendCircuitBreakerProbe,
+	)
 	return r
 }
 
@@ -152,7 +157,7 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 	if r.mu.state, err = r.mu.stateLoader.Load(ctx, r.Engine(), desc); err != nil {
 		return err
 	}
-	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine())
+	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine(), r.store.RaftEngine())
 	if err != nil {
 		return err
 	}
@@ -191,14 +196,16 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 		}
 	}
 
-	ssBase := r.Engine().GetAuxiliaryDir()
+	// Sideloaded SSTables are part of the raft log, and live alongside it in
+	// the store's raft engine.
+	ssBase := r.store.raftEng.GetAuxiliaryDir()
 	if r.raftMu.sideloaded, err = newDiskSideloadStorage(
 		r.store.cfg.Settings,
 		desc.RangeID,
 		replicaID,
 		ssBase,
 		r.store.limiters.BulkIOWriteRate,
-		r.store.engine,
+		r.store.raftEng,
 	); err != nil {
 		return errors.Wrap(err, "while initializing sideloaded storage")
 	}
@@ -12,6 +12,7 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
@@ -46,6 +47,9 @@ type ReplicaMetrics struct {
 	LatchInfoLocal  kvserverpb.LatchManagerInfo
 	LatchInfoGlobal kvserverpb.LatchManagerInfo
 	RaftLogTooLarge bool
+	// CircuitBreakerError indicates whether the replica's circuit breaker is
+	// tripped.
+	CircuitBreakerError bool
 }
 
 // Metrics returns the current metrics for the replica.
@@ -68,7 +72,7 @@ func (r *Replica) Metrics(
 
 	latchInfoGlobal, latchInfoLocal := r.concMgr.LatchMetrics()
 
-	return calcReplicaMetrics(
+	m := calcReplicaMetrics(
 		ctx,
 		now,
 		&r.store.cfg.RaftConfig,
@@ -86,6 +90,8 @@ func (r *Replica) Metrics(
 		raftLogSize,
 		raftLogSizeTrusted,
 	)
+	m.CircuitBreakerError = r.breaker.Err() != nil
+	return m
 }
 
 func calcReplicaMetrics(
@@ -243,6 +249,23 @@ func (r *Replica) WritesPerSecond() float64 {
 	return wps
 }
 
+// CPUNanosPerSecond returns the range's average nanoseconds of CPU time spent
+// per second evaluating requests and applying raft commands. The CPU time is
+// that of the goroutine performing the work (see grunning), so time spent
+// blocked on latches, locks, disk IO or replication does not count. It is
+// always zero on platforms where grunning is not supported.
+func (r *Replica) CPUNanosPerSecond() float64 {
+	cpu, _ := r.cpuStats.avgQPS()
+	return cpu
+}
+
+// recordCPU records CPU time spent evaluating requests or applying raft
+// commands on the replica for use in CPU-based rebalancing and load-based
+// splitting.
+func (r *Replica) recordCPU(d time.Duration) {
+	r.cpuStats.recordCount(float64(d.Nanoseconds()), 0 /* nodeID */)
+}
+
 func (r *Replica) needsSplitBySizeRLocked() bool {
 	exceeded, _ := r.exceedsMultipleOfSplitSizeRLocked(1)
 	return exceeded
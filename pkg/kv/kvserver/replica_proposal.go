@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/grunning"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
@@ -524,6 +526,13 @@ func addSSTablePreApply(
 		}
 
 		path = ingestPath
+		if auxDir := eng.GetAuxiliaryDir(); !strings.HasPrefix(path, auxDir+string(filepath.Separator)) {
+			// The sideloaded storage lives in a separate raft engine (see
+			// Store.RaftEngine), possibly on a different device. Copy the SSTable
+			// next to the engine ingesting it so that the ingestion can link it.
+			path = filepath.Join(auxDir, "sstingest",
+				filepath.Base(sideloaded.Dir())+"."+filepath.Base(ingestPath))
+		}
 
 		log.Eventf(ctx, "copying SSTable for ingestion at index %d, term %d: %s", index, term, path)
 
@@ -692,7 +701,10 @@ func (r *Replica) evaluateProposal(
 	// important since evaluating a proposal is expensive.
 	// TODO(tschottdorf): absorb all returned values in `res` below this point
 	// in the call stack as well.
+	var cpu grunning.Timer
+	cpu.Start()
 	batch, ms, br, res, pErr := r.evaluateWriteBatch(ctx, idKey, ba, latchSpans)
+	r.recordBatchCPU(ctx, latchSpans, cpu.Stop())
 
 	// Note: reusing the proposer's batch when applying the command on the
 	// proposer was explored as an optimization but resulted in no performance
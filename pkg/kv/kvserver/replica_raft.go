@@ -656,7 +656,7 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// Use a more efficient write-only batch because we don't need to do any
 	// reads from the batch. Any reads are performed via the "distinct" batch
 	// which passes the reads through to the underlying DB.
-	batch := r.store.Engine().NewWriteOnlyBatch()
+	batch := r.store.RaftEngine().NewWriteOnlyBatch()
 	defer batch.Close()
 
 	// We know that all of the writes from here forward will be to distinct keys.
@@ -709,6 +709,12 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// uncommitted log entries, and even if they did include log entries that
 	// were not persisted to disk, it wouldn't be a problem because raft does not
 	// infer the that entries are persisted on the node that sends a snapshot.
+	//
+	// If the raft log lives in a separate engine, the committed entries applied
+	// below may become durable in the state machine engine before the updated
+	// HardState does in the raft engine. This requires no additional sync: the
+	// entries themselves are durable, and the HardState's Commit is raised to
+	// the applied index after a crash (see Store.reconcileRaftEngine).
 	commitStart := timeutil.Now()
 	if err := batch.Commit(rd.MustSync && !disableSyncRaftLog.Get(&r.store.cfg.Settings.SV)); err != nil {
 		const expl = "while committing batch"
@@ -1667,11 +1673,15 @@ func (r *Replica) acquireMergeLock(
 // the associated RaftLogDelta. It is usually expected to be true, but may not
 // be for the first truncation after on a replica that recently received a
 // snapshot.
+//
+// The truncated log entries are cleared through raftWriter, which is distinct
+// from readWriter if the store keeps its raft log in a separate engine.
 func handleTruncatedStateBelowRaft(
 	ctx context.Context,
 	oldTruncatedState, newTruncatedState *roachpb.RaftTruncatedState,
 	loader stateloader.StateLoader,
 	readWriter storage.ReadWriter,
+	raftWriter storage.Writer,
 ) (_apply bool, _ error) {
 	// If this is a log truncation, load the resulting unreplicated or legacy
 	// replicated truncated state (in that order). If the migration is happening
@@ -1703,7 +1713,7 @@ func handleTruncatedStateBelowRaft(
 		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
 		// avoid allocating when constructing Raft log keys (16 bytes).
 		unsafeKey := prefixBuf.RaftLogKey(idx)
-		if err := readWriter.ClearUnversioned(unsafeKey); err != nil {
+		if err := raftWriter.ClearUnversioned(unsafeKey); err != nil {
 			return false, errors.Wrapf(err, "unable to clear truncated Raft entries for %+v", newTruncatedState)
 		}
 	}
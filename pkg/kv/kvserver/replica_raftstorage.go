@@ -64,7 +64,7 @@ var _ raft.Storage = (*replicaRaftStorage)(nil)
 // InitialState requires that r.mu is held.
 func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState, error) {
 	ctx := r.AnnotateCtx(context.TODO())
-	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.Engine())
+	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.RaftEngine())
 	// For uninitialized ranges, membership is unknown at this point.
 	if raft.IsEmptyHardState(hs) || err != nil {
 		return raftpb.HardState{}, raftpb.ConfState{}, err
@@ -79,11 +79,16 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 func (r *replicaRaftStorage) Entries(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
 	readonly := r.store.Engine().NewReadOnly()
 	defer readonly.Close()
+	raftReadonly := readonly
+	if r.store.separateRaftEngine() {
+		raftReadonly = r.store.RaftEngine().NewReadOnly()
+		defer raftReadonly.Close()
+	}
 	ctx := r.AnnotateCtx(context.TODO())
 	if r.raftMu.sideloaded == nil {
 		return nil, errors.New("sideloaded storage is uninitialized")
 	}
-	return entries(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache,
+	return entries(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache,
 		r.raftMu.sideloaded, lo, hi, maxBytes)
 }
 
@@ -96,10 +101,14 @@ func (r *Replica) raftEntriesLocked(lo, hi, maxBytes uint64) ([]raftpb.Entry, er
 // `sideloaded` can be supplied as nil, in which case sideloaded entries will
 // not be inlined, the raft entry cache will not be populated with *any* of the
 // loaded entries, and maxBytes will not be applied to the payloads.
+//
+// The log is read from raftReader and the truncated state from reader; these
+// are one and the same unless the store keeps its raft log in a separate
+// engine.
 func entries(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	reader, raftReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	sideloaded SideloadStorage,
@@ -175,7 +184,7 @@ func entries(
 		return nil
 	}
 
-	if err := iterateEntries(ctx, reader, rangeID, expectedIndex, hi, scanFunc); err != nil {
+	if err := iterateEntries(ctx, raftReader, rangeID, expectedIndex, hi, scanFunc); err != nil {
 		return nil, err
 	}
 	// Cache the fetched entries, if we may.
@@ -201,7 +210,7 @@ func entries(
 		}
 
 		// Was the missing index after the last index?
-		lastIndex, err := rsl.LoadLastIndex(ctx, reader)
+		lastIndex, err := rsl.LoadLastIndex(ctx, reader, raftReader)
 		if err != nil {
 			return nil, err
 		}
@@ -262,8 +271,13 @@ func (r *replicaRaftStorage) Term(i uint64) (uint64, error) {
 	}
 	readonly := r.store.Engine().NewReadOnly()
 	defer readonly.Close()
+	raftReadonly := readonly
+	if r.store.separateRaftEngine() {
+		raftReadonly = r.store.RaftEngine().NewReadOnly()
+		defer raftReadonly.Close()
+	}
 	ctx := r.AnnotateCtx(context.TODO())
-	return term(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache, i)
+	return term(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache, i)
 }
 
 // raftTermLocked requires that r.mu is locked for reading.
@@ -274,14 +288,14 @@ func (r *Replica) raftTermRLocked(i uint64) (uint64, error) {
 func term(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	reader, raftReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	i uint64,
 ) (uint64, error) {
 	// entries() accepts a `nil` sideloaded storage and will skip inlining of
 	// sideloaded entries. We only need the term, so this is what we do.
-	ents, err := entries(ctx, rsl, reader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
+	ents, err := entries(ctx, rsl, reader, raftReader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
 	if errors.Is(err, raft.ErrCompacted) {
 		ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
 		if err != nil {
@@ -402,6 +416,12 @@ func (r *Replica) GetSnapshot(
 	// the corresponding Raft command not applied yet).
 	r.raftMu.Lock()
 	snap := r.store.engine.NewSnapshot()
+	raftSnap := snap
+	if r.store.separateRaftEngine() {
+		// Raft log writes happen under raftMu, so the two snapshots provide a
+		// consistent view of the replica.
+		raftSnap = r.store.raftEng.NewSnapshot()
+	}
 	r.mu.Lock()
 	appliedIndex := r.mu.state.RaftAppliedIndex
 	// Cleared when OutgoingSnapshot closes.
@@ -418,6 +438,9 @@ func (r *Replica) GetSnapshot(
 		if err != nil {
 			release()
 			snap.Close()
+			if raftSnap != snap {
+				raftSnap.Close()
+			}
 		}
 	}()
 
@@ -444,7 +467,7 @@ func (r *Replica) GetSnapshot(
 	// create a new state loader.
 	snapData, err := snapshot(
 		ctx, snapUUID, stateloader.Make(rangeID), snapType,
-		snap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
+		snap, raftSnap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
 	)
 	if err != nil {
 		log.Errorf(ctx, "error generating snapshot: %+v", err)
@@ -463,6 +486,9 @@ type OutgoingSnapshot struct {
 	RaftSnap raftpb.Snapshot
 	// The RocksDB snapshot that will be streamed from.
 	EngineSnap storage.Reader
+	// The snapshot of the engine holding the raft log. This is EngineSnap
+	// unless the store keeps its raft log in a separate engine.
+	RaftEngineSnap storage.Reader
 	// The complete range iterator for the snapshot to stream.
 	Iter *rditer.ReplicaEngineDataIterator
 	// The replica state within the snapshot.
@@ -490,6 +516,9 @@ func (s *OutgoingSnapshot) SafeFormat(w redact.SafePrinter, _ rune) {
 func (s *OutgoingSnapshot) Close() {
 	s.Iter.Close()
 	s.EngineSnap.Close()
+	if s.RaftEngineSnap != s.EngineSnap {
+		s.RaftEngineSnap.Close()
+	}
 	if s.onClose != nil {
 		s.onClose()
 	}
@@ -526,13 +555,15 @@ func (s *IncomingSnapshot) SafeFormat(w redact.SafePrinter, _ rune) {
 }
 
 // snapshot creates an OutgoingSnapshot containing a rocksdb snapshot for the
-// given range. Note that snapshot() is called without Replica.raftMu held.
+// given range. Note that snapshot() is called without Replica.raftMu held. The
+// raft log is read from raftSnap, which is snap itself unless the store keeps
+// its raft log in a separate engine.
 func snapshot(
 	ctx context.Context,
 	snapUUID uuid.UUID,
 	rsl stateloader.StateLoader,
 	snapType SnapshotRequest_Type,
-	snap storage.Reader,
+	snap, raftSnap storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	withSideloaded func(func(SideloadStorage) error) error,
@@ -558,7 +589,7 @@ func snapshot(
 		return OutgoingSnapshot{}, err
 	}
 
-	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
+	term, err := term(ctx, rsl, snap, raftSnap, rangeID, eCache, appliedIndex)
 	if err != nil {
 		return OutgoingSnapshot{}, errors.Errorf("failed to fetch term of %d: %s", appliedIndex, err)
 	}
@@ -576,6 +607,7 @@ func snapshot(
 		RaftEntryCache: eCache,
 		WithSideloaded: withSideloaded,
 		EngineSnap:     snap,
+		RaftEngineSnap: raftSnap,
 		Iter:           iter,
 		State:          state,
 		SnapUUID:       snapUUID,
@@ -856,9 +888,29 @@ func (r *Replica) applySnapshot(
 		return errors.Wrapf(err, "error clearing range of unreplicated SST writer")
 	}
 
+	// If the store keeps its raft log in a separate engine, the HardState and
+	// the log entries are written to that engine, along with the removal of the
+	// raft state of any subsumed replicas, but only once the SSTs have been
+	// ingested. See Store.RaftEngine.
+	raftWriter := storage.Writer(&unreplicatedSST)
+	var raftBatch storage.Batch
+	if r.store.separateRaftEngine() {
+		raftBatch = r.store.raftEng.NewWriteOnlyBatch()
+		defer raftBatch.Close()
+		if err := clearRaftEngineData(r.RangeID, raftBatch); err != nil {
+			return errors.Wrapf(err, "unable to clear raft engine data")
+		}
+		for _, sr := range subsumedRepls {
+			if err := clearRaftEngineData(sr.RangeID, raftBatch); err != nil {
+				return errors.Wrapf(err, "unable to clear raft engine data of subsumed replica")
+			}
+		}
+		raftWriter = raftBatch
+	}
+
 	// Update HardState.
-	if err := r.raftMu.stateLoader.SetHardState(ctx, &unreplicatedSST, hs); err != nil {
-		return errors.Wrapf(err, "unable to write HardState to unreplicated SST writer")
+	if err := r.raftMu.stateLoader.SetHardState(ctx, raftWriter, hs); err != nil {
+		return errors.Wrapf(err, "unable to write HardState")
 	}
 
 	// Update Raft entries.
@@ -878,7 +930,7 @@ func (r *Replica) applySnapshot(
 			return err
 		}
 		raftLogSize += sideloadedEntriesSize
-		_, lastTerm, raftLogSize, err = r.append(ctx, &unreplicatedSST, 0, invalidLastTerm, raftLogSize, logEntries)
+		_, lastTerm, raftLogSize, err = r.append(ctx, raftWriter, 0, invalidLastTerm, raftLogSize, logEntries)
 		if err != nil {
 			return err
 		}
@@ -959,6 +1011,14 @@ func (r *Replica) applySnapshot(
 	// has not yet been updated. Any errors past this point must therefore be
 	// treated as fatal.
 
+	if raftBatch != nil {
+		// A crash before this batch is durable leaves the raft engine behind
+		// the ingested state, which is reconciled when the store next starts.
+		if err := raftBatch.Commit(true /* sync */); err != nil {
+			log.Fatalf(ctx, "unable to commit raft engine batch while applying snapshot: %+v", err)
+		}
+	}
+
 	if err := r.clearSubsumedReplicaInMemoryData(ctx, subsumedRepls, mergedTombstoneReplicaID); err != nil {
 		log.Fatalf(ctx, "failed to clear in-memory data of subsumed replicas while applying snapshot: %+v", err)
 	}
@@ -899,6 +899,16 @@ func (r *Replica) leaseGoodToGo(ctx context.Context) (kvserverpb.LeaseStatus, bo
 //  referring to? It appears to have rotted.
 func (r *Replica) redirectOnOrAcquireLease(
 	ctx context.Context,
+) (kvserverpb.LeaseStatus, *roachpb.Error) {
+	return r.redirectOnOrAcquireLeaseForRequest(ctx, false /* bypassBreaker */)
+}
+
+// redirectOnOrAcquireLeaseForRequest is like redirectOnOrAcquireLease. A lease
+// acquisition that doesn't complete within the slow replication threshold
+// trips the replica's circuit breaker, and waiting for the lease acquisition
+// fails once the breaker is tripped, unless bypassBreaker is set.
+func (r *Replica) redirectOnOrAcquireLeaseForRequest(
+	ctx context.Context, bypassBreaker bool,
 ) (kvserverpb.LeaseStatus, *roachpb.Error) {
 	if status, ok := r.leaseGoodToGo(ctx); ok {
 		return status, nil
@@ -1044,6 +1054,15 @@ func (r *Replica) redirectOnOrAcquireLease(
 			slowTimer := timeutil.NewTimer()
 			defer slowTimer.Stop()
 			slowTimer.Reset(base.SlowRequestThreshold)
+			breakerTimer := timeutil.NewTimer()
+			defer breakerTimer.Stop()
+			var breakerSignal <-chan struct{}
+			if !bypassBreaker {
+				breakerSignal = r.breaker.Signal()
+				if threshold := r.breaker.slowReplicationThreshold(); threshold > 0 {
+					breakerTimer.Reset(threshold)
+				}
+			}
 			tBegin := timeutil.Now()
 			for {
 				select {
@@ -1095,6 +1114,19 @@ func (r *Replica) redirectOnOrAcquireLease(
 						r.store.metrics.SlowLeaseRequests.Dec(1)
 						log.Infof(ctx, "slow lease acquisition finished after %s with error %v after %d attempts", timeutil.Since(tBegin), pErr, attempt)
 					}()
+				case <-breakerTimer.C:
+					breakerTimer.Read = true
+					r.tripCircuitBreaker(ctx, timeutil.Since(tBegin))
+				case <-breakerSignal:
+					err := r.breaker.Err()
+					if err == nil {
+						// The breaker was reset in the meantime.
+						breakerSignal = r.breaker.Signal()
+						continue
+					}
+					llHandle.Cancel()
+					log.VErrEventf(ctx, 2, "lease acquisition failed: %s", err)
+					return roachpb.NewError(err)
 				case <-ctx.Done():
 					llHandle.Cancel()
 					log.VErrEventf(ctx, 2, "lease acquisition failed: %s", ctx.Err())
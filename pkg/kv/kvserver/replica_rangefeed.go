@@ -41,6 +41,14 @@ var RangefeedEnabled = settings.RegisterBoolSetting(
 	false,
 ).WithPublic()
 
+// RangefeedTBIEnabled controls whether catch-up scans use time-bound
+// iterators.
+var RangefeedTBIEnabled = settings.RegisterBoolSetting(
+	"kv.rangefeed.catchup_scan_iterator_optimization.enabled",
+	"if true, rangefeeds will use time-bound iterators for catchup-scans when possible",
+	true,
+)
+
 // lockedRangefeedStream is an implementation of rangefeed.Stream which provides
 // support for concurrent calls to Send. Note that the default implementation of
 // grpc.Stream is not safe for concurrent calls to Send.
@@ -121,6 +129,30 @@ func (i iteratorWithCloser) Close() {
 	i.close()
 }
 
+// newTimeBoundCatchupIterator returns an iterator for the catch-up scan of the
+// given rangefeed request which only visits the versions above the request's
+// timestamp. Time-bound iterators have had correctness issues in the past
+// (#28358, #34819) when used on their own, so the returned
+// MVCCIncrementalIterator only uses one to skip over keys that the main,
+// regular iterator would otherwise visit. Intents and inline values are
+// emitted, as the catch-up scan expects, rather than returned as errors.
+func newTimeBoundCatchupIterator(
+	reader storage.Reader, args *roachpb.RangeFeedRequest,
+) storage.SimpleMVCCIterator {
+	return storage.NewMVCCIncrementalIterator(reader, storage.MVCCIncrementalIterOptions{
+		IterOptions: storage.IterOptions{
+			UpperBound:       args.Span.EndKey,
+			MinTimestampHint: args.Timestamp.Next(),
+			MaxTimestampHint: hlc.MaxTimestamp,
+		},
+		// The catch-up scan emits versions strictly above args.Timestamp.
+		StartTime:    args.Timestamp,
+		EndTime:      hlc.MaxTimestamp,
+		IntentPolicy: storage.MVCCIncrementalIterIntentPolicyEmit,
+		InlinePolicy: storage.MVCCIncrementalIterInlinePolicyEmit,
+	})
+}
+
 // RangeFeed registers a rangefeed over the specified span. It sends updates to
 // the provided stream and returns with an optional error when the rangefeed is
 // complete. The provided ConcurrentRequestLimiter is used to limit the number
@@ -168,9 +200,14 @@ func (r *Replica) RangeFeed(
 	if !args.Timestamp.IsEmpty() {
 		usingCatchupIter = true
 		lim := &r.store.limiters.ConcurrentRangefeedIters
-		if err := lim.Begin(ctx); err != nil {
+		metrics := r.store.metrics.RangeFeedMetrics
+		metrics.RangeFeedCatchupScansWaiting.Inc(1)
+		err := lim.Begin(ctx)
+		metrics.RangeFeedCatchupScansWaiting.Dec(1)
+		if err != nil {
 			return roachpb.NewError(err)
 		}
+		metrics.RangeFeedCatchupScansInFlight.Inc(1)
 		// Finish the iterator limit if we exit before the iterator finishes.
 		// The release function will be hooked into the Close method on the
 		// iterator below. The sync.Once prevents any races between exiting early
@@ -181,7 +218,10 @@ func (r *Replica) RangeFeed(
 		// scan.
 		var iterSemReleaseOnce sync.Once
 		iterSemRelease = func() {
-			iterSemReleaseOnce.Do(lim.Finish)
+			iterSemReleaseOnce.Do(func() {
+				lim.Finish()
+				metrics.RangeFeedCatchupScansInFlight.Dec(1)
+			})
 		}
 		defer iterSemRelease()
 	}
@@ -199,22 +239,27 @@ func (r *Replica) RangeFeed(
 	// Register the stream with a catch-up iterator.
 	var catchUpIterFunc rangefeed.IteratorConstructor
 	if usingCatchupIter {
+		// Catch-up scans that don't need previous values only have to visit
+		// versions above args.Timestamp, so they can use the time-bound iterator
+		// optimization to skip over sstables without any such versions.
+		useTBI := !args.WithDiff && RangefeedTBIEnabled.Get(&r.store.cfg.Settings.SV)
 		catchUpIterFunc = func() storage.SimpleMVCCIterator {
-
+			if useTBI {
+				return iteratorWithCloser{
+					SimpleMVCCIterator: newTimeBoundCatchupIterator(r.Engine(), args),
+					close:              iterSemRelease,
+				}
+			}
 			innerIter := r.Engine().NewMVCCIterator(storage.MVCCKeyAndIntentsIterKind, storage.IterOptions{
 				UpperBound: args.Span.EndKey,
-				// RangeFeed originally intended to use the time-bound iterator
-				// performance optimization. However, they've had correctness issues in
-				// the past (#28358, #34819) and no-one has the time for the due-diligence
-				// necessary to be confidant in their correctness going forward. Not using
-				// them causes the total time spent in RangeFeed catchup on changefeed
-				// over tpcc-1000 to go from 40s -> 4853s, which is quite large but still
-				// workable. See #35122 for details.
-				// MinTimestampHint: args.Timestamp,
 			})
+			// Surface MVCC range tombstones as point deletions of the keys they
+			// cover, which is all the catch-up scan knows how to emit.
 			catchUpIter := iteratorWithCloser{
-				SimpleMVCCIterator: innerIter,
-				close:              iterSemRelease,
+				SimpleMVCCIterator: storage.NewPointSynthesizingIter(
+					r.Engine(), innerIter, args.Span.EndKey,
+				),
+				close: iterSemRelease,
 			}
 			return catchUpIter
 		}
@@ -341,6 +386,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 		PushTxnsAge:      r.store.TestingKnobs().RangeFeedPushTxnsAge,
 		EventChanCap:     defaultEventChanCap,
 		EventChanTimeout: 50 * time.Millisecond,
+		CatchupScanRate:  r.store.limiters.RangefeedCatchupScanRate,
 		Metrics:          r.store.metrics.RangeFeedMetrics,
 	}
 	p = rangefeed.NewProcessor(cfg)
@@ -460,7 +506,8 @@ func (r *Replica) populatePrevValsInLogicalOpLogRaftMuLocked(
 		case *enginepb.MVCCWriteIntentOp,
 			*enginepb.MVCCUpdateIntentOp,
 			*enginepb.MVCCAbortIntentOp,
-			*enginepb.MVCCAbortTxnOp:
+			*enginepb.MVCCAbortTxnOp,
+			*enginepb.MVCCDeleteRangeOp:
 			// Nothing to do.
 			continue
 		default:
@@ -537,6 +584,14 @@ func (r *Replica) handleLogicalOpLogRaftMuLocked(
 			*enginepb.MVCCAbortTxnOp:
 			// Nothing to do.
 			continue
+		case *enginepb.MVCCDeleteRangeOp:
+			// An MVCC range tombstone deletes an unknown number of keys, which
+			// can't be published as individual values without scanning the
+			// span. Instead, disconnect the rangefeed. Clients will reconnect
+			// from their last checkpoint, and the catch-up scan will surface the
+			// deletion of each key covered by the range tombstone.
+			r.disconnectRangefeedWithReason(roachpb.RangeFeedRetryError_REASON_LOGICAL_OPS_MISSING)
+			return
 		default:
 			panic(errors.AssertionFailedf("unknown logical op %T", t))
 		}
@@ -24,17 +24,21 @@ const (
 type replicaWithStats struct {
 	repl *Replica
 	qps  float64
+	// cpu is the nanoseconds of CPU time per second spent by the replica
+	// evaluating requests and applying raft commands.
+	cpu float64
 	// TODO(a-robinson): Include writes-per-second and logicalBytes of storage?
 }
 
 // replicaRankings maintains top-k orderings of the replicas in a store along
-// different dimensions of concern, such as QPS, keys written per second, and
-// disk used.
+// different dimensions of concern, such as QPS, CPU time, keys written per
+// second, and disk used.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator *rrAccumulator
+		byQPS       []replicaWithStats
+		byCPU       []replicaWithStats
 	}
 }
 
@@ -45,12 +49,13 @@ func newReplicaRankings() *replicaRankings {
 func (rr *replicaRankings) newAccumulator() *rrAccumulator {
 	res := &rrAccumulator{}
 	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	res.cpu.val = func(r replicaWithStats) float64 { return r.cpu }
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
@@ -59,12 +64,23 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 	defer rr.mu.Unlock()
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if rr.mu.accumulator.qps.Len() > 0 {
+		rr.mu.byQPS = consumeAccumulator(&rr.mu.accumulator.qps)
 	}
 	return rr.mu.byQPS
 }
 
+func (rr *replicaRankings) topCPU() []replicaWithStats {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	// If we have a new set of data, consume it. Otherwise, just return the most
+	// recently consumed data.
+	if rr.mu.accumulator.cpu.Len() > 0 {
+		rr.mu.byCPU = consumeAccumulator(&rr.mu.accumulator.cpu)
+	}
+	return rr.mu.byCPU
+}
+
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
 // The typical pattern should be to call replicaRankings.newAccumulator, add
 // all the replicas you care about to the accumulator using addReplica, then
@@ -75,20 +91,28 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // `update`d accumulator will win.
 type rrAccumulator struct {
 	qps rrPriorityQueue
+	cpu rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
+	a.qps.add(repl)
+	a.cpu.add(repl)
+}
+
+// add pushes the replica onto the heap if the heap isn't full or if the
+// replica is more deserving than the current tip of the heap.
+func (pq *rrPriorityQueue) add(repl replicaWithStats) {
 	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
 		return
 	}
 
 	// Otherwise, conditionally push if the new replica is more deserving than
 	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
 	}
 }
 
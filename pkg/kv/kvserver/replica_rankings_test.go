@@ -73,3 +73,36 @@ func TestReplicaRankings(t *testing.T) {
 		}
 	}
 }
+
+// TestReplicaRankingsCPU verifies that replicas are ranked independently by
+// CPU time and by QPS.
+func TestReplicaRankingsCPU(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rr := newReplicaRankings()
+	acc := rr.newAccumulator()
+	// The replicas serving the most queries are the cheapest to serve.
+	for i := 0; i < 4; i++ {
+		acc.addReplica(replicaWithStats{
+			repl: &Replica{RangeID: roachpb.RangeID(i)},
+			qps:  float64(i),
+			cpu:  float64(3 - i),
+		})
+	}
+	rr.update(acc)
+
+	var byQPS, byCPU []roachpb.RangeID
+	for _, r := range rr.topQPS() {
+		byQPS = append(byQPS, r.repl.RangeID)
+	}
+	for _, r := range rr.topCPU() {
+		byCPU = append(byCPU, r.repl.RangeID)
+	}
+	if expected := []roachpb.RangeID{3, 2, 1, 0}; !reflect.DeepEqual(byQPS, expected) {
+		t.Errorf("got %v ranked by QPS; want %v", byQPS, expected)
+	}
+	if expected := []roachpb.RangeID{0, 1, 2, 3}; !reflect.DeepEqual(byCPU, expected) {
+		t.Errorf("got %v ranked by CPU; want %v", byCPU, expected)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/observedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -68,6 +69,12 @@ func (r *Replica) sendWithRangeID(
 		return nil, roachpb.NewError(err)
 	}
 
+	// Fail fast if the replica is unable to replicate writes. The probe which
+	// determines whether that is still the case bypasses the breaker.
+	if err := r.breaker.Err(); err != nil && !isCircuitBreakerProbe(ba) {
+		return nil, roachpb.NewError(err)
+	}
+
 	if err := r.maybeBackpressureBatch(ctx, ba); err != nil {
 		return nil, roachpb.NewError(err)
 	}
@@ -299,14 +306,18 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		} else {
 			// If the request is a write or a consistent read, it requires the
 			// range lease or permission to serve via follower reads.
-			if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
+			if status, pErr = r.redirectOnOrAcquireLeaseForRequest(
+				ctx, isCircuitBreakerProbe(ba),
+			); pErr != nil {
 				if nErr := r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
 					return nil, nErr
 				}
 			}
 		}
 		// Limit the transaction's maximum timestamp using observed timestamps.
-		ba.Txn = observedts.LimitTxnMaxTimestamp(ctx, ba.Txn, status)
+		if canUseObservedTimestamps(r.Desc().GetGlobalReads(), ba.Txn, r.Clock().MaxOffset()) {
+			ba.Txn = observedts.LimitTxnMaxTimestamp(ctx, ba.Txn, status)
+		}
 
 		// Determine the maximal set of key spans that the batch will operate
 		// on. We only need to do this once and we make sure to do so after we
@@ -660,6 +671,20 @@ func (r *Replica) checkBatchRequest(ba *roachpb.BatchRequest, isReadOnly bool) e
 		return errors.Errorf("%v mode is only available to reads", ba.ReadConsistency)
 	}
 
+	if ba.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		if !isReadOnly {
+			return errors.Errorf("%v wait policy is only available to reads", ba.WaitPolicy)
+		}
+		if !consistent {
+			return errors.Errorf("%v wait policy is not available to %v reads", ba.WaitPolicy, ba.ReadConsistency)
+		}
+		for _, union := range ba.Requests {
+			if args := union.GetInner(); !roachpb.CanSkipLocked(args) {
+				return errors.Errorf("%v wait policy is not supported by %s requests", ba.WaitPolicy, args.Method())
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -34,6 +34,15 @@ var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
 	2500, // 2500 req/s
 ).WithPublic()
 
+// SplitByLoadCPUThreshold wraps "kv.range_split.load_cpu_threshold".
+var SplitByLoadCPUThreshold = settings.RegisterDurationSetting(
+	"kv.range_split.load_cpu_threshold",
+	"the CPU time per second over which, the range becomes a candidate for load based "+
+		"splitting when kv.allocator.load_based_rebalancing.objective is cpu",
+	250*time.Millisecond,
+	settings.NonNegativeDuration,
+).WithPublic()
+
 // SplitByLoadMergeDelay wraps "kv.range_split.by_load_merge_delay".
 var SplitByLoadMergeDelay = settings.RegisterDurationSetting(
 	"kv.range_split.by_load_merge_delay",
@@ -42,9 +51,16 @@ var SplitByLoadMergeDelay = settings.RegisterDurationSetting(
 	settings.NonNegativeDuration,
 )
 
-// SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
-func (r *Replica) SplitByLoadQPSThreshold() float64 {
-	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
+// SplitByLoadThreshold returns the load over which the replica becomes a
+// candidate for load based splitting. The threshold is a QPS request rate or
+// a rate of CPU nanoseconds per second, depending on the load based
+// rebalancing objective.
+func (r *Replica) SplitByLoadThreshold(ctx context.Context) float64 {
+	st := r.store.cfg.Settings
+	if loadBasedRebalancingObjective(ctx, st) == LBRebalancingCPU {
+		return float64(SplitByLoadCPUThreshold.Get(&st.SV))
+	}
+	return float64(SplitByLoadQPSThreshold.Get(&st.SV))
 }
 
 // SplitByLoadEnabled returns whether load based splitting is enabled.
@@ -55,15 +71,37 @@ func (r *Replica) SplitByLoadEnabled() bool {
 		!r.store.TestingKnobs().DisableLoadBasedSplitting
 }
 
-// recordBatchForLoadBasedSplitting records the batch's spans to be considered
-// for load based splitting.
+// recordBatchForLoadBasedSplitting records the batch's spans to be
+// considered for load based splitting. When splitting on CPU, the batch is
+// instead recorded by recordBatchCPU once its CPU cost is known.
 func (r *Replica) recordBatchForLoadBasedSplitting(
 	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet,
 ) {
-	if !r.SplitByLoadEnabled() {
+	if !r.SplitByLoadEnabled() ||
+		loadBasedRebalancingObjective(ctx, r.store.cfg.Settings) == LBRebalancingCPU {
+		return
+	}
+	r.recordLoadForLoadBasedSplitting(ctx, len(ba.Requests), spans)
+}
+
+// recordBatchCPU records the CPU time spent evaluating a batch that declared
+// the provided spans. The time counts towards the replica's CPU usage and,
+// when splitting on CPU, is weighed by the load based splitter.
+func (r *Replica) recordBatchCPU(
+	ctx context.Context, spans *spanset.SpanSet, cpu time.Duration,
+) {
+	r.recordCPU(cpu)
+	if !r.SplitByLoadEnabled() ||
+		loadBasedRebalancingObjective(ctx, r.store.cfg.Settings) != LBRebalancingCPU {
 		return
 	}
-	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+	r.recordLoadForLoadBasedSplitting(ctx, int(cpu.Nanoseconds()), spans)
+}
+
+func (r *Replica) recordLoadForLoadBasedSplitting(
+	ctx context.Context, n int, spans *spanset.SpanSet,
+) {
+	shouldInitSplit := r.loadBasedSplitter.Record(ctx, timeutil.Now(), n, func() roachpb.Span {
 		return spans.BoundarySpan(spanset.SpanGlobal)
 	})
 	if shouldInitSplit {
@@ -15,6 +15,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/tscache"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -78,6 +79,20 @@ func (r *Replica) updateTimestampCache(
 		}
 		header := args.Header()
 		start, end := header.Key, header.EndKey
+
+		if ba.WaitPolicy == lock.WaitPolicy_SkipLocked && roachpb.CanSkipLocked(args) {
+			// A request using the SkipLocked wait policy makes no claim about
+			// the keys that it skipped over, so it only needs to protect the
+			// keys that it returned from being rewritten beneath it.
+			if err := roachpb.ResponseKeyIterate(args, br.Responses[i].GetInner(), func(k roachpb.Key) {
+				addToTSCache(k, nil, ts, txnID)
+			}); err != nil {
+				log.Errorf(ctx, "error iterating over response keys while "+
+					"updating timestamp cache for ba=%v, br=%v: %v", ba, br, err)
+			}
+			continue
+		}
+
 		switch t := args.(type) {
 		case *roachpb.EndTxnRequest:
 			// EndTxn requests that finalize their transaction record a
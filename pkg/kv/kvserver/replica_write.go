@@ -86,6 +86,10 @@ func (r *Replica) executeWriteBatch(
 
 	minTS, untrack := r.store.cfg.ClosedTimestamp.Tracker.Track(ctx)
 	defer untrack(ctx, 0, 0, 0) // covers all error returns below
+	// Ranges in non-blocking mode place their writes ahead of the closed
+	// timestamp. The latches held by the batch ensure that the mode can't
+	// change until the batch has been proposed.
+	minTS = globalReadsMinWriteTimestamp(r.Desc().GetGlobalReads(), minTS)
 
 	// Examine the timestamp cache for preceding commands which require this
 	// command to move its timestamp forward. Or, in the case of a transactional
@@ -173,6 +177,19 @@ func (r *Replica) executeWriteBatch(
 	slowTimer := timeutil.NewTimer()
 	defer slowTimer.Stop()
 	slowTimer.Reset(base.SlowRequestThreshold)
+	// The circuit breaker trips if the command isn't applied within the slow
+	// replication threshold, and fails the command once it is tripped. The
+	// circuit breaker probe is exempt from both, as it is what resets the
+	// breaker.
+	breakerTimer := timeutil.NewTimer()
+	defer breakerTimer.Stop()
+	var breakerSignal <-chan struct{}
+	if !isCircuitBreakerProbe(ba) {
+		breakerSignal = r.breaker.Signal()
+		if threshold := r.breaker.slowReplicationThreshold(); threshold > 0 {
+			breakerTimer.Reset(threshold)
+		}
+	}
 	// NOTE: this defer was moved from a case in the select statement to here
 	// because escape analysis does a better job avoiding allocations to the
 	// heap when defers are unconditional. When this was in the slowTimer select
@@ -225,6 +242,21 @@ func (r *Replica) executeWriteBatch(
 			rangeUnavailableMessage(&s, r.Desc(), r.store.cfg.NodeLiveness.GetIsLiveMap(),
 				r.RaftStatus(), ba, timeutil.Since(startPropTime))
 			log.Errorf(ctx, "range unavailable: %v", s)
+		case <-breakerTimer.C:
+			breakerTimer.Read = true
+			r.tripCircuitBreaker(ctx, timeutil.Since(startPropTime))
+		case <-breakerSignal:
+			err := r.breaker.Err()
+			if err == nil {
+				// The breaker was reset in the meantime.
+				breakerSignal = r.breaker.Signal()
+				continue
+			}
+			// The command may still apply, so the result is ambiguous.
+			abandon()
+			log.VEventf(ctx, 2, "circuit breaker tripped after %0.1fs of attempting command %s",
+				timeutil.Since(startTime).Seconds(), ba)
+			return nil, nil, roachpb.NewError(roachpb.NewAmbiguousResultError(err.Error()))
 		case <-ctxDone:
 			// If our context was canceled, return an AmbiguousResultError,
 			// which indicates to the caller that the command may have executed.
@@ -599,7 +631,7 @@ func (r *Replica) evaluateWriteBatchWrapper(
 	latchSpans *spanset.SpanSet,
 ) (storage.Batch, *roachpb.BatchResponse, result.Result, *roachpb.Error) {
 	batch, opLogger := r.newBatchedEngine(latchSpans)
-	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, false /* readOnly */)
+	br, res, pErr := evaluateBatch(ctx, idKey, batch, rec, ms, ba, nil /* g */, false /* readOnly */)
 	if pErr == nil {
 		if opLogger != nil {
 			res.LogicalOpLog = &kvserverpb.LogicalOpLog{
@@ -11,6 +11,9 @@
 package spanset
 
 import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -241,7 +244,7 @@ func (i *EngineIterator) Close() {
 // SeekEngineKeyGE is part of the storage.EngineIterator interface.
 func (i *EngineIterator) SeekEngineKeyGE(key storage.EngineKey) (valid bool, err error) {
 	valid, err = i.i.SeekEngineKeyGE(key)
-	if !valid {
+	if !valid || isMVCCRangeTombstoneKey(key.Key) {
 		return valid, err
 	}
 	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
@@ -253,7 +256,7 @@ func (i *EngineIterator) SeekEngineKeyGE(key storage.EngineKey) (valid bool, err
 // SeekEngineKeyLT is part of the storage.EngineIterator interface.
 func (i *EngineIterator) SeekEngineKeyLT(key storage.EngineKey) (valid bool, err error) {
 	valid, err = i.i.SeekEngineKeyLT(key)
-	if !valid {
+	if !valid || isMVCCRangeTombstoneKey(key.Key) {
 		return valid, err
 	}
 	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{EndKey: key.Key}); err != nil {
@@ -285,6 +288,9 @@ func (i *EngineIterator) checkKeyAllowed() (valid bool, err error) {
 	if err != nil {
 		return false, err
 	}
+	if isMVCCRangeTombstoneKey(key.Key) {
+		return true, nil
+	}
 	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
 		// Invalid, but no error.
 		return false, nil // nolint:returnerrcheck
@@ -292,6 +298,14 @@ func (i *EngineIterator) checkKeyAllowed() (valid bool, err error) {
 	return true, nil
 }
 
+// isMVCCRangeTombstoneKey returns whether the key is an MVCC range tombstone
+// key. Range tombstones are read alongside the user keys they cover, under the
+// latches declared for those keys, so reads of them are not checked against
+// the span set.
+func isMVCCRangeTombstoneKey(key roachpb.Key) bool {
+	return bytes.HasPrefix(key, keys.LocalMVCCRangeTombstonePrefix)
+}
+
 // UnsafeEngineKey is part of the storage.EngineIterator interface.
 func (i *EngineIterator) UnsafeEngineKey() (storage.EngineKey, error) {
 	return i.i.UnsafeEngineKey()
@@ -406,7 +420,7 @@ func (s spanSetReader) NewMVCCIterator(
 }
 
 func (s spanSetReader) NewEngineIterator(opts storage.IterOptions) storage.EngineIterator {
-	if !s.spansOnly {
+	if !s.spansOnly && !isMVCCRangeTombstoneKey(opts.LowerBound) {
 		panic("cannot do timestamp checking for EngineIterator")
 	}
 	return &EngineIterator{
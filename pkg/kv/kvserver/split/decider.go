@@ -13,6 +13,7 @@
 package split
 
 import (
+	"context"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -37,8 +38,8 @@ const minSplitSuggestionInterval = time.Minute
 // split point from MaybeSplitKey (which may have disappeared either due to a drop
 // in qps or a change in the workload).
 type Decider struct {
-	intn         func(n int) int // supplied to Init
-	qpsThreshold func() float64  // supplied to Init
+	intn         func(n int) int               // supplied to Init
+	qpsThreshold func(context.Context) float64 // supplied to Init
 
 	mu struct {
 		syncutil.Mutex
@@ -55,7 +56,7 @@ type Decider struct {
 // embedding the Decider into a larger struct outside of the scope of this package
 // without incurring a pointer reference. This is relevant since many Deciders
 // may exist in the system at any given point in time.
-func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
+func Init(lbs *Decider, intn func(n int) int, qpsThreshold func(context.Context) float64) {
 	lbs.intn = intn
 	lbs.qpsThreshold = qpsThreshold
 }
@@ -68,14 +69,18 @@ func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
 // If the returned boolean is true, a split key is available (though it may
 // disappear as more keys are sampled) and should be initiated by the caller,
 // which can call MaybeSplitKey to retrieve the suggested key.
-func (d *Decider) Record(now time.Time, n int, span func() roachpb.Span) bool {
+func (d *Decider) Record(
+	ctx context.Context, now time.Time, n int, span func() roachpb.Span,
+) bool {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	return d.recordLocked(now, n, span)
+	return d.recordLocked(ctx, now, n, span)
 }
 
-func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) bool {
+func (d *Decider) recordLocked(
+	ctx context.Context, now time.Time, n int, span func() roachpb.Span,
+) bool {
 	d.mu.count += int64(n)
 
 	// First compute requests per second since the last check.
@@ -97,7 +102,7 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		// begin to Record requests so it can find a split point. If a
 		// splitFinder already exists, we check if a split point is ready
 		// to be used.
-		if d.mu.qps >= d.qpsThreshold() {
+		if d.mu.qps >= d.qpsThreshold(ctx) {
 			if d.mu.splitFinder == nil {
 				d.mu.splitFinder = NewFinder(now)
 			}
@@ -120,9 +125,9 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 }
 
 // LastQPS returns the most recent QPS measurement.
-func (d *Decider) LastQPS(now time.Time) float64 {
+func (d *Decider) LastQPS(ctx context.Context, now time.Time) float64 {
 	d.mu.Lock()
-	d.recordLocked(now, 0, nil)
+	d.recordLocked(ctx, now, 0, nil)
 	qps := d.mu.qps
 	d.mu.Unlock()
 
@@ -134,11 +139,11 @@ func (d *Decider) LastQPS(now time.Time) float64 {
 // or if it wasn't able to determine a suitable split key.
 //
 // It is legal to call MaybeSplitKey at any time.
-func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
+func (d *Decider) MaybeSplitKey(ctx context.Context, now time.Time) roachpb.Key {
 	var key roachpb.Key
 
 	d.mu.Lock()
-	d.recordLocked(now, 0, nil)
+	d.recordLocked(ctx, now, 0, nil)
 	if d.mu.splitFinder != nil && d.mu.splitFinder.Ready(now) {
 		// We've found a key to split at. This key might be in the middle of a
 		// SQL row. If we fail to rectify that, we'll cause SQL crashes:
@@ -11,6 +11,7 @@
 package split
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"testing"
@@ -27,10 +28,11 @@ import (
 func TestDecider(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
+	ctx := context.Background()
 	intn := rand.New(rand.NewSource(12)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 10.0 })
+	Init(&d, intn, func(context.Context) float64 { return 10.0 })
 
 	ms := func(i int) time.Time {
 		ts, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
@@ -44,11 +46,11 @@ func TestDecider(t *testing.T) {
 
 	assertQPS := func(i int, expQPS float64) {
 		t.Helper()
-		qps := d.LastQPS(ms(i))
+		qps := d.LastQPS(ctx, ms(i))
 		assert.Equal(t, expQPS, qps)
 	}
 
-	assert.Equal(t, false, d.Record(ms(100), 1, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(100), 1, nil))
 	assertQPS(100, 0)
 
 	// The first operation was interpreted as having happened after an eternity
@@ -57,17 +59,17 @@ func TestDecider(t *testing.T) {
 	assert.Equal(t, ms(100), d.mu.lastQPSRollover)
 	assert.EqualValues(t, 0, d.mu.count)
 
-	assert.Equal(t, false, d.Record(ms(400), 4, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(400), 4, nil))
 	assertQPS(100, 0)
 	assertQPS(700, 0)
 
-	assert.Equal(t, false, d.Record(ms(300), 3, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(300), 3, nil))
 	assertQPS(100, 0)
 
-	assert.Equal(t, false, d.Record(ms(900), 1, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(900), 1, nil))
 	assertQPS(0, 0)
 
-	assert.Equal(t, false, d.Record(ms(1099), 1, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(1099), 1, nil))
 	assertQPS(0, 0)
 
 	// Now 9 operations happened in the interval [100, 1099]. The next higher
@@ -75,7 +77,7 @@ func TestDecider(t *testing.T) {
 
 	// It won't engage because the duration between the rollovers is 1.1s, and
 	// we had 10 events over that interval.
-	assert.Equal(t, false, d.Record(ms(1200), 1, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(1200), 1, nil))
 	assertQPS(0, float64(10)/float64(1.1))
 	assert.Equal(t, ms(1200), d.mu.lastQPSRollover)
 
@@ -83,11 +85,11 @@ func TestDecider(t *testing.T) {
 
 	assert.Equal(t, nilFinder, d.mu.splitFinder)
 
-	assert.Equal(t, false, d.Record(ms(2199), 12, nil))
+	assert.Equal(t, false, d.Record(ctx, ms(2199), 12, nil))
 	assert.Equal(t, nilFinder, d.mu.splitFinder)
 
 	// 2200 is the next rollover point, and 12+1=13 qps should be computed.
-	assert.Equal(t, false, d.Record(ms(2200), 1, op("a")))
+	assert.Equal(t, false, d.Record(ctx, ms(2200), 1, op("a")))
 	assert.Equal(t, ms(2200), d.mu.lastQPSRollover)
 	assertQPS(0, float64(13))
 
@@ -98,7 +100,7 @@ func TestDecider(t *testing.T) {
 	// to split. We don't test the details of exactly when that happens because
 	// this is done in the finder tests.
 	tick := 2200
-	for o := op("a"); !d.Record(ms(tick), 11, o); tick += 1000 {
+	for o := op("a"); !d.Record(ctx, ms(tick), 11, o); tick += 1000 {
 		if tick/1000%2 == 0 {
 			o = op("z")
 		} else {
@@ -106,7 +108,7 @@ func TestDecider(t *testing.T) {
 		}
 	}
 
-	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ms(tick)))
+	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ctx, ms(tick)))
 
 	// We were told to split, but won't be told to split again for some time
 	// to avoid busy-looping on split attempts.
@@ -115,34 +117,34 @@ func TestDecider(t *testing.T) {
 		if i%2 != 0 {
 			o = op("a")
 		}
-		assert.False(t, d.Record(ms(tick), 11, o))
-		assert.True(t, d.LastQPS(ms(tick)) > 1.0)
+		assert.False(t, d.Record(ctx, ms(tick), 11, o))
+		assert.True(t, d.LastQPS(ctx, ms(tick)) > 1.0)
 		// Even though the split key remains.
-		assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ms(tick+999)))
+		assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ctx, ms(tick+999)))
 		tick += 1000
 	}
 	// But after minSplitSuggestionInterval of ticks, we get another one.
-	assert.True(t, d.Record(ms(tick), 11, op("a")))
-	assert.True(t, d.LastQPS(ms(tick)) > 1.0)
+	assert.True(t, d.Record(ctx, ms(tick), 11, op("a")))
+	assert.True(t, d.LastQPS(ctx, ms(tick)) > 1.0)
 
 	// Split key suggestion vanishes once qps drops.
 	tick += 1000
-	assert.False(t, d.Record(ms(tick), 9, op("a")))
-	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ms(tick)))
+	assert.False(t, d.Record(ctx, ms(tick), 9, op("a")))
+	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ctx, ms(tick)))
 	assert.Equal(t, nilFinder, d.mu.splitFinder)
 
 	// Hammer a key with writes above threshold. There shouldn't be a split
 	// since everyone is hitting the same key and load can't be balanced.
 	for i := 0; i < 1000; i++ {
-		assert.False(t, d.Record(ms(tick), 11, op("q")))
+		assert.False(t, d.Record(ctx, ms(tick), 11, op("q")))
 		tick += 1000
 	}
 	assert.True(t, d.mu.splitFinder.Ready(ms(tick)))
-	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ms(tick)))
+	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ctx, ms(tick)))
 
 	// But the finder keeps sampling to adapt to changing workload...
 	for i := 0; i < 1000; i++ {
-		assert.False(t, d.Record(ms(tick), 11, op("p")))
+		assert.False(t, d.Record(ctx, ms(tick), 11, op("p")))
 		tick += 1000
 	}
 
@@ -154,7 +156,7 @@ func TestDecider(t *testing.T) {
 	// Since the new workload is also not partitionable, nothing changes in
 	// the decision.
 	assert.True(t, d.mu.splitFinder.Ready(ms(tick)))
-	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ms(tick)))
+	assert.Equal(t, roachpb.Key(nil), d.MaybeSplitKey(ctx, ms(tick)))
 
 	// Get the decider engaged again so that we can test Reset().
 	for i := 0; i < 1000; i++ {
@@ -162,25 +164,27 @@ func TestDecider(t *testing.T) {
 		if i%2 != 0 {
 			o = op("a")
 		}
-		d.Record(ms(tick), 11, o)
+		d.Record(ctx, ms(tick), 11, o)
 		tick += 500
 	}
 
 	// The finder wants to split, until Reset is called, at which point it starts
 	// back up at zero.
 	assert.True(t, d.mu.splitFinder.Ready(ms(tick)))
-	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ms(tick)))
+	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ctx, ms(tick)))
 	d.Reset()
-	assert.Nil(t, d.MaybeSplitKey(ms(tick)))
+	assert.Nil(t, d.MaybeSplitKey(ctx, ms(tick)))
 	assert.Nil(t, d.mu.splitFinder)
 }
 
 func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func(context.Context) float64 { return 1.0 })
 
 	baseKey := keys.SystemSQLCodec.TablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -196,10 +200,10 @@ func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 	var now time.Time
 	for i := 0; i < 2*int(minSplitSuggestionInterval/time.Second); i++ {
 		now = now.Add(500 * time.Millisecond)
-		d.Record(now, 1, c0)
+		d.Record(ctx, now, 1, c0)
 		now = now.Add(500 * time.Millisecond)
-		d.Record(now, 1, c1)
-		k = d.MaybeSplitKey(now)
+		d.Record(ctx, now, 1, c1)
+		k = d.MaybeSplitKey(ctx, now)
 		if len(k) != 0 {
 			break
 		}
@@ -210,10 +214,12 @@ func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 
 func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func(context.Context) float64 { return 1.0 })
 
 	baseKey := keys.SystemSQLCodec.TablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -233,10 +239,10 @@ func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 	var now time.Time
 	for i := 0; i < 2*int(minSplitSuggestionInterval/time.Second); i++ {
 		now = now.Add(500 * time.Millisecond)
-		d.Record(now, 1, c0)
+		d.Record(ctx, now, 1, c0)
 		now = now.Add(500 * time.Millisecond)
-		d.Record(now, 1, c1)
-		k = d.MaybeSplitKey(now)
+		d.Record(ctx, now, 1, c1)
+		k = d.MaybeSplitKey(ctx, now)
 		if len(k) != 0 {
 			break
 		}
@@ -100,7 +100,7 @@ func WriteInitialState(
 	if err != nil {
 		return enginepb.MVCCStats{}, err
 	}
-	if err := Make(desc.RangeID).SynthesizeRaftState(ctx, readWriter); err != nil {
+	if err := Make(desc.RangeID).SynthesizeRaftState(ctx, readWriter, readWriter); err != nil {
 		return enginepb.MVCCStats{}, err
 	}
 	return newMS, nil
@@ -507,11 +507,15 @@ func (rsl StateLoader) SetGCThreshold(
 
 // The rest is not technically part of ReplicaState.
 
-// LoadLastIndex loads the last index.
-func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader storage.Reader) (uint64, error) {
+// LoadLastIndex loads the last index. The raft log is read from raftReader and
+// the truncated state from reader; these are one and the same unless the store
+// keeps its raft log in a separate engine.
+func (rsl StateLoader) LoadLastIndex(
+	ctx context.Context, reader, raftReader storage.Reader,
+) (uint64, error) {
 	prefix := rsl.RaftLogPrefix()
 	// NB: raft log has no intents.
-	iter := reader.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{LowerBound: prefix})
+	iter := raftReader.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{LowerBound: prefix})
 	defer iter.Close()
 
 	var lastIndex uint64
@@ -620,11 +624,13 @@ func (rsl StateLoader) SetHardState(
 // SynthesizeRaftState creates a Raft state which synthesizes both a HardState
 // and a lastIndex from pre-seeded data in the engine (typically created via
 // writeInitialReplicaState and, on a split, perhaps the activity of an
-// uninitialized Raft group)
+// uninitialized Raft group). The HardState is read from and written to
+// raftReadWriter, which is distinct from readWriter only if the store keeps
+// its raft log in a separate engine.
 func (rsl StateLoader) SynthesizeRaftState(
-	ctx context.Context, readWriter storage.ReadWriter,
+	ctx context.Context, readWriter, raftReadWriter storage.ReadWriter,
 ) error {
-	hs, err := rsl.LoadHardState(ctx, readWriter)
+	hs, err := rsl.LoadHardState(ctx, raftReadWriter)
 	if err != nil {
 		return err
 	}
@@ -636,7 +642,7 @@ func (rsl StateLoader) SynthesizeRaftState(
 	if err != nil {
 		return err
 	}
-	return rsl.SynthesizeHardState(ctx, readWriter, hs, truncState, raftAppliedIndex)
+	return rsl.SynthesizeHardState(ctx, raftReadWriter, hs, truncState, raftAppliedIndex)
 }
 
 // SynthesizeHardState synthesizes an on-disk HardState from the given input,
@@ -144,6 +144,28 @@ var concurrentRangefeedItersLimit = settings.RegisterIntSetting(
 	settings.PositiveInt,
 )
 
+// rangefeedCatchupScanRate limits the rate at which rangefeed catch-up scans
+// read from the engine.
+var rangefeedCatchupScanRate = settings.RegisterByteSizeSetting(
+	"kv.rangefeed.catchup_scan_max_rate",
+	"the rate limit (bytes/sec) for the data read by rangefeed catchup scans on a store; 0 disables the limit",
+	0,
+	settings.NonNegativeInt,
+)
+
+// rangefeedCatchupScanBurst is the burst of the rangefeed catch-up scan rate
+// limiter.
+const rangefeedCatchupScanBurst = 512 << 10 // 512 KB
+
+// rangefeedCatchupScanRateLimit returns the limit of the rangefeed catch-up scan
+// rate limiter.
+func rangefeedCatchupScanRateLimit(sv *settings.Values) rate.Limit {
+	if limit := rangefeedCatchupScanRate.Get(sv); limit > 0 {
+		return rate.Limit(limit)
+	}
+	return rate.Inf
+}
+
 // Minimum time interval between system config updates which will lead to
 // enqueuing replicas.
 var queueAdditionOnSystemConfigUpdateRate = settings.RegisterFloatSetting(
@@ -417,6 +439,7 @@ type Store struct {
 	cfg                StoreConfig
 	db                 *kv.DB
 	engine             storage.Engine // The underlying key-value store
+	raftEng            storage.Engine // The engine holding the raft log; see RaftEngine
 	tsCache            tscache.Cache  // Most recent timestamps for keys / key ranges
 	allocator          Allocator      // Makes allocation decisions
 	replRankings       *replicaRankings
@@ -819,6 +842,7 @@ func NewStore(
 		cfg:      cfg,
 		db:       cfg.DB, // TODO(tschottdorf): remove redundancy.
 		engine:   eng,
+		raftEng:  storage.RaftEngine(eng),
 		nodeDesc: nodeDesc,
 		metrics:  newStoreMetrics(cfg.HistogramWindowInterval),
 	}
@@ -915,6 +939,11 @@ func NewStore(
 		s.limiters.ConcurrentRangefeedIters.SetLimit(
 			int(concurrentRangefeedItersLimit.Get(&cfg.Settings.SV)))
 	})
+	s.limiters.RangefeedCatchupScanRate = rate.NewLimiter(
+		rangefeedCatchupScanRateLimit(&cfg.Settings.SV), rangefeedCatchupScanBurst)
+	rangefeedCatchupScanRate.SetOnChange(&cfg.Settings.SV, func() {
+		s.limiters.RangefeedCatchupScanRate.SetLimit(rangefeedCatchupScanRateLimit(&cfg.Settings.SV))
+	})
 
 	s.tenantRateLimiters = tenantrate.NewLimiterFactory(cfg.Settings, &cfg.TestingKnobs.TenantRateKnobs)
 	s.metrics.registry.AddMetricStruct(s.tenantRateLimiters.Metrics())
@@ -1397,6 +1426,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	ctx = s.AnnotateCtx(ctx)
 	log.Event(ctx, "read store identity")
 
+	// Move the raft log into the separate raft engine, if one was configured
+	// and the store hasn't done so yet.
+	if err := s.maybeMigrateToSeparateRaftEngine(ctx); err != nil {
+		return err
+	}
+
 	// Add the store ID to the scanner's AmbientContext before starting it, since
 	// the AmbientContext provided during construction did not include it.
 	// Note that this is just a hacky way of getting around that without
@@ -1489,6 +1524,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 					log.Safe(s.StoreID()))
 			}
 
+			// Bring the replica's raft state in the separate raft engine, if
+			// any, in line with its state machine.
+			if err := s.reconcileRaftEngine(ctx, &desc); err != nil {
+				return err
+			}
+
 			rep, err := newReplica(ctx, &desc, s, replicaDesc.ReplicaID)
 			if err != nil {
 				return err
@@ -1535,6 +1576,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	if err != nil {
 		return err
 	}
+	if err := s.removeRaftEngineGarbage(ctx); err != nil {
+		return err
+	}
 
 	// Start Raft processing goroutines.
 	s.cfg.Transport.Listen(s.StoreID(), s)
@@ -1591,6 +1635,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// Connect rangefeeds to closed timestamp updates.
 	s.startClosedTimestampRangefeedSubscriber(ctx)
 
+	// Rewrite sstables encrypted with retired data keys.
+	s.startReencryption(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
@@ -1878,6 +1925,16 @@ func (s *Store) systemGossipUpdate(sysCfg *config.SystemConfig) {
 			zone = s.cfg.DefaultZoneConfig
 		}
 		repl.SetZoneConfig(zone)
+		if (zone.GlobalReads != nil && *zone.GlobalReads) != (repl.Desc().GetGlobalReads().Lead != 0) {
+			// The range needs to enter or leave non-blocking mode. This is done
+			// by the leaseholder, through a transaction on the range descriptor.
+			if err := s.stopper.RunAsyncTask(ctx, "storage.Store: update global reads",
+				func(ctx context.Context) {
+					repl.maybeUpdateGlobalReads(ctx, zone)
+				}); err != nil {
+				log.Warningf(ctx, "unable to update global reads: %+v", err)
+			}
+		}
 		if shouldQueue {
 			s.splitQueue.Async(ctx, "gossip update", true /* wait */, func(ctx context.Context, h queueHelper) {
 				h.MaybeAdd(ctx, repl, now)
@@ -2294,6 +2351,11 @@ func (s *Store) Clock() *hlc.Clock { return s.cfg.Clock }
 // Engine accessor.
 func (s *Store) Engine() storage.Engine { return s.engine }
 
+// RaftEngine returns the engine holding the store's raft log entries and
+// HardStates. This is the same as Engine unless the store was configured to
+// keep its raft log in a separate engine.
+func (s *Store) RaftEngine() storage.Engine { return s.raftEng }
+
 // DB accessor.
 func (s *Store) DB() *kv.DB { return s.cfg.DB }
 
@@ -2360,6 +2422,7 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalCPUPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
@@ -2386,9 +2449,15 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var cpu float64
+		if avgCPU, dur := r.cpuStats.avgQPS(); dur >= MinStatsDuration {
+			cpu = avgCPU
+			totalCPUPerSecond += avgCPU
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
 			repl: r,
 			qps:  qps,
+			cpu:  cpu,
 		})
 		return true
 	})
@@ -2397,6 +2466,7 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.CPUPerSecond = totalCPUPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2508,6 +2578,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		underreplicatedRangeCount int64
 		overreplicatedRangeCount  int64
 		behindCount               int64
+
+		circuitBreakerTrippedCount int64
 	)
 
 	timestamp := s.cfg.Clock.Now()
@@ -2552,6 +2624,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 			}
 		}
 		behindCount += metrics.BehindCount
+		if metrics.CircuitBreakerError {
+			circuitBreakerTrippedCount++
+		}
 		if qps, dur := rep.leaseholderStats.avgQPS(); dur >= MinStatsDuration {
 			averageQueriesPerSecond += qps
 		}
@@ -2580,6 +2655,7 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.UnderReplicatedRangeCount.Update(underreplicatedRangeCount)
 	s.metrics.OverReplicatedRangeCount.Update(overreplicatedRangeCount)
 	s.metrics.RaftLogFollowerBehindCount.Update(behindCount)
+	s.metrics.ReplicaCircuitBreakerCurTripped.Update(circuitBreakerTrippedCount)
 
 	if !minMaxClosedTS.IsEmpty() {
 		nanos := timeutil.Since(minMaxClosedTS.GoTime()).Nanoseconds()
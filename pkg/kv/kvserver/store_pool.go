@@ -581,6 +581,10 @@ type StoreList struct {
 	// are eligible to be rebalance targets.
 	candidateQueriesPerSecond stat
 
+	// candidateCPUPerSecond tracks CPU-time-per-second stats for stores that
+	// are eligible to be rebalance targets.
+	candidateCPUPerSecond stat
+
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
@@ -597,6 +601,7 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLeases.update(float64(desc.Capacity.LeaseCount))
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
+		sl.candidateCPUPerSecond.update(desc.Capacity.CPUPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
 	}
 	return sl
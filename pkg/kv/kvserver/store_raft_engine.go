@@ -0,0 +1,416 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+// A store may keep its raft log entries and HardStates in a separate raft
+// engine (see storage.WithRaftEngine), possibly on a different device than
+// the rest of its data. Since the two engines can't be written atomically,
+// the following ordering is maintained:
+//
+// - log entries and HardStates produced by raft are written (and synced) to
+//   the raft engine before the corresponding entries are applied to the state
+//   machine, just like with a single engine. The exception is the HardState's
+//   Commit, which raft doesn't require to be synced and which may thus fall
+//   behind the applied index.
+// - writes to the raft engine that follow from state machine writes (log
+//   truncations, HardStates of replicas created by splits or initialized by
+//   snapshots, and the removal of the raft state of destroyed replicas) are
+//   performed only once the state machine writes are durable.
+//
+// As a result, the raft engine may lag behind the state machine after a
+// crash, but it never runs ahead of it. The lag is reconciled when the store
+// starts, see reconcileRaftEngine and removeRaftEngineGarbage.
+
+// raftEngineMigrationBatchSize bounds the size of the batches used when moving
+// the raft log of an existing store into a separate raft engine.
+const raftEngineMigrationBatchSize = 32 << 20 // 32 MiB
+
+// separateRaftEngine returns whether the store keeps its raft log in a
+// separate raft engine.
+func (s *Store) separateRaftEngine() bool {
+	return s.raftEng != s.engine
+}
+
+// clearRaftEngineData stages the removal of the raft log and HardState of the
+// given range in raftWriter, which must write to a separate raft engine. The
+// raft engine holds no other range-ID local keys, so the entire unreplicated
+// range-ID local span is cleared.
+func clearRaftEngineData(rangeID roachpb.RangeID, raftWriter storage.Writer) error {
+	prefix := keys.MakeRangeIDUnreplicatedPrefix(rangeID)
+	return raftWriter.ClearRawRange(prefix, prefix.PrefixEnd())
+}
+
+// isRaftEngineKey returns whether the given key belongs in a separate raft
+// engine, i.e. whether it is a raft log entry or a HardState.
+func isRaftEngineKey(key roachpb.Key) bool {
+	if !bytes.HasPrefix(key, keys.LocalRangeIDPrefix) {
+		return false
+	}
+	_, _, suffix, _, err := keys.DecodeRangeIDKey(key)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(suffix, keys.LocalRaftLogSuffix) ||
+		bytes.Equal(suffix, keys.LocalRaftHardStateSuffix)
+}
+
+// readRaftEngineMarker reads the marker written to both engines of a store once
+// its raft log has been moved to a separate raft engine. The marker holds the
+// store's ident.
+func readRaftEngineMarker(
+	ctx context.Context, reader storage.Reader,
+) (_ roachpb.StoreIdent, found bool, _ error) {
+	var ident roachpb.StoreIdent
+	found, err := storage.MVCCGetProto(
+		ctx, reader, keys.StoreRaftEngineKey(), hlc.Timestamp{}, &ident, storage.MVCCGetOptions{})
+	return ident, found, err
+}
+
+// writeRaftEngineMarker writes the marker read by readRaftEngineMarker.
+func (s *Store) writeRaftEngineMarker(ctx context.Context, rw storage.ReadWriter) error {
+	return storage.MVCCPutProto(
+		ctx, rw, nil /* ms */, keys.StoreRaftEngineKey(), hlc.Timestamp{}, nil /* txn */, s.Ident)
+}
+
+// sideloadedDir returns the directory below which the given engine holds the
+// sideloaded SSTables of a store's replicas. See sideloadedPath.
+func sideloadedDir(eng storage.Engine) string {
+	return filepath.Join(eng.GetAuxiliaryDir(), "sideloading")
+}
+
+// copyDirTree copies the files below dir in src to the same paths below
+// dstDir in dst, overwriting any existing files, and syncs them. It returns
+// the number of files copied. A missing dir is treated as empty.
+func copyDirTree(src storage.Engine, dir string, dst storage.Engine, dstDir string) (int, error) {
+	names, err := src.List(dir)
+	if err != nil {
+		if oserror.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if err := dst.MkdirAll(dstDir); err != nil {
+		return 0, err
+	}
+	var copied int
+	for _, name := range names {
+		path, dstPath := filepath.Join(dir, name), filepath.Join(dstDir, name)
+		info, err := src.Stat(path)
+		if err != nil {
+			return copied, err
+		}
+		if info.IsDir() {
+			n, err := copyDirTree(src, path, dst, dstPath)
+			copied += n
+			if err != nil {
+				return copied, err
+			}
+			continue
+		}
+		data, err := src.ReadFile(path)
+		if err != nil {
+			return copied, err
+		}
+		f, err := dst.Create(dstPath)
+		if err != nil {
+			return copied, err
+		}
+		_, err = f.Write(data)
+		if err == nil {
+			err = f.Sync()
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	d, err := dst.OpenDir(dstDir)
+	if err != nil {
+		return copied, err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return copied, err
+}
+
+// maybeMigrateToSeparateRaftEngine moves the raft log entries, HardStates and
+// sideloaded SSTables of the store into its separate raft engine, if it has
+// one and hasn't done so already. The data is first copied to the raft engine,
+// which is then marked as holding the raft log, and only then removed from the
+// state machine engine. The migration is idempotent and resumes where it left
+// off if interrupted.
+func (s *Store) maybeMigrateToSeparateRaftEngine(ctx context.Context) error {
+	_, stateMarked, err := readRaftEngineMarker(ctx, s.engine)
+	if err != nil {
+		return err
+	}
+	if !s.separateRaftEngine() {
+		if stateMarked {
+			return errors.WithHint(
+				errors.Newf("store %s keeps its raft log in a separate engine, but none was configured",
+					s.StoreID()),
+				"Specify the raft-log-path the store was previously started with.")
+		}
+		return nil
+	}
+
+	raftIdent, raftMarked, err := readRaftEngineMarker(ctx, s.raftEng)
+	if err != nil {
+		return err
+	}
+	if raftMarked && raftIdent != *s.Ident {
+		return errors.Errorf("raft log engine of store %s belongs to store %s", s.Ident, raftIdent)
+	}
+	if stateMarked && !raftMarked {
+		return errors.WithHint(
+			errors.Newf("raft log engine of store %s does not hold the store's raft log", s.StoreID()),
+			"Specify the raft-log-path the store was previously started with.")
+	}
+	if stateMarked {
+		return nil
+	}
+
+	start := keys.LocalRangeIDPrefix.AsRawKey()
+	end := keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey()
+
+	if !raftMarked {
+		log.Infof(ctx, "moving raft log into separate raft engine")
+		var copied int
+		batch := s.raftEng.NewWriteOnlyBatch()
+		defer func() { batch.Close() }()
+		if err := s.engine.MVCCIterate(start, end, storage.MVCCKeyIterKind,
+			func(kv storage.MVCCKeyValue) error {
+				if !isRaftEngineKey(kv.Key.Key) {
+					return nil
+				}
+				if err := batch.PutUnversioned(kv.Key.Key, kv.Value); err != nil {
+					return err
+				}
+				copied++
+				if batch.Len() < raftEngineMigrationBatchSize {
+					return nil
+				}
+				if err := batch.Commit(false /* sync */); err != nil {
+					return err
+				}
+				batch.Close()
+				batch = s.raftEng.NewWriteOnlyBatch()
+				return nil
+			}); err != nil {
+			return errors.Wrap(err, "copying raft log into raft engine")
+		}
+		// The sideloaded SSTables referenced by the raft log are copied as well.
+		// The engines may be on different devices, so they can't be linked.
+		files, err := copyDirTree(s.engine, sideloadedDir(s.engine), s.raftEng, sideloadedDir(s.raftEng))
+		if err != nil {
+			return errors.Wrap(err, "copying sideloaded SSTables into raft engine")
+		}
+		// The marker is written in the final batch, which is synced along with
+		// all of the copied data that precedes it.
+		if err := s.writeRaftEngineMarker(ctx, batch); err != nil {
+			return err
+		}
+		if err := batch.Commit(true /* sync */); err != nil {
+			return errors.Wrap(err, "copying raft log into raft engine")
+		}
+		log.Infof(ctx, "copied %d raft log keys and %d sideloaded SSTables into separate raft engine",
+			copied, files)
+	}
+
+	// The raft engine now holds the raft log. Remove it from the state machine
+	// engine and mark the latter as migrated.
+	var removed int
+	batch := s.engine.NewWriteOnlyBatch()
+	defer func() { batch.Close() }()
+	if err := s.engine.MVCCIterate(start, end, storage.MVCCKeyIterKind,
+		func(kv storage.MVCCKeyValue) error {
+			if !isRaftEngineKey(kv.Key.Key) {
+				return nil
+			}
+			if err := batch.ClearUnversioned(kv.Key.Key); err != nil {
+				return err
+			}
+			removed++
+			if batch.Len() < raftEngineMigrationBatchSize {
+				return nil
+			}
+			if err := batch.Commit(false /* sync */); err != nil {
+				return err
+			}
+			batch.Close()
+			batch = s.engine.NewWriteOnlyBatch()
+			return nil
+		}); err != nil {
+		return errors.Wrap(err, "removing raft log from state machine engine")
+	}
+	if err := s.engine.RemoveAll(sideloadedDir(s.engine)); err != nil {
+		return errors.Wrap(err, "removing sideloaded SSTables from state machine engine")
+	}
+	if err := s.writeRaftEngineMarker(ctx, batch); err != nil {
+		return err
+	}
+	if err := batch.Commit(true /* sync */); err != nil {
+		return errors.Wrap(err, "removing raft log from state machine engine")
+	}
+	log.Infof(ctx, "removed %d raft log keys from state machine engine", removed)
+	return nil
+}
+
+// reconcileRaftEngine brings the raft state of the given initialized replica
+// in the store's separate raft engine, if any, in line with the replica's
+// state machine after a crash:
+//
+// - if the HardState's Commit is below the applied index and so is the
+//   truncated index, the replica was initialized by a snapshot or a split
+//   whose raft engine writes were lost. Any log entries predate the snapshot
+//   and are removed, and a HardState consistent with the state machine is
+//   synthesized.
+// - if the HardState's Commit is below the applied index otherwise, the
+//   update of the Commit wasn't synced before the entries were applied. The
+//   entries were synced when they were appended, so the Commit is raised to
+//   the applied index. This saves syncing the raft engine before applying
+//   committed entries.
+// - any log entries at or below the truncated index were left behind by a
+//   log truncation and are removed.
+func (s *Store) reconcileRaftEngine(ctx context.Context, desc *roachpb.RangeDescriptor) error {
+	if !s.separateRaftEngine() {
+		return nil
+	}
+	rsl := stateloader.Make(desc.RangeID)
+	truncState, _, err := rsl.LoadRaftTruncatedState(ctx, s.engine)
+	if err != nil {
+		return err
+	}
+	appliedIndex, _, err := rsl.LoadAppliedIndex(ctx, s.engine)
+	if err != nil {
+		return err
+	}
+	hs, err := rsl.LoadHardState(ctx, s.raftEng)
+	if err != nil {
+		return err
+	}
+
+	batch := s.raftEng.NewBatch()
+	defer batch.Close()
+	if hs.Commit < appliedIndex && truncState.Index >= appliedIndex {
+		log.Infof(ctx, "r%d: resetting raft state to applied index %d (HardState %+v)",
+			desc.RangeID, appliedIndex, hs)
+		if err := clearRaftEngineData(desc.RangeID, batch); err != nil {
+			return err
+		}
+		if err := rsl.SynthesizeHardState(ctx, batch, hs, truncState, appliedIndex); err != nil {
+			return err
+		}
+	} else {
+		if hs.Commit < appliedIndex {
+			lastIndex, err := rsl.LoadLastIndex(ctx, s.engine, s.raftEng)
+			if err != nil {
+				return err
+			}
+			if lastIndex < appliedIndex {
+				return errors.AssertionFailedf(
+					"r%d: raft log entries (%d, %d] missing from raft engine (HardState %+v)",
+					desc.RangeID, lastIndex, appliedIndex, hs)
+			}
+			log.Infof(ctx, "r%d: raising HardState commit index %d to applied index %d",
+				desc.RangeID, hs.Commit, appliedIndex)
+			hs.Commit = appliedIndex
+			if err := rsl.SetHardState(ctx, batch, hs); err != nil {
+				return err
+			}
+		}
+		prefix := keys.RaftLogPrefix(desc.RangeID)
+		end := keys.RaftLogKey(desc.RangeID, truncState.Index+1)
+		if err := storage.ClearRangeWithHeuristic(s.raftEng, batch, prefix, end); err != nil {
+			return err
+		}
+	}
+	if batch.Empty() {
+		return nil
+	}
+	return batch.Commit(true /* sync */)
+}
+
+// removeRaftEngineGarbage removes the raft state of replicas that no longer
+// exist on the store from its separate raft engine, if any. Such state is left
+// behind if the store crashed after durably removing a replica from the state
+// machine engine, but before its raft state was removed. Uninitialized replicas
+// don't have a range descriptor and are only represented by their HardState,
+// which never has a non-zero Commit; their raft state is left alone.
+//
+// This must be called after all initialized replicas have been added to the
+// store.
+func (s *Store) removeRaftEngineGarbage(ctx context.Context) error {
+	if !s.separateRaftEngine() {
+		return nil
+	}
+	end := keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey()
+	iter := s.raftEng.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{UpperBound: end})
+	defer iter.Close()
+
+	batch := s.raftEng.NewWriteOnlyBatch()
+	defer batch.Close()
+	var removed int
+	for iter.SeekGE(storage.MakeMVCCMetadataKey(keys.LocalRangeIDPrefix.AsRawKey())); ; {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		rangeID, _, _, _, err := keys.DecodeRangeIDKey(iter.UnsafeKey().Key)
+		if err != nil {
+			return err
+		}
+		if _, err := s.GetReplica(rangeID); err != nil {
+			rsl := stateloader.Make(rangeID)
+			hs, err := rsl.LoadHardState(ctx, s.raftEng)
+			if err != nil {
+				return err
+			}
+			lastIndex, err := rsl.LoadLastIndex(ctx, s.engine, s.raftEng)
+			if err != nil {
+				return err
+			}
+			if hs.Commit != 0 || lastIndex != 0 {
+				if err := clearRaftEngineData(rangeID, batch); err != nil {
+					return err
+				}
+				removed++
+			}
+		}
+		iter.SeekGE(storage.MakeMVCCMetadataKey(keys.MakeRangeIDPrefix(rangeID + 1)))
+	}
+	if removed == 0 {
+		return nil
+	}
+	log.Infof(ctx, "removed raft state of %d destroyed replicas from raft engine", removed)
+	return batch.Commit(true /* sync */)
+}
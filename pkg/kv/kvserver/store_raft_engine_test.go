@@ -0,0 +1,224 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/v3/raftpb"
+)
+
+// startStoreWithRaftEngine bootstraps a single-range store in eng and starts
+// it with raftEng as its separate raft engine. The stopper takes ownership of
+// both engines.
+func startStoreWithRaftEngine(
+	t *testing.T, stopper *stop.Stopper, eng, raftEng storage.Engine,
+) *Store {
+	ctx := context.Background()
+	cfg := TestStoreConfig(nil /* clock */)
+	cfg.Transport = NewDummyRaftTransport(cfg.Settings)
+	cfg.TestingKnobs.DisableScanner = true
+	factory := &testSenderFactory{}
+	cfg.DB = kv.NewDB(cfg.AmbientCtx, factory, cfg.Clock, stopper)
+
+	require.NoError(t, WriteClusterVersion(ctx, eng, clusterversion.TestingClusterVersion))
+	require.NoError(t, InitEngine(ctx, eng, testIdent))
+	kvs, _ := bootstrap.MakeMetadataSchema(
+		keys.SystemSQLCodec, cfg.DefaultZoneConfig, cfg.DefaultSystemZoneConfig,
+	).GetInitialValues()
+	require.NoError(t, WriteInitialClusterData(
+		ctx, eng, kvs, clusterversion.TestingBinaryVersion,
+		1 /* numStores */, nil /* splits */, cfg.Clock.PhysicalNow(),
+	))
+
+	combined := storage.WithRaftEngine(eng, raftEng)
+	stopper.AddCloser(combined)
+	store := NewStore(ctx, cfg, combined, &roachpb.NodeDescriptor{NodeID: 1})
+	factory.setStore(store)
+	require.NoError(t, store.Start(ctx, stopper))
+	return store
+}
+
+// countKeys returns the number of keys in reader within [start, end).
+func countKeys(t *testing.T, reader storage.Reader, start, end roachpb.Key) int {
+	var n int
+	require.NoError(t, reader.MVCCIterate(start, end, storage.MVCCKeyIterKind,
+		func(storage.MVCCKeyValue) error {
+			n++
+			return nil
+		}))
+	return n
+}
+
+// TestStoreSeparateRaftEngine verifies that an existing store's raft log is
+// moved into its separate raft engine when it starts, that new raft log
+// entries are written to the raft engine only, and that the store refuses to
+// start without its raft engine afterwards.
+func TestStoreSeparateRaftEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	eng := storage.NewDefaultInMem()
+	raftEng := storage.NewDefaultInMem()
+	rsl := stateloader.Make(1)
+
+	// A sideloaded SSTable left in the state machine engine by a previous run.
+	sstPath := filepath.Join(sideloadedPath(eng.GetAuxiliaryDir(), 1), "i10.t5")
+	require.NoError(t, eng.MkdirAll(filepath.Dir(sstPath)))
+	require.NoError(t, eng.WriteFile(sstPath, []byte("sst")))
+
+	store := startStoreWithRaftEngine(t, stopper, eng, raftEng)
+	require.Equal(t, raftEng, store.RaftEngine())
+
+	// The sideloaded SSTable was moved to the raft engine.
+	data, err := raftEng.ReadFile(
+		filepath.Join(sideloadedPath(raftEng.GetAuxiliaryDir(), 1), "i10.t5"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("sst"), data)
+	_, err = eng.Stat(sideloadedDir(eng))
+	require.True(t, oserror.IsNotExist(err), "%v", err)
+
+	// The HardState written by the bootstrap was moved to the raft engine.
+	hs, err := rsl.LoadHardState(ctx, raftEng)
+	require.NoError(t, err)
+	require.NotEqual(t, raftpb.HardState{}, hs)
+	hs, err = rsl.LoadHardState(ctx, eng)
+	require.NoError(t, err)
+	require.Equal(t, raftpb.HardState{}, hs)
+
+	// Both engines are marked as having been migrated.
+	for _, e := range []storage.Engine{eng, raftEng} {
+		ident, found, err := readRaftEngineMarker(ctx, e)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, testIdent, ident)
+	}
+
+	// Writes append raft log entries to the raft engine only.
+	require.NoError(t, store.DB().Put(ctx, "a", "b"))
+	prefix := keys.RaftLogPrefix(1)
+	require.NotZero(t, countKeys(t, raftEng, prefix, prefix.PrefixEnd()))
+	require.Zero(t, countKeys(t, eng, prefix, prefix.PrefixEnd()))
+
+	// The store can't be started without its raft engine anymore.
+	cfg := TestStoreConfig(nil /* clock */)
+	cfg.Transport = NewDummyRaftTransport(cfg.Settings)
+	cfg.DB = kv.NewDB(cfg.AmbientCtx, &testSenderFactory{}, cfg.Clock, stopper)
+	otherStopper := stop.NewStopper()
+	defer otherStopper.Stop(ctx)
+	err = NewStore(ctx, cfg, eng, &roachpb.NodeDescriptor{NodeID: 1}).Start(ctx, otherStopper)
+	require.Error(t, err)
+	require.Regexp(t, "keeps its raft log in a separate engine", err)
+}
+
+// TestStoreReconcileRaftEngine verifies that the raft state left behind in a
+// separate raft engine by a crash is reconciled with the state machine.
+func TestStoreReconcileRaftEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	eng := storage.NewDefaultInMem()
+	raftEng := storage.NewDefaultInMem()
+	store := startStoreWithRaftEngine(t, stopper, eng, raftEng)
+
+	// setState writes the state machine and raft state of a fictional replica.
+	setState := func(
+		rangeID roachpb.RangeID, truncIndex, appliedIndex uint64, hs raftpb.HardState, lo, hi uint64,
+	) {
+		rsl := stateloader.Make(rangeID)
+		if truncIndex != 0 {
+			require.NoError(t, rsl.SetRaftTruncatedState(
+				ctx, eng, &roachpb.RaftTruncatedState{Index: truncIndex, Term: 5}))
+			require.NoError(t, rsl.SetRangeAppliedState(
+				ctx, eng, appliedIndex, 1 /* leaseAppliedIndex */, &enginepb.MVCCStats{}))
+		}
+		require.NoError(t, rsl.SetHardState(ctx, raftEng, hs))
+		for i := lo; i <= hi; i++ {
+			require.NoError(t, storage.MVCCPutProto(ctx, raftEng, nil, /* ms */
+				keys.RaftLogKey(rangeID, i), hlc.Timestamp{}, nil, /* txn */
+				&raftpb.Entry{Index: i, Term: 5}))
+		}
+	}
+	logKeys := func(rangeID roachpb.RangeID) int {
+		prefix := keys.RaftLogPrefix(rangeID)
+		return countKeys(t, raftEng, prefix, prefix.PrefixEnd())
+	}
+
+	// r50 was initialized by a snapshot whose raft engine writes were lost: its
+	// stale log is removed and its HardState synthesized.
+	setState(50, 20, 20, raftpb.HardState{Term: 3, Vote: 2, Commit: 5}, 6, 8)
+	require.NoError(t, store.reconcileRaftEngine(ctx, &roachpb.RangeDescriptor{RangeID: 50}))
+	require.Zero(t, logKeys(50))
+	hs, err := stateloader.Make(50).LoadHardState(ctx, raftEng)
+	require.NoError(t, err)
+	require.Equal(t, raftpb.HardState{Term: 5, Commit: 20}, hs)
+
+	// r51 crashed during a log truncation: the entries at or below the
+	// truncated index are removed.
+	setState(51, 20, 25, raftpb.HardState{Term: 5, Commit: 25}, 15, 25)
+	require.NoError(t, store.reconcileRaftEngine(ctx, &roachpb.RangeDescriptor{RangeID: 51}))
+	require.Equal(t, 5, logKeys(51))
+
+	// r52 applied entries whose HardState update wasn't synced: its HardState's
+	// commit index is raised to the applied index.
+	setState(52, 10, 20, raftpb.HardState{Term: 5, Commit: 15}, 11, 22)
+	require.NoError(t, store.reconcileRaftEngine(ctx, &roachpb.RangeDescriptor{RangeID: 52}))
+	require.Equal(t, 12, logKeys(52))
+	hs, err = stateloader.Make(52).LoadHardState(ctx, raftEng)
+	require.NoError(t, err)
+	require.Equal(t, raftpb.HardState{Term: 5, Commit: 20}, hs)
+
+	// r53 is missing applied entries, which must never happen.
+	setState(53, 10, 20, raftpb.HardState{Term: 5, Commit: 15}, 11, 18)
+	require.Regexp(t, "missing from raft engine",
+		store.reconcileRaftEngine(ctx, &roachpb.RangeDescriptor{RangeID: 53}))
+
+	// r60 was destroyed, but its raft state wasn't removed. r61 is an
+	// uninitialized replica, whose HardState has to be preserved.
+	setState(60, 0, 0, raftpb.HardState{Term: 5, Commit: 7}, 1, 7)
+	setState(61, 0, 0, raftpb.HardState{Term: 5, Vote: 1}, 1, 0)
+	require.NoError(t, store.removeRaftEngineGarbage(ctx))
+	for _, rangeID := range []roachpb.RangeID{50, 51, 52, 53, 60} {
+		hs, err := stateloader.Make(rangeID).LoadHardState(ctx, raftEng)
+		require.NoError(t, err)
+		require.Equal(t, raftpb.HardState{}, hs, "r%d", rangeID)
+		require.Zero(t, logKeys(rangeID), "r%d", rangeID)
+	}
+	for _, rangeID := range []roachpb.RangeID{1, 61} {
+		hs, err := stateloader.Make(rangeID).LoadHardState(ctx, raftEng)
+		require.NoError(t, err)
+		require.NotEqual(t, raftpb.HardState{}, hs, "r%d", rangeID)
+	}
+}
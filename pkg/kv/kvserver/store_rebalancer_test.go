@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -75,6 +76,7 @@ type testRange struct {
 	// The first storeID in the list will be the leaseholder.
 	storeIDs []roachpb.StoreID
 	qps      float64
+	cpu      time.Duration
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
@@ -102,6 +104,7 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		acc.addReplica(replicaWithStats{
 			repl: repl,
 			qps:  r.qps,
+			cpu:  float64(r.cpu),
 		})
 	}
 	rr.update(acc)
@@ -183,7 +186,7 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 		loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 		hottestRanges := rr.topQPS()
 		_, target, _ := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+			ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 		if target.StoreID != tc.expectTarget {
 			t.Errorf("got target store %d for range with replicas %v and %f qps; want %d",
 				target.StoreID, tc.storeIDs, tc.qps, tc.expectTarget)
@@ -191,6 +194,97 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 	}
 }
 
+func TestChooseLeaseToTransferCPU(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// All stores serve the same QPS, but s5 is under-utilized in terms of CPU,
+	// s2-s4 are in the middle, and s1 is over-utilized.
+	var stores []*roachpb.StoreDescriptor
+	for i, cpu := range []time.Duration{1500, 1100, 1000, 900, 500} {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i + 1),
+			Node:    roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i + 1)},
+			Capacity: roachpb.StoreCapacity{
+				QueriesPerSecond: 1000,
+				CPUPerSecond:     float64(cpu * time.Millisecond),
+			},
+		})
+	}
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+	storeList, _, _ := a.storePool.getStoreList(storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	const minCPU = float64(800 * time.Millisecond)
+	const maxCPU = float64(1200 * time.Millisecond)
+
+	localDesc := *stores[0]
+	cfg := TestStoreConfig(nil)
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	rq := newReplicateQueue(s, g, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	testCases := []struct {
+		storeIDs     []roachpb.StoreID
+		cpu          time.Duration
+		expectTarget roachpb.StoreID
+	}{
+		{[]roachpb.StoreID{1}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 2}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 3}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 4}, 100 * time.Millisecond, 4},
+		{[]roachpb.StoreID{1, 5}, 100 * time.Millisecond, 5},
+		{[]roachpb.StoreID{5, 1}, 100 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 4}, 200 * time.Millisecond, 0},
+		{[]roachpb.StoreID{1, 5}, 700 * time.Millisecond, 5},
+		{[]roachpb.StoreID{1, 5}, 800 * time.Millisecond, 0},
+	}
+
+	for _, tc := range testCases {
+		loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, cpu: tc.cpu}})
+		hottestRanges := rr.topCPU()
+		_, target, _ := sr.chooseLeaseToTransfer(
+			ctx, LBRebalancingCPU, &hottestRanges, &localDesc, storeList, storeMap, minCPU, maxCPU)
+		if target.StoreID != tc.expectTarget {
+			t.Errorf("got target store %d for range with replicas %v and %s cpu; want %d",
+				target.StoreID, tc.storeIDs, tc.cpu, tc.expectTarget)
+		}
+
+		// The range serves no queries, so it isn't worth moving for QPS.
+		hottestRanges = rr.topQPS()
+		_, target, _ = sr.chooseLeaseToTransfer(
+			ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, 800, 1200)
+		if target.StoreID != 0 {
+			t.Errorf("got target store %d for range with replicas %v and no qps; want none",
+				target.StoreID, tc.storeIDs)
+		}
+	}
+}
+
 func TestChooseReplicaToRebalance(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -295,7 +389,7 @@ func TestChooseReplicaToRebalance(t *testing.T) {
 			loadRanges(rr, s, []testRange{{storeIDs: tc.storeIDs, qps: tc.qps}})
 			hottestRanges := rr.topQPS()
 			_, targets := sr.chooseReplicaToRebalance(
-				ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+				ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 
 			if len(targets) != len(tc.expectTargets) {
 				t.Fatalf("chooseReplicaToRebalance(existing=%v, qps=%f) got %v; want %v",
@@ -380,7 +474,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	}
 
 	_, target, _ := sr.chooseLeaseToTransfer(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTarget := roachpb.StoreID(4)
 	if target.StoreID != expectTarget {
 		t.Errorf("got target store s%d for range with RaftStatus %v; want s%d",
@@ -395,7 +489,7 @@ func TestNoLeaseTransferToBehindReplicas(t *testing.T) {
 	repl = hottestRanges[0].repl
 
 	_, targets := sr.chooseReplicaToRebalance(
-		ctx, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
+		ctx, LBRebalancingQueries, &hottestRanges, &localDesc, storeList, storeMap, minQPS, maxQPS)
 	expectTargets := []roachpb.ReplicationTarget{
 		{NodeID: 4, StoreID: 4}, {NodeID: 5, StoreID: 5}, {NodeID: 3, StoreID: 3},
 	}
@@ -0,0 +1,91 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"golang.org/x/time/rate"
+)
+
+// encryptionReencryptionRate is the rate at which sstables encrypted with a
+// retired data key are rewritten under the active data key.
+var encryptionReencryptionRate = settings.RegisterByteSizeSetting(
+	"storage.encryption.reencryption_rate",
+	"the rate limit (bytes/sec) at which sstables encrypted with retired data keys are "+
+		"rewritten under the active data key when encryption-at-rest is enabled (0 disables re-encryption)",
+	8<<20,
+	settings.NonNegativeInt,
+)
+
+// reencryptionInterval is the interval at which the store looks for sstables
+// encrypted with retired data keys.
+const reencryptionInterval = time.Minute
+
+// reencryptionBurst is the burst of the re-encryption rate limiter, and the
+// maximum size of the chunks in which sstables are rewritten.
+const reencryptionBurst = 128 << 10 // 128 KB
+
+// startReencryption starts a goroutine which periodically rewrites the
+// sstables of the store's engines that are encrypted with a retired data key
+// under the active data key. Data keys are rotated only for new files, so
+// without it, an sstable remains encrypted with a retired key until a
+// compaction happens to rewrite it, which may never happen for cold data.
+func (s *Store) startReencryption(ctx context.Context) {
+	engines := []storage.Engine{s.engine}
+	if s.raftEng != s.engine {
+		engines = append(engines, s.raftEng)
+	}
+	sv := &s.cfg.Settings.SV
+	limiter := rate.NewLimiter(rate.Limit(encryptionReencryptionRate.Get(sv)), reencryptionBurst)
+	encryptionReencryptionRate.SetOnChange(sv, func() {
+		limiter.SetLimit(rate.Limit(encryptionReencryptionRate.Get(sv)))
+	})
+
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		for {
+			timer.Reset(reencryptionInterval)
+			select {
+			case <-timer.C:
+				timer.Read = true
+			case <-s.stopper.ShouldQuiesce():
+				return
+			}
+			if encryptionReencryptionRate.Get(sv) == 0 {
+				continue
+			}
+			for _, eng := range engines {
+				files, bytes, err := eng.ReencryptDataFiles(ctx, limiter)
+				s.metrics.EncryptionReencryptedFiles.Inc(int64(files))
+				s.metrics.EncryptionReencryptedBytes.Inc(bytes)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Warningf(ctx, "failed to re-encrypt sstables: %v", err)
+					}
+					break
+				}
+				if files > 0 {
+					log.Infof(ctx, "re-encrypted %d sstables (%d bytes) under the active data key",
+						files, bytes)
+				}
+			}
+		}
+	})
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
@@ -129,6 +130,15 @@ func (s *Store) Send(
 				if ba.Timestamp.Less(br.Timestamp) {
 					s.cfg.Clock.Update(br.Timestamp)
 				}
+				// Non-transactional writes to ranges in non-blocking mode are
+				// placed ahead of present time. Like the TxnCoordSender does for
+				// transactions, wait for their timestamp to pass before
+				// returning to the client.
+				if br.Timestamp.IsFlagSet(hlc.TimestampFlag_SYNTHETIC) {
+					if err := s.cfg.Clock.SleepUntil(ctx, br.Timestamp); err != nil {
+						br, pErr = nil, roachpb.NewError(err)
+					}
+				}
 			}
 		}
 
@@ -215,14 +215,15 @@ func (msstw *multiSSTWriter) Close() {
 //
 // 1. Replicated range-id local key range
 // 2. Range-local key range
-// 3. Two lock-table key ranges (optional)
-// 4. User key range
+// 3. Range tombstone key range
+// 4. Two lock-table key ranges (optional)
+// 5. User key range
 func (kvSS *kvBatchSnapshotStrategy) Receive(
 	ctx context.Context, stream incomingSnapshotStream, header SnapshotRequest_Header,
 ) (IncomingSnapshot, error) {
 	assertStrategy(ctx, header, SnapshotRequest_KV_BATCH)
 
-	// At the moment we'll write at most five SSTs.
+	// At the moment we'll write at most six SSTs.
 	// TODO(jeffreyxiao): Re-evaluate as the default range size grows.
 	keyRanges := rditer.MakeReplicatedKeyRanges(header.State.Desc)
 	msstw, err := newMultiSSTWriter(ctx, kvSS.scratch, keyRanges, kvSS.sstChunkSize)
@@ -393,7 +394,7 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 
 	rangeID := header.State.Desc.RangeID
 
-	if err := iterateEntries(ctx, snap.EngineSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
+	if err := iterateEntries(ctx, snap.RaftEngineSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
 		return 0, err
 	}
 
@@ -967,6 +968,7 @@ func SendEmptySnapshot(
 		// quickly.
 		SnapshotRequest_VIA_SNAPSHOT_QUEUE,
 		eng,
+		eng,
 		desc.RangeID,
 		raftentry.NewCache(1), // cache is not used
 		func(func(SideloadStorage) error) error { return nil }, // this is used for sstables, not needed here as there are no logs
@@ -26,9 +26,14 @@ import (
 
 // splitPreApply is called when the raft command is applied. Any
 // changes to the given ReadWriter will be written atomically with the
-// split commit.
+// split commit. Changes to the RHS's HardState are staged in raftReadWriter,
+// which is distinct from readWriter if the store keeps its raft log in a
+// separate engine.
 func splitPreApply(
-	ctx context.Context, readWriter storage.ReadWriter, split roachpb.SplitTrigger, r *Replica,
+	ctx context.Context,
+	readWriter, raftReadWriter storage.ReadWriter,
+	split roachpb.SplitTrigger,
+	r *Replica,
 ) {
 	// Sanity check that the store is in the split.
 	//
@@ -90,7 +95,7 @@ func splitPreApply(
 			if rightRepl.IsInitialized() {
 				log.Fatalf(ctx, "unexpectedly found initialized newer RHS of split: %v", rightRepl.Desc())
 			}
-			hs, err = rightRepl.raftMu.stateLoader.LoadHardState(ctx, readWriter)
+			hs, err = rightRepl.raftMu.stateLoader.LoadHardState(ctx, raftReadWriter)
 			if err != nil {
 				log.Fatalf(ctx, "failed to load hard state for removed rhs: %v", err)
 			}
@@ -101,7 +106,7 @@ func splitPreApply(
 			log.Fatalf(ctx, "failed to clear range data for removed rhs: %v", err)
 		}
 		if rightRepl != nil {
-			if err := rightRepl.raftMu.stateLoader.SetHardState(ctx, readWriter, hs); err != nil {
+			if err := rightRepl.raftMu.stateLoader.SetHardState(ctx, raftReadWriter, hs); err != nil {
 				log.Fatalf(ctx, "failed to set hard state with 0 commit index for removed rhs: %v", err)
 			}
 		}
@@ -112,7 +117,7 @@ func splitPreApply(
 	// replica is initialized (combining it with existing or default
 	// Term and Vote). This is the common case.
 	rsl := stateloader.Make(split.RightDesc.RangeID)
-	if err := rsl.SynthesizeRaftState(ctx, readWriter); err != nil {
+	if err := rsl.SynthesizeRaftState(ctx, readWriter, raftReadWriter); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 
@@ -324,9 +329,12 @@ func (s *Store) SplitRange(
 	if rightReplOrNil == nil {
 		throwawayRightWriteStats := new(replicaStats)
 		leftRepl.writeStats.splitRequestCounts(throwawayRightWriteStats)
+		throwawayRightCPUStats := new(replicaStats)
+		leftRepl.cpuStats.splitRequestCounts(throwawayRightCPUStats)
 	} else {
 		rightRepl := rightReplOrNil
 		leftRepl.writeStats.splitRequestCounts(rightRepl.writeStats)
+		leftRepl.cpuStats.splitRequestCounts(rightRepl.cpuStats)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Errorf("unable to add replica %v: %s", rightRepl, err)
 		}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -47,8 +48,9 @@ type Stores struct {
 	}
 }
 
-var _ kv.Sender = &Stores{}      // Stores implements the client.Sender interface
-var _ gossip.Storage = &Stores{} // Stores implements the gossip.Storage interface
+var _ kv.Sender = &Stores{}                      // Stores implements the client.Sender interface
+var _ gossip.Storage = &Stores{}                 // Stores implements the gossip.Storage interface
+var _ admission.StoreMetricsProvider = &Stores{} // Stores provides store metrics to admission control
 
 // NewStores returns a local-only sender which directly accesses
 // a collection of stores.
@@ -134,6 +136,25 @@ func (ls *Stores) VisitStores(visitor func(s *Store) error) error {
 	return err
 }
 
+// GetStoreMetrics implements admission.StoreMetricsProvider. Stores whose
+// engine metrics can't be retrieved are omitted.
+func (ls *Stores) GetStoreMetrics() []admission.StoreMetrics {
+	var storeMetrics []admission.StoreMetrics
+	_ = ls.VisitStores(func(s *Store) error {
+		m, err := s.Engine().GetMetrics()
+		if err != nil {
+			log.Warningf(context.Background(), "unable to retrieve metrics of s%d: %v", s.StoreID(), err)
+			return nil
+		}
+		storeMetrics = append(storeMetrics, admission.StoreMetrics{
+			StoreID:           int32(s.StoreID()),
+			ReadAmplification: m.ReadAmplification,
+		})
+		return nil
+	})
+	return storeMetrics
+}
+
 // GetReplicaForRangeID returns the replica and store which contains the
 // specified range. If the replica is not found on any store then
 // roachpb.RangeNotFoundError will be returned.
@@ -103,6 +103,7 @@ const (
 	updatesTSCacheOnErr             // commands which make read data available on errors
 	needsRefresh                    // commands which require refreshes to avoid serializable retries
 	canBackpressure                 // commands which deserve backpressure when a Range grows too large
+	canSkipLocked                   // commands which can evaluate under the SkipLocked wait policy
 )
 
 // IsReadOnly returns true iff the request is read-only. A request is
@@ -199,6 +200,13 @@ func CanBackpressure(args Request) bool {
 	return (args.flags() & canBackpressure) != 0
 }
 
+// CanSkipLocked returns whether the command can evaluate under the
+// SkipLocked wait policy, skipping over keys that are locked by other
+// transactions instead of waiting for them to be released.
+func CanSkipLocked(args Request) bool {
+	return (args.flags() & canSkipLocked) != 0
+}
+
 // Request is an interface for RPC requests.
 type Request interface {
 	protoutil.Message
@@ -1135,7 +1143,7 @@ func scanLockStrength(forUpdate bool) lock.Strength {
 }
 
 func (*GetRequest) flags() int {
-	return isRead | isTxn | updatesTSCache | needsRefresh
+	return isRead | isTxn | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 func (*PutRequest) flags() int {
@@ -1191,6 +1199,12 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isRead | isWrite | isRange | isAlone
 	}
+	// A DeleteRange using a range tombstone is non-transactional as well. It
+	// must not be written below any reads of the span, nor be written under by
+	// later writes, so it consults and updates the timestamp cache.
+	if drr.UseRangeTombstone {
+		return isWrite | isRange | isAlone | consultsTSCache | updatesTSCache | canBackpressure
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave intents or
 	// tombstones for keys which don't yet exist or keys that already have
 	// tombstones on them, but still wants to prevent anybody from writing under
@@ -1213,7 +1227,7 @@ func (sr *ScanRequest) flags() int {
 	if sr.KeyLocking != lock.None {
 		maybeLocking = isLocking
 	}
-	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh
+	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 func (rsr *ReverseScanRequest) flags() int {
@@ -1221,7 +1235,7 @@ func (rsr *ReverseScanRequest) flags() int {
 	if rsr.KeyLocking != lock.None {
 		maybeLocking = isLocking
 	}
-	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh
+	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 // EndTxn updates the timestamp cache to prevent replays.
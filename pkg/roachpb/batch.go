@@ -393,7 +393,13 @@ func (ba *BatchRequest) LockSpanIterate(br *BatchResponse, fn func(Span, lock.Du
 // ResumeSpan is subtracted from the request span to provide a more
 // minimal span of keys affected by the request. The supplied function
 // is called with each span.
-func (ba *BatchRequest) RefreshSpanIterate(br *BatchResponse, fn func(Span)) {
+//
+// Requests in a batch with a SkipLocked wait policy only need to refresh the
+// keys that they returned, since any keys that they skipped over were never
+// observed. For these requests, the supplied function is called with a point
+// span for each returned key. Get requests which returned no value are treated
+// like skipped keys: they contribute no refresh span.
+func (ba *BatchRequest) RefreshSpanIterate(br *BatchResponse, fn func(Span)) error {
 	for i, arg := range ba.Requests {
 		req := arg.GetInner()
 		if !NeedsRefresh(req) {
@@ -403,10 +409,59 @@ func (ba *BatchRequest) RefreshSpanIterate(br *BatchResponse, fn func(Span)) {
 		if br != nil {
 			resp = br.Responses[i].GetInner()
 		}
+		if ba.WaitPolicy == lock.WaitPolicy_SkipLocked && CanSkipLocked(req) {
+			if err := ResponseKeyIterate(req, resp, func(k Key) {
+				fn(Span{Key: k})
+			}); err != nil {
+				return err
+			}
+			continue
+		}
 		if span, ok := ActualSpan(req, resp); ok {
 			fn(span)
 		}
 	}
+	return nil
+}
+
+// ResponseKeyIterate calls the passed function with the keys returned in the
+// provided request's response. If no keys are being returned, the function is
+// not called. Only requests for which CanSkipLocked returns true are
+// supported.
+func ResponseKeyIterate(req Request, resp Response, fn func(Key)) error {
+	if resp == nil {
+		return errors.AssertionFailedf("unexpected nil response for %s request", req.Method())
+	}
+	switch v := resp.(type) {
+	case *GetResponse:
+		if v.Value != nil {
+			fn(req.Header().Key)
+		}
+		return nil
+	case *ScanResponse:
+		return responseKeyIterate(v.Rows, v.BatchResponses, fn)
+	case *ReverseScanResponse:
+		return responseKeyIterate(v.Rows, v.BatchResponses, fn)
+	default:
+		return errors.AssertionFailedf("cannot iterate over keys of %s response", req.Method())
+	}
+}
+
+func responseKeyIterate(rows []KeyValue, batchResponses [][]byte, fn func(Key)) error {
+	for i := range rows {
+		fn(rows[i].Key)
+	}
+	for _, b := range batchResponses {
+		for len(b) > 0 {
+			key, _, rest, err := enginepb.ScanDecodeKeyValueNoTS(b)
+			if err != nil {
+				return err
+			}
+			fn(key)
+			b = rest
+		}
+	}
+	return nil
 }
 
 // ActualSpan returns the actual request span which was operated on,
@@ -305,7 +305,7 @@ func TestRefreshSpanIterate(t *testing.T) {
 	fn := func(span Span) {
 		readSpans = append(readSpans, span)
 	}
-	ba.RefreshSpanIterate(&br, fn)
+	require.NoError(t, ba.RefreshSpanIterate(&br, fn))
 	// The conditional put and init put are not considered read spans.
 	expReadSpans := []Span{testCases[4].span, testCases[5].span, testCases[6].span, testCases[7].span}
 	require.Equal(t, expReadSpans, readSpans)
@@ -324,7 +324,7 @@ func TestRefreshSpanIterate(t *testing.T) {
 	}
 
 	readSpans = []Span{}
-	ba.RefreshSpanIterate(&br, fn)
+	require.NoError(t, ba.RefreshSpanIterate(&br, fn))
 	expReadSpans = []Span{
 		sp("a", "b"),
 		sp("b", ""),
@@ -332,6 +332,30 @@ func TestRefreshSpanIterate(t *testing.T) {
 		sp("g", "h"),
 	}
 	require.Equal(t, expReadSpans, readSpans)
+
+	// Batches with a SkipLocked wait policy only refresh the keys returned by
+	// scans and gets.
+	ba = BatchRequest{}
+	ba.WaitPolicy = lock.WaitPolicy_SkipLocked
+	ba.Add(&ScanRequest{RequestHeader: RequestHeaderFromSpan(sp("a", "e"))})
+	ba.Add(&ReverseScanRequest{RequestHeader: RequestHeaderFromSpan(sp("f", "k"))})
+	ba.Add(&GetRequest{RequestHeader: RequestHeaderFromSpan(sp("l", ""))})
+	ba.Add(&GetRequest{RequestHeader: RequestHeaderFromSpan(sp("m", ""))})
+	br = BatchResponse{}
+	br.Add(&ScanResponse{Rows: []KeyValue{{Key: Key("a")}, {Key: Key("c")}}})
+	br.Add(&ReverseScanResponse{Rows: []KeyValue{{Key: Key("j")}}})
+	br.Add(&GetResponse{Value: &Value{}})
+	br.Add(&GetResponse{})
+
+	readSpans = []Span{}
+	require.NoError(t, ba.RefreshSpanIterate(&br, fn))
+	expReadSpans = []Span{
+		sp("a", ""),
+		sp("c", ""),
+		sp("j", ""),
+		sp("l", ""),
+	}
+	require.Equal(t, expReadSpans, readSpans)
 }
 
 func TestBatchResponseCombine(t *testing.T) {
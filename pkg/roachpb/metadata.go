@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -181,6 +182,9 @@ func (r *RangeDescriptor) Equal(other *RangeDescriptor) bool {
 	if !r.StickyBit.Equal(other.StickyBit) {
 		return false
 	}
+	if !r.GlobalReads.Equal(other.GlobalReads) {
+		return false
+	}
 	return true
 }
 
@@ -318,6 +322,14 @@ func (r *RangeDescriptor) GetStickyBit() hlc.Timestamp {
 	return *r.StickyBit
 }
 
+// GetGlobalReads returns the non-blocking mode state of this RangeDescriptor.
+func (r *RangeDescriptor) GetGlobalReads() GlobalReadsState {
+	if r.GlobalReads == nil {
+		return GlobalReadsState{}
+	}
+	return *r.GlobalReads
+}
+
 // Validate performs some basic validation of the contents of a range descriptor.
 func (r *RangeDescriptor) Validate() error {
 	if r.NextReplicaID == 0 {
@@ -375,6 +387,9 @@ func (r RangeDescriptor) SafeFormat(w redact.SafePrinter, _ rune) {
 	if s := r.GetStickyBit(); !s.IsEmpty() {
 		w.Printf(", sticky=%s", s)
 	}
+	if g := r.GetGlobalReads(); g.Lead != 0 {
+		w.Printf(", global_reads=%s", redact.Safe(g.Lead))
+	}
 	w.SafeString("]")
 }
 
@@ -478,12 +493,12 @@ func (sc StoreCapacity) String() string {
 // SafeFormat implements the redact.SafeFormatter interface.
 func (sc StoreCapacity) SafeFormat(w redact.SafePrinter, _ rune) {
 	w.Printf("disk (capacity=%s, available=%s, used=%s, logicalBytes=%s), "+
-		"ranges=%d, leases=%d, queries=%.2f, writes=%.2f, "+
+		"ranges=%d, leases=%d, queries=%.2f, writes=%.2f, cpu=%s, "+
 		"bytesPerReplica={%s}, writesPerReplica={%s}",
 		redact.Safe(humanizeutil.IBytes(sc.Capacity)), redact.Safe(humanizeutil.IBytes(sc.Available)),
 		redact.Safe(humanizeutil.IBytes(sc.Used)), redact.Safe(humanizeutil.IBytes(sc.LogicalBytes)),
 		sc.RangeCount, sc.LeaseCount, sc.QueriesPerSecond, sc.WritesPerSecond,
-		sc.BytesPerReplica, sc.WritesPerReplica)
+		redact.Safe(time.Duration(sc.CPUPerSecond)), sc.BytesPerReplica, sc.WritesPerReplica)
 }
 
 // FractionUsed computes the fraction of storage capacity that is in use.
@@ -59,7 +59,12 @@ func appendEmptySha256(password string) []byte {
 // CompareHashAndPassword tests that the provided bytes are equivalent to the
 // hash of the supplied password. If they are not equivalent, returns an
 // error.
+//
+// The hash can either be a bcrypt hash or a SCRAM-SHA-256 verifier.
 func CompareHashAndPassword(hashedPassword []byte, password string) error {
+	if IsSCRAMHash(hashedPassword) {
+		return compareSCRAMHashAndPassword(hashedPassword, password)
+	}
 	return bcrypt.CompareHashAndPassword(hashedPassword, appendEmptySha256(password))
 }
 
@@ -68,6 +73,64 @@ func HashPassword(password string) ([]byte, error) {
 	return bcrypt.GenerateFromPassword(appendEmptySha256(password), BcryptCost)
 }
 
+// PasswordHashMethod is the method used to hash the passwords stored in
+// system.users.
+type PasswordHashMethod int64
+
+const (
+	// HashBCrypt indicates bcrypt hashes, usable with the password
+	// authentication method only.
+	HashBCrypt PasswordHashMethod = 1
+	// HashSCRAMSHA256 indicates SCRAM-SHA-256 verifiers, usable with both the
+	// password and the scram-sha-256 authentication methods.
+	HashSCRAMSHA256 PasswordHashMethod = 2
+)
+
+// PasswordHashMethodSetting is the cluster setting that configures the
+// method used to hash passwords set via SQL.
+var PasswordHashMethodSetting = settings.RegisterEnumSetting(
+	"server.user_login.password_encryption",
+	"which hash method to use to encode new passwords. "+
+		"Users whose password is hashed with crdb-bcrypt cannot log in with "+
+		"the scram-sha-256 authentication method. scram-sha-256 only takes "+
+		"effect once the cluster version is upgraded.",
+	"crdb-bcrypt",
+	map[int64]string{
+		int64(HashBCrypt):      "crdb-bcrypt",
+		int64(HashSCRAMSHA256): "scram-sha-256",
+	},
+).WithPublic()
+
+// UpgradeBcryptStoredPasswordsToSCRAM is the cluster setting that
+// configures whether bcrypt password hashes are converted to SCRAM-SHA-256
+// verifiers upon successful cleartext password authentication.
+var UpgradeBcryptStoredPasswordsToSCRAM = settings.RegisterBoolSetting(
+	"server.user_login.upgrade_bcrypt_stored_passwords_to_scram",
+	"if server.user_login.password_encryption=scram-sha-256, this controls "+
+		"whether to automatically re-encode stored passwords using crdb-bcrypt "+
+		"to scram-sha-256 when users log in with the password authentication method",
+	false,
+).WithPublic()
+
+// HashPasswordWithMethod takes a raw password and returns its hash computed
+// with the given method.
+func HashPasswordWithMethod(method PasswordHashMethod, password string) ([]byte, error) {
+	switch method {
+	case HashBCrypt:
+		return HashPassword(password)
+	case HashSCRAMSHA256:
+		return HashPasswordSCRAM(password)
+	default:
+		return nil, errors.AssertionFailedf("unknown password hash method %d", method)
+	}
+}
+
+// GetConfiguredPasswordHashMethod returns the hash method configured via
+// server.user_login.password_encryption.
+func GetConfiguredPasswordHashMethod(sv *settings.Values) PasswordHashMethod {
+	return PasswordHashMethod(PasswordHashMethodSetting.Get(sv))
+}
+
 // PromptForPassword prompts for a password.
 // This is meant to be used when using a password.
 func PromptForPassword() (string, error) {
@@ -0,0 +1,322 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements SCRAM-SHA-256 (RFC 5802 and RFC 7677) as used by the
+// PostgreSQL SASL authentication exchange. Channel binding (SCRAM-SHA-256-PLUS)
+// is not supported.
+//
+// Unlike PostgreSQL, passwords are not normalized with SASLprep before being
+// hashed. The two only differ for passwords containing non-ASCII characters.
+
+// SCRAMSHA256MechanismName is the name of the SASL mechanism implemented
+// here.
+const SCRAMSHA256MechanismName = "SCRAM-SHA-256"
+
+// ScramIterCount is the iteration count to use when hashing passwords with
+// SCRAM-SHA-256. It is exposed for testing.
+//
+// 4096 is the minimum value recommended by RFC 7677, and the default used by
+// PostgreSQL.
+var ScramIterCount = 4096
+
+const (
+	scramHashPrefix  = SCRAMSHA256MechanismName + "$"
+	scramSaltLength  = 16
+	scramNonceLength = 18
+)
+
+// SCRAMHash is a SCRAM-SHA-256 password verifier. It does not contain enough
+// information to recover the password, but allows the server to verify the
+// proof sent by a client during a SCRAM exchange.
+//
+// It is stored in system.users in the same format as PostgreSQL uses for
+// pg_authid.rolpassword:
+//
+//	SCRAM-SHA-256$<iteration count>:<salt>$<StoredKey>:<ServerKey>
+//
+// where the salt and the keys are base64 encoded.
+type SCRAMHash struct {
+	Iters     int
+	Salt      []byte
+	StoredKey []byte
+	ServerKey []byte
+}
+
+// IsSCRAMHash returns true if the hashed password is a SCRAM-SHA-256 verifier
+// as opposed to a bcrypt hash.
+func IsSCRAMHash(hashedPassword []byte) bool {
+	return bytes.HasPrefix(hashedPassword, []byte(scramHashPrefix))
+}
+
+// ParseSCRAMHash parses a SCRAM-SHA-256 verifier as encoded by Encode.
+func ParseSCRAMHash(hashedPassword []byte) (SCRAMHash, error) {
+	var h SCRAMHash
+	if !IsSCRAMHash(hashedPassword) {
+		return h, errors.New("not a SCRAM-SHA-256 hash")
+	}
+	s := string(hashedPassword[len(scramHashPrefix):])
+	parts := strings.Split(s, "$")
+	if len(parts) != 2 {
+		return h, errors.New("malformed SCRAM-SHA-256 hash")
+	}
+	params := strings.Split(parts[0], ":")
+	keys := strings.Split(parts[1], ":")
+	if len(params) != 2 || len(keys) != 2 {
+		return h, errors.New("malformed SCRAM-SHA-256 hash")
+	}
+	var err error
+	if h.Iters, err = strconv.Atoi(params[0]); err != nil || h.Iters <= 0 {
+		return h, errors.New("invalid iteration count in SCRAM-SHA-256 hash")
+	}
+	if h.Salt, err = base64.StdEncoding.DecodeString(params[1]); err != nil {
+		return h, errors.Wrap(err, "invalid salt in SCRAM-SHA-256 hash")
+	}
+	if h.StoredKey, err = base64.StdEncoding.DecodeString(keys[0]); err != nil ||
+		len(h.StoredKey) != sha256.Size {
+		return h, errors.New("invalid stored key in SCRAM-SHA-256 hash")
+	}
+	if h.ServerKey, err = base64.StdEncoding.DecodeString(keys[1]); err != nil ||
+		len(h.ServerKey) != sha256.Size {
+		return h, errors.New("invalid server key in SCRAM-SHA-256 hash")
+	}
+	return h, nil
+}
+
+// Encode returns the representation of the verifier stored in system.users.
+func (h SCRAMHash) Encode() []byte {
+	return []byte(fmt.Sprintf("%s%d:%s$%s:%s", scramHashPrefix, h.Iters,
+		base64.StdEncoding.EncodeToString(h.Salt),
+		base64.StdEncoding.EncodeToString(h.StoredKey),
+		base64.StdEncoding.EncodeToString(h.ServerKey)))
+}
+
+// HashPasswordSCRAM takes a raw password and returns a SCRAM-SHA-256 verifier
+// with a random salt.
+func HashPasswordSCRAM(password string) ([]byte, error) {
+	salt := make([]byte, scramSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return makeSCRAMHash(password, salt, ScramIterCount).Encode(), nil
+}
+
+func makeSCRAMHash(password string, salt []byte, iters int) SCRAMHash {
+	saltedPassword := scramHi([]byte(password), salt, iters)
+	clientKey := scramHMAC(saltedPassword, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	return SCRAMHash{
+		Iters:     iters,
+		Salt:      salt,
+		StoredKey: storedKey[:],
+		ServerKey: scramHMAC(saltedPassword, []byte("Server Key")),
+	}
+}
+
+// MakeMockSCRAMHash returns a SCRAM verifier which no password matches, used
+// to carry out the exchange for users who have no verifier. As in PostgreSQL,
+// its salt is derived from the user name and a server secret rather than
+// drawn at random, so that repeated exchanges for the same user present the
+// same salt, as they would if the user had a verifier.
+func MakeMockSCRAMHash(username string, secret []byte) (SCRAMHash, error) {
+	keys := make([]byte, 2*sha256.Size)
+	if _, err := rand.Read(keys); err != nil {
+		return SCRAMHash{}, err
+	}
+	return SCRAMHash{
+		Iters:     ScramIterCount,
+		Salt:      scramHMAC(secret, []byte(username))[:scramSaltLength],
+		StoredKey: keys[:sha256.Size],
+		ServerKey: keys[sha256.Size:],
+	}, nil
+}
+
+// compareSCRAMHashAndPassword tests that the provided verifier was computed
+// from the supplied password. If it was not, returns an error.
+func compareSCRAMHashAndPassword(hashedPassword []byte, password string) error {
+	h, err := ParseSCRAMHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+	computed := makeSCRAMHash(password, h.Salt, h.Iters)
+	if subtle.ConstantTimeCompare(computed.StoredKey, h.StoredKey) != 1 {
+		return errors.New("password does not match SCRAM-SHA-256 hash")
+	}
+	return nil
+}
+
+func scramHMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// scramHi is the Hi() function of RFC 5802, i.e. PBKDF2 with HMAC-SHA-256 as
+// the pseudorandom function and an output length of a single block.
+func scramHi(password, salt []byte, iters int) []byte {
+	mac := hmac.New(sha256.New, password)
+	_, _ = mac.Write(salt)
+	var blockIndex [4]byte
+	binary.BigEndian.PutUint32(blockIndex[:], 1)
+	_, _ = mac.Write(blockIndex[:])
+	u := mac.Sum(nil)
+	result := append([]byte(nil), u...)
+	for i := 1; i < iters; i++ {
+		mac.Reset()
+		_, _ = mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
+
+// ErrSCRAMAuthFailed is returned by SCRAMServer when the client's proof does
+// not match the verifier, i.e. when the client used the wrong password.
+var ErrSCRAMAuthFailed = errors.New("SCRAM-SHA-256 proof does not match")
+
+// SCRAMServer is the server side of a SCRAM-SHA-256 exchange, which goes:
+//
+//	client-first-message  (client -> server)
+//	server-first-message  (server -> client)
+//	client-final-message  (client -> server)
+//	server-final-message  (server -> client)
+//
+// A SCRAMServer is used for a single exchange.
+type SCRAMServer struct {
+	hash SCRAMHash
+
+	// serverNonce is the server's part of the nonce.
+	serverNonce string
+
+	gs2Header          string
+	clientFirstBare    string
+	serverFirstMessage string
+	nonce              string
+}
+
+// NewSCRAMServer returns a SCRAMServer verifying the client's proof against
+// the given verifier.
+func NewSCRAMServer(hash SCRAMHash) (*SCRAMServer, error) {
+	raw := make([]byte, scramNonceLength)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	return &SCRAMServer{
+		hash:        hash,
+		serverNonce: base64.StdEncoding.EncodeToString(raw),
+	}, nil
+}
+
+// ServerFirstMessage parses the client-first-message and returns the
+// server-first-message.
+//
+// The user name sent by the client is ignored: as in PostgreSQL, the user is
+// the one provided in the connection's startup message.
+func (s *SCRAMServer) ServerFirstMessage(clientFirstMessage []byte) ([]byte, error) {
+	msg := string(clientFirstMessage)
+	// gs2-header = gs2-cbind-flag "," [ authzid ] ","
+	parts := strings.SplitN(msg, ",", 3)
+	if len(parts) != 3 {
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	switch {
+	case parts[0] == "n", parts[0] == "y":
+		// The client does not support channel binding, or thinks the server
+		// does not.
+	case strings.HasPrefix(parts[0], "p="):
+		return nil, errors.New("SCRAM channel binding is not supported")
+	default:
+		return nil, errors.Newf("invalid SCRAM channel binding flag %q", parts[0])
+	}
+	if parts[1] != "" {
+		return nil, errors.New("SCRAM authorization identities are not supported")
+	}
+	s.gs2Header = parts[0] + "," + parts[1] + ","
+	s.clientFirstBare = parts[2]
+
+	// client-first-message-bare = [reserved-mext ","] username "," nonce ["," extensions]
+	attrs := strings.Split(s.clientFirstBare, ",")
+	if len(attrs) < 2 {
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	if strings.HasPrefix(attrs[0], "m=") {
+		return nil, errors.New("SCRAM mandatory extensions are not supported")
+	}
+	if !strings.HasPrefix(attrs[0], "n=") {
+		return nil, errors.New("malformed SCRAM client-first-message: missing user name")
+	}
+	if !strings.HasPrefix(attrs[1], "r=") || len(attrs[1]) == len("r=") {
+		return nil, errors.New("malformed SCRAM client-first-message: missing nonce")
+	}
+	s.nonce = attrs[1][len("r="):] + s.serverNonce
+
+	s.serverFirstMessage = fmt.Sprintf("r=%s,s=%s,i=%d",
+		s.nonce, base64.StdEncoding.EncodeToString(s.hash.Salt), s.hash.Iters)
+	return []byte(s.serverFirstMessage), nil
+}
+
+// ServerFinalMessage parses the client-final-message, verifies the client's
+// proof and returns the server-final-message. ErrSCRAMAuthFailed is returned
+// if the proof does not match the verifier.
+func (s *SCRAMServer) ServerFinalMessage(clientFinalMessage []byte) ([]byte, error) {
+	msg := string(clientFinalMessage)
+	// client-final-message = channel-binding "," nonce ["," extensions] "," proof
+	proofIdx := strings.LastIndex(msg, ",p=")
+	if proofIdx < 0 {
+		return nil, errors.New("malformed SCRAM client-final-message: missing proof")
+	}
+	withoutProof := msg[:proofIdx]
+	proof, err := base64.StdEncoding.DecodeString(msg[proofIdx+len(",p="):])
+	if err != nil || len(proof) != sha256.Size {
+		return nil, errors.New("malformed SCRAM client-final-message: invalid proof")
+	}
+	attrs := strings.Split(withoutProof, ",")
+	if len(attrs) < 2 {
+		return nil, errors.New("malformed SCRAM client-final-message")
+	}
+	if attrs[0] != "c="+base64.StdEncoding.EncodeToString([]byte(s.gs2Header)) {
+		return nil, errors.New("SCRAM channel binding does not match client-first-message")
+	}
+	if attrs[1] != "r="+s.nonce {
+		return nil, errors.New("SCRAM nonce does not match server-first-message")
+	}
+
+	authMessage := []byte(s.clientFirstBare + "," + s.serverFirstMessage + "," + withoutProof)
+	clientSignature := scramHMAC(s.hash.StoredKey, authMessage)
+	clientKey := make([]byte, len(proof))
+	for i := range proof {
+		clientKey[i] = proof[i] ^ clientSignature[i]
+	}
+	storedKey := sha256.Sum256(clientKey)
+	if subtle.ConstantTimeCompare(storedKey[:], s.hash.StoredKey) != 1 {
+		return nil, ErrSCRAMAuthFailed
+	}
+
+	serverSignature := scramHMAC(s.hash.ServerKey, authMessage)
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)), nil
+}
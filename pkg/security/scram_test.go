@@ -0,0 +1,201 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc7677Hash is the verifier for the password "pencil" with the salt and
+// iteration count of the example exchange in RFC 7677.
+const rfc7677Hash = "SCRAM-SHA-256$4096:W22ZaJ0SNY7soEsUEjb6gQ==$" +
+	"WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=:wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU="
+
+func TestSCRAMHash(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	h, err := security.ParseSCRAMHash([]byte(rfc7677Hash))
+	require.NoError(t, err)
+	require.Equal(t, 4096, h.Iters)
+	require.Equal(t, rfc7677Hash, string(h.Encode()))
+	require.NoError(t, security.CompareHashAndPassword([]byte(rfc7677Hash), "pencil"))
+	require.Error(t, security.CompareHashAndPassword([]byte(rfc7677Hash), "pencils"))
+
+	hash, err := security.HashPasswordSCRAM("pencil")
+	require.NoError(t, err)
+	require.True(t, security.IsSCRAMHash(hash))
+	require.NotEqual(t, rfc7677Hash, string(hash))
+	require.NoError(t, security.CompareHashAndPassword(hash, "pencil"))
+
+	bcryptHash, err := security.HashPassword("pencil")
+	require.NoError(t, err)
+	require.False(t, security.IsSCRAMHash(bcryptHash))
+	require.NoError(t, security.CompareHashAndPassword(bcryptHash, "pencil"))
+
+	for _, bad := range []string{
+		"SCRAM-SHA-256$",
+		"SCRAM-SHA-256$4096:c2FsdA==",
+		"SCRAM-SHA-256$0:c2FsdA==$WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=:wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU=",
+		"SCRAM-SHA-256$4096:c2FsdA==$c2hvcnQ=:wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU=",
+	} {
+		_, err := security.ParseSCRAMHash([]byte(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+// scramClientFinal computes the client-final-message for the given password,
+// as a client would.
+func scramClientFinal(
+	t *testing.T, password, clientFirstBare, serverFirst string,
+) (clientFinal string, serverSignature string) {
+	attrs := strings.Split(serverFirst, ",")
+	require.Len(t, attrs, 3)
+	nonce := strings.TrimPrefix(attrs[0], "r=")
+	salt, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(attrs[1], "s="))
+	require.NoError(t, err)
+	var iters int
+	_, err = fmt.Sscanf(attrs[2], "i=%d", &iters)
+	require.NoError(t, err)
+
+	mac := func(key []byte, data string) []byte {
+		m := hmac.New(sha256.New, key)
+		_, _ = m.Write([]byte(data))
+		return m.Sum(nil)
+	}
+	// Hi(), i.e. single block PBKDF2-HMAC-SHA-256.
+	u := mac([]byte(password), string(salt)+"\x00\x00\x00\x01")
+	salted := append([]byte(nil), u...)
+	for i := 1; i < iters; i++ {
+		u = mac([]byte(password), string(u))
+		for j := range salted {
+			salted[j] ^= u[j]
+		}
+	}
+	clientKey := mac(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := clientFirstBare + "," + serverFirst + "," + withoutProof
+	clientSignature := mac(storedKey[:], authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range proof {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	serverSig := mac(mac(salted, "Server Key"), authMessage)
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof),
+		"v=" + base64.StdEncoding.EncodeToString(serverSig)
+}
+
+func TestSCRAMServer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	h, err := security.ParseSCRAMHash([]byte(rfc7677Hash))
+	require.NoError(t, err)
+
+	const clientFirstBare = "n=user,r=rOprNGfwEbeRWgbNEkqO"
+	exchange := func(password string) (string, string, error) {
+		s, err := security.NewSCRAMServer(h)
+		require.NoError(t, err)
+		serverFirst, err := s.ServerFirstMessage([]byte("n,," + clientFirstBare))
+		require.NoError(t, err)
+		require.Regexp(t, `^r=rOprNGfwEbeRWgbNEkqO[^,]+,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096$`,
+			string(serverFirst))
+		clientFinal, expectedSig := scramClientFinal(t, password, clientFirstBare, string(serverFirst))
+		serverFinal, err := s.ServerFinalMessage([]byte(clientFinal))
+		return string(serverFinal), expectedSig, err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		serverFinal, expectedSig, err := exchange("pencil")
+		require.NoError(t, err)
+		require.Equal(t, expectedSig, serverFinal)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, _, err := exchange("pencils")
+		require.Equal(t, security.ErrSCRAMAuthFailed, err)
+	})
+
+	t.Run("protocol errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			clientFirst string
+			clientFinal string
+			err         string
+		}{
+			{clientFirst: "p=tls-server-end-point,," + clientFirstBare, err: "channel binding is not supported"},
+			{clientFirst: "n,a=admin," + clientFirstBare, err: "authorization identities are not supported"},
+			{clientFirst: "n,,m=ext," + clientFirstBare, err: "mandatory extensions are not supported"},
+			{clientFirst: "n,,n=user", err: "malformed"},
+			{clientFirst: "n,,n=user,r=", err: "missing nonce"},
+			{clientFirst: "n,," + clientFirstBare, clientFinal: "c=biws,r=abc", err: "missing proof"},
+			{
+				clientFirst: "n,," + clientFirstBare,
+				clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqO,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+				err:         "nonce does not match",
+			},
+			{
+				clientFirst: "y,," + clientFirstBare,
+				clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqO,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+				err:         "channel binding does not match",
+			},
+		} {
+			s, err := security.NewSCRAMServer(h)
+			require.NoError(t, err)
+			_, err = s.ServerFirstMessage([]byte(tc.clientFirst))
+			if tc.clientFinal == "" {
+				require.Error(t, err, tc.clientFirst)
+				require.Contains(t, err.Error(), tc.err)
+				continue
+			}
+			require.NoError(t, err)
+			_, err = s.ServerFinalMessage([]byte(tc.clientFinal))
+			require.Error(t, err, tc.clientFinal)
+			require.Contains(t, err.Error(), tc.err)
+		}
+	})
+}
+
+func TestMakeMockSCRAMHash(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	secret := []byte("secret")
+	h1, err := security.MakeMockSCRAMHash("user", secret)
+	require.NoError(t, err)
+	h2, err := security.MakeMockSCRAMHash("user", secret)
+	require.NoError(t, err)
+	require.Equal(t, h1.Salt, h2.Salt)
+	require.Equal(t, security.ScramIterCount, h1.Iters)
+
+	other, err := security.MakeMockSCRAMHash("other", secret)
+	require.NoError(t, err)
+	require.NotEqual(t, h1.Salt, other.Salt)
+	other, err = security.MakeMockSCRAMHash("user", []byte("other secret"))
+	require.NoError(t, err)
+	require.NotEqual(t, h1.Salt, other.Salt)
+
+	// No password matches the mock verifier.
+	const clientFirstBare = "n=user,r=rOprNGfwEbeRWgbNEkqO"
+	s, err := security.NewSCRAMServer(h1)
+	require.NoError(t, err)
+	serverFirst, err := s.ServerFirstMessage([]byte("n,," + clientFirstBare))
+	require.NoError(t, err)
+	clientFinal, _ := scramClientFinal(t, "", clientFirstBare, string(serverFirst))
+	_, err = s.ServerFinalMessage([]byte(clientFinal))
+	require.Equal(t, security.ErrSCRAMAuthFailed, err)
+}
@@ -548,10 +548,28 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			if len(spec.RocksDBOptions) > 0 {
 				return nil, errors.Errorf("store %d: using Pebble storage engine but StoreSpec provides RocksDB options", i)
 			}
+			// The raft log, if configured to live in its own Pebble instance
+			// (possibly on a separate device), shares the block cache and
+			// options with the store's primary engine. The options are cloned
+			// before opening the primary engine, which mutates them.
+			raftConfig := pebbleConfig
+			raftConfig.Dir = spec.RaftLogPath
+			raftConfig.MaxSize = 0
+			raftConfig.Opts = pebbleConfig.Opts.Clone()
 			eng, err := storage.NewPebble(ctx, pebbleConfig)
 			if err != nil {
 				return Engines{}, err
 			}
+			if spec.RaftLogPath != "" {
+				raftEng, err := storage.NewPebble(ctx, raftConfig)
+				if err != nil {
+					eng.Close()
+					return Engines{}, errors.Wrapf(err, "store %d: opening raft log engine", i)
+				}
+				details = append(details, fmt.Sprintf("store %d: raft log stored at %s", i, spec.RaftLogPath))
+				engines = append(engines, storage.WithRaftEngine(eng, raftEng))
+				continue
+			}
 			engines = append(engines, eng)
 		}
 	}
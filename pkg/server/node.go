@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -169,6 +170,10 @@ type Node struct {
 	additionalStoreInitCh chan struct{}
 
 	perReplicaServer kvserver.Server
+
+	// kvAdmissionQ is the queue in which incoming batches wait until they are
+	// admitted. It is nil if the node doesn't use admission control.
+	kvAdmissionQ *admission.WorkQueue
 }
 
 var _ roachpb.InternalServer = &Node{}
@@ -284,20 +289,22 @@ func NewNode(
 	txnMetrics kvcoord.TxnMetrics,
 	execCfg *sql.ExecutorConfig,
 	clusterID *base.ClusterIDContainer,
+	kvAdmissionQ *admission.WorkQueue,
 ) *Node {
 	var sqlExec *sql.InternalExecutor
 	if execCfg != nil {
 		sqlExec = execCfg.InternalExecutor
 	}
 	n := &Node{
-		storeCfg:   cfg,
-		stopper:    stopper,
-		recorder:   recorder,
-		metrics:    makeNodeMetrics(reg, cfg.HistogramWindowInterval),
-		stores:     kvserver.NewStores(cfg.AmbientCtx, cfg.Clock),
-		txnMetrics: txnMetrics,
-		sqlExec:    sqlExec,
-		clusterID:  clusterID,
+		storeCfg:     cfg,
+		stopper:      stopper,
+		recorder:     recorder,
+		metrics:      makeNodeMetrics(reg, cfg.HistogramWindowInterval),
+		stores:       kvserver.NewStores(cfg.AmbientCtx, cfg.Clock),
+		txnMetrics:   txnMetrics,
+		sqlExec:      sqlExec,
+		clusterID:    clusterID,
+		kvAdmissionQ: kvAdmissionQ,
 	}
 	n.perReplicaServer = kvserver.MakeServer(&n.Descriptor, n.stores)
 	return n
@@ -853,6 +860,17 @@ func (n *Node) batchInternal(
 			log.Eventf(ctx, "node received request: %s", args.Summary())
 		}
 
+		if n.kvAdmissionQ != nil {
+			info := admissionInfoForBatch(ctx, args)
+			enabled, err := n.kvAdmissionQ.Admit(ctx, info)
+			if err != nil {
+				return err
+			}
+			if enabled {
+				defer n.kvAdmissionQ.AdmittedWorkDone(info.TenantID)
+			}
+		}
+
 		tStart := timeutil.Now()
 		var pErr *roachpb.Error
 		br, pErr = n.stores.Send(ctx, *args)
@@ -872,6 +890,38 @@ func (n *Node) batchInternal(
 	return br, nil
 }
 
+// admissionInfoForBatch returns the information used to order the batch in
+// the KV admission queue.
+func admissionInfoForBatch(ctx context.Context, ba *roachpb.BatchRequest) admission.WorkInfo {
+	info := admission.WorkInfo{
+		TenantID: roachpb.SystemTenantID,
+		Priority: admission.NormalPri,
+	}
+	if tenantID, ok := roachpb.TenantFromContext(ctx); ok {
+		info.TenantID = tenantID
+	}
+	if ba.Txn != nil {
+		// Batches of older transactions are admitted first.
+		info.CreateTime = ba.Txn.MinTimestamp.WallTime
+	} else {
+		info.CreateTime = timeutil.Now().UnixNano()
+	}
+	if up := ba.UserPriority; up != roachpb.UnspecifiedUserPriority {
+		if up < roachpb.NormalUserPriority {
+			info.Priority = admission.LowPri
+		} else if up > roachpb.NormalUserPriority {
+			info.Priority = admission.HighPri
+		}
+	}
+	// Requests to the system ranges, which include node liveness heartbeats
+	// and meta range lookups, must not wait behind user work, or overload
+	// would cause the node to lose its liveness.
+	if rs, err := keys.Range(ba.Requests); err == nil && rs.Key.Less(roachpb.RKey(keys.SystemMax)) {
+		info.BypassAdmission = true
+	}
+	return info
+}
+
 // Batch implements the roachpb.InternalServer interface.
 func (n *Node) Batch(
 	ctx context.Context, args *roachpb.BatchRequest,
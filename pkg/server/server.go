@@ -69,6 +69,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
@@ -156,6 +157,10 @@ type Server struct {
 	protectedtsProvider   protectedts.Provider
 	protectedtsReconciler *ptreconcile.Reconciler
 
+	// admissionCoordinator coordinates the admission of KV work and DistSQL
+	// flow setups on this node.
+	admissionCoordinator *admission.GrantCoordinator
+
 	sqlServer *sqlServer
 
 	// Created in NewServer but initialized (made usable) in `(*Server).Start`.
@@ -548,9 +553,15 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	recorder := status.NewMetricsRecorder(clock, nodeLiveness, rpcContext, g, st)
 	registry.AddMetricStruct(rpcContext.RemoteClocks.Metrics())
 
+	admissionCoordinator := admission.NewGrantCoordinator(st)
+	for _, metricStruct := range admissionCoordinator.MetricStructs() {
+		registry.AddMetricStruct(metricStruct)
+	}
+
 	node := NewNode(
 		storeCfg, recorder, registry, stopper,
-		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID)
+		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID,
+		admissionCoordinator.GetWorkQueue(admission.KVWork))
 	lateBoundNode = node
 	roachpb.RegisterInternalServer(grpcServer.Server, node)
 	kvserver.RegisterPerReplicaServer(grpcServer.Server, node.perReplicaServer)
@@ -618,6 +629,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			externalStorage:        externalStorage,
 			externalStorageFromURI: externalStorageFromURI,
 			isMeta1Leaseholder:     node.stores.IsMeta1Leaseholder,
+			sqlLeafStartWorkQueue:  admissionCoordinator.GetWorkQueue(admission.SQLStatementLeafStartWork),
 		},
 		SQLConfig:                &cfg.SQLConfig,
 		BaseConfig:               &cfg.BaseConfig,
@@ -676,6 +688,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		replicationReporter:    replicationReporter,
 		protectedtsProvider:    protectedtsProvider,
 		protectedtsReconciler:  protectedtsReconciler,
+		admissionCoordinator:   admissionCoordinator,
 		sqlServer:              sqlServer,
 		externalStorageBuilder: externalStorageBuilder,
 	}
@@ -1532,6 +1545,12 @@ func (s *Server) PreStart(ctx context.Context) error {
 	}
 	s.replicationReporter.Start(ctx, s.stopper)
 
+	// Start adjusting admission control to the load of the node, now that
+	// the stores whose metrics it watches are running.
+	if err := s.admissionCoordinator.Start(ctx, s.stopper, s.node.stores); err != nil {
+		return err
+	}
+
 	sentry.ConfigureScope(func(scope *sentry.Scope) {
 		scope.SetTags(map[string]string{
 			"cluster":         s.ClusterID().String(),
@@ -1586,6 +1586,7 @@ func (s *statusServer) Ranges(
 				NoLease:                metrics.Leader && !metrics.LeaseValid && !metrics.Quiescent,
 				QuiescentEqualsTicking: raftStatus != nil && metrics.Quiescent == metrics.Ticking,
 				RaftLogTooLarge:        metrics.RaftLogTooLarge,
+				CircuitBreakerError:    metrics.CircuitBreakerError,
 			},
 			LatchesLocal:  metrics.LatchInfoLocal,
 			LatchesGlobal: metrics.LatchInfoGlobal,
@@ -2104,6 +2105,10 @@ func (s *statusServer) Stores(
 		storeDetails.TotalBytes = envStats.TotalBytes
 		storeDetails.ActiveKeyFiles = envStats.ActiveKeyFiles
 		storeDetails.ActiveKeyBytes = envStats.ActiveKeyBytes
+		for _, u := range envStats.DataKeyUsage {
+			storeDetails.DataKeyUsage = append(storeDetails.DataKeyUsage,
+				serverpb.StoreDetails_DataKeyUsage{KeyID: u.KeyID, Files: u.Files, Bytes: u.Bytes})
+		}
 
 		resp.Stores = append(resp.Stores, storeDetails)
 
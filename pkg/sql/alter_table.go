@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -267,6 +268,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 					return err
 				}
 
+			case *tree.ExclusionConstraintTableDef:
+				// Adding an exclusion constraint would require building its
+				// backing index and validating the existing rows against it.
+				return unimplemented.NewWithIssue(46657,
+					"adding exclusion constraints to existing tables is not supported",
+				)
+
 			case *tree.ForeignKeyConstraintTableDef:
 				for _, colName := range d.FromCols {
 					col, err := n.tableDesc.FindActiveOrNewColumnByName(colName)
@@ -532,6 +540,15 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			n.tableDesc.UniqueWithoutIndexConstraints = n.tableDesc.UniqueWithoutIndexConstraints[:sliceIdx]
 
+			// Drop exclusion constraints that reference the column.
+			validExclusions := n.tableDesc.ExclusionConstraints[:0]
+			for _, constraint := range n.tableDesc.ExclusionConstraints {
+				if !descpb.ColumnIDs(constraint.ColumnIDs).Contains(colToDrop.ID) {
+					validExclusions = append(validExclusions, constraint)
+				}
+			}
+			n.tableDesc.ExclusionConstraints = validExclusions
+
 			// Drop check constraints which reference the column.
 			validChecks := n.tableDesc.Checks[:0]
 			for _, check := range n.tableDesc.AllActiveAndInactiveChecks() {
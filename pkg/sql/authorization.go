@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -595,6 +596,8 @@ func (p *planner) checkCanAlterToNewOwner(
 		objType = "schema"
 	case *dbdesc.Mutable:
 		objType = "database"
+	case *funcdesc.Mutable:
+		objType = "function"
 	default:
 		return errors.AssertionFailedf("unknown object descriptor type %v", desc)
 	}
@@ -95,6 +95,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -119,4 +121,75 @@ type ConstraintDetail struct {
 
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
+
+	// Only populated for Exclusion Constraints.
+	ExclusionConstraint *ExclusionConstraint
+}
+
+// Deferrable returns true if the checks of the constraint can be deferred
+// until the end of the transaction.
+func (c *ConstraintDetail) Deferrable() bool {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrable
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrable
+	}
+	return false
+}
+
+// InitiallyDeferred returns true if the checks of the constraint are deferred
+// until the end of the transaction by default.
+func (c *ConstraintDetail) InitiallyDeferred() bool {
+	switch {
+	case c.FK != nil:
+		return c.FK.InitiallyDeferred
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.InitiallyDeferred
+	}
+	return false
+}
+
+// ExclusionConstraintOperators maps the string representation of the
+// comparison operators which can be used in exclusion constraints, as stored in
+// ExclusionConstraint.Operators, to the operators themselves.
+var ExclusionConstraintOperators = map[string]tree.ComparisonOperator{
+	tree.EQ.String():       tree.EQ,
+	tree.Overlaps.String(): tree.Overlaps,
+}
+
+// Operator returns the comparison operator applied to the i-th column of the
+// exclusion constraint.
+func (c *ExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return ExclusionConstraintOperators[c.Operators[i]]
+}
+
+// CanBeBackedBy returns true if the given index can be used to look up the
+// rows which may conflict with a row under the exclusion constraint. If the
+// constraint compares any column with &&, the index must be an inverted index
+// on one of those columns. Otherwise, it must be a forward index whose first
+// column is one of the constraint's columns. Partial indexes cannot back an
+// exclusion constraint, since they do not contain all the rows of the table.
+func (c *ExclusionConstraint) CanBeBackedBy(idx *IndexDescriptor) bool {
+	if idx.IsPartial() || len(idx.ColumnIDs) == 0 {
+		return false
+	}
+	hasOverlaps := false
+	for i := range c.ColumnIDs {
+		if c.Operator(i) == tree.Overlaps {
+			hasOverlaps = true
+			break
+		}
+	}
+	for i, colID := range c.ColumnIDs {
+		if hasOverlaps {
+			if c.Operator(i) == tree.Overlaps && idx.Type == IndexDescriptor_INVERTED &&
+				len(idx.ColumnIDs) == 1 && idx.ColumnIDs[0] == colID {
+				return true
+			}
+		} else if idx.Type == IndexDescriptor_FORWARD && idx.ColumnIDs[0] == colID {
+			return true
+		}
+	}
+	return false
 }
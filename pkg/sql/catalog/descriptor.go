@@ -96,6 +96,14 @@ type SchemaDescriptor interface {
 	SchemaDesc() *descpb.SchemaDescriptor
 }
 
+// FunctionDescriptor will eventually be called funcdesc.Descriptor.
+// It is implemented by Immutable.
+type FunctionDescriptor interface {
+	Descriptor
+	FuncDesc() *descpb.FunctionDescriptor
+	Validate() error
+}
+
 // TableDescriptor is an interface around the table descriptor types.
 type TableDescriptor interface {
 	Descriptor
@@ -174,6 +182,8 @@ type TableDescriptor interface {
 	AllActiveAndInactiveChecks() []*descpb.TableDescriptor_CheckConstraint
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 	AllActiveAndInactiveUniqueWithoutIndexConstraints() []*descpb.UniqueWithoutIndexConstraint
+	GetExclusionConstraints() []descpb.ExclusionConstraint
+	GetTriggers() []descpb.TriggerDescriptor
 	ForeachInboundFK(f func(fk *descpb.ForeignKeyConstraint) error) error
 	FindActiveColumnByName(s string) (*descpb.ColumnDescriptor, error)
 	WritableColumns() []descpb.ColumnDescriptor
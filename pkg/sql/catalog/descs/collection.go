@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/hydratedtables"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
@@ -1151,6 +1152,46 @@ func (tc *Collection) GetTypeVersionByID(
 	return typ, nil
 }
 
+// User defined function accessors.
+
+// GetMutableFunctionVersionByID is the equivalent of
+// GetMutableTypeVersionByID but for accessing functions.
+func (tc *Collection) GetMutableFunctionVersionByID(
+	ctx context.Context, txn *kv.Txn, fnID descpb.ID,
+) (*funcdesc.Mutable, error) {
+	desc, err := tc.GetMutableDescriptorByID(ctx, fnID, txn)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	fn, ok := desc.(*funcdesc.Mutable)
+	if !ok {
+		return nil, pgerror.Newf(
+			pgcode.UndefinedFunction, "function with ID %d does not exist", fnID)
+	}
+	return fn, nil
+}
+
+// GetFunctionVersionByID is the equivalent of GetTypeVersionByID but for
+// accessing functions.
+func (tc *Collection) GetFunctionVersionByID(
+	ctx context.Context, txn *kv.Txn, fnID descpb.ID, flags tree.ObjectLookupFlags,
+) (*funcdesc.Immutable, error) {
+	desc, err := tc.getDescriptorVersionByID(ctx, txn, fnID, flags.CommonLookupFlags, true /* setTxnDeadline */)
+	if err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil, pgerror.Newf(
+				pgcode.UndefinedFunction, "function with ID %d does not exist", fnID)
+		}
+		return nil, err
+	}
+	fn, ok := desc.(*funcdesc.Immutable)
+	if !ok {
+		return nil, pgerror.Newf(
+			pgcode.UndefinedFunction, "function with ID %d does not exist", fnID)
+	}
+	return fn, nil
+}
+
 // getUncommittedDescriptor returns a descriptor for the requested name
 // if the requested name is for a descriptor modified within the transaction
 // affiliated with the Collection.
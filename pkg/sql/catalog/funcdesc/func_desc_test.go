@@ -0,0 +1,121 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSafeMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		desc catalog.FunctionDescriptor
+		exp  string
+	}{
+		{
+			desc: funcdesc.NewImmutable(descpb.FunctionDescriptor{
+				ID:             52,
+				Version:        1,
+				ParentID:       50,
+				ParentSchemaID: 29,
+				State:          descpb.DescriptorState_OFFLINE,
+				OfflineReason:  "foo",
+			}),
+			exp: "funcdesc.Immutable: {ID: 52, Version: 1, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 29, State: OFFLINE, OfflineReason: \"foo\"}",
+		},
+		{
+			desc: funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{
+				ID:             53,
+				Version:        1,
+				ParentID:       50,
+				ParentSchemaID: 29,
+				State:          descpb.DescriptorState_PUBLIC,
+			}),
+			exp: "funcdesc.Mutable: {ID: 53, Version: 1, IsUncommitted: true, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 29, State: PUBLIC}",
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			redacted := string(redact.Sprint(tc.desc).Redact())
+			require.Equal(t, tc.exp, redacted)
+			{
+				var m map[string]interface{}
+				require.NoError(t, yaml.UnmarshalStrict([]byte(redacted), &m))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	makeDesc := func() descpb.FunctionDescriptor {
+		return descpb.FunctionDescriptor{
+			Name:           "f",
+			ID:             52,
+			ParentID:       50,
+			ParentSchemaID: 29,
+			Args:           []descpb.FunctionDescriptor_Argument{{Name: "a", Type: types.Int}},
+			ReturnType:     types.Int,
+			Body:           "SELECT a + 1",
+			Privileges:     funcdesc.NewDefaultPrivilegeDescriptor(security.RootUserName()),
+		}
+	}
+	for _, tc := range []struct {
+		mutate func(desc *descpb.FunctionDescriptor)
+		err    string
+	}{
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) {},
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.Name = "" },
+			err:    "empty function name",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.ParentSchemaID = 0 },
+			err:    "invalid parent schema ID 0 for function 52",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.ReturnType = nil },
+			err:    "missing return type for function 52",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.Args[0].Type = nil },
+			err:    "missing type for argument 1 of function 52",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.Body = "" },
+			err:    "missing body for function 52",
+		},
+	} {
+		t.Run(tc.err, func(t *testing.T) {
+			desc := makeDesc()
+			tc.mutate(&desc)
+			err := funcdesc.NewImmutable(desc).Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -44,6 +44,8 @@ func ShouldSplitAtDesc(rawDesc *roachpb.Value) bool {
 		return false
 	case *descpb.Descriptor_Schema:
 		return false
+	case *descpb.Descriptor_Function:
+		return false
 	default:
 		panic(errors.AssertionFailedf("unexpected descriptor type %#v", &desc))
 	}
@@ -345,6 +347,17 @@ CREATE TABLE system.sqlliveness (
     expiration       DECIMAL NOT NULL,
   	FAMILY fam0_session_id_expiration (session_id, expiration)
 )`
+
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+    id         INT8 DEFAULT unique_rowid() PRIMARY KEY NOT NULL,
+    channel    STRING NOT NULL,
+    payload    STRING NOT NULL,
+    node_id    INT8 NOT NULL,
+    created    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    INDEX (created),
+    FAMILY "primary" (id, channel, payload, node_id, created)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1690,6 +1703,53 @@ var (
 		FormatVersion:  descpb.InterleavedFormatVersion,
 		NextMutationID: 1,
 	})
+
+	// NotificationsTable is the descriptor for the notifications table, which
+	// stores the notifications sent with NOTIFY until they are delivered to
+	// the listening sessions of every node.
+	NotificationsTable = tabledesc.NewImmutable(descpb.TableDescriptor{
+		Name:                    "notifications",
+		ID:                      keys.NotificationsTableID,
+		ParentID:                keys.SystemDatabaseID,
+		UnexposedParentSchemaID: keys.PublicSchemaID,
+		Version:                 1,
+		Columns: []descpb.ColumnDescriptor{
+			{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString, Nullable: false},
+			{Name: "channel", ID: 2, Type: types.String, Nullable: false},
+			{Name: "payload", ID: 3, Type: types.String, Nullable: false},
+			{Name: "node_id", ID: 4, Type: types.Int, Nullable: false},
+			{Name: "created", ID: 5, Type: types.TimestampTZ, DefaultExpr: &nowTZString, Nullable: false},
+		},
+		NextColumnID: 6,
+		Families: []descpb.ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ColumnNames: []string{"id", "channel", "payload", "node_id", "created"},
+				ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: pk("id"),
+		// The index on created is used to delete the notifications which are
+		// older than the retention period.
+		Indexes: []descpb.IndexDescriptor{
+			{
+				Name:             "notifications_created_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"created"},
+				ColumnDirections: []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC},
+				ColumnIDs:        []descpb.ColumnID{5},
+				ExtraColumnIDs:   []descpb.ColumnID{1},
+				Version:          descpb.EmptyArraysInInvertedIndexesVersion,
+			},
+		},
+		NextIndexID: 3,
+		Privileges: descpb.NewCustomSuperuserPrivilegeDescriptor(
+			descpb.SystemAllowedPrivileges[keys.NotificationsTableID], security.NodeUserName()),
+		FormatVersion:  descpb.InterleavedFormatVersion,
+		NextMutationID: 1,
+	})
 )
 
 // newCommentPrivilegeDescriptor returns a privilege descriptor for comment table
@@ -176,6 +176,7 @@ func formatSafeTableConstraints(w *redact.StringBuilder, desc catalog.TableDescr
 	td := desc.TableDesc()
 	formatSafeTableChecks(w, td.Checks)
 	formatSafeTableUniqueWithoutIndexConstraints(w, td.UniqueWithoutIndexConstraints)
+	formatSafeTableExclusionConstraints(w, td.ExclusionConstraints)
 	formatSafeTableFKs(w, "InboundFKs", td.InboundFKs)
 	formatSafeTableFKs(w, "OutboundFKs", td.OutboundFKs)
 }
@@ -246,6 +247,23 @@ func formatSafeTableUniqueWithoutIndexConstraints(
 	}
 }
 
+func formatSafeTableExclusionConstraints(
+	w *redact.StringBuilder, constraints []descpb.ExclusionConstraint,
+) {
+	for i := range constraints {
+		c := &constraints[i]
+		if i == 0 {
+			w.Printf(", Exclusion Constraints: [")
+		} else {
+			w.Printf(", ")
+		}
+		formatSafeExclusionConstraint(w, c)
+	}
+	if len(constraints) > 0 {
+		w.Printf("]")
+	}
+}
+
 func formatSafeTableColumnFamilies(w *redact.StringBuilder, desc catalog.TableDescriptor) {
 	td := desc.TableDesc()
 	w.Printf(", NextFamilyID: %d", td.NextFamilyID)
@@ -386,6 +404,22 @@ func formatSafeUniqueWithoutIndexConstraint(
 	w.Printf("}")
 }
 
+func formatSafeExclusionConstraint(w *redact.StringBuilder, c *descpb.ExclusionConstraint) {
+	w.Printf("{TableID: %d", c.TableID)
+	w.Printf(", Columns: ")
+	formatSafeColumnIDs(w, c.ColumnIDs)
+	w.Printf(", Operators: [")
+	for i, op := range c.Operators {
+		if i > 0 {
+			w.Printf(", ")
+		}
+		w.Printf("%s", redact.SafeString(op))
+	}
+	w.Printf("]")
+	w.Printf(", Validity: %s", c.Validity.String())
+	w.Printf("}")
+}
+
 func formatSafeColumnIDs(w *redact.StringBuilder, colIDs []descpb.ColumnID) {
 	w.Printf("[")
 	for i, colID := range colIDs {
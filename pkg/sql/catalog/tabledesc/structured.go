@@ -2016,6 +2016,14 @@ func (desc *Immutable) ValidateTable(ctx context.Context) error {
 			return err
 		}
 
+		if err := desc.validateExclusionConstraints(columnIDs); err != nil {
+			return err
+		}
+
+		if err := desc.validateTriggers(); err != nil {
+			return err
+		}
+
 		if err := desc.validateTableIndexes(columnNames); err != nil {
 			return err
 		}
@@ -2297,6 +2305,79 @@ func (desc *Immutable) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that exclusion constraints are well
+// formed. Checks include validating the column IDs and the operators.
+func (desc *Immutable) validateExclusionConstraints(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	for i := range desc.ExclusionConstraints {
+		c := &desc.ExclusionConstraints[i]
+		if err := catalog.ValidateName(c.Name, "exclusion constraint"); err != nil {
+			return err
+		}
+
+		// Verify that the table ID is valid.
+		if c.TableID != desc.ID {
+			return fmt.Errorf(
+				"TableID mismatch for exclusion constraint %q: \"%d\" doesn't match descriptor: \"%d\"",
+				c.Name, c.TableID, desc.ID,
+			)
+		}
+
+		if len(c.ColumnIDs) == 0 {
+			return fmt.Errorf("exclusion constraint %q has no columns", c.Name)
+		}
+		if len(c.ColumnIDs) != len(c.Operators) {
+			return fmt.Errorf(
+				"exclusion constraint %q has %d columns but %d operators",
+				c.Name, len(c.ColumnIDs), len(c.Operators),
+			)
+		}
+
+		// Verify that the constraint's column IDs and operators are valid.
+		for j, colID := range c.ColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return fmt.Errorf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.Name, colID,
+				)
+			}
+			if _, ok := descpb.ExclusionConstraintOperators[c.Operators[j]]; !ok {
+				return fmt.Errorf(
+					"exclusion constraint %q contains invalid operator %q", c.Name, c.Operators[j],
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTriggers validates that triggers are well formed. Checks include
+// validating the trigger names and verifying that each trigger has at least
+// one event and a body.
+func (desc *Immutable) validateTriggers() error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		if err := catalog.ValidateName(t.Name, "trigger"); err != nil {
+			return err
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("duplicate trigger name: %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+
+		if !t.OnInsert && !t.OnUpdate && !t.OnDelete {
+			return fmt.Errorf("trigger %q has no events", t.Name)
+		}
+		if t.Body == "" {
+			return fmt.Errorf("trigger %q has no body", t.Name)
+		}
+	}
+
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -2700,6 +2781,29 @@ func (desc *Mutable) AddColumn(col *descpb.ColumnDescriptor) {
 	desc.Columns = append(desc.Columns, *col)
 }
 
+// AddFunctionReference records that the user-defined function with the given
+// ID depends on the table. It has no effect if the ID is already present.
+func (desc *Mutable) AddFunctionReference(new descpb.ID) {
+	for _, id := range desc.DependedOnByFunctions {
+		if new == id {
+			return
+		}
+	}
+	desc.DependedOnByFunctions = append(desc.DependedOnByFunctions, new)
+}
+
+// RemoveFunctionReference removes the given user-defined function ID from
+// the functions which depend on the table. It has no effect if the ID is not
+// present.
+func (desc *Mutable) RemoveFunctionReference(remove descpb.ID) {
+	for i, id := range desc.DependedOnByFunctions {
+		if id == remove {
+			desc.DependedOnByFunctions = append(desc.DependedOnByFunctions[:i], desc.DependedOnByFunctions[i+1:]...)
+			return
+		}
+	}
+}
+
 // AddFamily adds a family to the table.
 func (desc *Mutable) AddFamily(fam descpb.ColumnFamilyDescriptor) {
 	desc.Families = append(desc.Families, fam)
@@ -3212,6 +3316,21 @@ func (desc *Mutable) DropConstraint(
 		}
 		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
 
+	case descpb.ConstraintTypeExclusion:
+		// Exclusion constraints only restrict the writes to the table, so they can
+		// be dropped immediately: nodes which still use a previous version of the
+		// descriptor continue to enforce the constraint until their lease expires,
+		// which is more restrictive than necessary but harmless.
+		for i := range desc.ExclusionConstraints {
+			if desc.ExclusionConstraints[i].Name == name {
+				desc.ExclusionConstraints = append(
+					desc.ExclusionConstraints[:i], desc.ExclusionConstraints[i+1:]...,
+				)
+				return nil
+			}
+		}
+		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("drop-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(name))
@@ -3291,6 +3410,10 @@ func (desc *Mutable) RenameConstraint(
 		detail.CheckConstraint.Name = newName
 		return nil
 
+	case descpb.ConstraintTypeExclusion:
+		detail.ExclusionConstraint.Name = newName
+		return nil
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(oldName))
@@ -329,6 +329,23 @@ func (desc *Immutable) collectConstraintInfo(
 		}
 		info[c.Name] = detail
 	}
+
+	for i := range desc.ExclusionConstraints {
+		c := &desc.ExclusionConstraints[i]
+		if _, ok := info[c.Name]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate constraint name: %q", c.Name)
+		}
+		detail := descpb.ConstraintDetail{Kind: descpb.ConstraintTypeExclusion}
+		detail.Unvalidated = c.Validity != descpb.ConstraintValidity_Validated
+		var err error
+		detail.Columns, err = desc.NamesForColumnIDs(c.ColumnIDs)
+		if err != nil {
+			return nil, err
+		}
+		detail.ExclusionConstraint = c
+		info[c.Name] = detail
+	}
 	return info, nil
 }
 
@@ -420,6 +437,25 @@ func FindFKOriginIndexInTxn(
 	)
 }
 
+// FindExclusionConstraintIndex finds the first index in the supplied table
+// that can back the supplied exclusion constraint, or returns nil if there is
+// none. See descpb.ExclusionConstraint.CanBeBackedBy.
+func FindExclusionConstraintIndex(
+	desc catalog.TableDescriptor, c *descpb.ExclusionConstraint,
+) *descpb.IndexDescriptor {
+	if primaryIndex := desc.GetPrimaryIndex(); c.CanBeBackedBy(primaryIndex) {
+		return primaryIndex
+	}
+	indexes := desc.GetPublicNonPrimaryIndexes()
+	for i := range indexes {
+		idx := &indexes[i]
+		if c.CanBeBackedBy(idx) {
+			return idx
+		}
+	}
+	return nil
+}
+
 // InitTableDescriptor returns a blank TableDescriptor.
 func InitTableDescriptor(
 	id, parentID, parentSchemaID descpb.ID,
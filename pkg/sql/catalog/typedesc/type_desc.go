@@ -566,20 +566,23 @@ func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) erro
 		return errors.New("unknown type descriptor type")
 	}
 
-	// Validate that all of the referencing descriptors exist.
-	tableExists := func(id descpb.ID) func(got catalog.Descriptor) error {
+	// Validate that all of the referencing descriptors exist. Types may be
+	// referenced by tables and by the signatures of user-defined functions.
+	referenceExists := func(id descpb.ID) func(got catalog.Descriptor) error {
 		return func(got catalog.Descriptor) error {
-			if _, isTable := got.(catalog.TableDescriptor); !isTable {
+			switch got.(type) {
+			case catalog.TableDescriptor, catalog.FunctionDescriptor:
+				return nil
+			default:
 				return errors.AssertionFailedf("referencing descriptor %d does not exist", id)
 			}
-			return nil
 		}
 	}
 	if !desc.Dropped() {
 
 		for _, id := range desc.ReferencingDescriptorIDs {
 			reqs = append(reqs, id)
-			checks = append(checks, tableExists(id))
+			checks = append(checks, referenceExists(id))
 		}
 	}
 
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -426,6 +427,13 @@ func (s *Server) SetupConn(
 		ctx, sd, args.SessionDefaults, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.getStatsForApplication(sd.ApplicationName),
 	)
+	if s.cfg.NotificationRegistry != nil {
+		ex.notifications = s.cfg.NotificationRegistry.NewListener(func(ctx context.Context) {
+			// Push only fails if the buffer was closed, in which case the session
+			// is finishing and the notifications don't matter anymore.
+			_ = stmtBuf.Push(ctx, DeliverNotifications{})
+		})
+	}
 	return ConnectionHandler{ex}, nil
 }
 
@@ -870,6 +878,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notifications != nil {
+		ex.notifications.Close()
+	}
+
 	// Stop idle timer if the connExecutor is closed to ensure cancel session
 	// is not called.
 	ex.mu.IdleInSessionTimeout.Stop()
@@ -954,6 +966,15 @@ type connExecutor struct {
 		// queued up for the given ID.
 		schemaChangeJobsCache map[descpb.ID]*jobs.Job
 
+		// deferredConstraints tracks the checking modes of deferrable constraints
+		// set with SET CONSTRAINTS and the constraints that need to be validated
+		// before the transaction commits.
+		deferredConstraints deferredConstraintState
+		// deferredConstraintsAtTxnRewindPos is a snapshot of deferredConstraints
+		// before processing the command at position txnRewindPos. When rewinding,
+		// we're going to restore this snapshot.
+		deferredConstraintsAtTxnRewindPos deferredConstraintState
+
 		// autoRetryCounter keeps track of the which iteration of a transaction
 		// auto-retry we're currently in. It's 0 whenever the transaction state is not
 		// stateOpen.
@@ -1112,6 +1133,11 @@ type connExecutor struct {
 	// going to find a suitable time to close the connection.
 	draining bool
 
+	// notifications receives the notifications sent to the channels the
+	// session listens on. It is nil for internal executors, which don't
+	// support LISTEN.
+	notifications *notify.Listener
+
 	// executorType is set to whether this executor is an ordinary executor which
 	// responds to user queries or an internal one.
 	executorType executorType
@@ -1235,6 +1261,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
+		ex.extraTxnState.deferredConstraints.reset()
 		// After txn is finished, we need to call onTxnFinish (if it's non-nil).
 		if ex.extraTxnState.onTxnFinish != nil {
 			ex.extraTxnState.onTxnFinish(ev)
@@ -1559,6 +1586,11 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 				return errDrainingComplete
 			}
 		}
+		if ex.notifications != nil && ex.idleConn() && ex.notifications.HasPending() {
+			// Notifications were received while the session was in a transaction;
+			// deliver them now that the transaction is over.
+			_ = ex.stmtBuf.Push(ctx, DeliverNotifications{})
+		}
 	case CopyIn:
 		res = ex.clientComm.CreateCopyInResult(pos)
 		var err error
@@ -1579,6 +1611,17 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// Like Postgres, we only deliver notifications between transactions. If
+		// we are in a transaction, they'll be delivered once a Sync command is
+		// processed outside of a transaction.
+		notifRes := ex.clientComm.CreateNotificationResult(pos)
+		res = notifRes
+		if ex.notifications != nil && ex.idleConn() {
+			for _, n := range ex.notifications.TakePending() {
+				notifRes.BufferNotification(n)
+			}
+		}
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -1640,6 +1683,7 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 	case rewind:
 		ex.rewindPrepStmtNamespace(ctx)
 		ex.extraTxnState.savepoints = ex.extraTxnState.savepointsAtTxnRewindPos
+		ex.extraTxnState.deferredConstraints = ex.extraTxnState.deferredConstraintsAtTxnRewindPos.clone()
 		advInfo.rewCap.rewindAndUnlock(ctx)
 	case stayInPlace:
 		// Nothing to do. The same statement will be executed again.
@@ -1758,6 +1802,8 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -1782,6 +1828,7 @@ func (ex *connExecutor) setTxnRewindPos(ctx context.Context, pos CmdPos) {
 	ex.stmtBuf.ltrim(ctx, pos)
 	ex.commitPrepStmtNamespace(ctx)
 	ex.extraTxnState.savepointsAtTxnRewindPos = ex.extraTxnState.savepoints.clone()
+	ex.extraTxnState.deferredConstraintsAtTxnRewindPos = ex.extraTxnState.deferredConstraints.clone()
 }
 
 // stmtDoesntNeedRetry returns true if the given statement does not need to be
@@ -2161,6 +2208,14 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	evalCtx.Mon = ex.state.mon
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
+	// Statements run by internal executors on behalf of another transaction
+	// never defer constraint checks, since that transaction doesn't know about
+	// this executor's pending constraints.
+	evalCtx.DeferredConstraints = nil
+	if ex.executorType == executorTypeExec {
+		evalCtx.DeferredConstraints = &ex.extraTxnState.deferredConstraints
+	}
+	evalCtx.Notifications = ex.notifications
 }
 
 // getTransactionState retrieves a text representation of the given state.
@@ -736,6 +736,13 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	if err := validateDeferredConstraints(
+		ctx, ex.extraTxnState.deferredConstraints.takePending(nil /* filter */),
+		ex.server.cfg.InternalExecutor, ex.state.mu.txn, ex.server.cfg.Codec,
+	); err != nil {
+		return err
+	}
+
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return err
 	}
@@ -1001,6 +1008,13 @@ func (ex *connExecutor) execWithDistSQLEngine(
 			return recv.stats, recv.commErr
 		}
 	}
+	if len(planner.curPlan.cascades) != 0 {
+		if !ex.server.cfg.DistSQLPlanner.PlanAndRunBeforeTriggers(
+			ctx, planner, evalCtxFactory, &planner.curPlan.planComponents, recv,
+		) {
+			return recv.stats, recv.commErr
+		}
+	}
 	recv.discardRows = planner.instrumentation.ShouldDiscardRows()
 	// We pass in whether or not we wanted to distribute this plan, which tells
 	// the planner whether or not to plan remote table readers.
@@ -105,6 +105,8 @@ func (ex *connExecutor) execSavepointInOpenState(
 		commitOnRelease: commitOnRelease,
 		kvToken:         token,
 		numDDL:          ex.extraTxnState.numDDL,
+
+		deferredConstraints: ex.extraTxnState.deferredConstraints.clone(),
 	}
 	savepoints.push(sp)
 
@@ -189,6 +191,7 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 	}
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.clone()
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -267,6 +270,7 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.deferredConstraints = entry.deferredConstraints.clone()
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -312,6 +316,11 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// deferredConstraints is a snapshot of the state of deferrable constraints
+	// at the time the savepoint was created, restored when rolling back to the
+	// savepoint.
+	deferredConstraints deferredConstraintState
 }
 
 type savepointStack []savepoint
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
@@ -346,6 +347,21 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// DeliverNotifications is pushed when notifications sent with NOTIFY to a
+// channel the session listens on become pending. The notifications are
+// delivered to the client if the session is not in a transaction; otherwise
+// they are delivered once the transaction finishes.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -618,6 +634,9 @@ type ClientComm interface {
 	CreateCopyInResult(pos CmdPos) CopyInResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateNotificationResult creates a result for a DeliverNotifications
+	// command.
+	CreateNotificationResult(pos CmdPos) NotificationResult
 
 	// lockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -787,6 +806,16 @@ type DrainResult interface {
 	ResultBase
 }
 
+// NotificationResult represents the result of a DeliverNotifications command.
+// Closing this result sends the buffered notifications to the client and
+// flushes them.
+type NotificationResult interface {
+	ResultBase
+
+	// BufferNotification buffers a notification to be sent to the client.
+	BufferNotification(n notify.Notification)
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
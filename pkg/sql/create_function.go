@@ -0,0 +1,410 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// createFunctionNode represents a CREATE FUNCTION statement.
+type createFunctionNode struct {
+	// n is the CREATE FUNCTION statement, with the function name fully
+	// qualified and all table names in the body fully qualified.
+	n      *tree.CreateFunction
+	dbDesc *dbdesc.Immutable
+	schema catalog.ResolvedSchema
+
+	// planDeps tracks which tables, views and sequences the function body
+	// depends on. This is collected during the construction of the logical
+	// plan of the body.
+	planDeps planDependencies
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.UserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"creating functions requires all nodes to be upgraded to %s",
+			clusterversion.ByKey(clusterversion.UserDefinedFunctions))
+	}
+	if n.n.Replace {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("or_replace_function"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+	}
+
+	funcName := n.n.FuncName.Object()
+	log.VEventf(params.ctx, 2, "dependencies for function %s:\n%s", funcName, n.planDeps.String())
+
+	switch n.schema.Kind {
+	case catalog.SchemaTemporary:
+		return unimplemented.NewWithIssue(17511, "cannot create functions in temporary schemas")
+	case catalog.SchemaVirtual:
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"cannot create functions in schema %s", n.schema.Name)
+	}
+	if n.dbDesc.GetID() == keys.SystemDatabaseID {
+		return pgerror.New(pgcode.InvalidObjectDefinition,
+			"cannot create functions in the system database")
+	}
+
+	// Check that the function does not refer to other databases.
+	for _, dep := range n.planDeps {
+		if dbID := dep.desc.ParentID; dbID != n.dbDesc.GetID() && dbID != keys.SystemDatabaseID {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"the function cannot refer to other databases")
+		}
+		if dep.desc.Temporary {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"the function cannot refer to temporary table %q", dep.desc.Name)
+		}
+	}
+
+	args := make([]descpb.FunctionDescriptor_Argument, len(n.n.Args))
+	for i := range n.n.Args {
+		typ, err := tree.ResolveType(params.ctx, n.n.Args[i].Type, params.p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return err
+		}
+		args[i] = descpb.FunctionDescriptor_Argument{Name: string(n.n.Args[i].Name), Type: typ}
+	}
+	retType, err := tree.ResolveType(params.ctx, n.n.ReturnType, params.p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+
+	db, err := params.p.ResolveMutableDatabaseDescriptor(params.ctx, n.dbDesc.GetName(), true /* required */)
+	if err != nil {
+		return err
+	}
+
+	// Look for an existing function with the same signature in the same
+	// schema.
+	existing, err := params.p.findFunctionOverload(params.ctx, db, n.schema.ID, funcName, args)
+	if err != nil {
+		return err
+	}
+
+	var desc *funcdesc.Mutable
+	if existing != nil {
+		if !n.n.Replace {
+			return sqlerrors.NewFunctionAlreadyExistsError(funcName)
+		}
+		if err := params.p.CheckPrivilege(params.ctx, existing, privilege.DROP); err != nil {
+			return err
+		}
+		if !existing.ReturnType.Identical(retType) {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change return type of existing function")
+		}
+		// Remove the references of the previous definition. The new ones are
+		// added below.
+		if err := params.p.removeFunctionBackReferences(params.ctx, existing); err != nil {
+			return err
+		}
+		existing.DependsOn = nil
+		existing.DependsOnTypes = nil
+		existing.Args = args
+		desc = existing
+	} else {
+		id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB, params.p.ExecCfg().Codec)
+		if err != nil {
+			return err
+		}
+		desc = funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{
+			Name:           funcName,
+			ID:             id,
+			ParentID:       db.GetID(),
+			ParentSchemaID: n.schema.ID,
+			Version:        1,
+			Privileges:     funcdesc.NewDefaultPrivilegeDescriptor(params.SessionData().User()),
+			Args:           args,
+			ReturnType:     retType,
+		})
+	}
+	if err := setFunctionOptions(desc, n.n.Options); err != nil {
+		return err
+	}
+
+	// Collect all the tables, views and sequences, as well as the user-defined
+	// types in the signature, that this function depends on.
+	for id := range n.planDeps {
+		desc.AddTableDependency(id)
+	}
+	for _, typ := range append(desc.ArgTypes().Types(), retType) {
+		for id := range typedesc.GetTypeDescriptorClosure(typ) {
+			desc.AddTypeDependency(id)
+		}
+	}
+
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	if existing != nil {
+		if err := params.p.writeFunctionDescChange(params.ctx, desc, jobDesc); err != nil {
+			return err
+		}
+	} else {
+		if err := params.p.createFunctionDesc(params.ctx, desc); err != nil {
+			return err
+		}
+		// Register the new overload in the parent database, so that the
+		// function can be found during name resolution.
+		if db.Functions == nil {
+			db.Functions = make(map[string]descpb.DatabaseDescriptor_FunctionInfo)
+		}
+		info := db.Functions[funcName]
+		info.Overloads = append(info.Overloads, descpb.DatabaseDescriptor_FunctionInfo_Overload{
+			ID:       desc.ID,
+			SchemaID: desc.ParentSchemaID,
+		})
+		db.Functions[funcName] = info
+		if err := params.p.writeNonDropDatabaseChange(
+			params.ctx, db,
+			fmt.Sprintf("updating parent database %s for %s", db.GetName(), jobDesc),
+		); err != nil {
+			return err
+		}
+	}
+
+	// Persist the back-references in all referenced descriptors.
+	return params.p.addFunctionBackReferences(params.ctx, desc)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
+
+// setFunctionOptions applies the options of a CREATE FUNCTION statement to
+// the given function descriptor. Options which are not specified are reset
+// to their defaults.
+func setFunctionOptions(desc *funcdesc.Mutable, options tree.FunctionOptions) error {
+	desc.Volatility = descpb.FunctionDescriptor_VOLATILE
+	desc.LeakProof = false
+	desc.NullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+	for _, option := range options {
+		switch t := option.(type) {
+		case tree.FunctionLanguage:
+			if t != tree.FunctionLangSQL {
+				return pgerror.Newf(pgcode.UndefinedObject, "language %q does not exist", string(t))
+			}
+		case tree.FunctionVolatility:
+			switch tree.Volatility(t) {
+			case tree.VolatilityImmutable:
+				desc.Volatility = descpb.FunctionDescriptor_IMMUTABLE
+			case tree.VolatilityStable:
+				desc.Volatility = descpb.FunctionDescriptor_STABLE
+			default:
+				desc.Volatility = descpb.FunctionDescriptor_VOLATILE
+			}
+		case tree.FunctionLeakProof:
+			desc.LeakProof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			if t == tree.FunctionCalledOnNullInput {
+				desc.NullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+			} else {
+				desc.NullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+			}
+		case tree.FunctionBody:
+			desc.Body = string(t)
+		}
+	}
+	if desc.LeakProof && desc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"cannot create leakproof function with non-immutable volatility")
+	}
+	return nil
+}
+
+// findFunctionOverload returns the mutable descriptor of the function with
+// the given name and argument types in the given schema of the database, or
+// nil if there is no such function.
+func (p *planner) findFunctionOverload(
+	ctx context.Context,
+	db *dbdesc.Mutable,
+	schemaID descpb.ID,
+	name string,
+	args []descpb.FunctionDescriptor_Argument,
+) (*funcdesc.Mutable, error) {
+	for _, ov := range db.Functions[name].Overloads {
+		if ov.SchemaID != schemaID {
+			continue
+		}
+		desc, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, ov.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(desc.Args) != len(args) {
+			continue
+		}
+		match := true
+		for i := range args {
+			if !desc.Args[i].Type.Identical(args[i].Type) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return desc, nil
+		}
+	}
+	return nil, nil
+}
+
+// createFunctionDesc writes a new function descriptor. Functions have no
+// namespace entry, so they are only reachable through the parent database.
+func (p *planner) createFunctionDesc(ctx context.Context, desc *funcdesc.Mutable) error {
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+	b := p.txn.NewBatch()
+	if err := catalogkv.WriteNewDescToBatch(
+		ctx,
+		p.ExtendedEvalContext().Tracing.KVTracingEnabled(),
+		p.ExecCfg().Settings,
+		b,
+		p.ExecCfg().Codec,
+		desc.GetID(),
+		desc,
+	); err != nil {
+		return err
+	}
+	if err := p.Descriptors().AddUncommittedDescriptor(desc); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// writeFunctionDescChange writes an updated function descriptor and queues a
+// job which waits for the leases on the previous version to be released.
+func (p *planner) writeFunctionDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	job, jobExists := p.extendedEvalCtx.SchemaChangeJobCache[desc.ID]
+	if jobExists {
+		// Update it.
+		if err := job.WithTxn(p.txn).SetDescription(ctx,
+			func(ctx context.Context, desc string) (string, error) {
+				return desc + "; " + jobDesc, nil
+			},
+		); err != nil {
+			return err
+		}
+		log.Infof(ctx, "job %d: updated with for change on function %d", *job.ID(), desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress: jobspb.SchemaChangeProgress{},
+		}
+		newJob, err := p.extendedEvalCtx.QueueJob(jobRecord)
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "queued new schema change job %d for function %d", *newJob.ID(), desc.ID)
+	}
+
+	if !desc.Dropped() {
+		if err := desc.Validate(); err != nil {
+			return err
+		}
+	}
+	return p.Descriptors().WriteDesc(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, p.txn,
+	)
+}
+
+// addFunctionBackReferences adds back-references to the given function in
+// the tables and types it depends on.
+func (p *planner) addFunctionBackReferences(ctx context.Context, desc *funcdesc.Mutable) error {
+	for _, id := range desc.DependsOn {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		tbl.AddFunctionReference(desc.ID)
+		if err := p.writeSchemaChange(
+			ctx, tbl, descpb.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)", desc.Name, tbl.Name, tbl.ID),
+		); err != nil {
+			return err
+		}
+	}
+	for _, id := range desc.DependsOnTypes {
+		jobDesc := fmt.Sprintf("updating type back reference %d for function %d", id, desc.ID)
+		if err := p.addTypeBackReference(ctx, id, desc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFunctionBackReferences removes the back-references to the given
+// function from the tables and types it depends on.
+func (p *planner) removeFunctionBackReferences(
+	ctx context.Context, desc *funcdesc.Mutable,
+) error {
+	for _, id := range desc.DependsOn {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if tbl.Dropped() {
+			// The table is being dropped along with the function.
+			continue
+		}
+		tbl.RemoveFunctionReference(desc.ID)
+		if err := p.writeSchemaChange(
+			ctx, tbl, descpb.InvalidMutationID,
+			fmt.Sprintf("removing function reference %q in table %s(%d)", desc.Name, tbl.Name, tbl.ID),
+		); err != nil {
+			return err
+		}
+	}
+	for _, id := range desc.DependsOnTypes {
+		jobDesc := fmt.Sprintf("updating type back reference %d for function %d", id, desc.ID)
+		if err := p.removeTypeBackReference(ctx, id, desc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -322,7 +322,8 @@ func (p *planner) checkPasswordAndGetHash(
 		}
 	}
 
-	hashedPassword, err = security.HashPassword(password)
+	method := getPasswordHashMethod(ctx, st)
+	hashedPassword, err = security.HashPasswordWithMethod(method, password)
 	if err != nil {
 		return hashedPassword, err
 	}
@@ -604,6 +604,9 @@ func (p *planner) MaybeUpgradeDependentOldForeignKeyVersionTables(
 // The passed validationBehavior is used to determine whether or not preexisting
 // entries in the table need to be validated against the unique constraint being
 // added. This only applies for existing tables, not new tables.
+//
+// The passed deferrability determines whether the uniqueness checks of the
+// constraint can be deferred until the end of the transaction.
 func ResolveUniqueWithoutIndexConstraint(
 	ctx context.Context,
 	tbl *tabledesc.Mutable,
@@ -611,6 +614,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	colNames []string,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
+	deferrability tree.ConstraintDeferrability,
 ) error {
 	var colSet catalog.TableColSet
 	cols := make([]*descpb.ColumnDescriptor, len(colNames))
@@ -662,10 +666,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Validity:  validity,
+		Name:              constraintName,
+		TableID:           tbl.ID,
+		ColumnIDs:         columnIDs,
+		Validity:          validity,
+		Deferrable:        deferrability.Deferrable(),
+		InitiallyDeferred: deferrability.InitiallyDeferred(),
 	}
 
 	if ts == NewTable {
@@ -681,6 +687,175 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// ResolveExclusionConstraint looks up the columns mentioned in an EXCLUDE
+// constraint, checks that the types of the columns support the operators they
+// are compared with, and adds metadata representing that constraint to the
+// descriptor.
+//
+// Exclusion constraints are enforced by check queries planned with the
+// mutations of the table, which look up the conflicting rows in a backing
+// index: an inverted index on a column compared with &&, or a forward index on
+// a column compared with = if there is no such column. If the table does not
+// have a suitable index, one is created and named after the constraint.
+func ResolveExclusionConstraint(
+	ctx context.Context,
+	tbl *tabledesc.Mutable,
+	d *tree.ExclusionConstraintTableDef,
+	ts TableState,
+	indexEncodingVersion descpb.IndexDescriptorVersion,
+) error {
+	// Like Postgres, the access method defaults to btree, which only supports
+	// the equality operator.
+	method := d.Using
+	if method == "" {
+		method = "btree"
+	}
+	switch method {
+	case "btree", "gist":
+	case "gin", "hash", "spgist", "brin":
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"access method %q does not support exclusion constraints", method)
+	default:
+		return pgerror.Newf(pgcode.UndefinedObject, "access method %q does not exist", method)
+	}
+
+	colNames := make([]string, len(d.Elems))
+	columnIDs := make(descpb.ColumnIDs, len(d.Elems))
+	operators := make([]string, len(d.Elems))
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		col, err := tbl.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return err
+		}
+		if elem.Operator == tree.Overlaps && method == "btree" {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"operator %s is not supported by access method %q", elem.Operator, method)
+		}
+		if _, ok := tree.CmpOps[elem.Operator].LookupImpl(col.Type, col.Type); !ok {
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"operator does not exist: %s %s %s", col.Type.SQLString(), elem.Operator, col.Type.SQLString())
+		}
+		colNames[i] = col.Name
+		columnIDs[i] = col.ID
+		operators[i] = elem.Operator.String()
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintInfo, err := tbl.GetConstraintInfo(ctx, nil)
+	if err != nil {
+		return err
+	}
+	constraintName := string(d.Name)
+	if constraintName == "" {
+		constraintName = tabledesc.GenerateUniqueConstraintName(
+			fmt.Sprintf("exclude_%s", strings.Join(colNames, "_")),
+			func(p string) bool {
+				_, ok := constraintInfo[p]
+				return ok
+			},
+		)
+	} else {
+		if _, ok := constraintInfo[constraintName]; ok {
+			return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", constraintName)
+		}
+	}
+
+	if ts != NewTable {
+		return errors.AssertionFailedf(
+			"resolving exclusion constraints on existing tables not yet supported",
+		)
+	}
+	c := descpb.ExclusionConstraint{
+		Name:      constraintName,
+		TableID:   tbl.ID,
+		ColumnIDs: columnIDs,
+		Operators: operators,
+		Method:    d.Using,
+		Validity:  descpb.ConstraintValidity_Validated,
+	}
+	if tabledesc.FindExclusionConstraintIndex(tbl, &c) == nil {
+		idx, err := makeExclusionConstraintIndex(tbl, d, constraintName, indexEncodingVersion)
+		if err != nil {
+			return err
+		}
+		if err := tbl.AddIndex(idx, false /* primary */); err != nil {
+			return err
+		}
+		// Allocate the IDs of the index now, so that it can be found by other
+		// exclusion constraints which use the same columns.
+		if err := tbl.AllocateIDs(ctx); err != nil {
+			return err
+		}
+	}
+	tbl.ExclusionConstraints = append(tbl.ExclusionConstraints, c)
+	return nil
+}
+
+// makeExclusionConstraintIndex returns the descriptor of an index which can
+// back the given exclusion constraint. The index is an inverted index on the
+// first column compared with &&, or a forward index on the indexable columns
+// compared with = if there is no such column.
+func makeExclusionConstraintIndex(
+	tbl *tabledesc.Mutable,
+	d *tree.ExclusionConstraintTableDef,
+	name string,
+	indexEncodingVersion descpb.IndexDescriptorVersion,
+) (descpb.IndexDescriptor, error) {
+	idx := descpb.IndexDescriptor{
+		Name:    name,
+		Version: indexEncodingVersion,
+	}
+	var columns tree.IndexElemList
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		if elem.Operator != tree.Overlaps {
+			continue
+		}
+		col, err := tbl.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return descpb.IndexDescriptor{}, err
+		}
+		switch col.Type.Family() {
+		case types.ArrayFamily:
+		case types.GeometryFamily:
+			config, err := geoindex.GeometryIndexConfigForSRID(col.Type.GeoSRIDOrZero())
+			if err != nil {
+				return descpb.IndexDescriptor{}, err
+			}
+			idx.GeoConfig = *config
+		default:
+			// The conflicting rows can only be looked up efficiently in an
+			// inverted index, which does not support this type.
+			return descpb.IndexDescriptor{}, unimplemented.NewWithIssuef(46657,
+				"exclusion constraints comparing type %s with %s are not supported",
+				col.Type.SQLString(), elem.Operator)
+		}
+		idx.Type = descpb.IndexDescriptor_INVERTED
+		columns = tree.IndexElemList{{Column: elem.Column}}
+		break
+	}
+	if idx.Type != descpb.IndexDescriptor_INVERTED {
+		for i := range d.Elems {
+			col, err := tbl.FindActiveOrNewColumnByName(d.Elems[i].Column)
+			if err != nil {
+				return descpb.IndexDescriptor{}, err
+			}
+			if colinfo.ColumnTypeIsIndexable(col.Type) {
+				columns = append(columns, tree.IndexElem{Column: d.Elems[i].Column})
+			}
+		}
+		if len(columns) == 0 {
+			return descpb.IndexDescriptor{}, unimplemented.NewWithIssuef(46657,
+				"exclusion constraint %q has no indexable column", name)
+		}
+	}
+	if err := idx.FillColumns(columns); err != nil {
+		return descpb.IndexDescriptor{}, err
+	}
+	return idx, nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -717,6 +892,13 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if d.Deferrability.Deferrable() &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use DEFERRABLE foreign key constraints",
+			clusterversion.DeferrableConstraints)
+	}
+
 	var originColSet catalog.TableColSet
 	originCols := make([]*descpb.ColumnDescriptor, len(d.FromCols))
 	for i, col := range d.FromCols {
@@ -932,6 +1114,8 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:          d.Deferrability.Deferrable(),
+		InitiallyDeferred:   d.Deferrability.InitiallyDeferred(),
 	}
 
 	if ts == NewTable {
@@ -1634,7 +1818,8 @@ func NewTableDesc(
 			if d.Interleave != nil {
 				return nil, unimplemented.NewWithIssue(9148, "use CREATE INDEX to make interleaved indexes")
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -1772,7 +1957,7 @@ func NewTableDesc(
 				// Add a unique constraint.
 				if err := ResolveUniqueWithoutIndexConstraint(
 					ctx, &desc, string(d.Unique.ConstraintName), []string{string(d.Name)}, NewTable,
-					tree.ValidationDefault,
+					tree.ValidationDefault, tree.ConstraintNotDeferrable,
 				); err != nil {
 					return nil, err
 				}
@@ -1812,6 +1997,12 @@ func NewTableDesc(
 						"unique constraints with a predicate but without an index are not supported",
 					)
 				}
+				if d.Deferrability.Deferrable() &&
+					!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+					return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+						"version %v must be finalized to use DEFERRABLE unique constraints",
+						clusterversion.DeferrableConstraints)
+				}
 				// Add a unique constraint.
 				colNames := make([]string, len(d.Columns))
 				for i := range colNames {
@@ -1819,6 +2010,7 @@ func NewTableDesc(
 				}
 				if err := ResolveUniqueWithoutIndexConstraint(
 					ctx, &desc, string(d.Name), colNames, NewTable, tree.ValidationDefault,
+					d.Deferrability,
 				); err != nil {
 					return nil, err
 				}
@@ -1841,6 +2033,18 @@ func NewTableDesc(
 				return nil, err
 			}
 
+		case *tree.ExclusionConstraintTableDef:
+			if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.ExclusionConstraints) {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use EXCLUDE constraints",
+					clusterversion.ExclusionConstraints)
+			}
+			if err := ResolveExclusionConstraint(
+				ctx, &desc, d, NewTable, indexEncodingVersion,
+			); err != nil {
+				return nil, err
+			}
+
 		default:
 			return nil, errors.Errorf("unsupported table def: %T", def)
 		}
@@ -2172,6 +2376,27 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 				}
 				defs = append(defs, def)
 			}
+			// Like Postgres, exclusion constraints are copied along with the
+			// indexes rather than the other constraints.
+			for i := range td.ExclusionConstraints {
+				c := &td.ExclusionConstraints[i]
+				def := tree.ExclusionConstraintTableDef{
+					Name:  tree.Name(c.Name),
+					Using: c.Method,
+					Elems: make(tree.ExclusionElemList, 0, len(c.ColumnIDs)),
+				}
+				colNames, err := td.NamesForColumnIDs(c.ColumnIDs)
+				if err != nil {
+					return nil, err
+				}
+				for j := range colNames {
+					def.Elems = append(def.Elems, tree.ExclusionElem{
+						Column:   tree.Name(colNames[j]),
+						Operator: c.Operator(j),
+					})
+				}
+				defs = append(defs, &def)
+			}
 		}
 		newDefs = append(newDefs, defs...)
 	}
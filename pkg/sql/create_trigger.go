@@ -0,0 +1,224 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+}
+
+// CreateTrigger creates a row-level trigger on a table.
+// Privileges: CREATE on table.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelTriggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"creating triggers requires all nodes to be upgraded to %s",
+			clusterversion.ByKey(clusterversion.RowLevelTriggers))
+	}
+
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	tableDesc := n.tableDesc
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.n.Name) {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", n.n.Name, tableDesc.Name)
+		}
+	}
+
+	trigger := descpb.TriggerDescriptor{Name: string(n.n.Name)}
+	if n.n.ActionTime == tree.TriggerBefore {
+		trigger.ActionTime = descpb.TriggerDescriptor_BEFORE
+	} else {
+		trigger.ActionTime = descpb.TriggerDescriptor_AFTER
+	}
+	for _, ev := range n.n.Events {
+		var dup bool
+		switch ev {
+		case tree.TriggerInsert:
+			dup, trigger.OnInsert = trigger.OnInsert, true
+		case tree.TriggerUpdate:
+			dup, trigger.OnUpdate = trigger.OnUpdate, true
+		case tree.TriggerDelete:
+			dup, trigger.OnDelete = trigger.OnDelete, true
+		}
+		if dup {
+			return pgerror.New(pgcode.Syntax, "duplicate trigger events specified")
+		}
+	}
+
+	body, err := validateTriggerBody(n.n.Body)
+	if err != nil {
+		return err
+	}
+	trigger.Body = body
+
+	if n.n.When != nil {
+		if err := validateTriggerWhen(params.ctx, params.p, tableDesc, &trigger, n.n.When); err != nil {
+			return err
+		}
+		trigger.WhenExpr = tree.Serialize(n.n.When)
+	}
+
+	// Triggers that fire for the same event are executed in alphabetical order
+	// of their names, so we keep them sorted.
+	tableDesc.Triggers = append(tableDesc.Triggers, trigger)
+	sort.Slice(tableDesc.Triggers, func(i, j int) bool {
+		return tableDesc.Triggers[i].Name < tableDesc.Triggers[j].Name
+	})
+
+	if err := tableDesc.Validate(
+		params.ctx, catalogkv.NewOneLevelUncachedDescGetter(params.p.txn, params.ExecCfg().Codec),
+	); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// validateTriggerBody checks that the body of a trigger is a single INSERT,
+// UPSERT, UPDATE or DELETE statement, and returns it in its canonical form.
+func validateTriggerBody(body string) (string, error) {
+	stmts, err := parser.Parse(body)
+	if err != nil {
+		return "", pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid trigger body")
+	}
+	if len(stmts) != 1 {
+		return "", pgerror.New(pgcode.InvalidFunctionDefinition,
+			"trigger body must contain exactly one statement")
+	}
+	if stmts[0].NumPlaceholders > 0 {
+		return "", pgerror.New(pgcode.InvalidFunctionDefinition,
+			"trigger body cannot contain placeholders")
+	}
+	var returning tree.ReturningClause
+	switch t := stmts[0].AST.(type) {
+	case *tree.Insert:
+		returning = t.Returning
+	case *tree.Update:
+		returning = t.Returning
+	case *tree.Delete:
+		returning = t.Returning
+	default:
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s statements are not supported in trigger bodies", stmts[0].AST.StatementTag())
+	}
+	if tree.HasReturningClause(returning) {
+		return "", pgerror.New(pgcode.FeatureNotSupported,
+			"RETURNING is not supported in trigger bodies")
+	}
+	return tree.AsString(stmts[0].AST), nil
+}
+
+// validateTriggerWhen checks that the WHEN condition of a trigger is a boolean
+// expression that only references the columns of the table through NEW and
+// OLD, and does not reference a row that is missing for one of its events.
+func validateTriggerWhen(
+	ctx context.Context,
+	p *planner,
+	tableDesc *tabledesc.Mutable,
+	trigger *descpb.TriggerDescriptor,
+	when tree.Expr,
+) error {
+	replaced, err := tree.SimpleVisit(when, func(expr tree.Expr) (bool, tree.Expr, error) {
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok {
+			return true, expr, nil
+		}
+		vn, err := name.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+		c, ok := vn.(*tree.ColumnItem)
+		if !ok || c.TableName == nil || c.TableName.NumParts != 1 {
+			return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"trigger WHEN condition can only reference columns through NEW and OLD: %s",
+				tree.ErrString(name))
+		}
+		switch record := c.TableName.Parts[0]; record {
+		case "new":
+			if trigger.OnDelete {
+				return false, nil, pgerror.New(pgcode.InvalidColumnReference,
+					"DELETE trigger's WHEN condition cannot reference NEW values")
+			}
+		case "old":
+			if trigger.OnInsert {
+				return false, nil, pgerror.New(pgcode.InvalidColumnReference,
+					"INSERT trigger's WHEN condition cannot reference OLD values")
+			}
+		default:
+			return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"trigger WHEN condition can only reference columns through NEW and OLD: %s",
+				tree.ErrString(name))
+		}
+		col, err := tableDesc.FindActiveColumnByName(string(c.ColumnName))
+		if err != nil {
+			return false, nil, err
+		}
+		// The values of the row are not known yet; a typed NULL is enough to
+		// type check the condition.
+		return false, &tree.CastExpr{Expr: tree.DNull, Type: col.Type}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	defer p.semaCtx.Properties.Restore(p.semaCtx.Properties)
+	p.semaCtx.Properties.Require("WHEN", tree.RejectSpecial|tree.RejectSubqueries)
+	_, err = tree.TypeCheckAndRequire(ctx, replaced, &p.semaCtx, types.Bool, "WHEN")
+	return err
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
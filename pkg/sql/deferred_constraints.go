@@ -0,0 +1,382 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// deferredConstraint identifies a deferrable constraint: foreign key
+// constraints are identified by their origin table, and unique constraints
+// without an index by the table they are defined on.
+type deferredConstraint struct {
+	tableID descpb.ID
+	name    string
+}
+
+// constraintMode is the checking mode of deferrable constraints, as set by
+// SET CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault indicates that the constraint is checked according
+	// to its INITIALLY DEFERRED or INITIALLY IMMEDIATE declaration.
+	constraintModeDefault constraintMode = iota
+	// constraintModeDeferred indicates that the constraint is checked at commit.
+	constraintModeDeferred
+	// constraintModeImmediate indicates that the constraint is checked at the
+	// end of each statement.
+	constraintModeImmediate
+)
+
+func makeConstraintMode(deferred bool) constraintMode {
+	if deferred {
+		return constraintModeDeferred
+	}
+	return constraintModeImmediate
+}
+
+// deferredConstraintState tracks the deferrable constraints of a SQL
+// transaction: the checking modes set with SET CONSTRAINTS, and the rows
+// whose checks were deferred by previous statements and which must be
+// validated again before the transaction commits.
+//
+// The zero value is ready to use and represents the state at the beginning of
+// a transaction.
+type deferredConstraintState struct {
+	// all is the mode set by the last SET CONSTRAINTS ALL statement.
+	all constraintMode
+
+	// modes contains the modes set for individual constraints after the last
+	// SET CONSTRAINTS ALL statement.
+	modes map[deferredConstraint]constraintMode
+
+	// pending contains the constraints whose checks were deferred.
+	pending map[deferredConstraint]*pendingConstraint
+}
+
+// pendingConstraint is a deferred constraint along with the values of its
+// columns in the rows which violated it when they were written. A row that
+// satisfies a constraint when it is written can only violate it later if
+// another write, which is checked in turn, changes the referenced or
+// conflicting rows, so only these values need to be validated again.
+type pendingConstraint struct {
+	deferredConstraint
+
+	// rows contains the distinct values of the constraint columns, in the order
+	// in which the constraint lists them.
+	rows []tree.Datums
+	// seen contains the string representations of rows.
+	seen map[string]struct{}
+}
+
+// isDeferred returns true if the checks of the given constraint are currently
+// deferred until the end of the transaction.
+func (s *deferredConstraintState) isDeferred(c deferredConstraint, initiallyDeferred bool) bool {
+	mode, ok := s.modes[c]
+	if !ok {
+		mode = s.all
+	}
+	if mode == constraintModeDefault {
+		return initiallyDeferred
+	}
+	return mode == constraintModeDeferred
+}
+
+// setAllModes sets the checking mode of all deferrable constraints.
+func (s *deferredConstraintState) setAllModes(deferred bool) {
+	s.all = makeConstraintMode(deferred)
+	s.modes = nil
+}
+
+// setMode sets the checking mode of the given constraint.
+func (s *deferredConstraintState) setMode(c deferredConstraint, deferred bool) {
+	if s.modes == nil {
+		s.modes = make(map[deferredConstraint]constraintMode)
+	}
+	s.modes[c] = makeConstraintMode(deferred)
+}
+
+// addPending records that the check of the given constraint was deferred for
+// a row with the given values of the constraint columns.
+func (s *deferredConstraintState) addPending(c deferredConstraint, row tree.Datums) {
+	if s.pending == nil {
+		s.pending = make(map[deferredConstraint]*pendingConstraint)
+	}
+	pc, ok := s.pending[c]
+	if !ok {
+		pc = &pendingConstraint{deferredConstraint: c, seen: make(map[string]struct{})}
+		s.pending[c] = pc
+	}
+	key := row.String()
+	if _, ok := pc.seen[key]; ok {
+		return
+	}
+	pc.seen[key] = struct{}{}
+	pc.rows = append(pc.rows, row)
+}
+
+// takePending removes the pending constraints for which the given filter
+// returns true (or all of them, if filter is nil) and returns them in a
+// deterministic order.
+func (s *deferredConstraintState) takePending(
+	filter func(deferredConstraint) bool,
+) []*pendingConstraint {
+	var res []*pendingConstraint
+	for c, pc := range s.pending {
+		if filter == nil || filter(c) {
+			res = append(res, pc)
+			delete(s.pending, c)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].tableID != res[j].tableID {
+			return res[i].tableID < res[j].tableID
+		}
+		return res[i].name < res[j].name
+	})
+	return res
+}
+
+// clone returns a deep copy of the state, used to restore it when rolling
+// back to a savepoint or rewinding the transaction.
+func (s *deferredConstraintState) clone() deferredConstraintState {
+	res := deferredConstraintState{all: s.all}
+	if len(s.modes) > 0 {
+		res.modes = make(map[deferredConstraint]constraintMode, len(s.modes))
+		for c, m := range s.modes {
+			res.modes[c] = m
+		}
+	}
+	if len(s.pending) > 0 {
+		res.pending = make(map[deferredConstraint]*pendingConstraint, len(s.pending))
+		for c, pc := range s.pending {
+			// The rows themselves are never modified, so they can be shared.
+			clone := &pendingConstraint{
+				deferredConstraint: c,
+				rows:               append([]tree.Datums(nil), pc.rows...),
+				seen:               make(map[string]struct{}, len(pc.seen)),
+			}
+			for k := range pc.seen {
+				clone.seen[k] = struct{}{}
+			}
+			res.pending[c] = clone
+		}
+	}
+	return res
+}
+
+// reset clears the state at the end of a transaction.
+func (s *deferredConstraintState) reset() {
+	*s = deferredConstraintState{}
+}
+
+// maybeDeferCheck returns a function which records the rows returned by the
+// check query enforcing the given deferrable constraint, to be validated
+// when the transaction commits, or nil if the check query should fail the
+// statement as usual. Checks are never deferred in implicit transactions.
+func (p *planner) maybeDeferCheck(d *exec.DeferrableCheck) func(row tree.Datums) error {
+	state := p.extendedEvalCtx.DeferredConstraints
+	if state == nil || p.extendedEvalCtx.TxnImplicit {
+		return nil
+	}
+	c := deferredConstraint{tableID: descpb.ID(d.Table.ID()), name: d.Name}
+	if !state.isDeferred(c, d.InitiallyDeferred) {
+		return nil
+	}
+	return func(row tree.Datums) error {
+		state.addPending(c, d.KeyValues(row))
+		return nil
+	}
+}
+
+// validateDeferredConstraints validates the given deferred constraints against
+// the data written by the transaction. Only the rows which violated a
+// constraint when they were written are checked again. Constraints that were
+// dropped in the meantime (possibly along with their table) are skipped.
+func validateDeferredConstraints(
+	ctx context.Context,
+	constraints []*pendingConstraint,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	codec keys.SQLCodec,
+) error {
+	for _, c := range constraints {
+		desc, err := catalogkv.GetDescriptorByID(
+			ctx, txn, codec, c.tableID, catalogkv.Immutable,
+			catalogkv.TableDescriptorKind, false, /* required */
+		)
+		if err != nil {
+			return err
+		}
+		if desc == nil || desc.Dropped() {
+			continue
+		}
+		tbl := desc.(catalog.TableDescriptor)
+		var fk *descpb.ForeignKeyConstraint
+		if err := tbl.ForeachOutboundFK(func(ref *descpb.ForeignKeyConstraint) error {
+			if ref.Name == c.name {
+				fk = ref
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if fk != nil {
+			if err := validateDeferredForeignKey(ctx, tbl, fk, c.rows, ie, txn, codec); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, uc := range tbl.AllActiveAndInactiveUniqueWithoutIndexConstraints() {
+			if uc.Name == c.name {
+				if err := validateDeferredUniqueConstraint(ctx, tbl, uc, c.rows, ie, txn); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// validateDeferredForeignKey verifies that the rows of srcTable with the given
+// values of the origin columns of the foreign key, if any are left, have a
+// matching row in the referenced table.
+func validateDeferredForeignKey(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	rows []tree.Datums,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	codec keys.SQLCodec,
+) error {
+	desc, err := catalogkv.GetDescriptorByID(ctx, txn, codec, fk.ReferencedTableID, catalogkv.Immutable,
+		catalogkv.TableDescriptorKind, true /* required */)
+	if err != nil {
+		return err
+	}
+	targetTable := desc.(catalog.TableDescriptor)
+	colNames, err := srcTable.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return err
+	}
+	referencedColNames, err := targetTable.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		srcWhere := make([]string, len(row))
+		targetWhere := make([]string, len(row))
+		hasNull := false
+		for i := range row {
+			// The values are passed as placeholders, $1 being the first one.
+			srcWhere[i] = fmt.Sprintf("%s IS NOT DISTINCT FROM $%d", tree.NameString(colNames[i]), i+1)
+			targetWhere[i] = fmt.Sprintf("%s = $%d", tree.NameString(referencedColNames[i]), i+1)
+			hasNull = hasNull || row[i] == tree.DNull
+		}
+		// Rows are only recorded with NULL values if they violate MATCH FULL,
+		// in which case no referenced row can match them.
+		query := fmt.Sprintf(`SELECT 1 FROM [%d AS src]@{IGNORE_FOREIGN_KEYS} WHERE %s`,
+			srcTable.GetID(), strings.Join(srcWhere, " AND "))
+		if !hasNull {
+			query += fmt.Sprintf(` AND NOT EXISTS (SELECT 1 FROM [%d AS target] WHERE %s)`,
+				targetTable.GetID(), strings.Join(targetWhere, " AND "))
+		}
+		query += ` LIMIT 1`
+
+		args := make([]interface{}, len(row))
+		for i := range row {
+			args[i] = row[i]
+		}
+		values, err := ie.QueryRow(ctx, "validate deferred fk constraint", txn, query, args...)
+		if err != nil {
+			return err
+		}
+		if values.Len() == 0 {
+			continue
+		}
+		if hasNull {
+			return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
+				"foreign key violation: MATCH FULL does not allow mixing of null and nonnull values %s for %s",
+				formatValues(colNames, row), fk.Name,
+			), fk.Name)
+		}
+		return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
+			"foreign key violation: %q row %s has no match in %q",
+			srcTable.GetName(), formatValues(colNames, row), targetTable.GetName()), fk.Name)
+	}
+	return nil
+}
+
+// validateDeferredUniqueConstraint verifies that at most one row of srcTable
+// has each of the given values of the columns of the unique constraint.
+func validateDeferredUniqueConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	rows []tree.Datums,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	colNames, err := srcTable.NamesForColumnIDs(uc.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	where := make([]string, len(colNames))
+	for i, n := range colNames {
+		where[i] = fmt.Sprintf("%s = $%d", tree.NameString(n), i+1)
+	}
+	query := fmt.Sprintf(`SELECT count(*) FROM [%d AS tbl] WHERE %s`,
+		srcTable.GetID(), strings.Join(where, " AND "))
+
+	for _, row := range rows {
+		args := make([]interface{}, len(row))
+		for i := range row {
+			args[i] = row[i]
+		}
+		values, err := ie.QueryRow(ctx, "validate deferred unique constraint", txn, query, args...)
+		if err != nil {
+			return err
+		}
+		if values == nil || tree.MustBeDInt(values[0]) <= 1 {
+			continue
+		}
+		valueStrs := make([]string, len(row))
+		for i := range row {
+			valueStrs[i] = row[i].String()
+		}
+		return errors.WithDetail(
+			pgerror.WithConstraintName(pgerror.Newf(pgcode.UniqueViolation,
+				"duplicate key value violates unique constraint %q", uc.Name,
+			), uc.Name),
+			fmt.Sprintf("Key (%s)=(%s) already exists.",
+				strings.Join(colNames, ", "), strings.Join(valueStrs, ", ")),
+		)
+	}
+	return nil
+}
@@ -36,6 +36,11 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// UNLISTEN *
+		if l := p.extendedEvalCtx.Notifications; l != nil {
+			l.UnlistenAll()
+		}
 	default:
 		return nil, errors.AssertionFailedf("unknown mode for DISCARD: %d", s.Mode)
 	}
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -549,6 +550,24 @@ func (ds *ServerImpl) SetupFlow(
 	log.VEventf(ctx, 1, "received SetupFlow request from n%v for flow %v", req.Flow.Gateway, req.Flow.FlowID)
 	parentSpan := tracing.SpanFromContext(ctx)
 
+	// Wait until the node has the capacity to start the flow. Only the setup
+	// of the flow is subject to admission control; the KV requests issued by
+	// the flow are admitted separately.
+	if q := ds.ServerConfig.SQLLeafStartWorkQueue; q != nil {
+		info := admission.WorkInfo{
+			TenantID:   roachpb.SystemTenantID,
+			Priority:   admission.NormalPri,
+			CreateTime: timeutil.Now().UnixNano(),
+		}
+		enabled, err := q.Admit(ctx, info)
+		if err != nil {
+			return &execinfrapb.SimpleResponse{Error: execinfrapb.NewError(ctx, err)}, nil
+		}
+		if enabled {
+			defer q.AdmittedWorkDone(info.TenantID)
+		}
+	}
+
 	// Note: the passed context will be canceled when this RPC completes, so we
 	// can't associate it with the flow.
 	ctx = ds.AnnotateCtx(context.Background())
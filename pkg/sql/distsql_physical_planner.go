@@ -3556,14 +3556,26 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 	if err != nil {
 		return nil, err
 	}
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
+	spec := &execinfrapb.CSVWriterSpec{
 		Destination:      n.destination,
 		NamePattern:      n.fileNamePattern,
 		Options:          n.csvOpts,
 		ChunkRows:        int64(n.chunkRows),
 		CompressionCodec: n.fileCompression,
 		UserProto:        planCtx.planner.User().EncodeProto(),
-	}}
+		Format:           n.format,
+		RowGroupSize:     n.rowGroupSize,
+	}
+	if n.format == execinfrapb.ExportFormat_Parquet {
+		// Parquet files are self-describing, so they need the names of the
+		// exported columns.
+		cols := planColumns(n.source)
+		spec.ColumnNames = make([]string, len(cols))
+		for i := range cols {
+			spec.ColumnNames[i] = cols[i].Name
+		}
+	}
+	core := execinfrapb.ProcessorCoreUnion{CSVWriter: spec}
 
 	resTypes := make([]*types.T, len(colinfo.ExportColumns))
 	for i := range colinfo.ExportColumns {
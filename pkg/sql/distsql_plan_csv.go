@@ -70,36 +70,40 @@ func (b *RowResultWriter) Err() error {
 	return b.err
 }
 
-// callbackResultWriter is a rowResultWriter that runs a callback function
+// CallbackResultWriter is a rowResultWriter that runs a callback function
 // on AddRow.
-type callbackResultWriter struct {
+type CallbackResultWriter struct {
 	fn           func(ctx context.Context, row tree.Datums) error
 	rowsAffected int
 	err          error
 }
 
-var _ rowResultWriter = &callbackResultWriter{}
+var _ rowResultWriter = &CallbackResultWriter{}
 
-// newCallbackResultWriter creates a new callbackResultWriter.
-func newCallbackResultWriter(
+// NewCallbackResultWriter creates a new CallbackResultWriter.
+func NewCallbackResultWriter(
 	fn func(ctx context.Context, row tree.Datums) error,
-) *callbackResultWriter {
-	return &callbackResultWriter{fn: fn}
+) *CallbackResultWriter {
+	return &CallbackResultWriter{fn: fn}
 }
 
-func (c *callbackResultWriter) IncrementRowsAffected(n int) {
+// IncrementRowsAffected implements the rowResultWriter interface.
+func (c *CallbackResultWriter) IncrementRowsAffected(n int) {
 	c.rowsAffected += n
 }
 
-func (c *callbackResultWriter) AddRow(ctx context.Context, row tree.Datums) error {
+// AddRow implements the rowResultWriter interface.
+func (c *CallbackResultWriter) AddRow(ctx context.Context, row tree.Datums) error {
 	return c.fn(ctx, row)
 }
 
-func (c *callbackResultWriter) SetError(err error) {
+// SetError is part of the rowResultWriter interface.
+func (c *CallbackResultWriter) SetError(err error) {
 	c.err = err
 }
 
-func (c *callbackResultWriter) Err() error {
+// Err is part of the rowResultWriter interface.
+func (c *CallbackResultWriter) Err() error {
 	return c.err
 }
 
@@ -271,7 +275,7 @@ func DistIngest(
 	}
 
 	var res roachpb.BulkOpSummary
-	rowResultWriter := newCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+	rowResultWriter := NewCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
 		var counts roachpb.BulkOpSummary
 		if err := protoutil.Unmarshal([]byte(*row[0].(*tree.DBytes)), &counts); err != nil {
 			return err
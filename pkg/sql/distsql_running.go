@@ -983,6 +983,17 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 
 	// We treat plan.cascades as a queue.
 	for i := 0; i < len(plan.cascades); i++ {
+		if plan.cascades[i].Before {
+			// BEFORE triggers were executed before the mutation.
+			continue
+		}
+		if plan.cascades[i].PlanTriggerFn != nil {
+			if !dsp.planAndRunTrigger(ctx, planner, evalCtxFactory, plan, i, recv) {
+				return false
+			}
+			continue
+		}
+
 		// The original bufferNode is stored in c.Buffer; we can refer to it
 		// directly.
 		// TODO(radu): this requires keeping all previous plans "alive" until the
@@ -1030,39 +1041,11 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 		}
 		cp := cascadePlan.(*planComponents)
 		plan.cascades[i].plan = cp.main
-		if len(cp.subqueryPlans) > 0 {
-			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
-			return false
-		}
-
-		// Queue any new cascades.
-		if len(cp.cascades) > 0 {
-			plan.cascades = append(plan.cascades, cp.cascades...)
-		}
-
-		// Collect any new checks.
-		if len(cp.checkPlans) > 0 {
-			plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
-		}
-
-		// In cyclical reference situations, the number of cascading operations can
-		// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
-		// safeguard in case we have a bug that results in an infinite cascade loop.
-		if limit := evalCtx.SessionData.OptimizerFKCascadesLimit; len(plan.cascades) > limit {
-			telemetry.Inc(sqltelemetry.CascadesLimitReached)
-			err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
-			recv.SetError(err)
-			return false
-		}
+		plan.cascades[i].subqueryPlans = cp.subqueryPlans
 
-		if err := dsp.planAndRunPostquery(
-			ctx,
-			cp.main,
-			planner,
-			evalCtx,
-			recv,
-		); err != nil {
-			recv.SetError(err)
+		if !dsp.planAndRunCascadePlan(
+			ctx, planner, evalCtxFactory, plan, cp, i, false /* fromTrigger */, recv,
+		) {
 			return false
 		}
 	}
@@ -1084,6 +1067,20 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 
 	for i := range plan.checkPlans {
 		log.VEventf(ctx, 1, "executing check query %d out of %d", i+1, len(plan.checkPlans))
+		if d := plan.checkPlans[i].deferrable; d != nil {
+			if onRow := planner.maybeDeferCheck(d); onRow != nil {
+				// The rows violating the constraint are recorded, to be
+				// validated again at commit, instead of failing the statement.
+				n, ok := plan.checkPlans[i].plan.planNode.(*errorIfRowsNode)
+				if !ok {
+					recv.SetError(errors.AssertionFailedf(
+						"unexpected check query root %T", plan.checkPlans[i].plan.planNode))
+					return false
+				}
+				n.onRow = onRow
+				log.VEventf(ctx, 1, "check of constraint %s is deferred", d.Name)
+			}
+		}
 		if err := dsp.planAndRunPostquery(
 			ctx,
 			plan.checkPlans[i].plan,
@@ -1099,6 +1096,196 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	return true
 }
 
+// PlanAndRunBeforeTriggers runs the BEFORE triggers of the main query. It
+// must be called after the subqueries of the plan are executed, since they
+// buffer the input of the mutation.
+//
+// Like PlanAndRunCascadesAndChecks, this method can append to plan.cascades
+// and plan.checkPlans.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) PlanAndRunBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	recv *DistSQLReceiver,
+) bool {
+	prevSteppingMode := planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	defer func() { _ = planner.Txn().ConfigureStepping(ctx, prevSteppingMode) }()
+
+	return dsp.planAndRunBeforeTriggers(ctx, planner, evalCtxFactory, plan, 0 /* start */, recv)
+}
+
+// planAndRunBeforeTriggers runs the BEFORE triggers in plan.cascades, starting
+// at the given index. If any trigger runs, a sequence point is placed
+// afterwards, so that the mutation observes the writes of the triggers.
+func (dsp *DistSQLPlanner) planAndRunBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	start int,
+	recv *DistSQLReceiver,
+) bool {
+	// Note that more cascades can be appended to plan.cascades while the
+	// triggers run; these are triggers of other mutations and are handled by
+	// the callers.
+	ran := false
+	for i, n := start, len(plan.cascades); i < n; i++ {
+		if !plan.cascades[i].Before {
+			continue
+		}
+		if !dsp.planAndRunTrigger(ctx, planner, evalCtxFactory, plan, i, recv) {
+			return false
+		}
+		ran = true
+	}
+	if ran {
+		_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+		if err := planner.Txn().Step(ctx); err != nil {
+			recv.SetError(err)
+			return false
+		}
+	}
+	return true
+}
+
+// planAndRunTrigger runs the row-level trigger plan.cascades[i] for each row of
+// its buffer. The query of the trigger is built once, and only planned for
+// each row.
+func (dsp *DistSQLPlanner) planAndRunTrigger(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	i int,
+	recv *DistSQLReceiver,
+) bool {
+	// Note that plan.cascades can be reallocated while the trigger runs, so we
+	// don't hold on to a pointer to the cascade.
+	trigger := plan.cascades[i].Cascade
+	rows := trigger.Buffer.(*bufferNode).bufferedRows
+	if rows.Len() == 0 {
+		return true
+	}
+
+	log.VEventf(ctx, 1, "executing trigger %s for %d rows", trigger.Trigger, rows.Len())
+
+	planRow, err := trigger.PlanTriggerFn(ctx, &planner.semaCtx, &evalCtxFactory().EvalContext)
+	if err != nil {
+		recv.SetError(err)
+		return false
+	}
+
+	for r := 0; r < rows.Len(); r++ {
+		// We place a sequence point before every row, so that the statement
+		// executed for each row can observe the writes of the previous rows.
+		_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+		if err := planner.Txn().Step(ctx); err != nil {
+			recv.SetError(err)
+			return false
+		}
+
+		evalCtx := evalCtxFactory()
+		execFactory := newExecFactory(planner)
+		rowPlan, err := planRow(
+			ctx, &evalCtx.EvalContext, execFactory, rows.At(r), false, /* allowAutoCommit */
+		)
+		if err != nil {
+			recv.SetError(err)
+			return false
+		}
+		if rowPlan == nil {
+			// The trigger does not fire for this row.
+			continue
+		}
+		cp := rowPlan.(*planComponents)
+		plan.cascades[i].rowPlans = append(plan.cascades[i].rowPlans, cp)
+
+		if !dsp.planAndRunCascadePlan(
+			ctx, planner, evalCtxFactory, plan, cp, i, true /* fromTrigger */, recv,
+		) {
+			return false
+		}
+	}
+	return true
+}
+
+// planAndRunCascadePlan runs the plan of a cascade or trigger query, which was
+// created for plan.cascades[parent]. The cascades and checks of the plan are
+// added to the given plan; BEFORE triggers are executed right away, while
+// other cascades and checks are executed later by PlanAndRunCascadesAndChecks.
+// fromTrigger is set if the plan was created for one of the rows of a trigger.
+func (dsp *DistSQLPlanner) planAndRunCascadePlan(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	cp *planComponents,
+	parent int,
+	fromTrigger bool,
+	recv *DistSQLReceiver,
+) bool {
+	// The subqueries buffer the input of mutations with BEFORE triggers.
+	if len(cp.subqueryPlans) > 0 {
+		if !dsp.PlanAndRunSubqueries(ctx, planner, evalCtxFactory, cp.subqueryPlans, recv) {
+			return false
+		}
+	}
+
+	// Queue any new cascades.
+	start := len(plan.cascades)
+	depth := plan.cascades[parent].depth + 1
+	triggered := plan.cascades[parent].triggered || fromTrigger
+	for j := range cp.cascades {
+		c := cp.cascades[j]
+		c.depth = depth
+		c.triggered = triggered
+		plan.cascades = append(plan.cascades, c)
+		if triggered {
+			plan.numTriggeredCascades++
+		}
+	}
+
+	// Collect any new checks.
+	if len(cp.checkPlans) > 0 {
+		plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
+	}
+
+	// In cyclical reference situations, the number of cascading operations can
+	// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
+	// safeguard in case we have a bug that results in an infinite cascade loop.
+	// The cascades queued by triggers are only limited by their depth, since a
+	// trigger queues the cascades of its query for every row.
+	evalCtx := evalCtxFactory()
+	limit := evalCtx.SessionData.OptimizerFKCascadesLimit
+	if len(plan.cascades)-plan.numTriggeredCascades > limit ||
+		(len(cp.cascades) > 0 && depth > limit) {
+		telemetry.Inc(sqltelemetry.CascadesLimitReached)
+		err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
+		recv.SetError(err)
+		return false
+	}
+
+	if !dsp.planAndRunBeforeTriggers(ctx, planner, evalCtxFactory, plan, start, recv) {
+		return false
+	}
+
+	if err := dsp.planAndRunPostquery(
+		ctx,
+		cp.main,
+		planner,
+		evalCtxFactory(),
+		recv,
+	); err != nil {
+		recv.SetError(err)
+		return false
+	}
+	return true
+}
+
 // planAndRunPostquery runs a cascade or check query.
 func (dsp *DistSQLPlanner) planAndRunPostquery(
 	ctx context.Context,
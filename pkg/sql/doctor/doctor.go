@@ -160,6 +160,11 @@ func ExamineDescriptors(
 		case catalog.SchemaDescriptor:
 			// parent schema id is always 0.
 			parentSchemaExists = true
+		case catalog.FunctionDescriptor:
+			if err := d.Validate(); err != nil {
+				problemsFound = true
+				fmt.Fprint(stdout, reportMsg(desc, "%s", err))
+			}
 		}
 		if desc.GetParentID() != descpb.InvalidID && !parentExists {
 			problemsFound = true
@@ -172,6 +177,15 @@ func ExamineDescriptors(
 			fmt.Fprint(stdout, reportMsg(desc, "invalid parent schema id %d", desc.GetParentSchemaID()))
 		}
 
+		// Functions are not named in the namespace table, the overloads are
+		// listed in the parent database descriptor instead.
+		if _, isFunction := desc.(catalog.FunctionDescriptor); isFunction {
+			if verbose {
+				fmt.Fprint(stdout, reportMsg(desc, "processed"))
+			}
+			continue
+		}
+
 		// Process namespace entries pointing to this descriptor.
 		names, ok := nMap[row.ID]
 		if !ok {
@@ -324,6 +338,8 @@ func reportMsg(desc catalog.Descriptor, format string, args ...interface{}) stri
 		header = "  Schema"
 	case catalog.DatabaseDescriptor:
 		header = "Database"
+	case catalog.FunctionDescriptor:
+		header = "Function"
 	}
 	return fmt.Sprintf("%s %3d: ParentID %3d, ParentSchemaID %2d, Name '%s': ",
 		header, desc.GetID(), desc.GetParentID(), desc.GetParentSchemaID(), desc.GetName()) +
@@ -17,11 +17,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/errors"
 )
@@ -34,6 +36,11 @@ type dropCascadeState struct {
 	td                      []toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
+
+	// droppingDatabase is true if the parent database of the objects is being
+	// dropped as well.
+	droppingDatabase bool
 
 	droppedNames []string
 }
@@ -62,6 +69,23 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	// User-defined functions are not part of the namespace, so they are found
+	// through the parent database.
+	for _, info := range db.Functions {
+		for _, overload := range info.Overloads {
+			if overload.SchemaID != schema.ID {
+				continue
+			}
+			fn, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, overload.ID)
+			if err != nil {
+				return err
+			}
+			if fn.Dropped() {
+				continue
+			}
+			d.functionsToDelete = append(d.functionsToDelete, fn)
+		}
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
@@ -72,6 +96,7 @@ func (d *dropCascadeState) collectObjectsInSchema(
 func (d *dropCascadeState) resolveCollectedObjects(
 	ctx context.Context, p *planner, db *dbdesc.Mutable,
 ) error {
+	d.droppingDatabase = db != nil
 	d.td = make([]toDelete, 0, len(d.objectNamesToDelete))
 	// Resolve each of the collected names.
 	for i := range d.objectNamesToDelete {
@@ -175,11 +200,58 @@ func (d *dropCascadeState) resolveCollectedObjects(
 		return err
 	}
 	d.allTableObjectsToDelete = allObjectsToDelete
+
+	// Functions in other schemas may depend on the objects being dropped, in
+	// which case they are dropped as well.
+	for _, tbl := range d.allTableObjectsToDelete {
+		for _, id := range tbl.DependedOnByFunctions {
+			if d.hasFunction(id) {
+				continue
+			}
+			fn, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, id)
+			if err != nil {
+				return err
+			}
+			if fn.Dropped() {
+				continue
+			}
+			d.functionsToDelete = append(d.functionsToDelete, fn)
+		}
+	}
+	for _, fn := range d.functionsToDelete {
+		if err := p.CheckPrivilege(ctx, fn, privilege.DROP); err != nil {
+			return err
+		}
+	}
 	d.td = filterImplicitlyDeletedObjects(d.td, implicitDeleteMap)
 	return nil
 }
 
 func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *planner) error {
+	// Delete all of the collected functions first, so that they are not
+	// dropped again as dependents of the tables below. The back-references in
+	// the tables and types which are being dropped don't need to be removed.
+	for _, fn := range d.functionsToDelete {
+		dependsOn := fn.DependsOn[:0]
+		for _, id := range fn.DependsOn {
+			if !d.hasTableObject(id) {
+				dependsOn = append(dependsOn, id)
+			}
+		}
+		fn.DependsOn = dependsOn
+		dependsOnTypes := fn.DependsOnTypes[:0]
+		for _, id := range fn.DependsOnTypes {
+			if !d.hasType(id) {
+				dependsOnTypes = append(dependsOnTypes, id)
+			}
+		}
+		fn.DependsOnTypes = dependsOnTypes
+		if err := p.dropFunctionImpl(ctx, fn, d.parentDatabase(fn), "dropping function"); err != nil {
+			return err
+		}
+		d.droppedNames = append(d.droppedNames, fn.Name)
+	}
+
 	// Delete all of the collected tables.
 	for _, toDel := range d.td {
 		desc := toDel.desc
@@ -213,6 +285,48 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 	return nil
 }
 
+func (d *dropCascadeState) hasFunction(id descpb.ID) bool {
+	for _, fn := range d.functionsToDelete {
+		if fn.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dropCascadeState) hasTableObject(id descpb.ID) bool {
+	for _, tbl := range d.allTableObjectsToDelete {
+		if tbl.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dropCascadeState) hasType(id descpb.ID) bool {
+	for _, typ := range d.typesToDelete {
+		if typ.ID == id || typ.ArrayTypeID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parentDatabase returns the descriptor of the parent database of the given
+// function, which is written once all the objects have been dropped, or nil if
+// the database is being dropped.
+func (d *dropCascadeState) parentDatabase(fn *funcdesc.Mutable) *dbdesc.Mutable {
+	if d.droppingDatabase {
+		return nil
+	}
+	for _, sc := range d.schemasToDelete {
+		if sc.dbDesc.ID == fn.ParentID {
+			return sc.dbDesc
+		}
+	}
+	return nil
+}
+
 func (d *dropCascadeState) getDroppedTableDetails() []jobspb.DroppedTableDetails {
 	res := make([]jobspb.DroppedTableDetails, len(d.allTableObjectsToDelete))
 	for i := range d.allTableObjectsToDelete {
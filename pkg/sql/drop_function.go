@@ -0,0 +1,311 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n  *tree.DropFunction
+	fd []*funcdesc.Mutable
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropFunctionNode{n: nil}
+
+// DropFunction drops user-defined functions.
+// Privileges: DROP on the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	seen := make(map[descpb.ID]struct{})
+	for i := range n.Functions {
+		desc, err := p.ResolveMutableFunctionDescriptor(ctx, &n.Functions[i], !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			continue
+		}
+		if _, ok := seen[desc.ID]; ok {
+			continue
+		}
+		seen[desc.ID] = struct{}{}
+		if err := p.CheckPrivilege(ctx, desc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		node.fd = append(node.fd, desc)
+	}
+	// Functions cannot be referenced by other objects, so there is nothing
+	// for CASCADE to drop.
+	return node, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+	for _, fn := range n.fd {
+		if err := params.p.dropFunctionAndUpdateDatabase(
+			params.ctx, fn, tree.AsStringWithFQNames(n.n, params.Ann()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropFunctionNode) Close(ctx context.Context)           {}
+func (n *dropFunctionNode) ReadingOwnWrites()                   {}
+
+// ResolveMutableFunctionDescriptor resolves the function identified by the
+// given name and, if specified, argument types. If no argument types are
+// specified, the name must refer to a single overload. It returns nil if the
+// function does not exist and required is false.
+func (p *planner) ResolveMutableFunctionDescriptor(
+	ctx context.Context, fn *tree.FuncObj, required bool,
+) (*funcdesc.Mutable, error) {
+	notFound := func() (*funcdesc.Mutable, error) {
+		if !required {
+			return nil, nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedFunction,
+			"function %s does not exist", tree.ErrString(fn))
+	}
+
+	name := fn.FuncName
+	dbName := p.CurrentDatabase()
+	if name.NumParts == 3 {
+		dbName = name.Parts[2]
+	}
+	if dbName == "" {
+		return notFound()
+	}
+	dbDesc, err := p.Descriptors().GetDatabaseByName(
+		ctx, p.txn, dbName, p.CommonLookupFlags(required),
+	)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+	info, ok := dbDesc.DatabaseDesc().Functions[name.Object()]
+	if !ok {
+		return notFound()
+	}
+
+	var argTypes []descpb.FunctionDescriptor_Argument
+	if fn.ArgsSpecified {
+		argTypes = make([]descpb.FunctionDescriptor_Argument, len(fn.Args))
+		for i := range fn.Args {
+			typ, err := tree.ResolveType(ctx, fn.Args[i], p.semaCtx.GetTypeResolver())
+			if err != nil {
+				return nil, err
+			}
+			argTypes[i].Type = typ
+		}
+	}
+
+	var schemaNames []string
+	if name.NumParts >= 2 {
+		schemaNames = []string{name.Parts[1]}
+	} else {
+		iter := p.CurrentSearchPath().Iter()
+		for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+			schemaNames = append(schemaNames, scName)
+		}
+	}
+	for _, scName := range schemaNames {
+		found, sc, err := p.Descriptors().GetSchemaByName(
+			ctx, p.txn, dbDesc.GetID(), scName, p.CommonLookupFlags(false /* required */),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !found || sc.Kind == catalog.SchemaTemporary {
+			continue
+		}
+		var candidates []*funcdesc.Mutable
+		for _, overload := range info.Overloads {
+			if overload.SchemaID != sc.ID {
+				continue
+			}
+			desc, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, overload.ID)
+			if err != nil {
+				return nil, err
+			}
+			if desc.Dropped() {
+				continue
+			}
+			candidates = append(candidates, desc)
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		if !fn.ArgsSpecified {
+			if len(candidates) > 1 {
+				return nil, pgerror.Newf(pgcode.AmbiguousFunction,
+					"function name %q is not unique", tree.ErrString(name))
+			}
+			return candidates[0], nil
+		}
+		for _, desc := range candidates {
+			if len(desc.Args) != len(argTypes) {
+				continue
+			}
+			match := true
+			for i := range argTypes {
+				if !desc.Args[i].Type.Identical(argTypes[i].Type) {
+					match = false
+					break
+				}
+			}
+			if match {
+				return desc, nil
+			}
+		}
+		// Only the schema which contains overloads of the name is searched.
+		break
+	}
+	return notFound()
+}
+
+// canRemoveDependentFunctions returns an error if the given table, view or
+// sequence is depended on by user-defined functions and the drop behavior is
+// not CASCADE.
+func (p *planner) canRemoveDependentFunctions(
+	ctx context.Context, from *tabledesc.Mutable, behavior tree.DropBehavior,
+) error {
+	for _, id := range from.DependedOnByFunctions {
+		fn, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, id)
+		if err != nil {
+			return errors.Wrapf(err, "error resolving dependent function ID %d", id)
+		}
+		if behavior != tree.DropCascade {
+			return errors.WithHintf(
+				sqlerrors.NewDependentObjectErrorf("cannot drop %s %q because function %q depends on it",
+					from.TypeName(), from.Name, fn.Name),
+				"you can drop %s instead.", fn.Name)
+		}
+		if err := p.CheckPrivilege(ctx, fn, privilege.DROP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropDependentFunctions drops the user-defined functions which depend on the
+// given table, view or sequence, which is being dropped.
+func (p *planner) dropDependentFunctions(ctx context.Context, tableDesc *tabledesc.Mutable) error {
+	for _, id := range tableDesc.DependedOnByFunctions {
+		fn, err := p.Descriptors().GetMutableFunctionVersionByID(ctx, p.txn, id)
+		if err != nil {
+			return err
+		}
+		// This function is already getting dropped. Don't do it twice.
+		if fn.Dropped() {
+			continue
+		}
+		// The table is being dropped, so its back-reference does not need to be
+		// removed.
+		for i, dep := range fn.DependsOn {
+			if dep == tableDesc.ID {
+				fn.DependsOn = append(fn.DependsOn[:i], fn.DependsOn[i+1:]...)
+				break
+			}
+		}
+		if err := p.dropFunctionAndUpdateDatabase(ctx, fn, "dropping dependent function"); err != nil {
+			return err
+		}
+	}
+	tableDesc.DependedOnByFunctions = nil
+	return nil
+}
+
+// dropFunctionAndUpdateDatabase drops the given function and writes its
+// parent database, from which the function is removed.
+func (p *planner) dropFunctionAndUpdateDatabase(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	mutDesc, err := p.Descriptors().GetMutableDescriptorByID(ctx, desc.ParentID, p.txn)
+	if err != nil {
+		return err
+	}
+	db, ok := mutDesc.(*dbdesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("parent %d of function %d is not a database",
+			desc.ParentID, desc.ID)
+	}
+	if err := p.dropFunctionImpl(ctx, desc, db, jobDesc); err != nil {
+		return err
+	}
+	return p.writeNonDropDatabaseChange(
+		ctx, db,
+		fmt.Sprintf("updating parent database %s for %s", db.GetName(), jobDesc),
+	)
+}
+
+// dropFunctionImpl does the work of dropping a function: it removes the
+// back-references to the function and marks the descriptor as dropped. If
+// parentDB is not nil, the function is also removed from it, and the caller
+// is responsible for writing it. parentDB is nil when the parent database is
+// itself being dropped.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, desc *funcdesc.Mutable, parentDB *dbdesc.Mutable, jobDesc string,
+) error {
+	if desc.Dropped() {
+		return errors.Errorf("function %q is already being dropped", desc.Name)
+	}
+
+	if err := p.removeFunctionBackReferences(ctx, desc); err != nil {
+		return err
+	}
+
+	if parentDB != nil {
+		name := desc.Name
+		info := parentDB.Functions[name]
+		for i := range info.Overloads {
+			if info.Overloads[i].ID == desc.ID {
+				info.Overloads = append(info.Overloads[:i], info.Overloads[i+1:]...)
+				break
+			}
+		}
+		if len(info.Overloads) == 0 {
+			delete(parentDB.Functions, name)
+		} else {
+			parentDB.Functions[name] = info
+		}
+	}
+
+	desc.SetDropped()
+	return p.writeFunctionDescChange(ctx, desc, jobDesc)
+}
@@ -408,6 +408,27 @@ func (p *planner) dropIndexByName(
 	}
 	tableDesc.InboundFKs = tableDesc.InboundFKs[:sliceIdx]
 
+	// The index may be the only one in which the checks of an exclusion
+	// constraint can look up conflicting rows, in which case the constraint is
+	// dropped along with it.
+	sliceIdx = 0
+	for i := range tableDesc.ExclusionConstraints {
+		c := &tableDesc.ExclusionConstraints[i]
+		tableDesc.ExclusionConstraints[sliceIdx] = *c
+		sliceIdx++
+		if c.CanBeBackedBy(idx) && !indexHasReplacementCandidate(c.CanBeBackedBy) {
+			if behavior != tree.DropCascade && constraintBehavior != ignoreIdxConstraint {
+				return errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"index %q is in use as exclusion constraint %q", idx.Name, c.Name),
+					"use CASCADE if you really want to drop it.",
+				)
+			}
+			sliceIdx--
+		}
+	}
+	tableDesc.ExclusionConstraints = tableDesc.ExclusionConstraints[:sliceIdx]
+
 	if len(idx.Interleave.Ancestors) > 0 {
 		if err := p.removeInterleaveBackReference(ctx, tableDesc, idx); err != nil {
 			return err
@@ -90,12 +90,14 @@ func (p *planner) DropSchema(ctx context.Context, n *tree.DropSchema) (planNode,
 				return nil, pgerror.Newf(pgcode.InsufficientPrivilege, "permission denied to drop schema %q", sc.Name)
 			}
 			namesBefore := len(d.objectNamesToDelete)
+			functionsBefore := len(d.functionsToDelete)
 			if err := d.collectObjectsInSchema(ctx, p, db, &sc); err != nil {
 				return nil, err
 			}
 			// We added some new objects to delete. Ensure that we have the correct
 			// drop behavior to be doing this.
-			if namesBefore != len(d.objectNamesToDelete) && n.DropBehavior != tree.DropCascade {
+			if (namesBefore != len(d.objectNamesToDelete) || functionsBefore != len(d.functionsToDelete)) &&
+				n.DropBehavior != tree.DropCascade {
 				return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
 					"schema %q is not empty and CASCADE was not specified", scName)
 			}
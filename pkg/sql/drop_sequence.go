@@ -115,16 +115,21 @@ func (p *planner) dropSequenceImpl(
 	if err := removeSequenceOwnerIfExists(ctx, p, seqDesc.ID, seqDesc.GetSequenceOpts()); err != nil {
 		return err
 	}
+	if behavior == tree.DropCascade {
+		if err := p.dropDependentFunctions(ctx, seqDesc); err != nil {
+			return err
+		}
+	}
 	return p.initiateDropTable(ctx, seqDesc, queueJob, jobDesc, true /* drainName */)
 }
 
 // sequenceDependency error returns an error if the given sequence cannot be dropped because
-// a table uses it in a DEFAULT expression on one of its columns, or nil if there is no
-// such dependency.
+// a table uses it in a DEFAULT expression on one of its columns or a function refers to
+// it, or nil if there is no such dependency.
 func (p *planner) sequenceDependencyError(
 	ctx context.Context, droppedDesc *tabledesc.Mutable,
 ) error {
-	if len(droppedDesc.DependedOnBy) > 0 {
+	if len(droppedDesc.DependedOnBy) > 0 || len(droppedDesc.DependedOnByFunctions) > 0 {
 		return pgerror.Newf(
 			pgcode.DependentObjectsStillExist,
 			"cannot drop sequence %s because other objects depend on it",
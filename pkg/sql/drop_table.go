@@ -96,6 +96,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 				}
 			}
 		}
+		if err := p.canRemoveDependentFunctions(ctx, droppedDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
 		if err := p.canRemoveAllTableOwnedSequences(ctx, droppedDesc, n.DropBehavior); err != nil {
 			return nil, err
 		}
@@ -340,6 +343,11 @@ func (p *planner) dropTableImpl(
 		droppedViews = append(droppedViews, viewDesc.Name)
 	}
 
+	// Drop all user-defined functions that depend on this table.
+	if err := p.dropDependentFunctions(ctx, tableDesc); err != nil {
+		return droppedViews, err
+	}
+
 	err := p.removeTableComments(ctx, tableDesc)
 	if err != nil {
 		return droppedViews, err
@@ -117,15 +117,22 @@ func (p *planner) canDropTypeDesc(
 	if len(desc.ReferencingDescriptorIDs) > 0 && behavior != tree.DropCascade {
 		var dependentNames []string
 		for _, id := range desc.ReferencingDescriptorIDs {
-			desc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+			refDesc, err := p.Descriptors().GetMutableDescriptorByID(ctx, id, p.txn)
 			if err != nil {
 				return errors.Wrapf(err, "type has dependent objects")
 			}
-			fqName, err := p.getQualifiedTableName(ctx, desc)
-			if err != nil {
-				return errors.Wrapf(err, "type %q has dependent objects", desc.Name)
+			switch refDesc := refDesc.(type) {
+			case *tabledesc.Mutable:
+				fqName, err := p.getQualifiedTableName(ctx, refDesc)
+				if err != nil {
+					return errors.Wrapf(err, "type %q has dependent objects", desc.Name)
+				}
+				dependentNames = append(dependentNames, fqName.FQString())
+			default:
+				// User-defined functions are not relations, so we just use
+				// their name.
+				dependentNames = append(dependentNames, refDesc.GetName())
 			}
-			dependentNames = append(dependentNames, fqName.FQString())
 		}
 		return pgerror.Newf(
 			pgcode.DependentObjectsStillExist,
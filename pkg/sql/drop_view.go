@@ -77,6 +77,9 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 				return nil, err
 			}
 		}
+		if err := p.canRemoveDependentFunctions(ctx, droppedDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(td) == 0 {
@@ -229,6 +232,9 @@ func (p *planner) dropViewImpl(
 			cascadeDroppedViews = append(cascadeDroppedViews, cascadedViews...)
 			cascadeDroppedViews = append(cascadeDroppedViews, dependentDesc.Name)
 		}
+		if err := p.dropDependentFunctions(ctx, viewDesc); err != nil {
+			return cascadeDroppedViews, err
+		}
 	}
 
 	// Remove any references to types that this view has.
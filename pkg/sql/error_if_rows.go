@@ -26,6 +26,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// onRow, if set, is called for every row produced by the wrapped node
+	// instead of returning an error. It is used for the checks of deferred
+	// constraints, which are validated again when the transaction commits.
+	onRow func(tree.Datums) error
+
 	nexted bool
 }
 
@@ -39,14 +44,18 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
-	ok, err := n.plan.Next(params)
-	if err != nil {
-		return false, err
-	}
-	if ok {
-		return false, n.mkErr(n.plan.Values())
+	for {
+		ok, err := n.plan.Next(params)
+		if err != nil || !ok {
+			return false, err
+		}
+		if n.onRow == nil {
+			return false, n.mkErr(n.plan.Values())
+		}
+		if err := n.onRow(n.plan.Values()); err != nil {
+			return false, err
+		}
 	}
-	return false, nil
 }
 
 func (n *errorIfRowsNode) Values() tree.Datums {
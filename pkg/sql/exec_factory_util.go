@@ -32,7 +32,7 @@ func constructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 ) (exec.Plan, error) {
 	res := &planComponents{}
 	assignPlan := func(plan *planMaybePhysical, node exec.Node) {
@@ -77,7 +77,8 @@ func constructPlan(
 	if len(checks) > 0 {
 		res.checkPlans = make([]checkPlan, len(checks))
 		for i := range checks {
-			assignPlan(&res.checkPlans[i].plan, checks[i])
+			assignPlan(&res.checkPlans[i].plan, checks[i].Plan)
+			res.checkPlans[i].deferrable = checks[i].Deferrable
 		}
 	}
 
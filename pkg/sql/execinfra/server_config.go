@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -149,6 +150,11 @@ type ServerConfig struct {
 	HydratedTables *hydratedtables.Cache
 
 	LatencyGetter *serverpb.LatencyGetter
+
+	// SQLLeafStartWorkQueue is used to admit the setup of flows on behalf of
+	// remote gateways. It is nil if admission control isn't used, as is the
+	// case for SQL tenant servers.
+	SQLLeafStartWorkQueue *admission.WorkQueue
 }
 
 // RuntimeStats is an interface through which the rowexec layer can get
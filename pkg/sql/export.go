@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -40,8 +41,10 @@ type exportNode struct {
 	// fileNamePattern represents the file naming pattern for the
 	// export, typically to be appended to the destination URI
 	fileNamePattern string
+	format          execinfrapb.ExportFormat
 	csvOpts         roachpb.CSVOptions
 	chunkRows       int
+	rowGroupSize    int64
 	fileCompression execinfrapb.FileCompression
 }
 
@@ -62,25 +65,34 @@ func (e *exportNode) Close(ctx context.Context) {
 }
 
 const (
-	exportOptionDelimiter   = "delimiter"
-	exportOptionNullAs      = "nullas"
-	exportOptionChunkRows   = "chunk_rows"
-	exportOptionFileName    = "filename"
-	exportOptionCompression = "compression"
+	exportOptionDelimiter    = "delimiter"
+	exportOptionNullAs       = "nullas"
+	exportOptionChunkRows    = "chunk_rows"
+	exportOptionFileName     = "filename"
+	exportOptionCompression  = "compression"
+	exportOptionRowGroupSize = "row_group_size"
 )
 
 var exportOptionExpectValues = map[string]KVStringOptValidate{
-	exportOptionChunkRows:   KVStringOptRequireValue,
-	exportOptionDelimiter:   KVStringOptRequireValue,
-	exportOptionFileName:    KVStringOptRequireValue,
-	exportOptionNullAs:      KVStringOptRequireValue,
-	exportOptionCompression: KVStringOptRequireValue,
+	exportOptionChunkRows:    KVStringOptRequireValue,
+	exportOptionDelimiter:    KVStringOptRequireValue,
+	exportOptionFileName:     KVStringOptRequireValue,
+	exportOptionNullAs:       KVStringOptRequireValue,
+	exportOptionCompression:  KVStringOptRequireValue,
+	exportOptionRowGroupSize: KVStringOptRequireValue,
 }
 
+// exportCSVOnlyOptions and exportParquetOnlyOptions are the options which are
+// only valid for one of the export formats.
+var exportCSVOnlyOptions = []string{exportOptionDelimiter, exportOptionNullAs}
+var exportParquetOnlyOptions = []string{exportOptionRowGroupSize}
+
 const exportChunkRowsDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
 const exportCompressionCodec = "gzip"
+const exportSnappyCompressionCodec = "snappy"
 
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
@@ -113,7 +125,20 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	var format execinfrapb.ExportFormat
+	switch fileFormat {
+	case "CSV":
+		format = execinfrapb.ExportFormat_CSV
+	case "PARQUET":
+		if !ef.planner.ExecCfg().Settings.Version.IsActive(
+			ef.planner.EvalContext().Context, clusterversion.ParquetFormat,
+		) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"exporting to PARQUET requires all nodes to be upgraded to %s",
+				clusterversion.ByKey(clusterversion.ParquetFormat))
+		}
+		format = execinfrapb.ExportFormat_Parquet
+	default:
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -147,6 +172,17 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	invalidOptions := exportParquetOnlyOptions
+	if format == execinfrapb.ExportFormat_Parquet {
+		invalidOptions = exportCSVOnlyOptions
+	}
+	for _, opt := range invalidOptions {
+		if _, ok := optVals[opt]; ok {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s option is not supported for %s export", opt, fileFormat)
+		}
+	}
+
 	csvOpts := roachpb.CSVOptions{}
 
 	if override, ok := optVals[exportOptionDelimiter]; ok {
@@ -171,27 +207,48 @@ func (ef *execFactory) ConstructExport(
 		}
 	}
 
+	var rowGroupSize int64
+	if override, ok := optVals[exportOptionRowGroupSize]; ok {
+		rowGroupSize, err = strconv.ParseInt(override, 10, 64)
+		if err != nil {
+			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+		}
+		if rowGroupSize < 1 {
+			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid parquet row group size")
+		}
+	}
+
 	// Check whenever compression is expected and extract compression codec name in case
-	// of positive result
+	// of positive result. Parquet files compress their pages rather than the
+	// whole file, and additionally support snappy.
 	var codec execinfrapb.FileCompression
 	if name, ok := optVals[exportOptionCompression]; ok && len(name) != 0 {
 		if strings.EqualFold(name, exportCompressionCodec) {
 			codec = execinfrapb.FileCompression_Gzip
+		} else if strings.EqualFold(name, exportSnappyCompressionCodec) &&
+			format == execinfrapb.ExportFormat_Parquet {
+			codec = execinfrapb.FileCompression_Snappy
 		} else {
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s", name)
 		}
 	}
 
+	filePatternDefault := exportFilePatternDefault
+	if format == execinfrapb.ExportFormat_Parquet {
+		filePatternDefault = exportParquetFilePatternDefault
+	}
 	exportID := ef.planner.stmt.QueryID.String()
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePatternDefault)
+	namePattern := fmt.Sprintf("export%s-%s", exportID, filePatternDefault)
 
 	return &exportNode{
 		source:          input.(planNode),
 		destination:     string(*destination),
 		fileNamePattern: namePattern,
+		format:          format,
 		csvOpts:         csvOpts,
 		chunkRows:       chunkRows,
+		rowGroupSize:    rowGroupSize,
 		fileCompression: codec,
 	}, nil
 }
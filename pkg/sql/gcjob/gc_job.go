@@ -108,6 +108,17 @@ func (r schemaChangeGCResumer) Resume(
 		}
 	}
 
+	// Delete the data of dropped tables and indexes using range tombstones
+	// right away. The data is still cleared once the GC TTL expires.
+	if err := deleteDroppedTables(ctx, execCfg, progress); err != nil {
+		return err
+	}
+	if details.Indexes != nil {
+		if err := deleteDroppedIndexes(ctx, execCfg, details.ParentID, progress); err != nil {
+			return err
+		}
+	}
+
 	tableDropTimes, indexDropTimes := getDropTimes(details)
 
 	timer := timeutil.NewTimer()
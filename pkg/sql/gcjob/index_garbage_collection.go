@@ -13,10 +13,12 @@ package gcjob
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -78,6 +80,55 @@ func gcIndexes(
 	return nil
 }
 
+// deleteDroppedIndexes deletes the data of the dropped indexes that are waiting
+// for their GC TTL to expire using range tombstones, like deleteDroppedTables
+// does for the dropped tables. This covers the indexes replaced by TRUNCATE as
+// well as those removed by DROP INDEX.
+func deleteDroppedIndexes(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	parentID descpb.ID,
+	progress *jobspb.SchemaChangeGCProgress,
+) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) {
+		return nil
+	}
+	var waiting []descpb.IndexID
+	for _, index := range progress.Indexes {
+		if index.Status == jobspb.SchemaChangeGCProgress_WAITING_FOR_GC {
+			waiting = append(waiting, index.IndexID)
+		}
+	}
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	// As in gcIndexes, old versions of the table descriptor may still be
+	// writing to the indexes.
+	if err := sql.WaitToUpdateLeases(ctx, execCfg.LeaseManager, parentID); err != nil {
+		return err
+	}
+	var parentTable *tabledesc.Immutable
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+		parentTable, err = catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, parentID)
+		return err
+	}); err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil
+		}
+		return errors.Wrapf(err, "fetching parent table %d", parentID)
+	}
+
+	for _, indexID := range waiting {
+		log.Infof(ctx, "deleting index %d from table %d using a range tombstone", indexID, parentID)
+		sp := parentTable.IndexSpan(execCfg.Codec, indexID)
+		if err := deleteSpanUsingRangeTombstone(ctx, execCfg.DB, sp); err != nil {
+			return errors.Wrapf(err, "deleting index %d", indexID)
+		}
+	}
+	return nil
+}
+
 // clearIndexes issues Clear Range requests over all specified indexes.
 func clearIndex(
 	ctx context.Context,
@@ -14,11 +14,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
@@ -81,6 +83,89 @@ func gcTables(
 	return nil
 }
 
+// deleteDroppedTables deletes the data of the dropped tables that are waiting
+// for their GC TTL to expire using range tombstones, so that the deletion is
+// visible to incremental backups and changefeeds right away while the history
+// remains readable until the data is cleared. The job may be resumed after
+// the tombstones were written, in which case they are written again; this is
+// harmless, as the additional range tombstones don't delete anything.
+func deleteDroppedTables(
+	ctx context.Context, execCfg *sql.ExecutorConfig, progress *jobspb.SchemaChangeGCProgress,
+) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) {
+		return nil
+	}
+	for _, droppedTable := range progress.Tables {
+		if droppedTable.Status != jobspb.SchemaChangeGCProgress_WAITING_FOR_GC {
+			continue
+		}
+		var table *tabledesc.Immutable
+		if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			var err error
+			table, err = catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, droppedTable.ID)
+			return err
+		}); err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				continue
+			}
+			return errors.Wrapf(err, "fetching table %d", droppedTable.ID)
+		}
+		if !table.Dropped() || table.DropTime == 0 {
+			continue
+		}
+		if _, err := DeleteTableDataUsingRangeTombstone(
+			ctx, execCfg.DB, execCfg.Settings, execCfg.Codec, table,
+		); err != nil {
+			return errors.Wrapf(err, "deleting data for table %d", table.ID)
+		}
+	}
+	return nil
+}
+
+// DeleteTableDataUsingRangeTombstone deletes all of the data in the specified
+// table by writing an MVCC range tombstone across the table's span, which
+// takes constant time per range. Unlike ClearTableData, it retains the history
+// of the table's data until it is garbage collected, so AS OF SYSTEM TIME
+// queries, incremental backups and changefeeds observe the deletion.
+//
+// It returns false without deleting anything if range tombstones can't be
+// used, either because the cluster version doesn't support them yet or
+// because the table is interleaved and its span holds data of other tables.
+func DeleteTableDataUsingRangeTombstone(
+	ctx context.Context,
+	db *kv.DB,
+	settings *cluster.Settings,
+	codec keys.SQLCodec,
+	table *tabledesc.Immutable,
+) (bool, error) {
+	if !settings.Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) || table.IsInterleaved() {
+		return false, nil
+	}
+	log.Infof(ctx, "deleting data for table %d using a range tombstone", table.ID)
+
+	tableKey := codec.TablePrefix(uint32(table.ID))
+	if err := deleteSpanUsingRangeTombstone(
+		ctx, db, roachpb.Span{Key: tableKey, EndKey: tableKey.PrefixEnd()},
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteSpanUsingRangeTombstone writes an MVCC range tombstone across the
+// span.
+func deleteSpanUsingRangeTombstone(ctx context.Context, db *kv.DB, sp roachpb.Span) error {
+	var b kv.Batch
+	b.AddRawRequest(&roachpb.DeleteRangeRequest{
+		RequestHeader: roachpb.RequestHeader{
+			Key:    sp.Key,
+			EndKey: sp.EndKey,
+		},
+		UseRangeTombstone: true,
+	})
+	return errors.Wrapf(db.Run(ctx, &b), "delete range %s - %s", sp.Key, sp.EndKey)
+}
+
 // ClearTableData deletes all of the data in the specified table.
 func ClearTableData(
 	ctx context.Context,
@@ -680,6 +680,10 @@ CREATE TABLE information_schema.constraint_column_usage (
 			dbNameStr := tree.NewDString(db.GetName())
 
 			for conName, con := range conInfo {
+				// Like Postgres, exclusion constraints are not included.
+				if con.Kind == descpb.ConstraintTypeExclusion {
+					continue
+				}
 				conTable := table
 				conCols := con.Columns
 				conNameStr := tree.NewDString(conName)
@@ -1385,16 +1389,20 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					// Like Postgres, exclusion constraints are not included.
+					if c.Kind == descpb.ConstraintTypeExclusion {
+						continue
+					}
 					if err := addRow(
-						dbNameStr,                       // constraint_catalog
-						scNameStr,                       // constraint_schema
-						tree.NewDString(conName),        // constraint_name
-						dbNameStr,                       // table_catalog
-						scNameStr,                       // table_schema
-						tbNameStr,                       // table_name
-						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						dbNameStr,                           // constraint_catalog
+						scNameStr,                           // constraint_schema
+						tree.NewDString(conName),            // constraint_name
+						dbNameStr,                           // table_catalog
+						scNameStr,                           // table_schema
+						tbNameStr,                           // table_name
+						tree.NewDString(string(c.Kind)),     // constraint_type
+						yesOrNoDatum(c.Deferrable()),        // is_deferrable
+						yesOrNoDatum(c.InitiallyDeferred()), // initially_deferred
 					); err != nil {
 						return err
 					}
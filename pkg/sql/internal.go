@@ -629,6 +629,11 @@ func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
 }
 
+// CreateNotificationResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateNotificationResult(pos CmdPos) NotificationResult {
+	panic("unimplemented")
+}
+
 // noopClientLock is an implementation of ClientLock that says that no results
 // have been communicated to the client.
 type noopClientLock internalClientComm
@@ -0,0 +1,112 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+//
+// Unlike in Postgres, the session starts listening on the channel right away
+// rather than when the transaction commits.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	l, err := p.notificationListener("LISTEN")
+	if err != nil {
+		return nil, err
+	}
+	telemetry.Inc(sqltelemetry.ListenUseCounter)
+	l.Listen(string(n.Channel))
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	l, err := p.notificationListener("UNLISTEN")
+	if err != nil {
+		return nil, err
+	}
+	if n.Star {
+		l.UnlistenAll()
+	} else {
+		l.Unlisten(string(n.Channel))
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// notificationListener returns the listener of the session, or an error if
+// the session doesn't support receiving notifications.
+func (p *planner) notificationListener(stmt string) (*notify.Listener, error) {
+	l := p.extendedEvalCtx.Notifications
+	if l == nil {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported in this context", stmt)
+	}
+	return l, nil
+}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.SendNotification(params.ctx, string(n.n.Channel), n.n.Payload)
+}
+
+func (n *notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *notifyNode) Close(context.Context)        {}
+
+// SendNotification is part of the tree.EvalPlanner interface.
+//
+// The notification is written to system.notifications in the transaction of
+// the planner, so that it is only delivered if the transaction commits.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(payload) >= notify.MaxPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"sending notifications requires all nodes to be upgraded to %s",
+			clusterversion.ByKey(clusterversion.NotificationsTable))
+	}
+	telemetry.Inc(sqltelemetry.NotifyUseCounter)
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"send-notification",
+		p.Txn(),
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`INSERT INTO system.notifications (channel, payload, node_id) VALUES ($1, $2, $3)`,
+		channel, payload, int64(p.ExecCfg().NodeID.SQLInstanceID()),
+	)
+	return err
+}
@@ -0,0 +1,474 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notify implements the delivery of the notifications sent with
+// NOTIFY to the sessions which LISTEN to their channel.
+//
+// Notifications are written to the system.notifications table by the
+// notifying transaction, so that they only become visible once the
+// transaction commits. Every node watches the table with a rangefeed and
+// hands the notifications to the local sessions listening to their channel.
+// Notifications are deleted from the table once they are older than the
+// sql.notifications.retention cluster setting.
+package notify
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var retention = settings.RegisterDurationSetting(
+	"sql.notifications.retention",
+	"the amount of time notifications sent with NOTIFY are kept in system.notifications",
+	5*time.Minute,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("cannot be set to a non-positive duration: %s", v)
+		}
+		return nil
+	},
+)
+
+func init() {
+	retention.SetVisibility(settings.Reserved)
+}
+
+// MaxPayloadLength is the maximum length of the payload of a notification,
+// like in Postgres.
+const MaxPayloadLength = 8000
+
+// maxPendingNotifications is the maximum number of notifications which can be
+// waiting to be delivered to a session. Further notifications to the session
+// are dropped.
+const maxPendingNotifications = 10000
+
+// gcBatchSize is the maximum number of notifications deleted by a single
+// statement when garbage collecting system.notifications. The statement scans
+// the index on the created column.
+const gcBatchSize = 1000
+
+// Notification is a message sent to a channel with NOTIFY.
+type Notification struct {
+	Channel string
+	Payload string
+	// NodeID is the ID of the SQL instance on which the notifying session ran.
+	// It is reported to clients in place of the process ID of the notifying
+	// backend.
+	NodeID int32
+}
+
+// Registry keeps track of the sessions of the node which listen to
+// notification channels, and delivers them the notifications sent to these
+// channels on any node.
+type Registry struct {
+	codec keys.SQLCodec
+	db    *kv.DB
+	ie    sqlutil.InternalExecutor
+	st    *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps a channel to the listeners of the channel.
+		listeners map[string]map[*Listener]struct{}
+	}
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry(
+	codec keys.SQLCodec, db *kv.DB, ie sqlutil.InternalExecutor, st *cluster.Settings,
+) *Registry {
+	r := &Registry{codec: codec, db: db, ie: ie, st: st}
+	r.mu.listeners = make(map[string]map[*Listener]struct{})
+	return r
+}
+
+// Start starts watching system.notifications for new notifications, as well
+// as the periodic deletion of old notifications.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+	r.watchForNotifications(ctx, stopper)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "notifications-gc", r.gcLoop)
+}
+
+// NewListener creates a Listener for a session. onNotify is called when
+// notifications become pending for the listener; it must not block.
+func (r *Registry) NewListener(onNotify func(ctx context.Context)) *Listener {
+	return &Listener{r: r, onNotify: onNotify}
+}
+
+// watchForNotifications runs a rangefeed on system.notifications which
+// dispatches the new notifications to the listeners.
+func (r *Registry) watchForNotifications(ctx context.Context, stopper *stop.Stopper) {
+	distSender := r.db.NonTransactionalSender().(*kv.CrossRangeTxnWrapperSender).Wrapped().(*kvcoord.DistSender)
+	eventCh := make(chan *roachpb.RangeFeedEvent)
+	prefix := r.codec.IndexPrefix(keys.NotificationsTableID, uint32(systemschema.NotificationsTable.GetPrimaryIndexID()))
+	watchedSpan := roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+
+	// frontier tracks the timestamp up to which all the notifications have
+	// been dispatched. The rangefeed is restarted from it after a failure, so
+	// that no notification is missed.
+	var frontier struct {
+		syncutil.Mutex
+		*span.Frontier
+	}
+	frontier.Frontier = span.MakeFrontier(watchedSpan)
+	frontier.Forward(watchedSpan, r.db.Clock().Now())
+	dedup := deduplicator{frontier: frontier.Frontier.Frontier()}
+
+	if err := stopper.RunAsyncTask(ctx, "notifications-rangefeed", func(ctx context.Context) {
+		// Run the rangefeed in a loop in the case of failure, likely due to node
+		// failures or general unavailability. We'll reset the retrier if the
+		// rangefeed runs for longer than the resetThreshold.
+		const resetThreshold = 30 * time.Second
+		restartLogEvery := log.Every(10 * time.Second)
+		for i, rt := 1, retry.StartWithCtx(ctx, retry.Options{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Closer:         stopper.ShouldQuiesce(),
+		}); rt.Next(); i++ {
+			frontier.Lock()
+			ts := frontier.Frontier.Frontier()
+			frontier.Unlock()
+			log.VEventf(ctx, 1, "starting rangefeed from %v on %v", ts, watchedSpan)
+			start := timeutil.Now()
+			err := distSender.RangeFeed(ctx, watchedSpan, ts, false /* withDiff */, eventCh)
+			if err != nil && ctx.Err() == nil && restartLogEvery.ShouldLog() {
+				log.Warningf(ctx, "notifications rangefeed failed %d times, restarting: %v",
+					log.Safe(i), log.Safe(err))
+			}
+			if ctx.Err() != nil {
+				log.VEventf(ctx, 1, "exiting rangefeed")
+				return
+			}
+			if ranFor := timeutil.Since(start); ranFor > resetThreshold {
+				i = 1
+				rt.Reset()
+			}
+		}
+	}); err != nil {
+		// This will only fail if the stopper has been stopped.
+		return
+	}
+
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-eventCh:
+				switch {
+				case e.Checkpoint != nil:
+					frontier.Lock()
+					frontier.Forward(e.Checkpoint.Span, e.Checkpoint.ResolvedTS)
+					ts := frontier.Frontier.Frontier()
+					frontier.Unlock()
+					dedup.forward(ts)
+				case e.Error != nil:
+					log.Warningf(ctx, "got an error from a rangefeed: %v", e.Error.Error)
+				case e.Val != nil:
+					if len(e.Val.Value.RawBytes) == 0 {
+						// The notification was deleted.
+						continue
+					}
+					if dedup.isDuplicate(e.Val) {
+						// The notification was emitted again by the catch-up scan
+						// of a restarted rangefeed.
+						continue
+					}
+					n, err := decodeNotification(e.Val.Value)
+					if err != nil {
+						log.Warningf(ctx, "%s: unable to decode notification: %v", e.Val.Key, err)
+						continue
+					}
+					r.dispatch(ctx, n)
+				}
+			}
+		}
+	})
+}
+
+// deduplicator filters out the notifications which were already dispatched.
+// When the rangefeed is restarted from the frontier, the notifications written
+// after the frontier are emitted again by its catch-up scan.
+type deduplicator struct {
+	// frontier is the timestamp up to which all the notifications have been
+	// dispatched.
+	frontier hlc.Timestamp
+	// seen contains the keys and timestamps of the notifications written after
+	// the frontier which were dispatched.
+	seen map[deduplicationKey]struct{}
+}
+
+type deduplicationKey struct {
+	key string
+	ts  hlc.Timestamp
+}
+
+// isDuplicate returns true if the notification was already dispatched, and
+// records it as dispatched otherwise.
+func (d *deduplicator) isDuplicate(v *roachpb.RangeFeedValue) bool {
+	ts := v.Value.Timestamp
+	if ts.LessEq(d.frontier) {
+		return true
+	}
+	k := deduplicationKey{key: string(v.Key), ts: ts}
+	if _, ok := d.seen[k]; ok {
+		return true
+	}
+	if d.seen == nil {
+		d.seen = make(map[deduplicationKey]struct{})
+	}
+	d.seen[k] = struct{}{}
+	return false
+}
+
+// forward forwards the frontier, and forgets about the notifications written
+// at or below it.
+func (d *deduplicator) forward(frontier hlc.Timestamp) {
+	if !d.frontier.Forward(frontier) {
+		return
+	}
+	for k := range d.seen {
+		if k.ts.LessEq(d.frontier) {
+			delete(d.seen, k)
+		}
+	}
+}
+
+// decodeNotification decodes the value of a row of system.notifications.
+func decodeNotification(value roachpb.Value) (Notification, error) {
+	var n Notification
+	b, err := value.GetTuple()
+	if err != nil {
+		return n, err
+	}
+	var a rowenc.DatumAlloc
+	var colID descpb.ColumnID
+	for len(b) > 0 {
+		_, dataOffset, colIDDiff, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return n, err
+		}
+		colID += descpb.ColumnID(colIDDiff)
+		col, err := systemschema.NotificationsTable.FindColumnByID(colID)
+		if err != nil {
+			// Skip the columns we don't know about.
+			l, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+			if err != nil {
+				return n, err
+			}
+			b = b[l:]
+			continue
+		}
+		var d tree.Datum
+		d, b, err = rowenc.DecodeTableValue(&a, col.Type, b)
+		if err != nil {
+			return n, err
+		}
+		switch col.Name {
+		case "channel":
+			n.Channel = string(tree.MustBeDString(d))
+		case "payload":
+			n.Payload = string(tree.MustBeDString(d))
+		case "node_id":
+			n.NodeID = int32(tree.MustBeDInt(d))
+		}
+	}
+	if n.Channel == "" {
+		return n, errors.AssertionFailedf("notification without channel")
+	}
+	return n, nil
+}
+
+// dispatch queues a notification for delivery to the listeners of its
+// channel.
+func (r *Registry) dispatch(ctx context.Context, n Notification) {
+	var toNotify []*Listener
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for l := range r.mu.listeners[n.Channel] {
+			if len(l.pending) >= maxPendingNotifications {
+				log.Warningf(ctx, "dropping notification on channel %q: too many pending notifications",
+					n.Channel)
+				continue
+			}
+			l.pending = append(l.pending, n)
+			if len(l.pending) == 1 {
+				toNotify = append(toNotify, l)
+			}
+		}
+	}()
+	for _, l := range toNotify {
+		l.onNotify(ctx)
+	}
+}
+
+// gcLoop periodically deletes the notifications which are older than the
+// retention period.
+func (r *Registry) gcLoop(ctx context.Context) {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		timer.Reset(retention.Get(&r.st.SV))
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Read = true
+		}
+		if !r.st.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+			continue
+		}
+		if err := r.deleteOldNotifications(ctx); err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "error deleting old notifications: %v", err)
+		}
+	}
+}
+
+func (r *Registry) deleteOldNotifications(ctx context.Context) error {
+	for {
+		n, err := r.ie.ExecEx(
+			ctx, "delete-old-notifications", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+			`DELETE FROM system.notifications WHERE created < now() - $1::INTERVAL LIMIT $2`,
+			retention.Get(&r.st.SV), gcBatchSize,
+		)
+		if err != nil || n < gcBatchSize {
+			return err
+		}
+	}
+}
+
+// Listener receives the notifications sent to the channels a session listens
+// to.
+type Listener struct {
+	r        *Registry
+	onNotify func(ctx context.Context)
+
+	// The following fields are protected by r.mu.
+
+	// channels is the set of channels the session listens to.
+	channels map[string]struct{}
+	// pending contains the notifications which haven't been delivered to the
+	// session yet.
+	pending []Notification
+}
+
+// Listen registers the listener on a channel.
+func (l *Listener) Listen(channel string) {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	if l.channels == nil {
+		l.channels = make(map[string]struct{})
+	}
+	l.channels[channel] = struct{}{}
+	listeners, ok := l.r.mu.listeners[channel]
+	if !ok {
+		listeners = make(map[*Listener]struct{})
+		l.r.mu.listeners[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+}
+
+// Unlisten unregisters the listener from a channel.
+func (l *Listener) Unlisten(channel string) {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	l.unlistenLocked(channel)
+}
+
+// UnlistenAll unregisters the listener from all its channels.
+func (l *Listener) UnlistenAll() {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	for channel := range l.channels {
+		l.unlistenLocked(channel)
+	}
+}
+
+func (l *Listener) unlistenLocked(channel string) {
+	delete(l.channels, channel)
+	if listeners, ok := l.r.mu.listeners[channel]; ok {
+		delete(listeners, l)
+		if len(listeners) == 0 {
+			delete(l.r.mu.listeners, channel)
+		}
+	}
+}
+
+// Channels returns the channels the listener is registered on, in
+// alphabetical order.
+func (l *Listener) Channels() []string {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	res := make([]string, 0, len(l.channels))
+	for channel := range l.channels {
+		res = append(res, channel)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// HasPending returns true if there are notifications waiting to be delivered
+// to the session.
+func (l *Listener) HasPending() bool {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	return len(l.pending) > 0
+}
+
+// TakePending returns the notifications waiting to be delivered to the
+// session, and forgets about them.
+func (l *Listener) TakePending() []Notification {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	res := l.pending
+	l.pending = nil
+	return res
+}
+
+// Close unregisters the listener from all its channels and discards its
+// pending notifications.
+func (l *Listener) Close() {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	for channel := range l.channels {
+		l.unlistenLocked(channel)
+	}
+	l.pending = nil
+}
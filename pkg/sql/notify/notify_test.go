@@ -0,0 +1,57 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	val := func(key string, wallTime int64) *roachpb.RangeFeedValue {
+		return &roachpb.RangeFeedValue{
+			Key:   roachpb.Key(key),
+			Value: roachpb.Value{Timestamp: ts(wallTime)},
+		}
+	}
+
+	d := deduplicator{frontier: ts(10)}
+	require.True(t, d.isDuplicate(val("a", 5)))
+	require.True(t, d.isDuplicate(val("a", 10)))
+	require.False(t, d.isDuplicate(val("a", 11)))
+	require.False(t, d.isDuplicate(val("b", 11)))
+	require.False(t, d.isDuplicate(val("a", 12)))
+
+	// The notifications are emitted again when the rangefeed restarts from the
+	// frontier.
+	require.True(t, d.isDuplicate(val("a", 11)))
+	require.True(t, d.isDuplicate(val("b", 11)))
+	require.True(t, d.isDuplicate(val("a", 12)))
+	require.False(t, d.isDuplicate(val("c", 12)))
+
+	// Forwarding the frontier forgets about the notifications below it.
+	d.forward(ts(11))
+	require.Len(t, d.seen, 2)
+	require.True(t, d.isDuplicate(val("b", 11)))
+	require.True(t, d.isDuplicate(val("c", 12)))
+	d.forward(ts(5))
+	require.Len(t, d.seen, 2)
+	d.forward(ts(12))
+	require.Len(t, d.seen, 0)
+	require.False(t, d.isDuplicate(val("a", 13)))
+}
@@ -125,6 +125,19 @@ type Catalog interface {
 		ctx context.Context, name *tree.UnresolvedObjectName,
 	) (*types.T, error)
 
+	// ResolveFunction locates the overloads of the user-defined function with
+	// the given name. If the name is not qualified with a schema, the schemas in
+	// the search path are tried in order, and the overloads from the first one
+	// that contains a function with that name are returned.
+	//
+	// If no such function exists, ResolveFunction returns no overloads and no
+	// error, so that the caller can fall back to the error for an unknown
+	// builtin function.
+	//
+	// NOTE: The returned functions must be immutable after construction, and
+	// so can be safely copied or used across goroutines.
+	ResolveFunction(ctx context.Context, name *tree.UnresolvedObjectName) ([]Function, error)
+
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
@@ -0,0 +1,81 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+)
+
+// Function is an interface to a single overload of a user-defined function,
+// exposing only the information needed by the query optimizer.
+type Function interface {
+	Object
+
+	// Name returns the unqualified name of the function.
+	Name() tree.Name
+
+	// ArgCount returns the number of arguments of the function.
+	ArgCount() int
+
+	// ArgName returns the name of the ith argument of the function, where
+	// i < ArgCount. The name is empty if the argument can only be referenced
+	// by position.
+	ArgName(i int) tree.Name
+
+	// ArgType returns the type of the ith argument of the function, where
+	// i < ArgCount.
+	ArgType(i int) *types.T
+
+	// ReturnType returns the type of the value returned by the function.
+	ReturnType() *types.T
+
+	// Volatility returns the volatility of the function, as declared by the
+	// user.
+	Volatility() tree.Volatility
+
+	// CalledOnNullInput returns true if the function is evaluated when any of
+	// its arguments is NULL. If false, the function returns NULL in that case
+	// without being evaluated.
+	CalledOnNullInput() bool
+
+	// Body returns the SQL text of the SELECT statement which defines the
+	// function.
+	Body() string
+}
+
+// FormatFunction nicely formats a catalog function using a treeprinter for
+// debugging and testing.
+func FormatFunction(fn Function, tp treeprinter.Node) {
+	var buf bytes.Buffer
+	for i, n := 0, fn.ArgCount(); i < n; i++ {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		if name := fn.ArgName(i); name != "" {
+			fmt.Fprintf(&buf, "%s ", name.String())
+		}
+		buf.WriteString(fn.ArgType(i).SQLString())
+	}
+	var strict string
+	if !fn.CalledOnNullInput() {
+		strict = " strict"
+	}
+	child := tp.Childf(
+		"FUNCTION %s(%s) -> %s [%s%s]",
+		fn.Name(), buf.String(), fn.ReturnType().SQLString(), fn.Volatility(), strict,
+	)
+	child.Child(fn.Body())
+}
@@ -131,6 +131,23 @@ type Table interface {
 	// Unique returns the ith unique constraint defined on this table, where
 	// i < UniqueCount.
 	Unique(i int) UniqueConstraint
+
+	// ExclusionConstraintCount returns the number of exclusion constraints
+	// defined on this table.
+	ExclusionConstraintCount() int
+
+	// ExclusionConstraint returns the ith exclusion constraint defined on this
+	// table, where i < ExclusionConstraintCount.
+	ExclusionConstraint(i int) ExclusionConstraint
+
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount. Triggers are ordered by name, which is the order in
+	// which they fire.
+	Trigger(i int) Trigger
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -244,6 +261,14 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrable is true if the checks of the constraint can be deferred until
+	// the end of the transaction.
+	Deferrable() bool
+
+	// InitiallyDeferred is true if the checks of the constraint are deferred
+	// until the end of the transaction unless changed with SET CONSTRAINTS.
+	InitiallyDeferred() bool
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -277,4 +302,66 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrable is true if the checks of the constraint can be deferred until
+	// the end of the transaction. Only unique constraints without an index can
+	// be deferrable.
+	Deferrable() bool
+
+	// InitiallyDeferred is true if the checks of the constraint are deferred
+	// until the end of the transaction unless changed with SET CONSTRAINTS.
+	InitiallyDeferred() bool
+}
+
+// ExclusionConstraint represents an exclusion constraint, which ensures that
+// no two rows of a table are such that comparing each of the constraint's
+// columns with its operator returns true for all of them. For example, the
+// following statement prevents overlapping geometries:
+//   CREATE TABLE t (g GEOMETRY, EXCLUDE USING gist (g WITH &&));
+// Exclusion constraints are enforced by the optimizer with checks that are
+// run as postqueries, which look up the conflicting rows in the index that
+// backs the constraint (see FindExclusionConstraintIndex).
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the comparison operator of the ith column in this
+	// constraint. It is either tree.EQ or tree.Overlaps.
+	Operator(i int) tree.ComparisonOperator
+}
+
+// Trigger represents a row-level trigger, which executes a SQL statement for
+// each row that is inserted, updated or deleted in a table. For example:
+//   CREATE TRIGGER audit AFTER INSERT ON t FOR EACH ROW
+//     AS 'INSERT INTO audit_log VALUES (NEW.k)';
+// Triggers are built by the optimizer as part of the mutation, and executed
+// after it similarly to foreign key cascades (or before it, for BEFORE
+// triggers).
+type Trigger interface {
+	// Name of the trigger.
+	Name() tree.Name
+
+	// ActionTime returns whether the trigger fires before or after the row is
+	// written.
+	ActionTime() tree.TriggerActionTime
+
+	// Fires returns true if the trigger fires for the given event.
+	Fires(event tree.TriggerEvent) bool
+
+	// When returns the serialized WHEN condition of the trigger, or the empty
+	// string if the trigger is unconditional. The condition references the
+	// columns of the row through NEW and OLD.
+	When() string
+
+	// Body returns the statement executed by the trigger for each row. It is a
+	// single INSERT, UPSERT, UPDATE or DELETE statement which references the
+	// columns of the row through NEW and OLD.
+	Body() string
 }
@@ -121,6 +121,38 @@ func ResolveTableIndex(
 	return found, foundTabName, nil
 }
 
+// FindExclusionConstraintIndex returns the ordinal of the first index of the
+// table which can be used to look up the rows that may conflict with a row
+// under the given exclusion constraint, and ok=false if there is none. If the
+// constraint compares any column with &&, the index must be a single-column
+// inverted index on one of those columns. Otherwise, it must be a forward
+// index whose first column is one of the constraint's columns. Partial indexes
+// are never used, since they do not contain all the rows of the table.
+func FindExclusionConstraintIndex(tab Table, ec ExclusionConstraint) (ord int, ok bool) {
+	var cols, overlapsCols util.FastIntSet
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		cols.Add(ec.ColumnOrdinal(tab, i))
+		if ec.Operator(i) == tree.Overlaps {
+			overlapsCols.Add(ec.ColumnOrdinal(tab, i))
+		}
+	}
+	for i := 0; i < tab.IndexCount(); i++ {
+		idx := tab.Index(i)
+		if _, isPartial := idx.Predicate(); isPartial {
+			continue
+		}
+		if !overlapsCols.Empty() {
+			if idx.IsInverted() && idx.NonInvertedPrefixColumnCount() == 0 &&
+				overlapsCols.Contains(idx.VirtualInvertedColumn().InvertedSourceColumnOrdinal()) {
+				return i, true
+			}
+		} else if !idx.IsInverted() && cols.Contains(idx.Column(0).Ordinal()) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 // FormatTable nicely formats a catalog table using a treeprinter for debugging
 // and testing.
 func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
@@ -173,6 +205,21 @@ func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
 		)
 	}
 
+	for i := 0; i < tab.ExclusionConstraintCount(); i++ {
+		c := tab.ExclusionConstraint(i)
+		var buf bytes.Buffer
+		buf.WriteByte('(')
+		for j := 0; j < c.ColumnCount(); j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			colName := tab.Column(c.ColumnOrdinal(tab, j)).ColName()
+			fmt.Fprintf(&buf, "%s WITH %s", colName.String(), c.Operator(j))
+		}
+		buf.WriteByte(')')
+		child.Childf("CONSTRAINT %s EXCLUDE %s", c.Name(), buf.String())
+	}
+
 	// TODO(radu): show stats.
 }
 
@@ -172,6 +172,174 @@ func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 	}
 }
 
+// setupTrigger fills in an exec.Cascade struct for the given row-level
+// trigger. Unlike a cascade, the query executed by a trigger runs once for
+// every row of the mutation input, with the values of that row.
+func (cb *cascadeBuilder) setupTrigger(trigger *memo.Trigger) (exec.Cascade, error) {
+	if cb.mutationBuffer == nil {
+		return exec.Cascade{}, errors.AssertionFailedf("triggers require the buffered mutation input")
+	}
+	oldOrds, err := cb.bufferOrdinals(trigger.OldValues)
+	if err != nil {
+		return exec.Cascade{}, err
+	}
+	newOrds, err := cb.bufferOrdinals(trigger.NewValues)
+	if err != nil {
+		return exec.Cascade{}, err
+	}
+	canaryOrd := -1
+	if trigger.CanaryCol != 0 {
+		ord, ok := cb.mutationBufferCols.Get(int(trigger.CanaryCol))
+		if !ok {
+			return exec.Cascade{}, errors.AssertionFailedf("canary column %d not in buffer", trigger.CanaryCol)
+		}
+		canaryOrd = ord
+	}
+
+	return exec.Cascade{
+		Trigger: trigger.Name,
+		Before:  trigger.Before,
+		Buffer:  cb.mutationBuffer,
+		PlanTriggerFn: func(
+			ctx context.Context,
+			semaCtx *tree.SemaContext,
+			evalCtx *tree.EvalContext,
+		) (exec.TriggerRowPlanFn, error) {
+			return cb.planTrigger(ctx, semaCtx, evalCtx, trigger, oldOrds, newOrds, canaryOrd)
+		},
+	}, nil
+}
+
+// planTrigger is used to build the query executed by a trigger. Like
+// planCascade, it is run by the execution logic (through
+// exec.Cascade.PlanTriggerFn).
+//
+// The query is built and normalized once, with placeholders in place of the
+// values of the row, similarly to a prepared statement. The returned function
+// only assigns the placeholders and finishes the optimization for each row.
+func (cb *cascadeBuilder) planTrigger(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	trigger *memo.Trigger,
+	oldOrds, newOrds []int,
+	canaryOrd int,
+) (exec.TriggerRowPlanFn, error) {
+	var o xform.Optimizer
+	o.Init(evalCtx, cb.b.catalog)
+	factory := o.Factory()
+
+	when, relExpr, placeholderTypes, err := trigger.Builder.Build(
+		ctx, semaCtx, evalCtx, cb.b.catalog, factory,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while building trigger %q", trigger.Name)
+	}
+	o.Memo().SetRoot(relExpr, &physical.Required{})
+	prepared := o.Memo()
+
+	return func(
+		ctx context.Context,
+		evalCtx *tree.EvalContext,
+		execFactory exec.Factory,
+		row tree.Datums,
+		allowAutoCommit bool,
+	) (exec.Plan, error) {
+		if canaryOrd != -1 {
+			// The canary column is NULL for the rows that are inserted by an
+			// upsert, and not NULL for the rows that are updated.
+			inserted := row[canaryOrd] == tree.DNull
+			if inserted != (trigger.Event == tree.TriggerInsert) {
+				return nil, nil
+			}
+		}
+
+		numCols := len(placeholderTypes) / 2
+		values := make(tree.QueryArguments, 0, len(placeholderTypes))
+		values = appendTriggerRowValues(values, row, oldOrds, numCols)
+		values = appendTriggerRowValues(values, row, newOrds, numCols)
+		evalCtx = evalCtx.Copy()
+		evalCtx.Placeholders = &tree.PlaceholderInfo{
+			PlaceholderTypesInfo: tree.PlaceholderTypesInfo{Types: placeholderTypes},
+			Values:               values,
+		}
+
+		if when != nil {
+			res, err := when.Eval(evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			if res != tree.DBoolTrue {
+				// The trigger does not fire for this row.
+				return nil, nil
+			}
+		}
+
+		var o xform.Optimizer
+		o.Init(evalCtx, cb.b.catalog)
+		factory := o.Factory()
+		factory.FoldingControl().AllowStableFolds()
+		if err := factory.AssignPlaceholders(prepared); err != nil {
+			return nil, errors.Wrap(err, "while assigning trigger row values")
+		}
+		optimizedExpr, err := o.Optimize()
+		if err != nil {
+			return nil, errors.Wrap(err, "while optimizing trigger expression")
+		}
+
+		eb := New(execFactory, factory.Memo(), cb.b.catalog, optimizedExpr, evalCtx, allowAutoCommit)
+		plan, err := eb.Build()
+		if err != nil {
+			return nil, errors.Wrap(err, "while building trigger plan")
+		}
+		return plan, nil
+	}, nil
+}
+
+// bufferOrdinals maps the given mutation input columns to column ordinals in
+// the buffer node. Zero column IDs are mapped to -1. It returns nil if the
+// list is nil.
+func (cb *cascadeBuilder) bufferOrdinals(cols opt.ColList) ([]int, error) {
+	if cols == nil {
+		return nil, nil
+	}
+	res := make([]int, len(cols))
+	for i, col := range cols {
+		if col == 0 {
+			res[i] = -1
+			continue
+		}
+		ord, ok := cb.mutationBufferCols.Get(int(col))
+		if !ok {
+			return nil, errors.AssertionFailedf("column %d not in buffer", col)
+		}
+		res[i] = ord
+	}
+	return res, nil
+}
+
+// appendTriggerRowValues appends the values at the given ordinals of a
+// buffered row, or numCols NULL values if ords is nil. Ordinals equal to -1
+// get NULL values.
+func appendTriggerRowValues(
+	values tree.QueryArguments, row tree.Datums, ords []int, numCols int,
+) tree.QueryArguments {
+	if ords == nil {
+		for i := 0; i < numCols; i++ {
+			values = append(values, tree.DNull)
+		}
+		return values
+	}
+	for _, ord := range ords {
+		if ord == -1 {
+			values = append(values, tree.DNull)
+		} else {
+			values = append(values, row[ord])
+		}
+	}
+	return values
+}
+
 // planCascade is used to plan a cascade query. It is NOT run while
 // planning the query; it is run by the execution logic (through
 // exec.Cascade.PlanFn) after the main query was executed.
@@ -72,6 +72,20 @@ func (b *Builder) buildMutationInput(
 
 		b.addBuiltWithExpr(p.WithID, input.outputCols, bufferNode)
 		input.root = bufferNode
+
+		if hasBeforeTriggers(p.Triggers) {
+			// BEFORE triggers are executed before the mutation writes any row, so
+			// the input is buffered ahead of time by a subquery, and the mutation
+			// reads the buffered rows.
+			b.subqueries = append(b.subqueries, exec.Subquery{
+				Mode: exec.SubqueryAllRows,
+				Root: bufferNode,
+			})
+			input.root, err = b.factory.ConstructScanBuffer(bufferNode, label)
+			if err != nil {
+				return execPlan{}, err
+			}
+		}
 	}
 	return input, nil
 }
@@ -104,7 +118,7 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		returnOrds,
 		checkOrds,
 		b.allowAutoCommit && len(ins.UniqueChecks) == 0 &&
-			len(ins.FKChecks) == 0 && len(ins.FKCascades) == 0,
+			len(ins.FKChecks) == 0 && len(ins.FKCascades) == 0 && len(ins.Triggers) == 0,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -115,12 +129,18 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		ep.outputCols = mutationOutputColMap(ins)
 	}
 
-	// TODO(rytaft): build unique checks.
+	if err := b.buildUniqueChecks(ins.UniqueChecks); err != nil {
+		return execPlan{}, err
+	}
 
 	if err := b.buildFKChecks(ins.FKChecks); err != nil {
 		return execPlan{}, err
 	}
 
+	if err := b.buildTriggers(ins.WithID, ins.Triggers); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -148,6 +168,16 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	//  - there are no uniqueness checks;
+	if len(ins.UniqueChecks) > 0 {
+		return execPlan{}, false, nil
+	}
+
+	//  - there are no row-level triggers;
+	if len(ins.Triggers) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
@@ -331,13 +361,15 @@ func (b *Builder) buildUpdate(upd *memo.UpdateExpr) (execPlan, error) {
 		checkOrds,
 		passthroughCols,
 		b.allowAutoCommit && len(upd.UniqueChecks) == 0 &&
-			len(upd.FKChecks) == 0 && len(upd.FKCascades) == 0,
+			len(upd.FKChecks) == 0 && len(upd.FKCascades) == 0 && len(upd.Triggers) == 0,
 	)
 	if err != nil {
 		return execPlan{}, err
 	}
 
-	// TODO(rytaft): build unique checks.
+	if err := b.buildUniqueChecks(upd.UniqueChecks); err != nil {
+		return execPlan{}, err
+	}
 
 	if err := b.buildFKChecks(upd.FKChecks); err != nil {
 		return execPlan{}, err
@@ -347,6 +379,10 @@ func (b *Builder) buildUpdate(upd *memo.UpdateExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildTriggers(upd.WithID, upd.Triggers); err != nil {
+		return execPlan{}, err
+	}
+
 	// Construct the output column map.
 	ep := execPlan{root: node}
 	if upd.NeedResults() {
@@ -415,13 +451,15 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		returnColOrds,
 		checkOrds,
 		b.allowAutoCommit && len(ups.UniqueChecks) == 0 &&
-			len(ups.FKChecks) == 0 && len(ups.FKCascades) == 0,
+			len(ups.FKChecks) == 0 && len(ups.FKCascades) == 0 && len(ups.Triggers) == 0,
 	)
 	if err != nil {
 		return execPlan{}, err
 	}
 
-	// TODO(rytaft): build unique checks.
+	if err := b.buildUniqueChecks(ups.UniqueChecks); err != nil {
+		return execPlan{}, err
+	}
 
 	if err := b.buildFKChecks(ups.FKChecks); err != nil {
 		return execPlan{}, err
@@ -431,6 +469,10 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildTriggers(ups.WithID, ups.Triggers); err != nil {
+		return execPlan{}, err
+	}
+
 	// If UPSERT returns rows, they contain all non-mutation columns from the
 	// table, in the same order they're defined in the table. Each output column
 	// value is taken from an insert, fetch, or update column, depending on the
@@ -471,7 +513,8 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 		tab,
 		fetchColOrds,
 		returnColOrds,
-		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
+		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0 &&
+			len(del.Triggers) == 0,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -485,6 +528,10 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildTriggers(del.WithID, del.Triggers); err != nil {
+		return execPlan{}, err
+	}
+
 	// Construct the output column map.
 	ep := execPlan{root: node}
 	if del.NeedResults() {
@@ -504,6 +551,11 @@ func (b *Builder) tryBuildDeleteRange(del *memo.DeleteExpr) (_ execPlan, ok bool
 		return execPlan{}, false, nil
 	}
 
+	// Triggers need the values of the deleted rows.
+	if len(del.Triggers) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	// Check for simple Scan input operator without a limit; anything else is not
 	// supported by a range delete.
 	if scan, ok := del.Input.(*memo.ScanExpr); !ok || scan.HardLimit != 0 {
@@ -778,23 +830,193 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 		if err != nil {
 			return err
 		}
+		keyValues := makeKeyValuesFn(query, c.KeyCols)
 		// Wrap the query in an error node.
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+			return mkFKCheckErr(md, c, keyValues(row))
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		if err != nil {
+			return err
+		}
+		b.checks = append(b.checks, exec.Check{
+			Plan:       node,
+			Deferrable: b.deferrableFKCheck(c, keyValues),
+		})
+	}
+	return nil
+}
+
+// makeKeyValuesFn returns a function which extracts the values of the given
+// columns from a row produced by a check query.
+func makeKeyValuesFn(query execPlan, keyCols opt.ColList) func(row tree.Datums) tree.Datums {
+	ords := make([]exec.NodeColumnOrdinal, len(keyCols))
+	for i, col := range keyCols {
+		ords[i] = query.getNodeColumnOrdinal(col)
+	}
+	return func(row tree.Datums) tree.Datums {
+		keyVals := make(tree.Datums, len(ords))
+		for i, ord := range ords {
+			keyVals[i] = row[ord]
+		}
+		return keyVals
+	}
+}
+
+// deferrableFKCheck returns the information needed to defer the given FK
+// check, or nil if the check must always be run immediately.
+func (b *Builder) deferrableFKCheck(
+	c *memo.FKChecksItem, keyValues func(row tree.Datums) tree.Datums,
+) *exec.DeferrableCheck {
+	md := b.mem.Metadata()
+	origin := md.Table(c.OriginTable)
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = origin.OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+		// As in Postgres, the RESTRICT action is never deferred, even if the
+		// constraint is deferrable.
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			return nil
+		}
+	}
+	if !fk.Deferrable() {
+		return nil
+	}
+	return &exec.DeferrableCheck{
+		Table:             origin,
+		Name:              fk.Name(),
+		InitiallyDeferred: fk.InitiallyDeferred(),
+		KeyValues:         keyValues,
+	}
+}
+
+func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
+	md := b.mem.Metadata()
+	for i := range checks {
+		c := &checks[i]
+		// Construct the query that returns uniqueness violations.
+		query, err := b.buildRelational(c.Check)
+		if err != nil {
+			return err
+		}
+		keyValues := makeKeyValuesFn(query, c.KeyCols)
+		// Wrap the query in an error node.
+		mkErr := func(row tree.Datums) error {
+			if c.Exclusion {
+				return mkExclusionCheckErr(md, c, keyValues(row))
 			}
-			return mkFKCheckErr(md, c, keyVals)
+			return mkUniqueCheckErr(md, c, keyValues(row))
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		var deferrable *exec.DeferrableCheck
+		tab := md.Table(c.Table)
+		// Exclusion constraints are never deferrable.
+		if !c.Exclusion {
+			if uc := tab.Unique(c.CheckOrdinal); uc.Deferrable() {
+				// The key columns are the constraint columns, optionally
+				// followed by the primary key columns.
+				deferrable = &exec.DeferrableCheck{
+					Table:             tab,
+					Name:              uc.Name(),
+					InitiallyDeferred: uc.InitiallyDeferred(),
+					KeyValues:         makeKeyValuesFn(query, c.KeyCols[:uc.ColumnCount()]),
+				}
+			}
+		}
+		b.checks = append(b.checks, exec.Check{Plan: node, Deferrable: deferrable})
 	}
 	return nil
 }
 
+// mkUniqueCheckErr generates a user-friendly error describing a uniqueness
+// violation. The keyVals are the values that correspond to the
+// cat.UniqueConstraint columns, optionally followed by the primary key columns.
+func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	uc := tabMeta.Table.Unique(c.CheckOrdinal)
+
+	// Generate an error of the form:
+	//   ERROR:  duplicate key value violates unique constraint "foo"
+	//   DETAIL: Key (k)=(2) already exists.
+	var msg, details bytes.Buffer
+	msg.WriteString("duplicate key value violates unique constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, uc.Name())
+
+	details.WriteString("Key (")
+	for i := 0; i < uc.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(uc.ColumnOrdinal(tabMeta.Table, i))
+		details.WriteString(string(col.ColName()))
+	}
+	details.WriteString(")=(")
+	for i := 0; i < uc.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(keyVals[i].String())
+	}
+	details.WriteString(") already exists.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.UniqueViolation, "%s", msg.String()),
+			uc.Name(),
+		),
+		details.String(),
+	)
+}
+
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values that correspond to the
+// cat.ExclusionConstraint columns.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.ExclusionConstraint(c.CheckOrdinal)
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (slots)=(ARRAY[9,10]) conflicts with an existing key.
+	var msg, details bytes.Buffer
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, ec.Name())
+
+	details.WriteString("Key (")
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		details.WriteString(string(col.ColName()))
+	}
+	details.WriteString(")=(")
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(keyVals[i].String())
+	}
+	details.WriteString(") conflicts with an existing key.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			ec.Name(),
+		),
+		details.String(),
+	)
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -902,6 +1124,37 @@ func (b *Builder) buildFKCascades(withID opt.WithID, cascades memo.FKCascades) e
 	return nil
 }
 
+// buildTriggers adds the row-level triggers of a mutation to the cascades of
+// the plan; see exec.Cascade.RowPlanFn.
+func (b *Builder) buildTriggers(withID opt.WithID, triggers memo.Triggers) error {
+	if len(triggers) == 0 {
+		return nil
+	}
+	cb, err := makeCascadeBuilder(b, withID)
+	if err != nil {
+		return err
+	}
+	for i := range triggers {
+		c, err := cb.setupTrigger(&triggers[i])
+		if err != nil {
+			return err
+		}
+		b.cascades = append(b.cascades, c)
+	}
+	return nil
+}
+
+// hasBeforeTriggers returns true if any of the given triggers fires before the
+// mutation writes its rows.
+func hasBeforeTriggers(triggers memo.Triggers) bool {
+	for i := range triggers {
+		if triggers[i].Before {
+			return true
+		}
+	}
+	return false
+}
+
 // canAutoCommit determines if it is safe to auto commit the mutation contained
 // in the expression.
 //
@@ -127,7 +127,7 @@ func (f *Factory) AnnotateNode(execNode exec.Node, id exec.ExplainAnnotationID,
 
 // ConstructPlan is part of the exec.Factory interface.
 func (f *Factory) ConstructPlan(
-	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Node,
+	root exec.Node, subqueries []exec.Subquery, cascades []exec.Cascade, checks []exec.Check,
 ) (exec.Plan, error) {
 	p := &Plan{
 		Root:       root.(*Node),
@@ -136,7 +136,7 @@ func (f *Factory) ConstructPlan(
 		Checks:     make([]*Node, len(checks)),
 	}
 	for i := range checks {
-		p.Checks[i] = checks[i].(*Node)
+		p.Checks[i] = checks[i].Plan.(*Node)
 	}
 
 	wrappedSubqueries := append([]exec.Subquery(nil), subqueries...)
@@ -149,9 +149,9 @@ func (f *Factory) ConstructPlan(
 			wrappedCascades[i].Buffer = wrappedCascades[i].Buffer.(*Node).WrappedNode()
 		}
 	}
-	wrappedChecks := make([]exec.Node, len(checks))
+	wrappedChecks := append([]exec.Check(nil), checks...)
 	for i := range wrappedChecks {
-		wrappedChecks[i] = checks[i].(*Node).WrappedNode()
+		wrappedChecks[i].Plan = wrappedChecks[i].Plan.(*Node).WrappedNode()
 	}
 	var err error
 	p.WrappedPlan, err = f.wrappedFactory.ConstructPlan(
@@ -233,6 +233,74 @@ type Cascade struct {
 		numBufferedRows int,
 		allowAutoCommit bool,
 	) (Plan, error)
+
+	// Trigger is the name of a row-level trigger. It is set if the cascade
+	// executes the trigger rather than a foreign key action; in that case,
+	// FKName and PlanFn are unset and PlanTriggerFn is used instead.
+	Trigger string
+
+	// Before is set if the trigger fires before the mutation writes its rows.
+	// The mutation input is then buffered by a subquery, and the trigger is
+	// executed after the subqueries and before the main query.
+	Before bool
+
+	// PlanTriggerFn builds the query executed by the trigger, once for all the
+	// rows of the buffer, and returns a function which creates the plan of the
+	// query for one of the rows.
+	//
+	// Like PlanFn, this method does not mutate any captured state.
+	PlanTriggerFn func(
+		ctx context.Context,
+		semaCtx *tree.SemaContext,
+		evalCtx *tree.EvalContext,
+	) (TriggerRowPlanFn, error)
+}
+
+// TriggerRowPlanFn creates the plan of the query executed by a row-level
+// trigger for one of the rows of the mutation input. It returns a nil plan if
+// the trigger does not fire for the row. Like the plans returned by
+// Cascade.PlanFn, the generated Plan can contain more cascades and checks.
+type TriggerRowPlanFn func(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	execFactory Factory,
+	row tree.Datums,
+	allowAutoCommit bool,
+) (Plan, error)
+
+// Check describes a check query, which is executed after the main query and
+// all cascades. Check queries don't return results but can generate errors
+// (e.g. foreign key check failures).
+type Check struct {
+	// Plan is the root of the check query.
+	Plan Node
+
+	// Deferrable is set if the check enforces a deferrable constraint; it is nil
+	// otherwise.
+	Deferrable *DeferrableCheck
+}
+
+// DeferrableCheck identifies the deferrable constraint that a check query
+// enforces. If the constraint is deferred in the current transaction, the
+// check query doesn't fail the statement; instead, the constraint values of
+// the violating rows it returns are validated again when the transaction
+// commits.
+type DeferrableCheck struct {
+	// Table is the table on which the constraint is defined.
+	Table cat.Table
+
+	// Name is the name of the constraint.
+	Name string
+
+	// InitiallyDeferred is true if the constraint is deferred unless changed
+	// with SET CONSTRAINTS.
+	InitiallyDeferred bool
+
+	// KeyValues returns the values of the constraint columns, in the order in
+	// which the constraint lists them, of a row returned by the check query.
+	// For foreign keys, these are the values of the origin columns or,
+	// equivalently, of the referenced columns.
+	KeyValues func(row tree.Datums) tree.Datums
 }
 
 // InsertFastPathFKCheck contains information about a foreign key check to be
@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 // JSONOrArrayToContainingSpanExpr converts a JSON or Array datum to a
@@ -65,3 +66,47 @@ func JSONOrArrayToContainingSpanExpr(
 	}
 	return nil, nil
 }
+
+// ArrayToOverlappingSpanExpr converts an Array datum to a SpanExpression that
+// represents the key ranges of arrays overlapping the given array according to
+// the Array overlaps (&&) operator, i.e. the arrays which have at least one
+// non-NULL element in common with it. If no array can overlap the given one,
+// because it is NULL or has no non-NULL elements, ArrayToOverlappingSpanExpr
+// returns nil. If the provided datum is not an Array, returns an error.
+func ArrayToOverlappingSpanExpr(evalCtx *tree.EvalContext, d tree.Datum) (*SpanExpression, error) {
+	if d == tree.DNull {
+		return nil, nil
+	}
+	arr, ok := tree.UnwrapDatum(evalCtx, d).(*tree.DArray)
+	if !ok {
+		return nil, errors.AssertionFailedf("expected an array, found %T", d)
+	}
+
+	// An array overlaps the given array if it contains any one of its
+	// elements, so the result is the union of the spans of the arrays
+	// containing each element.
+	var invExpr InvertedExpression
+	for _, elem := range arr.Array {
+		if elem == tree.DNull {
+			// NULL elements never compare equal, so they cannot overlap.
+			continue
+		}
+		elemArr := tree.NewDArray(arr.ParamTyp)
+		if err := elemArr.Append(elem); err != nil {
+			return nil, err
+		}
+		spanExpr, err := JSONOrArrayToContainingSpanExpr(evalCtx, elemArr)
+		if err != nil {
+			return nil, err
+		}
+		if invExpr == nil {
+			invExpr = spanExpr
+		} else {
+			invExpr = Or(invExpr, spanExpr)
+		}
+	}
+	if invExpr == nil {
+		return nil, nil
+	}
+	return invExpr.(*SpanExpression), nil
+}
@@ -45,6 +45,13 @@ func (j *jsonOrArrayJoinPlanner) extractInvertedJoinConditionFromLeaf(
 		}
 		return nil
 
+	case *memo.OverlapsExpr:
+		if t.Left.DataType().Family() == types.ArrayFamily &&
+			j.canExtractJSONOrArrayJoinCondition(t.Left, t.Right) {
+			return t
+		}
+		return nil
+
 	default:
 		return nil
 	}
@@ -90,11 +97,18 @@ func (j *jsonOrArrayJoinPlanner) canExtractJSONOrArrayJoinCondition(
 }
 
 // getSpanExprForJSONOrArrayIndex gets a SpanExpression that constrains a
-// json or array index according to the given constant.
+// json or array index according to the given operator and constant. The
+// operator is either Contains or, for arrays, Overlaps.
 func getSpanExprForJSONOrArrayIndex(
-	evalCtx *tree.EvalContext, d tree.Datum,
+	evalCtx *tree.EvalContext, op tree.ComparisonOperator, d tree.Datum,
 ) *invertedexpr.SpanExpression {
-	spanExpr, err := invertedexpr.JSONOrArrayToContainingSpanExpr(evalCtx, d)
+	var spanExpr *invertedexpr.SpanExpression
+	var err error
+	if op == tree.Overlaps {
+		spanExpr, err = invertedexpr.ArrayToOverlappingSpanExpr(evalCtx, d)
+	} else {
+		spanExpr, err = invertedexpr.JSONOrArrayToContainingSpanExpr(evalCtx, d)
+	}
 	if err != nil {
 		panic(err)
 	}
@@ -169,7 +183,7 @@ func NewJSONOrArrayDatumsToInvertedExpr(
 	getInvertedExprLeaf := func(expr tree.TypedExpr) (tree.TypedExpr, error) {
 		switch t := expr.(type) {
 		case *tree.ComparisonExpr:
-			if t.Operator != tree.Contains {
+			if t.Operator != tree.Contains && t.Operator != tree.Overlaps {
 				return nil, fmt.Errorf("%s cannot be index-accelerated", t)
 			}
 
@@ -180,7 +194,7 @@ func NewJSONOrArrayDatumsToInvertedExpr(
 			// it for every row.
 			var spanExpr *invertedexpr.SpanExpression
 			if d, ok := nonIndexParam.(tree.Datum); ok {
-				spanExpr = getSpanExprForJSONOrArrayIndex(evalCtx, d)
+				spanExpr = getSpanExprForJSONOrArrayIndex(evalCtx, t.Operator, d)
 			}
 
 			return &jsonOrArrayInvertedExpr{
@@ -224,7 +238,10 @@ func (g *jsonOrArrayDatumsToInvertedExpr) Convert(
 			if d == tree.DNull {
 				return nil, nil
 			}
-			return getSpanExprForJSONOrArrayIndex(g.evalCtx, d), nil
+			if spanExpr := getSpanExprForJSONOrArrayIndex(g.evalCtx, t.Operator, d); spanExpr != nil {
+				return spanExpr, nil
+			}
+			return nil, nil
 
 		default:
 			return nil, fmt.Errorf("unsupported expression %v", t)
@@ -277,7 +294,7 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 	switch t := expr.(type) {
 	// TODO(rytaft): Support JSON fetch val operator (->).
 	case *memo.ContainsExpr:
-		invertedExpr := j.extractJSONOrArrayFilterCondition(evalCtx, t.Left, t.Right)
+		invertedExpr := j.extractJSONOrArrayFilterCondition(evalCtx, tree.Contains, t.Left, t.Right)
 		if !invertedExpr.IsTight() {
 			remainingFilters = expr
 		}
@@ -286,6 +303,16 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 		// the returned pre-filter state is nil.
 		return invertedExpr, remainingFilters, nil
 
+	case *memo.OverlapsExpr:
+		if t.Left.DataType().Family() != types.ArrayFamily {
+			return invertedexpr.NonInvertedColExpression{}, expr, nil
+		}
+		invertedExpr := j.extractJSONOrArrayFilterCondition(evalCtx, tree.Overlaps, t.Left, t.Right)
+		if !invertedExpr.IsTight() {
+			remainingFilters = expr
+		}
+		return invertedExpr, remainingFilters, nil
+
 	default:
 		return invertedexpr.NonInvertedColExpression{}, expr, nil
 	}
@@ -293,10 +320,10 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 
 // extractJSONOrArrayFilterCondition extracts an InvertedExpression
 // representing an inverted filter over the given inverted index, based
-// on the given left and right expression arguments. Returns an empty
-// InvertedExpression if no inverted filter could be extracted.
+// on the given operator and left and right expression arguments. Returns an
+// empty InvertedExpression if no inverted filter could be extracted.
 func (j *jsonOrArrayFilterPlanner) extractJSONOrArrayFilterCondition(
-	evalCtx *tree.EvalContext, left, right opt.ScalarExpr,
+	evalCtx *tree.EvalContext, op tree.ComparisonOperator, left, right opt.ScalarExpr,
 ) invertedexpr.InvertedExpression {
 	// The first argument should be a variable corresponding to the index
 	// column.
@@ -325,5 +352,9 @@ func (j *jsonOrArrayFilterPlanner) extractJSONOrArrayFilterCondition(
 		}
 	}
 
-	return getSpanExprForJSONOrArrayIndex(evalCtx, d)
+	spanExpr := getSpanExprForJSONOrArrayIndex(evalCtx, op, d)
+	if spanExpr == nil {
+		return invertedexpr.NonInvertedColExpression{}
+	}
+	return spanExpr
 }
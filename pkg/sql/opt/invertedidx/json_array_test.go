@@ -90,6 +90,17 @@ func TestTryJoinJsonOrArrayIndex(t *testing.T) {
 			indexOrd:     arrayOrd,
 			invertedExpr: "array2 @> array1",
 		},
+		{
+			filters:      "array2 && array1",
+			indexOrd:     arrayOrd,
+			invertedExpr: "array2 && array1",
+		},
+		{
+			// Indexed column must be first with &&.
+			filters:      "array1 && array2",
+			indexOrd:     arrayOrd,
+			invertedExpr: "",
+		},
 		{
 			// Wrong index ordinal.
 			filters:      "json2 @> json1",
@@ -269,6 +280,28 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			indexOrd: arrayOrd,
 			ok:       false,
 		},
+		{
+			filters:  "a && '{1}'",
+			indexOrd: arrayOrd,
+			ok:       true,
+			tight:    true,
+			unique:   true,
+		},
+		{
+			// We cannot guarantee unique primary keys when an array may contain
+			// several of the elements.
+			filters:  "a && '{1, 2}'",
+			indexOrd: arrayOrd,
+			ok:       true,
+			tight:    true,
+			unique:   false,
+		},
+		{
+			// NULL elements never overlap, so there are no spans to scan.
+			filters:  "a && '{NULL}'",
+			indexOrd: arrayOrd,
+			ok:       false,
+		},
 		{
 			// Wrong index ordinal.
 			filters:  "a @> '{1}'",
@@ -970,3 +970,73 @@ type CascadeBuilder interface {
 		oldValues, newValues opt.ColList,
 	) (RelExpr, error)
 }
+
+// Triggers stores metadata necessary for building the queries executed by
+// row-level triggers.
+type Triggers []Trigger
+
+// Trigger stores metadata necessary for building the queries executed by a
+// row-level trigger for one of the events of a mutation. Like cascading
+// queries, these queries are built as needed, once the mutation input has been
+// buffered: the query is built once, and executed for each row of the buffer
+// with the values of that row assigned to the references to NEW and OLD.
+type Trigger struct {
+	// Name is the name of the trigger.
+	Name string
+
+	// Before is true if the trigger fires before the rows are written by the
+	// mutation, and false if it fires after.
+	Before bool
+
+	// Event is the event for which the trigger fires.
+	Event tree.TriggerEvent
+
+	// Builder is an object that can be used as the "optbuilder" for the
+	// queries of the trigger.
+	Builder TriggerBuilder
+
+	// OldValues are column IDs from the mutation input that correspond to the
+	// old values of the modified rows. The list maps 1-to-1 to the table
+	// columns; a zero ID indicates that the column is not available. It is empty
+	// if the event is an insertion.
+	OldValues opt.ColList
+
+	// NewValues are column IDs from the mutation input that correspond to the
+	// new values of the modified rows. The list maps 1-to-1 to the table
+	// columns; a zero ID indicates that the column is not available. It is empty
+	// if the event is a deletion.
+	NewValues opt.ColList
+
+	// CanaryCol is used with upserts, for which the same input row can either
+	// be inserted or update an existing row. If it is non-zero, the trigger only
+	// fires for the rows where the canary column is NULL if Event is an
+	// insertion, and for the rows where it is not NULL if Event is an update.
+	CanaryCol opt.ColumnID
+}
+
+// TriggerBuilder is an interface used to construct the query executed by a
+// row-level trigger.
+type TriggerBuilder interface {
+	// Build constructs the query executed by the trigger. The references to the
+	// values of the row are built as placeholders: with n table columns, the
+	// placeholder with index i refers to the old value of the i-th column, and
+	// the placeholder with index n+i to its new value. placeholderTypes contains
+	// the types of all 2n placeholders.
+	//
+	// If the trigger has a WHEN condition, it is returned as a typed expression
+	// which references the same placeholders; the trigger only fires for the
+	// rows for which it is true.
+	//
+	// The method does not mutate any captured state; it is ok to call Build
+	// concurrently (e.g. if the plan it originates from is cached and reused).
+	//
+	// Note: factory is always *norm.Factory; it is an interface{} only to avoid
+	// circular package dependencies.
+	Build(
+		ctx context.Context,
+		semaCtx *tree.SemaContext,
+		evalCtx *tree.EvalContext,
+		catalog cat.Catalog,
+		factory interface{},
+	) (when tree.TypedExpr, query RelExpr, placeholderTypes tree.PlaceholderTypes, _ error)
+}
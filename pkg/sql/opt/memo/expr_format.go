@@ -207,7 +207,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -595,26 +595,11 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		}
 		tp.Child(f.Buffer.String())
 
-		n := tp.Child("dependencies")
-		for _, dep := range t.Deps {
-			f.Buffer.Reset()
-			name := dep.DataSource.Name()
-			f.Buffer.WriteString(name.String())
-			if dep.SpecificIndex {
-				fmt.Fprintf(f.Buffer, "@%s", dep.DataSource.(cat.Table).Index(dep.Index).Name())
-			}
-			colNames, isTable := dep.GetColumnNames()
-			if len(colNames) > 0 {
-				fmt.Fprintf(f.Buffer, " [columns:")
-				for _, colName := range colNames {
-					fmt.Fprintf(f.Buffer, " %s", colName)
-				}
-				fmt.Fprintf(f.Buffer, "]")
-			} else if isTable {
-				fmt.Fprintf(f.Buffer, " [no columns]")
-			}
-			n.Child(f.Buffer.String())
-		}
+		f.formatViewDeps(tp, t.Deps)
+
+	case *CreateFunctionExpr:
+		tp.Child(t.Syntax.String())
+		f.formatViewDeps(tp, t.Deps)
 
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
@@ -1013,14 +998,25 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
-		constraint := tab.Table.Unique(t.CheckOrdinal)
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
-		for i := 0; i < constraint.ColumnCount(); i++ {
-			if i > 0 {
-				f.Buffer.WriteByte(',')
+		if t.Exclusion {
+			constraint := tab.Table.ExclusionConstraint(t.CheckOrdinal)
+			for i := 0; i < constraint.ColumnCount(); i++ {
+				if i > 0 {
+					f.Buffer.WriteByte(',')
+				}
+				col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
+				fmt.Fprintf(f.Buffer, "%s %s", col.ColName(), constraint.Operator(i))
+			}
+		} else {
+			constraint := tab.Table.Unique(t.CheckOrdinal)
+			for i := 0; i < constraint.ColumnCount(); i++ {
+				if i > 0 {
+					f.Buffer.WriteByte(',')
+				}
+				col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
+				f.Buffer.WriteString(string(col.ColName()))
 			}
-			col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
-			f.Buffer.WriteString(string(col.ColName()))
 		}
 		f.Buffer.WriteByte(')')
 
@@ -1206,6 +1202,42 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 			c.Child(p.FKCascades[i].FKName)
 		}
 	}
+	if len(p.Triggers) > 0 {
+		c := tp.Childf("triggers")
+		for i := range p.Triggers {
+			t := &p.Triggers[i]
+			if t.Before {
+				c.Childf("%s (BEFORE %s)", t.Name, t.Event)
+			} else {
+				c.Childf("%s (AFTER %s)", t.Name, t.Event)
+			}
+		}
+	}
+}
+
+// formatViewDeps adds a "dependencies" child to the given node, listing the
+// data source dependencies of a view or function definition.
+func (f *ExprFmtCtx) formatViewDeps(tp treeprinter.Node, deps opt.ViewDeps) {
+	n := tp.Child("dependencies")
+	for _, dep := range deps {
+		f.Buffer.Reset()
+		name := dep.DataSource.Name()
+		f.Buffer.WriteString(name.String())
+		if dep.SpecificIndex {
+			fmt.Fprintf(f.Buffer, "@%s", dep.DataSource.(cat.Table).Index(dep.Index).Name())
+		}
+		colNames, isTable := dep.GetColumnNames()
+		if len(colNames) > 0 {
+			fmt.Fprintf(f.Buffer, " [columns:")
+			for _, colName := range colNames {
+				fmt.Fprintf(f.Buffer, " %s", colName)
+			}
+			fmt.Fprintf(f.Buffer, "]")
+		} else if isTable {
+			fmt.Fprintf(f.Buffer, " [no columns]")
+		}
+		n.Child(f.Buffer.String())
+	}
 }
 
 // ColumnString returns the column in the same format as formatColSimple.
@@ -1389,6 +1421,10 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		schema := f.Memo.Metadata().Schema(t.Schema)
+		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.Syntax.FuncName.Object())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
@@ -497,6 +497,12 @@ func (h *hasher) HashFKCascades(val FKCascades) {
 	}
 }
 
+func (h *hasher) HashTriggers(val Triggers) {
+	for i := range val {
+		h.HashUint64(uint64(reflect.ValueOf(val[i].Builder).Pointer()))
+	}
+}
+
 func (h *hasher) HashExplainOptions(val tree.ExplainOptions) {
 	h.HashUint64(uint64(val.Mode))
 	hash := h.hash
@@ -889,6 +895,19 @@ func (h *hasher) IsFKCascadesEqual(l, r FKCascades) bool {
 	return true
 }
 
+func (h *hasher) IsTriggersEqual(l, r Triggers) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		// It's sufficient to compare the TriggerBuilder instances.
+		if l[i].Builder != r[i].Builder {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *hasher) IsExplainOptionsEqual(l, r tree.ExplainOptions) bool {
 	return l == r
 }
@@ -111,6 +111,10 @@ type Metadata struct {
 	// needed for EXPLAIN (opt, env).
 	views []cat.View
 
+	// functions stores information about the user-defined functions referenced
+	// by the query. See mdFunc for more details.
+	functions []mdFunc
+
 	// currUniqueID is the highest UniqueID that has been assigned.
 	currUniqueID UniqueID
 
@@ -130,6 +134,19 @@ type mdDep struct {
 	privileges privilegeBitmap
 }
 
+// mdFunc stores the overloads of a user-defined function that a function name
+// in the query resolved to, as well as the overloads that are invoked by the
+// query.
+type mdFunc struct {
+	name tree.UnresolvedObjectName
+
+	overloads []cat.Function
+
+	// invoked is the subset of overloads that are invoked by the query. The
+	// EXECUTE privilege is required on each of them.
+	invoked []cat.Function
+}
+
 // MDDepName stores either the unresolved DataSourceName or the StableID from
 // the query that was used to resolve a data source.
 type MDDepName struct {
@@ -180,6 +197,11 @@ func (md *Metadata) Init() {
 	}
 	md.views = md.views[:0]
 
+	for i := range md.functions {
+		md.functions[i] = mdFunc{}
+	}
+	md.functions = md.functions[:0]
+
 	md.currUniqueID = 0
 
 	md.withBindings = nil
@@ -195,6 +217,7 @@ func (md *Metadata) Init() {
 func (md *Metadata) CopyFrom(from *Metadata) {
 	if len(md.schemas) != 0 || len(md.cols) != 0 || len(md.tables) != 0 ||
 		len(md.sequences) != 0 || len(md.deps) != 0 || len(md.views) != 0 ||
+		len(md.functions) != 0 ||
 		len(md.userDefinedTypes) != 0 || len(md.userDefinedTypesSlice) != 0 {
 		panic(errors.AssertionFailedf("CopyFrom requires empty destination"))
 	}
@@ -222,6 +245,7 @@ func (md *Metadata) CopyFrom(from *Metadata) {
 	md.sequences = append(md.sequences, from.sequences...)
 	md.deps = append(md.deps, from.deps...)
 	md.views = append(md.views, from.views...)
+	md.functions = append(md.functions, from.functions...)
 	md.currUniqueID = from.currUniqueID
 
 	// We cannot copy the bound expressions; they must be rebuilt in the new memo.
@@ -307,6 +331,28 @@ func (md *Metadata) CheckDependencies(
 			privs &= ^(1 << priv)
 		}
 	}
+	// Check that all of the user-defined function names still resolve to the
+	// same overloads, and that the user can still execute the invoked ones.
+	for i := range md.functions {
+		fn := &md.functions[i]
+		toCheck, err := catalog.ResolveFunction(ctx, &fn.name)
+		if err != nil {
+			return false, err
+		}
+		if len(toCheck) != len(fn.overloads) {
+			return false, nil
+		}
+		for j := range toCheck {
+			if !toCheck[j].Equals(fn.overloads[j]) {
+				return false, nil
+			}
+		}
+		for _, invoked := range fn.invoked {
+			if err := catalog.CheckPrivilege(ctx, invoked, privilege.EXECUTE); err != nil {
+				return false, err
+			}
+		}
+	}
 	// Check that all of the user defined types present have not changed.
 	for _, typ := range md.AllUserDefinedTypes() {
 		toCheck, err := catalog.ResolveTypeByOID(ctx, typ.Oid())
@@ -324,6 +370,35 @@ func (md *Metadata) CheckDependencies(
 	return true, nil
 }
 
+// AddFunction tracks a user-defined function which is invoked by the query.
+// The name is the one that was used to resolve the given overloads, and
+// invoked is the overload which is called. If the Memo using this metadata is
+// cached, then a call to CheckDependencies can detect if the name resolves to
+// different overloads now, or if the user no longer has the privilege to
+// execute the function.
+func (md *Metadata) AddFunction(
+	name *tree.UnresolvedObjectName, overloads []cat.Function, invoked cat.Function,
+) {
+	for i := range md.functions {
+		fn := &md.functions[i]
+		if name.NumParts != fn.name.NumParts || name.Parts != fn.name.Parts {
+			continue
+		}
+		for _, o := range fn.invoked {
+			if o == invoked {
+				return
+			}
+		}
+		fn.invoked = append(fn.invoked, invoked)
+		return
+	}
+	md.functions = append(md.functions, mdFunc{
+		name:      *name,
+		overloads: overloads,
+		invoked:   []cat.Function{invoked},
+	})
+}
+
 // AddSchema indexes a new reference to a schema used by the query.
 func (md *Metadata) AddSchema(sch cat.Schema) SchemaID {
 	md.schemas = append(md.schemas, sch)
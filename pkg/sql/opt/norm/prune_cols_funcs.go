@@ -67,6 +67,10 @@ func (c *CustomFuncs) NeededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	for i := range private.Triggers {
+		addCols(private.Triggers[i].OldValues)
+		addCols(private.Triggers[i].NewValues)
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -141,6 +145,21 @@ func neededMutationFetchCols(
 		}
 	}
 
+	// Retain any FetchCols that are passed to triggers as the old values of the
+	// rows, or as the new values of columns that are not updated.
+	for i := range private.Triggers {
+		t := &private.Triggers[i]
+		for ord, col := range private.FetchCols {
+			if col == 0 {
+				continue
+			}
+			if (len(t.OldValues) > 0 && t.OldValues[ord] == col) ||
+				(len(t.NewValues) > 0 && t.NewValues[ord] == col) {
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
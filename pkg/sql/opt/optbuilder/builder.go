@@ -108,8 +108,23 @@ type Builder struct {
 	// are disabled and certain statements (like mutations) are disallowed.
 	insideViewDef bool
 
+	// If set, we are processing the body of a function definition; in this
+	// case, certain statements (like mutations) are disallowed, and
+	// placeholders refer to the arguments of the function.
+	insideFuncDef bool
+
+	// If set, we are building the body of a user-defined function which is
+	// invoked by the query. Placeholders refer to the arguments of the
+	// function.
+	insideFuncBody bool
+
+	// If set, we are building the statement executed by a row-level trigger
+	// for a single row, and references to NEW and OLD are replaced by the
+	// values of that row.
+	trigger *triggerRow
+
 	// If set, we are collecting view dependencies in viewDeps. This can only
-	// happen inside view definitions.
+	// happen inside view or function definitions.
 	//
 	// When a view depends on another view, we only want to track the dependency
 	// on the inner view itself, and not the transitive dependencies (so
@@ -252,13 +267,24 @@ func (b *Builder) buildStmt(
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
 		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
-			*tree.Split, *tree.Unsplit, *tree.Relocate,
+			*tree.CreateFunction, *tree.Split, *tree.Unsplit, *tree.Relocate,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
 				pgcode.Syntax, "%s cannot be used inside a view definition", stmt.StatementTag(),
 			))
 		}
 	}
+	if b.insideFuncDef {
+		// A blocklist of statements that can't be used from inside a function.
+		switch stmt := stmt.(type) {
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+			*tree.CreateFunction, *tree.Split, *tree.Unsplit, *tree.Relocate,
+			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
+			panic(pgerror.Newf(
+				pgcode.Syntax, "%s cannot be used inside a function definition", stmt.StatementTag(),
+			))
+		}
+	}
 
 	switch stmt := stmt.(type) {
 	case *tree.Select:
@@ -288,6 +314,9 @@ func (b *Builder) buildStmt(
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
+	case *tree.CreateFunction:
+		return b.buildCreateFunction(stmt, inScope)
+
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
@@ -0,0 +1,153 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+func (b *Builder) buildCreateFunction(
+	cf *tree.CreateFunction, inScope *scope,
+) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := cf.FuncName.ToTableName()
+	sch, resName := b.resolveSchemaForCreate(&tn)
+	schID := b.factory.Metadata().AddSchema(sch)
+
+	var body string
+	for _, option := range cf.Options {
+		if t, ok := option.(tree.FunctionBody); ok {
+			body = string(t)
+		}
+	}
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		panic(err)
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(unimplemented.NewWithIssuef(17511,
+			"%s statements are not supported in function bodies", stmt.AST.StatementTag()))
+	}
+
+	// The arguments of the function are made available to the body as NULL
+	// constants of the argument types. They can be referenced either by name or
+	// by position.
+	paramScope := b.allocScope()
+	for i := range cf.Args {
+		typ, err := tree.ResolveType(b.ctx, cf.Args[i].Type, b.semaCtx.GetTypeResolver())
+		if err != nil {
+			panic(err)
+		}
+		posName := fmt.Sprintf("$%d", i+1)
+		name := string(cf.Args[i].Name)
+		if name == "" {
+			name = posName
+		}
+		col := b.synthesizeColumn(paramScope, name, typ, nil /* expr */, b.factory.ConstructNull(typ))
+		if name != posName {
+			posCol := *col
+			posCol.name = tree.Name(posName)
+			posCol.scalar = nil
+			paramScope.cols = append(paramScope.cols, posCol)
+		}
+	}
+	paramScope.setTableAlias(tree.Name(cf.FuncName.Object()))
+	paramScope.expr = b.constructProject(
+		b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+			Cols: opt.ColList{},
+			ID:   b.factory.Metadata().NextUniqueID(),
+		}),
+		paramScope.cols,
+	)
+
+	retType, err := tree.ResolveType(b.ctx, cf.ReturnType, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+
+	// We build the body of the function to:
+	//  - check the statement semantically,
+	//  - get the fully resolved names into the AST, and
+	//  - collect the function dependencies in b.viewDeps.
+	// The result is not otherwise used.
+	b.insideFuncDef = true
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+	defer func(annotations tree.Annotations) {
+		b.insideFuncDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.qualifyDataSourceNamesInAST = false
+		b.semaCtx.Annotations = annotations
+	}(b.semaCtx.Annotations)
+	b.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
+
+	b.pushWithFrame()
+	defScope := b.buildStmtAtRoot(sel, []*types.T{retType}, paramScope.push())
+	b.popWithFrame(defScope)
+
+	p := defScope.makePhysicalProps().Presentation
+	if len(p) != 1 {
+		panic(errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", retType),
+			"Final statement must return exactly one column.",
+		))
+	}
+	colType := b.factory.Metadata().ColumnMeta(p[0].ID).Type
+	if colType.Family() != types.UnknownFamily && !colType.Equivalent(retType) {
+		if _, ok := tree.LookupCastVolatility(colType, retType); !ok {
+			panic(errors.WithDetailf(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"return type mismatch in function declared to return %s", retType),
+				"Actual return type is %s.", colType,
+			))
+		}
+	}
+
+	// Store the function with its fully qualified name and body.
+	syntax := *cf
+	syntax.FuncName, err = tree.NewUnresolvedObjectName(3, /* numParts */
+		[3]string{cf.FuncName.Object(), string(resName.SchemaName), string(resName.CatalogName)},
+		tree.NoAnnotation,
+	)
+	if err != nil {
+		panic(err)
+	}
+	syntax.Options = make(tree.FunctionOptions, len(cf.Options))
+	for i, option := range cf.Options {
+		if _, ok := option.(tree.FunctionBody); ok {
+			option = tree.FunctionBody(tree.AsStringWithFlags(sel, tree.FmtParsable))
+		}
+		syntax.Options[i] = option
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema: schID,
+			Syntax: &syntax,
+			Deps:   b.viewDeps,
+		},
+	)
+	return outScope
+}
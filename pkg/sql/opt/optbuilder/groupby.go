@@ -45,6 +45,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -96,6 +98,22 @@ type groupby struct {
 	// projects that expression.
 	groupStrs groupByStrSet
 
+	// groupingSets contains the IDs of the grouping columns that make up each
+	// of the grouping sets specified by the GROUP BY clause. A GROUP BY clause
+	// without ROLLUP, CUBE or GROUPING SETS has a single grouping set. For
+	// example:
+	//
+	//   SELECT a, b, c, count(*) FROM t GROUP BY a, ROLLUP (b, c)
+	//
+	// has the grouping sets (a, b, c), (a, b) and (a).
+	groupingSets []opt.ColSet
+
+	// groupingSetCol is the column that identifies which grouping set each row
+	// passed to the GroupBy operator belongs to, as an ordinal into
+	// groupingSets. It is only set if there are multiple grouping sets. See
+	// constructGroupingSetsInput.
+	groupingSetCol opt.ColumnID
+
 	// buildingGroupingCols is true while the grouping columns are being built.
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
@@ -127,6 +145,12 @@ func (g *groupby) hasNonCommutativeAggregates() bool {
 	return false
 }
 
+// hasGroupingSets returns true if the rows of the GroupBy operator are grouped
+// by multiple grouping sets.
+func (g *groupby) hasGroupingSets() bool {
+	return len(g.groupingSets) > 1
+}
+
 // groupingCols returns the columns in the aggInScope corresponding to grouping
 // columns.
 func (g *groupby) groupingCols() []scopeColumn {
@@ -354,9 +378,22 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.hasGroupingSets() {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates are not supported with ROLLUP, CUBE or GROUPING SETS"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
+	// With multiple grouping sets, the input rows are replicated for each
+	// grouping set, and the grouping set column becomes an additional grouping
+	// column which keeps the groups of different grouping sets apart.
+	var groupingSetRowCol opt.ColumnID
+	if g.hasGroupingSets() {
+		groupingSetRowCol = b.constructGroupingSetsInput(fromScope)
+		groupingColSet.Add(g.groupingSetCol)
+	}
+
 	aggInfos := g.aggs
 
 	// Construct the aggregation operators.
@@ -397,12 +434,20 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		// if FILTER (WHERE ...) was specified in the query.
 		// TODO(justin): add a norm rule to push these filters below GroupBy where
 		// possible.
+		var filterCol opt.ColumnID
 		if agg.filter != nil {
 			// Column containing filter expression is always after the argument
 			// columns (which have already been processed).
-			colID := argCols[0].id
+			filterCol = argCols[0].id
 			argCols = argCols[1:]
-			variable := b.factory.ConstructVariable(colID)
+		}
+		if groupingSetRowCol != 0 {
+			// The aggregate must ignore the placeholder rows which are added for
+			// empty grouping sets (see constructGroupingSetsInput).
+			filterCol = b.constructGroupingSetsAggFilter(g, filterCol, groupingSetRowCol)
+		}
+		if filterCol != 0 {
+			variable := b.factory.ConstructVariable(filterCol)
 			aggCols[i].scalar = b.factory.ConstructAggFilter(aggCols[i].scalar, variable)
 		}
 
@@ -430,6 +475,19 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
+	// When there are placeholder rows for empty grouping sets, determine which
+	// groups contain actual input rows, so that the groups which only contain
+	// placeholder rows can be discarded below.
+	var groupingSetHasRowsCol opt.ColumnID
+	if groupingSetRowCol != 0 {
+		groupingSetHasRowsCol = b.factory.Metadata().AddColumn("grouping_set_has_rows", types.Bool)
+		aggCols = append(aggCols[:len(aggCols):len(aggCols)], scopeColumn{
+			typ:    types.Bool,
+			id:     groupingSetHasRowsCol,
+			scalar: b.factory.ConstructBoolOr(b.factory.ConstructVariable(groupingSetRowCol)),
+		})
+	}
+
 	g.aggOutScope.expr = b.constructGroupBy(
 		g.aggInScope.expr.(memo.RelExpr),
 		groupingColSet,
@@ -437,6 +495,19 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		g.aggInScope.ordering,
 	)
 
+	if groupingSetHasRowsCol != 0 {
+		input := g.aggOutScope.expr.(memo.RelExpr)
+		filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(
+			b.factory.ConstructOr(
+				b.factory.ConstructVariable(groupingSetHasRowsCol),
+				b.constructGroupingSetIn(g, len(g.groupingSets), func(i int) bool {
+					return g.groupingSets[i].Empty()
+				}),
+			),
+		)}
+		g.aggOutScope.expr = b.factory.ConstructSelect(input, filters)
+	}
+
 	// Wrap with having filter if it exists.
 	if having != nil {
 		input := g.aggOutScope.expr.(memo.RelExpr)
@@ -444,9 +515,129 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		g.aggOutScope.expr = b.factory.ConstructSelect(input, filters)
 	}
 
+	if g.hasGroupingSets() {
+		// Project away the grouping set columns.
+		g.aggOutScope.expr = b.factory.ConstructProject(
+			g.aggOutScope.expr.(memo.RelExpr), nil /* projections */, g.aggOutScope.colSet(),
+		)
+	}
+
 	return g.aggOutScope
 }
 
+// constructGroupingSetsInput replaces the input of the aggregation with one
+// in which each input row is repeated once for each grouping set, tagged with
+// the ordinal of the grouping set in the grouping set column. For example:
+//
+//   SELECT a, b, sum(c) FROM t GROUP BY GROUPING SETS ((a, b), (a), (b))
+//
+//   inner-join (cross)
+//    ├── scan t
+//    └── values [grouping_set]
+//         ├── (0,)
+//         ├── (1,)
+//         └── (2,)
+//
+// The grouping columns are NULL for the rows of grouping sets they are not
+// part of (see buildGroupingSets), so a single GroupBy operator which groups
+// by the grouping columns and the grouping set column computes the
+// aggregations for all of the grouping sets in one pass over the input.
+//
+// An empty grouping set produces a result row even if the input has no rows.
+// If there is an empty grouping set, the grouping set ordinals are therefore
+// left-joined with the input instead, which produces a placeholder row for
+// each grouping set if the input is empty. The input rows are marked by a
+// column which is NULL for placeholder rows; its ID is returned, or 0 if there
+// is no empty grouping set.
+func (b *Builder) constructGroupingSetsInput(fromScope *scope) (groupingSetRowCol opt.ColumnID) {
+	g := fromScope.groupby
+	md := b.factory.Metadata()
+
+	tupleTyp := types.MakeTuple([]*types.T{types.Int})
+	rows := make(memo.ScalarListExpr, len(g.groupingSets))
+	hasEmptySet := false
+	for i := range g.groupingSets {
+		rows[i] = b.factory.ConstructTuple(
+			memo.ScalarListExpr{b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int)},
+			tupleTyp,
+		)
+		if g.groupingSets[i].Empty() {
+			hasEmptySet = true
+		}
+	}
+	values := b.factory.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{g.groupingSetCol},
+		ID:   md.NextUniqueID(),
+	})
+	g.aggInScope.extraCols = append(g.aggInScope.extraCols, scopeColumn{
+		typ: types.Int,
+		id:  g.groupingSetCol,
+	})
+
+	input := fromScope.expr.(memo.RelExpr)
+	if !hasEmptySet {
+		fromScope.expr = b.factory.ConstructInnerJoin(input, values, memo.TrueFilter, memo.EmptyJoinPrivate)
+		return 0
+	}
+
+	groupingSetRowCol = md.AddColumn("grouping_set_row", types.Bool)
+	input = b.factory.ConstructProject(
+		input,
+		memo.ProjectionsExpr{b.factory.ConstructProjectionsItem(memo.TrueSingleton, groupingSetRowCol)},
+		input.Relational().OutputCols,
+	)
+	fromScope.expr = b.factory.ConstructLeftJoin(values, input, memo.TrueFilter, memo.EmptyJoinPrivate)
+	g.aggInScope.extraCols = append(g.aggInScope.extraCols, scopeColumn{
+		typ: types.Bool,
+		id:  groupingSetRowCol,
+	})
+	return groupingSetRowCol
+}
+
+// constructGroupingSetsAggFilter returns the column to use as the filter of an
+// aggregate so that it ignores the placeholder rows of empty grouping sets.
+// filterCol is the column of the aggregate's own FILTER clause, or 0 if it has
+// none.
+func (b *Builder) constructGroupingSetsAggFilter(
+	g *groupby, filterCol, groupingSetRowCol opt.ColumnID,
+) opt.ColumnID {
+	if filterCol == 0 {
+		return groupingSetRowCol
+	}
+	col := scopeColumn{
+		typ: types.Bool,
+		id:  b.factory.Metadata().AddColumn("filter", types.Bool),
+		scalar: b.factory.ConstructAnd(
+			b.factory.ConstructVariable(filterCol),
+			b.factory.ConstructVariable(groupingSetRowCol),
+		),
+	}
+	g.aggInScope.extraCols = append(g.aggInScope.extraCols, col)
+	return col.id
+}
+
+// constructGroupingSetIn returns an expression which is true for the rows of
+// the grouping sets for whose ordinals include returns true.
+func (b *Builder) constructGroupingSetIn(
+	g *groupby, numSets int, include func(ord int) bool,
+) opt.ScalarExpr {
+	var ords memo.ScalarListExpr
+	var typs []*types.T
+	for i := 0; i < numSets; i++ {
+		if include(i) {
+			ords = append(ords, b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int))
+			typs = append(typs, types.Int)
+		}
+	}
+	if len(ords) == 0 {
+		return memo.FalseSingleton
+	}
+	return b.factory.ConstructIn(
+		b.factory.ConstructVariable(g.groupingSetCol),
+		b.factory.ConstructTuple(ords, types.MakeTuple(typs)),
+	)
+}
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -507,16 +698,231 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
+	defer func() { g.buildingGroupingCols = false }()
+
+	// We need to save and restore the previous value of the field in semaCtx
+	// in case we are recursively called within a subquery context.
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+
+	// Make sure the GROUP BY columns have no special functions.
+	b.semaCtx.Properties.Require(exprKindGroupBy.String(), tree.RejectSpecial)
+	fromScope.context = exprKindGroupBy
+
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSets); ok {
+			b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+			return
+		}
+	}
+
 	for _, e := range groupBy {
 		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
 	}
-	g.buildingGroupingCols = false
+	var groupingSet opt.ColSet
+	for _, col := range g.groupingCols() {
+		groupingSet.Add(col.id)
+	}
+	g.groupingSets = []opt.ColSet{groupingSet}
+}
+
+// maxGroupingSets is the maximum number of grouping sets a GROUP BY clause
+// can expand to. It matches the limit in Postgres.
+const maxGroupingSets = 4096
+
+// buildGroupingSets is the equivalent of the loop over the GROUP BY
+// expressions in buildGroupingList for a GROUP BY clause which contains
+// ROLLUP, CUBE or GROUPING SETS. It expands the clause into the list of
+// grouping sets, which is the cross product of the grouping sets of each of
+// its elements, and populates groupingSets. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//   =>
+//   grouping sets: (a, b, c), (a, b), (a)
+//
+// A grouping expression which is not part of every grouping set is built as a
+// column which is NULL for the rows of the grouping sets it is not part of:
+//
+//   aggInScope: a, b, CASE WHEN grouping_set IN (0) THEN c ELSE NULL END
+//
+// See constructGroupingSetsInput for how the rows for each grouping set are
+// produced.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) {
+	g := fromScope.groupby
+
+	// Resolve all grouping expressions, deduplicating them, and represent each
+	// grouping set as a set of ordinals into exprs.
+	type groupingExpr struct {
+		expr    tree.TypedExpr
+		alias   string
+		exprStr string
+	}
+	var exprs []groupingExpr
+	ords := make(map[string]int)
+	resolve := func(e tree.Expr) (set util.FastIntSet) {
+		resolved, alias := b.resolveGrouping(e, selects, projectionsScope, fromScope)
+		for _, r := range resolved {
+			exprStr := symbolicExprStr(r)
+			ord, ok := ords[exprStr]
+			if !ok {
+				ord = len(exprs)
+				ords[exprStr] = ord
+				exprs = append(exprs, groupingExpr{expr: r, alias: alias, exprStr: exprStr})
+			}
+			set.Add(ord)
+		}
+		return set
+	}
+
+	sets := []util.FastIntSet{{}}
+	for _, e := range groupBy {
+		elemSets := expandGroupingSets(e, resolve)
+		if len(sets)*len(elemSets) > maxGroupingSets {
+			panic(pgerror.Newf(pgcode.StatementTooComplex,
+				"too many grouping sets present (maximum %d)", maxGroupingSets))
+		}
+		product := make([]util.FastIntSet, 0, len(sets)*len(elemSets))
+		for _, set := range sets {
+			for _, elemSet := range elemSets {
+				product = append(product, set.Union(elemSet))
+			}
+		}
+		sets = product
+	}
+
+	inAllSets := sets[0].Copy()
+	for _, set := range sets[1:] {
+		inAllSets.IntersectionWith(set)
+	}
+	if len(sets) > 1 {
+		g.groupingSetCol = b.factory.Metadata().AddColumn("grouping_set", types.Int)
+	}
+
+	// Build each of the GROUP BY columns.
+	cols := make([]opt.ColumnID, len(exprs))
+	for ord, e := range exprs {
+		var col *scopeColumn
+		if inAllSets.Contains(ord) {
+			col = b.addColumn(g.aggInScope, e.alias, e.expr)
+			b.buildScalar(e.expr, fromScope, g.aggInScope, col, nil)
+		} else {
+			scalar := b.factory.ConstructCase(
+				memo.TrueSingleton,
+				memo.ScalarListExpr{b.factory.ConstructWhen(
+					b.constructGroupingSetIn(g, len(sets), func(i int) bool {
+						return sets[i].Contains(ord)
+					}),
+					b.buildScalar(e.expr, fromScope, nil, nil, nil),
+				)},
+				b.factory.ConstructNull(e.expr.ResolvedType()),
+			)
+			alias := e.alias
+			if c, ok := e.expr.(*scopeColumn); ok && alias == "" {
+				alias = string(c.name)
+			}
+			col = b.synthesizeColumn(g.aggInScope, alias, e.expr.ResolvedType(), e.expr, scalar)
+		}
+		g.groupStrs[e.exprStr] = col
+		cols[ord] = col.id
+	}
+
+	g.groupingSets = make([]opt.ColSet, len(sets))
+	for i, set := range sets {
+		set.ForEach(func(ord int) {
+			g.groupingSets[i].Add(cols[ord])
+		})
+	}
+}
+
+// expandGroupingSets returns the grouping sets of the given element of a GROUP
+// BY clause, as sets of ordinals of grouping expressions. resolve resolves an
+// expression, or a parenthesized list of expressions, to such a set.
+func expandGroupingSets(
+	e tree.Expr, resolve func(tree.Expr) util.FastIntSet,
+) []util.FastIntSet {
+	t, ok := e.(*tree.GroupingSets)
+	if !ok {
+		return []util.FastIntSet{resolve(e)}
+	}
+
+	switch t.Type {
+	case tree.RollupGroupingSets:
+		// ROLLUP (a, b, c) => (a, b, c), (a, b), (a), ()
+		sets := make([]util.FastIntSet, len(t.Exprs)+1)
+		var prefix util.FastIntSet
+		for i := range t.Exprs {
+			prefix.UnionWith(resolve(t.Exprs[i]))
+			sets[len(t.Exprs)-1-i] = prefix.Copy()
+		}
+		return sets
+
+	case tree.CubeGroupingSets:
+		// CUBE (a, b) => (a, b), (a), (b), ()
+		n := len(t.Exprs)
+		if n >= 32 || 1<<n > maxGroupingSets {
+			panic(pgerror.Newf(pgcode.StatementTooComplex,
+				"too many grouping sets present (maximum %d)", maxGroupingSets))
+		}
+		elems := make([]util.FastIntSet, n)
+		for i := range t.Exprs {
+			elems[i] = resolve(t.Exprs[i])
+		}
+		sets := make([]util.FastIntSet, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set util.FastIntSet
+			for i := range elems {
+				if mask&(1<<(n-1-i)) != 0 {
+					set.UnionWith(elems[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	case tree.ExplicitGroupingSets:
+		var sets []util.FastIntSet
+		for _, e := range t.Exprs {
+			sets = append(sets, expandGroupingSets(e, resolve)...)
+		}
+		return sets
+	}
+	panic(errors.AssertionFailedf("unknown grouping sets type %v", t.Type))
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
 // groupStrs and to the aggInScope.
 //
+// aggInScope is the scope that will contain the grouping expressions as well
+// as the aggregate function arguments. See resolveGrouping for the remaining
+// arguments.
+func (b *Builder) buildGrouping(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
+) {
+	exprs, alias := b.resolveGrouping(groupBy, selects, projectionsScope, fromScope)
+
+	// Finally, build each of the GROUP BY columns.
+	for _, e := range exprs {
+		// If a grouping column has already been added, don't add it again.
+		// GROUP BY a, a is semantically equivalent to GROUP BY a.
+		exprStr := symbolicExprStr(e)
+		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			continue
+		}
+
+		// Save a representation of the GROUP BY expression for validation of the
+		// SELECT and HAVING expressions. This enables queries such as:
+		//   SELECT x+y FROM t GROUP BY x+y
+		col := b.addColumn(aggInScope, alias, e)
+		b.buildScalar(e, fromScope, aggInScope, col, nil)
+		fromScope.groupby.groupStrs[exprStr] = col
+	}
+}
+
+// resolveGrouping resolves a GROUP BY expression, returning the resolved
+// expression (or expressions, if we have a star or a tuple) along with the
+// alias of the SELECT target it refers to, if any.
 //
 // groupBy          The given GROUP BY expression.
 // selects          The select expressions are needed in case the GROUP BY
@@ -525,14 +931,11 @@ func (b *Builder) buildGroupingList(
 //                  (used when GROUP BY refers to a target by alias).
 // fromScope        The scope for the input to the aggregation (the FROM
 //                  clause).
-// aggInScope       The scope that will contain the grouping expressions as well
-//                  as the aggregate function arguments.
-func (b *Builder) buildGrouping(
-	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+func (b *Builder) resolveGrouping(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) (exprs []tree.TypedExpr, alias string) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
-	alias := ""
 
 	// Comment below pasted from PostgreSQL (findTargetListEntrySQL92 in
 	// src/backend/parser/parse_clause.c).
@@ -610,34 +1013,9 @@ func (b *Builder) buildGrouping(
 		}
 	}()
 
-	// We need to save and restore the previous value of the field in semaCtx
-	// in case we are recursively called within a subquery context.
-	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
-
-	// Make sure the GROUP BY columns have no special functions.
-	b.semaCtx.Properties.Require(exprKindGroupBy.String(), tree.RejectSpecial)
-	fromScope.context = exprKindGroupBy
-
 	// Resolve types, expand stars, and flatten tuples.
-	exprs := b.expandStarAndResolveType(groupBy, fromScope)
-	exprs = flattenTuples(exprs)
-
-	// Finally, build each of the GROUP BY columns.
-	for _, e := range exprs {
-		// If a grouping column has already been added, don't add it again.
-		// GROUP BY a, a is semantically equivalent to GROUP BY a.
-		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
-			continue
-		}
-
-		// Save a representation of the GROUP BY expression for validation of the
-		// SELECT and HAVING expressions. This enables queries such as:
-		//   SELECT x+y FROM t GROUP BY x+y
-		col := b.addColumn(aggInScope, alias, e)
-		b.buildScalar(e, fromScope, aggInScope, col, nil)
-		fromScope.groupby.groupStrs[exprStr] = col
-	}
+	exprs = b.expandStarAndResolveType(groupBy, fromScope)
+	return flattenTuples(exprs), alias
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -755,6 +1133,78 @@ func (b *Builder) buildAggregateFunction(
 	return &info
 }
 
+// buildGroupingFunc builds a GROUPING(...) expression. Its value only depends
+// on the grouping set that produced the result row, so it is built as an
+// additional grouping column that is computed from the grouping set column.
+// For example:
+//
+//   SELECT a, b, GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)
+//   =>
+//   aggInScope: a, CASE WHEN grouping_set IN (0) THEN b ELSE NULL END,
+//               CASE grouping_set WHEN 0 THEN 0 WHEN 1 THEN 1 WHEN 2 THEN 3 END
+//
+// See Builder.buildStmt for a description of the remaining input and return
+// values.
+func (b *Builder) buildGroupingFunc(
+	f *tree.GroupingExpr, inScope, outScope *scope, outCol *scopeColumn, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	g := inScope.groupby
+	if len(f.Exprs) > 31 {
+		panic(pgerror.New(pgcode.TooManyArguments, "GROUPING must have fewer than 32 arguments"))
+	}
+
+	var allGroupingCols opt.ColSet
+	for _, set := range g.groupingSets {
+		allGroupingCols.UnionWith(set)
+	}
+	cols := make([]opt.ColumnID, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(e)]
+		if !ok || !allGroupingCols.Contains(col.id) {
+			panic(pgerror.New(pgcode.Grouping,
+				"arguments to GROUPING must be grouping expressions of the associated query level"))
+		}
+		cols[i] = col.id
+	}
+
+	// Compute the value for each grouping set. The rightmost argument
+	// corresponds to the least significant bit, which is set if the argument is
+	// not part of the grouping set.
+	masks := make([]int, len(g.groupingSets))
+	for i, set := range g.groupingSets {
+		for _, col := range cols {
+			masks[i] <<= 1
+			if !set.Contains(col) {
+				masks[i] |= 1
+			}
+		}
+	}
+
+	if !g.hasGroupingSets() {
+		scalar := b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(masks[0])), types.Int)
+		return b.finishBuildScalar(f, scalar, inScope, outScope, outCol)
+	}
+
+	whens := make(memo.ScalarListExpr, len(masks))
+	for i, mask := range masks {
+		whens[i] = b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int),
+		)
+	}
+	scalar := b.factory.ConstructCase(
+		b.factory.ConstructVariable(g.groupingSetCol), whens, b.factory.ConstructNull(types.Int),
+	)
+
+	// Add a new grouping column; these show up both in aggInScope and
+	// aggOutScope.
+	aggInCol := b.synthesizeColumn(g.aggInScope, "grouping", types.Int, f, scalar)
+	g.groupStrs[symbolicExprStr(f)] = aggInCol
+	g.aggOutScope.appendColumn(aggInCol)
+
+	return b.finishBuildScalarRef(aggInCol, g.aggOutScope, outScope, outCol, colRefs)
+}
+
 func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.ScalarExpr {
 	switch name {
 	case "rank":
@@ -903,6 +1353,11 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.hasGroupingSets() {
+		// The PK columns are NULL for the rows of the grouping sets they are not
+		// part of, so they do not determine col.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
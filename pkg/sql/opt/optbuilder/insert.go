@@ -349,6 +349,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //   2. All non-key columns (including mutation columns) have insert and update
 //      values specified for them.
 //   3. Each update value is the same as the corresponding insert value.
+//   4. There are no row-level triggers on the table.
 //
 // TODO(radu): once FKs no longer require indexes, this function will have to
 // take FKs into account explicitly.
@@ -363,6 +364,12 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	if mb.tab.TriggerCount() > 0 {
+		// Triggers need to know whether each row is inserted or updated, and
+		// need the old values of the updated rows.
+		return true
+	}
+
 	// Key columns are never updated and are assumed to be the same as the insert
 	// values.
 	// TODO(andyk): This is not true in the case of composite key encodings. See
@@ -654,8 +661,12 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForInsert()
 
+	mb.buildExclusionChecksForInsert()
+
 	mb.buildFKChecksForInsert()
 
+	mb.buildTriggersForInsert()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -1080,8 +1091,12 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 		mb.projectPartialIndexPutCols(preCheckScope)
 	}
 
+	mb.buildExclusionChecksForUpsert()
+
 	mb.buildFKChecksForUpsert()
 
+	mb.buildTriggersForUpsert()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
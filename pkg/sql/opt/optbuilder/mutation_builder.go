@@ -162,6 +162,10 @@ type mutationBuilder struct {
 	// cascades contains foreign key check cascades; see buildFK* methods.
 	cascades memo.FKCascades
 
+	// triggers contains the row-level triggers that fire for the mutation; see
+	// buildTriggers* methods.
+	triggers memo.Triggers
+
 	// withID is nonzero if we need to buffer the input for FK checks.
 	withID opt.WithID
 
@@ -176,6 +180,9 @@ type mutationBuilder struct {
 
 	// uniqueCheckHelper is used to prevent allocating the helper separately.
 	uniqueCheckHelper uniqueCheckHelper
+
+	// exclusionCheckHelper is used to prevent allocating the helper separately.
+	exclusionCheckHelper exclusionCheckHelper
 }
 
 func (mb *mutationBuilder) init(b *Builder, opName string, tab cat.Table, alias tree.TableName) {
@@ -938,10 +945,13 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		PartialIndexPutCols: checkEmptyList(mb.partialIndexPutColIDs),
 		PartialIndexDelCols: checkEmptyList(mb.partialIndexDelColIDs),
 		FKCascades:          mb.cascades,
+		Triggers:            mb.triggers,
 	}
 
-	// If we didn't actually plan any checks or cascades, don't buffer the input.
-	if len(mb.uniqueChecks) > 0 || len(mb.fkChecks) > 0 || len(mb.cascades) > 0 {
+	// If we didn't actually plan any checks, cascades or triggers, don't buffer
+	// the input.
+	if len(mb.uniqueChecks) > 0 || len(mb.fkChecks) > 0 || len(mb.cascades) > 0 ||
+		len(mb.triggers) > 0 {
 		private.WithID = mb.withID
 	}
 
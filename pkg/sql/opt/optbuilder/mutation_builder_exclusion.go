@@ -0,0 +1,248 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecksForInsert builds exclusion check queries for an insert.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	mb.buildExclusionChecks(false /* onlyUpdatedCols */)
+}
+
+// buildExclusionChecksForUpdate builds exclusion check queries for an update.
+// Only the constraints that involve at least one of the updated columns are
+// checked.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	mb.buildExclusionChecks(true /* onlyUpdatedCols */)
+}
+
+// buildExclusionChecksForUpsert builds exclusion check queries for an upsert.
+// All the constraints are checked, since the upsert can insert new rows.
+func (mb *mutationBuilder) buildExclusionChecksForUpsert() {
+	mb.buildExclusionChecks(false /* onlyUpdatedCols */)
+}
+
+func (mb *mutationBuilder) buildExclusionChecks(onlyUpdatedCols bool) {
+	count := mb.tab.ExclusionConstraintCount()
+	if count == 0 {
+		// No relevant exclusion checks.
+		return
+	}
+
+	h := &mb.exclusionCheckHelper
+	planned := false
+	for i := 0; i < count; i++ {
+		if onlyUpdatedCols && !mb.exclusionColsUpdated(mb.tab.ExclusionConstraint(i)) {
+			continue
+		}
+		if h.init(mb, i) {
+			mb.ensureWithID()
+			mb.uniqueChecks = append(mb.uniqueChecks, h.buildCheck())
+			planned = true
+		}
+	}
+	if planned {
+		telemetry.Inc(sqltelemetry.ExclusionChecksUseCounter)
+	}
+}
+
+// exclusionColsUpdated returns true if any of the columns of the given
+// exclusion constraint is updated by the mutation.
+func (mb *mutationBuilder) exclusionColsUpdated(ec cat.ExclusionConstraint) bool {
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if mb.updateColIDs[ec.ColumnOrdinal(mb.tab, i)] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// exclusionCheckHelper is a type associated with a single exclusion
+// constraint and is used to build its check query.
+type exclusionCheckHelper struct {
+	mb *mutationBuilder
+
+	exclusion        cat.ExclusionConstraint
+	exclusionOrdinal int
+
+	// colOrdinals includes the distinct table ordinals of the columns of the
+	// exclusion constraint, followed by the ordinals of any primary key columns
+	// that are not already included.
+	colOrdinals []int
+
+	// numExclusionCols is the number of ordinals in colOrdinals which belong to
+	// the exclusion constraint.
+	numExclusionCols int
+}
+
+// init initializes the helper with an exclusion constraint.
+//
+// Returns false if the constraint should be ignored (e.g. because the new
+// values for one of its columns are known to be always NULL).
+func (h *exclusionCheckHelper) init(mb *mutationBuilder, exclusionOrdinal int) bool {
+	*h = exclusionCheckHelper{
+		mb:               mb,
+		exclusion:        mb.tab.ExclusionConstraint(exclusionOrdinal),
+		exclusionOrdinal: exclusionOrdinal,
+	}
+
+	var exclusionOrds util.FastIntSet
+	allEq := true
+	for i, n := 0, h.exclusion.ColumnCount(); i < n; i++ {
+		exclusionOrds.Add(h.exclusion.ColumnOrdinal(mb.tab, i))
+		if h.exclusion.Operator(i) != tree.EQ {
+			allEq = false
+		}
+	}
+
+	// If the constraint only uses equality and the primary key columns are a
+	// subset of its columns, no two rows can conflict and the check is not
+	// needed.
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	primaryOrds.DifferenceWith(exclusionOrds)
+	if allEq && primaryOrds.Empty() {
+		return false
+	}
+
+	h.colOrdinals = append(exclusionOrds.Ordered(), primaryOrds.Ordered()...)
+	h.numExclusionCols = exclusionOrds.Len()
+
+	// The comparison of a NULL value never returns true, so if any of the
+	// columns is getting a NULL value the check is not needed.
+	for _, tabOrd := range h.colOrdinals[:h.numExclusionCols] {
+		colID := mb.mapToReturnColID(tabOrd)
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, colID) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildCheck creates an exclusion check for the rows which are written by the
+// mutation. The check is a semi join between the new rows and the existing
+// rows of the table, which returns the new rows that conflict with a different
+// row. The existing rows are read from the index backing the constraint, so
+// that the semi join can be planned as a lookup join or an inverted join into
+// that index rather than a join against a scan of the whole table.
+func (h *exclusionCheckHelper) buildCheck() memo.UniqueChecksItem {
+	checkInput, withScanCols, _ := h.mb.makeCheckInputScan(checkInputScanNewVals, h.colOrdinals)
+
+	f := h.mb.b.factory
+	scanScope := h.buildTableScan()
+
+	// Build the join filters:
+	//   (new_a op_a existing_a) AND (new_b op_b existing_b) AND ...
+	//
+	// Set the capacity to one condition for each column in the exclusion
+	// constraint, plus one additional condition to prevent rows from matching
+	// themselves (see below).
+	colCount := h.exclusion.ColumnCount()
+	keyCols := make(opt.ColList, colCount)
+	semiJoinFilters := make(memo.FiltersExpr, 0, colCount+1)
+	for i := 0; i < colCount; i++ {
+		tabOrd := h.exclusion.ColumnOrdinal(h.mb.tab, i)
+		idx := h.colIdx(tabOrd)
+		keyCols[i] = withScanCols[idx]
+		newVal := f.ConstructVariable(withScanCols[idx])
+		existingVal := f.ConstructVariable(scanScope.cols[idx].id)
+
+		// The && operator is commutative, so the existing value is placed on the
+		// left, where the inverted join planners expect the indexed column.
+		var cmp opt.ScalarExpr
+		switch h.exclusion.Operator(i) {
+		case tree.EQ:
+			cmp = f.ConstructEq(newVal, existingVal)
+		case tree.Overlaps:
+			switch h.mb.tab.Column(tabOrd).DatumType().Family() {
+			case types.GeometryFamily, types.Box2DFamily:
+				// The && operator means "intersects" when used with geometry or
+				// bounding box operands.
+				cmp = f.ConstructBBoxIntersects(existingVal, newVal)
+			default:
+				cmp = f.ConstructOverlaps(existingVal, newVal)
+			}
+		default:
+			panic(errors.AssertionFailedf(
+				"unexpected exclusion constraint operator %s", h.exclusion.Operator(i),
+			))
+		}
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(cmp))
+	}
+
+	// We need to prevent rows from matching themselves in the semi join. We can
+	// do this by adding another filter that uses the primary keys to check if
+	// two rows are identical:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	primaryIndex := h.mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primaryIndex.LaxKeyColumnCount(); i < n; i++ {
+		idx := h.colIdx(primaryIndex.Column(i).Ordinal())
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(withScanCols[idx]),
+			f.ConstructVariable(scanScope.cols[idx].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	semiJoin := f.ConstructSemiJoin(checkInput, scanScope.expr, semiJoinFilters, &memo.JoinPrivate{})
+
+	return f.ConstructUniqueChecksItem(semiJoin, &memo.UniqueChecksItemPrivate{
+		Table:        h.mb.tabID,
+		CheckOrdinal: h.exclusionOrdinal,
+		Exclusion:    true,
+		KeyCols:      keyCols,
+		OpName:       h.mb.opName,
+	})
+}
+
+// colIdx returns the index in colOrdinals of the given table ordinal.
+func (h *exclusionCheckHelper) colIdx(tabOrd int) int {
+	for i := range h.colOrdinals {
+		if h.colOrdinals[i] == tabOrd {
+			return i
+		}
+	}
+	panic(errors.AssertionFailedf("column %d not found in exclusion check", tabOrd))
+}
+
+// buildTableScan builds a Scan of the table which is forced to use the index
+// backing the exclusion constraint, if there is one. Tables created before
+// exclusion constraints were backed by an index may not have one, in which
+// case the check falls back to reading the whole table.
+func (h *exclusionCheckHelper) buildTableScan() *scope {
+	tabMeta := h.mb.b.addTable(h.mb.tab, tree.NewUnqualifiedTableName(h.mb.tab.Name()))
+	var indexFlags *tree.IndexFlags
+	if ord, ok := cat.FindExclusionConstraintIndex(h.mb.tab, h.exclusion); ok {
+		indexFlags = &tree.IndexFlags{Index: tree.UnrestrictedName(h.mb.tab.Index(ord).Name())}
+	}
+	return h.mb.b.buildScan(
+		tabMeta,
+		h.colOrdinals,
+		indexFlags,
+		noRowLocking,
+		h.mb.b.allocScope(),
+	)
+}
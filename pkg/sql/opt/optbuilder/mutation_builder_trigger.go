@@ -0,0 +1,239 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildTriggersForInsert adds the row-level triggers that fire for the rows
+// inserted by the mutation.
+func (mb *mutationBuilder) buildTriggersForInsert() {
+	mb.buildTriggers(func(add func(tree.TriggerEvent, opt.ColList, opt.ColList)) {
+		add(tree.TriggerInsert, nil /* oldValues */, mb.insertColIDs)
+	})
+}
+
+// buildTriggersForUpdate adds the row-level triggers that fire for the rows
+// updated by the mutation.
+func (mb *mutationBuilder) buildTriggersForUpdate() {
+	mb.buildTriggers(func(add func(tree.TriggerEvent, opt.ColList, opt.ColList)) {
+		add(tree.TriggerUpdate, mb.fetchColIDs, mb.updatedValueCols())
+	})
+}
+
+// buildTriggersForDelete adds the row-level triggers that fire for the rows
+// deleted by the mutation.
+func (mb *mutationBuilder) buildTriggersForDelete() {
+	mb.buildTriggers(func(add func(tree.TriggerEvent, opt.ColList, opt.ColList)) {
+		add(tree.TriggerDelete, mb.fetchColIDs, nil /* newValues */)
+	})
+}
+
+// buildTriggersForUpsert adds the row-level triggers that fire for the rows
+// inserted or updated by the mutation. The canary column is used to determine
+// which of the two happens to each row.
+func (mb *mutationBuilder) buildTriggersForUpsert() {
+	mb.buildTriggers(func(add func(tree.TriggerEvent, opt.ColList, opt.ColList)) {
+		add(tree.TriggerInsert, nil /* oldValues */, mb.insertColIDs)
+		add(tree.TriggerUpdate, mb.fetchColIDs, mb.updatedValueCols())
+	})
+}
+
+// updatedValueCols returns the columns which contain the values of the rows
+// after they are updated: the update column if the column is updated, and the
+// fetch column otherwise.
+func (mb *mutationBuilder) updatedValueCols() opt.ColList {
+	cols := make(opt.ColList, len(mb.fetchColIDs))
+	for i := range cols {
+		if cols[i] = mb.updateColIDs[i]; cols[i] == 0 {
+			cols[i] = mb.fetchColIDs[i]
+		}
+	}
+	return cols
+}
+
+// buildTriggers adds the triggers of the table which fire for the events of
+// the mutation. The events are added by the given function; the triggers are
+// ordered by name, and the events of the same trigger in the order in which
+// they are added.
+func (mb *mutationBuilder) buildTriggers(
+	events func(add func(event tree.TriggerEvent, oldValues, newValues opt.ColList)),
+) {
+	count := mb.tab.TriggerCount()
+	if count == 0 {
+		return
+	}
+
+	// Only keep the values of the columns that are visible to triggers.
+	triggerCols := func(cols opt.ColList) opt.ColList {
+		if cols == nil {
+			return nil
+		}
+		res := make(opt.ColList, len(cols))
+		for i := range cols {
+			if mb.tab.Column(i).Kind() == cat.Ordinary {
+				res[i] = cols[i]
+			}
+		}
+		return res
+	}
+
+	for i := 0; i < count; i++ {
+		trigger := mb.tab.Trigger(i)
+		events(func(event tree.TriggerEvent, oldValues, newValues opt.ColList) {
+			if !trigger.Fires(event) {
+				return
+			}
+			mb.ensureWithID()
+			mb.triggers = append(mb.triggers, memo.Trigger{
+				Name:      string(trigger.Name()),
+				Before:    trigger.ActionTime() == tree.TriggerBefore,
+				Event:     event,
+				Builder:   &triggerBuilder{tab: mb.tab, trigger: trigger},
+				OldValues: triggerCols(oldValues),
+				NewValues: triggerCols(newValues),
+				CanaryCol: mb.canaryColID,
+			})
+		})
+	}
+	if len(mb.triggers) > 0 {
+		telemetry.Inc(sqltelemetry.TriggersUseCounter)
+	}
+}
+
+// triggerBuilder is a memo.TriggerBuilder which builds the statement executed
+// by a row-level trigger.
+type triggerBuilder struct {
+	tab     cat.Table
+	trigger cat.Trigger
+}
+
+var _ memo.TriggerBuilder = &triggerBuilder{}
+
+// Build is part of the memo.TriggerBuilder interface.
+func (tb *triggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+) (when tree.TypedExpr, _ memo.RelExpr, placeholderTypes tree.PlaceholderTypes, _ error) {
+	query, err := buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		// The values of the row are assigned to the placeholders when the trigger
+		// fires, so they must not be replaced by the values of the placeholders
+		// of the statement which fired it.
+		b.KeepPlaceholders = true
+		b.trigger = &triggerRow{tab: tb.tab}
+
+		n := tb.tab.ColumnCount()
+		hints := make(tree.PlaceholderTypes, 2*n)
+		for i := 0; i < n; i++ {
+			hints[i] = tb.tab.Column(i).DatumType()
+			hints[n+i] = hints[i]
+		}
+		defer func(placeholders tree.PlaceholderInfo) {
+			b.semaCtx.Placeholders = placeholders
+		}(b.semaCtx.Placeholders)
+		if err := b.semaCtx.Placeholders.Init(2*n, hints); err != nil {
+			panic(err)
+		}
+
+		if whenStr := tb.trigger.When(); whenStr != "" {
+			expr, err := parser.ParseExpr(whenStr)
+			if err != nil {
+				panic(err)
+			}
+			s := b.allocScope()
+			when, err = tree.TypeCheckAndRequire(
+				ctx, s.walkExprTree(expr), b.semaCtx, types.Bool, "WHEN",
+			)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		stmt, err := parser.ParseOne(tb.trigger.Body())
+		if err != nil {
+			panic(pgerror.Wrapf(err, pgcode.Syntax,
+				"failed to parse body of trigger %q", tb.trigger.Name()))
+		}
+		if stmt.NumPlaceholders > 0 {
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"body of trigger %q cannot contain placeholders", tb.trigger.Name()))
+		}
+		defer func(annotations tree.Annotations) {
+			b.semaCtx.Annotations = annotations
+		}(b.semaCtx.Annotations)
+		b.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
+
+		outScope := b.buildStmtAtRoot(stmt.AST, nil /* desiredTypes */, b.allocScope())
+
+		// Placeholders which are not referenced keep the type of their column.
+		placeholderTypes = make(tree.PlaceholderTypes, 2*n)
+		for i := range placeholderTypes {
+			if placeholderTypes[i] = b.semaCtx.Placeholders.Types[i]; placeholderTypes[i] == nil {
+				placeholderTypes[i] = hints[i]
+			}
+		}
+		return outScope.expr
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return when, query, placeholderTypes, nil
+}
+
+// triggerRow is used to resolve the references to NEW and OLD in the statement
+// executed by a trigger.
+type triggerRow struct {
+	tab cat.Table
+}
+
+// resolve returns the placeholder for the given column of NEW or OLD, or
+// false if the column item does not refer to NEW or OLD. See
+// memo.TriggerBuilder for the placeholder indexes. The values of a row that
+// does not exist (OLD for insertions and NEW for deletions) are NULL.
+func (r *triggerRow) resolve(c *tree.ColumnItem) (tree.Expr, bool) {
+	if c.TableName == nil || c.TableName.NumParts != 1 {
+		return nil, false
+	}
+	n := r.tab.ColumnCount()
+	var offset int
+	switch c.TableName.Parts[0] {
+	case "old":
+		offset = 0
+	case "new":
+		offset = n
+	default:
+		return nil, false
+	}
+	for i := 0; i < n; i++ {
+		col := r.tab.Column(i)
+		if col.Kind() != cat.Ordinary || col.ColName() != c.ColumnName {
+			continue
+		}
+		return &tree.Placeholder{Idx: tree.PlaceholderIdx(offset + i)}, true
+	}
+	panic(pgerror.Newf(pgcode.UndefinedColumn,
+		"record %q has no field %q", c.TableName.Parts[0], c.ColumnName))
+}
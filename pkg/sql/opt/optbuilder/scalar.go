@@ -112,6 +112,13 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *tree.GroupingExpr:
+		if !inGroupingContext {
+			panic(pgerror.New(pgcode.Grouping,
+				"arguments to GROUPING must be grouping expressions of the associated query level"))
+		}
+		return b.buildGroupingFunc(t, inScope, outScope, outCol, colRefs)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(tree.ReType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(tree.ReType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
@@ -376,6 +383,9 @@ func (b *Builder) buildScalar(
 	case *sqlFnInfo:
 		out = b.buildSQLFn(t, inScope, outScope, outCol, colRefs)
 
+	case *udf:
+		out = b.buildUDF(t, inScope, colRefs)
+
 	case *srf:
 		if len(t.cols) == 1 {
 			if inGroupingContext {
@@ -910,6 +910,11 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return s.VisitPre(vn)
 
 	case *tree.ColumnItem:
+		if s.builder.trigger != nil {
+			if val, ok := s.builder.trigger.resolve(t); ok {
+				return false, val
+			}
+		}
 		colI, err := t.Resolve(s.builder.ctx, s)
 		if err != nil {
 			panic(err)
@@ -919,6 +924,10 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.FuncExpr:
 		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
 		if err != nil {
+			// The name may refer to a user-defined function.
+			if u := s.replaceUDF(t); u != nil {
+				return false, u
+			}
 			panic(err)
 		}
 
@@ -942,6 +951,15 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.Placeholder:
+		if s.builder.insideFuncDef || s.builder.insideFuncBody {
+			// Inside the body of a user-defined function, placeholders refer to
+			// the arguments of the function by position.
+			return s.VisitPre(&tree.UnresolvedName{
+				NumParts: 1, Parts: tree.NameParts{fmt.Sprintf("$%d", t.Idx+1)},
+			})
+		}
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
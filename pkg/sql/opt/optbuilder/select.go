@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -528,6 +529,12 @@ func (b *Builder) buildScan(
 	}
 	if locking.isSet() {
 		private.Locking = locking.get()
+		if private.Locking.Strength <= tree.ForShare &&
+			!b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.SharedLocksAndSkipLocked) {
+			// Nodes running older versions do not support Shared locks, so FOR
+			// SHARE and FOR KEY SHARE remain no-ops until the cluster is upgraded.
+			private.Locking = nil
+		}
 	}
 
 	b.addCheckConstraintsForTable(tabMeta)
@@ -1383,11 +1390,11 @@ func (b *Builder) validateLockingInFrom(
 			// AST nodes should not be created with this locking strength.
 			panic(errors.AssertionFailedf("locking item without strength"))
 		case tree.ForUpdate, tree.ForNoKeyUpdate, tree.ForShare, tree.ForKeyShare:
-			// CockroachDB treats all of the FOR LOCKED modes as no-ops. Since all
-			// transactions are serializable in CockroachDB, clients can't observe
-			// whether or not FOR UPDATE (or any of the other weaker modes) actually
-			// created a lock. This behavior may improve as the transaction model gains
-			// more capabilities.
+			// FOR UPDATE and FOR NO KEY UPDATE acquire Exclusive locks on the
+			// scanned keys, and FOR SHARE and FOR KEY SHARE acquire Shared locks.
+			// Since all transactions are serializable in CockroachDB, clients
+			// can't observe the difference between the weaker modes and their
+			// stronger counterparts, other than through contention.
 		default:
 			panic(errors.AssertionFailedf("unknown locking strength: %s", li.Strength))
 		}
@@ -1397,8 +1404,12 @@ func (b *Builder) validateLockingInFrom(
 		case tree.LockWaitBlock:
 			// Default. Block on conflicting locks.
 		case tree.LockWaitSkip:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"SKIP LOCKED lock wait policy is not supported"))
+			// Skip over rows that are locked by other transactions.
+			if !b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.SharedLocksAndSkipLocked) {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"SKIP LOCKED lock wait policy requires all nodes to be upgraded to %s",
+					clusterversion.ByKey(clusterversion.SharedLocksAndSkipLocked)))
+			}
 		case tree.LockWaitError:
 			// Raise an error on conflicting locks.
 		default:
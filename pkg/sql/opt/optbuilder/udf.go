@@ -0,0 +1,269 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// udf stores information about a call to a user-defined function. The call is
+// built as a correlated subquery which evaluates the body of the function,
+// with the arguments of the call bound to the parameters of the function.
+type udf struct {
+	*tree.FuncExpr
+
+	// fn is the overload of the user-defined function which is invoked.
+	fn cat.Function
+}
+
+// Walk is part of the tree.Expr interface.
+func (u *udf) Walk(v tree.Visitor) tree.Expr {
+	return u
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (u *udf) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	if _, err := u.FuncExpr.TypeCheck(ctx, semaCtx, desired); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// replaceUDF resolves the name of the given function call to the overloads of
+// a user-defined function, and returns a udf which replaces the call. It
+// returns nil if the name does not refer to any user-defined function.
+func (s *scope) replaceUDF(f *tree.FuncExpr) tree.Expr {
+	n, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok || n.NumParts > 3 {
+		return nil
+	}
+	name, err := tree.NewUnresolvedObjectName(
+		n.NumParts, [3]string{n.Parts[0], n.Parts[1], n.Parts[2]}, tree.NoAnnotation,
+	)
+	if err != nil {
+		return nil
+	}
+	b := s.builder
+	overloads, err := b.catalog.ResolveFunction(b.ctx, name)
+	if err != nil {
+		panic(err)
+	}
+	if len(overloads) == 0 {
+		return nil
+	}
+
+	if b.insideViewDef {
+		panic(unimplemented.NewWithIssue(17511, "user-defined functions cannot be used in views"))
+	}
+	if b.insideFuncDef || b.insideFuncBody {
+		panic(unimplemented.NewWithIssue(
+			17511, "user-defined functions cannot be called from other functions",
+		))
+	}
+	if f.Type == tree.DistinctFuncType {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"DISTINCT specified, but %s is not an aggregate function", tree.ErrString(n)))
+	}
+
+	// Build a function definition with one overload per overload of the
+	// user-defined function, so that the call can be type checked like a call
+	// to a builtin function.
+	ovs := make([]tree.Overload, len(overloads))
+	for i, fn := range overloads {
+		args := make(tree.ArgTypes, fn.ArgCount())
+		for j := range args {
+			args[j].Name = string(fn.ArgName(j))
+			args[j].Typ = fn.ArgType(j)
+		}
+		ovs[i] = tree.Overload{
+			Types:      args,
+			ReturnType: tree.FixedReturnType(fn.ReturnType()),
+			Volatility: fn.Volatility(),
+		}
+	}
+	def := tree.NewUDFFunctionDefinition(n.String(), ovs)
+
+	// Copy the function call so that the tree isn't mutated.
+	copy := *f
+	copy.Func = tree.ResolvableFunctionReference{FunctionReference: def}
+
+	// We need to save and restore the previous value of the field in
+	// semaCtx in case we are recursively called within a subquery
+	// context.
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+	b.semaCtx.Properties.Require("user-defined function", tree.RejectSpecial)
+
+	expr := copy.Walk(s)
+	typedFunc, err := tree.TypeCheck(b.ctx, expr, b.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	typedFuncExpr := typedFunc.(*tree.FuncExpr)
+
+	var invoked cat.Function
+	for i := range ovs {
+		if &ovs[i] == typedFuncExpr.ResolvedOverload() {
+			invoked = overloads[i]
+			break
+		}
+	}
+	if invoked == nil {
+		panic(errors.AssertionFailedf("could not find overload for %s", tree.ErrString(n)))
+	}
+
+	b.factory.Metadata().AddFunction(name, overloads, invoked)
+	if err := b.catalog.CheckPrivilege(b.ctx, invoked, privilege.EXECUTE); err != nil {
+		panic(err)
+	}
+
+	return &udf{FuncExpr: typedFuncExpr, fn: invoked}
+}
+
+// buildUDF builds the body of the given user-defined function call as a
+// correlated subquery. The arguments of the call are projected as columns of
+// a single row, and the body is joined to that row with an apply join, so that
+// it can refer to the arguments as outer columns:
+//
+//   SELECT f(x) FROM t
+//     ==> SELECT (SELECT body FROM (SELECT x AS a) AS f LIMIT 1) FROM t
+//
+// The body can refer to an argument either by its name, if it has one, or by
+// its position (e.g. $1).
+func (b *Builder) buildUDF(u *udf, inScope *scope, colRefs *opt.ColSet) opt.ScalarExpr {
+	fn := u.fn
+	md := b.factory.Metadata()
+
+	// Project the arguments in a single row. The parameter scope has no parent,
+	// so that the body cannot refer to any columns of the calling query.
+	paramScope := b.allocScope()
+	for i, n := 0, fn.ArgCount(); i < n; i++ {
+		argType := fn.ArgType(i)
+		arg := b.buildScalar(u.Exprs[i].(tree.TypedExpr), inScope, nil, nil, colRefs)
+		if !arg.DataType().Identical(argType) {
+			arg = b.factory.ConstructCast(arg, argType)
+		}
+		posName := fmt.Sprintf("$%d", i+1)
+		name := string(fn.ArgName(i))
+		if name == "" {
+			name = posName
+		}
+		col := b.synthesizeColumn(paramScope, name, argType, nil /* expr */, arg)
+		if name != posName {
+			// Make the argument accessible by position as well.
+			posCol := *col
+			posCol.name = tree.Name(posName)
+			posCol.scalar = nil
+			paramScope.cols = append(paramScope.cols, posCol)
+		}
+	}
+	paramScope.setTableAlias(fn.Name())
+	paramScope.expr = b.constructProject(
+		b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+			Cols: opt.ColList{},
+			ID:   md.NextUniqueID(),
+		}),
+		paramScope.cols,
+	)
+
+	// If the function is strict, it returns NULL when any of its arguments is
+	// NULL, without evaluating the body.
+	params := paramScope.expr.(memo.RelExpr)
+	if !fn.CalledOnNullInput() && fn.ArgCount() > 0 {
+		filters := make(memo.FiltersExpr, 0, fn.ArgCount())
+		var seen opt.ColSet
+		for i := range paramScope.cols {
+			col := &paramScope.cols[i]
+			if seen.Contains(col.id) {
+				continue
+			}
+			seen.Add(col.id)
+			filters = append(filters, b.factory.ConstructFiltersItem(
+				b.factory.ConstructIsNot(b.factory.ConstructVariable(col.id), memo.NullSingleton),
+			))
+		}
+		params = b.factory.ConstructSelect(params, filters)
+	}
+
+	stmt, err := parser.ParseOne(fn.Body())
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.Syntax,
+			"failed to parse body of function %q", fn.Name()))
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(errors.AssertionFailedf("expected SELECT statement"))
+	}
+
+	// The body is built as an independent query, so any outer columns that it
+	// references belong to the parameter scope rather than to a subquery that
+	// encloses the function call.
+	defer func(subquery *subquery, insideFuncBody bool, annotations tree.Annotations) {
+		b.subquery = subquery
+		b.insideFuncBody = insideFuncBody
+		b.semaCtx.Annotations = annotations
+	}(b.subquery, b.insideFuncBody, b.semaCtx.Annotations)
+	b.subquery = nil
+	b.insideFuncBody = true
+	b.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
+
+	retType := fn.ReturnType()
+	b.pushWithFrame()
+	bodyScope := b.buildStmt(sel, []*types.T{retType}, paramScope.push())
+	b.popWithFrame(bodyScope)
+	bodyScope.removeHiddenCols()
+	if len(bodyScope.cols) != 1 {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", retType))
+	}
+
+	// A function returns the first row of its body, or NULL if the body
+	// returns no rows. Any extra columns needed by the ordering are projected
+	// away below.
+	body := b.factory.ConstructLimit(
+		bodyScope.expr,
+		b.factory.ConstructConstVal(tree.NewDInt(1), types.Int),
+		bodyScope.makeOrderingChoice(),
+	)
+	resultCol := bodyScope.cols[0].id
+	if !bodyScope.cols[0].typ.Identical(retType) {
+		castScope := bodyScope.replace()
+		col := b.synthesizeColumn(
+			castScope, string(fn.Name()), retType, nil, /* expr */
+			b.factory.ConstructCast(b.factory.ConstructVariable(resultCol), retType),
+		)
+		resultCol = col.id
+		body = b.constructProject(body, castScope.cols)
+	}
+
+	input := b.factory.ConstructInnerJoinApply(params, body, memo.TrueFilter, memo.EmptyJoinPrivate)
+	var passthrough opt.ColSet
+	passthrough.Add(resultCol)
+	input = b.factory.ConstructProject(input, nil /* projections */, passthrough)
+
+	return b.factory.ConstructSubquery(input, &memo.SubqueryPrivate{
+		OriginalExpr: &tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+	})
+}
@@ -348,8 +348,12 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	// Project partial index PUT and DEL boolean columns.
 	mb.projectPartialIndexPutAndDelCols(preCheckScope, mb.fetchScope)
 
+	mb.buildExclusionChecksForUpdate()
+
 	mb.buildFKChecksForUpdate()
 
+	mb.buildTriggersForUpdate()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
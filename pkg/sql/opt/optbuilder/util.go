@@ -52,6 +52,9 @@ func (b *Builder) expandStar(
 	if b.insideViewDef {
 		panic(unimplemented.NewWithIssue(10028, "views do not currently support * expressions"))
 	}
+	if b.insideFuncDef {
+		panic(unimplemented.NewWithIssue(17511, "functions do not currently support * expressions"))
+	}
 	switch t := expr.(type) {
 	case *tree.TupleStar:
 		texpr := inScope.resolveType(t.Expr, types.Any)
@@ -580,8 +583,8 @@ func (b *Builder) resolveDataSource(
 	tn *tree.TableName, priv privilege.Kind,
 ) (cat.DataSource, opt.MDDepName, cat.DataSourceName) {
 	var flags cat.Flags
-	if b.insideViewDef {
-		// Avoid taking table leases when we're creating a view.
+	if b.insideViewDef || b.insideFuncDef {
+		// Avoid taking table leases when we're creating a view or function.
 		flags.AvoidDescriptorCaches = true
 	}
 	ds, resName, err := b.catalog.ResolveDataSource(b.ctx, flags, tn)
@@ -607,8 +610,8 @@ func (b *Builder) resolveDataSourceRef(
 	ref *tree.TableRef, priv privilege.Kind,
 ) (cat.DataSource, opt.MDDepName) {
 	var flags cat.Flags
-	if b.insideViewDef {
-		// Avoid taking table leases when we're creating a view.
+	if b.insideViewDef || b.insideFuncDef {
+		// Avoid taking table leases when we're creating a view or function.
 		flags.AvoidDescriptorCaches = true
 	}
 	ds, _, err := b.catalog.ResolveDataSourceByID(b.ctx, flags, cat.StableID(ref.TableID))
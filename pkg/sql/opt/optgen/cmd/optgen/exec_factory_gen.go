@@ -67,7 +67,7 @@ func (g *execFactoryGen) genExecFactory() {
 	g.w.writeIndent("// Checks are executed after all cascades have been executed. They don't\n")
 	g.w.writeIndent("// return results but can generate errors (e.g. foreign key check failures).\n")
 	g.w.nestIndent("ConstructPlan(\n")
-	g.w.writeIndent("root Node, subqueries []Subquery, cascades []Cascade, checks []Node,\n")
+	g.w.writeIndent("root Node, subqueries []Subquery, cascades []Cascade, checks []Check,\n")
 	g.w.unnest(") (Plan, error)\n")
 
 	for _, define := range g.compiled.Defines {
@@ -96,7 +96,7 @@ func (g *execFactoryGen) genStubFactory() {
 	g.w.write("var _ Factory = StubFactory{}\n")
 	g.w.write("\n")
 	g.w.nestIndent("func (StubFactory) ConstructPlan(\n")
-	g.w.writeIndent("root Node, subqueries []Subquery, cascades []Cascade, checks []Node,\n")
+	g.w.writeIndent("root Node, subqueries []Subquery, cascades []Cascade, checks []Check,\n")
 	g.w.unnest(") (Plan, error) {\n")
 	g.w.nestIndent("return struct{}{}, nil\n")
 	g.w.unnest("}\n")
@@ -0,0 +1,49 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// CreateFunction creates a test function from a parsed DDL statement and adds
+// it to the catalog. Unlike a real CREATE FUNCTION, the body is stored as-is,
+// without being validated.
+func (tc *Catalog) CreateFunction(stmt *tree.CreateFunction) *Function {
+	fn := &Function{
+		FuncID:   tc.nextStableID(),
+		FuncName: tree.Name(stmt.FuncName.Object()),
+		ArgNames: make([]tree.Name, len(stmt.Args)),
+		ArgTypes: make([]*types.T, len(stmt.Args)),
+		RetType:  tree.MustBeStaticallyKnownType(stmt.ReturnType),
+		Vol:      tree.VolatilityVolatile,
+	}
+	for i := range stmt.Args {
+		fn.ArgNames[i] = stmt.Args[i].Name
+		fn.ArgTypes[i] = tree.MustBeStaticallyKnownType(stmt.Args[i].Type)
+	}
+	for _, option := range stmt.Options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			fn.Vol = tree.Volatility(t)
+		case tree.FunctionNullInputBehavior:
+			fn.Strict = t != tree.FunctionCalledOnNullInput
+		case tree.FunctionBody:
+			fn.BodyText = string(t)
+		}
+	}
+
+	// Add the new function to the catalog.
+	tc.AddFunction(fn)
+
+	return fn
+}
@@ -191,7 +191,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex)
+				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex, def.Deferrability)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -199,6 +199,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.IndexTableDef:
 			tab.addIndex(def, nonUniqueIndex)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
@@ -209,6 +212,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						def.Unique.ConstraintName,
 						tree.IndexElemList{{Column: def.Name}},
 						def.Unique.WithoutIndex,
+						tree.ConstraintNotDeferrable,
 					)
 				} else {
 					tab.addIndex(
@@ -453,13 +457,18 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrable:               d.Deferrability.Deferrable(),
+		initiallyDeferred:        d.Deferrability.InitiallyDeferred(),
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	cols := make([]int, len(columns))
 	for i, c := range columns {
@@ -476,15 +485,31 @@ func (tt *Table) addUniqueConstraint(
 
 	// We didn't find an existing constraint, so add a new one.
 	u := UniqueConstraint{
-		name:           string(name),
-		tabID:          tt.TabID,
-		columnOrdinals: cols,
-		withoutIndex:   withoutIndex,
-		validated:      true,
+		name:              string(name),
+		tabID:             tt.TabID,
+		columnOrdinals:    cols,
+		withoutIndex:      withoutIndex,
+		validated:         true,
+		deferrable:        deferrability.Deferrable(),
+		initiallyDeferred: deferrability.InitiallyDeferred(),
 	}
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	c := ExclusionConstraint{
+		name:           string(def.Name),
+		tabID:          tt.TabID,
+		columnOrdinals: make([]int, len(def.Elems)),
+		operators:      make([]tree.ComparisonOperator, len(def.Elems)),
+	}
+	for i := range def.Elems {
+		c.columnOrdinals[i] = tt.FindOrdinal(string(def.Elems[i].Column))
+		c.operators[i] = def.Elems[i].Operator
+	}
+	tt.exclusionConstraints = append(tt.exclusionConstraints, c)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -549,7 +574,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, false /* withoutIndex */, tree.ConstraintNotDeferrable,
+		)
 	}
 
 	idx := &Index{
@@ -45,6 +45,7 @@ const (
 type Catalog struct {
 	tree.TypeReferenceResolver
 	testSchema Schema
+	functions  map[string][]*Function
 	counter    int
 }
 
@@ -68,6 +69,7 @@ func New() *Catalog {
 			},
 			dataSources: make(map[string]dataSource),
 		},
+		functions: make(map[string][]*Function),
 	}
 }
 
@@ -178,6 +180,25 @@ func (tc *Catalog) ResolveTypeByOID(context.Context, oid.Oid) (*types.T, error)
 	return nil, errors.Newf("test catalog cannot handle user defined types")
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (tc *Catalog) ResolveFunction(
+	_ context.Context, name *tree.UnresolvedObjectName,
+) ([]cat.Function, error) {
+	// All functions live in the t.public schema.
+	if name.NumParts > 2 && name.Parts[2] != testDB {
+		return nil, nil
+	}
+	if name.NumParts > 1 && name.Parts[1] != tree.PublicSchema {
+		return nil, nil
+	}
+	overloads := tc.functions[name.Parts[0]]
+	res := make([]cat.Function, len(overloads))
+	for i := range overloads {
+		res[i] = overloads[i]
+	}
+	return res, nil
+}
+
 // CheckPrivilege is part of the cat.Catalog interface.
 func (tc *Catalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	return tc.CheckAnyPrivilege(ctx, o)
@@ -202,6 +223,10 @@ func (tc *Catalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 		if t.Revoked {
 			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.SeqName)
 		}
+	case *Function:
+		if t.Revoked {
+			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.FuncName)
+		}
 	default:
 		panic("invalid Object")
 	}
@@ -327,6 +352,28 @@ func (tc *Catalog) AddSequence(seq *Sequence) {
 	tc.testSchema.dataSources[fq] = seq
 }
 
+// AddFunction adds the given test function overload to the catalog.
+func (tc *Catalog) AddFunction(fn *Function) {
+	name := string(fn.FuncName)
+	for _, o := range tc.functions[name] {
+		if len(o.ArgTypes) != len(fn.ArgTypes) {
+			continue
+		}
+		same := true
+		for i := range o.ArgTypes {
+			if !o.ArgTypes[i].Equivalent(fn.ArgTypes[i]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			panic(pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists with same argument types", name))
+		}
+	}
+	tc.functions[name] = append(tc.functions[name], fn)
+}
+
 // ExecuteMultipleDDL parses the given semicolon-separated DDL SQL statements
 // and applies each of them to the test catalog.
 func (tc *Catalog) ExecuteMultipleDDL(sql string) error {
@@ -395,6 +442,10 @@ func (tc *Catalog) ExecuteDDLWithIndexVersion(
 		tc.CreateSequence(stmt)
 		return "", nil
 
+	case *tree.CreateFunction:
+		tc.CreateFunction(stmt)
+		return "", nil
+
 	case *tree.SetZoneConfig:
 		tc.SetZoneConfig(stmt)
 		return "", nil
@@ -570,6 +621,90 @@ func (tv *View) ColumnName(i int) tree.Name {
 	return tv.ColumnNames[i]
 }
 
+// Function implements the cat.Function interface for testing purposes.
+type Function struct {
+	FuncID      cat.StableID
+	FuncVersion int
+	FuncName    tree.Name
+	ArgNames    []tree.Name
+	ArgTypes    []*types.T
+	RetType     *types.T
+	Vol         tree.Volatility
+	Strict      bool
+	BodyText    string
+
+	// If Revoked is true, then the user has had privileges on the function
+	// revoked.
+	Revoked bool
+}
+
+var _ cat.Function = &Function{}
+
+func (tf *Function) String() string {
+	tp := treeprinter.New()
+	cat.FormatFunction(tf, tp)
+	return tp.String()
+}
+
+// ID is part of the cat.Object interface.
+func (tf *Function) ID() cat.StableID {
+	return tf.FuncID
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (tf *Function) PostgresDescriptorID() cat.StableID {
+	return tf.FuncID
+}
+
+// Equals is part of the cat.Object interface.
+func (tf *Function) Equals(other cat.Object) bool {
+	otherFunc, ok := other.(*Function)
+	if !ok {
+		return false
+	}
+	return tf.FuncID == otherFunc.FuncID && tf.FuncVersion == otherFunc.FuncVersion
+}
+
+// Name is part of the cat.Function interface.
+func (tf *Function) Name() tree.Name {
+	return tf.FuncName
+}
+
+// ArgCount is part of the cat.Function interface.
+func (tf *Function) ArgCount() int {
+	return len(tf.ArgTypes)
+}
+
+// ArgName is part of the cat.Function interface.
+func (tf *Function) ArgName(i int) tree.Name {
+	return tf.ArgNames[i]
+}
+
+// ArgType is part of the cat.Function interface.
+func (tf *Function) ArgType(i int) *types.T {
+	return tf.ArgTypes[i]
+}
+
+// ReturnType is part of the cat.Function interface.
+func (tf *Function) ReturnType() *types.T {
+	return tf.RetType
+}
+
+// Volatility is part of the cat.Function interface.
+func (tf *Function) Volatility() tree.Volatility {
+	return tf.Vol
+}
+
+// CalledOnNullInput is part of the cat.Function interface.
+func (tf *Function) CalledOnNullInput() bool {
+	return !tf.Strict
+}
+
+// Body is part of the cat.Function interface.
+func (tf *Function) Body() string {
+	return tf.BodyText
+}
+
 // Table implements the cat.Table interface for testing purposes.
 type Table struct {
 	TabID      cat.StableID
@@ -597,6 +732,8 @@ type Table struct {
 	inboundFKs  []ForeignKeyConstraint
 
 	uniqueConstraints []UniqueConstraint
+
+	exclusionConstraints []ExclusionConstraint
 }
 
 var _ cat.Table = &Table{}
@@ -736,6 +873,26 @@ func (tt *Table) Unique(i int) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraintCount() int {
+	return len(tt.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &tt.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // FindOrdinal returns the ordinal of the column with the given name.
 func (tt *Table) FindOrdinal(name string) int {
 	for i, col := range tt.Columns {
@@ -1075,6 +1232,9 @@ type ForeignKeyConstraint struct {
 	matchMethod  tree.CompositeKeyMatchMethod
 	deleteAction tree.ReferenceAction
 	updateAction tree.ReferenceAction
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1142,6 +1302,16 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrable is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrable() bool {
+	return fk.deferrable
+}
+
+// InitiallyDeferred is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) InitiallyDeferred() bool {
+	return fk.initiallyDeferred
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1150,6 +1320,9 @@ type UniqueConstraint struct {
 	columnOrdinals []int
 	withoutIndex   bool
 	validated      bool
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1190,6 +1363,53 @@ func (u *UniqueConstraint) Validated() bool {
 	return u.validated
 }
 
+// Deferrable is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrable() bool {
+	return u.deferrable
+}
+
+// InitiallyDeferred is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) InitiallyDeferred() bool {
+	return u.initiallyDeferred
+}
+
+// ExclusionConstraint implements cat.ExclusionConstraint. See that interface
+// for more information on the fields.
+type ExclusionConstraint struct {
+	name           string
+	tabID          cat.StableID
+	columnOrdinals []int
+	operators      []tree.ComparisonOperator
+}
+
+var _ cat.ExclusionConstraint = &ExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnCount() int {
+	return len(e.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.tabID {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.tabID,
+		))
+	}
+	return e.columnOrdinals[i]
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return e.operators[i]
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -259,6 +260,77 @@ func (oc *optCatalog) ResolveType(
 	return oc.planner.ResolveType(ctx, name)
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) ([]cat.Function, error) {
+	dbName := oc.planner.CurrentDatabase()
+	if name.NumParts == 3 {
+		dbName = name.Parts[2]
+	}
+	if dbName == "" {
+		return nil, nil
+	}
+	dbDesc, err := oc.planner.Descriptors().GetDatabaseByName(
+		ctx, oc.planner.txn, dbName, oc.planner.CommonLookupFlags(false /* required */),
+	)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+
+	// The functions map of the database tells us whether the name refers to a
+	// user-defined function at all, without having to look up any schemas.
+	info, ok := dbDesc.DatabaseDesc().Functions[name.Object()]
+	if !ok {
+		return nil, nil
+	}
+
+	var schemaNames []string
+	if name.NumParts >= 2 {
+		schemaNames = []string{name.Parts[1]}
+	} else {
+		iter := oc.planner.CurrentSearchPath().Iter()
+		for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+			schemaNames = append(schemaNames, scName)
+		}
+	}
+
+	// The overloads in the first schema that contains any are the ones that the
+	// name resolves to.
+	for _, scName := range schemaNames {
+		found, sc, err := oc.planner.Descriptors().GetSchemaByName(
+			ctx, oc.planner.txn, dbDesc.GetID(), scName, oc.planner.CommonLookupFlags(false /* required */),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !found || sc.Kind == catalog.SchemaTemporary {
+			continue
+		}
+		var overloads []cat.Function
+		for _, overload := range info.Overloads {
+			if overload.SchemaID != sc.ID {
+				continue
+			}
+			desc, err := oc.planner.Descriptors().GetFunctionVersionByID(
+				ctx, oc.planner.txn, overload.ID, tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			fn, err := oc.newOptFunction(ctx, desc)
+			if err != nil {
+				return nil, err
+			}
+			overloads = append(overloads, fn)
+		}
+		if len(overloads) > 0 {
+			return overloads, nil
+		}
+	}
+	return nil, nil
+}
+
 func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, error) {
 	switch t := o.(type) {
 	case *optSchema:
@@ -271,6 +343,8 @@ func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, e
 		return t.desc, nil
 	case *optSequence:
 		return t.desc, nil
+	case *optFunction:
+		return t.desc, nil
 	default:
 		return nil, errors.AssertionFailedf("invalid object type: %T", o)
 	}
@@ -556,6 +630,104 @@ func (os *optSequence) Name() tree.Name {
 // SequenceMarker is part of the cat.Sequence interface.
 func (os *optSequence) SequenceMarker() {}
 
+// optFunction is a wrapper around funcdesc.Immutable that implements the
+// cat.Object and cat.Function interfaces.
+type optFunction struct {
+	desc *funcdesc.Immutable
+
+	// argTypes and retType are the types of the function signature, with any
+	// user-defined types hydrated.
+	argTypes []*types.T
+	retType  *types.T
+}
+
+var _ cat.Function = &optFunction{}
+
+func (oc *optCatalog) newOptFunction(
+	ctx context.Context, desc *funcdesc.Immutable,
+) (*optFunction, error) {
+	// The types in the descriptor are shared, so user-defined types are
+	// resolved again rather than hydrated in place.
+	hydrate := func(typ *types.T) (*types.T, error) {
+		if !typ.UserDefined() {
+			return typ, nil
+		}
+		return oc.planner.ResolveTypeByOID(ctx, typ.Oid())
+	}
+	fn := &optFunction{desc: desc, argTypes: make([]*types.T, len(desc.Args))}
+	for i := range desc.Args {
+		typ, err := hydrate(desc.Args[i].Type)
+		if err != nil {
+			return nil, err
+		}
+		fn.argTypes[i] = typ
+	}
+	var err error
+	if fn.retType, err = hydrate(desc.ReturnType); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+// ID is part of the cat.Object interface.
+func (of *optFunction) ID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (of *optFunction) PostgresDescriptorID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// Equals is part of the cat.Object interface.
+func (of *optFunction) Equals(other cat.Object) bool {
+	otherFn, ok := other.(*optFunction)
+	if !ok {
+		return false
+	}
+	return of.desc.ID == otherFn.desc.ID && of.desc.Version == otherFn.desc.Version
+}
+
+// Name is part of the cat.Function interface.
+func (of *optFunction) Name() tree.Name {
+	return tree.Name(of.desc.Name)
+}
+
+// ArgCount is part of the cat.Function interface.
+func (of *optFunction) ArgCount() int {
+	return len(of.argTypes)
+}
+
+// ArgName is part of the cat.Function interface.
+func (of *optFunction) ArgName(i int) tree.Name {
+	return tree.Name(of.desc.Args[i].Name)
+}
+
+// ArgType is part of the cat.Function interface.
+func (of *optFunction) ArgType(i int) *types.T {
+	return of.argTypes[i]
+}
+
+// ReturnType is part of the cat.Function interface.
+func (of *optFunction) ReturnType() *types.T {
+	return of.retType
+}
+
+// Volatility is part of the cat.Function interface.
+func (of *optFunction) Volatility() tree.Volatility {
+	return of.desc.GetVolatility()
+}
+
+// CalledOnNullInput is part of the cat.Function interface.
+func (of *optFunction) CalledOnNullInput() bool {
+	return of.desc.NullInputBehavior == descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+}
+
+// Body is part of the cat.Function interface.
+func (of *optFunction) Body() string {
+	return of.desc.Body
+}
+
 // optTable is a wrapper around sqlbase.Immutable that caches
 // index wrappers and maintains a ColumnID => Column mapping for fast lookup.
 type optTable struct {
@@ -597,6 +769,12 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
+	// triggers are the row-level triggers defined on the table, in the order in
+	// which they fire.
+	triggers []optTrigger
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -770,14 +948,31 @@ func newOptTable(
 	for i := range ot.desc.UniqueWithoutIndexConstraints {
 		u := &ot.desc.UniqueWithoutIndexConstraints[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:              u.Name,
+			table:             ot.ID(),
+			columns:           u.ColumnIDs,
+			withoutIndex:      true,
+			validity:          u.Validity,
+			deferrable:        u.Deferrable,
+			initiallyDeferred: u.InitiallyDeferred,
 		})
 	}
 
+	exclusionConstraints := ot.desc.GetExclusionConstraints()
+	ot.exclusionConstraints = make([]optExclusionConstraint, len(exclusionConstraints))
+	for i := range exclusionConstraints {
+		ot.exclusionConstraints[i] = optExclusionConstraint{
+			desc:  &exclusionConstraints[i],
+			table: ot.ID(),
+		}
+	}
+
+	triggers := ot.desc.GetTriggers()
+	ot.triggers = make([]optTrigger, len(triggers))
+	for i := range triggers {
+		ot.triggers[i] = optTrigger{desc: &triggers[i]}
+	}
+
 	for i := range ot.desc.OutboundFKs {
 		fk := &ot.desc.OutboundFKs[i]
 		ot.outboundFKs = append(ot.outboundFKs, optForeignKeyConstraint{
@@ -790,6 +985,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 	for i := range ot.desc.InboundFKs {
@@ -804,6 +1001,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 
@@ -1068,14 +1267,34 @@ func (ot *optTable) InboundForeignKey(i int) cat.ForeignKeyConstraint {
 
 // UniqueCount is part of the cat.Table interface.
 func (ot *optTable) UniqueCount() int {
-	// TODO(rytaft): return the number of unique constraints (both with and
-	//  without indexes).
-	return 0
+	// TODO(rytaft): include the unique constraints that are enforced by unique
+	//  indexes.
+	return len(ot.uniqueConstraints)
 }
 
 // Unique is part of the cat.Table interface.
 func (ot *optTable) Unique(i int) cat.UniqueConstraint {
-	panic(errors.AssertionFailedf("unique constraint [%d] does not exist", i))
+	return &ot.uniqueConstraints[i]
+}
+
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraintCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
 }
 
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
@@ -1478,6 +1697,9 @@ type optUniqueConstraint struct {
 
 	withoutIndex bool
 	validity     descpb.ConstraintValidity
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1520,6 +1742,96 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrable is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrable() bool {
+	return u.deferrable
+}
+
+// InitiallyDeferred is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) InitiallyDeferred() bool {
+	return u.initiallyDeferred
+}
+
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// exclusion constraint.
+type optExclusionConstraint struct {
+	desc  *descpb.ExclusionConstraint
+	table cat.StableID
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.desc.Name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.desc.ColumnIDs)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := tab.(*optTable)
+	ord, _ := optTab.lookupColumnOrdinal(e.desc.ColumnIDs[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return e.desc.Operator(i)
+}
+
+// optTrigger implements cat.Trigger and represents a row-level trigger.
+type optTrigger struct {
+	desc *descpb.TriggerDescriptor
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+// Name is part of the cat.Trigger interface.
+func (t *optTrigger) Name() tree.Name {
+	return tree.Name(t.desc.Name)
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (t *optTrigger) ActionTime() tree.TriggerActionTime {
+	if t.desc.ActionTime == descpb.TriggerDescriptor_BEFORE {
+		return tree.TriggerBefore
+	}
+	return tree.TriggerAfter
+}
+
+// Fires is part of the cat.Trigger interface.
+func (t *optTrigger) Fires(event tree.TriggerEvent) bool {
+	switch event {
+	case tree.TriggerInsert:
+		return t.desc.OnInsert
+	case tree.TriggerUpdate:
+		return t.desc.OnUpdate
+	case tree.TriggerDelete:
+		return t.desc.OnDelete
+	}
+	return false
+}
+
+// When is part of the cat.Trigger interface.
+func (t *optTrigger) When() string {
+	return t.desc.WhenExpr
+}
+
+// Body is part of the cat.Trigger interface.
+func (t *optTrigger) Body() string {
+	return t.desc.Body
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1537,6 +1849,9 @@ type optForeignKeyConstraint struct {
 	match        descpb.ForeignKeyReference_Match
 	deleteAction descpb.ForeignKeyReference_Action
 	updateAction descpb.ForeignKeyReference_Action
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1608,6 +1923,16 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrable is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrable() bool {
+	return fk.deferrable
+}
+
+// InitiallyDeferred is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) InitiallyDeferred() bool {
+	return fk.initiallyDeferred
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc *tabledesc.Immutable
@@ -1902,6 +2227,26 @@ func (ot *optVirtualTable) Unique(i int) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
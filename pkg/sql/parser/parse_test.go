@@ -229,6 +229,10 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
@@ -237,12 +241,19 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE WITHOUT INDEX (b, c))`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE WITHOUT INDEX (b, c) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE WITHOUT INDEX (b, c) DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, c INET, EXCLUDE (b WITH =))`},
+		{`CREATE TABLE a (b INT8, c INET, EXCLUDE USING gist (b WITH =, c WITH &&))`},
+		{`CREATE TABLE a (b INT8, c INET, CONSTRAINT d EXCLUDE USING gist (b WITH =, c WITH &&))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT ON UPDATE RESTRICT)`},
@@ -442,6 +453,22 @@ func TestParse(t *testing.T) {
 		{`DROP TYPE IF EXISTS db.sc.a, sc.a CASCADE`},
 		{`DROP TYPE IF EXISTS db.sc.a, sc.a RESTRICT`},
 
+		{`CREATE FUNCTION f() RETURNS INT8 AS 'SELECT 1'`},
+		{`CREATE FUNCTION sc.f(a INT8, b STRING) RETURNS STRING LANGUAGE SQL IMMUTABLE LEAKPROOF STRICT AS 'SELECT b || a::STRING'`},
+		{`CREATE OR REPLACE FUNCTION f(INT8, typ) RETURNS BOOL STABLE NOT LEAKPROOF CALLED ON NULL INPUT AS 'SELECT $1 > 0'`},
+		{`CREATE FUNCTION db.sc.f(a INT8) RETURNS INT8 VOLATILE RETURNS NULL ON NULL INPUT AS 'SELECT a FROM t'`},
+
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION IF EXISTS db.sc.f(INT8, STRING), g CASCADE`},
+		{`DROP FUNCTION f(INT8) RESTRICT`},
+
+		{`CREATE TRIGGER tr AFTER INSERT ON t FOR EACH ROW AS 'INSERT INTO audit VALUES (NEW.a)'`},
+		{`CREATE TRIGGER tr BEFORE INSERT OR UPDATE OR DELETE ON db.sc.t FOR EACH ROW AS 'DELETE FROM u'`},
+		{`CREATE TRIGGER tr AFTER UPDATE ON t FOR EACH ROW WHEN (new.a != old.a) AS 'UPDATE u SET b = b + 1'`},
+		{`DROP TRIGGER tr ON t`},
+		{`DROP TRIGGER IF EXISTS tr ON sc.t CASCADE`},
+
 		{`DELETE FROM a`},
 		{`EXPLAIN DELETE FROM a`},
 		{`DELETE FROM a.b`},
@@ -458,6 +485,13 @@ func TestParse(t *testing.T) {
 
 		{`DISCARD ALL`},
 
+		{`LISTEN a`},
+		{`UNLISTEN a`},
+		{`UNLISTEN *`},
+		{`NOTIFY a`},
+		{`NOTIFY a, 'hello'`},
+		{`NOTIFY "B", 'it''s'`},
+
 		{`DROP DATABASE a`},
 		{`EXPLAIN DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
@@ -768,6 +802,8 @@ func TestParse(t *testing.T) {
 
 		// GRANT ON TYPE.
 		{`GRANT USAGE ON TYPE foo TO root`},
+		{`GRANT EXECUTE ON FUNCTION f(INT8), sc.g TO root`},
+		{`REVOKE EXECUTE ON FUNCTION f() FROM root`},
 		{`GRANT USAGE, GRANT ON TYPE foo TO root`},
 		{`GRANT ALL ON TYPE foo TO root`},
 
@@ -1122,6 +1158,14 @@ func TestParse(t *testing.T) {
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
 		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`},
+		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b, (c, d))`},
+		{`SELECT 1 FROM t GROUP BY CUBE (a, b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS ((a, b), (a), ())`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (a, ROLLUP (b), CUBE (c), GROUPING SETS (d, ()))`},
+		{`SELECT a, GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT rollup(a) FROM t GROUP BY a`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -1281,6 +1325,11 @@ func TestParse(t *testing.T) {
 		{`SET TRANSACTION NOT DEFERRABLE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, PRIORITY HIGH, AS OF SYSTEM TIME '-1s', NOT DEFERRABLE`},
 
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a DEFERRED`},
+		{`SET CONSTRAINTS a, b IMMEDIATE`},
+
 		{`SET TRACING = off`},
 		{`EXPLAIN SET TRACING = off`},
 		{`SET TRACING = 'cluster', 'kv'`},
@@ -1411,6 +1460,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE IF EXISTS a ADD COLUMN b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE a ADD COLUMN b INT8 UNIQUE WITHOUT INDEX, ADD CONSTRAINT a_no_idx UNIQUE WITHOUT INDEX (a)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =)`},
 		{`ALTER TABLE a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a) NOT VALID`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
@@ -1661,6 +1711,8 @@ func TestParse(t *testing.T) {
 		{`SHOW BACKUP RANGES 'bar'`},
 		{`SHOW BACKUP FILES 'bar'`},
 		{`SHOW BACKUP FILES 'bar' WITH foo = 'bar'`},
+		{`SHOW BACKUP TIMESTAMPS 'bar'`},
+		{`SHOW BACKUP TIMESTAMPS $1 WITH encryption_passphrase = 'secret'`},
 
 		{`SHOW BACKUPS IN 'bar'`},
 		{`SHOW BACKUPS IN $1`},
@@ -1779,6 +1831,12 @@ func TestParse2(t *testing.T) {
 	}{
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
+		{`CREATE FUNCTION f(IN a INT) RETURNS INT AS $$ SELECT a $$ LANGUAGE 'sql'`,
+			`CREATE FUNCTION f(a INT8) RETURNS INT8 AS ' SELECT a ' LANGUAGE SQL`},
+		{`DROP FUNCTION f(a INT, STRING)`,
+			`DROP FUNCTION f(INT8, STRING)`},
+		{`CREATE TRIGGER tr AFTER DELETE ON t FOR EACH ROW WHEN (OLD.a > 0) AS $$ INSERT INTO audit VALUES (OLD.a) $$`,
+			`CREATE TRIGGER tr AFTER DELETE ON t FOR EACH ROW WHEN (old.a > 0) AS ' INSERT INTO audit VALUES (OLD.a) '`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
@@ -1807,6 +1865,12 @@ func TestParse2(t *testing.T) {
 			`CREATE DATABASE a PRIMARY REGION = "us-west-1"`,
 			`CREATE DATABASE a PRIMARY REGION "us-west-1"`,
 		},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`},
 		{`CREATE TABLE a (b INT) WITH (fillfactor=100)`,
 			`CREATE TABLE a (b INT8)`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
@@ -2285,6 +2349,8 @@ $function$`,
 			`DEALLOCATE a`},
 		{`DEALLOCATE PREPARE ALL`,
 			`DEALLOCATE ALL`},
+		{`NOTIFY a, ''`,
+			`NOTIFY a`},
 
 		{`CANCEL JOB a`, `CANCEL JOBS VALUES (a)`},
 		{`EXPLAIN CANCEL JOB a`, `EXPLAIN CANCEL JOBS VALUES (a)`},
@@ -2500,6 +2566,10 @@ $function$`,
 			`RESTORE TABLE foo, baz FROM 'bar'`},
 		{`RESTORE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`,
 			`RESTORE TABLE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`},
+		{`RESTORE TABLE foo AS foo_restored FROM 'bar'`,
+			`RESTORE TABLE foo AS foo_restored FROM 'bar'`},
+		{`RESTORE TABLE db.foo AS foo2, db.sc.baz AS baz2 FROM 'bar' IN $1 AS OF SYSTEM TIME '1' WITH into_db = 'other'`,
+			`RESTORE TABLE db.foo AS foo2, db.sc.baz AS baz2 FROM 'bar' IN $1 AS OF SYSTEM TIME '1' WITH into_db='other'`},
 		{`BACKUP foo TO 'bar' WITH ENCRYPTION_PASSPHRASE = 'secret', revision_history`,
 			`BACKUP TABLE foo TO 'bar' WITH revision_history, encryption_passphrase='secret'`},
 		{`BACKUP foo TO 'bar' WITH KMS = 'foo', revision_history`,
@@ -2517,6 +2587,10 @@ skip_missing_foreign_keys, skip_missing_sequences, skip_missing_sequence_owners,
 		{`RESTORE foo FROM 'bar' WITH ENCRYPTION_PASSPHRASE = 'secret', INTO_DB=baz,
 SKIP_MISSING_FOREIGN_KEYS, SKIP_MISSING_SEQUENCES, SKIP_MISSING_SEQUENCE_OWNERS, SKIP_MISSING_VIEWS`,
 			`RESTORE TABLE foo FROM 'bar' WITH encryption_passphrase='secret', into_db='baz', skip_missing_foreign_keys, skip_missing_sequence_owners, skip_missing_sequences, skip_missing_views`},
+		{`RESTORE foo FROM 'bar' WITH verify_backup_table_data`,
+			`RESTORE TABLE foo FROM 'bar' WITH verify_backup_table_data`},
+		{`RESTORE FROM 'bar' WITH VERIFY_BACKUP_TABLE_DATA, ENCRYPTION_PASSPHRASE = 'secret'`,
+			`RESTORE FROM 'bar' WITH encryption_passphrase='secret', verify_backup_table_data`},
 
 		{`CREATE CHANGEFEED FOR foo INTO 'sink'`, `CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
 
@@ -3134,7 +3208,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		hint     string
 	}{
 		{`ALTER TABLE a ALTER CONSTRAINT foo`, 31632, `alter constraint`, ``},
-		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =)`, 46657, `add constraint exclude using`, ``},
 		{`ALTER TABLE a INHERITS b`, 22456, `alter table inherits`, ``},
 		{`ALTER TABLE a NO INHERITS b`, 22456, `alter table no inherits`, ``},
 
@@ -3149,8 +3222,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE DEFAULT CONVERSION a`, 0, `create def conv`, ``},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`, ``},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`, ``},
-		{`CREATE FUNCTION a`, 17511, `create`, ``},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
+		{`CREATE FUNCTION a() RETURNS INT LANGUAGE plpgsql AS 'a'`, 17511, `create function language plpgsql`, ``},
+		{`CREATE FUNCTION a(OUT b INT) RETURNS INT AS 'a'`, 17511, `out function argument`, ``},
+		{`CREATE FUNCTION a(VARIADIC b INT[]) RETURNS INT AS 'a'`, 17511, `variadic function argument`, ``},
+		{`CREATE FUNCTION a(b INT DEFAULT 1) RETURNS INT AS 'a'`, 17511, `function argument default`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
 		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
@@ -3159,7 +3234,8 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`, ``},
 		{`CREATE TABLESPACE a`, 54113, `create tablespace`, ``},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`, ``},
-		{`CREATE TRIGGER a`, 28296, `create`, ``},
+		{`CREATE TRIGGER a AFTER TRUNCATE ON b FOR EACH ROW AS 'c'`, 28296, `truncate`, ``},
+		{`CREATE TRIGGER a AFTER UPDATE OF c ON b FOR EACH ROW AS 'c'`, 28296, `update of`, ``},
 
 		{`DROP ACCESS METHOD a`, 0, `drop access method`, ``},
 		{`DROP AGGREGATE a`, 0, `drop aggregate`, ``},
@@ -3170,7 +3246,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`, ``},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`, ``},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`, ``},
-		{`DROP FUNCTION a`, 17511, `drop `, ``},
 		{`DROP LANGUAGE a`, 17511, `drop language a`, ``},
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
 		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
@@ -3178,14 +3253,12 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
-		{`DROP TRIGGER a`, 28296, `drop`, ``},
 
 		{`DISCARD PLANS`, 0, `discard plans`, ``},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`, ``},
 		{`DISCARD TEMP`, 0, `discard temp`, ``},
 		{`DISCARD TEMPORARY`, 0, `discard temp`, ``},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`, ``},
 		{`SET LOCAL foo = bar`, 32562, ``, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`, ``},
 
@@ -3200,13 +3273,8 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`, ``},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`, ``},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable unique index`, ``},
+		{`CREATE TABLE a(b INT8, UNIQUE (b) INITIALLY DEFERRED)`, 31632, `deferrable unique index`, ``},
 
 		{`CREATE TABLE a (LIKE b INCLUDING COMMENTS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING IDENTITY)`, 47071, `like table`, ``},
@@ -3267,16 +3335,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`, ``},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`, ``},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`, ``},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
 
-		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY CUBE (b)`, 46280, `cube`, ``},
-		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`, 46280, `grouping sets`, ``},
-
 		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`, 6224, ``, ``},
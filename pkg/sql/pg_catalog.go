@@ -706,7 +706,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -762,6 +761,9 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		condeferrable := tree.DBoolFalse
+		condeferred := tree.DBoolFalse
+		conexclop := tree.DNull
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -793,6 +795,8 @@ func populateTableConstraints(
 				conindid = h.IndexOid(con.ReferencedTable.ID, idx.ID)
 			}
 			confrelid = tableOid(con.ReferencedTable.ID)
+			condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+			condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 			if r, ok := fkActionMap[con.FK.OnUpdate]; ok {
 				confupdtype = r
 			}
@@ -850,6 +854,13 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				writeConstraintDeferrability(
+					&f.Buffer,
+					con.UniqueWithoutIndexConstraint.Deferrable,
+					con.UniqueWithoutIndexConstraint.InitiallyDeferred,
+				)
+				condeferrable = tree.MakeDBool(tree.DBool(con.UniqueWithoutIndexConstraint.Deferrable))
+				condeferred = tree.MakeDBool(tree.DBool(con.UniqueWithoutIndexConstraint.InitiallyDeferred))
 			} else {
 				return errors.AssertionFailedf(
 					"Index or UniqueWithoutIndexConstraint must be non-nil for a unique constraint",
@@ -874,6 +885,32 @@ func populateTableConstraints(
 				validity = " NOT VALID"
 			}
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
+
+		case descpb.ConstraintTypeExclusion:
+			oid = h.ExclusionConstraintOid(db.GetID(), scName, table.GetID(), con.ExclusionConstraint)
+			contype = conTypeExclusion
+			if conkey, err = colIDArrayToDatum(con.ExclusionConstraint.ColumnIDs); err != nil {
+				return err
+			}
+			ops := tree.NewDArray(types.Oid)
+			returnType := tree.NewDOid(tree.DInt(types.Bool.Oid()))
+			for i, colID := range con.ExclusionConstraint.ColumnIDs {
+				col, err := table.FindColumnByID(colID)
+				if err != nil {
+					return err
+				}
+				typ := tree.NewDOid(tree.DInt(col.Type.Oid()))
+				opName := con.ExclusionConstraint.Operator(i).String()
+				if err := ops.Append(h.OperatorOid(opName, typ, typ, returnType)); err != nil {
+					return err
+				}
+			}
+			conexclop = ops
+			var buf bytes.Buffer
+			if err := showExclusionConstraint(&buf, table, con.ExclusionConstraint); err != nil {
+				return err
+			}
+			condef = tree.NewDString(buf.String())
 		}
 
 		if err := addRow(
@@ -881,8 +918,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -899,7 +936,7 @@ func populateTableConstraints(
 			tree.DNull,     // conpfeqop
 			tree.DNull,     // conppeqop
 			tree.DNull,     // conffeqop
-			tree.DNull,     // conexclop
+			conexclop,      // conexclop
 			conbin,         // conbin
 			consrc,         // consrc
 			condef,         // condef
@@ -2757,6 +2794,7 @@ const (
 	collationTypeTag
 	operatorTypeTag
 	enumEntryTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2795,6 +2833,11 @@ func (h oidHasher) writeCheckConstraint(check *descpb.TableDescriptor_CheckConst
 	h.writeStr(check.Expr)
 }
 
+func (h oidHasher) writeExclusionConstraint(ec *descpb.ExclusionConstraint) {
+	h.writeUInt32(uint32(ec.TableID))
+	h.writeStr(ec.Name)
+}
+
 func (h oidHasher) writeForeignKeyConstraint(fk *descpb.ForeignKeyConstraint) {
 	h.writeUInt32(uint32(fk.ReferencedTableID))
 	h.writeStr(fk.Name)
@@ -2865,6 +2908,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scName string, tableID descpb.ID, ec *descpb.ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scName)
+	h.writeTable(tableID)
+	h.writeExclusionConstraint(ec)
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scName string, tableID descpb.ID, indexID descpb.IndexID,
 ) *tree.DOid {
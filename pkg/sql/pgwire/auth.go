@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -30,6 +31,16 @@ const (
 	// authCleartextPassword is the pgwire auth response code to request
 	// a plaintext password during the connection handshake.
 	authCleartextPassword int32 = 3
+	// authSASL is the pgwire auth response code to start a SASL
+	// authentication exchange. It is followed by the list of SASL
+	// mechanisms supported by the server.
+	authSASL int32 = 10
+	// authSASLContinue is the pgwire auth response code carrying a SASL
+	// challenge during the exchange.
+	authSASLContinue int32 = 11
+	// authSASLFinal is the pgwire auth response code carrying the outcome of
+	// a successful SASL exchange.
+	authSASLFinal int32 = 12
 )
 
 type authOptions struct {
@@ -93,6 +104,7 @@ func (c *conn) handleAuthentication(
 
 	if !exists {
 		ac.Logf(ctx, "user does not exist: %q", c.sessionArgs.User)
+		c.mockSCRAMExchange(ctx, ac, authOpt, execCfg)
 		return nil, sendError(errors.Errorf(security.ErrPasswordUserAuthFailed, c.sessionArgs.User))
 	}
 
@@ -130,6 +142,27 @@ func (c *conn) handleAuthentication(
 	return connClose, c.msgBuilder.finishMsg(c.conn)
 }
 
+// mockSCRAMExchange carries out the SCRAM exchange of the HBA rule matching
+// the connection, if it selects one, for a user which does not exist. As in
+// PostgreSQL, the exchange is run against a mock verifier and always fails,
+// so that the client cannot tell that the user does not exist from the
+// absence of the exchange.
+func (c *conn) mockSCRAMExchange(
+	ctx context.Context, ac AuthConn, authOpt authOptions, execCfg *sql.ExecutorConfig,
+) {
+	tlsState, hbaEntry, methodFn, err := c.findAuthenticationMethod(authOpt)
+	if err != nil || !usesSCRAMExchange(hbaEntry, tlsState) {
+		return
+	}
+	noPassword := func(context.Context) ([]byte, error) { return nil, nil }
+	noExpiry := func(context.Context) (*tree.DTimestamp, error) { return nil, nil }
+	hook, err := methodFn(ctx, ac, tlsState, noPassword, noExpiry, execCfg, hbaEntry)
+	if err != nil {
+		return
+	}
+	_, _ = hook(c.sessionArgs.User, true /* clientConnection */)
+}
+
 func (c *conn) findAuthenticationMethod(
 	authOpt authOptions,
 ) (tlsState tls.ConnectionState, hbaEntry *hba.Entry, methodFn AuthMethod, err error) {
@@ -19,7 +19,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -42,6 +46,12 @@ func loadDefaultMethods() {
 	// method over secure connections, e.g. those encrypted using SSL.
 	RegisterAuthMethod("password", authPassword, hba.ConnAny, nil)
 
+	// The "scram-sha-256" method performs a SCRAM-SHA-256 exchange as
+	// defined by RFC 7677, so that the password is never sent over the
+	// connection. It requires the password of the user to be stored as a
+	// SCRAM verifier, see server.user_login.password_encryption.
+	RegisterAuthMethod("scram-sha-256", authSCRAMSHA256, hba.ConnAny, nil)
+
 	// The "cert" method requires a valid client certificate for the
 	// user attempting to connect.
 	//
@@ -53,6 +63,10 @@ func loadDefaultMethods() {
 	// a cleartext password.
 	RegisterAuthMethod("cert-password", authCertPassword, hba.ConnAny, nil)
 
+	// The "cert-scram-sha-256" method is like "cert-password" but uses
+	// the "scram-sha-256" method when no certificate is provided.
+	RegisterAuthMethod("cert-scram-sha-256", authCertSCRAMSHA256, hba.ConnAny, nil)
+
 	// The "reject" method rejects any connection attempt that matches
 	// the current rule.
 	RegisterAuthMethod("reject", authReject, hba.ConnAny, nil)
@@ -87,7 +101,7 @@ func authPassword(
 	_ tls.ConnectionState,
 	pwRetrieveFn PasswordRetrievalFn,
 	pwValidUntilFn PasswordValidUntilFn,
-	_ *sql.ExecutorConfig,
+	execCfg *sql.ExecutorConfig,
 	_ *hba.Entry,
 ) (security.UserAuthHook, error) {
 	if err := c.SendAuthRequest(authCleartextPassword, nil /* data */); err != nil {
@@ -109,20 +123,183 @@ func authPassword(
 		c.Logf(ctx, "user has no password defined")
 	}
 
+	if err := checkPasswordExpiry(ctx, c, pwValidUntilFn); err != nil {
+		return nil, err
+	}
+
+	hook := security.UserAuthPasswordHook(
+		false /*insecure*/, password, hashedPassword,
+	)
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		connClose, err := hook(requestedUser, clientConnection)
+		if err != nil {
+			return connClose, err
+		}
+		// Now that we know the cleartext password is correct, take the
+		// opportunity to convert the stored bcrypt hash to a SCRAM verifier
+		// if so configured. A failure here does not prevent the user from
+		// logging in.
+		upgraded, upgradeErr := sql.MaybeUpgradeStoredPasswordHash(
+			ctx, execCfg, requestedUser, password, hashedPassword)
+		if upgradeErr != nil {
+			log.Warningf(ctx, "unable to upgrade stored password hash: %v", upgradeErr)
+		} else if upgraded {
+			c.Logf(ctx, "stored password hash upgraded to SCRAM-SHA-256")
+		}
+		return connClose, nil
+	}, nil
+}
+
+// checkPasswordExpiry returns an error if the user's password has expired.
+func checkPasswordExpiry(
+	ctx context.Context, c AuthConn, pwValidUntilFn PasswordValidUntilFn,
+) error {
 	validUntil, err := pwValidUntilFn(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if validUntil != nil {
 		if validUntil.Sub(timeutil.Now()) < 0 {
 			c.Logf(ctx, "password is expired")
-			return nil, errors.New("password is expired")
+			return errors.New("password is expired")
 		}
 	}
+	return nil
+}
 
-	return security.UserAuthPasswordHook(
-		false /*insecure*/, password, hashedPassword,
-	), nil
+func authSCRAMSHA256(
+	ctx context.Context,
+	c AuthConn,
+	_ tls.ConnectionState,
+	pwRetrieveFn PasswordRetrievalFn,
+	pwValidUntilFn PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	_ *hba.Entry,
+) (security.UserAuthHook, error) {
+	// The exchange is carried out by the hook, which knows the name of the
+	// user logging in: it is needed to derive the mock verifier used when the
+	// user has no SCRAM verifier.
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		if requestedUser.Undefined() {
+			return nil, errors.New("user is missing")
+		}
+		if !clientConnection {
+			return nil, errors.New("password authentication is only available for client connections")
+		}
+		return nil, scramExchange(ctx, c, requestedUser, pwRetrieveFn, pwValidUntilFn, execCfg)
+	}, nil
+}
+
+// scramExchange carries out a SCRAM-SHA-256 exchange with the client, and
+// returns an error unless the client proved it knows the user's password.
+func scramExchange(
+	ctx context.Context,
+	c AuthConn,
+	user security.SQLUsername,
+	pwRetrieveFn PasswordRetrievalFn,
+	pwValidUntilFn PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+) error {
+	// Advertise the list of supported mechanisms, terminated by an empty
+	// string.
+	mechanisms := []byte(security.SCRAMSHA256MechanismName + "\x00\x00")
+	if err := c.SendAuthRequest(authSASL, mechanisms); err != nil {
+		return err
+	}
+	initialData, err := c.GetPwdData()
+	if err != nil {
+		return err
+	}
+	mechanism, clientFirst, err := parseSASLInitialResponse(initialData)
+	if err != nil {
+		return err
+	}
+	if mechanism != security.SCRAMSHA256MechanismName {
+		return pgerror.Newf(pgcode.ProtocolViolation,
+			"client selected an invalid SASL authentication mechanism: %q", mechanism)
+	}
+
+	hashedPassword, err := pwRetrieveFn(ctx)
+	if err != nil {
+		return err
+	}
+	// If the user has no SCRAM verifier, the exchange is still carried out
+	// against a mock verifier, so that the client cannot tell missing
+	// passwords apart from incorrect ones before the end of the exchange.
+	verifierOK := true
+	var verifier security.SCRAMHash
+	if security.IsSCRAMHash(hashedPassword) {
+		verifier, err = security.ParseSCRAMHash(hashedPassword)
+		if err != nil {
+			c.Logf(ctx, "invalid stored SCRAM verifier: %v", err)
+			verifierOK = false
+		}
+	} else {
+		if len(hashedPassword) == 0 {
+			c.Logf(ctx, "user has no password defined")
+		} else {
+			c.Logf(ctx, "user password is not stored as a SCRAM verifier")
+		}
+		verifierOK = false
+	}
+	if !verifierOK {
+		verifier, err = security.MakeMockSCRAMHash(
+			user.Normalized(), []byte(sql.ClusterSecret.Get(&execCfg.Settings.SV)))
+		if err != nil {
+			return err
+		}
+	}
+
+	server, err := security.NewSCRAMServer(verifier)
+	if err != nil {
+		return err
+	}
+	serverFirst, err := server.ServerFirstMessage(clientFirst)
+	if err != nil {
+		return pgerror.WithCandidateCode(err, pgcode.ProtocolViolation)
+	}
+	if err := c.SendAuthRequest(authSASLContinue, serverFirst); err != nil {
+		return err
+	}
+	clientFinal, err := c.GetPwdData()
+	if err != nil {
+		return err
+	}
+	serverFinal, err := server.ServerFinalMessage(clientFinal)
+	if err != nil && !errors.Is(err, security.ErrSCRAMAuthFailed) {
+		return pgerror.WithCandidateCode(err, pgcode.ProtocolViolation)
+	}
+	if err != nil || !verifierOK {
+		return errors.Errorf(security.ErrPasswordUserAuthFailed, user)
+	}
+
+	if err := checkPasswordExpiry(ctx, c, pwValidUntilFn); err != nil {
+		return err
+	}
+	return c.SendAuthRequest(authSASLFinal, serverFinal)
+}
+
+// parseSASLInitialResponse parses the body of a SASLInitialResponse
+// message, which contains the name of the mechanism selected by the client
+// followed by the length-prefixed initial response of the mechanism.
+func parseSASLInitialResponse(data []byte) (mechanism string, response []byte, _ error) {
+	buf := pgwirebase.ReadBuffer{Msg: data}
+	mechanism, err := buf.GetString()
+	if err != nil {
+		return "", nil, err
+	}
+	length, err := buf.GetUint32()
+	if err != nil {
+		return "", nil, err
+	}
+	if int32(length) == -1 {
+		return mechanism, nil, nil
+	}
+	response, err = buf.GetBytes(int(length))
+	if err != nil {
+		return "", nil, err
+	}
+	return mechanism, response, nil
 }
 
 func passwordString(pwdData []byte) (string, error) {
@@ -172,6 +349,39 @@ func authCertPassword(
 	return fn(ctx, c, tlsState, pwRetrieveFn, pwValidUntilFn, execCfg, entry)
 }
 
+func authCertSCRAMSHA256(
+	ctx context.Context,
+	c AuthConn,
+	tlsState tls.ConnectionState,
+	pwRetrieveFn PasswordRetrievalFn,
+	pwValidUntilFn PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	entry *hba.Entry,
+) (security.UserAuthHook, error) {
+	var fn AuthMethod
+	if len(tlsState.PeerCertificates) == 0 {
+		c.Logf(ctx, "no client certificate, proceeding with SCRAM authentication")
+		fn = authSCRAMSHA256
+	} else {
+		c.Logf(ctx, "client presented certificate, proceeding with certificate validation")
+		fn = authCert
+	}
+	return fn(ctx, c, tlsState, pwRetrieveFn, pwValidUntilFn, execCfg, entry)
+}
+
+// usesSCRAMExchange returns whether the method of the given HBA entry
+// authenticates the connection with a SCRAM exchange.
+func usesSCRAMExchange(entry *hba.Entry, tlsState tls.ConnectionState) bool {
+	switch entry.Method.Value {
+	case "scram-sha-256":
+		return true
+	case "cert-scram-sha-256":
+		return len(tlsState.PeerCertificates) == 0
+	default:
+		return false
+	}
+}
+
 func authTrust(
 	_ context.Context,
 	_ AuthConn,
@@ -0,0 +1,242 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthSCRAMSHA256 checks that clients can authenticate using the
+// scram-sha-256 method, and that bcrypt password hashes can be converted to
+// SCRAM verifiers upon login.
+//
+// The datadriven auth tests cannot exercise this method since lib/pq does
+// not support SCRAM, so this test uses pgx instead.
+func TestAuthSCRAMSHA256(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE USER bcrypt_user WITH PASSWORD 'abc'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.user_login.password_encryption = 'scram-sha-256'`)
+	sqlDB.Exec(t, `CREATE USER scram_user WITH PASSWORD 'abc'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = $1`,
+		"host all bcrypt_user all password\nhost all all all scram-sha-256")
+
+	storedHash := func(user string) []byte {
+		var hash []byte
+		sqlDB.QueryRow(t, `SELECT "hashedPassword" FROM system.users WHERE username = $1`,
+			user).Scan(&hash)
+		return hash
+	}
+	require.True(t, security.IsSCRAMHash(storedHash("scram_user")))
+	require.False(t, security.IsSCRAMHash(storedHash("bcrypt_user")))
+
+	connect := func(user, password string) error {
+		url := fmt.Sprintf("postgres://%s:%s@%s/defaultdb?sslmode=require",
+			user, password, s.ServingSQLAddr())
+		conn, err := pgx.Connect(ctx, url)
+		if err != nil {
+			return err
+		}
+		var n int
+		if err := conn.QueryRow(ctx, "SELECT 1").Scan(&n); err != nil {
+			return err
+		}
+		return conn.Close(ctx)
+	}
+
+	// Wait for the HBA configuration to be in effect.
+	testutils.SucceedsSoon(t, func() error { return connect("scram_user", "abc") })
+	require.Regexp(t, `password authentication failed for user scram_user`,
+		connect("scram_user", "wrong"))
+
+	// The bcrypt hash is left alone when the upgrade is not enabled.
+	require.NoError(t, connect("bcrypt_user", "abc"))
+	require.False(t, security.IsSCRAMHash(storedHash("bcrypt_user")))
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.user_login.upgrade_bcrypt_stored_passwords_to_scram = true`)
+	testutils.SucceedsSoon(t, func() error {
+		if err := connect("bcrypt_user", "abc"); err != nil {
+			return err
+		}
+		if !security.IsSCRAMHash(storedHash("bcrypt_user")) {
+			return fmt.Errorf("password hash not upgraded yet")
+		}
+		return nil
+	})
+
+	// Now that its hash has been upgraded, the user can use scram-sha-256.
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = $1`,
+		"host all all all scram-sha-256")
+	testutils.SucceedsSoon(t, func() error { return connect("bcrypt_user", "abc") })
+}
+
+// TestAuthSCRAMSHA256MockSalt checks that the salt presented to clients
+// logging in as a user which does not exist, or has no SCRAM verifier, is the
+// same across attempts, so that it does not give away which users exist.
+func TestAuthSCRAMSHA256MockSalt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE USER bcrypt_user WITH PASSWORD 'abc'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.user_login.password_encryption = 'scram-sha-256'`)
+	sqlDB.Exec(t, `CREATE USER scram_user WITH PASSWORD 'abc'`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = $1`,
+		"host all all all scram-sha-256")
+
+	// salt starts a SCRAM exchange as the given user, and returns the salt
+	// sent by the server in its server-first-message.
+	salt := func(user string) (string, error) {
+		conn, err := net.Dial("tcp", s.ServingSQLAddr())
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		if _, err := conn.Write((&pgproto3.SSLRequest{}).Encode(nil)); err != nil {
+			return "", err
+		}
+		var response [1]byte
+		if _, err := io.ReadFull(conn, response[:]); err != nil {
+			return "", err
+		}
+		if response[0] != 'S' {
+			return "", errors.Errorf("server refused TLS: %q", response[0])
+		}
+		tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+		fe := pgproto3.NewFrontend(pgproto3.NewChunkReader(tlsConn), tlsConn)
+		if err := fe.Send(&pgproto3.StartupMessage{
+			ProtocolVersion: pgproto3.ProtocolVersionNumber,
+			Parameters:      map[string]string{"user": user, "database": "defaultdb"},
+		}); err != nil {
+			return "", err
+		}
+		msg, err := fe.Receive()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := msg.(*pgproto3.AuthenticationSASL); !ok {
+			return "", errors.Errorf("expected a SASL exchange, got %T", msg)
+		}
+		if err := fe.Send(&pgproto3.SASLInitialResponse{
+			AuthMechanism: security.SCRAMSHA256MechanismName,
+			Data:          []byte("n,,n=,r=rOprNGfwEbeRWgbNEkqO"),
+		}); err != nil {
+			return "", err
+		}
+		msg, err = fe.Receive()
+		if err != nil {
+			return "", err
+		}
+		serverFirst, ok := msg.(*pgproto3.AuthenticationSASLContinue)
+		if !ok {
+			return "", errors.Errorf("expected a server-first-message, got %T", msg)
+		}
+		for _, attr := range strings.Split(string(serverFirst.Data), ",") {
+			if strings.HasPrefix(attr, "s=") {
+				return strings.TrimPrefix(attr, "s="), nil
+			}
+		}
+		return "", errors.Errorf("no salt in %q", serverFirst.Data)
+	}
+
+	// Wait for the HBA configuration to be in effect.
+	var scramSalt string
+	testutils.SucceedsSoon(t, func() error {
+		var err error
+		scramSalt, err = salt("scram_user")
+		return err
+	})
+
+	for _, user := range []string{"bcrypt_user", "nonexistent"} {
+		first, err := salt(user)
+		require.NoError(t, err)
+		second, err := salt(user)
+		require.NoError(t, err)
+		require.Equal(t, first, second, user)
+		require.NotEqual(t, scramSalt, first, user)
+	}
+	unknown, err := salt("nonexistent")
+	require.NoError(t, err)
+	other, err := salt("other_nonexistent")
+	require.NoError(t, err)
+	require.NotEqual(t, unknown, other)
+}
+
+// TestSCRAMHashRequiresClusterVersion checks that passwords are hashed with
+// bcrypt, whatever server.user_login.password_encryption says, until every
+// node is able to verify SCRAM-SHA-256 verifiers.
+func TestSCRAMHashRequiresClusterVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	oldVersion := clusterversion.ByKey(clusterversion.SCRAMAuthentication - 1)
+	newVersion := clusterversion.ByKey(clusterversion.SCRAMAuthentication)
+	st := cluster.MakeTestingClusterSettingsWithVersions(
+		newVersion, oldVersion, false /* initializeVersion */)
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Settings: st,
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				BinaryVersionOverride:          oldVersion,
+				DisableAutomaticVersionUpgrade: 1,
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	storedHash := func(user string) []byte {
+		var hash []byte
+		sqlDB.QueryRow(t, `SELECT "hashedPassword" FROM system.users WHERE username = $1`,
+			user).Scan(&hash)
+		return hash
+	}
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.user_login.password_encryption = 'scram-sha-256'`)
+	sqlDB.Exec(t, `CREATE USER before_upgrade WITH PASSWORD 'abc'`)
+	require.False(t, security.IsSCRAMHash(storedHash("before_upgrade")))
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING version = $1`, newVersion.String())
+	sqlDB.Exec(t, `CREATE USER after_upgrade WITH PASSWORD 'abc'`)
+	require.True(t, security.IsSCRAMHash(storedHash("after_upgrade")))
+}
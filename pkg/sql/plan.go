@@ -147,6 +147,7 @@ var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &changePrivilegesNode{}
 var _ planNode = &createDatabaseNode{}
+var _ planNode = &createFunctionNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
@@ -159,6 +160,7 @@ var _ planNode = &deleteNode{}
 var _ planNode = &deleteRangeNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
+var _ planNode = &dropFunctionNode{}
 var _ planNode = &dropIndexNode{}
 var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
@@ -229,10 +231,12 @@ var _ planNodeReadingOwnWrites = &alterTypeNode{}
 var _ planNodeReadingOwnWrites = &createIndexNode{}
 var _ planNodeReadingOwnWrites = &createSequenceNode{}
 var _ planNodeReadingOwnWrites = &createDatabaseNode{}
+var _ planNodeReadingOwnWrites = &createFunctionNode{}
 var _ planNodeReadingOwnWrites = &createTableNode{}
 var _ planNodeReadingOwnWrites = &createTypeNode{}
 var _ planNodeReadingOwnWrites = &createViewNode{}
 var _ planNodeReadingOwnWrites = &changePrivilegesNode{}
+var _ planNodeReadingOwnWrites = &dropFunctionNode{}
 var _ planNodeReadingOwnWrites = &dropSchemaNode{}
 var _ planNodeReadingOwnWrites = &dropTypeNode{}
 var _ planNodeReadingOwnWrites = &refreshMaterializedViewNode{}
@@ -408,6 +412,10 @@ type planComponents struct {
 	// checkPlans contains all the plans for queries that are to be executed after
 	// the main query (for example, foreign key checks).
 	checkPlans []checkPlan
+
+	// numTriggeredCascades is the number of cascades which are marked as
+	// triggered.
+	numTriggeredCascades int
 }
 
 type cascadeMetadata struct {
@@ -415,12 +423,32 @@ type cascadeMetadata struct {
 	// plan for the cascade. This plan is not populated upfront; it is created
 	// only when it needs to run, after the main query (and previous cascades).
 	plan planMaybePhysical
+	// subqueryPlans are the subqueries of plan, if any. They buffer the input
+	// of a mutation with BEFORE triggers.
+	subqueryPlans []subquery
+	// rowPlans are the plans of a row-level trigger, one for each row for which
+	// the trigger fired. Like plan, they are created only when they need to run.
+	rowPlans []*planComponents
+	// depth is the number of cascades and triggers from which this cascade was
+	// queued; it is zero for the cascades of the main query.
+	depth int
+	// triggered is set if this cascade was queued, directly or not, by the
+	// query of a row-level trigger. Such cascades are queued again for every
+	// row for which the trigger fires, so they don't count toward the cascades
+	// limit; their depth is limited instead.
+	triggered bool
 }
 
 // checkPlan is a query tree that is executed after the main one. It can only
 // return an error (for example, foreign key violation).
 type checkPlan struct {
 	plan planMaybePhysical
+
+	// deferrable is set if the check enforces a deferrable constraint. If the
+	// constraint is deferred in the current transaction, the rows violating it
+	// don't fail the statement; they are validated again at commit time
+	// instead.
+	deferrable *exec.DeferrableCheck
 }
 
 // close calls Close on all plan trees.
@@ -431,6 +459,15 @@ func (p *planComponents) close(ctx context.Context) {
 	}
 	for i := range p.cascades {
 		p.cascades[i].plan.Close(ctx)
+		for j := range p.cascades[i].subqueryPlans {
+			p.cascades[i].subqueryPlans[j].plan.Close(ctx)
+		}
+		for _, rowPlan := range p.cascades[i].rowPlans {
+			rowPlan.main.Close(ctx)
+			for j := range rowPlan.subqueryPlans {
+				rowPlan.subqueryPlans[j].plan.Close(ctx)
+			}
+		}
 	}
 	for i := range p.checkPlans {
 		p.checkPlans[i].plan.Close(ctx)
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
@@ -89,6 +90,15 @@ type extendedEvalContext struct {
 	// SchemaChangeJobCache refers to schemaChangeJobsCache in extraTxnState.
 	SchemaChangeJobCache map[descpb.ID]*jobs.Job
 
+	// DeferredConstraints refers to deferredConstraints in extraTxnState. It is
+	// nil for statements run by internal executors, which never defer constraint
+	// checks.
+	DeferredConstraints *deferredConstraintState
+
+	// Notifications is the listener of the session for notifications sent with
+	// NOTIFY. It is nil for statements run by internal executors.
+	Notifications *notify.Listener
+
 	schemaAccessors *schemaInterface
 
 	sqlStatsCollector *sqlStatsCollector
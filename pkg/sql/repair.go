@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -114,6 +115,8 @@ func (p *planner) UnsafeUpsertDescriptor(
 		md.DatabaseDescriptor = *desc.GetDatabase()
 	case *typedesc.Mutable:
 		md.TypeDescriptor = *desc.GetType()
+	case *funcdesc.Mutable:
+		md.FunctionDescriptor = *desc.GetFunction()
 	case nil:
 		// nolint:descriptormarshal
 		if tableDesc := desc.GetTable(); tableDesc != nil {
@@ -124,6 +127,8 @@ func (p *planner) UnsafeUpsertDescriptor(
 			existing = dbdesc.NewCreatedMutable(*dbDesc)
 		} else if typeDesc := desc.GetType(); typeDesc != nil {
 			existing = typedesc.NewCreatedMutable(*typeDesc)
+		} else if funcDesc := desc.GetFunction(); funcDesc != nil {
+			existing = funcdesc.NewCreatedMutable(*funcDesc)
 		} else {
 			return pgerror.New(pgcode.InvalidTableDefinition, "invalid ")
 		}
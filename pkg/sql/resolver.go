@@ -318,6 +318,21 @@ func getDescriptorsFromTargetListForPrivilegeChange(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]catalog.Descriptor, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			descriptor, err := p.ResolveMutableFunctionDescriptor(ctx, &targets.Functions[i], true /* required */)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
 	if targets.Types != nil {
 		if len(targets.Types) == 0 {
 			return nil, errNoType
@@ -140,9 +140,7 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 		// Promote to FOR_SHARE.
 		fallthrough
 	case descpb.ScanLockingStrength_FOR_SHARE:
-		// We currently perform no per-key locking when FOR_SHARE is used
-		// because Shared locks have not yet been implemented.
-		return lock.None
+		return lock.Shared
 
 	case descpb.ScanLockingStrength_FOR_NO_KEY_UPDATE:
 		// Promote to FOR_UPDATE.
@@ -165,8 +163,7 @@ func (f *txnKVFetcher) getWaitPolicy() lock.WaitPolicy {
 		return lock.WaitPolicy_Block
 
 	case descpb.ScanLockingWaitPolicy_SKIP:
-		// Should not get here. Query should be rejected during planning.
-		panic(errors.AssertionFailedf("unsupported wait policy %s", f.lockWaitPolicy))
+		return lock.WaitPolicy_SkipLocked
 
 	case descpb.ScanLockingWaitPolicy_ERROR:
 		return lock.WaitPolicy_Error
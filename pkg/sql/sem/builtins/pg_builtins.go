@@ -1132,6 +1132,36 @@ SELECT description
 		},
 	),
 
+	// pg_notify sends a notification like the NOTIFY statement. Unlike NOTIFY,
+	// it accepts arbitrary expressions as channel name and payload.
+	// https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-ASYNC-NOTIFY
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlocklist: true,
+			NullableArgs:     true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.SendNotification(ctx.Context, channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "pg_notify sends a notification with the given payload on the given " +
+				"channel. The notification is delivered when the current transaction commits.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	// pg_is_in_recovery returns true if the Postgres database is currently in
 	// recovery.  This is not applicable so this can always return false.
 	// https://www.postgresql.org/docs/current/static/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
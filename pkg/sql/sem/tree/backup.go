@@ -114,13 +114,18 @@ type RestoreOptions struct {
 	SkipMissingSequenceOwners bool
 	SkipMissingViews          bool
 	Detached                  bool
+	VerifyBackupTableData     bool
 }
 
 var _ NodeFormatter = &RestoreOptions{}
 
 // Restore represents a RESTORE statement.
 type Restore struct {
-	Targets            TargetList
+	Targets TargetList
+	// AsNames holds the new names of the tables restored by RESTORE TABLE a AS
+	// b, ..., in the order of Targets.Tables. It is empty when the restored
+	// objects keep their names.
+	AsNames            NameList
 	DescriptorCoverage DescriptorCoverage
 	From               []StringOrPlaceholderOptList
 	AsOf               AsOfClause
@@ -134,7 +139,19 @@ var _ Statement = &Restore{}
 func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
 	if node.DescriptorCoverage == RequestedDescriptors {
-		ctx.FormatNode(&node.Targets)
+		if len(node.AsNames) > 0 {
+			ctx.WriteString("TABLE ")
+			for i := range node.AsNames {
+				if i > 0 {
+					ctx.WriteString(", ")
+				}
+				ctx.FormatNode(node.Targets.Tables[i])
+				ctx.WriteString(" AS ")
+				ctx.FormatNode(&node.AsNames[i])
+			}
+		} else {
+			ctx.FormatNode(&node.Targets)
+		}
 		ctx.WriteString(" ")
 	}
 	ctx.WriteString("FROM ")
@@ -321,6 +338,11 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("detached")
 	}
+
+	if o.VerifyBackupTableData {
+		maybeAddSep()
+		ctx.WriteString("verify_backup_table_data")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -384,6 +406,14 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.Detached = other.Detached
 	}
 
+	if o.VerifyBackupTableData {
+		if other.VerifyBackupTableData {
+			return errors.New("verify_backup_table_data specified multiple times")
+		}
+	} else {
+		o.VerifyBackupTableData = other.VerifyBackupTableData
+	}
+
 	return nil
 }
 
@@ -397,5 +427,6 @@ func (o RestoreOptions) IsDefault() bool {
 		cmp.Equal(o.DecryptionKMSURI, options.DecryptionKMSURI) &&
 		o.EncryptionPassphrase == options.EncryptionPassphrase &&
 		o.IntoDB == options.IntoDB &&
-		o.Detached == options.Detached
+		o.Detached == options.Detached &&
+		o.VerifyBackupTableData == options.VerifyBackupTableData
 }
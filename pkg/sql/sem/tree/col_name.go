@@ -11,6 +11,8 @@
 package tree
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -67,6 +69,14 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *FuncExpr:
 		fd, err := e.Func.Resolve(sp)
 		if err != nil {
+			// The function may be a user-defined function, which cannot be
+			// resolved here. Use the unqualified function name in that case;
+			// if the function does not exist, an error is reported when the
+			// expression is type checked.
+			if n, ok := e.Func.FunctionReference.(*UnresolvedName); ok &&
+				pgerror.GetPGCode(err) == pgcode.UndefinedFunction {
+				return 2, n.Parts[0], nil
+			}
 			return 0, "", err
 		}
 		return 2, fd.Name, nil
@@ -113,6 +123,9 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *ArrayFlatten:
 		return 2, "array", nil
 
+	case *GroupingExpr:
+		return 2, "grouping", nil
+
 	case *Subquery:
 		if e.Exists {
 			return 2, "exists", nil
@@ -330,6 +330,284 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	FuncName   *UnresolvedObjectName
+	Replace    bool
+	Args       FuncArgs
+	ReturnType ResolvableTypeReference
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatTypeReference(node.ReturnType)
+	for _, opt := range node.Options {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(opt)
+	}
+}
+
+// FuncArg represents an argument of a function in a CREATE FUNCTION
+// statement. The name may be empty.
+type FuncArg struct {
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncArgs represents a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an interface for the options of a CREATE FUNCTION
+// statement.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionLanguage) functionOption()          {}
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakProof) functionOption()         {}
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionBody) functionOption()              {}
+
+// FunctionOptions represents a list of function options.
+type FunctionOptions []FunctionOption
+
+// FunctionLanguage is the LANGUAGE option of a CREATE FUNCTION statement.
+// Only SQL is supported.
+type FunctionLanguage string
+
+// FunctionLangSQL is the SQL function language.
+const FunctionLangSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	ctx.WriteString(strings.ToUpper(string(node)))
+}
+
+// FunctionVolatility is the volatility option of a CREATE FUNCTION
+// statement. Only VolatilityImmutable, VolatilityStable and VolatilityVolatile
+// are valid.
+type FunctionVolatility Volatility
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	ctx.WriteString(strings.ToUpper(Volatility(node).String()))
+}
+
+// FunctionLeakProof is the [NOT] LEAKPROOF option of a CREATE FUNCTION
+// statement.
+type FunctionLeakProof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakProof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionNullInputBehavior is the option of a CREATE FUNCTION statement
+// which determines how the function handles NULL arguments.
+type FunctionNullInputBehavior int
+
+const (
+	// FunctionCalledOnNullInput indicates that the function is invoked when
+	// some of its arguments are NULL.
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// without being invoked when any of its arguments is NULL.
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is a synonym for FunctionReturnsNullOnNullInput.
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionBody is the AS option of a CREATE FUNCTION statement, which holds
+// the SQL body of the function.
+type FunctionBody string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBody) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+}
+
+// ValidateFunctionOptions checks that the options of a CREATE FUNCTION
+// statement are neither conflicting nor redundant, and that the function
+// body was specified.
+func ValidateFunctionOptions(options FunctionOptions) error {
+	var seenLang, seenVolatility, seenLeakProof, seenNullInput, seenBody bool
+	for _, option := range options {
+		var seen *bool
+		switch option.(type) {
+		case FunctionLanguage:
+			seen = &seenLang
+		case FunctionVolatility:
+			seen = &seenVolatility
+		case FunctionLeakProof:
+			seen = &seenLeakProof
+		case FunctionNullInputBehavior:
+			seen = &seenNullInput
+		case FunctionBody:
+			seen = &seenBody
+		default:
+			return errors.AssertionFailedf("unknown function option %T", option)
+		}
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+	}
+	if !seenBody {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	return nil
+}
+
+// TriggerActionTime specifies whether a row-level trigger fires before or
+// after the row is modified.
+type TriggerActionTime int
+
+const (
+	// TriggerBefore indicates that the trigger fires before the row is
+	// modified.
+	TriggerBefore TriggerActionTime = iota
+	// TriggerAfter indicates that the trigger fires after the row is modified.
+	TriggerAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerBefore: "BEFORE",
+	TriggerAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEvent is a kind of modification which fires a row-level trigger.
+type TriggerEvent int
+
+const (
+	// TriggerInsert is the INSERT event.
+	TriggerInsert TriggerEvent = iota
+	// TriggerUpdate is the UPDATE event.
+	TriggerUpdate
+	// TriggerDelete is the DELETE event.
+	TriggerDelete
+)
+
+var triggerEventName = [...]string{
+	TriggerInsert: "INSERT",
+	TriggerUpdate: "UPDATE",
+	TriggerDelete: "DELETE",
+}
+
+func (e TriggerEvent) String() string {
+	return triggerEventName[e]
+}
+
+// TriggerEvents represents the list of events of a CREATE TRIGGER statement.
+type TriggerEvents []TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i, e := range *node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.WriteString(e.String())
+	}
+}
+
+// Contains returns true if the list contains the given event.
+func (node TriggerEvents) Contains(e TriggerEvent) bool {
+	for _, ev := range node {
+		if ev == e {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	// When is the condition of the WHEN clause, or nil if there is none.
+	When Expr
+	// Body is the SQL statement executed by the trigger for each row.
+	Body string
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" FOR EACH ROW")
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" AS ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Body, ctx.flags.EncodeFlags())
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -344,6 +622,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -401,6 +680,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -530,6 +810,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
@@ -647,6 +928,10 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		if node.References.Deferrability != ConstraintNotDeferrable {
+			ctx.WriteByte(' ')
+			ctx.WriteString(node.References.Deferrability.String())
+		}
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -751,10 +1036,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -837,13 +1123,15 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -883,6 +1171,10 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Deferrability != ConstraintNotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Deferrability.String())
+	}
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -956,14 +1248,48 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the checks of a constraint can be
+// deferred until the end of the transaction, and whether they are deferred by
+// default. See https://www.postgresql.org/docs/current/sql-set-constraints.html.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	ConstraintInitiallyImmediate
+	ConstraintInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	ConstraintNotDeferrable:      "NOT DEFERRABLE",
+	ConstraintInitiallyImmediate: "DEFERRABLE",
+	ConstraintInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Deferrable returns true if the checks of the constraint can be deferred.
+func (d ConstraintDeferrability) Deferrable() bool {
+	return d != ConstraintNotDeferrable
+}
+
+// InitiallyDeferred returns true if the checks of the constraint are deferred
+// by default.
+func (d ConstraintDeferrability) InitiallyDeferred() bool {
+	return d == ConstraintInitiallyDeferred
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -991,6 +1317,11 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+
+	if node.Deferrability != ConstraintNotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.Deferrability.String())
+	}
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1023,6 +1354,66 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an exclusion constraint within a
+// CREATE TABLE statement.
+type ExclusionConstraintTableDef struct {
+	Name Name
+	// Using is the index access method named in the USING clause, or the empty
+	// string if there is no USING clause.
+	Using string
+	Elems ExclusionElemList
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Using != "" {
+		ctx.WriteString("USING ")
+		ctx.WriteString(node.Using)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+}
+
+// ExclusionElem represents a column of an exclusion constraint, along with
+// the operator used to compare its values.
+type ExclusionElem struct {
+	Column   Name
+	Operator ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionElemList is a list of ExclusionElems.
+type ExclusionElemList []ExclusionElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1334,12 +1725,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -3064,6 +3064,10 @@ type EvalPlanner interface {
 		descID int64,
 		force bool,
 	) error
+
+	// SendNotification sends a notification on a channel, to be delivered to
+	// the sessions listening on it once the current transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
 }
 
 // EvalSessionAccessor is a limited interface to access session variables.
@@ -3982,6 +3986,11 @@ func ensureExpectedType(exp *types.T, d Datum) error {
 	return nil
 }
 
+// Eval implements the TypedExpr interface.
+func (expr *GroupingExpr) Eval(ctx *EvalContext) (Datum, error) {
+	return nil, errors.AssertionFailedf("unhandled type %T", expr)
+}
+
 // Eval implements the TypedExpr interface.
 func (expr *IfErrExpr) Eval(ctx *EvalContext) (Datum, error) {
 	cond, evalErr := expr.Cond.(TypedExpr).Eval(ctx)
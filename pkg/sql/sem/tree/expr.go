@@ -698,6 +698,24 @@ func (node *IsOfTypeExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// GroupingExpr represents a GROUPING(a, b, ...) expression. It evaluates to
+// an integer bit mask with one bit per argument, the rightmost argument
+// corresponding to the least significant bit. A bit is set if the
+// corresponding argument is not part of the grouping set that produced the
+// current result row.
+type GroupingExpr struct {
+	Exprs Exprs
+
+	typeAnnotation
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING(")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // IfErrExpr represents an IFERROR expression.
 type IfErrExpr struct {
 	Cond    Expr
@@ -1786,6 +1804,8 @@ func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingExpr) String() string     { return AsString(node) }
+func (node *GroupingSets) String() string     { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
@@ -126,6 +126,10 @@ const (
 	// should also include a definition for Overload.Fn, which is executed
 	// like a NormalClass function and returns a Datum.
 	SQLClass
+	// UDFClass is a user-defined SQL function. Overloads of this class have
+	// no Fn; instead the optimizer expands the function call into the body of
+	// the function.
+	UDFClass
 )
 
 // Avoid vet warning about unused enum value.
@@ -155,6 +159,26 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUDFFunctionDefinition allocates a function definition for the given
+// overloads of a user-defined function.
+func NewUDFFunctionDefinition(name string, def []Overload) *FunctionDefinition {
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		overloads[i] = &def[i]
+	}
+	return &FunctionDefinition{
+		Name:       name,
+		Definition: overloads,
+		FunctionProperties: FunctionProperties{
+			// Strictness is a property of each overload of a user-defined
+			// function, so it is handled when the function call is expanded.
+			NullableArgs: true,
+			Class:        UDFClass,
+			Category:     "User-defined",
+		},
+	}
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
@@ -0,0 +1,57 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lex"
+
+// Listen represents a LISTEN statement.
+type Listen struct {
+	Channel Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Listen) Format(ctx *FmtCtx) {
+	ctx.WriteString("LISTEN ")
+	ctx.FormatNode(&node.Channel)
+}
+
+// Unlisten represents an UNLISTEN statement.
+type Unlisten struct {
+	Channel Name
+	// Star is set for UNLISTEN *, which stops listening on all channels.
+	Star bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Unlisten) Format(ctx *FmtCtx) {
+	ctx.WriteString("UNLISTEN ")
+	if node.Star {
+		ctx.WriteByte('*')
+	} else {
+		ctx.FormatNode(&node.Channel)
+	}
+}
+
+// Notify represents a NOTIFY statement.
+type Notify struct {
+	Channel Name
+	Payload string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Notify) Format(ctx *FmtCtx) {
+	ctx.WriteString("NOTIFY ")
+	ctx.FormatNode(&node.Channel)
+	if node.Payload != "" {
+		ctx.WriteString(", ")
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, node.Payload, ctx.flags.EncodeFlags())
+	}
+}
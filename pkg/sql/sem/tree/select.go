@@ -570,6 +570,49 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetsType indicates the kind of a GroupingSets element.
+type GroupingSetsType int
+
+const (
+	// RollupGroupingSets represents ROLLUP (a, b, ...), which groups by each
+	// prefix of the list of elements.
+	RollupGroupingSets GroupingSetsType = iota
+	// CubeGroupingSets represents CUBE (a, b, ...), which groups by each subset
+	// of the list of elements.
+	CubeGroupingSets
+	// ExplicitGroupingSets represents GROUPING SETS (...), which groups by each
+	// of the listed grouping sets.
+	ExplicitGroupingSets
+)
+
+var groupingSetsTypeName = [...]string{
+	RollupGroupingSets:   "ROLLUP",
+	CubeGroupingSets:     "CUBE",
+	ExplicitGroupingSets: "GROUPING SETS",
+}
+
+func (t GroupingSetsType) String() string {
+	return groupingSetsTypeName[t]
+}
+
+// GroupingSets represents a ROLLUP, CUBE or GROUPING SETS element of a GROUP
+// BY clause. For ROLLUP and CUBE, each of the Exprs is an expression or a
+// parenthesized list of expressions that is treated as a unit. For GROUPING
+// SETS, each of the Exprs is a grouping set, which may itself be a nested
+// GroupingSets element.
+type GroupingSets struct {
+	Type  GroupingSetsType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSets) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -83,6 +83,8 @@ const (
 	BackupRangeDetails
 	// BackupFileDetails identifies a SHOW BACKUP FILES statement.
 	BackupFileDetails
+	// BackupTimestampDetails identifies a SHOW BACKUP TIMESTAMPS statement.
+	BackupTimestampDetails
 )
 
 // ShowBackup represents a SHOW BACKUP statement.
@@ -106,6 +108,8 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString("RANGES ")
 	} else if node.Details == BackupFileDetails {
 		ctx.WriteString("FILES ")
+	} else if node.Details == BackupTimestampDetails {
+		ctx.WriteString("TIMESTAMPS ")
 	}
 	if node.ShouldIncludeSchemas {
 		ctx.WriteString("SCHEMAS ")
@@ -1071,6 +1071,32 @@ func (expr *FuncExpr) TypeCheck(
 	return expr, nil
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingExpr) TypeCheck(
+	ctx context.Context, semaCtx *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	if semaCtx != nil && semaCtx.Properties.required.rejectFlags&RejectAggregates != 0 {
+		return nil, pgerror.Newf(pgcode.Grouping,
+			"grouping operations are not allowed in %s", semaCtx.Properties.required.context)
+	}
+	for i, e := range expr.Exprs {
+		typedExpr, err := e.TypeCheck(ctx, semaCtx, types.Any)
+		if err != nil {
+			return nil, err
+		}
+		expr.Exprs[i] = typedExpr
+	}
+	expr.typ = types.Int
+	return expr, nil
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSets) TypeCheck(
+	_ context.Context, _ *SemaContext, _ *types.T,
+) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgcode.Syntax, "%s is only allowed in GROUP BY", expr.Type)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *IfErrExpr) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
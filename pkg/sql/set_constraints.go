@@ -0,0 +1,117 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetConstraints sets the checking mode of deferrable constraints for the
+// current transaction. Switching constraints to IMMEDIATE validates any of
+// their checks that were deferred by previous statements.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	state := p.extendedEvalCtx.DeferredConstraints
+	if state == nil || p.extendedEvalCtx.TxnImplicit {
+		// Like Postgres, we only warn about SET CONSTRAINTS outside of a
+		// transaction block, since it has no effect there.
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	var toValidate []*pendingConstraint
+	if n.Names == nil {
+		state.setAllModes(n.Deferred)
+		if !n.Deferred {
+			toValidate = state.takePending(nil /* filter */)
+		}
+	} else {
+		constraints, err := p.resolveDeferrableConstraints(ctx, n.Names)
+		if err != nil {
+			return nil, err
+		}
+		for c := range constraints {
+			state.setMode(c, n.Deferred)
+		}
+		if !n.Deferred {
+			toValidate = state.takePending(func(c deferredConstraint) bool {
+				_, ok := constraints[c]
+				return ok
+			})
+		}
+	}
+
+	if err := validateDeferredConstraints(
+		ctx, toValidate, p.ExecCfg().InternalExecutor, p.txn, p.ExecCfg().Codec,
+	); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// resolveDeferrableConstraints returns the deferrable constraints with the
+// given names which are defined on tables of the current database that are
+// visible through the search path. An error is returned if a name doesn't
+// match any constraint, or if it matches a constraint that is not deferrable.
+func (p *planner) resolveDeferrableConstraints(
+	ctx context.Context, names tree.NameList,
+) (map[deferredConstraint]struct{}, error) {
+	db, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	searchPath := p.SessionData().SearchPath
+	found := make([]bool, len(names))
+	res := make(map[deferredConstraint]struct{})
+	if err := forEachTableDescWithTableLookup(ctx, p, db, hideVirtual,
+		func(
+			_ *dbdesc.Immutable, scName string, table catalog.TableDescriptor, tableLookup tableLookupFn,
+		) error {
+			if !searchPath.Contains(scName) {
+				return nil
+			}
+			conInfo, err := table.GetConstraintInfoWithLookup(tableLookup.getTableByID)
+			if err != nil {
+				return err
+			}
+			for i, name := range names {
+				detail, ok := conInfo[string(name)]
+				if !ok {
+					continue
+				}
+				if !detail.Deferrable() {
+					return pgerror.Newf(pgcode.WrongObjectType,
+						"constraint %q is not deferrable", tree.ErrString(&names[i]))
+				}
+				found[i] = true
+				res[deferredConstraint{tableID: table.GetID(), name: string(name)}] = struct{}{}
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+	for i := range names {
+		if !found[i] {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", tree.ErrString(&names[i]))
+		}
+	}
+	return res, nil
+}
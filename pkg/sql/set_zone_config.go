@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -83,6 +84,9 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 		loadYAML(&c.LeasePreferences, string(tree.MustBeDString(d)))
 		c.InheritedLeasePreferences = false
 	}},
+	"global_reads": {types.Bool, func(c *zonepb.ZoneConfig, d tree.Datum) {
+		c.GlobalReads = proto.Bool(bool(tree.MustBeDBool(d)))
+	}},
 }
 
 // zoneOptionKeys contains the keys from suportedZoneConfigOptions in
@@ -570,6 +574,13 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 				}
 			}
 
+			if finalZone.GlobalReads != nil && !params.p.ExecCfg().Settings.Version.IsActive(
+				params.ctx, clusterversion.NonBlockingTransactions,
+			) {
+				return pgerror.New(pgcode.FeatureNotSupported,
+					"global_reads is not supported until version upgrade is finalized")
+			}
+
 			// Validate that there are no conflicts in the zone setup.
 			if err := validateNoRepeatKeysInZone(&newZone); err != nil {
 				return err
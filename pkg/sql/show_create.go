@@ -209,6 +209,10 @@ func ShowCreateTable(
 		}
 	}
 
+	if err := showTriggers(tn, desc, &f.Buffer); err != nil {
+		return "", err
+	}
+
 	return f.CloseAndGetString(), nil
 }
 
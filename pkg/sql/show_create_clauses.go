@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -101,6 +102,48 @@ func ShowCreateView(
 	return f.CloseAndGetString(), nil
 }
 
+// showTriggers prints out the CREATE TRIGGER statements sufficient to recreate
+// a table's row-level triggers.
+func showTriggers(tn *tree.TableName, table catalog.TableDescriptor, buf *bytes.Buffer) error {
+	triggers := table.GetTriggers()
+	if len(triggers) == 0 {
+		return nil
+	}
+	f := tree.NewFmtCtx(tree.FmtSimple)
+	for i := range triggers {
+		t := &triggers[i]
+		n := &tree.CreateTrigger{
+			Name:       tree.Name(t.Name),
+			ActionTime: tree.TriggerAfter,
+			Table:      *tn,
+			Body:       t.Body,
+		}
+		if t.ActionTime == descpb.TriggerDescriptor_BEFORE {
+			n.ActionTime = tree.TriggerBefore
+		}
+		if t.OnInsert {
+			n.Events = append(n.Events, tree.TriggerInsert)
+		}
+		if t.OnUpdate {
+			n.Events = append(n.Events, tree.TriggerUpdate)
+		}
+		if t.OnDelete {
+			n.Events = append(n.Events, tree.TriggerDelete)
+		}
+		if t.WhenExpr != "" {
+			when, err := parser.ParseExpr(t.WhenExpr)
+			if err != nil {
+				return err
+			}
+			n.When = when
+		}
+		f.WriteString(";\n")
+		f.FormatNode(n)
+	}
+	buf.WriteString(f.CloseAndGetString())
+	return nil
+}
+
 // showComments prints out the COMMENT statements sufficient to populate a
 // table's comments, including its index and column comments.
 func showComments(
@@ -215,12 +258,53 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	writeConstraintDeferrability(buf, fk.Deferrable, fk.InitiallyDeferred)
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
 	return nil
 }
 
+// writeConstraintDeferrability appends the DEFERRABLE clause of a constraint
+// to buf, if the constraint is deferrable.
+func writeConstraintDeferrability(buf *bytes.Buffer, deferrable, initiallyDeferred bool) {
+	if !deferrable {
+		return
+	}
+	buf.WriteString(" DEFERRABLE")
+	if initiallyDeferred {
+		buf.WriteString(" INITIALLY DEFERRED")
+	}
+}
+
+// showExclusionConstraint appends the EXCLUDE clause of the given exclusion
+// constraint to buf.
+func showExclusionConstraint(
+	buf *bytes.Buffer, desc catalog.TableDescriptor, c *descpb.ExclusionConstraint,
+) error {
+	colNames, err := desc.NamesForColumnIDs(c.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	buf.WriteString("EXCLUDE ")
+	if c.Method != "" {
+		buf.WriteString("USING ")
+		buf.WriteString(c.Method)
+		buf.WriteString(" ")
+	}
+	buf.WriteString("(")
+	for i := range colNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		formatQuoteNames(buf, colNames[i])
+		buf.WriteString(" WITH ")
+		buf.WriteString(c.Operator(i).String())
+	}
+	buf.WriteString(")")
+	return nil
+}
+
 // ShowCreateSequence returns a valid SQL representation of the
 // CREATE SEQUENCE statement used to create the given sequence.
 func ShowCreateSequence(
@@ -456,6 +540,21 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		writeConstraintDeferrability(&f.Buffer, c.Deferrable, c.InitiallyDeferred)
+		if c.Validity != descpb.ConstraintValidity_Validated {
+			f.WriteString(" NOT VALID")
+		}
+	}
+	exclusionConstraints := desc.GetExclusionConstraints()
+	for i := range exclusionConstraints {
+		c := &exclusionConstraints[i]
+		f.WriteString(",\n\t")
+		f.WriteString("CONSTRAINT ")
+		formatQuoteNames(&f.Buffer, c.Name)
+		f.WriteString(" ")
+		if err := showExclusionConstraint(&f.Buffer, desc, c); err != nil {
+			return err
+		}
 		if c.Validity != descpb.ConstraintValidity_Validated {
 			f.WriteString(" NOT VALID")
 		}
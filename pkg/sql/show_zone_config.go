@@ -214,6 +214,11 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 	if !zone.InheritedLeasePreferences {
 		writeComma(f, useComma)
 		f.Printf("\tlease_preferences = %s", lex.EscapeSQLString(prefs))
+		useComma = true
+	}
+	if zone.GlobalReads != nil {
+		writeComma(f, useComma)
+		f.Printf("\tglobal_reads = %t", *zone.GlobalReads)
 	}
 	return f.String(), nil
 }
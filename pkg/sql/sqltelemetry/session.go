@@ -38,3 +38,11 @@ func UnimplementedSessionVarValueCounter(varName, val string) telemetry.Counter
 func DummySessionVarValueCounter(varName string) telemetry.Counter {
 	return telemetry.GetCounter(fmt.Sprintf("sql.session_var.dummy.%s", varName))
 }
+
+// ListenUseCounter is to be incremented every time a client starts listening
+// on a notification channel with LISTEN.
+var ListenUseCounter = telemetry.GetCounterOnce("sql.notify.listen")
+
+// NotifyUseCounter is to be incremented every time a notification is sent
+// with NOTIFY or pg_notify().
+var NotifyUseCounter = telemetry.GetCounterOnce("sql.notify.notify")
@@ -14,8 +14,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -90,6 +92,55 @@ func GetUserHashedPassword(
 		err
 }
 
+// getPasswordHashMethod returns the method used to hash new passwords. Until
+// the SCRAMAuthentication version is active, nodes at older versions may not
+// be able to verify SCRAM-SHA-256 verifiers, so bcrypt is used regardless of
+// server.user_login.password_encryption.
+func getPasswordHashMethod(ctx context.Context, st *cluster.Settings) security.PasswordHashMethod {
+	method := security.GetConfiguredPasswordHashMethod(&st.SV)
+	if method == security.HashSCRAMSHA256 &&
+		!st.Version.IsActive(ctx, clusterversion.SCRAMAuthentication) {
+		return security.HashBCrypt
+	}
+	return method
+}
+
+// MaybeUpgradeStoredPasswordHash replaces the bcrypt hash of a user's
+// password stored in system.users by a SCRAM-SHA-256 verifier, if the
+// cluster is configured to do so. It is called after the user has
+// successfully authenticated with the given cleartext password, which is
+// needed to compute the verifier.
+//
+// The update is conditional on the stored hash not having changed since it
+// was retrieved, so that a concurrent password change is not overwritten.
+// Returns true if the stored hash was upgraded.
+func MaybeUpgradeStoredPasswordHash(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	username security.SQLUsername,
+	cleartext string,
+	currentHash []byte,
+) (bool, error) {
+	if len(currentHash) == 0 || security.IsSCRAMHash(currentHash) ||
+		!security.UpgradeBcryptStoredPasswordsToSCRAM.Get(&execCfg.Settings.SV) ||
+		getPasswordHashMethod(ctx, execCfg.Settings) != security.HashSCRAMSHA256 {
+		return false, nil
+	}
+	newHash, err := security.HashPasswordSCRAM(cleartext)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := execCfg.InternalExecutor.ExecEx(
+		ctx, "upgrade-password-hash", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`UPDATE system.users SET "hashedPassword" = $3 WHERE username = $1 AND "hashedPassword" = $2`,
+		username, currentHash, newHash)
+	if err != nil {
+		return false, errors.Wrapf(err, "error upgrading password hash for user %s", username)
+	}
+	return rowsAffected > 0, nil
+}
+
 func retrieveUserAndPassword(
 	ctx context.Context, ie *InternalExecutor, isRoot bool, normalizedUsername security.SQLUsername,
 ) (exists bool, canLogin bool, hashedPassword []byte, validUntil *tree.DTimestamp, err error) {
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
+	"golang.org/x/time/rate"
 )
 
 // DefaultStorageEngine represents the default storage engine to use.
@@ -562,6 +563,11 @@ type Engine interface {
 	// GetEnvStats retrieves stats about the engine's environment
 	// For RocksDB, this includes details of at-rest encryption.
 	GetEnvStats() (*EnvStats, error)
+	// ReencryptDataFiles rewrites the sstables that are encrypted with a data
+	// key other than the active one under the active data key, waiting on the
+	// limiter, if any, for the bytes it writes. It returns the number of files
+	// and bytes rewritten, and is a no-op unless encryption-at-rest is enabled.
+	ReencryptDataFiles(ctx context.Context, limiter *rate.Limiter) (files int, size int64, _ error)
 	// GetAuxiliaryDir returns a path under which files can be stored
 	// persistently, and from which data can be ingested by the engine.
 	//
@@ -730,6 +736,10 @@ type EnvStats struct {
 	ActiveKeyFiles uint64
 	// ActiveKeyBytes is the size of files using the active data key.
 	ActiveKeyBytes uint64
+	// DataKeyUsage is the number and size of files using each key found in the
+	// file registry, sorted by key ID. Besides data keys, this includes the
+	// store key protecting the data key registry.
+	DataKeyUsage []DataKeyUsage
 	// EncryptionType is an enum describing the active encryption algorithm.
 	// See: ccl/storageccl/engineccl/enginepbccl/key_registry.proto
 	EncryptionType int32
@@ -737,6 +747,16 @@ type EnvStats struct {
 	EncryptionStatus []byte
 }
 
+// DataKeyUsage is the number and size of files using a data key.
+type DataKeyUsage struct {
+	// KeyID is the ID of the data key, or "plain" for plaintext files.
+	KeyID string
+	// Files is the number of files using the key.
+	Files uint64
+	// Bytes is the size of the sstables using the key.
+	Bytes uint64
+}
+
 // EncryptionRegistries contains the encryption-related registries:
 // Both are serialized protobufs.
 type EncryptionRegistries struct {
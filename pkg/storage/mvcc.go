@@ -770,6 +770,18 @@ type MVCCGetOptions struct {
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
+	// SkipLocked indicates that the key should be treated as missing if it is
+	// locked by another transaction, instead of returning a WriteIntentError.
+	// See MVCCScanOptions.SkipLocked.
+	SkipLocked bool
+	// LockTable is used by SkipLocked reads to determine whether the key is
+	// locked by a conflicting transaction in the in-memory lock table. It may
+	// be nil, in which case only intents are considered.
+	LockTable LockTableView
+
+	// rangeTombstones are the MVCC range tombstones covering the key, which
+	// are loaded before reading the key. See scanMVCCRangeTombstonesAt.
+	rangeTombstones MVCCRangeTombstones
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -779,6 +791,9 @@ func (opts *MVCCGetOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
@@ -817,6 +832,11 @@ func newMVCCIterator(reader Reader, inlineMeta bool, opts IterOptions) MVCCItera
 func MVCCGet(
 	ctx context.Context, reader Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions,
 ) (*roachpb.Value, *roachpb.Intent, error) {
+	var err error
+	opts.rangeTombstones, err = scanMVCCRangeTombstonesAt(reader, key, nil, timestamp)
+	if err != nil {
+		return nil, nil, err
+	}
 	iter := newMVCCIterator(reader, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 	value, intent, err := mvccGet(ctx, iter, key, timestamp, opts)
@@ -854,7 +874,12 @@ func mvccGet(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
+		rangeTombstones:  opts.rangeTombstones,
+
+		rangeTombstoneKeyBuf: mvccScanner.rangeTombstoneKeyBuf,
 	}
 
 	mvccScanner.init(opts.Txn)
@@ -1090,12 +1115,17 @@ func MVCCPut(
 	// If we're not tracking stats for the key and we're writing a non-versioned
 	// key we can utilize a blind put to avoid reading any existing value.
 	var iter MVCCIterator
+	var tombstones MVCCRangeTombstones
 	blind := ms == nil && timestamp.IsEmpty()
 	if !blind {
+		var err error
+		if tombstones, err = scanMVCCRangeTombstonesAt(rw, key, nil, timestamp); err != nil {
+			return err
+		}
 		iter = rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{Prefix: true})
 		defer iter.Close()
 	}
-	return mvccPutUsingIter(ctx, rw, iter, ms, key, timestamp, value, txn, nil /* valueFn */)
+	return mvccPutUsingIter(ctx, rw, iter, tombstones, ms, key, timestamp, value, txn, nil /* valueFn */)
 }
 
 // MVCCBlindPut is a fast-path of MVCCPut. See the MVCCPut comments for details
@@ -1103,7 +1133,8 @@ func MVCCPut(
 // the key requiring the caller to guarantee no versions for the key currently
 // exist in order for stats to be updated properly. If a previous version of
 // the key does exist it is up to the caller to properly account for their
-// existence in updating the stats.
+// existence in updating the stats. The same holds for MVCC range tombstones
+// covering the key.
 //
 // Note that, when writing transactionally, the txn's timestamps
 // dictate the timestamp of the operation, and the timestamp paramater is
@@ -1117,7 +1148,7 @@ func MVCCBlindPut(
 	value roachpb.Value,
 	txn *roachpb.Transaction,
 ) error {
-	return mvccPutUsingIter(ctx, writer, nil, ms, key, timestamp, value, txn, nil /* valueFn */)
+	return mvccPutUsingIter(ctx, writer, nil, nil, ms, key, timestamp, value, txn, nil /* valueFn */)
 }
 
 // MVCCDelete marks the key deleted so that it will not be returned in
@@ -1134,10 +1165,14 @@ func MVCCDelete(
 	timestamp hlc.Timestamp,
 	txn *roachpb.Transaction,
 ) error {
+	tombstones, err := scanMVCCRangeTombstonesAt(rw, key, nil, timestamp)
+	if err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
-	return mvccPutUsingIter(ctx, rw, iter, ms, key, timestamp, noValue, txn, nil /* valueFn */)
+	return mvccPutUsingIter(ctx, rw, iter, tombstones, ms, key, timestamp, noValue, txn, nil /* valueFn */)
 }
 
 var noValue = roachpb.Value{}
@@ -1145,11 +1180,14 @@ var noValue = roachpb.Value{}
 // mvccPutUsingIter sets the value for a specified key using the provided
 // MVCCIterator. The function takes a value and a valueFn, only one of which
 // should be provided. If the valueFn is nil, value's raw bytes will be set
-// for the key, else the bytes provided by the valueFn will be used.
+// for the key, else the bytes provided by the valueFn will be used. The
+// MVCC range tombstones covering the key must be provided alongside the
+// iterator.
 func mvccPutUsingIter(
 	ctx context.Context,
 	writer Writer,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	ms *enginepb.MVCCStats,
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
@@ -1167,7 +1205,7 @@ func mvccPutUsingIter(
 
 	buf := newPutBuffer()
 
-	err := mvccPutInternal(ctx, writer, iter, ms, key, timestamp, rawBytes,
+	err := mvccPutInternal(ctx, writer, iter, tombstones, ms, key, timestamp, rawBytes,
 		txn, buf, valueFn)
 
 	// Using defer would be more convenient, but it is measurably slower.
@@ -1180,6 +1218,7 @@ func mvccPutUsingIter(
 func maybeGetValue(
 	ctx context.Context,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	key roachpb.Key,
 	value []byte,
 	exists bool,
@@ -1194,7 +1233,9 @@ func maybeGetValue(
 	var exVal optionalValue
 	if exists {
 		var err error
-		exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{Txn: txn, Tombstones: true})
+		exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{
+			Txn: txn, Tombstones: true, rangeTombstones: tombstones,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -1249,6 +1290,7 @@ func MVCCScanDecodeKeyValues(repr [][]byte, fn func(key MVCCKey, rawBytes []byte
 func replayTransactionalWrite(
 	ctx context.Context,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	meta *enginepb.MVCCMetadata,
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
@@ -1263,7 +1305,9 @@ func replayTransactionalWrite(
 		// This is a special case. This is when the intent hasn't made it
 		// to the intent history yet. We must now assert the value written
 		// in the intent to the value we're trying to write.
-		exVal, _, err := mvccGet(ctx, iter, key, timestamp, MVCCGetOptions{Txn: txn, Tombstones: true})
+		exVal, _, err := mvccGet(ctx, iter, key, timestamp, MVCCGetOptions{
+			Txn: txn, Tombstones: true, rangeTombstones: tombstones,
+		})
 		if err != nil {
 			return err
 		}
@@ -1297,7 +1341,9 @@ func replayTransactionalWrite(
 			// last committed value on the key. Since we want the last committed
 			// value on the key, we must make an inconsistent read so we ignore
 			// our previous intents here.
-			exVal, _, err = mvccGet(ctx, iter, key, timestamp, MVCCGetOptions{Inconsistent: true, Tombstones: true})
+			exVal, _, err = mvccGet(ctx, iter, key, timestamp, MVCCGetOptions{
+				Inconsistent: true, Tombstones: true, rangeTombstones: tombstones,
+			})
 			if err != nil {
 				return err
 			}
@@ -1347,6 +1393,7 @@ func mvccPutInternal(
 	ctx context.Context,
 	writer Writer,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	ms *enginepb.MVCCStats,
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
@@ -1383,7 +1430,7 @@ func mvccPutInternal(
 		}
 		var metaKeySize, metaValSize int64
 		if value, err = maybeGetValue(
-			ctx, iter, key, value, ok, timestamp, txn, valueFn); err != nil {
+			ctx, iter, tombstones, key, value, ok, timestamp, txn, valueFn); err != nil {
 			return err
 		}
 		if value == nil {
@@ -1458,12 +1505,30 @@ func mvccPutInternal(
 		IntentHistory: buf.meta.IntentHistory,
 	}
 
+	// A range tombstone covering the key deletes its newest version, if that
+	// version is below it. Like any other committed write, it also prevents
+	// writes at or below its timestamp.
+	var rangeTombstoneTS hlc.Timestamp
+	if covering := tombstones.Covering(key); len(covering) > 0 {
+		rangeTombstoneTS = covering[0].Timestamp
+		if ok && buf.meta.Txn == nil {
+			shadowedAt := tombstones.ShadowedAt(key, buf.meta.Timestamp.ToTimestamp())
+			if !shadowedAt.IsEmpty() {
+				buf.meta.Deleted = true
+				buf.meta.Timestamp = shadowedAt.ToLegacyTimestamp()
+			}
+		}
+	}
+
 	var maybeTooOldErr error
 	var prevValSize int64
 	if ok {
 		// There is existing metadata for this key; ensure our write is permitted.
 		meta = &buf.meta
 		metaTimestamp := meta.Timestamp.ToTimestamp()
+		if meta.Txn == nil {
+			metaTimestamp.Forward(rangeTombstoneTS)
+		}
 
 		if meta.Txn != nil {
 			// There is an uncommitted write intent.
@@ -1480,7 +1545,7 @@ func mvccPutInternal(
 				// The transaction has executed at this sequence before. This is merely a
 				// replay of the transactional write. Assert that all is in order and return
 				// early.
-				return replayTransactionalWrite(ctx, iter, meta, key, readTimestamp, value, txn, valueFn)
+				return replayTransactionalWrite(ctx, iter, tombstones, meta, key, readTimestamp, value, txn, valueFn)
 			}
 
 			// We're overwriting the intent that was present at this key, before we do
@@ -1512,7 +1577,9 @@ func mvccPutInternal(
 				if !enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, txn.IgnoredSeqNums) {
 					// Seqnum of last write is not ignored. Retrieve the value
 					// using a consistent read.
-					exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{Txn: txn, Tombstones: true})
+					exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{
+						Txn: txn, Tombstones: true, rangeTombstones: tombstones,
+					})
 					if err != nil {
 						return err
 					}
@@ -1533,7 +1600,9 @@ func mvccPutInternal(
 				//
 				// Since we want the last committed value on the key, we must make
 				// an inconsistent read so we ignore our previous intents here.
-				exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{Inconsistent: true, Tombstones: true})
+				exVal, _, err = mvccGet(ctx, iter, key, readTimestamp, MVCCGetOptions{
+					Inconsistent: true, Tombstones: true, rangeTombstones: tombstones,
+				})
 				if err != nil {
 					return err
 				}
@@ -1561,11 +1630,13 @@ func mvccPutInternal(
 					// move the intent above it. A similar phenomenon occurs in
 					// MVCCResolveWriteIntent.
 					latestKey := MVCCKey{Key: key, Timestamp: metaTimestamp}
-					_, prevUnsafeVal, haveNextVersion, err := unsafeNextVersion(iter, latestKey)
+					prevUnsafeKey, prevUnsafeVal, haveNextVersion, err := unsafeNextVersion(iter, latestKey)
 					if err != nil {
 						return err
 					}
-					if haveNextVersion {
+					// A version shadowed by a range tombstone is not live either
+					// way, like a deletion tombstone.
+					if haveNextVersion && tombstones.ShadowedAt(key, prevUnsafeKey.Timestamp).IsEmpty() {
 						prevValSize = int64(len(prevUnsafeVal))
 					}
 					iter = nil // prevent accidental use below
@@ -1640,7 +1711,7 @@ func mvccPutInternal(
 			// timestamp.
 			if txn != nil {
 				if value, err = maybeGetValue(
-					ctx, iter, key, value, ok, readTimestamp, txn, valueFn); err != nil {
+					ctx, iter, tombstones, key, value, ok, readTimestamp, txn, valueFn); err != nil {
 					return err
 				}
 			} else {
@@ -1651,19 +1722,24 @@ func mvccPutInternal(
 				// value, but that's a concern of evaluateBatch and not here.
 				readTimestamp = writeTimestamp
 				if value, err = maybeGetValue(
-					ctx, iter, key, value, ok, readTimestamp, txn, valueFn); err != nil {
+					ctx, iter, tombstones, key, value, ok, readTimestamp, txn, valueFn); err != nil {
 					return err
 				}
 			}
 		} else {
 			if value, err = maybeGetValue(
-				ctx, iter, key, value, ok, readTimestamp, txn, valueFn); err != nil {
+				ctx, iter, tombstones, key, value, ok, readTimestamp, txn, valueFn); err != nil {
 				return err
 			}
 		}
 	} else {
-		// There is no existing value for this key. Even if the new value is
-		// nil write a deletion tombstone for the key.
+		// There is no existing value for this key, but there may be a range
+		// tombstone above the read timestamp. See the write-too-old case above.
+		if readTimestamp.LessEq(rangeTombstoneTS) {
+			writeTimestamp.Forward(rangeTombstoneTS.Next())
+			maybeTooOldErr = roachpb.NewWriteTooOldError(readTimestamp, writeTimestamp)
+		}
+		// Even if the new value is nil write a deletion tombstone for the key.
 		if valueFn != nil {
 			value, err = valueFn(optionalValue{exists: false})
 			if err != nil {
@@ -1764,12 +1840,16 @@ func MVCCIncrement(
 	txn *roachpb.Transaction,
 	inc int64,
 ) (int64, error) {
+	tombstones, err := scanMVCCRangeTombstonesAt(rw, key, nil, timestamp)
+	if err != nil {
+		return 0, err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
 	var int64Val int64
 	var newInt64Val int64
-	err := mvccPutUsingIter(ctx, rw, iter, ms, key, timestamp, noValue, txn, func(value optionalValue) ([]byte, error) {
+	err = mvccPutUsingIter(ctx, rw, iter, tombstones, ms, key, timestamp, noValue, txn, func(value optionalValue) ([]byte, error) {
 		if value.IsPresent() {
 			var err error
 			if int64Val, err = value.GetInt(); err != nil {
@@ -1836,10 +1916,15 @@ func MVCCConditionalPut(
 	allowIfDoesNotExist CPutMissingBehavior,
 	txn *roachpb.Transaction,
 ) error {
+	tombstones, err := scanMVCCRangeTombstonesAt(rw, key, nil, timestamp)
+	if err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
 
-	return mvccConditionalPutUsingIter(ctx, rw, iter, ms, key, timestamp, value, expVal, allowIfDoesNotExist, txn)
+	return mvccConditionalPutUsingIter(
+		ctx, rw, iter, tombstones, ms, key, timestamp, value, expVal, allowIfDoesNotExist, txn)
 }
 
 // MVCCBlindConditionalPut is a fast-path of MVCCConditionalPut. See the
@@ -1862,13 +1947,15 @@ func MVCCBlindConditionalPut(
 	allowIfDoesNotExist CPutMissingBehavior,
 	txn *roachpb.Transaction,
 ) error {
-	return mvccConditionalPutUsingIter(ctx, writer, nil, ms, key, timestamp, value, expVal, allowIfDoesNotExist, txn)
+	return mvccConditionalPutUsingIter(
+		ctx, writer, nil, nil, ms, key, timestamp, value, expVal, allowIfDoesNotExist, txn)
 }
 
 func mvccConditionalPutUsingIter(
 	ctx context.Context,
 	writer Writer,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	ms *enginepb.MVCCStats,
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
@@ -1878,7 +1965,7 @@ func mvccConditionalPutUsingIter(
 	txn *roachpb.Transaction,
 ) error {
 	return mvccPutUsingIter(
-		ctx, writer, iter, ms, key, timestamp, noValue, txn,
+		ctx, writer, iter, tombstones, ms, key, timestamp, noValue, txn,
 		func(existVal optionalValue) ([]byte, error) {
 			if expValPresent, existValPresent := len(expBytes) != 0, existVal.IsPresent(); expValPresent && existValPresent {
 				if !bytes.Equal(expBytes, existVal.TagAndDataBytes()) {
@@ -1914,9 +2001,13 @@ func MVCCInitPut(
 	failOnTombstones bool,
 	txn *roachpb.Transaction,
 ) error {
+	tombstones, err := scanMVCCRangeTombstonesAt(rw, key, nil, timestamp)
+	if err != nil {
+		return err
+	}
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
 	defer iter.Close()
-	return mvccInitPutUsingIter(ctx, rw, iter, ms, key, timestamp, value, failOnTombstones, txn)
+	return mvccInitPutUsingIter(ctx, rw, iter, tombstones, ms, key, timestamp, value, failOnTombstones, txn)
 }
 
 // MVCCBlindInitPut is a fast-path of MVCCInitPut. See the MVCCInitPut
@@ -1937,13 +2028,14 @@ func MVCCBlindInitPut(
 	failOnTombstones bool,
 	txn *roachpb.Transaction,
 ) error {
-	return mvccInitPutUsingIter(ctx, rw, nil, ms, key, timestamp, value, failOnTombstones, txn)
+	return mvccInitPutUsingIter(ctx, rw, nil, nil, ms, key, timestamp, value, failOnTombstones, txn)
 }
 
 func mvccInitPutUsingIter(
 	ctx context.Context,
 	rw ReadWriter,
 	iter MVCCIterator,
+	tombstones MVCCRangeTombstones,
 	ms *enginepb.MVCCStats,
 	key roachpb.Key,
 	timestamp hlc.Timestamp,
@@ -1952,7 +2044,7 @@ func mvccInitPutUsingIter(
 	txn *roachpb.Transaction,
 ) error {
 	return mvccPutUsingIter(
-		ctx, rw, iter, ms, key, timestamp, noValue, txn,
+		ctx, rw, iter, tombstones, ms, key, timestamp, noValue, txn,
 		func(existVal optionalValue) ([]byte, error) {
 			if failOnTombstones && existVal.IsTombstone() {
 				// We found a tombstone and failOnTombstones is true: fail.
@@ -2044,7 +2136,62 @@ func MVCCMerge(
 // incremental deltas of clearing these keys (and correctly determining if it
 // does or not not change the live and gc keys) so the caller is responsible for
 // recomputing stats over the resulting span if needed.
+//
+// MVCC range tombstones in the time range are removed as well, over the part of
+// the span that was processed. Since removing a range tombstone changes the
+// stats of all the keys it shadowed, the stats are then computed by scanning
+// the span before and after clearing it.
 func MVCCClearTimeRange(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	key, endKey roachpb.Key,
+	startTime, endTime hlc.Timestamp,
+	maxBatchSize int64,
+) (*roachpb.Span, error) {
+	tombstones, err := ScanMVCCRangeTombstones(rw, key, endKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(tombstones) == 0 {
+		return mvccClearTimeRange(ctx, rw, ms, key, endKey, startTime, endTime, maxBatchSize)
+	}
+
+	nowNanos := endTime.WallTime
+	var msBefore enginepb.MVCCStats
+	if ms != nil {
+		if msBefore, err = computeStatsWithTombstones(rw, tombstones, key, endKey, nowNanos); err != nil {
+			return nil, err
+		}
+	}
+	resume, err := mvccClearTimeRange(ctx, rw, nil /* ms */, key, endKey, startTime, endTime, maxBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	clearedEndKey := endKey
+	if resume != nil {
+		clearedEndKey = resume.Key
+	}
+	if err := clearMVCCRangeTombstoneTimeRange(
+		rw, ms, key, clearedEndKey, startTime, endTime,
+	); err != nil {
+		return nil, err
+	}
+	if ms != nil {
+		if tombstones, err = ScanMVCCRangeTombstones(rw, key, endKey); err != nil {
+			return nil, err
+		}
+		msAfter, err := computeStatsWithTombstones(rw, tombstones, key, endKey, nowNanos)
+		if err != nil {
+			return nil, err
+		}
+		msAfter.Subtract(msBefore)
+		ms.Add(msAfter)
+	}
+	return resume, nil
+}
+
+func mvccClearTimeRange(
 	_ context.Context,
 	rw ReadWriter,
 	ms *enginepb.MVCCStats,
@@ -2251,6 +2398,10 @@ func MVCCDeleteRange(
 		return nil, nil, 0, err
 	}
 
+	tombstones, err := scanMVCCRangeTombstonesAt(rw, key, endKey, timestamp)
+	if err != nil {
+		return nil, nil, 0, err
+	}
 	buf := newPutBuffer()
 	defer buf.release()
 	iter := newMVCCIterator(rw, timestamp.IsEmpty(), IterOptions{Prefix: true})
@@ -2258,7 +2409,7 @@ func MVCCDeleteRange(
 
 	var keys []roachpb.Key
 	for i, kv := range res.KVs {
-		if err := mvccPutInternal(ctx, rw, iter, ms, kv.Key, timestamp, nil, txn, buf, nil); err != nil {
+		if err := mvccPutInternal(ctx, rw, iter, tombstones, ms, kv.Key, timestamp, nil, txn, buf, nil); err != nil {
 			return nil, nil, 0, err
 		}
 		if returnKeys {
@@ -2303,7 +2454,12 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
+		rangeTombstones:  opts.rangeTombstones,
+
+		rangeTombstoneKeyBuf: mvccScanner.rangeTombstoneKeyBuf,
 	}
 
 	mvccScanner.init(opts.Txn)
@@ -2416,6 +2572,21 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// SkipLocked indicates that the scan should skip over keys that are locked
+	// by other transactions instead of returning a WriteIntentError for them.
+	// Locks are determined both from intents in the scanned span and, if
+	// provided, from the LockTable. Skipped keys are not included in the scan
+	// result.
+	SkipLocked bool
+	// LockTable is used by SkipLocked scans to determine whether a key is
+	// locked by a conflicting transaction in the in-memory lock table. It may
+	// be nil, in which case only intents are considered.
+	LockTable LockTableView
+
+	// rangeTombstones are the MVCC range tombstones overlapping the scanned
+	// span, which are loaded before scanning it. See
+	// scanMVCCRangeTombstonesAt.
+	rangeTombstones MVCCRangeTombstones
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2425,9 +2596,21 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
+// LockTableView is a transaction-bound view into an in-memory collection of
+// key-level locks. It is used by scans with the SkipLocked option to determine
+// which keys are locked by conflicting transactions.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a conflicting transaction, along with the holder of the lock if so.
+	IsKeyLockedByConflictingTxn(roachpb.Key) (bool, *enginepb.TxnMeta)
+}
+
 // MVCCScanResult groups the values returned from an MVCCScan operation. Depending
 // on the operation invoked, KVData or KVs is populated, but never both.
 type MVCCScanResult struct {
@@ -2475,6 +2658,13 @@ type MVCCScanResult struct {
 // the read timestamp, the maximum will be returned in the WriteTooOldError.
 // Similarly, a WriteIntentError will be returned if the scan observes another
 // transaction's intent, even if it has a timestamp above the read timestamp.
+//
+// When scanning in "skip locked" mode, keys that are locked by transactions
+// other than the reader are not included in the result set and do not result
+// in a WriteIntentError. Keys are considered locked if they have a conflicting
+// intent or, if a LockTableView is provided, a conflicting lock in the lock
+// table. In "fail on more recent" mode, unlocked keys with versions above the
+// read timestamp still cause a WriteTooOldError.
 func MVCCScan(
 	ctx context.Context,
 	reader Reader,
@@ -2482,6 +2672,11 @@ func MVCCScan(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
+	var err error
+	opts.rangeTombstones, err = scanMVCCRangeTombstonesAt(reader, key, endKey, timestamp)
+	if err != nil {
+		return MVCCScanResult{}, err
+	}
 	iter := newMVCCIterator(reader, timestamp.IsEmpty(), IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 	return mvccScanToKvs(ctx, iter, key, endKey, timestamp, opts)
@@ -2495,6 +2690,11 @@ func MVCCScanToBytes(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
+	var err error
+	opts.rangeTombstones, err = scanMVCCRangeTombstonesAt(reader, key, endKey, timestamp)
+	if err != nil {
+		return MVCCScanResult{}, err
+	}
 	iter := newMVCCIterator(reader, timestamp.IsEmpty(), IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 	return mvccScanToBytes(ctx, iter, key, endKey, timestamp, opts)
@@ -2537,6 +2737,11 @@ func MVCCIterate(
 	opts MVCCScanOptions,
 	f func(roachpb.KeyValue) error,
 ) ([]roachpb.Intent, error) {
+	var err error
+	opts.rangeTombstones, err = scanMVCCRangeTombstonesAt(reader, key, endKey, timestamp)
+	if err != nil {
+		return nil, err
+	}
 	iter := newMVCCIterator(
 		reader, timestamp.IsEmpty(), IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
@@ -2930,8 +3135,21 @@ func mvccResolveWriteIntent(
 
 	// Update stat counters with older version.
 	if ms != nil {
+		restoredNanos := unsafeNextKey.Timestamp.WallTime
+		// If the older version is shadowed by a range tombstone, it is deleted
+		// as of the range tombstone's timestamp.
+		if !buf.newMeta.Deleted {
+			tombstones, err := ScanMVCCRangeTombstones(rw, intent.Key, intent.Key.Next())
+			if err != nil {
+				return false, err
+			}
+			if shadowedAt := tombstones.ShadowedAt(intent.Key, unsafeNextKey.Timestamp); !shadowedAt.IsEmpty() {
+				buf.newMeta.Deleted = true
+				restoredNanos = shadowedAt.WallTime
+			}
+		}
 		ms.Add(updateStatsOnClear(intent.Key, origMetaKeySize, origMetaValSize,
-			metaKeySize, metaValSize, meta, &buf.newMeta, unsafeNextKey.Timestamp.WallTime))
+			metaKeySize, metaValSize, meta, &buf.newMeta, restoredNanos))
 	}
 
 	return true, nil
@@ -3133,6 +3351,13 @@ func MVCCGarbageCollect(
 		return iKey.Less(jKey)
 	})
 
+	// Versions shadowed by a range tombstone are garbage once the range
+	// tombstone is below the GC threshold, just like for deletion tombstones.
+	tombstones, err := ScanMVCCRangeTombstones(rw, keys[0].Key, keys[len(keys)-1].Key.Next())
+	if err != nil {
+		return err
+	}
+
 	// Bound the iterator appropriately for the set of keys we'll be garbage
 	// collecting.
 	iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{
@@ -3167,9 +3392,15 @@ func MVCCGarbageCollect(
 			// For version keys, don't allow GC'ing the meta key if it's
 			// not marked deleted. However, for inline values we allow it;
 			// they are internal and GCing them directly saves the extra
-			// deletion step.
+			// deletion step. A version shadowed by a range tombstone counts as
+			// deleted at the range tombstone's timestamp.
 			if !meta.Deleted && !inlinedValue {
-				return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				shadowedAt := tombstones.ShadowedAt(gcKey.Key, meta.Timestamp.ToTimestamp())
+				if shadowedAt.IsEmpty() {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				meta.Deleted = true
+				meta.Timestamp = shadowedAt.ToLegacyTimestamp()
 			}
 			if meta.Txn != nil {
 				return errors.Errorf("request to GC intent at %q", gcKey.Key)
@@ -3293,11 +3524,15 @@ func MVCCGarbageCollect(
 				// when it's a deletion.
 				valSize := int64(len(iter.UnsafeValue()))
 
-				// A non-deletion becomes non-live when its newer neighbor shows up.
-				// A deletion tombstone becomes non-live right when it is created.
+				// A non-deletion becomes non-live when its newer neighbor or a
+				// covering range tombstone shows up. A deletion tombstone becomes
+				// non-live right when it is created.
 				fromNS := prevNanos
 				if valSize == 0 {
 					fromNS = unsafeIterKey.Timestamp.WallTime
+				} else if shadowedAt := tombstones.ShadowedAt(gcKey.Key, unsafeIterKey.Timestamp); !shadowedAt.IsEmpty() &&
+					shadowedAt.WallTime < fromNS {
+					fromNS = shadowedAt.WallTime
 				}
 
 				ms.Add(updateStatsOnGC(gcKey.Key, MVCCVersionTimestampSize,
@@ -3440,6 +3675,20 @@ func ComputeStatsForRange(
 	start, end roachpb.Key,
 	nowNanos int64,
 	callbacks ...func(MVCCKey, []byte) error,
+) (enginepb.MVCCStats, error) {
+	return computeStatsForRange(iter, nil /* tombstones */, start, end, nowNanos, callbacks...)
+}
+
+// computeStatsForRange implements ComputeStatsForRange and
+// ComputeStatsWithRangeTombstones. Versions shadowed by one of the given range
+// tombstones are treated as if a deletion tombstone had been written above
+// them at the range tombstone's timestamp.
+func computeStatsForRange(
+	iter SimpleMVCCIterator,
+	tombstones MVCCRangeTombstones,
+	start, end roachpb.Key,
+	nowNanos int64,
+	callbacks ...func(MVCCKey, []byte) error,
 ) (enginepb.MVCCStats, error) {
 	var ms enginepb.MVCCStats
 
@@ -3503,6 +3752,14 @@ func ComputeStatsForRange(
 			meta.ValBytes = int64(len(unsafeValue))
 			meta.Deleted = len(unsafeValue) == 0
 			meta.Timestamp.WallTime = unsafeKey.Timestamp.WallTime
+			if !meta.Deleted && !isSys {
+				// A live version shadowed by a range tombstone is deleted, and
+				// starts accruing GCBytesAge at the range tombstone's timestamp.
+				if shadowedAt := tombstones.ShadowedAt(unsafeKey.Key, unsafeKey.Timestamp); !shadowedAt.IsEmpty() {
+					meta.Deleted = true
+					meta.Timestamp.WallTime = shadowedAt.WallTime
+				}
+			}
 		}
 
 		if !isValue || implicitMeta {
@@ -3571,7 +3828,7 @@ func ComputeStatsForRange(
 					return ms, errors.Errorf("expected mvcc metadata val bytes to equal %d; got %d "+
 						"(meta: %s)", len(unsafeValue), meta.ValBytes, &meta)
 				}
-				accrueGCAgeNanos = meta.Timestamp.WallTime
+				accrueGCAgeNanos = unsafeKey.Timestamp.WallTime
 			} else {
 				// Overwritten value. Is it a deletion tombstone?
 				isTombstone := len(unsafeValue) == 0
@@ -3580,8 +3837,13 @@ func ComputeStatsForRange(
 					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - unsafeKey.Timestamp.WallTime/1e9)
 				} else {
 					// The kv pair is an overwritten value, so it became non-live when the closest more
-					// recent value was written.
-					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - accrueGCAgeNanos/1e9)
+					// recent value or covering range tombstone was written.
+					shadowedNanos := accrueGCAgeNanos
+					if shadowedAt := tombstones.ShadowedAt(unsafeKey.Key, unsafeKey.Timestamp); !shadowedAt.IsEmpty() &&
+						shadowedAt.WallTime < shadowedNanos {
+						shadowedNanos = shadowedAt.WallTime
+					}
+					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - shadowedNanos/1e9)
 				}
 				// Update for the next version we may end up looking at.
 				accrueGCAgeNanos = unsafeKey.Timestamp.WallTime
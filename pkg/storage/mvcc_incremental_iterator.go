@@ -65,10 +65,14 @@ import (
 // no delete in the subset of sstables used by timeBoundIter that deletes
 // k@t#n1, so the timeBoundIter will see k@t.
 //
-// NOTE: This is not used by CockroachDB and has been preserved to serve as an
-// oracle to prove the correctness of the new export logic.
+// MVCC range tombstones are surfaced as point deletion tombstones on each of
+// the keys they cover, see PointSynthesizingIter.
+//
+// NOTE: This is not used by export requests and has been preserved to serve as
+// an oracle to prove the correctness of the new export logic. It is used by
+// rangefeed catch-up scans, see MVCCIncrementalIterIntentPolicyEmit.
 type MVCCIncrementalIterator struct {
-	iter MVCCIterator
+	iter *PointSynthesizingIter
 
 	// A time-bound iterator cannot be used by itself due to a bug in the time-
 	// bound iterator (#28358). This was historically augmented with an iterator
@@ -83,6 +87,9 @@ type MVCCIncrementalIterator struct {
 	err       error
 	valid     bool
 
+	intentPolicy MVCCIncrementalIterIntentPolicy
+	inlinePolicy MVCCIncrementalIterInlinePolicy
+
 	// For allocation avoidance, meta is used to store the timestamp of keys
 	// regardless if they are metakeys.
 	meta enginepb.MVCCMetadata
@@ -99,8 +106,41 @@ type MVCCIncrementalIterOptions struct {
 	// time.
 	StartTime hlc.Timestamp
 	EndTime   hlc.Timestamp
+
+	IntentPolicy MVCCIncrementalIterIntentPolicy
+	InlinePolicy MVCCIncrementalIterInlinePolicy
 }
 
+// MVCCIncrementalIterIntentPolicy controls how the MVCCIncrementalIterator
+// handles intents that it encounters within its time bounds.
+type MVCCIncrementalIterIntentPolicy int
+
+const (
+	// MVCCIncrementalIterIntentPolicyError returns a WriteIntentError upon
+	// encountering an intent within the time bounds.
+	MVCCIncrementalIterIntentPolicyError MVCCIncrementalIterIntentPolicy = iota
+	// MVCCIncrementalIterIntentPolicyEmit positions the iterator on the
+	// metadata key of intents within the time bounds, leaving it to the caller
+	// to handle them. Calling Next on the metadata key moves the iterator to
+	// the provisional value.
+	MVCCIncrementalIterIntentPolicyEmit
+)
+
+// MVCCIncrementalIterInlinePolicy controls how the MVCCIncrementalIterator
+// handles inline values, which have no timestamp.
+type MVCCIncrementalIterInlinePolicy int
+
+const (
+	// MVCCIncrementalIterInlinePolicyError returns an error upon encountering
+	// an inline value.
+	MVCCIncrementalIterInlinePolicyError MVCCIncrementalIterInlinePolicy = iota
+	// MVCCIncrementalIterInlinePolicyEmit positions the iterator on inline
+	// values, regardless of the time bounds. Note that inline values on keys
+	// without any versions within the time bounds may be skipped by the
+	// time-bound iterator optimization.
+	MVCCIncrementalIterInlinePolicyEmit
+)
+
 // NewMVCCIncrementalIterator creates an MVCCIncrementalIterator with the
 // specified reader and options. The timestamp hint range should not be more
 // restrictive than the start and end time range.
@@ -126,10 +166,12 @@ func NewMVCCIncrementalIterator(
 	}
 
 	return &MVCCIncrementalIterator{
-		iter:          iter,
+		iter:          NewPointSynthesizingIter(reader, iter, opts.IterOptions.UpperBound),
 		startTime:     opts.StartTime,
 		endTime:       opts.EndTime,
 		timeBoundIter: timeBoundIter,
+		intentPolicy:  opts.IntentPolicy,
+		inlinePolicy:  opts.InlinePolicy,
 	}
 }
 
@@ -148,8 +190,17 @@ func (i *MVCCIncrementalIterator) SeekGE(startKey MVCCKey) {
 		tbiKey := i.timeBoundIter.Key().Key
 		if tbiKey.Compare(startKey.Key) > 0 {
 			// If the first key that the TBI sees is ahead of the given startKey, we
-			// can seek directly to the first version of the key.
-			startKey = MakeMVCCMetadataKey(tbiKey)
+			// can seek directly to the first version of the key, unless a range
+			// tombstone in the time bounds covers keys before it.
+			i.iter.maybeReadTombstones(startKey.Key)
+			if tk := i.iter.nextTombstoneInTimeRange(
+				startKey.Key, tbiKey, i.startTime, i.endTime,
+			); tk != nil {
+				tbiKey = tk
+			}
+			if tbiKey.Compare(startKey.Key) > 0 {
+				startKey = MakeMVCCMetadataKey(tbiKey)
+			}
 		}
 	}
 	i.iter.SeekGE(startKey)
@@ -249,6 +300,16 @@ func (i *MVCCIncrementalIterator) maybeSkipKeys() {
 		}
 
 		if cmp < 0 {
+			// Keys covered by a range tombstone within the time bounds must not be
+			// skipped, even if none of their point versions were seen by the TBI.
+			if tk := i.iter.nextTombstoneInTimeRange(
+				iterKey, tbiKey, i.startTime, i.endTime,
+			); tk != nil {
+				if tk.Compare(iterKey) <= 0 {
+					return
+				}
+				tbiKey = tk
+			}
 			// In the case that the next MVCC key that the TBI observes is not the
 			// same as the main iterator, we may be able to skip over a large group
 			// of keys. The main iterator is seeked to the TBI in hopes that many
@@ -267,7 +328,8 @@ func (i *MVCCIncrementalIterator) maybeSkipKeys() {
 
 // advance advances the main iterator until it is referencing a key within
 // (start_time, end_time].
-// It populates i.err with an error if either of the following was encountered:
+// It populates i.err with an error if either of the following was encountered,
+// unless the respective policy is to emit it:
 // a) an inline value
 // b) an intent with a timestamp within the incremental iterator's bounds
 func (i *MVCCIncrementalIterator) advance() {
@@ -294,6 +356,9 @@ func (i *MVCCIncrementalIterator) advance() {
 		}
 
 		if i.meta.IsInline() {
+			if i.inlinePolicy == MVCCIncrementalIterInlinePolicyEmit {
+				break
+			}
 			// Inline values are only used in non-user data. They're not needed
 			// for backup, so they're not handled by this method. If one shows
 			// up, throw an error so it's obvious something is wrong.
@@ -306,6 +371,9 @@ func (i *MVCCIncrementalIterator) advance() {
 		metaTimestamp := i.meta.Timestamp.ToTimestamp()
 		if i.meta.Txn != nil {
 			if i.startTime.Less(metaTimestamp) && metaTimestamp.LessEq(i.endTime) {
+				if i.intentPolicy == MVCCIncrementalIterIntentPolicyEmit {
+					break
+				}
 				i.err = &roachpb.WriteIntentError{
 					Intents: []roachpb.Intent{
 						roachpb.MakeIntent(i.meta.Txn, i.iter.Key().Key),
@@ -790,3 +790,68 @@ func TestMVCCIterateTimeBound(t *testing.T) {
 		})
 	}
 }
+
+func TestMVCCIncrementalIteratorEmitPolicies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	kA, kB, kC := roachpb.Key("kA"), roachpb.Key("kB"), roachpb.Key("kC")
+	txn := &roachpb.Transaction{
+		TxnMeta: enginepb.TxnMeta{
+			Key:            kC,
+			ID:             uuid.MakeV4(),
+			Epoch:          1,
+			WriteTimestamp: ts3,
+		},
+		ReadTimestamp: ts3,
+	}
+
+	db := NewInMem(ctx, roachpb.Attributes{}, 10<<20)
+	defer db.Close()
+
+	// kA is an inline value, kB has a committed value below the time bounds,
+	// and kC has an intent within the time bounds.
+	require.NoError(t, MVCCPut(ctx, db, nil, kA, hlc.Timestamp{}, roachpb.MakeValueFromString("vA"), nil))
+	require.NoError(t, MVCCPut(ctx, db, nil, kB, ts1, roachpb.MakeValueFromString("vB"), nil))
+	require.NoError(t, MVCCPut(ctx, db, nil, kC, ts3, roachpb.MakeValueFromString("vC"), txn))
+
+	slurp := func(opts MVCCIncrementalIterOptions) ([]MVCCKey, error) {
+		opts.IterOptions.UpperBound = keys.MaxKey
+		opts.StartTime = ts2
+		opts.EndTime = hlc.MaxTimestamp
+		iter := NewMVCCIncrementalIterator(db, opts)
+		defer iter.Close()
+		var found []MVCCKey
+		for iter.SeekGE(MakeMVCCMetadataKey(keys.LocalMax)); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+			found = append(found, iter.Key())
+		}
+		return found, nil
+	}
+
+	_, err := slurp(MVCCIncrementalIterOptions{})
+	require.Regexp(t, "inline values are unsupported", err)
+	_, err = slurp(MVCCIncrementalIterOptions{InlinePolicy: MVCCIncrementalIterInlinePolicyEmit})
+	require.Regexp(t, `conflicting intents on "kC"`, err)
+
+	// With both policies set to emit, the inline value and the intent are
+	// surfaced, followed by the intent's provisional value.
+	kvs, err := slurp(MVCCIncrementalIterOptions{
+		IntentPolicy: MVCCIncrementalIterIntentPolicyEmit,
+		InlinePolicy: MVCCIncrementalIterInlinePolicyEmit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []MVCCKey{
+		MakeMVCCMetadataKey(kA),
+		MakeMVCCMetadataKey(kC),
+		{Key: kC, Timestamp: ts3},
+	}, kvs)
+}
@@ -43,6 +43,8 @@ const (
 	MVCCCommitIntentOpType
 	// MVCCAbortIntentOpType corresponds to the MVCCAbortIntentOp variant.
 	MVCCAbortIntentOpType
+	// MVCCDeleteRangeOpType corresponds to the MVCCDeleteRangeOp variant.
+	MVCCDeleteRangeOpType
 )
 
 // MVCCLogicalOpDetails contains details about the occurrence of an MVCC logical
@@ -50,6 +52,7 @@ const (
 type MVCCLogicalOpDetails struct {
 	Txn       enginepb.TxnMeta
 	Key       roachpb.Key
+	EndKey    roachpb.Key
 	Timestamp hlc.Timestamp
 
 	// Safe indicates that the values in this struct will never be invalidated
@@ -80,6 +83,10 @@ func NewOpLoggerBatch(b Batch) *OpLoggerBatch {
 
 var _ Batch = &OpLoggerBatch{}
 
+func (ol *OpLoggerBatch) mayContainMVCCRangeTombstones() bool {
+	return mayContainMVCCRangeTombstones(ol.Batch)
+}
+
 // LogLogicalOp implements the Writer interface.
 func (ol *OpLoggerBatch) LogLogicalOp(op MVCCLogicalOpType, details MVCCLogicalOpDetails) {
 	if ol.distinctOpen {
@@ -135,6 +142,17 @@ func (ol *OpLoggerBatch) logLogicalOp(op MVCCLogicalOpType, details MVCCLogicalO
 		ol.recordOp(&enginepb.MVCCAbortIntentOp{
 			TxnID: details.Txn.ID,
 		})
+	case MVCCDeleteRangeOpType:
+		if !details.Safe {
+			ol.opsAlloc, details.Key = ol.opsAlloc.Copy(details.Key, 0)
+			ol.opsAlloc, details.EndKey = ol.opsAlloc.Copy(details.EndKey, 0)
+		}
+
+		ol.recordOp(&enginepb.MVCCDeleteRangeOp{
+			StartKey:  details.Key,
+			EndKey:    details.EndKey,
+			Timestamp: details.Timestamp,
+		})
 	default:
 		panic(fmt.Sprintf("unexpected op type %v", op))
 	}
@@ -169,6 +187,10 @@ type distinctOpLoggerBatch struct {
 	parent *OpLoggerBatch
 }
 
+func (dlw *distinctOpLoggerBatch) mayContainMVCCRangeTombstones() bool {
+	return mayContainMVCCRangeTombstones(dlw.ReadWriter)
+}
+
 // LogLogicalOp implements the Writer interface.
 func (dlw *distinctOpLoggerBatch) LogLogicalOp(op MVCCLogicalOpType, details MVCCLogicalOpDetails) {
 	dlw.parent.logLogicalOp(op, details)
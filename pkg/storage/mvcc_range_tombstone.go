@@ -0,0 +1,760 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// MVCCRangeTombstone is an MVCC deletion of all keys in [StartKey, EndKey) at
+// Timestamp. Any version of a key in the span with a timestamp below that of
+// the range tombstone is shadowed by it, exactly as if a point deletion
+// tombstone had been written for the key at Timestamp. Versions at or above the
+// tombstone's timestamp are unaffected. Unlike a ClearRange, a range tombstone
+// retains the history of the keys below it, so that time-travel reads,
+// incremental backups and changefeeds continue to observe the deletion.
+//
+// Range tombstones are stored as versioned keys in the range-local keyspace,
+// with the key being keys.MVCCRangeTombstoneKey(StartKey) at Timestamp and the
+// value a roachpb.Value holding EndKey. The range tombstones of a range are
+// always fragmented: any two stored tombstones either have identical bounds or
+// do not overlap at all. A fragment never crosses a range boundary, since
+// the split trigger refragments any tombstone straddling the split key.
+//
+// Range tombstones are accounted for as system data (SysBytes and SysCount),
+// with the same per-key and per-version sizes ComputeStatsForRange uses for
+// any other versioned range-local key. Their effect on the keys they shadow is
+// reflected in the regular live and GC stats.
+type MVCCRangeTombstone struct {
+	StartKey  roachpb.Key
+	EndKey    roachpb.Key
+	Timestamp hlc.Timestamp
+}
+
+// Span returns the span deleted by the range tombstone.
+func (t MVCCRangeTombstone) Span() roachpb.Span {
+	return roachpb.Span{Key: t.StartKey, EndKey: t.EndKey}
+}
+
+// MVCCRangeTombstones is a list of fragmented range tombstones, ordered by
+// start key and then by descending timestamp. Since fragments with the same
+// start key have the same end key, the list is also ordered by end key.
+type MVCCRangeTombstones []MVCCRangeTombstone
+
+// Covering returns the versions of the fragment that covers the given key, in
+// descending timestamp order, or nil if the key is not covered by any range
+// tombstone.
+func (ts MVCCRangeTombstones) Covering(key roachpb.Key) MVCCRangeTombstones {
+	i := sort.Search(len(ts), func(i int) bool {
+		return key.Compare(ts[i].EndKey) < 0
+	})
+	if i == len(ts) || key.Compare(ts[i].StartKey) < 0 {
+		return nil
+	}
+	j := i + 1
+	for j < len(ts) && ts[j].StartKey.Equal(ts[i].StartKey) {
+		j++
+	}
+	return ts[i:j]
+}
+
+// ShadowedAt returns the timestamp at which a version of the given key written
+// at the given timestamp was deleted by a range tombstone, i.e. the timestamp
+// of the oldest covering range tombstone above it. An empty timestamp is
+// returned if the version isn't shadowed by a range tombstone.
+func (ts MVCCRangeTombstones) ShadowedAt(key roachpb.Key, timestamp hlc.Timestamp) hlc.Timestamp {
+	covering := ts.Covering(key)
+	for i := len(covering) - 1; i >= 0; i-- {
+		if timestamp.Less(covering[i].Timestamp) {
+			return covering[i].Timestamp
+		}
+	}
+	return hlc.Timestamp{}
+}
+
+// newest returns the timestamp of the newest range tombstone in the list, or
+// an empty timestamp if the list is empty.
+func (ts MVCCRangeTombstones) newest() hlc.Timestamp {
+	var newest hlc.Timestamp
+	for i := range ts {
+		newest.Forward(ts[i].Timestamp)
+	}
+	return newest
+}
+
+// sysStats returns the system stats contribution of the range tombstones.
+func (ts MVCCRangeTombstones) sysStats() enginepb.MVCCStats {
+	var ms enginepb.MVCCStats
+	for i := range ts {
+		if i == 0 || !ts[i].StartKey.Equal(ts[i-1].StartKey) {
+			ms.SysCount++
+			ms.SysBytes += int64(len(keys.MVCCRangeTombstoneKey(ts[i].StartKey))) + 1
+		}
+		v := encodeMVCCRangeTombstoneValue(ts[i])
+		ms.SysBytes += int64(len(v.RawBytes)) + MVCCVersionTimestampSize
+	}
+	return ms
+}
+
+func encodeMVCCRangeTombstoneValue(t MVCCRangeTombstone) roachpb.Value {
+	v := roachpb.MakeValueFromBytes(t.EndKey)
+	v.InitChecksum(keys.MVCCRangeTombstoneKey(t.StartKey))
+	return v
+}
+
+// mvccRangeTombstonesFlag records whether an engine may contain range
+// tombstones. Most engines never see one, and looking them up costs an
+// iterator and two seeks on every MVCC operation, so reads and writes skip the
+// lookup until the flag is set. The flag is sticky: it is set when the engine
+// is opened with range tombstones, or before any range tombstone is committed
+// or ingested into it, and it is never cleared.
+type mvccRangeTombstonesFlag struct {
+	set int32
+}
+
+func (f *mvccRangeTombstonesFlag) isSet() bool {
+	return atomic.LoadInt32(&f.set) != 0
+}
+
+func (f *mvccRangeTombstonesFlag) markSet() {
+	atomic.StoreInt32(&f.set, 1)
+}
+
+// mvccRangeTombstoneReader is implemented by the readers which know whether
+// they may contain range tombstones.
+type mvccRangeTombstoneReader interface {
+	mayContainMVCCRangeTombstones() bool
+}
+
+// mayContainMVCCRangeTombstones returns false if the reader is known not to
+// contain any range tombstone. Readers that can't tell are assumed to contain
+// some.
+func mayContainMVCCRangeTombstones(reader Reader) bool {
+	if r, ok := reader.(mvccRangeTombstoneReader); ok {
+		return r.mayContainMVCCRangeTombstones()
+	}
+	return true
+}
+
+// isMVCCRangeTombstoneKey returns whether the encoded engine key is the key
+// of a range tombstone.
+func isMVCCRangeTombstoneKey(encodedKey []byte) bool {
+	return bytes.HasPrefix(encodedKey, keys.LocalMVCCRangeTombstonePrefix)
+}
+
+// mvccRangeTombstoneKeyPrefix is the common prefix of all encoded range
+// tombstone keys: the local prefix followed by the marker of the encoded start
+// key.
+var mvccRangeTombstoneKeyPrefix = keys.MVCCRangeTombstoneKey(nil)[:len(keys.LocalMVCCRangeTombstonePrefix)+1]
+
+// batchReprHasMVCCRangeTombstones returns whether the batch repr writes any
+// range tombstone.
+func batchReprHasMVCCRangeTombstones(repr []byte) (bool, error) {
+	// Decoding the batch is several times more expensive than applying it, so
+	// we first look for the prefix of the keys anywhere in the repr, which
+	// rules out almost all batches. We search for the prefix without its
+	// leading byte, which is also the kind of every entry of the batch and
+	// would make the search much slower, and check that byte for each match.
+	if !reprContainsPrefix(repr, mvccRangeTombstoneKeyPrefix) {
+		return false, nil
+	}
+	r, err := NewRocksDBBatchReader(repr)
+	if err != nil {
+		return false, err
+	}
+	for r.Next() {
+		if r.BatchType() == BatchTypeValue && isMVCCRangeTombstoneKey(r.Key()) {
+			return true, nil
+		}
+	}
+	return false, r.Error()
+}
+
+// reprContainsPrefix returns whether prefix appears anywhere in repr. The
+// prefix must be at least two bytes long.
+func reprContainsPrefix(repr, prefix []byte) bool {
+	for i := 1; i < len(repr); {
+		j := bytes.Index(repr[i:], prefix[1:])
+		if j < 0 {
+			return false
+		}
+		if i += j; repr[i-1] == prefix[0] {
+			return true
+		}
+		i++
+	}
+	return false
+}
+
+// probeMVCCRangeTombstones returns whether the reader contains any range
+// tombstone, irrespective of what mayContainMVCCRangeTombstones says.
+func probeMVCCRangeTombstones(reader Reader) (bool, error) {
+	iter := reader.NewEngineIterator(IterOptions{
+		LowerBound: keys.LocalMVCCRangeTombstonePrefix,
+		UpperBound: keys.LocalMVCCRangeTombstoneMax,
+	})
+	defer iter.Close()
+	return iter.SeekEngineKeyGE(EngineKey{Key: keys.LocalMVCCRangeTombstonePrefix})
+}
+
+// ScanMVCCRangeTombstones returns all range tombstone fragments that overlap
+// the span [start, end). The returned fragments are not truncated to the span.
+// Range tombstones only ever cover global keys, so the span is clamped to
+// keys.LocalMax.
+//
+// The range tombstones are read using an engine iterator, which allows
+// callers to hold on to MVCC iterators on the reader while scanning. No
+// iterator is created for readers which can't contain any range tombstone.
+func ScanMVCCRangeTombstones(reader Reader, start, end roachpb.Key) (MVCCRangeTombstones, error) {
+	if !mayContainMVCCRangeTombstones(reader) {
+		return nil, nil
+	}
+	if start.Compare(keys.LocalMax) < 0 {
+		start = keys.LocalMax
+	}
+	if end.Compare(start) <= 0 {
+		return nil, nil
+	}
+	iter := reader.NewEngineIterator(IterOptions{
+		LowerBound: keys.LocalMVCCRangeTombstonePrefix,
+		UpperBound: keys.MVCCRangeTombstoneKey(end),
+	})
+	defer iter.Close()
+
+	// A fragment starting before the span may still overlap it. Since fragments
+	// don't overlap, only the last fragment starting before the span can.
+	seekKey := EngineKey{Key: keys.MVCCRangeTombstoneKey(start)}
+	valid, err := iter.SeekEngineKeyLT(seekKey)
+	if err != nil {
+		return nil, err
+	}
+	if valid {
+		t, err := decodeMVCCRangeTombstone(iter)
+		if err != nil {
+			return nil, err
+		}
+		if start.Compare(t.EndKey) < 0 {
+			seekKey = EngineKey{Key: keys.MVCCRangeTombstoneKey(t.StartKey)}
+		}
+	}
+
+	var ts MVCCRangeTombstones
+	for valid, err = iter.SeekEngineKeyGE(seekKey); valid; valid, err = iter.NextEngineKey() {
+		t, err := decodeMVCCRangeTombstone(iter)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+func decodeMVCCRangeTombstone(iter EngineIterator) (MVCCRangeTombstone, error) {
+	engineKey, err := iter.UnsafeEngineKey()
+	if err != nil {
+		return MVCCRangeTombstone{}, err
+	}
+	mvccKey, err := engineKey.ToMVCCKey()
+	if err != nil {
+		return MVCCRangeTombstone{}, err
+	}
+	startKey, err := keys.DecodeMVCCRangeTombstoneKey(mvccKey.Key)
+	if err != nil {
+		return MVCCRangeTombstone{}, err
+	}
+	endKey, err := roachpb.Value{RawBytes: iter.UnsafeValue()}.GetBytes()
+	if err != nil {
+		return MVCCRangeTombstone{}, errors.Wrapf(err, "decoding range tombstone %s", mvccKey)
+	}
+	// Both keys may point into the iterator's buffers, so they are copied.
+	return MVCCRangeTombstone{
+		StartKey:  append(roachpb.Key(nil), startKey...),
+		EndKey:    append(roachpb.Key(nil), endKey...),
+		Timestamp: mvccKey.Timestamp,
+	}, nil
+}
+
+// scanMVCCRangeTombstonesAt is like ScanMVCCRangeTombstones, but returns nil
+// for inline (non-versioned) operations, which are never affected by range
+// tombstones.
+func scanMVCCRangeTombstonesAt(
+	reader Reader, start, end roachpb.Key, timestamp hlc.Timestamp,
+) (MVCCRangeTombstones, error) {
+	if timestamp.IsEmpty() {
+		return nil, nil
+	}
+	if len(end) == 0 {
+		end = start.Next()
+	}
+	return ScanMVCCRangeTombstones(reader, start, end)
+}
+
+// refragmentMVCCRangeTombstones applies fn to the range tombstones in the span
+// [start, end). The tombstones are broken up at the span bounds and the bounds
+// of all existing fragments, and fn is invoked with each resulting piece of the
+// span and the timestamps of the versions covering it (in descending order).
+// It returns the timestamps to keep for the piece. The resulting fragments are
+// returned with adjacent pieces holding identical versions merged again, but
+// fragments outside of the given tombstones are never merged into.
+func refragmentMVCCRangeTombstones(
+	ts MVCCRangeTombstones,
+	start, end roachpb.Key,
+	fn func(piece roachpb.Span, versions []hlc.Timestamp) []hlc.Timestamp,
+) MVCCRangeTombstones {
+	bounds := []roachpb.Key{start, end}
+	for _, t := range ts {
+		bounds = append(bounds, t.StartKey, t.EndKey)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Compare(bounds[j]) < 0 })
+
+	type piece struct {
+		span     roachpb.Span
+		versions []hlc.Timestamp
+	}
+	var pieces []piece
+	for i := 1; i < len(bounds); i++ {
+		span := roachpb.Span{Key: bounds[i-1], EndKey: bounds[i]}
+		if span.Key.Compare(span.EndKey) >= 0 {
+			continue
+		}
+		var versions []hlc.Timestamp
+		for _, t := range ts.Covering(span.Key) {
+			versions = append(versions, t.Timestamp)
+		}
+		if start.Compare(span.Key) <= 0 && span.EndKey.Compare(end) <= 0 {
+			versions = fn(span, versions)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[j].Less(versions[i]) })
+		n := 1
+		for j := 1; j < len(versions); j++ {
+			if !versions[j].EqOrdering(versions[n-1]) {
+				versions[n] = versions[j]
+				n++
+			}
+		}
+		versions = versions[:n]
+		if l := len(pieces); l > 0 && pieces[l-1].span.EndKey.Equal(span.Key) &&
+			timestampsEqual(pieces[l-1].versions, versions) {
+			pieces[l-1].span.EndKey = span.EndKey
+			continue
+		}
+		pieces = append(pieces, piece{span: span, versions: versions})
+	}
+
+	var result MVCCRangeTombstones
+	for _, p := range pieces {
+		for _, v := range p.versions {
+			result = append(result, MVCCRangeTombstone{StartKey: p.span.Key, EndKey: p.span.EndKey, Timestamp: v})
+		}
+	}
+	return result
+}
+
+func timestampsEqual(a, b []hlc.Timestamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].EqOrdering(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMVCCRangeTombstones replaces the range tombstone fragments in old by
+// those in new, writing and clearing only the versions that changed, and
+// updates the system stats accordingly.
+func writeMVCCRangeTombstones(
+	rw ReadWriter, ms *enginepb.MVCCStats, old, new MVCCRangeTombstones,
+) error {
+	type versionKey struct {
+		start string
+		ts    hlc.Timestamp
+	}
+	keep := make(map[versionKey]roachpb.Key, len(new))
+	for _, t := range new {
+		keep[versionKey{start: string(t.StartKey), ts: t.Timestamp}] = t.EndKey
+	}
+	existing := make(map[versionKey]roachpb.Key, len(old))
+	for _, t := range old {
+		k := versionKey{start: string(t.StartKey), ts: t.Timestamp}
+		existing[k] = t.EndKey
+		if _, ok := keep[k]; !ok {
+			if err := rw.ClearMVCC(MVCCKey{
+				Key:       keys.MVCCRangeTombstoneKey(t.StartKey),
+				Timestamp: t.Timestamp,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range new {
+		if endKey, ok := existing[versionKey{start: string(t.StartKey), ts: t.Timestamp}]; ok &&
+			endKey.Equal(t.EndKey) {
+			continue
+		}
+		v := encodeMVCCRangeTombstoneValue(t)
+		if err := rw.PutMVCC(MVCCKey{
+			Key:       keys.MVCCRangeTombstoneKey(t.StartKey),
+			Timestamp: t.Timestamp,
+		}, v.RawBytes); err != nil {
+			return err
+		}
+	}
+	if ms != nil {
+		ms.Add(new.sysStats())
+		ms.Subtract(old.sysStats())
+	}
+	return nil
+}
+
+// computeStatsWithTombstones computes the stats over [start, end) taking
+// into account the given range tombstones.
+func computeStatsWithTombstones(
+	reader Reader, tombstones MVCCRangeTombstones, start, end roachpb.Key, nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	iter := reader.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{UpperBound: end})
+	defer iter.Close()
+	return computeStatsForRange(iter, tombstones, start, end, nowNanos)
+}
+
+// MVCCDeleteRangeUsingTombstone deletes all keys in the span [start, end) at
+// the given timestamp by writing a single MVCC range tombstone, instead of a
+// point deletion tombstone per key. The history of the keys is retained below
+// the tombstone, and is removed by GC once it falls below the GC threshold.
+//
+// A WriteIntentError is returned if the span contains an intent, and a
+// WriteTooOldError if it contains a version (or another range tombstone) above
+// the timestamp. Writing a range tombstone at the same timestamp as an
+// existing one is allowed, so the operation is idempotent.
+//
+// If msCovered is provided, it must be the stats of exactly the span being
+// deleted; they allow updating ms without scanning the span when it contains
+// no intents and the stats are not estimates. Otherwise, the stats are
+// computed by scanning the span.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	start, end roachpb.Key,
+	timestamp hlc.Timestamp,
+	msCovered *enginepb.MVCCStats,
+) error {
+	if timestamp.IsEmpty() {
+		return errors.AssertionFailedf("range tombstones must have a timestamp")
+	}
+	if start.Compare(keys.LocalMax) < 0 || end.Compare(start) <= 0 {
+		return errors.AssertionFailedf("invalid range tombstone span %s", roachpb.Span{Key: start, EndKey: end})
+	}
+
+	existing, err := ScanMVCCRangeTombstones(rw, start, end)
+	if err != nil {
+		return err
+	}
+	if newest := existing.newest(); timestamp.Less(newest) {
+		return roachpb.NewWriteTooOldError(timestamp, newest.Next())
+	}
+
+	// Check for conflicts with the point keys in the span. Without precomputed
+	// stats we have to scan the span anyway, so we look at every key. Otherwise,
+	// a time-bound iterator finds any versions at or above the timestamp.
+	fast := msCovered != nil && msCovered.IntentCount == 0 && msCovered.ContainsEstimates == 0
+	if err := checkMVCCRangeTombstoneConflicts(rw, start, end, timestamp, fast); err != nil {
+		return err
+	}
+
+	updated := refragmentMVCCRangeTombstones(existing, start, end,
+		func(_ roachpb.Span, versions []hlc.Timestamp) []hlc.Timestamp {
+			return append(versions, timestamp)
+		})
+
+	if ms != nil {
+		if fast {
+			// Every live key in the span is now deleted at the tombstone's
+			// timestamp, from which on it accrues GCBytesAge.
+			ms.Add(enginepb.MVCCStats{
+				LastUpdateNanos: timestamp.WallTime,
+				LiveBytes:       -msCovered.LiveBytes,
+				LiveCount:       -msCovered.LiveCount,
+			})
+		} else {
+			msBefore, err := computeStatsWithTombstones(rw, existing, start, end, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			msAfter, err := computeStatsWithTombstones(rw, updated, start, end, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			msAfter.Subtract(msBefore)
+			ms.Add(msAfter)
+		}
+	}
+	if err := writeMVCCRangeTombstones(rw, ms, existing, updated); err != nil {
+		return err
+	}
+
+	rw.LogLogicalOp(MVCCDeleteRangeOpType, MVCCLogicalOpDetails{
+		Key:       start,
+		EndKey:    end,
+		Timestamp: timestamp,
+	})
+	return nil
+}
+
+// checkMVCCRangeTombstoneConflicts returns an error if there is an intent or a
+// version at or above the given timestamp in the span. Unless timeBound is set,
+// inline values are rejected too; if it is, the caller must guarantee that
+// there are none, and that the span contains no intents.
+func checkMVCCRangeTombstoneConflicts(
+	reader Reader, start, end roachpb.Key, timestamp hlc.Timestamp, timeBound bool,
+) error {
+	var iter MVCCIterator
+	if timeBound {
+		iter = reader.NewMVCCIterator(MVCCKeyIterKind, IterOptions{
+			UpperBound:       end,
+			MinTimestampHint: timestamp,
+			MaxTimestampHint: hlc.MaxTimestamp,
+		})
+	} else {
+		iter = reader.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{UpperBound: end})
+	}
+	defer iter.Close()
+
+	var meta enginepb.MVCCMetadata
+	for iter.SeekGE(MakeMVCCMetadataKey(start)); ; {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		k := iter.UnsafeKey()
+		if !k.IsValue() {
+			if err := iter.ValueProto(&meta); err != nil {
+				return err
+			}
+			if meta.Txn != nil {
+				return &roachpb.WriteIntentError{
+					Intents: []roachpb.Intent{roachpb.MakeIntent(meta.Txn, append([]byte{}, k.Key...))},
+				}
+			}
+			if meta.IsInline() {
+				return errors.Errorf("cannot write range tombstone over inline value %s", k.Key)
+			}
+			iter.Next()
+			continue
+		}
+		if timestamp.LessEq(k.Timestamp) {
+			return roachpb.NewWriteTooOldError(timestamp, k.Timestamp.Next())
+		}
+		if timeBound {
+			// The time-bound iterator may surface versions outside of its hints.
+			iter.Next()
+		} else {
+			// The newest version is below the timestamp, so all others are too.
+			iter.NextKey()
+		}
+	}
+}
+
+// ClearMVCCRangeTombstones removes all range tombstones in the span
+// [start, end), truncating any fragments that extend beyond it, and updates
+// the system stats accordingly. It does not update the stats for the point
+// keys previously shadowed by the range tombstones; it is meant to be used
+// alongside the removal of these keys, e.g. by ClearRange.
+func ClearMVCCRangeTombstones(
+	rw ReadWriter, ms *enginepb.MVCCStats, start, end roachpb.Key,
+) error {
+	existing, err := ScanMVCCRangeTombstones(rw, start, end)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	updated := refragmentMVCCRangeTombstones(existing, start, end,
+		func(roachpb.Span, []hlc.Timestamp) []hlc.Timestamp { return nil })
+	return writeMVCCRangeTombstones(rw, ms, existing, updated)
+}
+
+// clearMVCCRangeTombstoneTimeRange removes the versions of range tombstones in
+// the span [start, end) with timestamps in (startTime, endTime], and updates
+// the system stats accordingly. Like ClearMVCCRangeTombstones, it leaves the
+// stats of the point keys previously shadowed by them to the caller.
+func clearMVCCRangeTombstoneTimeRange(
+	rw ReadWriter, ms *enginepb.MVCCStats, start, end roachpb.Key, startTime, endTime hlc.Timestamp,
+) error {
+	existing, err := ScanMVCCRangeTombstones(rw, start, end)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	updated := refragmentMVCCRangeTombstones(existing, start, end,
+		func(_ roachpb.Span, versions []hlc.Timestamp) []hlc.Timestamp {
+			var keep []hlc.Timestamp
+			for _, v := range versions {
+				if v.LessEq(startTime) || endTime.Less(v) {
+					keep = append(keep, v)
+				}
+			}
+			return keep
+		})
+	return writeMVCCRangeTombstones(rw, ms, existing, updated)
+}
+
+// SplitMVCCRangeTombstones splits any range tombstone fragment straddling
+// splitKey into one fragment on each side of it. It is used by the split
+// trigger to make sure that each range only holds fragments within its own
+// bounds.
+func SplitMVCCRangeTombstones(
+	_ context.Context, rw ReadWriter, ms *enginepb.MVCCStats, splitKey roachpb.Key,
+) error {
+	existing, err := ScanMVCCRangeTombstones(rw, splitKey, splitKey.Next())
+	if err != nil {
+		return err
+	}
+	var old, updated MVCCRangeTombstones
+	for _, t := range existing {
+		if t.StartKey.Compare(splitKey) >= 0 {
+			continue
+		}
+		old = append(old, t)
+		left, right := t, t
+		left.EndKey, right.StartKey = splitKey, splitKey
+		updated = append(updated, left, right)
+	}
+	if len(old) == 0 {
+		return nil
+	}
+	sort.Slice(updated, func(i, j int) bool {
+		if c := updated[i].StartKey.Compare(updated[j].StartKey); c != 0 {
+			return c < 0
+		}
+		return updated[j].Timestamp.Less(updated[i].Timestamp)
+	})
+	return writeMVCCRangeTombstones(rw, ms, old, updated)
+}
+
+// MVCCGarbageCollectRangeTombstones removes the versions of range tombstones
+// in the span [start, end) at or below the GC threshold which no longer shadow
+// any point key version, i.e. once GC has removed all the versions they
+// deleted. Removing such a version has no effect on the visible history of the
+// span, and only changes the system stats.
+//
+// The point key versions are read from pointReader, which must observe the
+// same state as rw. It is separate from rw so that callers can read the point
+// keys without the access checks applied to rw: versions below the GC
+// threshold are immutable, so reading them doesn't require latches.
+func MVCCGarbageCollectRangeTombstones(
+	ctx context.Context,
+	rw ReadWriter,
+	pointReader Reader,
+	ms *enginepb.MVCCStats,
+	start, end roachpb.Key,
+	threshold hlc.Timestamp,
+) error {
+	existing, err := ScanMVCCRangeTombstones(rw, start, end)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	var iterErr error
+	updated := refragmentMVCCRangeTombstones(existing, start, end,
+		func(piece roachpb.Span, versions []hlc.Timestamp) []hlc.Timestamp {
+			var maxGCable hlc.Timestamp
+			for _, v := range versions {
+				if v.LessEq(threshold) {
+					maxGCable.Forward(v)
+				}
+			}
+			if maxGCable.IsEmpty() || iterErr != nil {
+				return versions
+			}
+			// A version of the tombstone can be removed if there are no point
+			// versions below it left for it to shadow.
+			oldest, err := oldestMVCCVersionBelow(pointReader, piece, maxGCable)
+			if err != nil {
+				iterErr = err
+				return versions
+			}
+			var keep []hlc.Timestamp
+			for _, v := range versions {
+				if threshold.Less(v) || (!oldest.IsEmpty() && oldest.Less(v)) {
+					keep = append(keep, v)
+				}
+			}
+			return keep
+		})
+	if iterErr != nil {
+		return iterErr
+	}
+	return writeMVCCRangeTombstones(rw, ms, existing, updated)
+}
+
+// oldestMVCCVersionBelow returns the timestamp of the oldest point key version
+// in the span at or below the given timestamp, or an empty timestamp if there
+// is none.
+func oldestMVCCVersionBelow(
+	reader Reader, span roachpb.Span, timestamp hlc.Timestamp,
+) (hlc.Timestamp, error) {
+	iter := reader.NewMVCCIterator(MVCCKeyIterKind, IterOptions{
+		UpperBound:       span.EndKey,
+		MinTimestampHint: hlc.Timestamp{}.Next(),
+		MaxTimestampHint: timestamp,
+	})
+	defer iter.Close()
+
+	var oldest hlc.Timestamp
+	for iter.SeekGE(MakeMVCCMetadataKey(span.Key)); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return hlc.Timestamp{}, err
+		} else if !ok {
+			return oldest, nil
+		}
+		ts := iter.UnsafeKey().Timestamp
+		if ts.IsEmpty() || timestamp.Less(ts) {
+			continue
+		}
+		if oldest.IsEmpty() || ts.Less(oldest) {
+			oldest = ts
+		}
+	}
+}
+
+// ComputeStatsWithRangeTombstones is like ComputeStatsForRange, but also
+// accounts for the range tombstones covering the span: point key versions
+// shadowed by a range tombstone are considered deleted at its timestamp. The
+// range tombstones are read from the given reader, which must be the one the
+// iterator was created from.
+func ComputeStatsWithRangeTombstones(
+	reader Reader,
+	iter SimpleMVCCIterator,
+	start, end roachpb.Key,
+	nowNanos int64,
+	callbacks ...func(MVCCKey, []byte) error,
+) (enginepb.MVCCStats, error) {
+	tombstones, err := ScanMVCCRangeTombstones(reader, start, end)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	return computeStatsForRange(iter, tombstones, start, end, nowNanos, callbacks...)
+}
@@ -0,0 +1,332 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/kr/pretty"
+	"github.com/stretchr/testify/require"
+)
+
+// assertRangeTombstoneStats verifies that the given stats match the stats
+// recomputed from scratch, including the range tombstones.
+func assertRangeTombstoneStats(
+	t *testing.T, reader Reader, debug string, ms enginepb.MVCCStats, nowNanos int64,
+) {
+	t.Helper()
+	iter := reader.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{UpperBound: roachpb.KeyMax})
+	defer iter.Close()
+	expMS, err := ComputeStatsWithRangeTombstones(reader, iter, roachpb.KeyMin, roachpb.KeyMax, nowNanos)
+	require.NoError(t, err)
+	ms.AgeTo(nowNanos)
+	if !ms.Equal(expMS) {
+		t.Errorf("%s: diff(ms, expMS) = %s", debug, pretty.Diff(ms, expMS))
+	}
+}
+
+func TestMVCCDeleteRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			var ms enginepb.MVCCStats
+			for _, key := range []roachpb.Key{testKey1, testKey2, testKey3} {
+				require.NoError(t, MVCCPut(ctx, engine, &ms, key, ts(1), value1, nil))
+			}
+			require.NoError(t, MVCCPut(ctx, engine, &ms, testKey2, ts(2), value2, nil))
+
+			// Delete [testKey1, testKey3) at ts 5.
+			require.NoError(t, MVCCDeleteRangeUsingTombstone(
+				ctx, engine, &ms, testKey1, testKey3, ts(5), nil /* msCovered */))
+			assertRangeTombstoneStats(t, engine, "after delete range", ms, 5)
+
+			tombstones, err := ScanMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+			require.NoError(t, err)
+			require.Equal(t, MVCCRangeTombstones{
+				{StartKey: testKey1, EndKey: testKey3, Timestamp: ts(5)},
+			}, tombstones)
+
+			// Reads below the tombstone see the history, reads above don't.
+			value, _, err := MVCCGet(ctx, engine, testKey2, ts(4), MVCCGetOptions{})
+			require.NoError(t, err)
+			require.NotNil(t, value)
+			require.Equal(t, value2.RawBytes, value.RawBytes)
+
+			value, _, err = MVCCGet(ctx, engine, testKey2, ts(5), MVCCGetOptions{})
+			require.NoError(t, err)
+			require.Nil(t, value)
+
+			value, _, err = MVCCGet(ctx, engine, testKey2, ts(5), MVCCGetOptions{Tombstones: true})
+			require.NoError(t, err)
+			require.NotNil(t, value)
+			require.Empty(t, value.RawBytes)
+
+			res, err := MVCCScan(ctx, engine, testKey1, testKey4, ts(6), MVCCScanOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 1)
+			require.Equal(t, testKey3, res.KVs[0].Key)
+
+			res, err = MVCCScan(ctx, engine, testKey1, testKey4, ts(4), MVCCScanOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 3)
+
+			// Writes below the tombstone are pushed above it.
+			err = MVCCPut(ctx, engine, &ms, testKey1, ts(3), value2, nil)
+			require.True(t, errors.HasType(err, (*roachpb.WriteTooOldError)(nil)), "%+v", err)
+
+			// Writes above the tombstone are visible again.
+			require.NoError(t, MVCCPut(ctx, engine, &ms, testKey1, ts(7), value2, nil))
+			value, _, err = MVCCGet(ctx, engine, testKey1, ts(8), MVCCGetOptions{})
+			require.NoError(t, err)
+			require.NotNil(t, value)
+			require.Equal(t, value2.RawBytes, value.RawBytes)
+			assertRangeTombstoneStats(t, engine, "after put", ms, 8)
+
+			// A tombstone can't be written below existing versions.
+			err = MVCCDeleteRangeUsingTombstone(ctx, engine, &ms, testKey1, testKey4, ts(6), nil)
+			require.True(t, errors.HasType(err, (*roachpb.WriteTooOldError)(nil)), "%+v", err)
+
+			// Overlapping tombstones are fragmented.
+			require.NoError(t, MVCCDeleteRangeUsingTombstone(
+				ctx, engine, &ms, testKey2, testKey4, ts(9), nil /* msCovered */))
+			assertRangeTombstoneStats(t, engine, "after overlapping delete range", ms, 9)
+
+			tombstones, err = ScanMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+			require.NoError(t, err)
+			require.Equal(t, MVCCRangeTombstones{
+				{StartKey: testKey1, EndKey: testKey2, Timestamp: ts(5)},
+				{StartKey: testKey2, EndKey: testKey3, Timestamp: ts(9)},
+				{StartKey: testKey2, EndKey: testKey3, Timestamp: ts(5)},
+				{StartKey: testKey3, EndKey: testKey4, Timestamp: ts(9)},
+			}, tombstones)
+
+			res, err = MVCCScan(ctx, engine, testKey1, testKey4, ts(10), MVCCScanOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 1)
+			require.Equal(t, testKey1, res.KVs[0].Key)
+		})
+	}
+}
+
+func TestMVCCDeleteRangeUsingTombstoneIntent(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	txn := *txn1
+	txn.ReadTimestamp = hlc.Timestamp{WallTime: 1}
+	txn.WriteTimestamp = txn.ReadTimestamp
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, txn.ReadTimestamp, value1, &txn))
+
+	err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, nil, testKey1, testKey3, hlc.Timestamp{WallTime: 2}, nil /* msCovered */)
+	require.True(t, errors.HasType(err, (*roachpb.WriteIntentError)(nil)), "%+v", err)
+}
+
+// TestMVCCRangeTombstonesFlag verifies that engines only look up range
+// tombstones once they may contain some, and that they never miss any.
+func TestMVCCRangeTombstonesFlag(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := hlc.Timestamp{WallTime: 2}
+	openEngine := func(fs vfs.FS) *Pebble {
+		opts := DefaultPebbleOptions()
+		opts.FS = fs
+		eng, err := NewPebble(ctx, PebbleConfig{
+			StorageConfig: base.StorageConfig{Dir: "db"},
+			Opts:          opts,
+		})
+		require.NoError(t, err)
+		return eng
+	}
+
+	fs := vfs.NewMem()
+	engine := openEngine(fs)
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, hlc.Timestamp{WallTime: 1}, value1, nil))
+	require.False(t, mayContainMVCCRangeTombstones(engine))
+	snapshot := engine.NewSnapshot()
+	defer snapshot.Close()
+	require.False(t, mayContainMVCCRangeTombstones(snapshot))
+
+	// A batch sees its own range tombstones before they are committed.
+	batch := engine.NewBatch()
+	defer batch.Close()
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(
+		ctx, batch, nil, testKey1, testKey3, ts, nil /* msCovered */))
+	require.True(t, mayContainMVCCRangeTombstones(batch))
+	require.False(t, mayContainMVCCRangeTombstones(engine))
+	value, _, err := MVCCGet(ctx, batch, testKey2, ts, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// Applying the batch to another engine marks it too.
+	other := openEngine(vfs.NewMem())
+	defer other.Close()
+	otherBatch := other.NewBatch()
+	defer otherBatch.Close()
+	require.NoError(t, otherBatch.ApplyBatchRepr(batch.Repr(), false /* sync */))
+	require.True(t, mayContainMVCCRangeTombstones(otherBatch))
+	require.False(t, mayContainMVCCRangeTombstones(other))
+	require.NoError(t, otherBatch.Commit(false /* sync */))
+	require.True(t, mayContainMVCCRangeTombstones(other))
+
+	require.NoError(t, batch.Commit(false /* sync */))
+	require.True(t, mayContainMVCCRangeTombstones(engine))
+	require.True(t, mayContainMVCCRangeTombstones(snapshot))
+	value, _, err = MVCCGet(ctx, engine, testKey2, ts, MVCCGetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// The range tombstones are found again when the engine is reopened.
+	engine.Close()
+	engine = openEngine(fs)
+	defer engine.Close()
+	require.True(t, mayContainMVCCRangeTombstones(engine))
+}
+
+func TestMVCCGarbageCollectRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	var ms enginepb.MVCCStats
+	require.NoError(t, MVCCPut(ctx, engine, &ms, testKey1, ts(1), value1, nil))
+	require.NoError(t, MVCCPut(ctx, engine, &ms, testKey2, ts(1), value1, nil))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(
+		ctx, engine, &ms, testKey1, testKey3, ts(2), nil /* msCovered */))
+
+	// The tombstone still shadows versions, so it can't be removed yet.
+	require.NoError(t, MVCCGarbageCollectRangeTombstones(
+		ctx, engine, engine, &ms, roachpb.KeyMin, roachpb.KeyMax, ts(3)))
+	tombstones, err := ScanMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+
+	// GC the shadowed versions, which are garbage once the tombstone is below
+	// the threshold.
+	require.NoError(t, MVCCGarbageCollect(ctx, engine, &ms, []roachpb.GCRequest_GCKey{
+		{Key: testKey1, Timestamp: ts(1)},
+		{Key: testKey2, Timestamp: ts(1)},
+	}, ts(3)))
+	assertRangeTombstoneStats(t, engine, "after point GC", ms, 3)
+
+	// The tombstone can be removed now, but only once it's below the threshold.
+	require.NoError(t, MVCCGarbageCollectRangeTombstones(
+		ctx, engine, engine, &ms, roachpb.KeyMin, roachpb.KeyMax, ts(1)))
+	tombstones, err = ScanMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+
+	require.NoError(t, MVCCGarbageCollectRangeTombstones(
+		ctx, engine, engine, &ms, roachpb.KeyMin, roachpb.KeyMax, ts(3)))
+	tombstones, err = ScanMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+	require.NoError(t, err)
+	require.Empty(t, tombstones)
+	assertRangeTombstoneStats(t, engine, "after tombstone GC", ms, 3)
+	require.Zero(t, ms.SysCount)
+	require.Zero(t, ms.SysBytes)
+}
+
+// slurpKVsInSpanAndTimeRange returns all versions in [start, end) that an
+// incremental iteration over the given time range emits.
+func slurpKVsInSpanAndTimeRange(
+	t *testing.T, reader Reader, start, end roachpb.Key, startTime, endTime hlc.Timestamp,
+) []MVCCKeyValue {
+	t.Helper()
+	iter := NewMVCCIncrementalIterator(reader, MVCCIncrementalIterOptions{
+		IterOptions: IterOptions{
+			UpperBound: end,
+		},
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	defer iter.Close()
+	var kvs []MVCCKeyValue
+	for iter.SeekGE(MakeMVCCMetadataKey(start)); ; iter.Next() {
+		ok, err := iter.Valid()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		kvs = append(kvs, MVCCKeyValue{Key: iter.Key(), Value: iter.Value()})
+	}
+	return kvs
+}
+
+func TestMVCCIncrementalIteratorRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, ts(1), value1, nil))
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, ts(1), value1, nil))
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, ts(3), value2, nil))
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey3, ts(1), value1, nil))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(
+		ctx, engine, nil, testKey1, testKey4, ts(5), nil /* msCovered */))
+
+	// An incremental iteration across the tombstone observes a point deletion
+	// for every key that existed below it.
+	kvs := slurpKVsInSpanAndTimeRange(t, engine, testKey1, testKey4, ts(4), ts(5))
+	require.Equal(t, []MVCCKeyValue{
+		{Key: MVCCKey{Key: testKey1, Timestamp: ts(5)}},
+		{Key: MVCCKey{Key: testKey2, Timestamp: ts(5)}},
+		{Key: MVCCKey{Key: testKey3, Timestamp: ts(5)}},
+	}, kvs)
+
+	// Iterations that don't include the tombstone's timestamp are unaffected.
+	kvs = slurpKVsInSpanAndTimeRange(t, engine, testKey1, testKey4, ts(2), ts(4))
+	require.Equal(t, []MVCCKeyValue{
+		{Key: MVCCKey{Key: testKey2, Timestamp: ts(3)}, Value: value2.RawBytes},
+	}, kvs)
+
+	// The synthetic deletions are interleaved with the point versions.
+	kvs = slurpKVsInSpanAndTimeRange(t, engine, testKey2, testKey4, hlc.Timestamp{}, ts(5))
+	require.Equal(t, []MVCCKeyValue{
+		{Key: MVCCKey{Key: testKey2, Timestamp: ts(5)}},
+		{Key: MVCCKey{Key: testKey2, Timestamp: ts(3)}, Value: value2.RawBytes},
+		{Key: MVCCKey{Key: testKey2, Timestamp: ts(1)}, Value: value1.RawBytes},
+		{Key: MVCCKey{Key: testKey3, Timestamp: ts(5)}},
+		{Key: MVCCKey{Key: testKey3, Timestamp: ts(1)}, Value: value1.RawBytes},
+	}, kvs)
+}
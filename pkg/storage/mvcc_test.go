@@ -1431,6 +1431,156 @@ func TestMVCCScanInconsistent(t *testing.T) {
 	}
 }
 
+// mockLockTableView is a LockTableView that reports the configured keys as
+// locked by the associated transaction.
+type mockLockTableView map[string]*enginepb.TxnMeta
+
+func (m mockLockTableView) IsKeyLockedByConflictingTxn(key roachpb.Key) (bool, *enginepb.TxnMeta) {
+	txn, ok := m[string(key)]
+	return ok, txn
+}
+
+// TestMVCCScanSkipLocked verifies that scans and gets with the SkipLocked
+// option omit keys that are locked by other transactions, whether through an
+// intent or through a lock in the lock table, instead of returning a
+// WriteIntentError.
+func TestMVCCScanSkipLocked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ts1 := hlc.Timestamp{WallTime: 1}
+			ts2 := hlc.Timestamp{WallTime: 2}
+			ts3 := hlc.Timestamp{WallTime: 3}
+			for _, k := range []roachpb.Key{testKey1, testKey2, testKey3, testKey4} {
+				if err := MVCCPut(ctx, engine, nil, k, ts1, value1, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// txn2 holds an intent on testKey3 and an unreplicated lock on
+			// testKey2 in the lock table.
+			txn2ts2 := makeTxn(*txn2, ts2)
+			if err := MVCCPut(ctx, engine, nil, testKey3, txn2ts2.ReadTimestamp, value2, txn2ts2); err != nil {
+				t.Fatal(err)
+			}
+			lockTable := mockLockTableView{string(testKey2): &txn2ts2.TxnMeta}
+			txn1ts3 := makeTxn(*txn1, ts3)
+
+			// Without SkipLocked, the intent causes a WriteIntentError.
+			_, err := MVCCScan(ctx, engine, testKey1, testKey4.Next(), ts3, MVCCScanOptions{
+				Txn: txn1ts3, FailOnMoreRecent: true,
+			})
+			if !errors.HasType(err, (*roachpb.WriteIntentError)(nil)) {
+				t.Fatalf("expected WriteIntentError, found %v", err)
+			}
+
+			keysOf := func(res MVCCScanResult) []roachpb.Key {
+				var ks []roachpb.Key
+				for _, kv := range res.KVs {
+					ks = append(ks, kv.Key)
+				}
+				return ks
+			}
+			for _, tc := range []struct {
+				name      string
+				start     roachpb.Key
+				reverse   bool
+				maxKeys   int64
+				lockTable LockTableView
+				expKeys   []roachpb.Key
+			}{
+				{
+					name:    "intents only",
+					start:   testKey1,
+					expKeys: []roachpb.Key{testKey1, testKey2, testKey4},
+				},
+				{
+					name:      "intents and lock table",
+					start:     testKey1,
+					lockTable: lockTable,
+					expKeys:   []roachpb.Key{testKey1, testKey4},
+				},
+				{
+					name:      "reverse",
+					start:     testKey1,
+					reverse:   true,
+					lockTable: lockTable,
+					expKeys:   []roachpb.Key{testKey4, testKey1},
+				},
+				{
+					name:      "limit",
+					start:     testKey2,
+					maxKeys:   1,
+					lockTable: lockTable,
+					expKeys:   []roachpb.Key{testKey4},
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					res, err := MVCCScan(ctx, engine, tc.start, testKey4.Next(), ts3, MVCCScanOptions{
+						Txn:              txn1ts3,
+						FailOnMoreRecent: true,
+						SkipLocked:       true,
+						LockTable:        tc.lockTable,
+						Reverse:          tc.reverse,
+						MaxKeys:          tc.maxKeys,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(res.Intents) != 0 {
+						t.Fatalf("expected no intents, found %v", res.Intents)
+					}
+					if ks := keysOf(res); !reflect.DeepEqual(ks, tc.expKeys) {
+						t.Fatalf("expected keys %v, found %v", tc.expKeys, ks)
+					}
+				})
+			}
+
+			// Gets treat locked keys as missing.
+			for _, tc := range []struct {
+				key      roachpb.Key
+				expValue bool
+			}{
+				{testKey1, true},
+				{testKey2, false},
+				{testKey3, false},
+			} {
+				val, intent, err := MVCCGet(ctx, engine, tc.key, ts3, MVCCGetOptions{
+					Txn:        txn1ts3,
+					SkipLocked: true,
+					LockTable:  lockTable,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if intent != nil {
+					t.Fatalf("expected no intent, found %v", intent)
+				}
+				if (val != nil) != tc.expValue {
+					t.Fatalf("%s: expected value %t, found %v", tc.key, tc.expValue, val)
+				}
+			}
+
+			// SkipLocked is incompatible with inconsistent reads.
+			if _, err := MVCCScan(ctx, engine, testKey1, testKey4.Next(), ts3, MVCCScanOptions{
+				Inconsistent: true, SkipLocked: true,
+			}); !testutils.IsError(err, "cannot allow inconsistent reads with skip locked option") {
+				t.Fatalf("expected error, found %v", err)
+			}
+			if _, _, err := MVCCGet(ctx, engine, testKey1, ts3, MVCCGetOptions{
+				Inconsistent: true, SkipLocked: true,
+			}); !testutils.IsError(err, "cannot allow inconsistent reads with skip locked option") {
+				t.Fatalf("expected error, found %v", err)
+			}
+		})
+	}
+}
+
 func TestMVCCDeleteRange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/pebble/bloom"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/cockroachdb/redact"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -431,6 +432,18 @@ type EncryptionStatsHandler interface {
 	GetKeyIDFromSettings(settings []byte) (string, error)
 }
 
+// DataFileReencrypter is implemented by the EncryptionStatsHandler of
+// encrypted environments that can rewrite existing files under the active
+// data key.
+type DataFileReencrypter interface {
+	// ReencryptFile rewrites the named file of the data FS under the active
+	// data key, waiting on the limiter, if any, for the bytes it writes. It
+	// returns the number of bytes rewritten, which is zero if the file was
+	// removed in the meantime. The rewrite is atomic: concurrent and future readers see
+	// either the old or the new file, both of which have the same contents.
+	ReencryptFile(ctx context.Context, filename string, limiter *rate.Limiter) (int64, error)
+}
+
 // Pebble is a wrapper around a Pebble database instance.
 type Pebble struct {
 	db *pebble.DB
@@ -455,6 +468,11 @@ type Pebble struct {
 
 	useWrappedIntentWriter bool
 	wrappedIntentWriter    intentDemuxWriter
+
+	// rangeTombstones is set once the engine may contain MVCC range
+	// tombstones. It is shared with the batches, read-only engines and
+	// snapshots created from the engine.
+	rangeTombstones mvccRangeTombstonesFlag
 }
 
 var _ Engine = &Pebble{}
@@ -562,9 +580,34 @@ func NewPebble(ctx context.Context, cfg PebbleConfig) (*Pebble, error) {
 	}
 	p.db = db
 
+	if err := p.probeMVCCRangeTombstones(); err != nil {
+		p.Close()
+		return nil, err
+	}
+
 	return p, nil
 }
 
+// probeMVCCRangeTombstones sets the rangeTombstones flag if the engine
+// contains range tombstones.
+func (p *Pebble) probeMVCCRangeTombstones() error {
+	if p.rangeTombstones.isSet() {
+		return nil
+	}
+	found, err := probeMVCCRangeTombstones(p)
+	if err != nil {
+		return err
+	}
+	if found {
+		p.rangeTombstones.markSet()
+	}
+	return nil
+}
+
+func (p *Pebble) mayContainMVCCRangeTombstones() bool {
+	return p.rangeTombstones.isSet()
+}
+
 func newPebbleInMem(
 	ctx context.Context, attrs roachpb.Attributes, cacheSize int64, settings *cluster.Settings,
 ) *Pebble {
@@ -731,6 +774,16 @@ func (p *Pebble) NewEngineIterator(opts IterOptions) EngineIterator {
 
 // ApplyBatchRepr implements the Engine interface.
 func (p *Pebble) ApplyBatchRepr(repr []byte, sync bool) error {
+	if !p.rangeTombstones.isSet() {
+		found, err := batchReprHasMVCCRangeTombstones(repr)
+		if err != nil {
+			return err
+		}
+		if found {
+			p.rangeTombstones.markSet()
+		}
+	}
+
 	// batch.SetRepr takes ownership of the underlying slice, so make a copy.
 	reprCopy := make([]byte, len(repr))
 	copy(reprCopy, repr)
@@ -872,14 +925,22 @@ func (p *Pebble) PutEngineKey(key EngineKey, value []byte) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.Set(key.Encode(), value, pebble.Sync)
+	encodedKey := key.Encode()
+	if isMVCCRangeTombstoneKey(encodedKey) {
+		p.rangeTombstones.markSet()
+	}
+	return p.db.Set(encodedKey, value, pebble.Sync)
 }
 
 func (p *Pebble) put(key MVCCKey, value []byte) error {
 	if len(key.Key) == 0 {
 		return emptyKeyError()
 	}
-	return p.db.Set(EncodeKey(key), value, pebble.Sync)
+	encodedKey := EncodeKey(key)
+	if isMVCCRangeTombstoneKey(encodedKey) {
+		p.rangeTombstones.markSet()
+	}
+	return p.db.Set(encodedKey, value, pebble.Sync)
 }
 
 // LogData implements the Engine interface.
@@ -1004,31 +1065,131 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		}
 	}
 
+	usage := make(map[string]*DataKeyUsage)
 	for filePath, entry := range fr.Files {
-		keyID, err := p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
+		keyID, err := p.fileKeyID(entry)
 		if err != nil {
 			return nil, err
 		}
-		if len(keyID) == 0 {
-			keyID = "plain"
+		u, ok := usage[keyID]
+		if !ok {
+			u = &DataKeyUsage{KeyID: keyID}
+			usage[keyID] = u
 		}
-		if keyID != activeKeyID {
-			continue
+		u.Files++
+
+		fileNum, ok, err := p.parseSSTableFileNum(filePath)
+		if err != nil {
+			return nil, err
 		}
-		stats.ActiveKeyFiles++
+		if ok {
+			u.Bytes += sstSizes[fileNum]
+		}
+	}
+	for _, u := range usage {
+		stats.DataKeyUsage = append(stats.DataKeyUsage, *u)
+	}
+	sort.Slice(stats.DataKeyUsage, func(i, j int) bool {
+		return stats.DataKeyUsage[i].KeyID < stats.DataKeyUsage[j].KeyID
+	})
+	if u, ok := usage[activeKeyID]; ok {
+		stats.ActiveKeyFiles = u.Files
+		stats.ActiveKeyBytes = u.Bytes
+	}
+	return stats, nil
+}
+
+// fileKeyID returns the ID of the data key the file registry entry is
+// encrypted with, or "plain" for plaintext files.
+func (p *Pebble) fileKeyID(entry *enginepb.FileEntry) (string, error) {
+	keyID, err := p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
+	if err != nil {
+		return "", err
+	}
+	if len(keyID) == 0 {
+		keyID = "plain"
+	}
+	return keyID, nil
+}
+
+// parseSSTableFileNum returns the file number of the sstable with the given
+// path, and false if the path isn't that of an sstable.
+func (p *Pebble) parseSSTableFileNum(filePath string) (pebble.FileNum, bool, error) {
+	filename := p.fs.PathBase(filePath)
+	numStr := strings.TrimSuffix(filename, ".sst")
+	if len(numStr) == len(filename) {
+		return 0, false, nil // not a sstable
+	}
+	u, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+	}
+	return pebble.FileNum(u), true, nil
+}
 
-		filename := p.fs.PathBase(filePath)
-		numStr := strings.TrimSuffix(filename, ".sst")
-		if len(numStr) == len(filename) {
-			continue // not a sstable
+// ReencryptDataFiles implements the Engine interface.
+//
+// Only sstables are rewritten, as they are immutable once written. The WAL and
+// MANIFEST files are replaced by new ones, which use the active data key, in
+// the normal course of operation.
+func (p *Pebble) ReencryptDataFiles(
+	ctx context.Context, limiter *rate.Limiter,
+) (files int, size int64, _ error) {
+	if p.statsHandler == nil {
+		return 0, 0, nil
+	}
+	reencrypter, ok := p.statsHandler.(DataFileReencrypter)
+	if !ok {
+		return 0, 0, nil
+	}
+	activeKeyID, err := p.statsHandler.GetActiveDataKeyID()
+	if err != nil {
+		return 0, 0, err
+	}
+	fr := p.fileRegistry.getRegistryCopy()
+	type sstable struct {
+		path    string
+		fileNum pebble.FileNum
+	}
+	var sstables []sstable
+	for filePath, entry := range fr.Files {
+		if entry.EnvType != enginepb.EnvType_Data || p.fs.PathBase(filePath) != filePath {
+			// Only files in the data directory are rewritten.
+			continue
 		}
-		u, err := strconv.ParseUint(numStr, 10, 64)
+		fileNum, ok, err := p.parseSSTableFileNum(filePath)
 		if err != nil {
-			return nil, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
+			return 0, 0, err
+		} else if !ok {
+			continue
+		}
+		keyID, err := p.fileKeyID(entry)
+		if err != nil {
+			return 0, 0, err
+		}
+		if keyID != activeKeyID {
+			sstables = append(sstables, sstable{path: filePath, fileNum: fileNum})
 		}
-		stats.ActiveKeyBytes += sstSizes[pebble.FileNum(u)]
 	}
-	return stats, nil
+	// Rewrite the oldest sstables first, as they are the least likely to be
+	// compacted away.
+	sort.Slice(sstables, func(i, j int) bool {
+		return sstables[i].fileNum < sstables[j].fileNum
+	})
+	for _, sst := range sstables {
+		if err := ctx.Err(); err != nil {
+			return files, size, err
+		}
+		n, err := reencrypter.ReencryptFile(ctx, p.fs.PathJoin(p.path, sst.path), limiter)
+		if err != nil {
+			return files, size, errors.Wrapf(err, "re-encrypting %s", sst.path)
+		}
+		if n > 0 {
+			files++
+			size += n
+		}
+	}
+	return files, size, nil
 }
 
 // GetAuxiliaryDir implements the Engine interface.
@@ -1038,7 +1199,7 @@ func (p *Pebble) GetAuxiliaryDir() string {
 
 // NewBatch implements the Engine interface.
 func (p *Pebble) NewBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewIndexedBatch())
+	return newPebbleBatch(p.db, p.db.NewIndexedBatch(), &p.rangeTombstones)
 }
 
 // NewReadOnly implements the Engine interface.
@@ -1050,13 +1211,14 @@ func (p *Pebble) NewReadOnly() ReadWriter {
 
 // NewWriteOnlyBatch implements the Engine interface.
 func (p *Pebble) NewWriteOnlyBatch() Batch {
-	return newPebbleBatch(p.db, p.db.NewBatch())
+	return newPebbleBatch(p.db, p.db.NewBatch(), &p.rangeTombstones)
 }
 
 // NewSnapshot implements the Engine interface.
 func (p *Pebble) NewSnapshot() Reader {
 	return &pebbleSnapshot{
-		snapshot: p.db.NewSnapshot(),
+		snapshot:        p.db.NewSnapshot(),
+		rangeTombstones: &p.rangeTombstones,
 	}
 }
 
@@ -1067,7 +1229,13 @@ func (p *Pebble) Type() enginepb.EngineType {
 
 // IngestExternalFiles implements the Engine interface.
 func (p *Pebble) IngestExternalFiles(ctx context.Context, paths []string) error {
-	return p.db.Ingest(paths)
+	if err := p.db.Ingest(paths); err != nil {
+		return err
+	}
+	// Snapshots may carry range tombstones. The replicas they are ingested
+	// for don't serve reads until the ingestion returns, so it is enough to
+	// look for them afterwards.
+	return p.probeMVCCRangeTombstones()
 }
 
 // PreIngestDelay implements the Engine interface.
@@ -1216,6 +1384,10 @@ type pebbleReadOnly struct {
 
 var _ ReadWriter = &pebbleReadOnly{}
 
+func (p *pebbleReadOnly) mayContainMVCCRangeTombstones() bool {
+	return p.parent.mayContainMVCCRangeTombstones()
+}
+
 func (p *pebbleReadOnly) Close() {
 	if p.closed {
 		panic("closing an already-closed pebbleReadOnly")
@@ -1419,12 +1591,17 @@ func (p *pebbleReadOnly) LogLogicalOp(op MVCCLogicalOpType, details MVCCLogicalO
 
 // pebbleSnapshot represents a snapshot created using Pebble.NewSnapshot().
 type pebbleSnapshot struct {
-	snapshot *pebble.Snapshot
-	closed   bool
+	snapshot        *pebble.Snapshot
+	rangeTombstones *mvccRangeTombstonesFlag
+	closed          bool
 }
 
 var _ Reader = &pebbleSnapshot{}
 
+func (p *pebbleSnapshot) mayContainMVCCRangeTombstones() bool {
+	return p.rangeTombstones.isSet()
+}
+
 // Close implements the Reader interface.
 func (p *pebbleSnapshot) Close() {
 	_ = p.snapshot.Close()
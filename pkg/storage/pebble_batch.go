@@ -41,6 +41,11 @@ type pebbleBatch struct {
 	isDistinct       bool
 	distinctOpen     bool
 	parentBatch      *pebbleBatch
+	// rangeTombstones is the flag of the engine, and wroteRangeTombstones
+	// whether the batch itself wrote range tombstones. See
+	// mvccRangeTombstonesFlag.
+	rangeTombstones      *mvccRangeTombstonesFlag
+	wroteRangeTombstones bool
 
 	useWrappedIntentWriter bool
 	wrappedIntentWriter    intentDemuxWriter
@@ -57,12 +62,15 @@ var pebbleBatchPool = sync.Pool{
 }
 
 // Instantiates a new pebbleBatch.
-func newPebbleBatch(db *pebble.DB, batch *pebble.Batch) *pebbleBatch {
+func newPebbleBatch(
+	db *pebble.DB, batch *pebble.Batch, rangeTombstones *mvccRangeTombstonesFlag,
+) *pebbleBatch {
 	pb := pebbleBatchPool.Get().(*pebbleBatch)
 	*pb = pebbleBatch{
-		db:    db,
-		batch: batch,
-		buf:   pb.buf,
+		db:              db,
+		batch:           batch,
+		rangeTombstones: rangeTombstones,
+		buf:             pb.buf,
 		prefixIter: pebbleIterator{
 			lowerBoundBuf: pb.prefixIter.lowerBoundBuf,
 			upperBoundBuf: pb.prefixIter.upperBoundBuf,
@@ -272,9 +280,31 @@ func (p *pebbleBatch) ApplyBatchRepr(repr []byte, sync bool) error {
 		return err
 	}
 
+	if !p.mayContainMVCCRangeTombstones() {
+		found, err := batchReprHasMVCCRangeTombstones(repr)
+		if err != nil {
+			return err
+		}
+		if found {
+			p.markWroteRangeTombstones()
+		}
+	}
 	return p.batch.Apply(&batch, nil)
 }
 
+func (p *pebbleBatch) mayContainMVCCRangeTombstones() bool {
+	return p.wroteRangeTombstones || p.rangeTombstones.isSet()
+}
+
+// markWroteRangeTombstones records that the batch wrote range tombstones. A
+// distinct batch writes to the batch of its parent, so the parent is marked as
+// well.
+func (p *pebbleBatch) markWroteRangeTombstones() {
+	for b := p; b != nil; b = b.parentBatch {
+		b.wroteRangeTombstones = true
+	}
+}
+
 // ClearMVCC implements the Batch interface.
 func (p *pebbleBatch) ClearMVCC(key MVCCKey) error {
 	if key.Timestamp.IsEmpty() {
@@ -452,6 +482,9 @@ func (p *pebbleBatch) PutEngineKey(key EngineKey, value []byte) error {
 	}
 
 	p.buf = key.EncodeToBuf(p.buf[:0])
+	if isMVCCRangeTombstoneKey(p.buf) {
+		p.markWroteRangeTombstones()
+	}
 	return p.batch.Set(p.buf, value, nil)
 }
 
@@ -464,6 +497,9 @@ func (p *pebbleBatch) put(key MVCCKey, value []byte) error {
 	}
 
 	p.buf = EncodeKeyToBuf(p.buf[:0], key)
+	if isMVCCRangeTombstoneKey(p.buf) {
+		p.markWroteRangeTombstones()
+	}
 	return p.batch.Set(p.buf, value, nil)
 }
 
@@ -485,6 +521,11 @@ func (p *pebbleBatch) Commit(sync bool) error {
 	if p.batch == nil {
 		panic("called with nil batch")
 	}
+	// The flag is set before committing, so that readers never miss the range
+	// tombstones of the batch.
+	if p.wroteRangeTombstones {
+		p.rangeTombstones.markSet()
+	}
 	err := p.batch.Commit(opts)
 	if err != nil {
 		panic(err)
@@ -508,9 +549,10 @@ func (p *pebbleBatch) Distinct() ReadWriter {
 	// optimization. In Pebble we're still using the same underlying batch and if
 	// it is indexed we'll still be indexing it as we Go.
 	p.distinctOpen = true
-	d := newPebbleBatch(p.db, p.batch)
+	d := newPebbleBatch(p.db, p.batch, p.rangeTombstones)
 	d.parentBatch = p
 	d.isDistinct = true
+	d.wroteRangeTombstones = p.wroteRangeTombstones
 	return d
 }
 
@@ -116,6 +116,7 @@ type pebbleMVCCScanner struct {
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
 	checkUncertainty         bool
+	skipLocked               bool
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
@@ -127,11 +128,18 @@ type pebbleMVCCScanner struct {
 	curValue  []byte
 	results   pebbleResults
 	intents   pebble.Batch
+	// MVCC range tombstones covering the scanned span, and a buffer for the
+	// keys of the deletions synthesized from them.
+	rangeTombstones      MVCCRangeTombstones
+	rangeTombstoneKeyBuf []byte
 	// mostRecentTS stores the largest timestamp observed that is equal to or
 	// above the scan timestamp. Only applicable if failOnMoreRecent is true. If
 	// set and no other error is hit, a WriteToOld error will be returned from
 	// the scan.
 	mostRecentTS hlc.Timestamp
+	// lockTable is consulted by skipLocked scans to determine whether keys are
+	// locked by conflicting transactions in the in-memory lock table.
+	lockTable LockTableView
 	// Stores any error returned. If non-nil, iteration short circuits.
 	err error
 	// Number of iterations to try before we do a Seek/SeekReverse. Stays within
@@ -285,6 +293,15 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance() bool {
+	if p.skipLocked && p.lockTable != nil {
+		if locked, _ := p.lockTable.IsKeyLockedByConflictingTxn(p.curKey.Key); locked {
+			// 0. The key is locked by a conflicting transaction in the lock table
+			// and we're skipping locked keys. Move on to the next key without
+			// returning the current one.
+			return p.advanceKey()
+		}
+	}
+
 	if !p.curKey.Timestamp.IsEmpty() {
 		// ts < read_ts
 		if p.curKey.Timestamp.Less(p.ts) {
@@ -424,6 +441,12 @@ func (p *pebbleMVCCScanner) getAndAdvance() bool {
 		// Note that this will trigger an error higher up the stack. We
 		// continue scanning so that we can return all of the intents
 		// in the scan range.
+		//
+		// If we're skipping locked keys, the intent is not recorded and
+		// the key is omitted from the results instead.
+		if p.skipLocked {
+			return p.advanceKey()
+		}
 		p.err = p.intents.Set(p.curRawKey, p.curValue, nil)
 		if p.err != nil {
 			return false
@@ -602,6 +625,19 @@ func (p *pebbleMVCCScanner) advanceKeyAtNewKey(key []byte) bool {
 // p.tombstones is true. Advances to the next key unless we've reached the max
 // results limit.
 func (p *pebbleMVCCScanner) addAndAdvance(rawKey []byte, val []byte) bool {
+	if len(p.rangeTombstones) > 0 && !p.curKey.Timestamp.IsEmpty() {
+		deletedAt, ok := p.checkRangeTombstones(val)
+		if !ok {
+			return false
+		}
+		if !deletedAt.IsEmpty() {
+			// The version is shadowed by a range tombstone, so present it as a
+			// deletion tombstone at the range tombstone's timestamp.
+			p.rangeTombstoneKeyBuf = EncodeKeyToBuf(p.rangeTombstoneKeyBuf[:0],
+				MVCCKey{Key: p.curKey.Key, Timestamp: deletedAt})
+			rawKey, val = p.rangeTombstoneKeyBuf, nil
+		}
+	}
 	// Don't include deleted versions len(val) == 0, unless we've been instructed
 	// to include tombstones in the results.
 	if len(val) > 0 || p.tombstones {
@@ -621,6 +657,42 @@ func (p *pebbleMVCCScanner) addAndAdvance(rawKey []byte, val []byte) bool {
 	return p.advanceKey()
 }
 
+// checkRangeTombstones looks at the range tombstones covering the current key,
+// whose visible version is about to be added to the result set with the given
+// value. It returns the timestamp of the newest range tombstone visible to the
+// scan that shadows the version, if any. Range tombstones above the scan
+// timestamp are treated like any other newer write: they are recorded in
+// mostRecentTS if failOnMoreRecent is set, and result in an uncertainty error
+// if they delete a live value within the uncertainty interval. Returns false
+// if an error was recorded.
+func (p *pebbleMVCCScanner) checkRangeTombstones(val []byte) (deletedAt hlc.Timestamp, ok bool) {
+	var uncertainTS hlc.Timestamp
+	for _, t := range p.rangeTombstones.Covering(p.curKey.Key) {
+		if !p.curKey.Timestamp.Less(t.Timestamp) {
+			// Range tombstones are in descending timestamp order, so none of the
+			// remaining ones shadow the version.
+			break
+		}
+		if p.ts.Less(t.Timestamp) {
+			if p.failOnMoreRecent {
+				p.mostRecentTS.Forward(t.Timestamp)
+			} else if p.checkUncertainty && t.Timestamp.LessEq(p.txn.MaxTimestamp) {
+				uncertainTS = t.Timestamp
+			}
+			continue
+		}
+		if p.failOnMoreRecent && t.Timestamp.EqOrdering(p.ts) {
+			p.mostRecentTS.Forward(t.Timestamp)
+		}
+		deletedAt = t.Timestamp
+		break
+	}
+	if !uncertainTS.IsEmpty() && deletedAt.IsEmpty() && len(val) > 0 {
+		return hlc.Timestamp{}, p.uncertaintyError(uncertainTS)
+	}
+	return deletedAt, true
+}
+
 // Seeks to the latest revision of the current key that's still less than or
 // equal to the specified timestamp, adds it to the result set, then moves onto
 // the next user key.
@@ -0,0 +1,268 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// PointSynthesizingIter wraps an MVCCIterator and synthesizes point deletion
+// tombstones for MVCC range tombstones. For every key that the wrapped
+// iterator visits and that is covered by range tombstones, a version with an
+// empty value is emitted at the timestamp of each covering range tombstone,
+// interleaved with the key's point versions in the usual descending timestamp
+// order. If a point version and a range tombstone have the same timestamp, only
+// the point version is emitted.
+//
+// This allows consumers that only understand point keys, such as incremental
+// exports and rangefeed catch-up scans, to observe the deletions performed by
+// range tombstones as if they had been written one key at a time. Keys without
+// any point versions are not covered by range tombstones in any observable
+// way, so no keys are synthesized for them.
+//
+// The iterator only supports forward iteration. The range tombstones are read
+// lazily on the first seek, from the seek key up to the given end key.
+type PointSynthesizingIter struct {
+	reader Reader
+	iter   MVCCIterator
+	end    roachpb.Key
+
+	// tombstones holds the range tombstones overlapping [tombstonesStart, end).
+	tombstones      MVCCRangeTombstones
+	tombstonesStart roachpb.Key
+	tombstonesRead  bool
+
+	// curKey is the key the iterator is currently positioned on, versions the
+	// range tombstones covering it, and versionIdx the next one to emit.
+	curKey     roachpb.Key
+	versions   MVCCRangeTombstones
+	versionIdx int
+	// atSynthetic is true if the iterator is positioned on the synthetic
+	// version versions[versionIdx] rather than on the wrapped iterator.
+	atSynthetic bool
+	// iterAtCurKey is true if the wrapped iterator is valid and positioned on
+	// curKey.
+	iterAtCurKey bool
+
+	err   error
+	valid bool
+}
+
+var _ SimpleMVCCIterator = &PointSynthesizingIter{}
+
+// NewPointSynthesizingIter creates a new PointSynthesizingIter wrapping the
+// given iterator, which must have been created from the given reader. The end
+// key bounds the range tombstones that are read, and should match the upper
+// bound of the wrapped iterator. A nil end key reads all range tombstones
+// after the seek key.
+func NewPointSynthesizingIter(
+	reader Reader, iter MVCCIterator, end roachpb.Key,
+) *PointSynthesizingIter {
+	if end == nil {
+		end = keys.MaxKey
+	}
+	return &PointSynthesizingIter{
+		reader: reader,
+		iter:   iter,
+		end:    end,
+	}
+}
+
+// Close closes the wrapped iterator.
+func (i *PointSynthesizingIter) Close() {
+	i.iter.Close()
+}
+
+// SeekGE positions the iterator at the first version, point or synthetic, at
+// or after the given key.
+func (i *PointSynthesizingIter) SeekGE(key MVCCKey) {
+	i.maybeReadTombstones(key.Key)
+	if i.err != nil {
+		i.valid = false
+		return
+	}
+	i.iter.SeekGE(key)
+	if !i.updateKey() {
+		return
+	}
+	if !key.Timestamp.IsEmpty() && i.curKey.Equal(key.Key) {
+		// Skip synthetic versions above the seek timestamp.
+		for i.versionIdx < len(i.versions) && key.Timestamp.Less(i.versions[i.versionIdx].Timestamp) {
+			i.versionIdx++
+		}
+	}
+	i.position()
+}
+
+// Next advances the iterator to the next version, point or synthetic.
+func (i *PointSynthesizingIter) Next() {
+	if i.atSynthetic {
+		i.versionIdx++
+	} else {
+		i.iter.Next()
+		if !i.checkIterAtCurKey() {
+			return
+		}
+	}
+	i.position()
+}
+
+// NextKey advances the iterator to the first version of the next key.
+func (i *PointSynthesizingIter) NextKey() {
+	if i.iterAtCurKey {
+		i.iter.NextKey()
+	}
+	if !i.updateKey() {
+		return
+	}
+	i.position()
+}
+
+// Valid implements the SimpleMVCCIterator interface.
+func (i *PointSynthesizingIter) Valid() (bool, error) {
+	return i.valid, i.err
+}
+
+// UnsafeKey implements the SimpleMVCCIterator interface.
+func (i *PointSynthesizingIter) UnsafeKey() MVCCKey {
+	if i.atSynthetic {
+		return MVCCKey{Key: i.curKey, Timestamp: i.versions[i.versionIdx].Timestamp}
+	}
+	return i.iter.UnsafeKey()
+}
+
+// UnsafeValue implements the SimpleMVCCIterator interface. Synthetic versions
+// have an empty value, i.e. they are deletion tombstones.
+func (i *PointSynthesizingIter) UnsafeValue() []byte {
+	if i.atSynthetic {
+		return nil
+	}
+	return i.iter.UnsafeValue()
+}
+
+// Key returns a copy of the current key.
+func (i *PointSynthesizingIter) Key() MVCCKey {
+	if i.atSynthetic {
+		key := i.UnsafeKey()
+		key.Key = append(roachpb.Key(nil), key.Key...)
+		return key
+	}
+	return i.iter.Key()
+}
+
+// Value returns a copy of the current value.
+func (i *PointSynthesizingIter) Value() []byte {
+	if i.atSynthetic {
+		return nil
+	}
+	return i.iter.Value()
+}
+
+// maybeReadTombstones reads the range tombstones from the given key onwards,
+// unless they have already been read from an earlier key.
+func (i *PointSynthesizingIter) maybeReadTombstones(key roachpb.Key) {
+	if i.tombstonesRead && key.Compare(i.tombstonesStart) >= 0 {
+		return
+	}
+	i.tombstones, i.err = ScanMVCCRangeTombstones(i.reader, key, i.end)
+	i.tombstonesStart = append(i.tombstonesStart[:0], key...)
+	i.tombstonesRead = i.err == nil
+}
+
+// updateKey moves the iterator to the key the wrapped iterator is positioned
+// on. It returns false if the iterator is exhausted or an error occurred.
+func (i *PointSynthesizingIter) updateKey() bool {
+	i.atSynthetic = false
+	if ok, err := i.iter.Valid(); !ok {
+		i.iterAtCurKey = false
+		i.err, i.valid = err, false
+		return false
+	}
+	i.curKey = append(i.curKey[:0], i.iter.UnsafeKey().Key...)
+	i.versions = i.tombstones.Covering(i.curKey)
+	i.versionIdx = 0
+	i.iterAtCurKey = true
+	return true
+}
+
+// checkIterAtCurKey updates iterAtCurKey after the wrapped iterator was moved.
+// It returns false if the wrapped iterator returned an error.
+func (i *PointSynthesizingIter) checkIterAtCurKey() bool {
+	ok, err := i.iter.Valid()
+	if err != nil {
+		i.err, i.valid = err, false
+		return false
+	}
+	i.iterAtCurKey = ok && i.iter.UnsafeKey().Key.Equal(i.curKey)
+	return true
+}
+
+// position decides whether the iterator is positioned on the wrapped iterator
+// or on a synthetic version of the current key, moving on to the next key once
+// both are exhausted for the current one.
+func (i *PointSynthesizingIter) position() {
+	for {
+		i.valid = true
+		if i.iterAtCurKey {
+			ts := i.iter.UnsafeKey().Timestamp
+			if ts.IsEmpty() {
+				// Intents and inline values sort before all versions.
+				i.atSynthetic = false
+				return
+			}
+			if i.versionIdx < len(i.versions) && ts.Less(i.versions[i.versionIdx].Timestamp) {
+				i.atSynthetic = true
+				return
+			}
+			if i.versionIdx < len(i.versions) && ts.Equal(i.versions[i.versionIdx].Timestamp) {
+				// The point version wins over a synthetic one at the same timestamp.
+				i.versionIdx++
+			}
+			i.atSynthetic = false
+			return
+		}
+		if i.versionIdx < len(i.versions) {
+			i.atSynthetic = true
+			return
+		}
+		// The current key is exhausted, move on to the one the wrapped iterator
+		// is positioned on.
+		if !i.updateKey() {
+			return
+		}
+	}
+}
+
+// nextTombstoneInTimeRange returns the first key in [from, to) covered by a
+// range tombstone with a timestamp in (startTime, endTime], or nil if there is
+// no such key. It is used to prevent time-bound iteration from skipping over
+// keys whose only changes in the time range were made by range tombstones.
+func (i *PointSynthesizingIter) nextTombstoneInTimeRange(
+	from, to roachpb.Key, startTime, endTime hlc.Timestamp,
+) roachpb.Key {
+	for _, t := range i.tombstones {
+		if t.EndKey.Compare(from) <= 0 {
+			continue
+		}
+		if t.StartKey.Compare(to) >= 0 {
+			break
+		}
+		if startTime.Less(t.Timestamp) && t.Timestamp.LessEq(endTime) {
+			if t.StartKey.Compare(from) < 0 {
+				return from
+			}
+			return t.StartKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+// engineWithRaftEngine is an Engine that is paired with a dedicated engine
+// holding the store's raft log entries and HardStates. All Engine methods
+// operate on the primary (state machine) engine; the raft engine is only
+// reachable through RaftEngine.
+type engineWithRaftEngine struct {
+	Engine
+	raftEng Engine
+}
+
+// WithRaftEngine pairs eng with raftEng, a separate engine that is to hold
+// the raft log. The returned Engine owns both engines and closes them both
+// when closed.
+func WithRaftEngine(eng, raftEng Engine) Engine {
+	return &engineWithRaftEngine{Engine: eng, raftEng: raftEng}
+}
+
+// Close implements the Engine interface.
+func (e *engineWithRaftEngine) Close() {
+	e.Engine.Close()
+	e.raftEng.Close()
+}
+
+// RaftEngine returns the engine holding the raft log for the store backed by
+// eng. This is eng itself unless eng was constructed using WithRaftEngine.
+func RaftEngine(eng Engine) Engine {
+	if e, ok := eng.(*engineWithRaftEngine); ok {
+		return e.raftEng
+	}
+	return eng
+}
@@ -245,6 +245,8 @@ func toMessage(typ string) interface{} {
 		return &pgproto3.ErrorResponse{}
 	case "Execute":
 		return &pgproto3.Execute{}
+	case "NotificationResponse":
+		return &pgproto3.NotificationResponse{}
 	case "Parse":
 		return &pgproto3.Parse{}
 	case "PortalSuspended":
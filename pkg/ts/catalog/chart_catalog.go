@@ -185,6 +185,72 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Process, "Admission Control", "KV"}},
+		Charts: []chartDescription{
+			{
+				Title: "Requests",
+				Metrics: []string{
+					"admission.requested.kv",
+					"admission.admitted.kv",
+					"admission.errored.kv",
+				},
+			},
+			{
+				Title:   "Wait Time",
+				Metrics: []string{"admission.wait_sum.kv"},
+			},
+			{
+				Title:   "Waiting Requests",
+				Metrics: []string{"admission.wait_queue_length.kv"},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Process, "Admission Control", "SQL Leaf Start"}},
+		Charts: []chartDescription{
+			{
+				Title: "Requests",
+				Metrics: []string{
+					"admission.requested.sql_leaf_start",
+					"admission.admitted.sql_leaf_start",
+					"admission.errored.sql_leaf_start",
+				},
+			},
+			{
+				Title:   "Wait Time",
+				Metrics: []string{"admission.wait_sum.sql_leaf_start"},
+			},
+			{
+				Title:   "Waiting Requests",
+				Metrics: []string{"admission.wait_queue_length.sql_leaf_start"},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Process, "Admission Control", "Granter"}},
+		Charts: []chartDescription{
+			{
+				Title: "KV Slots",
+				Metrics: []string{
+					"admission.granter.total_slots.kv",
+					"admission.granter.used_slots.kv",
+				},
+			},
+			{
+				Title:   "KV IO Tokens",
+				Metrics: []string{"admission.granter.io_tokens_available.kv"},
+			},
+			{
+				Title:   "SQL Leaf Start Slots",
+				Metrics: []string{"admission.granter.used_slots.sql_leaf_start"},
+			},
+			{
+				Title:   "Runnable Goroutines per CPU",
+				Metrics: []string{"admission.granter.runnable_goroutines_per_cpu"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Process, "Server", "Overview"}},
 		Charts: []chartDescription{
@@ -489,6 +555,19 @@ var charts = []sectionDescription{
 					"kv.rangefeed.catchup_scan_nanos",
 				},
 			},
+			{
+				Title: "Rangefeed Catchup Scan Bytes",
+				Metrics: []string{
+					"kv.rangefeed.catchup_scan_bytes",
+				},
+			},
+			{
+				Title: "Rangefeed Catchup Scans",
+				Metrics: []string{
+					"kv.rangefeed.catchup_scans_waiting",
+					"kv.rangefeed.catchup_scans_in_flight",
+				},
+			},
 			{
 				Title: "Snapshots",
 				Metrics: []string{
@@ -946,6 +1025,10 @@ var charts = []sectionDescription{
 					"txn.parallelcommits",
 				},
 			},
+			{
+				Title:   "Commit Waits",
+				Metrics: []string{"txn.commit_waits"},
+			},
 			{
 				Title:   "Durations",
 				Metrics: []string{"txn.durations"},
@@ -1402,6 +1485,19 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{ReplicationLayer, "Replica Circuit Breaker"}},
+		Charts: []chartDescription{
+			{
+				Title:   "Tripped Replicas",
+				Metrics: []string{"kv.replica_circuit_breaker.num_tripped_replicas"},
+			},
+			{
+				Title:   "Tripped Events",
+				Metrics: []string{"kv.replica_circuit_breaker.num_tripped_events"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{ReplicationLayer, "Replica GC Queue"}},
 		Charts: []chartDescription{
@@ -2065,6 +2161,18 @@ var charts = []sectionDescription{
 				Title:   "Algorithm Enum",
 				Metrics: []string{"rocksdb.encryption.algorithm"},
 			},
+			{
+				Title:   "Retired Key Bytes",
+				Metrics: []string{"rocksdb.encryption.retired-key-bytes"},
+			},
+			{
+				Title:   "Re-encrypted Bytes",
+				Metrics: []string{"rocksdb.encryption.reencrypted-bytes"},
+			},
+			{
+				Title:   "Re-encrypted SSTables",
+				Metrics: []string{"rocksdb.encryption.reencrypted-files"},
+			},
 		},
 	},
 	{
@@ -2283,6 +2391,7 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"jobs.auto_create_stats.currently_running",
 					"jobs.backup.currently_running",
+					"jobs.backup_compaction.currently_running",
 					"jobs.changefeed.currently_running",
 					"jobs.create_stats.currently_running",
 					"jobs.import.currently_running",
@@ -2316,6 +2425,18 @@ var charts = []sectionDescription{
 				},
 				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
 			},
+			{
+				Title: "Backup Compaction",
+				Metrics: []string{
+					"jobs.backup_compaction.fail_or_cancel_completed",
+					"jobs.backup_compaction.fail_or_cancel_failed",
+					"jobs.backup_compaction.fail_or_cancel_retry_error",
+					"jobs.backup_compaction.resume_completed",
+					"jobs.backup_compaction.resume_failed",
+					"jobs.backup_compaction.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 			{
 				Title: "Changefeed",
 				Metrics: []string{
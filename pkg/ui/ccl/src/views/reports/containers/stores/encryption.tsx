@@ -100,6 +100,21 @@ export default class EncryptionStatus {
     ];
   }
 
+  renderKeyUsage(stats: protos.cockroach.server.serverpb.IStoreDetails) {
+    if (_.isEmpty(stats.data_key_usage)) {
+      return null;
+    }
+
+    return [
+      this.renderHeaderRow("Keys in Use: files and bytes encrypted using each key"),
+      ..._.map(stats.data_key_usage, (usage) => {
+        const files = FixLong(usage.files);
+        const bytes = FixLong(usage.bytes);
+        return this.renderSimpleRow(usage.key_id, files + " files, " + Bytes(bytes.toNumber()));
+      }),
+    ];
+  }
+
   getEncryptionRows() {
     const { store } = this.props;
     const rawStatus = store.encryption_status;
@@ -120,6 +135,7 @@ export default class EncryptionStatus {
       this.renderStoreKey(decodedStatus.active_store_key),
       this.renderDataKey(decodedStatus.active_data_key),
       this.renderFileStats(store),
+      this.renderKeyUsage(store),
     ];
   }
 }
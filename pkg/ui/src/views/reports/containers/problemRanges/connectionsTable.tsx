@@ -60,6 +60,10 @@ const connectionTableColumns: ConnectionTableColumn[] = [
     title: "Raft log too large",
     extract: (problem) => problem.raft_log_too_large_range_ids.length,
   },
+  {
+    title: "Circuit breaker error",
+    extract: (problem) => problem.circuit_breaker_error_range_ids.length,
+  },
   {
     title: "Total",
     extract: (problem) => {
@@ -70,7 +74,8 @@ const connectionTableColumns: ConnectionTableColumn[] = [
         problem.underreplicated_range_ids.length +
         problem.overreplicated_range_ids.length +
         problem.quiescent_equals_ticking_range_ids.length +
-        problem.raft_log_too_large_range_ids.length;
+        problem.raft_log_too_large_range_ids.length +
+        problem.circuit_breaker_error_range_ids.length;
     },
   },
   { title: "Error", extract: (problem) => problem.error_message },
@@ -262,6 +262,9 @@ export default class RangeTable extends React.Component<RangeTableProps, {}> {
     if (problems.raft_log_too_large) {
       results = _.concat(results, "Raft log too large");
     }
+    if (problems.circuit_breaker_error) {
+      results = _.concat(results, "Circuit breaker error");
+    }
     if (awaitingGC) {
       results = _.concat(results, "Awaiting GC");
     }
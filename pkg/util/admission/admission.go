@@ -0,0 +1,179 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package admission implements node-level admission control, which protects a
+// node from overload by limiting the work it accepts based on its resource
+// use, rather than on static concurrency limits.
+//
+// Work of each WorkKind waits in a WorkQueue before it is performed. Within a
+// WorkQueue, waiting work is admitted in tenant fair-share order: the tenant
+// with the least admitted work that is still running goes first. Within a
+// tenant, work is ordered by priority and then by creation time, so that
+// older transactions get to finish first.
+//
+// A WorkQueue is paired with a granter, which decides when work can be
+// admitted. All the granters of a node are coordinated by a GrantCoordinator,
+// which hands out the capacity of the node in the order of the WorkKinds, so
+// that new DistSQL flows don't start while KV work is queued. The capacity is
+// measured in slots, i.e. the number of admitted pieces of work that are still
+// running. The number of KV slots is adjusted based on the number of runnable
+// goroutines per processor, which signals CPU overload (see goschedstats).
+// When the read amplification of a store's LSM exceeds a threshold, the rate
+// at which KV work is admitted is additionally limited by tokens, giving
+// compactions a chance to catch up.
+package admission
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// KVAdmissionControlEnabled controls whether KV work is subject to admission
+// control.
+var KVAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.kv.enabled",
+	"when true, work performed by the KV layer is subject to admission control",
+	true,
+)
+
+// SQLLeafStartAdmissionControlEnabled controls whether the setup of DistSQL
+// flows on behalf of remote gateways is subject to admission control.
+var SQLLeafStartAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.sql_leaf_start.enabled",
+	"when true, the setup of DistSQL flows on behalf of other nodes is subject to admission control",
+	true,
+)
+
+// KVSlotAdjusterOverloadThreshold is the number of runnable goroutines per
+// processor above which the node is considered to be CPU overloaded.
+var KVSlotAdjusterOverloadThreshold = settings.RegisterIntSetting(
+	"admission.kv_slot_adjuster.overload_threshold",
+	"when the number of runnable goroutines per CPU exceeds this value, the "+
+		"number of concurrently admitted KV requests is reduced",
+	32,
+	settings.PositiveInt,
+)
+
+// ReadAmplificationOverloadThreshold is the read amplification of a store's
+// LSM above which the store is considered to be overloaded.
+var ReadAmplificationOverloadThreshold = settings.RegisterIntSetting(
+	"admission.read_amplification_overload_threshold",
+	"when the read amplification of a store's LSM exceeds this value, the "+
+		"rate at which KV requests are admitted is limited",
+	20,
+	settings.PositiveInt,
+)
+
+// WorkPriority represents the priority of work. In a WorkQueue, it is only
+// used for ordering within a tenant. High priority work can starve lower
+// priority work.
+type WorkPriority int8
+
+const (
+	// LowPri is low priority work.
+	LowPri WorkPriority = math.MinInt8
+	// NormalPri is normal priority work.
+	NormalPri WorkPriority = 0
+	// HighPri is high priority work.
+	HighPri WorkPriority = math.MaxInt8
+)
+
+// WorkKind represents the kinds of work that are subject to admission
+// control. The kinds are ordered by importance: capacity that frees up is
+// offered to waiting work of a more important kind first, and work of a less
+// important kind isn't admitted while more important work is waiting.
+type WorkKind int8
+
+const (
+	// KVWork represents the batches of requests evaluated by the KV layer.
+	KVWork WorkKind = iota
+	// SQLStatementLeafStartWork represents the setup of DistSQL flows on
+	// behalf of a remote gateway. Starting a flow is what generates further KV
+	// work, so it is the first kind of work to be held back under overload.
+	SQLStatementLeafStartWork
+	numWorkKinds
+)
+
+func (wk WorkKind) String() string {
+	switch wk {
+	case KVWork:
+		return "kv"
+	case SQLStatementLeafStartWork:
+		return "sql-leaf-start"
+	default:
+		return "unknown"
+	}
+}
+
+// metricName returns the name used for the WorkKind in metric names.
+func (wk WorkKind) metricName() string {
+	switch wk {
+	case KVWork:
+		return "kv"
+	case SQLStatementLeafStartWork:
+		return "sql_leaf_start"
+	default:
+		return "unknown"
+	}
+}
+
+// WorkInfo provides information that is used to order work within a
+// WorkQueue.
+type WorkInfo struct {
+	// TenantID is the ID of the tenant on whose behalf the work is performed.
+	TenantID roachpb.TenantID
+	// Priority orders work within a tenant.
+	Priority WorkPriority
+	// CreateTime, in nanoseconds since the epoch, orders work of the same
+	// tenant and priority, with older work admitted first. For work performed
+	// on behalf of a transaction, using the transaction's start time lets
+	// older transactions finish first, which reduces the number of aborts
+	// under overload.
+	CreateTime int64
+	// BypassAdmission is set for work that must not queue, such as node
+	// liveness heartbeats: if it did, overload would cause the node to lose
+	// its liveness, which only adds to the overload of the cluster. Such work
+	// is admitted right away, but still counts against the node's capacity.
+	BypassAdmission bool
+}
+
+// granter is paired with a requester, to which it grants capacity. A
+// granter is only used by the requester it is paired with.
+type granter interface {
+	// tryGet tries to acquire a slot for work that would otherwise have to
+	// wait, and returns true if successful. It must only be called if the
+	// requester has no waiting work.
+	tryGet() bool
+	// returnGrant returns a slot that was acquired through tryGet, granted to
+	// the requester, or taken without permission.
+	returnGrant()
+	// tookWithoutPermission informs the granter that a slot was taken without
+	// asking for permission, i.e. by work that bypasses admission control.
+	tookWithoutPermission()
+	// tryGrant asks the granter to grant capacity to the requester's waiting
+	// work, if it has any. It is called by the requester after it enqueued
+	// work, since capacity may have been returned between a failed tryGet
+	// call and the work being enqueued.
+	tryGrant()
+}
+
+// requester is an interface implemented by an object that orders admission
+// work for a particular WorkKind, i.e. a WorkQueue.
+type requester interface {
+	// hasWaitingRequests returns whether there is any work waiting to be
+	// admitted.
+	hasWaitingRequests() bool
+	// granted is called by the granter when a slot is available for waiting
+	// work. It returns false if there was no waiting work to admit, in which
+	// case the slot is not used.
+	granted() bool
+}
@@ -0,0 +1,401 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/goschedstats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+const (
+	// unlimited is used for slot and token counts that don't limit admission.
+	unlimited = math.MaxInt64
+
+	// minKVSlots is the minimum number of KV slots. At least one slot is
+	// always available, so that the node makes progress under overload.
+	minKVSlots = 1
+
+	// sqlLeafStartSlotsPerProc is the number of concurrent DistSQL flow setups
+	// admitted per processor. Flow setups are short, and the load they cause
+	// is controlled through the KV work they generate.
+	sqlLeafStartSlotsPerProc = 4
+
+	// ioTokenAdjustmentInterval is the interval at which the stores' LSM
+	// metrics are polled and KV work is handed out tokens if any store is
+	// overloaded.
+	ioTokenAdjustmentInterval = time.Second
+
+	// minIOTokens is the minimum number of KV requests admitted per
+	// ioTokenAdjustmentInterval while a store is overloaded.
+	minIOTokens = 10
+)
+
+// StoreMetrics are the metrics of a store that admission control uses to
+// detect overload.
+type StoreMetrics struct {
+	StoreID int32
+	// ReadAmplification is the read amplification of the store's LSM, i.e.
+	// the number of sorted runs a read may have to consult.
+	ReadAmplification int64
+}
+
+// StoreMetricsProvider provides the StoreMetrics of the stores of a node.
+type StoreMetricsProvider interface {
+	GetStoreMetrics() []StoreMetrics
+}
+
+// slotGranter is the granter paired with the WorkQueue of a WorkKind. It
+// delegates to the GrantCoordinator, which keeps track of the slots of all
+// WorkKinds under a single mutex.
+type slotGranter struct {
+	coord     *GrantCoordinator
+	workKind  WorkKind
+	requester requester
+
+	// The fields below are protected by coord.mu.
+
+	usedSlots  int64
+	totalSlots int64
+	// availableIOTokens is the number of pieces of work that can still be
+	// admitted in the current ioTokenAdjustmentInterval. It is unlimited
+	// unless the work is limited because a store is overloaded.
+	availableIOTokens int64
+	// admittedCount is the number of pieces of work admitted in the current
+	// ioTokenAdjustmentInterval.
+	admittedCount int64
+}
+
+var _ granter = &slotGranter{}
+
+func (sg *slotGranter) tryGet() bool {
+	return sg.coord.tryGet(sg.workKind)
+}
+
+func (sg *slotGranter) returnGrant() {
+	sg.coord.returnGrant(sg.workKind)
+}
+
+func (sg *slotGranter) tookWithoutPermission() {
+	sg.coord.tookWithoutPermission(sg.workKind)
+}
+
+func (sg *slotGranter) tryGrant() {
+	sg.coord.tryGrant()
+}
+
+// canGrantLocked returns whether the granter has capacity for more work.
+func (sg *slotGranter) canGrantLocked() bool {
+	return sg.usedSlots < sg.totalSlots && sg.availableIOTokens > 0
+}
+
+// tookLocked accounts for a piece of work being admitted.
+func (sg *slotGranter) tookLocked() {
+	sg.usedSlots++
+	sg.admittedCount++
+	if sg.availableIOTokens != unlimited {
+		sg.availableIOTokens--
+	}
+}
+
+// GrantCoordinator coordinates the granters of the WorkQueues of a node.
+// There is a single GrantCoordinator per node, which adjusts the capacity of
+// the node based on its CPU and IO load.
+type GrantCoordinator struct {
+	settings *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		granters [numWorkKinds]*slotGranter
+	}
+	queues  [numWorkKinds]*WorkQueue
+	metrics GranterMetrics
+}
+
+// NewGrantCoordinator creates a GrantCoordinator along with the WorkQueues of
+// all WorkKinds. It doesn't adjust to the load of the node until Start is
+// called.
+func NewGrantCoordinator(st *cluster.Settings) *GrantCoordinator {
+	coord := &GrantCoordinator{
+		settings: st,
+		metrics:  makeGranterMetrics(),
+	}
+	numProcs := int64(runtime.GOMAXPROCS(0))
+	// The number of KV slots starts out at one per processor, and is then
+	// adjusted based on the number of runnable goroutines. Without samples of
+	// the runnable goroutines, KV work is only limited by IO overload.
+	kvSlots := numProcs
+	if !goschedstats.Supported() {
+		kvSlots = unlimited
+	}
+	enabled := [numWorkKinds]*settings.BoolSetting{
+		KVWork:                    KVAdmissionControlEnabled,
+		SQLStatementLeafStartWork: SQLLeafStartAdmissionControlEnabled,
+	}
+	totalSlots := [numWorkKinds]int64{
+		KVWork:                    kvSlots,
+		SQLStatementLeafStartWork: numProcs * sqlLeafStartSlotsPerProc,
+	}
+	for kind := WorkKind(0); kind < numWorkKinds; kind++ {
+		sg := &slotGranter{
+			coord:             coord,
+			workKind:          kind,
+			totalSlots:        totalSlots[kind],
+			availableIOTokens: unlimited,
+		}
+		coord.queues[kind] = makeWorkQueue(kind, sg, st, enabled[kind])
+		sg.requester = coord.queues[kind]
+		coord.mu.granters[kind] = sg
+	}
+	coord.updateMetricsLocked()
+	return coord
+}
+
+// GetWorkQueue returns the WorkQueue for the given WorkKind.
+func (coord *GrantCoordinator) GetWorkQueue(workKind WorkKind) *WorkQueue {
+	return coord.queues[workKind]
+}
+
+// Start starts adjusting the capacity of the node to its load: the number of
+// KV slots follows the number of runnable goroutines, and KV work is limited
+// by tokens while any of the stores provided by the StoreMetricsProvider is
+// overloaded.
+func (coord *GrantCoordinator) Start(
+	ctx context.Context, stopper *stop.Stopper, storeMetrics StoreMetricsProvider,
+) error {
+	id := goschedstats.RegisterRunnableCountCallback(coord.cpuLoad)
+	stopper.AddCloser(stop.CloserFn(func() {
+		goschedstats.UnregisterRunnableCountCallback(id)
+	}))
+	return stopper.RunAsyncTask(ctx, "admission-io-load-listener", func(ctx context.Context) {
+		ticker := time.NewTicker(ioTokenAdjustmentInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				coord.ioLoad(ctx, storeMetrics.GetStoreMetrics())
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+func (coord *GrantCoordinator) tryGet(workKind WorkKind) bool {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	// Don't let work skip ahead of more important work that is waiting.
+	for kind := WorkKind(0); kind < workKind; kind++ {
+		if coord.queues[kind].hasWaitingRequests() {
+			return false
+		}
+	}
+	sg := coord.mu.granters[workKind]
+	if !sg.canGrantLocked() {
+		return false
+	}
+	sg.tookLocked()
+	coord.updateMetricsLocked()
+	return true
+}
+
+func (coord *GrantCoordinator) returnGrant(workKind WorkKind) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.mu.granters[workKind].usedSlots--
+	coord.tryGrantLocked()
+}
+
+func (coord *GrantCoordinator) tookWithoutPermission(workKind WorkKind) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.mu.granters[workKind].tookLocked()
+	coord.updateMetricsLocked()
+}
+
+func (coord *GrantCoordinator) tryGrant() {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.tryGrantLocked()
+}
+
+// tryGrantLocked grants capacity to waiting work in the order of the
+// WorkKinds. Work of a WorkKind is only granted capacity once no more
+// important work is waiting.
+func (coord *GrantCoordinator) tryGrantLocked() {
+	defer coord.updateMetricsLocked()
+	for kind := WorkKind(0); kind < numWorkKinds; kind++ {
+		sg := coord.mu.granters[kind]
+		for sg.canGrantLocked() {
+			if !sg.requester.granted() {
+				break
+			}
+			sg.tookLocked()
+		}
+		if sg.requester.hasWaitingRequests() {
+			return
+		}
+	}
+}
+
+// cpuLoad is called with every sample of the number of runnable goroutines,
+// and adjusts the number of KV slots: it is decreased while the node is CPU
+// overloaded, and increased while the slots, rather than the CPU, limit the
+// admission of KV work.
+func (coord *GrantCoordinator) cpuLoad(numRunnable int, numProcs int) {
+	threshold := int(KVSlotAdjusterOverloadThreshold.Get(&coord.settings.SV))
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	sg := coord.mu.granters[KVWork]
+	if numRunnable >= threshold*numProcs {
+		// Overload. Decrease the total slots unless they have bottomed out. If
+		// more slots are in use than there are in total, the previous decrease
+		// hasn't taken effect yet, so hold off on decreasing further.
+		if sg.usedSlots > 0 && sg.totalSlots > minKVSlots && sg.usedSlots <= sg.totalSlots {
+			sg.totalSlots--
+		}
+	} else if numRunnable <= threshold*numProcs/2 {
+		// Underload. If all the slots are in use, they are what is holding
+		// back work, so increase them additively. This also ensures progress
+		// if all the admitted work is blocked, e.g. waiting for locks held by
+		// waiting work.
+		if sg.usedSlots >= sg.totalSlots {
+			sg.totalSlots++
+		}
+	}
+	coord.metrics.RunnableGoroutinesPerCPU.Update(float64(numRunnable) / float64(numProcs))
+	coord.tryGrantLocked()
+}
+
+// ioLoad is called every ioTokenAdjustmentInterval with the metrics of the
+// node's stores. While the read amplification of any store exceeds the
+// threshold, the number of KV requests admitted per interval is limited to
+// half of the number admitted in the previous interval, which backs off
+// multiplicatively until compactions catch up.
+func (coord *GrantCoordinator) ioLoad(ctx context.Context, metrics []StoreMetrics) {
+	threshold := ReadAmplificationOverloadThreshold.Get(&coord.settings.SV)
+	var overloaded *StoreMetrics
+	for i := range metrics {
+		if metrics[i].ReadAmplification > threshold {
+			overloaded = &metrics[i]
+			break
+		}
+	}
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	sg := coord.mu.granters[KVWork]
+	if overloaded == nil {
+		if sg.availableIOTokens != unlimited {
+			log.Infof(ctx, "IO overload cleared, no longer limiting KV admission")
+		}
+		sg.availableIOTokens = unlimited
+	} else {
+		tokens := sg.admittedCount / 2
+		if tokens < minIOTokens {
+			tokens = minIOTokens
+		}
+		if sg.availableIOTokens == unlimited {
+			log.Infof(ctx, "IO overload on store s%d with read amplification %d, limiting KV admission",
+				overloaded.StoreID, overloaded.ReadAmplification)
+		}
+		sg.availableIOTokens = tokens
+	}
+	sg.admittedCount = 0
+	coord.tryGrantLocked()
+}
+
+func (coord *GrantCoordinator) updateMetricsLocked() {
+	sg := coord.mu.granters[KVWork]
+	coord.metrics.KVTotalSlots.Update(sg.totalSlots)
+	coord.metrics.KVUsedSlots.Update(sg.usedSlots)
+	ioTokens := sg.availableIOTokens
+	if ioTokens == unlimited {
+		ioTokens = -1
+	}
+	coord.metrics.KVIOTokensAvailable.Update(ioTokens)
+	coord.metrics.SQLLeafStartUsedSlots.Update(coord.mu.granters[SQLStatementLeafStartWork].usedSlots)
+}
+
+// MetricStructs returns the metric structs of the GrantCoordinator and its
+// WorkQueues, to be added to a metric registry.
+func (coord *GrantCoordinator) MetricStructs() []metric.Struct {
+	structs := []metric.Struct{coord.metrics}
+	for _, q := range coord.queues {
+		structs = append(structs, q.metrics)
+	}
+	return structs
+}
+
+// GranterMetrics are the metrics of a GrantCoordinator.
+type GranterMetrics struct {
+	KVTotalSlots             *metric.Gauge
+	KVUsedSlots              *metric.Gauge
+	KVIOTokensAvailable      *metric.Gauge
+	SQLLeafStartUsedSlots    *metric.Gauge
+	RunnableGoroutinesPerCPU *metric.GaugeFloat64
+}
+
+var _ metric.Struct = GranterMetrics{}
+
+// MetricStruct implements the metric.Struct interface.
+func (GranterMetrics) MetricStruct() {}
+
+var (
+	metaKVTotalSlots = metric.Metadata{
+		Name:        "admission.granter.total_slots.kv",
+		Help:        "Total slots for KV work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaKVUsedSlots = metric.Metadata{
+		Name:        "admission.granter.used_slots.kv",
+		Help:        "Used slots for KV work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaKVIOTokensAvailable = metric.Metadata{
+		Name:        "admission.granter.io_tokens_available.kv",
+		Help:        "Number of KV requests that can still be admitted in the current interval while a store is overloaded, or -1 if unlimited",
+		Measurement: "Tokens",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaSQLLeafStartUsedSlots = metric.Metadata{
+		Name:        "admission.granter.used_slots.sql_leaf_start",
+		Help:        "Used slots for the setup of DistSQL flows",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRunnableGoroutinesPerCPU = metric.Metadata{
+		Name:        "admission.granter.runnable_goroutines_per_cpu",
+		Help:        "Number of runnable goroutines per CPU in the last sample taken by admission control",
+		Measurement: "Goroutines",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+func makeGranterMetrics() GranterMetrics {
+	return GranterMetrics{
+		KVTotalSlots:             metric.NewGauge(metaKVTotalSlots),
+		KVUsedSlots:              metric.NewGauge(metaKVUsedSlots),
+		KVIOTokensAvailable:      metric.NewGauge(metaKVIOTokensAvailable),
+		SQLLeafStartUsedSlots:    metric.NewGauge(metaSQLLeafStartUsedSlots),
+		RunnableGoroutinesPerCPU: metric.NewGaugeFloat64(metaRunnableGoroutinesPerCPU),
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func (coord *GrantCoordinator) kvSlots() (used, total int64) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	sg := coord.mu.granters[KVWork]
+	return sg.usedSlots, sg.totalSlots
+}
+
+func (coord *GrantCoordinator) setKVSlots(total int64) {
+	coord.mu.Lock()
+	defer coord.mu.Unlock()
+	coord.mu.granters[KVWork].totalSlots = total
+}
+
+func TestGrantCoordinatorCPULoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	KVSlotAdjusterOverloadThreshold.Override(&st.SV, 10)
+	coord := NewGrantCoordinator(st)
+	coord.setKVSlots(2)
+	kvQ := coord.GetWorkQueue(KVWork)
+	tenant := roachpb.SystemTenantID
+
+	for i := 0; i < 2; i++ {
+		_, err := kvQ.Admit(ctx, WorkInfo{TenantID: tenant})
+		require.NoError(t, err)
+	}
+	used, total := coord.kvSlots()
+	require.Equal(t, int64(2), used)
+	require.Equal(t, int64(2), total)
+
+	// Work has to wait while all slots are in use.
+	ch := admitAsync(ctx, kvQ, WorkInfo{TenantID: tenant})
+	waitForQueueLength(t, kvQ, 1)
+
+	// Under CPU overload, the slots are decreased.
+	coord.cpuLoad(40 /* numRunnable */, 2 /* numProcs */)
+	_, total = coord.kvSlots()
+	require.Equal(t, int64(1), total)
+	// More slots are in use than there are in total, so the decrease hasn't
+	// taken effect yet and the slots aren't decreased any further.
+	coord.cpuLoad(40 /* numRunnable */, 2 /* numProcs */)
+	_, total = coord.kvSlots()
+	require.Equal(t, int64(1), total)
+
+	// Returning a slot doesn't admit the waiting work, since more slots are
+	// in use than there are in total.
+	kvQ.AdmittedWorkDone(tenant)
+	require.True(t, kvQ.hasWaitingRequests())
+	kvQ.AdmittedWorkDone(tenant)
+	require.NoError(t, <-ch)
+
+	// Once the load is gone, the slots are increased again while they're all
+	// in use.
+	coord.cpuLoad(1 /* numRunnable */, 2 /* numProcs */)
+	used, total = coord.kvSlots()
+	require.Equal(t, int64(1), used)
+	require.Equal(t, int64(2), total)
+	coord.cpuLoad(1 /* numRunnable */, 2 /* numProcs */)
+	_, total = coord.kvSlots()
+	require.Equal(t, int64(2), total)
+	kvQ.AdmittedWorkDone(tenant)
+}
+
+func TestGrantCoordinatorIOLoad(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	ReadAmplificationOverloadThreshold.Override(&st.SV, 20)
+	coord := NewGrantCoordinator(st)
+	coord.setKVSlots(unlimited)
+	kvQ := coord.GetWorkQueue(KVWork)
+	sqlQ := coord.GetWorkQueue(SQLStatementLeafStartWork)
+	tenant := roachpb.SystemTenantID
+
+	admit := func(n int) {
+		for i := 0; i < n; i++ {
+			_, err := kvQ.Admit(ctx, WorkInfo{TenantID: tenant})
+			require.NoError(t, err)
+			kvQ.AdmittedWorkDone(tenant)
+		}
+	}
+	admit(100)
+
+	// Once a store is overloaded, the number of requests admitted in the next
+	// interval is limited to half the number admitted in the last one.
+	coord.ioLoad(ctx, []StoreMetrics{{StoreID: 1, ReadAmplification: 5}, {StoreID: 2, ReadAmplification: 30}})
+	admit(50)
+	ch := admitAsync(ctx, kvQ, WorkInfo{TenantID: tenant})
+	waitForQueueLength(t, kvQ, 1)
+
+	// SQL work doesn't get to skip ahead of the waiting KV work.
+	sqlCh := admitAsync(ctx, sqlQ, WorkInfo{TenantID: tenant})
+	waitForQueueLength(t, sqlQ, 1)
+
+	// The tokens of the next interval admit the waiting work.
+	coord.ioLoad(ctx, []StoreMetrics{{StoreID: 1, ReadAmplification: 5}, {StoreID: 2, ReadAmplification: 30}})
+	require.NoError(t, <-ch)
+	require.NoError(t, <-sqlCh)
+	kvQ.AdmittedWorkDone(tenant)
+	sqlQ.AdmittedWorkDone(tenant)
+
+	// Once the overload clears, admission is no longer limited.
+	coord.ioLoad(ctx, []StoreMetrics{{StoreID: 1, ReadAmplification: 5}, {StoreID: 2, ReadAmplification: 10}})
+	admit(1000)
+}
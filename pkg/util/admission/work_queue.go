@@ -0,0 +1,380 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// WorkQueue maintains a queue of work of a particular WorkKind waiting to be
+// admitted, and is paired with a granter that decides when it is admitted.
+// Waiting work is admitted in tenant fair-share order, i.e. the tenant with
+// the fewest running admitted pieces of work goes first, and then by priority
+// and create time within a tenant.
+//
+// Work is admitted by calling Admit, which blocks until the work is admitted
+// or its context is canceled. Once the admitted work is done, the caller must
+// call AdmittedWorkDone to return its slot.
+type WorkQueue struct {
+	workKind WorkKind
+	granter  granter
+	settings *cluster.Settings
+	enabled  *settings.BoolSetting
+
+	mu struct {
+		syncutil.Mutex
+		// tenants holds the tenants that have running or waiting work.
+		tenants map[uint64]*tenantInfo
+		// tenantHeap holds the tenants that have waiting work, ordered by the
+		// amount of running work.
+		tenantHeap tenantHeap
+	}
+	metrics WorkQueueMetrics
+}
+
+var _ requester = &WorkQueue{}
+
+func makeWorkQueue(
+	workKind WorkKind, granter granter, st *cluster.Settings, enabled *settings.BoolSetting,
+) *WorkQueue {
+	q := &WorkQueue{
+		workKind: workKind,
+		granter:  granter,
+		settings: st,
+		enabled:  enabled,
+		metrics:  makeWorkQueueMetrics(workKind),
+	}
+	q.mu.tenants = make(map[uint64]*tenantInfo)
+	return q
+}
+
+// Admit is called when requesting admission for some work. If err != nil,
+// the request was not admitted, which can only happen if the context is
+// canceled while waiting. If enabled is true and err == nil, the work was
+// admitted, and the caller must call AdmittedWorkDone once the work is done.
+// If enabled is false, admission control is disabled and AdmittedWorkDone
+// must not be called.
+func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err error) {
+	if !q.enabled.Get(&q.settings.SV) {
+		return false, nil
+	}
+	q.metrics.Requested.Inc(1)
+	tenantID := info.TenantID.ToUint64()
+
+	if info.BypassAdmission {
+		q.mu.Lock()
+		q.admitLocked(q.getTenantLocked(tenantID))
+		q.mu.Unlock()
+		q.granter.tookWithoutPermission()
+		q.metrics.Admitted.Inc(1)
+		return true, nil
+	}
+
+	q.mu.Lock()
+	if len(q.mu.tenantHeap) == 0 {
+		// Fast path: no work is waiting, so the work may be able to skip the
+		// queue. The mutex must not be held while calling into the granter,
+		// which calls back into the WorkQueue while holding its own mutex.
+		q.mu.Unlock()
+		if q.granter.tryGet() {
+			q.mu.Lock()
+			q.admitLocked(q.getTenantLocked(tenantID))
+			q.mu.Unlock()
+			q.metrics.Admitted.Inc(1)
+			return true, nil
+		}
+		q.mu.Lock()
+	}
+
+	// Slow path: the work has to wait until it is granted a slot.
+	tenant := q.getTenantLocked(tenantID)
+	work := &waitingWork{
+		priority:   info.Priority,
+		createTime: info.CreateTime,
+		ch:         make(chan struct{}, 1),
+		enqueuedAt: timeutil.Now(),
+	}
+	heap.Push(&tenant.waitingWorkHeap, work)
+	if len(tenant.waitingWorkHeap) == 1 {
+		heap.Push(&q.mu.tenantHeap, tenant)
+	}
+	q.mu.Unlock()
+	q.metrics.WaitQueueLength.Inc(1)
+	defer q.metrics.WaitQueueLength.Dec(1)
+	q.granter.tryGrant()
+
+	select {
+	case <-ctx.Done():
+		q.mu.Lock()
+		if work.heapIndex < 0 {
+			// The work was granted concurrently with the cancellation. Return
+			// the slot it was granted.
+			q.mu.Unlock()
+			q.AdmittedWorkDone(info.TenantID)
+		} else {
+			tenant.removeWaitingWork(work)
+			if len(tenant.waitingWorkHeap) == 0 {
+				heap.Remove(&q.mu.tenantHeap, tenant.heapIndex)
+				q.maybeRemoveTenantLocked(tenant)
+			}
+			q.mu.Unlock()
+		}
+		q.metrics.Errored.Inc(1)
+		waitDur := timeutil.Since(work.enqueuedAt)
+		q.metrics.WaitDurationSum.Inc(waitDur.Nanoseconds())
+		return true, errors.Wrapf(ctx.Err(), "%s work canceled while waiting for admission after %s",
+			q.workKind, waitDur)
+	case <-work.ch:
+		waitDur := timeutil.Since(work.enqueuedAt)
+		q.metrics.WaitDurationSum.Inc(waitDur.Nanoseconds())
+		q.metrics.Admitted.Inc(1)
+		if log.V(2) {
+			log.Infof(ctx, "%s work admitted after waiting for %s", q.workKind, waitDur)
+		}
+		return true, nil
+	}
+}
+
+// AdmittedWorkDone is called once work that was admitted by Admit is done.
+func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID) {
+	q.mu.Lock()
+	tenant, ok := q.mu.tenants[tenantID.ToUint64()]
+	if !ok || tenant.used == 0 {
+		q.mu.Unlock()
+		panic(errors.AssertionFailedf("no admitted %s work for tenant %s", q.workKind, tenantID))
+	}
+	tenant.used--
+	if tenant.heapIndex >= 0 {
+		heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+	q.maybeRemoveTenantLocked(tenant)
+	q.mu.Unlock()
+	q.granter.returnGrant()
+}
+
+// hasWaitingRequests implements the requester interface.
+func (q *WorkQueue) hasWaitingRequests() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.mu.tenantHeap) > 0
+}
+
+// granted implements the requester interface. It admits the first waiting
+// work of the tenant with the fewest running pieces of work.
+func (q *WorkQueue) granted() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.mu.tenantHeap) == 0 {
+		return false
+	}
+	tenant := q.mu.tenantHeap[0]
+	work := heap.Pop(&tenant.waitingWorkHeap).(*waitingWork)
+	q.admitLocked(tenant)
+	if len(tenant.waitingWorkHeap) == 0 {
+		heap.Remove(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+	work.ch <- struct{}{}
+	return true
+}
+
+func (q *WorkQueue) getTenantLocked(tenantID uint64) *tenantInfo {
+	tenant, ok := q.mu.tenants[tenantID]
+	if !ok {
+		tenant = &tenantInfo{id: tenantID, heapIndex: -1}
+		q.mu.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+// admitLocked accounts for a piece of work of the tenant being admitted.
+func (q *WorkQueue) admitLocked(tenant *tenantInfo) {
+	tenant.used++
+	if tenant.heapIndex >= 0 {
+		heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+}
+
+// maybeRemoveTenantLocked stops tracking a tenant once it has neither running
+// nor waiting work.
+func (q *WorkQueue) maybeRemoveTenantLocked(tenant *tenantInfo) {
+	if tenant.used == 0 && len(tenant.waitingWorkHeap) == 0 {
+		delete(q.mu.tenants, tenant.id)
+	}
+}
+
+// tenantInfo is the per-tenant state of a WorkQueue.
+type tenantInfo struct {
+	id uint64
+	// used is the number of running admitted pieces of work of the tenant.
+	used            uint64
+	waitingWorkHeap waitingWorkHeap
+	// heapIndex is the index of the tenant in the tenantHeap, or -1 if the
+	// tenant has no waiting work.
+	heapIndex int
+}
+
+func (t *tenantInfo) removeWaitingWork(work *waitingWork) {
+	heap.Remove(&t.waitingWorkHeap, work.heapIndex)
+}
+
+// tenantHeap is a heap of tenants with waiting work, with the tenant with
+// the fewest running pieces of work at the top.
+type tenantHeap []*tenantInfo
+
+var _ heap.Interface = (*tenantHeap)(nil)
+
+func (th *tenantHeap) Len() int {
+	return len(*th)
+}
+
+func (th *tenantHeap) Less(i, j int) bool {
+	if (*th)[i].used == (*th)[j].used {
+		return (*th)[i].id < (*th)[j].id
+	}
+	return (*th)[i].used < (*th)[j].used
+}
+
+func (th *tenantHeap) Swap(i, j int) {
+	(*th)[i], (*th)[j] = (*th)[j], (*th)[i]
+	(*th)[i].heapIndex = i
+	(*th)[j].heapIndex = j
+}
+
+func (th *tenantHeap) Push(x interface{}) {
+	n := len(*th)
+	item := x.(*tenantInfo)
+	item.heapIndex = n
+	*th = append(*th, item)
+}
+
+func (th *tenantHeap) Pop() interface{} {
+	old := *th
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*th = old[0 : n-1]
+	return item
+}
+
+// waitingWork is a piece of work waiting to be admitted.
+type waitingWork struct {
+	priority   WorkPriority
+	createTime int64
+	// ch is signaled when the work is admitted.
+	ch         chan struct{}
+	enqueuedAt time.Time
+	// heapIndex is the index of the work in its tenant's waitingWorkHeap, or
+	// -1 once it has been admitted.
+	heapIndex int
+}
+
+// waitingWorkHeap is a heap of waiting work, with the highest priority and,
+// within a priority, the oldest work at the top.
+type waitingWorkHeap []*waitingWork
+
+var _ heap.Interface = (*waitingWorkHeap)(nil)
+
+func (wwh *waitingWorkHeap) Len() int {
+	return len(*wwh)
+}
+
+func (wwh *waitingWorkHeap) Less(i, j int) bool {
+	if (*wwh)[i].priority == (*wwh)[j].priority {
+		return (*wwh)[i].createTime < (*wwh)[j].createTime
+	}
+	return (*wwh)[i].priority > (*wwh)[j].priority
+}
+
+func (wwh *waitingWorkHeap) Swap(i, j int) {
+	(*wwh)[i], (*wwh)[j] = (*wwh)[j], (*wwh)[i]
+	(*wwh)[i].heapIndex = i
+	(*wwh)[j].heapIndex = j
+}
+
+func (wwh *waitingWorkHeap) Push(x interface{}) {
+	n := len(*wwh)
+	item := x.(*waitingWork)
+	item.heapIndex = n
+	*wwh = append(*wwh, item)
+}
+
+func (wwh *waitingWorkHeap) Pop() interface{} {
+	old := *wwh
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*wwh = old[0 : n-1]
+	return item
+}
+
+// WorkQueueMetrics are the metrics of a WorkQueue.
+type WorkQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	WaitDurationSum *metric.Counter
+	WaitQueueLength *metric.Gauge
+}
+
+var _ metric.Struct = WorkQueueMetrics{}
+
+// MetricStruct implements the metric.Struct interface.
+func (WorkQueueMetrics) MetricStruct() {}
+
+func makeWorkQueueMetrics(workKind WorkKind) WorkQueueMetrics {
+	name := workKind.metricName()
+	return WorkQueueMetrics{
+		Requested: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.requested.%s", name),
+			Help:        fmt.Sprintf("Number of %s requests for admission", workKind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Admitted: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.admitted.%s", name),
+			Help:        fmt.Sprintf("Number of %s requests admitted", workKind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Errored: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.errored.%s", name),
+			Help:        fmt.Sprintf("Number of %s requests not admitted due to error", workKind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		WaitDurationSum: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.wait_sum.%s", name),
+			Help:        fmt.Sprintf("Total wait time of %s requests that waited for admission", workKind),
+			Measurement: "Wait time Duration",
+			Unit:        metric.Unit_NANOSECONDS,
+		}),
+		WaitQueueLength: metric.NewGauge(metric.Metadata{
+			Name:        fmt.Sprintf("admission.wait_queue_length.%s", name),
+			Help:        fmt.Sprintf("Length of the %s admission queue", workKind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+	}
+}
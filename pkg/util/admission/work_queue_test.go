@@ -0,0 +1,203 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testGranter is a granter with a fixed number of slots, which only grants
+// slots to waiting work when told to.
+type testGranter struct {
+	r  requester
+	mu struct {
+		syncutil.Mutex
+		used, total int
+	}
+}
+
+var _ granter = &testGranter{}
+
+func (tg *testGranter) tryGet() bool {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if tg.mu.used < tg.mu.total {
+		tg.mu.used++
+		return true
+	}
+	return false
+}
+
+func (tg *testGranter) returnGrant() {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.mu.used--
+}
+
+func (tg *testGranter) tookWithoutPermission() {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.mu.used++
+}
+
+func (tg *testGranter) tryGrant() {}
+
+// grant grants a slot to the first waiting work, if any.
+func (tg *testGranter) grant() bool {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if !tg.r.granted() {
+		return false
+	}
+	tg.mu.used++
+	return true
+}
+
+func (tg *testGranter) used() int {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	return tg.mu.used
+}
+
+func makeTestWorkQueue(total int) (*WorkQueue, *testGranter) {
+	st := cluster.MakeTestingClusterSettings()
+	tg := &testGranter{}
+	tg.mu.total = total
+	q := makeWorkQueue(KVWork, tg, st, KVAdmissionControlEnabled)
+	tg.r = q
+	return q, tg
+}
+
+// admitAsync starts admitting work with the given info, and returns a channel
+// on which the result of the admission is delivered.
+func admitAsync(ctx context.Context, q *WorkQueue, info WorkInfo) chan error {
+	ch := make(chan error, 1)
+	go func() {
+		_, err := q.Admit(ctx, info)
+		ch <- err
+	}()
+	return ch
+}
+
+func waitForQueueLength(t *testing.T, q *WorkQueue, n int64) {
+	t.Helper()
+	testutils.SucceedsSoon(t, func() error {
+		if l := q.metrics.WaitQueueLength.Value(); l != n {
+			return errors.Errorf("expected %d waiting, found %d", n, l)
+		}
+		return nil
+	})
+}
+
+func TestWorkQueueBasic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	q, tg := makeTestWorkQueue(1)
+	tenant := roachpb.SystemTenantID
+
+	// The first work takes the only slot.
+	enabled, err := q.Admit(ctx, WorkInfo{TenantID: tenant})
+	require.NoError(t, err)
+	require.True(t, enabled)
+	require.Equal(t, 1, tg.used())
+
+	// Work bypassing admission doesn't wait, but uses a slot.
+	enabled, err = q.Admit(ctx, WorkInfo{TenantID: tenant, BypassAdmission: true})
+	require.NoError(t, err)
+	require.True(t, enabled)
+	require.Equal(t, 2, tg.used())
+	q.AdmittedWorkDone(tenant)
+	q.AdmittedWorkDone(tenant)
+	require.Equal(t, 0, tg.used())
+
+	// Further work has to wait until it's granted a slot.
+	tg.mu.Lock()
+	tg.mu.total = 0
+	tg.mu.Unlock()
+	ch := admitAsync(ctx, q, WorkInfo{TenantID: tenant})
+	waitForQueueLength(t, q, 1)
+	require.True(t, tg.grant())
+	require.NoError(t, <-ch)
+	require.False(t, tg.grant())
+	q.AdmittedWorkDone(tenant)
+
+	// Waiting work is removed from the queue when its context is canceled.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	ch = admitAsync(cancelCtx, q, WorkInfo{TenantID: tenant})
+	waitForQueueLength(t, q, 1)
+	cancel()
+	require.True(t, errors.Is(<-ch, context.Canceled))
+	require.False(t, q.hasWaitingRequests())
+	require.False(t, tg.grant())
+
+	// Admission control can be disabled.
+	KVAdmissionControlEnabled.Override(&q.settings.SV, false)
+	enabled, err = q.Admit(ctx, WorkInfo{TenantID: tenant})
+	require.NoError(t, err)
+	require.False(t, enabled)
+}
+
+func TestWorkQueueOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	q, tg := makeTestWorkQueue(0)
+	tenant1, tenant2 := roachpb.MakeTenantID(10), roachpb.MakeTenantID(20)
+
+	// Tenant 1 has running work, tenant 2 doesn't.
+	_, err := q.Admit(ctx, WorkInfo{TenantID: tenant1, BypassAdmission: true})
+	require.NoError(t, err)
+
+	type work struct {
+		name string
+		info WorkInfo
+	}
+	now := time.Now().UnixNano()
+	works := []work{
+		{name: "t1-normal-new", info: WorkInfo{TenantID: tenant1, Priority: NormalPri, CreateTime: now + 1}},
+		{name: "t1-normal-old", info: WorkInfo{TenantID: tenant1, Priority: NormalPri, CreateTime: now}},
+		{name: "t1-high", info: WorkInfo{TenantID: tenant1, Priority: HighPri, CreateTime: now + 2}},
+		{name: "t2-low", info: WorkInfo{TenantID: tenant2, Priority: LowPri, CreateTime: now + 3}},
+		{name: "t2-normal", info: WorkInfo{TenantID: tenant2, Priority: NormalPri, CreateTime: now + 4}},
+	}
+	chs := make(map[string]chan error)
+	for i, w := range works {
+		chs[w.name] = admitAsync(ctx, q, w.info)
+		waitForQueueLength(t, q, int64(i+1))
+	}
+
+	// The tenant with the least running work goes first, with ties broken by
+	// tenant ID. Within a tenant, work is ordered by priority and create time.
+	for _, name := range []string{"t2-normal", "t1-high", "t2-low", "t1-normal-old", "t1-normal-new"} {
+		require.True(t, tg.grant())
+		select {
+		case err := <-chs[name]:
+			require.NoError(t, err, name)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s was not admitted", name)
+		}
+	}
+	require.False(t, tg.grant())
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package goschedstats samples the number of runnable goroutines, i.e.
+// goroutines that are ready to run but are waiting for a processor. Unlike
+// CPU utilization, which saturates at 100%, the number of runnable goroutines
+// per processor keeps growing with the amount of CPU overload, which makes it
+// a good signal for admission control.
+package goschedstats
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// samplePeriod is the interval at which the number of runnable goroutines is
+// sampled while callbacks are registered. The run queues are short-lived, so
+// a short period is needed to get a meaningful signal.
+const samplePeriod = time.Millisecond
+
+// Supported returns whether the number of runnable goroutines can be sampled
+// with the Go runtime this binary was built with. If it returns false, no
+// callbacks are ever invoked.
+func Supported() bool {
+	return supported
+}
+
+// RunnableCountCallback is invoked with the number of runnable goroutines and
+// the number of processors (GOMAXPROCS) every time a sample is taken.
+type RunnableCountCallback func(numRunnable int, numProcs int)
+
+type callbackWithID struct {
+	RunnableCountCallback
+	id int64
+}
+
+var callbackInfo struct {
+	mu syncutil.Mutex
+	// id is the ID of the last registered callback.
+	id int64
+	// callbacks is copied on write, so the sampler can read it without holding
+	// the mutex while invoking the callbacks.
+	callbacks []callbackWithID
+	// samplerRunning is true while the sampler goroutine is running. It exits
+	// once there are no callbacks left.
+	samplerRunning bool
+}
+
+// RegisterRunnableCountCallback registers a callback to be invoked with every
+// sample of the number of runnable goroutines. The callbacks are invoked
+// sequentially from a single goroutine, so they must be cheap. It returns an
+// ID that can be used to unregister the callback.
+func RegisterRunnableCountCallback(cb RunnableCountCallback) (id int64) {
+	callbackInfo.mu.Lock()
+	defer callbackInfo.mu.Unlock()
+	callbackInfo.id++
+	id = callbackInfo.id
+	callbacks := make([]callbackWithID, 0, len(callbackInfo.callbacks)+1)
+	callbacks = append(callbacks, callbackInfo.callbacks...)
+	callbackInfo.callbacks = append(callbacks, callbackWithID{RunnableCountCallback: cb, id: id})
+	if supported && !callbackInfo.samplerRunning {
+		callbackInfo.samplerRunning = true
+		go sample()
+	}
+	return id
+}
+
+// UnregisterRunnableCountCallback unregisters the callback with the given ID.
+func UnregisterRunnableCountCallback(id int64) {
+	callbackInfo.mu.Lock()
+	defer callbackInfo.mu.Unlock()
+	callbacks := make([]callbackWithID, 0, len(callbackInfo.callbacks))
+	for _, cb := range callbackInfo.callbacks {
+		if cb.id != id {
+			callbacks = append(callbacks, cb)
+		}
+	}
+	callbackInfo.callbacks = callbacks
+}
+
+// sample periodically samples the number of runnable goroutines and invokes
+// the registered callbacks, until all callbacks have been unregistered.
+func sample() {
+	ticker := time.NewTicker(samplePeriod)
+	defer ticker.Stop()
+	for range ticker.C {
+		callbackInfo.mu.Lock()
+		callbacks := callbackInfo.callbacks
+		if len(callbacks) == 0 {
+			callbackInfo.samplerRunning = false
+			callbackInfo.mu.Unlock()
+			return
+		}
+		callbackInfo.mu.Unlock()
+		numRunnable, numProcs := numRunnableGoroutines()
+		for _, cb := range callbacks {
+			cb.RunnableCountCallback(numRunnable, numProcs)
+		}
+	}
+}
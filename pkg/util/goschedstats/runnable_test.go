@@ -0,0 +1,68 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package goschedstats
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumRunnableGoroutines(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if !Supported() {
+		skip.IgnoreLintf(t, "sampling runnable goroutines is not supported by %s", runtime.Version())
+	}
+
+	// Keep more goroutines busy than there are processors, so that some of
+	// them have to wait in the run queues.
+	done := make(chan struct{})
+	defer close(done)
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+
+	type sample struct{ numRunnable, numProcs int }
+	samples := make(chan sample, 1)
+	id := RegisterRunnableCountCallback(func(numRunnable int, numProcs int) {
+		select {
+		case samples <- sample{numRunnable: numRunnable, numProcs: numProcs}:
+		default:
+		}
+	})
+	defer UnregisterRunnableCountCallback(id)
+
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case s := <-samples:
+			require.Equal(t, runtime.GOMAXPROCS(0), s.numProcs)
+			if s.numRunnable > 0 {
+				return
+			}
+		case <-deadline:
+			t.Fatal("no runnable goroutines observed")
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build gc,go1.15,!go1.16
+
+package goschedstats
+
+import (
+	"sync/atomic"
+	_ "unsafe" // required by go:linkname
+)
+
+// The structs below are copies of the prefixes of the corresponding structs
+// in runtime/runtime2.go, up to the fields read by numRunnableGoroutines. The
+// layout of these structs is necessarily tied to a specific Go release, which
+// is why this file is protected by a build tag. Any change to the Go version
+// must be accompanied by a check that the layout is still accurate.
+
+type puintptr uintptr
+type muintptr uintptr
+type guintptr uintptr
+
+type sysmontick struct {
+	schedtick   uint32
+	schedwhen   int64
+	syscalltick uint32
+	syscallwhen int64
+}
+
+type pageCache struct {
+	base  uintptr
+	cache uint64
+	scav  uint64
+}
+
+type p struct {
+	id          int32
+	status      uint32
+	link        puintptr
+	schedtick   uint32
+	syscalltick uint32
+	sysmontick  sysmontick
+	m           muintptr
+	mcache      uintptr
+	pcache      pageCache
+	raceprocctx uintptr
+
+	deferpool    [5][]uintptr
+	deferpoolbuf [5][32]uintptr
+
+	goidcache    uint64
+	goidcacheend uint64
+
+	// Queue of runnable goroutines. Accessed without lock.
+	runqhead uint32
+	runqtail uint32
+}
+
+type lockRankStruct struct{}
+
+type mutex struct {
+	lockRankStruct
+	key uintptr
+}
+
+type gQueue struct {
+	head guintptr
+	tail guintptr
+}
+
+type schedt struct {
+	goidgen   uint64
+	lastpoll  uint64
+	pollUntil uint64
+
+	lock mutex
+
+	midle        muintptr
+	nmidle       int32
+	nmidlelocked int32
+	mnext        int64
+	maxmcount    int32
+	nmsys        int32
+	nmfreed      int64
+
+	ngsys uint32
+
+	pidle      puintptr
+	npidle     uint32
+	nmspinning uint32
+
+	// Global runnable queue.
+	runq     gQueue
+	runqsize int32
+}
+
+//go:linkname allp runtime.allp
+var allp []*p
+
+//go:linkname sched runtime.sched
+var sched schedt
+
+//go:linkname lock runtime.lock
+func lock(l *mutex)
+
+//go:linkname unlock runtime.unlock
+func unlock(l *mutex)
+
+const supported = true
+
+// numRunnableGoroutines returns the number of goroutines waiting in the
+// runtime's global and per-P run queues, and the number of Ps.
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	lock(&sched.lock)
+	numRunnable = int(sched.runqsize)
+	numProcs = len(allp)
+	for _, p := range allp {
+		h := atomic.LoadUint32(&p.runqhead)
+		t := atomic.LoadUint32(&p.runqtail)
+		numRunnable += int(t - h)
+	}
+	unlock(&sched.lock)
+	return numRunnable, numProcs
+}
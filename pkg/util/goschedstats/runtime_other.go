@@ -0,0 +1,24 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build !gc !go1.15 go1.16
+
+package goschedstats
+
+import "runtime"
+
+// The runtime's scheduler internals are only mirrored for specific Go
+// releases, see runtime_go1.15.go. Elsewhere, the number of runnable
+// goroutines is unknown and no samples are taken.
+const supported = false
+
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	return 0, runtime.GOMAXPROCS(0)
+}
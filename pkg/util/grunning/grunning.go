@@ -0,0 +1,72 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package grunning measures the CPU time spent running individual
+// goroutines.
+//
+// The Go runtime does not account CPU time per goroutine. It is measured here
+// as the CPU time of the OS thread running the goroutine, which is locked to
+// that thread for the duration of the measurement so that no other goroutine
+// can run on it in the meantime. Time the goroutine spends blocked, be it in
+// a syscall reading from disk or waiting on a channel or mutex, is not
+// counted since its thread is not running either. This is what makes the
+// measurement differ from the wall time elapsed around the same work.
+//
+// Locking a goroutine to its thread is cheap while the goroutine runs, but
+// each time it blocks the runtime has to hand its processor off to another
+// thread and back. Timers should thus only surround work which mostly runs
+// on the CPU, such as evaluating a request.
+package grunning
+
+import (
+	"runtime"
+	"time"
+)
+
+// Supported returns whether the CPU time of goroutines can be measured on
+// this platform. If it returns false, timers always measure zero.
+func Supported() bool {
+	return supported
+}
+
+// Timer measures the CPU time spent by the goroutine that started it. The
+// zero value is ready for use. Start and Stop must be called on the same
+// goroutine, and every call to Start must be followed by a call to Stop.
+type Timer struct {
+	start   time.Duration
+	running bool
+}
+
+// Start starts the timer, locking the calling goroutine to its OS thread
+// until Stop is called.
+func (t *Timer) Start() {
+	if !supported || t.running {
+		return
+	}
+	runtime.LockOSThread()
+	t.start = threadCPUTime()
+	t.running = true
+}
+
+// Stop stops the timer, unlocks the calling goroutine from its OS thread and
+// returns the CPU time spent by the goroutine since Start was called. It
+// returns zero if the timer is not running.
+func (t *Timer) Stop() time.Duration {
+	if !t.running {
+		return 0
+	}
+	d := threadCPUTime() - t.start
+	runtime.UnlockOSThread()
+	*t = Timer{}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
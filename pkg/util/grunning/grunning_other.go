@@ -0,0 +1,23 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build !linux
+
+package grunning
+
+import "time"
+
+// The CPU time of the calling thread is only read on Linux, see
+// grunning_linux.go. Elsewhere, timers always measure zero.
+const supported = false
+
+func threadCPUTime() time.Duration {
+	return 0
+}
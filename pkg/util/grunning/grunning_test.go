@@ -0,0 +1,47 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package grunning
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if !Supported() {
+		skip.IgnoreLintf(t, "measuring goroutine CPU time is not supported on %s", runtime.GOOS)
+	}
+
+	// A goroutine spinning on the CPU is charged for (nearly) all the time it
+	// runs.
+	var spin Timer
+	spin.Start()
+	start := time.Now()
+	for time.Since(start) < 50*time.Millisecond {
+	}
+	require.Greater(t, int64(spin.Stop()), int64(10*time.Millisecond))
+
+	// A goroutine blocked waiting is not charged for the time it waits.
+	var sleep Timer
+	sleep.Start()
+	time.Sleep(50 * time.Millisecond)
+	require.Less(t, int64(sleep.Stop()), int64(10*time.Millisecond))
+
+	// Stopping a timer which isn't running measures nothing.
+	require.Zero(t, sleep.Stop())
+}
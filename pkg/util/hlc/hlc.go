@@ -334,7 +334,13 @@ func (c *Clock) PhysicalTime() time.Time {
 // the maximum clock offset. To receive an error response instead of forcing the
 // update in case the remote timestamp is too far into the future, use
 // UpdateAndCheckMaxOffset() instead.
+//
+// Synthetic timestamps are ignored, as they do not originate from any clock
+// and may lead present time by more than the maximum clock offset.
 func (c *Clock) Update(rt Timestamp) {
+	if rt.IsFlagSet(TimestampFlag_SYNTHETIC) {
+		return
+	}
 
 	// Fast path to avoid grabbing the mutex if the remote time is behind. This
 	// requires c.mu.timestamp.WallTime to be written atomically, even though
@@ -365,8 +371,12 @@ func (c *Clock) Update(rt Timestamp) {
 
 // UpdateAndCheckMaxOffset is like Update, but also takes the wall time into account and
 // returns an error in the event that the supplied remote timestamp exceeds
-// the wall clock time by more than the maximum clock offset.
+// the wall clock time by more than the maximum clock offset. Like Update, it
+// ignores synthetic timestamps.
 func (c *Clock) UpdateAndCheckMaxOffset(ctx context.Context, rt Timestamp) error {
+	if rt.IsFlagSet(TimestampFlag_SYNTHETIC) {
+		return nil
+	}
 	var err error
 	physicalClock := c.getPhysicalClockAndCheck(ctx)
 
@@ -385,6 +395,34 @@ func (c *Clock) UpdateAndCheckMaxOffset(ctx context.Context, rt Timestamp) error
 	return nil
 }
 
+// SleepUntil blocks until the clock's reading reaches or exceeds the
+// specified timestamp, or until the context is canceled. It is used to wait
+// out timestamps that lead present time, such as the synthetic commit
+// timestamps of writes to non-blocking ranges ("commit-wait").
+//
+// SleepUntil only consults the local clock; it does not account for the
+// clocks of other nodes, which may be up to the maximum clock offset behind.
+func (c *Clock) SleepUntil(ctx context.Context, t Timestamp) error {
+	t = t.ClearFlag(TimestampFlag_SYNTHETIC)
+	for {
+		now := c.Now()
+		if t.LessEq(now) {
+			return nil
+		}
+		d := time.Duration(t.WallTime - now.WallTime)
+		if d <= 0 {
+			// Only the logical component is ahead. The next clock tick is at
+			// most a nanosecond away.
+			d = time.Nanosecond
+		}
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // setForwardJumpCheckEnabled atomically sets forwardClockJumpCheckEnabled
 func (c *Clock) setForwardJumpCheckEnabled(forwardJumpCheckEnabled bool) {
 	if forwardJumpCheckEnabled {
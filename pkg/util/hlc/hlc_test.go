@@ -344,6 +344,41 @@ func TestHLCClock(t *testing.T) {
 	}
 }
 
+// TestHLCSyntheticTimestamps verifies that synthetic timestamps don't update
+// the clock, and that SleepUntil waits them out.
+func TestHLCSyntheticTimestamps(t *testing.T) {
+	ctx := context.Background()
+	m := NewManualClock(10)
+	c := NewClock(m.UnixNano, 10*time.Nanosecond)
+
+	syn := Timestamp{WallTime: 100}.SetFlag(TimestampFlag_SYNTHETIC)
+	c.Update(syn)
+	assert.Equal(t, Timestamp{WallTime: 10, Logical: 1}, c.Now())
+	// The synthetic timestamp is beyond the maximum clock offset, but that's
+	// not an error.
+	assert.NoError(t, c.UpdateAndCheckMaxOffset(ctx, syn))
+	assert.Equal(t, Timestamp{WallTime: 10, Logical: 2}, c.Now())
+
+	// SleepUntil returns immediately if the timestamp has already passed.
+	assert.NoError(t, c.SleepUntil(ctx, Timestamp{WallTime: 5}))
+
+	// Otherwise, it blocks until the clock catches up.
+	done := make(chan error, 1)
+	go func() { done <- c.SleepUntil(ctx, syn) }()
+	select {
+	case err := <-done:
+		t.Fatalf("SleepUntil returned early: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	m.Set(100)
+	assert.NoError(t, <-done)
+
+	// It respects context cancellation.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, c.SleepUntil(cancelCtx, Timestamp{WallTime: 200}))
+}
+
 // TestExampleManualClock shows how a manual clock can be
 // used as a physical clock. This is useful for testing.
 func TestExampleManualClock(t *testing.T) {
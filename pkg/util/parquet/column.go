@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// columnBuffer accumulates the values of a single leaf column for the row
+// group being written. Values are PLAIN encoded as they are added; the
+// repetition and definition levels are kept decoded and are RLE encoded when
+// the page is built.
+type columnBuffer struct {
+	physical                 physicalType
+	maxDefLevel, maxRepLevel uint8
+
+	values bytes.Buffer
+	// bools holds the values of BOOLEAN columns, which are bit-packed when the
+	// page is built.
+	bools     []bool
+	defLevels []uint8
+	repLevels []uint8
+	scratch   [8]byte
+}
+
+// numValues returns the number of entries, including nulls and empty lists,
+// in the buffer.
+func (c *columnBuffer) numValues() int {
+	return len(c.defLevels)
+}
+
+func (c *columnBuffer) reset() {
+	c.values.Reset()
+	c.bools = c.bools[:0]
+	c.defLevels = c.defLevels[:0]
+	c.repLevels = c.repLevels[:0]
+}
+
+// addLevels records the levels of the next entry in the column.
+func (c *columnBuffer) addLevels(repLevel, defLevel uint8) {
+	c.defLevels = append(c.defLevels, defLevel)
+	c.repLevels = append(c.repLevels, repLevel)
+}
+
+func (c *columnBuffer) writeBool(v bool) {
+	c.bools = append(c.bools, v)
+}
+
+func (c *columnBuffer) writeInt32(v int32) {
+	binary.LittleEndian.PutUint32(c.scratch[:4], uint32(v))
+	c.values.Write(c.scratch[:4])
+}
+
+func (c *columnBuffer) writeInt64(v int64) {
+	binary.LittleEndian.PutUint64(c.scratch[:8], uint64(v))
+	c.values.Write(c.scratch[:8])
+}
+
+func (c *columnBuffer) writeFloat(v float32) {
+	binary.LittleEndian.PutUint32(c.scratch[:4], math.Float32bits(v))
+	c.values.Write(c.scratch[:4])
+}
+
+func (c *columnBuffer) writeDouble(v float64) {
+	binary.LittleEndian.PutUint64(c.scratch[:8], math.Float64bits(v))
+	c.values.Write(c.scratch[:8])
+}
+
+// writeByteArray writes a BYTE_ARRAY value, which is prefixed by its length.
+func (c *columnBuffer) writeByteArray(v []byte) {
+	binary.LittleEndian.PutUint32(c.scratch[:4], uint32(len(v)))
+	c.values.Write(c.scratch[:4])
+	c.values.Write(v)
+}
+
+// writeFixedLenByteArray writes a FIXED_LEN_BYTE_ARRAY value. The caller is
+// responsible for v having the length declared in the schema.
+func (c *columnBuffer) writeFixedLenByteArray(v []byte) {
+	c.values.Write(v)
+}
+
+// encodePage returns the uncompressed contents of a data page holding every
+// entry in the buffer.
+func (c *columnBuffer) encodePage() []byte {
+	var page bytes.Buffer
+	if c.maxRepLevel > 0 {
+		writeLevels(&page, c.repLevels)
+	}
+	if c.maxDefLevel > 0 {
+		writeLevels(&page, c.defLevels)
+	}
+	if c.physical == physicalTypeBoolean {
+		// Booleans are bit-packed, least significant bit first.
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, b := range c.bools {
+			if b {
+				packed[i/8] |= 1 << (uint(i) % 8)
+			}
+		}
+		page.Write(packed)
+	} else {
+		page.Write(c.values.Bytes())
+	}
+	return page.Bytes()
+}
+
+// writeLevels writes levels using the RLE/bit-packing hybrid encoding,
+// prefixed by the 4 byte length of the encoded data. Only RLE runs are used,
+// which is a good fit for levels since they are mostly constant.
+func writeLevels(buf *bytes.Buffer, levels []uint8) {
+	var encoded bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		n := binary.PutUvarint(scratch[:], uint64(j-i)<<1)
+		encoded.Write(scratch[:n])
+		// The value of an RLE run is stored in ceil(bitWidth/8) bytes. Levels
+		// are at most 3, so that is always a single byte.
+		encoded.WriteByte(levels[i])
+		i = j
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(encoded.Len()))
+	buf.Write(length[:])
+	buf.Write(encoded.Bytes())
+}
@@ -0,0 +1,433 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// encodeFn writes a non-NULL datum into a column buffer.
+type encodeFn func(d tree.Datum, c *columnBuffer) error
+
+// column describes how a SQL column is stored in a parquet file. Every SQL
+// column maps to exactly one parquet leaf column: scalar types map to an
+// optional leaf and arrays map to the standard three-level LIST structure.
+type column struct {
+	typ *types.T
+	// elements are the schema elements of the column, from the top-level
+	// element down to the leaf.
+	elements []schemaElement
+	// path is the path_in_schema of the leaf.
+	path                     []string
+	isArray                  bool
+	maxDefLevel, maxRepLevel uint8
+	encode                   encodeFn
+}
+
+func (c *column) leaf() schemaElement {
+	return c.elements[len(c.elements)-1]
+}
+
+// SchemaDefinition is the parquet schema of a file holding rows with the given
+// SQL column names and types.
+type SchemaDefinition struct {
+	cols []column
+}
+
+// Definition levels of the entries in a column. For scalar columns an entry
+// is either NULL (0) or set (1). For array columns the levels count the number
+// of optional or repeated schema elements which are defined along the path to
+// the leaf, see the comment on NewSchema.
+const (
+	defLevelNull         = 0
+	defLevelScalar       = 1
+	defLevelEmptyArray   = 1
+	defLevelNullElement  = 2
+	defLevelArrayElement = 3
+)
+
+// NewSchema builds the schema of a parquet file whose rows have the given
+// column names and types.
+//
+// SQL types are mapped to parquet physical and logical types as follows:
+//
+//	BOOL                  BOOLEAN
+//	INT2, INT4            INT32 (INTEGER(16|32, signed))
+//	INT8                  INT64 (INTEGER(64, signed))
+//	FLOAT4                FLOAT
+//	FLOAT8                DOUBLE
+//	DECIMAL(p,s)          BYTE_ARRAY (DECIMAL(p,s))
+//	STRING, CHAR, NAME    BYTE_ARRAY (STRING)
+//	BYTES                 BYTE_ARRAY
+//	UUID                  FIXED_LEN_BYTE_ARRAY(16) (UUID)
+//	DATE                  INT32 (DATE)
+//	TIME                  INT64 (TIME(MICROS, local))
+//	TIMESTAMP             INT64 (TIMESTAMP(MICROS, local))
+//	TIMESTAMPTZ           INT64 (TIMESTAMP(MICROS, UTC))
+//	JSONB                 BYTE_ARRAY (JSON)
+//	ENUM                  BYTE_ARRAY (ENUM)
+//	T[]                   LIST of T
+//
+// Types without a faithful parquet counterpart, including DECIMAL without a
+// fixed scale, INTERVAL, INET and the spatial types, are written as strings
+// using the same format as EXPORT to CSV.
+//
+// Arrays use the three-level list structure required by the parquet
+// specification:
+//
+//	optional group <name> (LIST) {
+//	  repeated group list {
+//	    optional <element type> element;
+//	  }
+//	}
+//
+// so a NULL array has definition level 0, an empty array 1, a NULL element 2
+// and any other element 3.
+func NewSchema(columnNames []string, columnTypes []*types.T) (*SchemaDefinition, error) {
+	if len(columnNames) != len(columnTypes) {
+		return nil, errors.AssertionFailedf(
+			"%d column names provided for %d column types", len(columnNames), len(columnTypes))
+	}
+	seen := make(map[string]struct{}, len(columnNames))
+	sch := &SchemaDefinition{cols: make([]column, len(columnNames))}
+	for i, name := range columnNames {
+		if _, ok := seen[name]; ok {
+			return nil, errors.Errorf("duplicate column name %q", name)
+		}
+		seen[name] = struct{}{}
+
+		typ := columnTypes[i]
+		col := &sch.cols[i]
+		col.typ = typ
+		if typ.Family() != types.ArrayFamily {
+			leaf, encode, err := makeLeaf(name, typ)
+			if err != nil {
+				return nil, err
+			}
+			col.elements = []schemaElement{leaf}
+			col.path = []string{name}
+			col.maxDefLevel = defLevelScalar
+			col.encode = encode
+			continue
+		}
+
+		elemTyp := typ.ArrayContents()
+		if elemTyp.Family() == types.ArrayFamily {
+			return nil, errors.Errorf("column %q: nested arrays are not supported", name)
+		}
+		leaf, encode, err := makeLeaf("element", elemTyp)
+		if err != nil {
+			return nil, err
+		}
+		col.isArray = true
+		col.elements = []schemaElement{
+			{
+				name:        name,
+				isGroup:     true,
+				numChildren: 1,
+				repetition:  repetitionOptional,
+				converted:   convertedTypeList,
+				logical:     logicalType{kind: logicalTypeList},
+			},
+			{
+				name:        "list",
+				isGroup:     true,
+				numChildren: 1,
+				repetition:  repetitionRepeated,
+				converted:   convertedTypeNone,
+			},
+			leaf,
+		}
+		col.path = []string{name, "list", "element"}
+		col.maxDefLevel = defLevelArrayElement
+		col.maxRepLevel = 1
+		col.encode = encode
+	}
+	return sch, nil
+}
+
+// schemaElements returns the flattened, depth-first list of schema elements
+// which is stored in the file footer.
+func (s *SchemaDefinition) schemaElements() []schemaElement {
+	elements := []schemaElement{{
+		name:        "schema",
+		isRoot:      true,
+		isGroup:     true,
+		numChildren: int32(len(s.cols)),
+		converted:   convertedTypeNone,
+	}}
+	for i := range s.cols {
+		elements = append(elements, s.cols[i].elements...)
+	}
+	return elements
+}
+
+// makeLeaf returns the optional leaf schema element used to store values of
+// the given (non-array) type, along with the function that encodes them.
+func makeLeaf(name string, typ *types.T) (schemaElement, encodeFn, error) {
+	leaf := schemaElement{
+		name:       name,
+		repetition: repetitionOptional,
+		converted:  convertedTypeNone,
+	}
+	switch typ.Family() {
+	case types.BoolFamily:
+		leaf.typ = physicalTypeBoolean
+		return leaf, encodeBool, nil
+
+	case types.IntFamily:
+		switch typ.Width() {
+		case 16, 32:
+			leaf.typ = physicalTypeInt32
+			leaf.converted = convertedTypeInt32
+			if typ.Width() == 16 {
+				leaf.converted = convertedTypeInt16
+			}
+			leaf.logical = logicalType{kind: logicalTypeInteger, bitWidth: int8(typ.Width())}
+			return leaf, encodeInt32, nil
+		default:
+			leaf.typ = physicalTypeInt64
+			leaf.converted = convertedTypeInt64
+			leaf.logical = logicalType{kind: logicalTypeInteger, bitWidth: 64}
+			return leaf, encodeInt64, nil
+		}
+
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			leaf.typ = physicalTypeFloat
+			return leaf, encodeFloat, nil
+		}
+		leaf.typ = physicalTypeDouble
+		return leaf, encodeDouble, nil
+
+	case types.DecimalFamily:
+		if typ.Precision() == 0 {
+			// Without a fixed scale the values can't be stored as parquet
+			// decimals, which need one.
+			return makeStringLeaf(leaf), encodeFormatted, nil
+		}
+		leaf.typ = physicalTypeByteArray
+		leaf.converted = convertedTypeDecimal
+		leaf.logical = logicalType{
+			kind:      logicalTypeDecimal,
+			scale:     typ.Scale(),
+			precision: typ.Precision(),
+		}
+		return leaf, makeEncodeDecimal(typ.Scale()), nil
+
+	case types.StringFamily, types.CollatedStringFamily:
+		return makeStringLeaf(leaf), encodeString, nil
+
+	case types.BytesFamily:
+		leaf.typ = physicalTypeByteArray
+		return leaf, encodeBytes, nil
+
+	case types.UuidFamily:
+		leaf.typ = physicalTypeFixedLenByteArray
+		leaf.typeLength = 16
+		leaf.logical = logicalType{kind: logicalTypeUUID}
+		return leaf, encodeUUID, nil
+
+	case types.DateFamily:
+		leaf.typ = physicalTypeInt32
+		leaf.converted = convertedTypeDate
+		leaf.logical = logicalType{kind: logicalTypeDate}
+		return leaf, encodeDate, nil
+
+	case types.TimeFamily:
+		// Like TIMESTAMP_MICROS, the TIME_MICROS converted type implies
+		// adjustment to UTC, so it isn't set for SQL times, which are local.
+		leaf.typ = physicalTypeInt64
+		leaf.logical = logicalType{kind: logicalTypeTime}
+		return leaf, encodeTime, nil
+
+	case types.TimestampFamily:
+		// The TIMESTAMP_MICROS converted type implies adjustment to UTC, so it
+		// is only set for TIMESTAMPTZ.
+		leaf.typ = physicalTypeInt64
+		leaf.logical = logicalType{kind: logicalTypeTimestamp}
+		return leaf, encodeTimestamp, nil
+
+	case types.TimestampTZFamily:
+		leaf.typ = physicalTypeInt64
+		leaf.converted = convertedTypeTimestampMicros
+		leaf.logical = logicalType{kind: logicalTypeTimestamp, isAdjustedToUTC: true}
+		return leaf, encodeTimestampTZ, nil
+
+	case types.JsonFamily:
+		leaf.typ = physicalTypeByteArray
+		leaf.converted = convertedTypeJSON
+		leaf.logical = logicalType{kind: logicalTypeJSON}
+		return leaf, encodeJSON, nil
+
+	case types.EnumFamily:
+		leaf.typ = physicalTypeByteArray
+		leaf.converted = convertedTypeEnum
+		leaf.logical = logicalType{kind: logicalTypeEnum}
+		return leaf, encodeEnum, nil
+
+	case types.ArrayFamily:
+		return schemaElement{}, nil, errors.AssertionFailedf("unexpected array type %s", typ.SQLString())
+
+	default:
+		return makeStringLeaf(leaf), encodeFormatted, nil
+	}
+}
+
+func makeStringLeaf(leaf schemaElement) schemaElement {
+	leaf.typ = physicalTypeByteArray
+	leaf.converted = convertedTypeUTF8
+	leaf.logical = logicalType{kind: logicalTypeString}
+	return leaf
+}
+
+func encodeBool(d tree.Datum, c *columnBuffer) error {
+	c.writeBool(bool(tree.MustBeDBool(d)))
+	return nil
+}
+
+func encodeInt32(d tree.Datum, c *columnBuffer) error {
+	c.writeInt32(int32(tree.MustBeDInt(d)))
+	return nil
+}
+
+func encodeInt64(d tree.Datum, c *columnBuffer) error {
+	c.writeInt64(int64(tree.MustBeDInt(d)))
+	return nil
+}
+
+func encodeFloat(d tree.Datum, c *columnBuffer) error {
+	c.writeFloat(float32(*d.(*tree.DFloat)))
+	return nil
+}
+
+func encodeDouble(d tree.Datum, c *columnBuffer) error {
+	c.writeDouble(float64(*d.(*tree.DFloat)))
+	return nil
+}
+
+func encodeString(d tree.Datum, c *columnBuffer) error {
+	switch t := d.(type) {
+	case *tree.DString:
+		c.writeByteArray([]byte(*t))
+	case *tree.DCollatedString:
+		c.writeByteArray([]byte(t.Contents))
+	default:
+		return errors.AssertionFailedf("unexpected string datum %T", d)
+	}
+	return nil
+}
+
+func encodeBytes(d tree.Datum, c *columnBuffer) error {
+	c.writeByteArray([]byte(tree.MustBeDBytes(d)))
+	return nil
+}
+
+func encodeUUID(d tree.Datum, c *columnBuffer) error {
+	c.writeFixedLenByteArray(d.(*tree.DUuid).GetBytes())
+	return nil
+}
+
+func encodeDate(d tree.Datum, c *columnBuffer) error {
+	date := d.(*tree.DDate)
+	if !date.IsFinite() {
+		return errors.Errorf("cannot write infinite date %s to parquet", date)
+	}
+	c.writeInt32(int32(date.UnixEpochDays()))
+	return nil
+}
+
+func encodeTime(d tree.Datum, c *columnBuffer) error {
+	// TimeOfDay is the number of microseconds since midnight.
+	c.writeInt64(int64(*d.(*tree.DTime)))
+	return nil
+}
+
+func encodeTimestamp(d tree.Datum, c *columnBuffer) error {
+	c.writeInt64(unixMicros(d.(*tree.DTimestamp).Time))
+	return nil
+}
+
+func encodeTimestampTZ(d tree.Datum, c *columnBuffer) error {
+	c.writeInt64(unixMicros(d.(*tree.DTimestampTZ).Time))
+	return nil
+}
+
+// unixMicros returns the number of microseconds since the Unix epoch. Unlike
+// time.Time.UnixNano, it does not overflow for the years after 2262 which SQL
+// timestamps can hold.
+func unixMicros(t time.Time) int64 {
+	return t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+}
+
+func encodeJSON(d tree.Datum, c *columnBuffer) error {
+	c.writeByteArray([]byte(d.(*tree.DJSON).JSON.String()))
+	return nil
+}
+
+func encodeEnum(d tree.Datum, c *columnBuffer) error {
+	c.writeByteArray([]byte(d.(*tree.DEnum).LogicalRep))
+	return nil
+}
+
+// encodeFormatted writes the datum as a string in the format used by EXPORT
+// to CSV.
+func encodeFormatted(d tree.Datum, c *columnBuffer) error {
+	c.writeByteArray([]byte(tree.AsStringWithFlags(d, tree.FmtExport)))
+	return nil
+}
+
+// makeEncodeDecimal returns a function which writes decimals as the big-endian
+// two's complement representation of their unscaled value at the given scale.
+func makeEncodeDecimal(scale int32) encodeFn {
+	return func(d tree.Datum, c *columnBuffer) error {
+		dec := &d.(*tree.DDecimal).Decimal
+		if dec.Form != apd.Finite {
+			return errors.Errorf("cannot write decimal %s to parquet", dec)
+		}
+		unscaled := new(big.Int).Set(&dec.Coeff)
+		// Values in a DECIMAL(p,s) column are already rounded to s digits after
+		// the decimal point, so rescaling is exact.
+		if shift := int64(dec.Exponent) + int64(scale); shift > 0 {
+			unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(shift), nil))
+		} else if shift < 0 {
+			unscaled.Quo(unscaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(-shift), nil))
+		}
+		if dec.Negative {
+			unscaled.Neg(unscaled)
+		}
+		c.writeByteArray(twosComplement(unscaled))
+		return nil
+	}
+}
+
+// twosComplement returns the big-endian two's complement encoding of v.
+func twosComplement(v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			// Make room for the sign bit.
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// For negative numbers, compute 2^(8n) + v for the smallest n such that the
+	// result has its sign bit set.
+	n := (v.BitLen() + 8) / 8
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	return new(big.Int).Add(mod, v).Bytes()
+}
@@ -0,0 +1,36 @@
+# Copyright 2021 The Cockroach Authors.
+#
+# Use of this software is governed by the Business Source License
+# included in the file licenses/BSL.txt.
+#
+# As of the Change Date specified in that file, in accordance with
+# the Business Source License, use of this software will be governed
+# by the Apache License, Version 2.0, included in the file
+# licenses/APL.txt.
+
+# read_parquet.py reads a parquet file with pyarrow and prints the logical type
+# of every leaf column on the first line, followed by one JSON object per row.
+# It is used by TestWriterExternalReader.
+
+import datetime
+import decimal
+import json
+import sys
+
+import pyarrow.parquet as pq
+
+
+def default(v):
+    if isinstance(v, decimal.Decimal):
+        return str(v)
+    if isinstance(v, datetime.datetime):
+        return v.isoformat()
+    if isinstance(v, bytes):
+        return v.decode('utf-8')
+    raise TypeError(repr(v))
+
+
+f = pq.ParquetFile(sys.argv[1])
+print(json.dumps([f.schema.column(i).logical_type.type for i in range(len(f.schema))]))
+for row in f.read().to_pylist():
+    print(json.dumps(row, default=default, sort_keys=True))
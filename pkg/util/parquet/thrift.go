@@ -0,0 +1,425 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Parquet stores its metadata (the file footer and page headers) as Thrift
+// structs serialized with the Thrift compact protocol. Only the handful of
+// structs needed to write a file are implemented here, and only their write
+// paths. See parquet.thrift in the apache/parquet-format repository for the
+// authoritative definitions; field IDs below refer to that file.
+
+// Thrift compact protocol type IDs.
+const (
+	thriftBoolTrue   = 1
+	thriftBoolFalse  = 2
+	thriftByte       = 3
+	thriftI32        = 5
+	thriftI64        = 6
+	thriftBinary     = 8
+	thriftList       = 9
+	thriftTypeStruct = 12
+)
+
+// compactWriter serializes values using the Thrift compact protocol.
+type compactWriter struct {
+	buf bytes.Buffer
+	// lastFieldID is the ID of the last field written in the struct currently
+	// being serialized. Field headers are delta encoded against it.
+	lastFieldID int16
+	// fieldIDStack saves lastFieldID of enclosing structs.
+	fieldIDStack []int16
+	scratch      [binary.MaxVarintLen64]byte
+}
+
+func (w *compactWriter) writeUvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *compactWriter) writeVarint(v int64) {
+	// Thrift uses zigzag encoding, which matches encoding/binary.
+	n := binary.PutVarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - w.lastFieldID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.writeVarint(int64(id))
+	}
+	w.lastFieldID = id
+}
+
+func (w *compactWriter) structBegin() {
+	w.fieldIDStack = append(w.fieldIDStack, w.lastFieldID)
+	w.lastFieldID = 0
+}
+
+func (w *compactWriter) structEnd() {
+	w.buf.WriteByte(0) // STOP
+	w.lastFieldID = w.fieldIDStack[len(w.fieldIDStack)-1]
+	w.fieldIDStack = w.fieldIDStack[:len(w.fieldIDStack)-1]
+}
+
+func (w *compactWriter) listHeader(size int, elemType byte) {
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.writeUvarint(uint64(size))
+	}
+}
+
+func (w *compactWriter) boolField(id int16, v bool) {
+	if v {
+		w.fieldHeader(id, thriftBoolTrue)
+	} else {
+		w.fieldHeader(id, thriftBoolFalse)
+	}
+}
+
+func (w *compactWriter) byteField(id int16, v int8) {
+	w.fieldHeader(id, thriftByte)
+	w.buf.WriteByte(byte(v))
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.writeVarint(int64(v))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.writeVarint(v)
+}
+
+func (w *compactWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.writeUvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// emptyStructField writes a field holding a struct with no fields, which is
+// how Thrift unions select a member without a payload.
+func (w *compactWriter) emptyStructField(id int16) {
+	w.fieldHeader(id, thriftTypeStruct)
+	w.buf.WriteByte(0) // STOP
+}
+
+func (w *compactWriter) structField(id int16, s thriftStruct) {
+	w.fieldHeader(id, thriftTypeStruct)
+	w.structBegin()
+	s.write(w)
+	w.structEnd()
+}
+
+func (w *compactWriter) i32ListField(id int16, vs []int32) {
+	w.fieldHeader(id, thriftList)
+	w.listHeader(len(vs), thriftI32)
+	for _, v := range vs {
+		w.writeVarint(int64(v))
+	}
+}
+
+func (w *compactWriter) stringListField(id int16, vs []string) {
+	w.fieldHeader(id, thriftList)
+	w.listHeader(len(vs), thriftBinary)
+	for _, v := range vs {
+		w.writeUvarint(uint64(len(v)))
+		w.buf.WriteString(v)
+	}
+}
+
+func (w *compactWriter) structListField(id int16, n int, elem func(i int) thriftStruct) {
+	w.fieldHeader(id, thriftList)
+	w.listHeader(n, thriftTypeStruct)
+	for i := 0; i < n; i++ {
+		w.structBegin()
+		elem(i).write(w)
+		w.structEnd()
+	}
+}
+
+// writeStruct serializes s as a top-level struct.
+func (w *compactWriter) writeStruct(s thriftStruct) {
+	w.structBegin()
+	s.write(w)
+	w.structEnd()
+}
+
+// thriftStruct is implemented by the metadata structs below.
+type thriftStruct interface {
+	write(w *compactWriter)
+}
+
+// physicalType is the Type enum of parquet.thrift.
+type physicalType int32
+
+const (
+	physicalTypeBoolean           physicalType = 0
+	physicalTypeInt32             physicalType = 1
+	physicalTypeInt64             physicalType = 2
+	physicalTypeFloat             physicalType = 4
+	physicalTypeDouble            physicalType = 5
+	physicalTypeByteArray         physicalType = 6
+	physicalTypeFixedLenByteArray physicalType = 7
+)
+
+// convertedType is the ConvertedType enum of parquet.thrift. It is the
+// predecessor of LogicalType and is still written for the benefit of older
+// readers.
+type convertedType int32
+
+const (
+	convertedTypeNone convertedType = -1
+
+	convertedTypeUTF8            convertedType = 0
+	convertedTypeList            convertedType = 3
+	convertedTypeEnum            convertedType = 4
+	convertedTypeDecimal         convertedType = 5
+	convertedTypeDate            convertedType = 6
+	convertedTypeTimestampMicros convertedType = 10
+	convertedTypeInt16           convertedType = 16
+	convertedTypeInt32           convertedType = 17
+	convertedTypeInt64           convertedType = 18
+	convertedTypeJSON            convertedType = 19
+)
+
+// repetitionType is the FieldRepetitionType enum of parquet.thrift.
+type repetitionType int32
+
+const (
+	repetitionRequired repetitionType = 0
+	repetitionOptional repetitionType = 1
+	repetitionRepeated repetitionType = 2
+)
+
+// Encoding enum values of parquet.thrift.
+const (
+	encodingPlain int32 = 0
+	encodingRLE   int32 = 3
+)
+
+// pageTypeDataPage is the DATA_PAGE member of the PageType enum.
+const pageTypeDataPage int32 = 0
+
+// logicalTypeKind selects the member of the LogicalType union. The values are
+// the field IDs of the union members.
+type logicalTypeKind int16
+
+const (
+	logicalTypeNone      logicalTypeKind = 0
+	logicalTypeString    logicalTypeKind = 1
+	logicalTypeList      logicalTypeKind = 3
+	logicalTypeEnum      logicalTypeKind = 4
+	logicalTypeDecimal   logicalTypeKind = 5
+	logicalTypeDate      logicalTypeKind = 6
+	logicalTypeTime      logicalTypeKind = 7
+	logicalTypeTimestamp logicalTypeKind = 8
+	logicalTypeInteger   logicalTypeKind = 10
+	logicalTypeJSON      logicalTypeKind = 12
+	logicalTypeUUID      logicalTypeKind = 14
+)
+
+// logicalType is the LogicalType union of parquet.thrift. Only the fields
+// relevant to kind are used.
+type logicalType struct {
+	kind logicalTypeKind
+	// DECIMAL.
+	scale, precision int32
+	// TIME and TIMESTAMP. The unit is always microseconds.
+	isAdjustedToUTC bool
+	// INTEGER.
+	bitWidth int8
+}
+
+func (l logicalType) write(w *compactWriter) {
+	switch l.kind {
+	case logicalTypeDecimal:
+		w.structField(int16(l.kind), decimalType{scale: l.scale, precision: l.precision})
+	case logicalTypeTime, logicalTypeTimestamp:
+		w.structField(int16(l.kind), timeType{isAdjustedToUTC: l.isAdjustedToUTC})
+	case logicalTypeInteger:
+		w.structField(int16(l.kind), intType{bitWidth: l.bitWidth})
+	default:
+		w.emptyStructField(int16(l.kind))
+	}
+}
+
+type decimalType struct {
+	scale, precision int32
+}
+
+func (d decimalType) write(w *compactWriter) {
+	w.i32Field(1, d.scale)
+	w.i32Field(2, d.precision)
+}
+
+// timeType is both TimeType and TimestampType, which have the same layout.
+type timeType struct {
+	isAdjustedToUTC bool
+}
+
+func (t timeType) write(w *compactWriter) {
+	w.boolField(1, t.isAdjustedToUTC)
+	// The unit is a TimeUnit union; field 2 of the union is MICROS.
+	w.fieldHeader(2, thriftTypeStruct)
+	w.structBegin()
+	w.emptyStructField(2)
+	w.structEnd()
+}
+
+type intType struct {
+	bitWidth int8
+}
+
+func (t intType) write(w *compactWriter) {
+	w.byteField(1, t.bitWidth)
+	w.boolField(2, true /* isSigned */)
+}
+
+// schemaElement is the SchemaElement struct of parquet.thrift.
+type schemaElement struct {
+	name        string
+	typ         physicalType
+	typeLength  int32
+	repetition  repetitionType
+	numChildren int32
+	converted   convertedType
+	logical     logicalType
+	// isGroup is set for elements that have children (and thus no type).
+	isGroup bool
+	// isRoot is set for the root of the schema, which has no repetition.
+	isRoot bool
+}
+
+func (s schemaElement) write(w *compactWriter) {
+	if !s.isGroup {
+		w.i32Field(1, int32(s.typ))
+		if s.typ == physicalTypeFixedLenByteArray {
+			w.i32Field(2, s.typeLength)
+		}
+	}
+	if !s.isRoot {
+		w.i32Field(3, int32(s.repetition))
+	}
+	w.stringField(4, s.name)
+	if s.isGroup {
+		w.i32Field(5, s.numChildren)
+	}
+	if s.converted != convertedTypeNone {
+		w.i32Field(6, int32(s.converted))
+		if s.converted == convertedTypeDecimal {
+			w.i32Field(7, s.logical.scale)
+			w.i32Field(8, s.logical.precision)
+		}
+	}
+	if s.logical.kind != logicalTypeNone {
+		w.structField(10, s.logical)
+	}
+}
+
+// dataPageHeader is the DataPageHeader struct of parquet.thrift.
+type dataPageHeader struct {
+	numValues int32
+}
+
+func (h dataPageHeader) write(w *compactWriter) {
+	w.i32Field(1, h.numValues)
+	w.i32Field(2, encodingPlain)
+	w.i32Field(3, encodingRLE) // definition_level_encoding
+	w.i32Field(4, encodingRLE) // repetition_level_encoding
+}
+
+// pageHeader is the PageHeader struct of parquet.thrift. Only data pages are
+// ever written.
+type pageHeader struct {
+	uncompressedSize int32
+	compressedSize   int32
+	data             dataPageHeader
+}
+
+func (h pageHeader) write(w *compactWriter) {
+	w.i32Field(1, pageTypeDataPage)
+	w.i32Field(2, h.uncompressedSize)
+	w.i32Field(3, h.compressedSize)
+	w.structField(5, h.data)
+}
+
+// columnMetaData is the ColumnMetaData struct of parquet.thrift.
+type columnMetaData struct {
+	typ                   physicalType
+	path                  []string
+	codec                 CompressionCodec
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+}
+
+func (m columnMetaData) write(w *compactWriter) {
+	w.i32Field(1, int32(m.typ))
+	w.i32ListField(2, []int32{encodingPlain, encodingRLE})
+	w.stringListField(3, m.path)
+	w.i32Field(4, int32(m.codec))
+	w.i64Field(5, m.numValues)
+	w.i64Field(6, m.totalUncompressedSize)
+	w.i64Field(7, m.totalCompressedSize)
+	w.i64Field(9, m.dataPageOffset)
+}
+
+// columnChunk is the ColumnChunk struct of parquet.thrift.
+type columnChunk struct {
+	fileOffset int64
+	meta       columnMetaData
+}
+
+func (c columnChunk) write(w *compactWriter) {
+	w.i64Field(2, c.fileOffset)
+	w.structField(3, c.meta)
+}
+
+// rowGroup is the RowGroup struct of parquet.thrift.
+type rowGroup struct {
+	columns       []columnChunk
+	totalByteSize int64
+	numRows       int64
+}
+
+func (g rowGroup) write(w *compactWriter) {
+	w.structListField(1, len(g.columns), func(i int) thriftStruct { return g.columns[i] })
+	w.i64Field(2, g.totalByteSize)
+	w.i64Field(3, g.numRows)
+}
+
+// fileMetaData is the FileMetaData struct of parquet.thrift, which makes up
+// the file footer.
+type fileMetaData struct {
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+	createdBy string
+}
+
+func (m fileMetaData) write(w *compactWriter) {
+	w.i32Field(1, 1 /* version */)
+	w.structListField(2, len(m.schema), func(i int) thriftStruct { return m.schema[i] })
+	w.i64Field(3, m.numRows)
+	w.structListField(4, len(m.rowGroups), func(i int) thriftStruct { return m.rowGroups[i] })
+	w.stringField(6, m.createdBy)
+}
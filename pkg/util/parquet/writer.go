@@ -0,0 +1,288 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet writes SQL rows to files in the Apache Parquet format.
+//
+// The writer is deliberately simple: every column chunk is made of a single
+// PLAIN encoded data page, with definition and repetition levels RLE encoded,
+// and pages are optionally compressed with snappy or gzip. It does not write
+// dictionary pages, statistics or indexes, which are optional in the format.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// CompressionCodec is the codec used to compress the pages of a file. The
+// values match the CompressionCodec enum of the parquet format.
+type CompressionCodec int32
+
+const (
+	// CompressionNone leaves pages uncompressed.
+	CompressionNone CompressionCodec = 0
+	// CompressionSnappy compresses pages with snappy.
+	CompressionSnappy CompressionCodec = 1
+	// CompressionGzip compresses pages with gzip.
+	CompressionGzip CompressionCodec = 2
+)
+
+// DefaultMaxRowGroupLength is the default number of rows in a row group.
+const DefaultMaxRowGroupLength = 64 << 10
+
+var magic = []byte("PAR1")
+
+type config struct {
+	compression       CompressionCodec
+	maxRowGroupLength int64
+}
+
+// Option configures a Writer.
+type Option func(*config)
+
+// WithCompressionCodec sets the codec used to compress pages.
+func WithCompressionCodec(codec CompressionCodec) Option {
+	return func(c *config) { c.compression = codec }
+}
+
+// WithMaxRowGroupLength sets the maximum number of rows in a row group. Row
+// groups are buffered in memory until they are full, so this bounds the
+// memory used by the writer.
+func WithMaxRowGroupLength(n int64) Option {
+	return func(c *config) { c.maxRowGroupLength = n }
+}
+
+// Writer writes rows to a parquet file. Rows are buffered until a row group is
+// full, at which point the row group is written to the sink. The file is only
+// complete, and readable, once Close has been called.
+//
+// A Writer must not be used after any of its methods returned an error.
+type Writer struct {
+	sch  *SchemaDefinition
+	sink io.Writer
+	cfg  config
+
+	// offset is the number of bytes written to sink so far.
+	offset    int64
+	cols      []columnBuffer
+	groupRows int64
+	numRows   int64
+	rowGroups []rowGroup
+	closed    bool
+}
+
+// NewWriter returns a Writer that writes rows with the given schema to sink.
+func NewWriter(sch *SchemaDefinition, sink io.Writer, opts ...Option) (*Writer, error) {
+	cfg := config{
+		compression:       CompressionNone,
+		maxRowGroupLength: DefaultMaxRowGroupLength,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	switch cfg.compression {
+	case CompressionNone, CompressionSnappy, CompressionGzip:
+	default:
+		return nil, errors.Errorf("unsupported compression codec %d", cfg.compression)
+	}
+	if cfg.maxRowGroupLength <= 0 {
+		return nil, errors.Errorf("invalid max row group length %d", cfg.maxRowGroupLength)
+	}
+
+	w := &Writer{
+		sch:  sch,
+		sink: sink,
+		cfg:  cfg,
+		cols: make([]columnBuffer, len(sch.cols)),
+	}
+	for i := range sch.cols {
+		w.cols[i] = columnBuffer{
+			physical:    sch.cols[i].leaf().typ,
+			maxDefLevel: sch.cols[i].maxDefLevel,
+			maxRepLevel: sch.cols[i].maxRepLevel,
+		}
+	}
+	if err := w.write(magic); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddRow adds a row to the file. The datums must match the types of the
+// schema.
+func (w *Writer) AddRow(datums tree.Datums) error {
+	if w.closed {
+		return errors.AssertionFailedf("cannot add a row to a closed parquet writer")
+	}
+	if len(datums) != len(w.sch.cols) {
+		return errors.AssertionFailedf(
+			"expected %d datums, got %d", len(w.sch.cols), len(datums))
+	}
+	for i, d := range datums {
+		col, buf := &w.sch.cols[i], &w.cols[i]
+		if d == tree.DNull {
+			buf.addLevels(0, defLevelNull)
+			continue
+		}
+		d = tree.UnwrapDatum(nil /* evalCtx */, d)
+		if !col.isArray {
+			buf.addLevels(0, defLevelScalar)
+			if err := col.encode(d, buf); err != nil {
+				return err
+			}
+			continue
+		}
+
+		arr, ok := d.(*tree.DArray)
+		if !ok {
+			return errors.AssertionFailedf("expected array datum, got %T", d)
+		}
+		if arr.Len() == 0 {
+			buf.addLevels(0, defLevelEmptyArray)
+			continue
+		}
+		for j, elem := range arr.Array {
+			// The first element of a list starts a new record, subsequent
+			// elements repeat the list.
+			var repLevel uint8
+			if j > 0 {
+				repLevel = 1
+			}
+			if elem == tree.DNull {
+				buf.addLevels(repLevel, defLevelNullElement)
+				continue
+			}
+			buf.addLevels(repLevel, defLevelArrayElement)
+			if err := col.encode(tree.UnwrapDatum(nil /* evalCtx */, elem), buf); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.groupRows++
+	w.numRows++
+	if w.groupRows >= w.cfg.maxRowGroupLength {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes any buffered rows and the file footer. It does not close the
+// sink.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.flushRowGroup(); err != nil {
+		return err
+	}
+
+	var meta compactWriter
+	meta.writeStruct(fileMetaData{
+		schema:    w.sch.schemaElements(),
+		numRows:   w.numRows,
+		rowGroups: w.rowGroups,
+		createdBy: "CockroachDB " + build.GetInfo().Tag,
+	})
+	if err := w.write(meta.buf.Bytes()); err != nil {
+		return err
+	}
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(meta.buf.Len()))
+	if err := w.write(footerLen[:]); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.sink.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// flushRowGroup writes the buffered rows, if any, as a row group.
+func (w *Writer) flushRowGroup() error {
+	if w.groupRows == 0 {
+		return nil
+	}
+	group := rowGroup{
+		columns: make([]columnChunk, len(w.cols)),
+		numRows: w.groupRows,
+	}
+	for i := range w.cols {
+		buf := &w.cols[i]
+		page := buf.encodePage()
+		compressed, err := w.compress(page)
+		if err != nil {
+			return err
+		}
+
+		var header compactWriter
+		header.writeStruct(pageHeader{
+			uncompressedSize: int32(len(page)),
+			compressedSize:   int32(len(compressed)),
+			data:             dataPageHeader{numValues: int32(buf.numValues())},
+		})
+
+		chunkOffset := w.offset
+		if err := w.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := w.write(compressed); err != nil {
+			return err
+		}
+		uncompressedSize := int64(header.buf.Len() + len(page))
+		group.columns[i] = columnChunk{
+			fileOffset: chunkOffset,
+			meta: columnMetaData{
+				typ:                   buf.physical,
+				path:                  w.sch.cols[i].path,
+				codec:                 w.cfg.compression,
+				numValues:             int64(buf.numValues()),
+				totalUncompressedSize: uncompressedSize,
+				totalCompressedSize:   w.offset - chunkOffset,
+				dataPageOffset:        chunkOffset,
+			},
+		}
+		group.totalByteSize += uncompressedSize
+		buf.reset()
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.groupRows = 0
+	return nil
+}
+
+func (w *Writer) compress(page []byte) ([]byte, error) {
+	switch w.cfg.compression {
+	case CompressionSnappy:
+		return snappy.Encode(nil, page), nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(page); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return page, nil
+	}
+}
@@ -0,0 +1,512 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact protocol structs into maps from field ID
+// to value. It is just enough to read back the files written by Writer.
+type thriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftReader) uvarint(t *testing.T) uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	require.True(t, n > 0)
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint(t *testing.T) int64 {
+	v, n := binary.Varint(r.b[r.pos:])
+	require.True(t, n > 0)
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) value(t *testing.T, typ byte) interface{} {
+	switch typ {
+	case thriftBoolTrue:
+		return true
+	case thriftBoolFalse:
+		return false
+	case thriftByte:
+		r.pos++
+		return int64(int8(r.b[r.pos-1]))
+	case thriftI32, thriftI64:
+		return r.varint(t)
+	case thriftBinary:
+		n := int(r.uvarint(t))
+		r.pos += n
+		return string(r.b[r.pos-n : r.pos])
+	case thriftList:
+		header := r.b[r.pos]
+		r.pos++
+		size, elemType := int(header>>4), header&0x0f
+		if size == 15 {
+			size = int(r.uvarint(t))
+		}
+		l := make([]interface{}, size)
+		for i := range l {
+			l[i] = r.value(t, elemType)
+		}
+		return l
+	case thriftTypeStruct:
+		return r.readStruct(t)
+	default:
+		t.Fatalf("unexpected thrift type %d", typ)
+		return nil
+	}
+}
+
+func (r *thriftReader) readStruct(t *testing.T) map[int16]interface{} {
+	s := make(map[int16]interface{})
+	var lastID int16
+	for {
+		header := r.b[r.pos]
+		r.pos++
+		if header == 0 {
+			return s
+		}
+		typ := header & 0x0f
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint(t))
+		}
+		s[id] = r.value(t, typ)
+		lastID = id
+	}
+}
+
+type testColumn struct {
+	path []string
+	typ  physicalType
+	// rows holds, per row, nil for NULL, the decoded value for scalars, and a
+	// []interface{} for arrays.
+	rows []interface{}
+}
+
+type testFile struct {
+	numRows   int64
+	schema    []map[int16]interface{}
+	rowGroups int
+	columns   []testColumn
+}
+
+func readTestFile(t *testing.T, b []byte) testFile {
+	require.Equal(t, magic, b[:4])
+	require.Equal(t, magic, b[len(b)-4:])
+	footerLen := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	footer := &thriftReader{b: b[len(b)-8-footerLen : len(b)-8]}
+	meta := footer.readStruct(t)
+	require.Equal(t, footerLen, footer.pos)
+
+	var f testFile
+	f.numRows = meta[3].(int64)
+	for _, s := range meta[2].([]interface{}) {
+		f.schema = append(f.schema, s.(map[int16]interface{}))
+	}
+	for _, g := range meta[4].([]interface{}) {
+		f.rowGroups++
+		group := g.(map[int16]interface{})
+		var prevRows int
+		if len(f.columns) > 0 {
+			prevRows = len(f.columns[0].rows)
+		}
+		for i, c := range group[1].([]interface{}) {
+			chunkMeta := c.(map[int16]interface{})[3].(map[int16]interface{})
+			if len(f.columns) <= i {
+				col := testColumn{typ: physicalType(chunkMeta[1].(int64))}
+				for _, p := range chunkMeta[3].([]interface{}) {
+					col.path = append(col.path, p.(string))
+				}
+				f.columns = append(f.columns, col)
+			}
+			rows := readTestChunk(t, b, chunkMeta, f.columns[i])
+			f.columns[i].rows = append(f.columns[i].rows, rows...)
+		}
+		require.Equal(t, group[3].(int64), int64(len(f.columns[0].rows)-prevRows))
+	}
+	return f
+}
+
+func readTestChunk(
+	t *testing.T, b []byte, meta map[int16]interface{}, col testColumn,
+) []interface{} {
+	r := &thriftReader{b: b, pos: int(meta[9].(int64))}
+	header := r.readStruct(t)
+	require.Equal(t, int64(pageTypeDataPage), header[1].(int64))
+	compressed := b[r.pos : r.pos+int(header[3].(int64))]
+	require.Equal(t, meta[7].(int64), int64(r.pos)+int64(len(compressed))-meta[9].(int64))
+
+	var page []byte
+	switch CompressionCodec(meta[4].(int64)) {
+	case CompressionNone:
+		page = compressed
+	case CompressionSnappy:
+		var err error
+		page, err = snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+	case CompressionGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		page, err = ioutil.ReadAll(gz)
+		require.NoError(t, err)
+	}
+	require.Equal(t, header[2].(int64), int64(len(page)))
+
+	numValues := int(header[5].(map[int16]interface{})[1].(int64))
+	require.Equal(t, meta[5].(int64), int64(numValues))
+	isArray := len(col.path) == 3
+	maxDefLevel := uint8(defLevelScalar)
+	var repLevels []uint8
+	if isArray {
+		maxDefLevel = defLevelArrayElement
+		repLevels, page = readTestLevels(t, page, numValues)
+	}
+	defLevels, page := readTestLevels(t, page, numValues)
+
+	var values []interface{}
+	for _, l := range defLevels {
+		if l == maxDefLevel {
+			values = append(values, nil)
+		}
+	}
+	if col.typ == physicalTypeBoolean {
+		for i := range values {
+			values[i] = page[i/8]&(1<<(uint(i)%8)) != 0
+		}
+	} else {
+		for i := range values {
+			switch col.typ {
+			case physicalTypeInt32:
+				values[i] = int64(int32(binary.LittleEndian.Uint32(page)))
+				page = page[4:]
+			case physicalTypeInt64:
+				values[i] = int64(binary.LittleEndian.Uint64(page))
+				page = page[8:]
+			case physicalTypeFloat:
+				values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(page)))
+				page = page[4:]
+			case physicalTypeDouble:
+				values[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+				page = page[8:]
+			case physicalTypeByteArray:
+				n := int(binary.LittleEndian.Uint32(page))
+				values[i] = string(page[4 : 4+n])
+				page = page[4+n:]
+			case physicalTypeFixedLenByteArray:
+				values[i] = string(page[:16])
+				page = page[16:]
+			}
+		}
+		require.Len(t, page, 0)
+	}
+
+	var rows []interface{}
+	for i, l := range defLevels {
+		var v interface{}
+		if l == maxDefLevel {
+			v, values = values[0], values[1:]
+		}
+		if !isArray {
+			rows = append(rows, v)
+			continue
+		}
+		if repLevels[i] == 1 {
+			rows[len(rows)-1] = append(rows[len(rows)-1].([]interface{}), v)
+			continue
+		}
+		switch l {
+		case defLevelNull:
+			rows = append(rows, nil)
+		case defLevelEmptyArray:
+			rows = append(rows, []interface{}{})
+		default:
+			rows = append(rows, []interface{}{v})
+		}
+	}
+	return rows
+}
+
+// readTestLevels decodes levels written with RLE runs only.
+func readTestLevels(t *testing.T, page []byte, numValues int) ([]uint8, []byte) {
+	n := int(binary.LittleEndian.Uint32(page))
+	r := &thriftReader{b: page[4 : 4+n]}
+	var levels []uint8
+	for r.pos < len(r.b) {
+		header := r.uvarint(t)
+		require.Equal(t, uint64(0), header&1, "unexpected bit-packed run")
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, r.b[r.pos])
+		}
+		r.pos++
+	}
+	require.Len(t, levels, numValues)
+	return levels, page[4+n:]
+}
+
+func TestWriter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC)
+	names := []string{"b", "i", "i4", "f", "s", "by", "tstz", "arr"}
+	typs := []*types.T{
+		types.Bool, types.Int, types.Int4, types.Float, types.String, types.Bytes,
+		types.TimestampTZ, types.MakeArray(types.Int),
+	}
+	rows := []tree.Datums{
+		{
+			tree.DBoolTrue, tree.NewDInt(1), tree.NewDInt(-2), tree.NewDFloat(1.5),
+			tree.NewDString("a"), tree.NewDBytes("b"), tree.MustMakeDTimestampTZ(ts, time.Microsecond),
+			&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{tree.NewDInt(1), tree.DNull, tree.NewDInt(3)}},
+		},
+		{
+			tree.DNull, tree.DNull, tree.DNull, tree.DNull,
+			tree.DNull, tree.DNull, tree.DNull,
+			tree.DNull,
+		},
+		{
+			tree.DBoolFalse, tree.NewDInt(math.MaxInt64), tree.NewDInt(math.MinInt32), tree.NewDFloat(-2),
+			tree.NewDString(""), tree.NewDBytes(""), tree.MustMakeDTimestampTZ(time.Unix(0, 0), time.Microsecond),
+			&tree.DArray{ParamTyp: types.Int},
+		},
+		{
+			tree.DBoolTrue, tree.NewDInt(4), tree.NewDInt(5), tree.NewDFloat(6),
+			tree.NewDString("☃"), tree.NewDBytes("\x00"), tree.DNull,
+			&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{tree.NewDInt(7)}},
+		},
+		{
+			tree.DBoolFalse, tree.NewDInt(8), tree.NewDInt(9), tree.NewDFloat(10),
+			tree.NewDString("c"), tree.NewDBytes("d"), tree.DNull,
+			&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{tree.DNull}},
+		},
+	}
+	expected := [][]interface{}{
+		{true, nil, false, true, false},
+		{int64(1), nil, int64(math.MaxInt64), int64(4), int64(8)},
+		{int64(-2), nil, int64(math.MinInt32), int64(5), int64(9)},
+		{1.5, nil, -2.0, 6.0, 10.0},
+		{"a", nil, "", "☃", "c"},
+		{"b", nil, "", "\x00", "d"},
+		{ts.UnixNano() / 1000, nil, int64(0), nil, nil},
+		{
+			[]interface{}{int64(1), nil, int64(3)},
+			nil,
+			[]interface{}{},
+			[]interface{}{int64(7)},
+			[]interface{}{nil},
+		},
+	}
+
+	sch, err := NewSchema(names, typs)
+	require.NoError(t, err)
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionGzip} {
+		t.Run(fmt.Sprintf("codec=%d", codec), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(sch, &buf, WithCompressionCodec(codec), WithMaxRowGroupLength(2))
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			f := readTestFile(t, buf.Bytes())
+			require.Equal(t, int64(len(rows)), f.numRows)
+			require.Equal(t, 3, f.rowGroups)
+			require.Len(t, f.columns, len(names))
+			for i := range f.columns {
+				require.Equal(t, expected[i], f.columns[i].rows, "column %s", names[i])
+			}
+
+			// The root, one element per scalar column and three for the array.
+			require.Len(t, f.schema, 1+len(names)-1+3)
+			require.Equal(t, "schema", f.schema[0][4])
+			require.Equal(t, int64(len(names)), f.schema[0][5])
+			require.Equal(t, "arr", f.schema[8][4])
+			require.Equal(t, int64(convertedTypeList), f.schema[8][6])
+			require.Equal(t, int64(repetitionRepeated), f.schema[9][3])
+			require.Equal(t, "element", f.schema[10][4])
+			require.Equal(t, []string{"arr", "list", "element"}, f.columns[7].path)
+		})
+	}
+}
+
+// TestWriterExternalReader checks that files written by Writer are read back
+// correctly by pyarrow, which is the reference implementation most consumers
+// of EXPORT and changefeed files use. The reader used by the other tests only
+// knows what this package writes, so it can't catch misreadings of the
+// specification.
+func TestWriterExternalReader(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	if err := exec.Command("python3", "-c", "import pyarrow").Run(); err != nil {
+		skip.IgnoreLint(t, "python3 with pyarrow is not available")
+	}
+
+	enumTyp := types.MakeEnum(100100, 100101)
+	enumTyp.TypeMeta = types.UserDefinedTypeMetadata{
+		Name: &types.UserDefinedTypeName{Name: "mood"},
+		EnumData: &types.EnumMetadata{
+			LogicalRepresentations:  []string{"sad", "happy"},
+			PhysicalRepresentations: [][]byte{{0x40}, {0x80}},
+			IsMemberReadOnly:        []bool{false, false},
+		},
+	}
+	makeDecimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+	makeJSON := func(s string) tree.Datum {
+		d, err := tree.ParseDJSON(s)
+		require.NoError(t, err)
+		return d
+	}
+	makeEnum := func(s string) tree.Datum {
+		d, err := tree.MakeDEnumFromLogicalRepresentation(enumTyp, s)
+		require.NoError(t, err)
+		return d
+	}
+
+	names := []string{"d", "tstz", "arr", "j", "e", "strs"}
+	typs := []*types.T{
+		types.MakeDecimal(10, 2), types.TimestampTZ, types.MakeArray(types.Int), types.Jsonb,
+		enumTyp, types.MakeArray(types.String),
+	}
+	rows := []tree.Datums{
+		{
+			makeDecimal("12.34"),
+			tree.MustMakeDTimestampTZ(time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC), time.Microsecond),
+			&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{tree.NewDInt(1), tree.DNull, tree.NewDInt(3)}},
+			makeJSON(`{"a": [1, 2]}`),
+			makeEnum("sad"),
+			&tree.DArray{ParamTyp: types.String, Array: tree.Datums{tree.NewDString("x"), tree.DNull}},
+		},
+		{tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull, tree.DNull},
+		{
+			makeDecimal("-12345678.90"),
+			tree.MustMakeDTimestampTZ(time.Unix(0, 0), time.Microsecond),
+			&tree.DArray{ParamTyp: types.Int},
+			makeJSON(`"s"`),
+			makeEnum("happy"),
+			&tree.DArray{ParamTyp: types.String},
+		},
+		{
+			makeDecimal("-0.05"),
+			tree.DNull,
+			&tree.DArray{ParamTyp: types.Int, Array: tree.Datums{tree.DNull}},
+			makeJSON(`null`),
+			tree.DNull,
+			&tree.DArray{ParamTyp: types.String, Array: tree.Datums{tree.NewDString("☃")}},
+		},
+	}
+	expected := []string{
+		`["DECIMAL", "TIMESTAMP", "INT", "JSON", "ENUM", "STRING"]`,
+		`{"arr": [1, null, 3], "d": "12.34", "e": "sad", "j": "{\"a\": [1, 2]}", ` +
+			`"strs": ["x", null], "tstz": "2021-03-04T05:06:07.000008+00:00"}`,
+		`{"arr": null, "d": null, "e": null, "j": null, "strs": null, "tstz": null}`,
+		`{"arr": [], "d": "-12345678.90", "e": "happy", "j": "\"s\"", "strs": [], ` +
+			`"tstz": "1970-01-01T00:00:00+00:00"}`,
+		`{"arr": [null], "d": "-0.05", "e": null, "j": "null", "strs": ["\u2603"], "tstz": null}`,
+	}
+
+	sch, err := NewSchema(names, typs)
+	require.NoError(t, err)
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionGzip} {
+		t.Run(fmt.Sprintf("codec=%d", codec), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(sch, &buf, WithCompressionCodec(codec), WithMaxRowGroupLength(3))
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			path := filepath.Join(dir, fmt.Sprintf("%d.parquet", codec))
+			require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+			out, err := exec.Command(
+				"python3", testutils.TestDataPath("read_parquet.py"), path,
+			).CombinedOutput()
+			require.NoError(t, err, "%s", out)
+			require.Equal(t, expected, strings.Split(strings.TrimSpace(string(out)), "\n"))
+		})
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sch, err := NewSchema([]string{"a"}, []*types.T{types.Int})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := NewWriter(sch, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	f := readTestFile(t, buf.Bytes())
+	require.Equal(t, int64(0), f.numRows)
+	require.Equal(t, 0, f.rowGroups)
+	require.Len(t, f.schema, 2)
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, err := NewSchema([]string{"a", "a"}, []*types.T{types.Int, types.Int})
+	require.EqualError(t, err, `duplicate column name "a"`)
+}
+
+func TestTwosComplement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		v        int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{-1, []byte{0xff}},
+		{-127, []byte{0x81}},
+		{-129, []byte{0xff, 0x7f}},
+		{-32768, []byte{0xff, 0x80, 0x00}},
+	} {
+		require.Equal(t, tc.expected, twosComplement(big.NewInt(tc.v)), "%d", tc.v)
+	}
+}
@@ -96,3 +96,118 @@ func GetUsedSequenceNames(defaultExpr tree.TypedExpr) ([]string, error) {
 	}
 	return names, nil
 }
+
+// ReplaceSequenceNames replaces the names of the sequences passed to calls to
+// sequence functions in the given expression with the names returned by
+// rename, which returns its argument for sequences that keep their name. The
+// expression doesn't need to be type checked: the names may be string
+// literals, possibly cast or annotated, as in serialized default expressions.
+// e.g. nextval('foo':::STRING) => nextval('bar':::STRING) if rename("foo")
+// returns "bar"
+func ReplaceSequenceNames(
+	expr tree.Expr, rename func(seqName string) (string, error),
+) (tree.Expr, error) {
+	return tree.SimpleVisit(
+		expr,
+		func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+			t, ok := expr.(*tree.FuncExpr)
+			if !ok {
+				return true, expr, nil
+			}
+			// Resolve doesn't use the searchPath for resolving FunctionDefinitions
+			// so we can pass in an empty SearchPath.
+			def, err := t.Func.Resolve(sessiondata.SearchPath{})
+			if err != nil {
+				return false, nil, err
+			}
+			fnProps, overloads := builtins.GetBuiltinProperties(def.Name)
+			if fnProps == nil || !fnProps.HasSequenceArguments {
+				return true, expr, nil
+			}
+			var newFunc *tree.FuncExpr
+			for i, arg := range t.Exprs {
+				if !isSequenceNameArg(overloads, len(t.Exprs), i) {
+					continue
+				}
+				newArg, err := replaceSequenceNameArg(arg, rename)
+				if err != nil {
+					return false, nil, err
+				}
+				if newArg == arg {
+					continue
+				}
+				if newFunc == nil {
+					f := *t
+					f.Exprs = append(tree.Exprs(nil), t.Exprs...)
+					newFunc = &f
+				}
+				newFunc.Exprs[i] = newArg
+			}
+			if newFunc == nil {
+				return true, expr, nil
+			}
+			return false, newFunc, nil
+		},
+	)
+}
+
+// isSequenceNameArg returns whether the argument at position i of a call with
+// numArgs arguments is a sequence name in one of the given overloads.
+func isSequenceNameArg(overloads []tree.Overload, numArgs int, i int) bool {
+	for _, overload := range overloads {
+		argTypes, ok := overload.Types.(tree.ArgTypes)
+		if !ok || len(argTypes) != numArgs {
+			continue
+		}
+		if argTypes[i].Name == builtins.SequenceNameArg {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceSequenceNameArg replaces the sequence name in a sequence name
+// argument, which is a string literal possibly wrapped in casts, type
+// annotations or parentheses. Other arguments, which don't name a sequence
+// statically, are returned as is.
+func replaceSequenceNameArg(
+	arg tree.Expr, rename func(seqName string) (string, error),
+) (tree.Expr, error) {
+	switch a := arg.(type) {
+	case *tree.StrVal:
+		newName, err := rename(a.RawString())
+		if err != nil || newName == a.RawString() {
+			return arg, err
+		}
+		return tree.NewStrVal(newName), nil
+	case *tree.DString:
+		newName, err := rename(string(*a))
+		if err != nil || newName == string(*a) {
+			return arg, err
+		}
+		return tree.NewDString(newName), nil
+	case *tree.CastExpr:
+		newExpr, err := replaceSequenceNameArg(a.Expr, rename)
+		if err != nil || newExpr == a.Expr {
+			return arg, err
+		}
+		newCast := *a
+		newCast.Expr = newExpr
+		return &newCast, nil
+	case *tree.AnnotateTypeExpr:
+		newExpr, err := replaceSequenceNameArg(a.Expr, rename)
+		if err != nil || newExpr == a.Expr {
+			return arg, err
+		}
+		newAnnotate := *a
+		newAnnotate.Expr = newExpr
+		return &newAnnotate, nil
+	case *tree.ParenExpr:
+		newExpr, err := replaceSequenceNameArg(a.Expr, rename)
+		if err != nil || newExpr == a.Expr {
+			return arg, err
+		}
+		return &tree.ParenExpr{Expr: newExpr}, nil
+	}
+	return arg, nil
+}